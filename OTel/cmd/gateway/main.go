@@ -6,16 +6,26 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"otel/config"
 	_ "otel/docs" // Import docs for swagger
 	"otel/internal/gateway"
+	"otel/internal/repository"
+	"otel/pkg/debug"
+	"otel/pkg/featureflag"
+	"otel/pkg/slo"
 	"otel/pkg/telemetry"
+	"otel/pkg/tlsconfig"
 
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
 // @title OTEL Gateway Service
@@ -43,6 +53,11 @@ import (
 func main() {
 	log.Printf("[MAIN] Starting OTEL Gateway Service...")
 
+	// Load optional config file (reloaded on SIGHUP)
+	if err := config.LoadFromFile(); err != nil {
+		log.Fatalf("[MAIN] Failed to load config file: %v", err)
+	}
+
 	// Initialize OpenTelemetry tracing
 	zipkinURL := os.Getenv("ZIPKIN_URL")
 	if zipkinURL == "" {
@@ -54,13 +69,28 @@ func main() {
 		log.Fatalf("[MAIN] Failed to initialize tracer: %v", err)
 	}
 	defer func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := shutdown(ctx); err != nil {
+		// Bounded by TRACE_SHUTDOWN_TIMEOUT
+		if err := shutdown(context.Background()); err != nil {
 			log.Printf("[MAIN] Error shutting down tracer: %v", err)
 		}
 	}()
 
+	// SLIs are computed from the request histogram otelmux records; streams
+	// and health checks stay out of the objectives
+	sloCfg, err := slo.FromEnv("/cep", "/cep/full")
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid SLO configuration: %v", err)
+	}
+	sloCalculator := slo.New(sloCfg)
+	meterProvider := sdkmetric.NewMeterProvider(sloCalculator.MeterProviderOptions()...)
+	otel.SetMeterProvider(meterProvider)
+	defer meterProvider.Shutdown(context.Background())
+	sloCtx, stopSLO := context.WithCancel(context.Background())
+	defer stopSLO()
+	go sloCalculator.Run(sloCtx)
+	log.Printf("[MAIN] SLO: %.2f%% availability and %.2f%% under %v over %v for %v",
+		sloCfg.AvailabilityObjective*100, sloCfg.LatencyObjective*100, sloCfg.LatencyThreshold, sloCfg.Window, sloCfg.Routes)
+
 	// Get orchestration service URL from environment
 	orchestrationURL := os.Getenv("ORCHESTRATION_SERVICE_URL")
 	if orchestrationURL == "" {
@@ -79,35 +109,147 @@ func main() {
 		log.Printf("[MAIN] Using port from environment: %s", port)
 	}
 
+	// Feature flags from FEATURE_* variables and FEATURE_FLAGS_FILE
+	flags, err := featureflag.New(featureflag.Defaults())
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid feature flags: %v", err)
+	}
+	log.Printf("[MAIN] Feature flags: %s", flags)
+
 	// Initialize gateway handler
 	log.Printf("[MAIN] Initializing gateway handler...")
-	gatewayHandler := gateway.NewGatewayHandler(orchestrationURL)
+	gatewayHandler := gateway.NewGatewayHandler(orchestrationURL).
+		WithTraceIDInErrors(os.Getenv("TRACE_ID_IN_ERRORS") == "true").
+		WithStreamIdleTimeout(getEnvDuration("STREAM_IDLE_TIMEOUT", gateway.DefaultStreamIdleTimeout)).
+		WithFlags(flags)
+
+	// Addresses for POST /cep/full come straight from ViaCEP, in parallel with the orchestrator
+	addressRepo := repository.NewViaCEPRepository()
+	addressRepo.SetTimeout(getEnvDuration("ADDRESS_LOOKUP_TIMEOUT", 10*time.Second))
+	gatewayHandler.WithAddressLookup(addressRepo)
+	gatewayHandler.SetTimeout(getEnvDuration("ORCHESTRATION_TIMEOUT", 30*time.Second))
+
+	// Retry connection errors and 502/503 so orchestrator restarts don't reach clients
+	gatewayHandler.WithRetry(gateway.RetryPolicy{
+		MaxRetries: getEnvInt("ORCHESTRATION_MAX_RETRIES", gateway.DefaultRetryPolicy.MaxRetries),
+		BaseDelay:  getEnvDuration("ORCHESTRATION_RETRY_BACKOFF", gateway.DefaultRetryPolicy.BaseDelay),
+		MaxDelay:   getEnvDuration("ORCHESTRATION_RETRY_MAX_BACKOFF", gateway.DefaultRetryPolicy.MaxDelay),
+	})
+
+	// Optional regional routing by CEP prefix
+	if spec := os.Getenv("ORCHESTRATION_ROUTES"); spec != "" {
+		routes, err := gateway.ParseRoutes(spec)
+		if err != nil {
+			log.Fatalf("[MAIN] Invalid ORCHESTRATION_ROUTES: %v", err)
+		}
+		gatewayHandler.WithRoutes(routes,
+			getEnvInt("ORCHESTRATION_BREAKER_THRESHOLD", 5),
+			getEnvDuration("ORCHESTRATION_BREAKER_COOLDOWN", 30*time.Second),
+		)
+		log.Printf("[MAIN] Regional routing enabled with %d routes, default upstream: %s", len(routes), orchestrationURL)
+	}
+
+	// Optional (mutual) TLS for calls to the orchestration service
+	orchestrationTLS, err := tlsconfig.ClientFromEnv("ORCHESTRATION")
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid orchestration TLS configuration: %v", err)
+	}
+	if orchestrationTLS.Enabled() {
+		clientTLSConfig, err := orchestrationTLS.TLSConfig()
+		if err != nil {
+			log.Fatalf("[MAIN] Failed to load orchestration TLS configuration: %v", err)
+		}
+		gatewayHandler.WithTLSConfig(clientTLSConfig)
+		log.Printf("[MAIN] TLS enabled for orchestration calls (client certificate: %t)", orchestrationTLS.CertFile != "")
+	}
+
+	// Optional callback mode, enabled by a signing secret
+	var callbacks *gateway.CallbackDispatcher
+	if secret := os.Getenv("CALLBACK_SIGNING_SECRET"); secret != "" {
+		callbacks = gateway.NewCallbackDispatcher(secret,
+			getEnvInt("CALLBACK_MAX_ATTEMPTS", 5),
+			getEnvDuration("CALLBACK_RETRY_BACKOFF", time.Second),
+			getEnvInt("CALLBACK_MAX_PENDING", 100),
+		)
+		if hosts := os.Getenv("CALLBACK_ALLOWED_HOSTS"); hosts != "" {
+			callbacks.WithAllowedHosts(strings.Split(hosts, ","))
+		}
+		gatewayHandler.WithCallbacks(callbacks)
+		log.Printf("[MAIN] Callback mode enabled")
+	}
+
+	// Initialize load shedding limiter
+	limiter := gateway.NewConcurrencyLimiter(
+		getEnvInt("MAX_IN_FLIGHT_REQUESTS", 100),
+		getEnvDuration("QUEUE_TIMEOUT", 500*time.Millisecond),
+	)
+
+	// Reject oversized and non-JSON bodies before they take a slot
+	requestGuard := gateway.RequestGuard(int64(getEnvInt("MAX_REQUEST_BODY_BYTES", gateway.DefaultMaxBodyBytes)))
+
+	// Reload sampling, limits and timeouts on SIGHUP without dropping in-flight requests
+	stopReload := config.NotifyReload(func() {
+		if err := telemetry.ReloadSampler(); err != nil {
+			log.Printf("[MAIN] Keeping current trace sampler: %v", err)
+		}
+		limiter.SetLimits(
+			getEnvInt("MAX_IN_FLIGHT_REQUESTS", 100),
+			getEnvDuration("QUEUE_TIMEOUT", 500*time.Millisecond),
+		)
+		gatewayHandler.SetTimeout(getEnvDuration("ORCHESTRATION_TIMEOUT", 30*time.Second))
+		addressRepo.SetTimeout(getEnvDuration("ADDRESS_LOOKUP_TIMEOUT", 10*time.Second))
+		if err := flags.Reload(); err != nil {
+			log.Printf("[MAIN] Keeping current feature flags: %v", err)
+		} else {
+			log.Printf("[MAIN] Feature flags: %s", flags)
+		}
+		log.Printf("[MAIN] Configuration reloaded")
+	})
+	defer stopReload()
 
 	// Create router
 	log.Printf("[MAIN] Setting up routes...")
 	r := mux.NewRouter()
 
 	// Add OpenTelemetry middleware for automatic instrumentation
-	r.Use(otelmux.Middleware("otel-gateway"))
+	r.Use(otelmux.Middleware("otel-gateway", otelmux.WithMetricAttributesFn(slo.RouteAttributes)))
+
+	// Expose the trace ID to clients via X-Trace-Id
+	r.Use(telemetry.TraceIDMiddleware)
 
 	// Add logging middleware
 	r.Use(loggingMiddleware)
 
 	// Gateway routes
-	r.HandleFunc("/cep", gatewayHandler.ProcessCEP).Methods("POST")
+	r.Handle("/cep", requestGuard(limiter.Middleware(http.HandlerFunc(gatewayHandler.ProcessCEP)))).Methods("POST")
+	r.Handle("/cep/full", requestGuard(limiter.Middleware(http.HandlerFunc(gatewayHandler.ProcessCEPFull)))).Methods("POST")
+	// Streams are long-lived, so they bypass the in-flight limiter
+	r.Handle("/cep/{cep}/stream", flags.Require(featureflag.WeatherStream, http.HandlerFunc(gatewayHandler.StreamCEP))).Methods("GET")
+	r.Handle("/slo", sloCalculator).Methods("GET")
 	r.HandleFunc("/health", gatewayHandler.HealthCheck).Methods("GET")
 
 	// Swagger documentation
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
-	log.Printf("[MAIN] Routes configured: POST /cep, GET /health, /swagger/")
+	// Optional pprof and runtime stats, behind DEBUG_ENDPOINTS_ENABLED
+	debugCfg, err := debug.FromEnv()
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid debug endpoint configuration: %v", err)
+	}
+	debug.Register(r, debugCfg)
+	if debugCfg.Enabled {
+		log.Printf("[MAIN] Debug endpoints enabled: /debug/pprof/, /debug/vars (basic auth: %t)", debugCfg.Username != "")
+	}
+
+	log.Printf("[MAIN] Routes configured: POST /cep, POST /cep/full, GET /cep/{cep}/stream, GET /health, GET /slo, /swagger/")
 
 	// CORS middleware
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Client-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Trace-Id")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -124,10 +266,24 @@ func main() {
 	log.Printf("[MAIN] Swagger documentation available at: http://localhost:%s/swagger/index.html", port)
 	log.Printf("[MAIN] Server ready to accept connections...")
 
+	// Optional TLS termination
+	tlsCfg, err := tlsconfig.FromEnv()
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid TLS configuration: %v", err)
+	}
+	serverTLSConfig, err := tlsCfg.ServerTLS()
+	if err != nil {
+		log.Fatalf("[MAIN] Failed to load TLS configuration: %v", err)
+	}
+	if tlsCfg.ClientCAFile != "" {
+		log.Printf("[MAIN] Mutual TLS enabled: client certificates are required")
+	}
+
 	// Setup graceful shutdown
 	srv := &http.Server{
-		Addr:    ":" + port,
-		Handler: r,
+		Addr:      ":" + port,
+		Handler:   r,
+		TLSConfig: serverTLSConfig,
 	}
 
 	// Channel to listen for interrupt signal
@@ -136,11 +292,22 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := tlsCfg.ListenAndServe(srv); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("[MAIN] Server error: %v", err)
 		}
 	}()
 
+	// Redirect plain HTTP to HTTPS when enabled
+	redirectSrv := tlsCfg.RedirectServer(port)
+	if redirectSrv != nil {
+		log.Printf("[MAIN] Redirecting HTTP on port %s to HTTPS", tlsCfg.RedirectPort)
+		go func() {
+			if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("[MAIN] Redirect server error: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	<-c
 	log.Printf("[MAIN] Shutting down server...")
@@ -152,10 +319,48 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Printf("[MAIN] Server shutdown error: %v", err)
 	}
+	if redirectSrv != nil {
+		if err := redirectSrv.Shutdown(ctx); err != nil {
+			log.Printf("[MAIN] Redirect server shutdown error: %v", err)
+		}
+	}
+	if callbacks != nil {
+		if err := callbacks.Wait(ctx); err != nil {
+			log.Printf("[MAIN] Pending callbacks not delivered: %v", err)
+		}
+	}
 
 	log.Printf("[MAIN] Server shutdown complete")
 }
 
+// getEnvInt reads an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("[MAIN] Invalid %s=%q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvDuration reads a duration environment variable or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("[MAIN] Invalid %s=%q, using default %v", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
 // loggingMiddleware logs all incoming requests
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -190,3 +395,8 @@ func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.statusCode = code
 	lrw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap exposes the underlying writer so streams can flush and hijack the connection
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
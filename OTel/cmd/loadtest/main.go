@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"otel/internal/loadtest"
+)
+
+// Exit codes: 0 when the run stays within budget, 1 when a budget is violated,
+// 2 for invalid flags or when the report cannot be written.
+const (
+	exitBudgetViolated = 1
+	exitUsage          = 2
+)
+
+func main() {
+	target := flag.String("target", "http://localhost:8080/cep", "URL of the gateway POST /cep endpoint")
+	rate := flag.Int("rate", 50, "requests per second")
+	duration := flag.Duration("duration", 30*time.Second, "how long to send requests")
+	maxInFlight := flag.Int("max-in-flight", 200, "maximum concurrent requests; extra requests are dropped and counted as errors")
+	timeout := flag.Duration("timeout", 5*time.Second, "per-request timeout")
+	ceps := flag.String("ceps", "01310100", "comma-separated CEPs rotated across requests")
+	accept := flag.String("accept", "200", "comma-separated status codes counted as success")
+	p95 := flag.Duration("p95", 500*time.Millisecond, "p95 latency budget (0 disables)")
+	p99 := flag.Duration("p99", time.Second, "p99 latency budget (0 disables)")
+	maxErrorRate := flag.Float64("max-error-rate", 0.01, "maximum error rate between 0 and 1")
+	out := flag.String("out", "", "file to write the JSON report to (default stdout)")
+	flag.Parse()
+
+	cfg := loadtest.Config{
+		Target:      *target,
+		Rate:        *rate,
+		Duration:    *duration,
+		MaxInFlight: *maxInFlight,
+		Timeout:     *timeout,
+		CEPs:        splitList(*ceps),
+	}
+
+	codes, err := parseStatusCodes(*accept)
+	if err != nil {
+		log.Printf("[LOADTEST] Invalid -accept: %v", err)
+		os.Exit(exitUsage)
+	}
+	cfg.Accept = codes
+
+	if err := validate(cfg, *maxErrorRate); err != nil {
+		log.Printf("[LOADTEST] %v", err)
+		os.Exit(exitUsage)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			MaxIdleConns:        cfg.MaxInFlight,
+			MaxIdleConnsPerHost: cfg.MaxInFlight,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	log.Printf("[LOADTEST] Sending %d req/s to %s for %s", cfg.Rate, cfg.Target, cfg.Duration)
+	start := time.Now()
+	samples, dropped := loadtest.Run(ctx, cfg, client)
+	elapsed := time.Since(start)
+
+	report := loadtest.Summarize(cfg, samples, dropped, elapsed)
+	report.Check(loadtest.Budget{P95: *p95, P99: *p99, MaxErrorRate: *maxErrorRate})
+
+	if err := writeReport(report, *out); err != nil {
+		log.Printf("[LOADTEST] Failed to write report: %v", err)
+		os.Exit(exitUsage)
+	}
+
+	log.Printf("[LOADTEST] %d requests, p95=%.2fms p99=%.2fms error_rate=%.4f",
+		report.Requests, report.Latency.P95, report.Latency.P99, report.ErrorRate)
+	if !report.Passed {
+		for _, v := range report.Violations {
+			log.Printf("[LOADTEST] Budget violated: %s", v)
+		}
+		os.Exit(exitBudgetViolated)
+	}
+	log.Printf("[LOADTEST] All budgets met")
+}
+
+func validate(cfg loadtest.Config, maxErrorRate float64) error {
+	switch {
+	case cfg.Target == "":
+		return fmt.Errorf("-target is required")
+	case cfg.Rate <= 0:
+		return fmt.Errorf("-rate must be positive")
+	case cfg.Duration <= 0:
+		return fmt.Errorf("-duration must be positive")
+	case cfg.MaxInFlight <= 0:
+		return fmt.Errorf("-max-in-flight must be positive")
+	case cfg.Timeout <= 0:
+		return fmt.Errorf("-timeout must be positive")
+	case len(cfg.CEPs) == 0:
+		return fmt.Errorf("-ceps must list at least one CEP")
+	case maxErrorRate < 0 || maxErrorRate > 1:
+		return fmt.Errorf("-max-error-rate must be between 0 and 1")
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseStatusCodes(value string) ([]int, error) {
+	var codes []int
+	for _, item := range splitList(value) {
+		code, err := strconv.Atoi(item)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid status code %q", item)
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("at least one status code is required")
+	}
+	return codes, nil
+}
+
+func writeReport(report *loadtest.Report, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
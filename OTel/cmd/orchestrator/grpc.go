@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+
+	"otel/internal/analytics"
+	"otel/internal/handler"
+	"otel/internal/service"
+	"otel/pkg/api/weatherv1"
+	"otel/pkg/tlsconfig"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// newGRPCServer builds the instrumented gRPC server, using the same TLS
+// settings as the REST server when TLS is enabled; recorder may be nil
+func newGRPCServer(weatherService *service.WeatherService, recorder *analytics.Recorder, tlsCfg tlsconfig.Config, serverTLSConfig *tls.Config) (*grpc.Server, error) {
+	opts := []grpc.ServerOption{grpc.StatsHandler(otelgrpc.NewServerHandler())}
+	if tlsCfg.Enabled() {
+		cert, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		grpcTLSConfig := serverTLSConfig.Clone()
+		grpcTLSConfig.Certificates = []tls.Certificate{cert}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(grpcTLSConfig)))
+	}
+
+	srv := grpc.NewServer(opts...)
+	weatherv1.RegisterWeatherServiceServer(srv, handler.NewWeatherGRPCServer(weatherService).WithAnalytics(recorder))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	reflection.Register(srv)
+	return srv, nil
+}
+
+// stopGRPCServer drains in-flight RPCs, forcing the stop when ctx expires
+func stopGRPCServer(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,14 +13,23 @@ import (
 	_ "otel/docs" // Import docs for swagger
 
 	"otel/config"
+	"otel/internal/analytics"
+	"otel/internal/domain"
 	"otel/internal/handler"
 	"otel/internal/repository"
 	"otel/internal/service"
+	"otel/pkg/cityname"
+	"otel/pkg/debug"
+	"otel/pkg/featureflag"
+	"otel/pkg/slo"
 	"otel/pkg/telemetry"
+	"otel/pkg/tlsconfig"
 
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
 // @title OTEL Orchestration Service
@@ -44,9 +54,17 @@ import (
 // @tag.name health
 // @tag.description Health check da aplicação
 
+// @tag.name stats
+// @tag.description Estatísticas das consultas processadas
+
 func main() {
 	log.Printf("[MAIN] Starting OTEL Orchestration Service...")
 
+	// Load optional config file (reloaded on SIGHUP)
+	if err := config.LoadFromFile(); err != nil {
+		log.Fatalf("[MAIN] Failed to load config file: %v", err)
+	}
+
 	// Initialize OpenTelemetry tracing
 	zipkinURL := os.Getenv("ZIPKIN_URL")
 	if zipkinURL == "" {
@@ -58,13 +76,28 @@ func main() {
 		log.Fatalf("[MAIN] Failed to initialize tracer: %v", err)
 	}
 	defer func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := shutdown(ctx); err != nil {
+		// Bounded by TRACE_SHUTDOWN_TIMEOUT
+		if err := shutdown(context.Background()); err != nil {
 			log.Printf("[MAIN] Error shutting down tracer: %v", err)
 		}
 	}()
 
+	// SLIs are computed from the request histogram otelmux records; streams
+	// and health checks stay out of the objectives
+	sloCfg, err := slo.FromEnv("/weather/{cep}", "/alerts/{cep}")
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid SLO configuration: %v", err)
+	}
+	sloCalculator := slo.New(sloCfg)
+	meterProvider := sdkmetric.NewMeterProvider(sloCalculator.MeterProviderOptions()...)
+	otel.SetMeterProvider(meterProvider)
+	defer meterProvider.Shutdown(context.Background())
+	sloCtx, stopSLO := context.WithCancel(context.Background())
+	defer stopSLO()
+	go sloCalculator.Run(sloCtx)
+	log.Printf("[MAIN] SLO: %.2f%% availability and %.2f%% under %v over %v for %v",
+		sloCfg.AvailabilityObjective*100, sloCfg.LatencyObjective*100, sloCfg.LatencyThreshold, sloCfg.Window, sloCfg.Routes)
+
 	// Load configuration
 	log.Printf("[MAIN] Loading configuration...")
 	cfg := config.New()
@@ -77,17 +110,112 @@ func main() {
 	log.Printf("[MAIN] Initializing repositories...")
 	locationRepo := repository.NewViaCEPRepository()
 	weatherRepo := repository.NewWeatherAPIRepository(cfg.WeatherAPIKey)
+	locationRepo.SetTimeout(cfg.UpstreamTimeout)
+	weatherRepo.SetTimeout(cfg.UpstreamTimeout)
 	log.Printf("[MAIN] Repositories initialized successfully")
 
+	// Feature flags from FEATURE_* variables and FEATURE_FLAGS_FILE
+	flags, err := featureflag.New(featureflag.Defaults())
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid feature flags: %v", err)
+	}
+	log.Printf("[MAIN] Feature flags: %s", flags)
+
+	// Reload sampling, timeouts, the WeatherAPI key and feature flags on SIGHUP without dropping in-flight requests
+	stopReload := config.NotifyReload(func() {
+		reloaded := config.New()
+		if err := reloaded.Validate(); err != nil {
+			log.Printf("[MAIN] Reload aborted, invalid configuration: %v", err)
+			return
+		}
+		if err := telemetry.ReloadSampler(); err != nil {
+			log.Printf("[MAIN] Keeping current trace sampler: %v", err)
+		}
+		locationRepo.SetTimeout(reloaded.UpstreamTimeout)
+		weatherRepo.SetTimeout(reloaded.UpstreamTimeout)
+		weatherRepo.SetAPIKey(reloaded.WeatherAPIKey)
+		if err := flags.Reload(); err != nil {
+			log.Printf("[MAIN] Keeping current feature flags: %v", err)
+		} else {
+			log.Printf("[MAIN] Feature flags: %s", flags)
+		}
+		log.Printf("[MAIN] Configuration reloaded")
+	})
+	defer stopReload()
+
 	// Initialize services
 	log.Printf("[MAIN] Initializing services...")
-	weatherService := service.NewWeatherService(locationRepo, weatherRepo)
+	// Account WeatherAPI calls against the monthly quota, preferring cached and
+	// fallback readings once the threshold is crossed
+	openMeteoRepo := repository.NewOpenMeteoRepository()
+	openMeteoRepo.SetTimeout(cfg.UpstreamTimeout)
+	var fallbackRepo domain.WeatherDataService
+	if cfg.WeatherFallbackProvider == config.FallbackOpenMeteo {
+		fallbackRepo = openMeteoRepo
+		log.Printf("[MAIN] Fallback weather provider: %s", cfg.WeatherFallbackProvider)
+	}
+	quotaGuard := service.NewQuotaGuard(weatherRepo, fallbackRepo, service.QuotaPolicy{
+		MonthlyQuota: cfg.WeatherAPIMonthlyQuota,
+		DailyQuota:   cfg.WeatherAPIDailyQuota,
+		Threshold:    cfg.WeatherAPIQuotaThreshold,
+		Throttle:     cfg.WeatherAPIQuotaThrottle,
+		CostPerCall:  cfg.WeatherAPICostPerCall,
+		CacheMaxAge:  cfg.QuotaCacheMaxAge,
+	}).WithFlags(flags)
+
+	// Clients may pin a provider per request; unpinned lookups go through the quota guard
+	providerSelector := service.NewProviderSelector(quotaGuard, map[string]domain.WeatherDataService{
+		repository.ProviderWeatherAPI: quotaGuard.PrimaryOnly(),
+		repository.ProviderOpenMeteo:  openMeteoRepo,
+	})
+
+	// City names are rewritten into queries WeatherAPI resolves (CITY_ALIASES was validated with the config)
+	cityAliases, _ := cityname.ParseAliases(cfg.CityAliases)
+	cityNormalizer := cityname.New(cityname.Options{
+		StripAccents:      cfg.CityStripAccents,
+		DisambiguateState: cfg.CityDisambiguateState,
+		Aliases:           cityAliases,
+	})
+
+	weatherService := service.NewWeatherService(locationRepo, providerSelector).WithAnomalyPolicy(service.AnomalyPolicy{
+		MinTempC:    cfg.AnomalyMinTempC,
+		MaxTempC:    cfg.AnomalyMaxTempC,
+		Reject:      cfg.AnomalyReject,
+		CacheMaxAge: cfg.AnomalyCacheMaxAge,
+	}).WithCityNormalizer(cityNormalizer)
 	log.Printf("[MAIN] Services initialized successfully")
 
 	// Initialize handlers
 	log.Printf("[MAIN] Initializing handlers...")
-	weatherHandler := handler.NewWeatherHandler(weatherService)
-	healthHandler := handler.NewHealthHandler()
+	// Optional query analytics, persisted off the request path
+	var (
+		analyticsStore    *analytics.Store
+		analyticsRecorder *analytics.Recorder
+	)
+	if cfg.AnalyticsDriver != "" {
+		analyticsStore, err = analytics.Open(context.Background(), cfg.AnalyticsDriver, cfg.AnalyticsDSN)
+		if err != nil {
+			log.Fatalf("[MAIN] Failed to open analytics store: %v", err)
+		}
+		defer analyticsStore.Close()
+		analyticsRecorder = analytics.NewRecorder(analyticsStore, cfg.AnalyticsBufferSize)
+		log.Printf("[MAIN] Query analytics enabled (%s)", cfg.AnalyticsDriver)
+	}
+
+	weatherHandler := handler.NewWeatherHandler(weatherService, handler.CachePolicy{
+		MaxAge:      cfg.CacheMaxAge,
+		SuccessTTL:  cfg.CacheSuccessTTL,
+		NotFoundTTL: cfg.CacheNotFoundTTL,
+		Vary:        cfg.CacheVary,
+	}).WithTraceIDInErrors(cfg.TraceIDInErrors).
+		WithETagCache(cfg.ETagCacheTTL).
+		WithRefresher(service.NewWeatherRefresher(weatherService, cfg.StreamRefreshInterval)).
+		WithAnalytics(analyticsRecorder).
+		WithProviders(providerSelector).
+		WithFlags(flags)
+	alertsHandler := handler.NewAlertsHandler(service.NewAlertService(locationRepo, quotaGuard).WithCityNormalizer(cityNormalizer)).
+		WithTraceIDInErrors(cfg.TraceIDInErrors)
+	healthHandler := handler.NewHealthHandler().WithQuota(quotaGuard)
 	log.Printf("[MAIN] Handlers initialized successfully")
 
 	// Setup router
@@ -95,29 +223,65 @@ func main() {
 	r := mux.NewRouter()
 
 	// Add OpenTelemetry middleware for automatic instrumentation
-	r.Use(otelmux.Middleware("otel-orchestration"))
+	r.Use(otelmux.Middleware("otel-orchestration", otelmux.WithMetricAttributesFn(slo.RouteAttributes)))
+
+	// Expose the trace ID to clients via X-Trace-Id
+	r.Use(telemetry.TraceIDMiddleware)
 
 	// Add logging middleware
 	r.Use(loggingMiddleware)
 
 	// API endpoints
 	r.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
+	r.Handle("/weather/{cep}/stream", flags.Require(featureflag.WeatherStream, http.HandlerFunc(weatherHandler.StreamWeatherByCEP))).Methods("GET")
+	r.HandleFunc("/alerts/{cep}", alertsHandler.GetAlertsByCEP).Methods("GET")
+	r.Handle("/slo", sloCalculator).Methods("GET")
 	r.HandleFunc("/health", healthHandler.HealthCheck).Methods("GET")
+	r.HandleFunc("/health/ready", healthHandler.ReadinessCheck).Methods("GET")
+	r.HandleFunc("/quota", healthHandler.GetQuota).Methods("GET")
+	if analyticsStore != nil {
+		r.HandleFunc("/stats", handler.NewStatsHandler(analyticsStore).GetStats).Methods("GET")
+		log.Printf("[MAIN] Route configured: GET /stats")
+	}
 
 	// Swagger documentation
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
-	log.Printf("[MAIN] Routes configured: GET /weather/{cep}, GET /health, /swagger/")
+	// Optional pprof and runtime stats, behind DEBUG_ENDPOINTS_ENABLED
+	debugCfg, err := debug.FromEnv()
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid debug endpoint configuration: %v", err)
+	}
+	debug.Register(r, debugCfg)
+	if debugCfg.Enabled {
+		log.Printf("[MAIN] Debug endpoints enabled: /debug/pprof/, /debug/vars (basic auth: %t)", debugCfg.Username != "")
+	}
+
+	log.Printf("[MAIN] Routes configured: GET /weather/{cep}, GET /weather/{cep}/stream, GET /alerts/{cep}, GET /health, GET /health/ready, GET /quota, GET /slo, /swagger/")
 
 	log.Printf("[MAIN] OTEL Orchestration Service starting on port %s", cfg.Port)
 	log.Printf("[MAIN] Zipkin URL: %s", zipkinURL)
 	log.Printf("[MAIN] Swagger documentation available at: http://localhost:%s/swagger/index.html", cfg.Port)
 	log.Printf("[MAIN] Server ready to accept connections...")
 
+	// Optional TLS termination
+	tlsCfg, err := tlsconfig.FromEnv()
+	if err != nil {
+		log.Fatalf("[MAIN] Invalid TLS configuration: %v", err)
+	}
+	serverTLSConfig, err := tlsCfg.ServerTLS()
+	if err != nil {
+		log.Fatalf("[MAIN] Failed to load TLS configuration: %v", err)
+	}
+	if tlsCfg.ClientCAFile != "" {
+		log.Printf("[MAIN] Mutual TLS enabled: client certificates are required")
+	}
+
 	// Setup graceful shutdown
 	srv := &http.Server{
-		Addr:    ":" + cfg.Port,
-		Handler: r,
+		Addr:      ":" + cfg.Port,
+		Handler:   r,
+		TLSConfig: serverTLSConfig,
 	}
 
 	// Channel to listen for interrupt signal
@@ -126,11 +290,38 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := tlsCfg.ListenAndServe(srv); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("[MAIN] Server error: %v", err)
 		}
 	}()
 
+	// Serve the same weather lookup over gRPC for internal Go clients
+	grpcServer, err := newGRPCServer(weatherService, analyticsRecorder, tlsCfg, serverTLSConfig)
+	if err != nil {
+		log.Fatalf("[MAIN] Failed to configure gRPC server: %v", err)
+	}
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("[MAIN] Failed to listen on gRPC port %s: %v", cfg.GRPCPort, err)
+	}
+	log.Printf("[MAIN] gRPC server (weather.v1.WeatherService) starting on port %s", cfg.GRPCPort)
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("[MAIN] gRPC server error: %v", err)
+		}
+	}()
+
+	// Redirect plain HTTP to HTTPS when enabled
+	redirectSrv := tlsCfg.RedirectServer(cfg.Port)
+	if redirectSrv != nil {
+		log.Printf("[MAIN] Redirecting HTTP on port %s to HTTPS", tlsCfg.RedirectPort)
+		go func() {
+			if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("[MAIN] Redirect server error: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	<-c
 	log.Printf("[MAIN] Shutting down server...")
@@ -142,6 +333,17 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Printf("[MAIN] Server shutdown error: %v", err)
 	}
+	stopGRPCServer(ctx, grpcServer)
+	if redirectSrv != nil {
+		if err := redirectSrv.Shutdown(ctx); err != nil {
+			log.Printf("[MAIN] Redirect server shutdown error: %v", err)
+		}
+	}
+	if analyticsRecorder != nil {
+		if err := analyticsRecorder.Close(ctx); err != nil {
+			log.Printf("[MAIN] Pending analytics queries not persisted: %v", err)
+		}
+	}
 
 	log.Printf("[MAIN] Server shutdown complete")
 }
@@ -180,3 +382,8 @@ func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.statusCode = code
 	lrw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap exposes the underlying writer to http.ResponseController, e.g. to flush SSE events
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
@@ -6,23 +6,35 @@ package main
 // These tests focus on business logic and external API integration.
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"otel/config"
 	"otel/internal/domain"
 	"otel/internal/handler"
 	"otel/internal/service"
+	"otel/pkg/api/weatherv1"
+	"otel/pkg/tlsconfig"
 
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 // MockWeatherService for testing
 type MockWeatherService struct{}
 
-func (m *MockWeatherService) GetLocationByCEP(cep string) (*domain.ViaCEPResponse, error) {
+func (m *MockWeatherService) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
 	if cep == "01310100" {
 		return &domain.ViaCEPResponse{
 			CEP:        "01310-100",
@@ -42,7 +54,7 @@ func (m *MockWeatherService) GetLocationByCEP(cep string) (*domain.ViaCEPRespons
 	return nil, service.ErrCEPNotFound
 }
 
-func (m *MockWeatherService) GetWeatherByLocation(location string) (*domain.WeatherAPIResponse, error) {
+func (m *MockWeatherService) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
 	// Test that we handle locations with special characters properly
 	if location == "São Paulo,SP" || location == "Rio de Janeiro,RJ" {
 		return &domain.WeatherAPIResponse{
@@ -63,7 +75,12 @@ func setupTestRouter() *mux.Router {
 	weatherService := service.NewWeatherService(locationRepo, weatherRepo)
 
 	// Setup handlers
-	weatherHandler := handler.NewWeatherHandler(weatherService)
+	weatherHandler := handler.NewWeatherHandler(weatherService, handler.CachePolicy{
+		MaxAge:      time.Minute,
+		SuccessTTL:  5 * time.Minute,
+		NotFoundTTL: time.Hour,
+		Vary:        "Accept-Encoding",
+	})
 	healthHandler := handler.NewHealthHandler()
 
 	// Setup router
@@ -212,6 +229,250 @@ func TestWeatherEndpointWithSpecialCharacters(t *testing.T) {
 	}
 }
 
+// countingWeatherService counts upstream lookups
+type countingWeatherService struct {
+	MockWeatherService
+	calls int
+}
+
+func (m *countingWeatherService) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	m.calls++
+	return m.MockWeatherService.GetLocationByCEP(ctx, cep)
+}
+
+func TestWeatherEndpointETag(t *testing.T) {
+	repo := &countingWeatherService{}
+	weatherHandler := handler.NewWeatherHandler(service.NewWeatherService(repo, repo), handler.CachePolicy{
+		MaxAge:     time.Minute,
+		SuccessTTL: 5 * time.Minute,
+	}).WithETagCache(time.Minute)
+	router := mux.NewRouter()
+	router.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/weather/01310100", nil))
+	etag := rr.Header().Get("ETag")
+	if rr.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Expected 200 with ETag, got %d and %q", rr.Code, etag)
+	}
+
+	// Matching If-None-Match is answered from the payload cache
+	req := httptest.NewRequest("GET", "/weather/01310100", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotModified {
+		t.Errorf("Expected status %d, got %d", http.StatusNotModified, rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("Expected empty body on 304, got %q", rr.Body.String())
+	}
+	if rr.Header().Get("ETag") != etag || rr.Header().Get("Cache-Control") == "" {
+		t.Errorf("Expected ETag and Cache-Control on 304, got %v", rr.Header())
+	}
+	if repo.calls != 1 {
+		t.Errorf("Expected 1 upstream lookup, got %d", repo.calls)
+	}
+
+	// A stale ETag gets the full response
+	req = httptest.NewRequest("GET", "/weather/01310100", nil)
+	req.Header.Set("If-None-Match", `W/"outdated"`)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") != etag {
+		t.Errorf("Expected 200 with ETag %s, got %d and %q", etag, rr.Code, rr.Header().Get("ETag"))
+	}
+}
+
+// fixedWeatherRepo answers every location with the same reading
+type fixedWeatherRepo struct {
+	tempC    float64
+	provider string
+}
+
+func (f fixedWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	resp := &domain.WeatherAPIResponse{Provider: f.provider}
+	resp.Current.TempC = f.tempC
+	return resp, nil
+}
+
+func TestWeatherEndpointProviderSelection(t *testing.T) {
+	selector := service.NewProviderSelector(fixedWeatherRepo{20, "weatherapi"}, map[string]domain.WeatherDataService{
+		"weatherapi": fixedWeatherRepo{20, "weatherapi"},
+		"open-meteo": fixedWeatherRepo{22, "open-meteo"},
+	})
+	weatherHandler := handler.NewWeatherHandler(service.NewWeatherService(&MockWeatherService{}, selector), handler.CachePolicy{}).
+		WithETagCache(time.Minute).
+		WithProviders(selector)
+	router := mux.NewRouter()
+	router.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
+
+	tests := []struct {
+		name       string
+		query      string
+		header     string
+		wantStatus int
+		wantTempC  float64
+	}{
+		{"default selection", "", "", http.StatusOK, 20},
+		{"query parameter", "?provider=open-meteo", "", http.StatusOK, 22},
+		{"header", "", "open-meteo", http.StatusOK, 22},
+		{"query wins over header", "?provider=weatherapi", "open-meteo", http.StatusOK, 20},
+		{"unknown provider", "?provider=openweather", "", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather/01310100"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Weather-Provider", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Header().Get("Vary") != "X-Weather-Provider" {
+				t.Errorf("Expected Vary: X-Weather-Provider, got %q", rr.Header().Get("Vary"))
+			}
+			if tt.wantStatus != http.StatusOK {
+				var response domain.ErrorResponse
+				json.Unmarshal(rr.Body.Bytes(), &response)
+				if !strings.Contains(response.Message, "open-meteo, weatherapi") {
+					t.Errorf("Expected supported providers in message, got %q", response.Message)
+				}
+				return
+			}
+			var response domain.WeatherResponse
+			json.Unmarshal(rr.Body.Bytes(), &response)
+			if response.TempC != tt.wantTempC {
+				t.Errorf("Expected temp_C %v, got %v", tt.wantTempC, response.TempC)
+			}
+		})
+	}
+}
+
+func TestWeatherStreamEndpoint(t *testing.T) {
+	weatherService := service.NewWeatherService(&MockWeatherService{}, &MockWeatherService{})
+	weatherHandler := handler.NewWeatherHandler(weatherService, handler.CachePolicy{}).
+		WithRefresher(service.NewWeatherRefresher(weatherService, 20*time.Millisecond))
+	router := mux.NewRouter()
+	router.Use(otelmux.Middleware("otel-orchestration-test"))
+	router.Use(loggingMiddleware)
+	router.HandleFunc("/weather/{cep}/stream", weatherHandler.StreamWeatherByCEP).Methods("GET")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/weather/01310100/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %q", contentType)
+	}
+
+	// Two refreshes must arrive while the connection stays open
+	scanner := bufio.NewScanner(resp.Body)
+	events := 0
+	for events < 2 && scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var weather domain.WeatherResponse
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &weather); err != nil {
+			t.Fatalf("Invalid event data %q: %v", line, err)
+		}
+		if weather.TempC != 28.5 {
+			t.Errorf("Expected temp_C 28.5, got %v", weather.TempC)
+		}
+		events++
+	}
+	if events < 2 {
+		t.Errorf("Expected 2 weather events, got %d (%v)", events, scanner.Err())
+	}
+}
+
+func TestWeatherStreamEndpointCEPNotFound(t *testing.T) {
+	weatherService := service.NewWeatherService(&MockWeatherService{}, &MockWeatherService{})
+	weatherHandler := handler.NewWeatherHandler(weatherService, handler.CachePolicy{}).
+		WithRefresher(service.NewWeatherRefresher(weatherService, time.Minute))
+	router := mux.NewRouter()
+	router.HandleFunc("/weather/{cep}/stream", weatherHandler.StreamWeatherByCEP).Methods("GET")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/weather/99999999/stream", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestGRPCGetWeatherByCEP(t *testing.T) {
+	weatherService := service.NewWeatherService(&MockWeatherService{}, &MockWeatherService{})
+	grpcServer, err := newGRPCServer(weatherService, nil, tlsconfig.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create gRPC server: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create gRPC client: %v", err)
+	}
+	defer conn.Close()
+	client := weatherv1.NewWeatherServiceClient(conn)
+
+	tests := []struct {
+		name     string
+		cep      string
+		wantCode codes.Code
+		wantCity string
+	}{
+		{"valid CEP", "01310100", codes.OK, "São Paulo"},
+		{"valid CEP with hyphen", "20040-020", codes.OK, "Rio de Janeiro"},
+		{"CEP not found", "99999999", codes.NotFound, ""},
+		{"invalid CEP", "123", codes.InvalidArgument, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			resp, err := client.GetWeatherByCEP(ctx, &weatherv1.GetWeatherByCEPRequest{Cep: tt.cep})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("Expected code %v, got %v (%v)", tt.wantCode, code, err)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			if resp.GetCity() != tt.wantCity {
+				t.Errorf("Expected city %q, got %q", tt.wantCity, resp.GetCity())
+			}
+			if resp.GetTempC() != 28.5 || resp.GetTempK() != 301.5 {
+				t.Errorf("Unexpected temperatures: %+v", resp)
+			}
+		})
+	}
+}
+
 func TestConfig(t *testing.T) {
 	cfg := config.New()
 
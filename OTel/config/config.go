@@ -1,20 +1,114 @@
 package config
 
 import (
+	"fmt"
+	"log"
 	"os"
+	"strconv"
+	"time"
+
+	"otel/pkg/cityname"
 )
 
 // Config holds all configuration for the application
 type Config struct {
 	WeatherAPIKey string
 	Port          string
+
+	// GRPCPort serves the weather lookup over gRPC alongside REST
+	GRPCPort string
+
+	// Response caching headers for CDN / gateway cache fronting
+	CacheMaxAge      time.Duration
+	CacheSuccessTTL  time.Duration
+	CacheNotFoundTTL time.Duration
+	CacheVary        string
+
+	// ETagCacheTTL keeps payloads to answer If-None-Match without upstream calls (0 disables)
+	ETagCacheTTL time.Duration
+
+	// StreamRefreshInterval is how often SSE subscribers receive refreshed weather
+	StreamRefreshInterval time.Duration
+
+	// UpstreamTimeout bounds each call to ViaCEP and WeatherAPI
+	UpstreamTimeout time.Duration
+
+	// TraceIDInErrors includes the trace ID in error response bodies
+	TraceIDInErrors bool
+
+	// Plausible upstream temperature range; readings outside it are anomalies
+	AnomalyMinTempC float64
+	AnomalyMaxTempC float64
+	// AnomalyReject replaces anomalies with cached data or answers 502
+	AnomalyReject      bool
+	AnomalyCacheMaxAge time.Duration
+
+	// WeatherAPI calls allowed per month (0 only counts calls) and per day
+	// (0 disables), and the fraction after which cached readings and the
+	// fallback provider are preferred
+	WeatherAPIMonthlyQuota   int64
+	WeatherAPIDailyQuota     int64
+	WeatherAPIQuotaThreshold float64
+	// WeatherAPIQuotaThrottle spaces WeatherAPI calls past the threshold so the budget lasts until it resets
+	WeatherAPIQuotaThrottle bool
+	WeatherAPICostPerCall   float64
+	// WeatherFallbackProvider answers past the quota threshold ("" or "open-meteo")
+	WeatherFallbackProvider string
+	// QuotaCacheMaxAge bounds how old a reading may be to replace a WeatherAPI call past the threshold
+	QuotaCacheMaxAge time.Duration
+
+	// City-name normalization before WeatherAPI queries: strip accents, replace
+	// the UF by state name and country, and extra "City/UF=Alias;..." aliases
+	CityStripAccents      bool
+	CityDisambiguateState bool
+	CityAliases           string
+
+	// Query analytics store ("" disables, "sqlite" or "postgres") and its connection string
+	AnalyticsDriver string
+	AnalyticsDSN    string
+	// AnalyticsBufferSize is how many queries may wait to be written before new ones are dropped
+	AnalyticsBufferSize int
 }
 
+// FallbackOpenMeteo selects Open-Meteo as the fallback weather provider
+const FallbackOpenMeteo = "open-meteo"
+
 // New creates a new configuration instance
 func New() *Config {
 	return &Config{
-		WeatherAPIKey: getEnv("WEATHER_API_KEY", ""),
-		Port:          getEnv("PORT", "8081"),
+		WeatherAPIKey:    getEnv("WEATHER_API_KEY", ""),
+		Port:             getEnv("PORT", "8081"),
+		GRPCPort:         getEnv("GRPC_PORT", "50051"),
+		CacheMaxAge:      getEnvDuration("CACHE_MAX_AGE", time.Minute),
+		CacheSuccessTTL:  getEnvDuration("CACHE_SUCCESS_TTL", 5*time.Minute),
+		CacheNotFoundTTL: getEnvDuration("CACHE_NOT_FOUND_TTL", time.Hour),
+		CacheVary:        getEnv("CACHE_VARY", "Accept-Encoding"),
+		ETagCacheTTL:     getEnvDuration("ETAG_CACHE_TTL", time.Minute),
+		UpstreamTimeout:  getEnvDuration("UPSTREAM_TIMEOUT", 10*time.Second),
+		TraceIDInErrors:  getEnv("TRACE_ID_IN_ERRORS", "false") == "true",
+
+		StreamRefreshInterval: getEnvDuration("STREAM_REFRESH_INTERVAL", 30*time.Second),
+
+		AnomalyMinTempC:    getEnvFloat("ANOMALY_MIN_TEMP_C", -90),
+		AnomalyMaxTempC:    getEnvFloat("ANOMALY_MAX_TEMP_C", 60),
+		AnomalyReject:      getEnv("ANOMALY_REJECT", "false") == "true",
+		AnomalyCacheMaxAge: getEnvDuration("ANOMALY_CACHE_MAX_AGE", time.Hour),
+
+		WeatherAPIMonthlyQuota:   getEnvInt64("WEATHER_API_MONTHLY_QUOTA", 0),
+		WeatherAPIDailyQuota:     getEnvInt64("WEATHER_API_DAILY_QUOTA", 0),
+		WeatherAPIQuotaThreshold: getEnvFloat("WEATHER_API_QUOTA_THRESHOLD", 0.9),
+		WeatherAPIQuotaThrottle:  getEnv("WEATHER_API_QUOTA_THROTTLE", "false") == "true",
+		WeatherAPICostPerCall:    getEnvFloat("WEATHER_API_COST_PER_CALL", 0),
+		WeatherFallbackProvider:  getEnv("WEATHER_FALLBACK_PROVIDER", ""),
+		QuotaCacheMaxAge:         getEnvDuration("QUOTA_CACHE_MAX_AGE", time.Hour),
+
+		CityStripAccents:      getEnv("CITY_STRIP_ACCENTS", "false") == "true",
+		CityDisambiguateState: getEnv("CITY_DISAMBIGUATE_STATE", "false") == "true",
+		CityAliases:           getEnv("CITY_ALIASES", ""),
+
+		AnalyticsDriver:     getEnv("ANALYTICS_DRIVER", ""),
+		AnalyticsDSN:        getEnv("ANALYTICS_DSN", ""),
+		AnalyticsBufferSize: int(getEnvInt64("ANALYTICS_BUFFER_SIZE", 1000)),
 	}
 }
 
@@ -26,10 +120,75 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvDuration gets a duration environment variable or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("[CONFIG] Invalid %s=%q, using default %v", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// getEnvFloat gets a float environment variable or returns a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("[CONFIG] Invalid %s=%q, using default %v", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvInt64 gets an integer environment variable or returns a default value
+func getEnvInt64(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Printf("[CONFIG] Invalid %s=%q, using default %v", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.WeatherAPIKey == "" {
 		return ErrMissingWeatherAPIKey
 	}
+	if c.AnomalyMinTempC >= c.AnomalyMaxTempC {
+		return ErrInvalidAnomalyRange
+	}
+	if c.WeatherAPIQuotaThreshold <= 0 || c.WeatherAPIQuotaThreshold > 1 {
+		return ErrInvalidQuotaThreshold
+	}
+	if c.WeatherFallbackProvider != "" && c.WeatherFallbackProvider != FallbackOpenMeteo {
+		return ErrUnknownFallbackProvider
+	}
+	if _, err := cityname.ParseAliases(c.CityAliases); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCityAliases, err)
+	}
+	if c.AnalyticsDriver != "" {
+		if c.AnalyticsDriver != "sqlite" && c.AnalyticsDriver != "postgres" {
+			return ErrUnknownAnalyticsDriver
+		}
+		if c.AnalyticsDSN == "" {
+			return ErrMissingAnalyticsDSN
+		}
+		if c.AnalyticsBufferSize <= 0 {
+			return ErrInvalidAnalyticsBuffer
+		}
+	}
 	return nil
 }
@@ -5,4 +5,25 @@ import "errors"
 var (
 	// ErrMissingWeatherAPIKey is returned when the weather API key is not configured
 	ErrMissingWeatherAPIKey = errors.New("WEATHER_API_KEY environment variable is required")
+
+	// ErrInvalidAnomalyRange is returned when ANOMALY_MIN_TEMP_C is not below ANOMALY_MAX_TEMP_C
+	ErrInvalidAnomalyRange = errors.New("ANOMALY_MIN_TEMP_C must be lower than ANOMALY_MAX_TEMP_C")
+
+	// ErrInvalidQuotaThreshold is returned when WEATHER_API_QUOTA_THRESHOLD is not in (0, 1]
+	ErrInvalidQuotaThreshold = errors.New("WEATHER_API_QUOTA_THRESHOLD must be greater than 0 and at most 1")
+
+	// ErrUnknownFallbackProvider is returned when WEATHER_FALLBACK_PROVIDER names an unsupported provider
+	ErrUnknownFallbackProvider = errors.New("WEATHER_FALLBACK_PROVIDER must be empty or open-meteo")
+
+	// ErrInvalidCityAliases is returned when CITY_ALIASES is not a list of City/UF=Alias pairs
+	ErrInvalidCityAliases = errors.New("CITY_ALIASES must be a ;-separated list of City/UF=Alias pairs")
+
+	// ErrUnknownAnalyticsDriver is returned when ANALYTICS_DRIVER is not sqlite or postgres
+	ErrUnknownAnalyticsDriver = errors.New("ANALYTICS_DRIVER must be empty, sqlite or postgres")
+
+	// ErrMissingAnalyticsDSN is returned when ANALYTICS_DRIVER is set without ANALYTICS_DSN
+	ErrMissingAnalyticsDSN = errors.New("ANALYTICS_DSN is required when ANALYTICS_DRIVER is set")
+
+	// ErrInvalidAnalyticsBuffer is returned when ANALYTICS_BUFFER_SIZE is not positive
+	ErrInvalidAnalyticsBuffer = errors.New("ANALYTICS_BUFFER_SIZE must be positive")
 )
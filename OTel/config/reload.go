@@ -0,0 +1,91 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// ConfigFileEnv names the environment variable pointing to an optional KEY=VALUE config file
+const ConfigFileEnv = "CONFIG_FILE"
+
+// LoadEnvFile reads KEY=VALUE lines from path into the process environment.
+// Blank lines and lines starting with # are ignored; values in the file
+// override variables already set in the environment.
+func LoadEnvFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return fmt.Errorf("invalid config line %d: %q", lineNumber, line)
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	return nil
+}
+
+// LoadFromFile loads the file named by CONFIG_FILE, if set
+func LoadFromFile() error {
+	path := os.Getenv(ConfigFileEnv)
+	if path == "" {
+		return nil
+	}
+
+	log.Printf("[CONFIG] Loading configuration from %s", path)
+	return LoadEnvFile(path)
+}
+
+// NotifyReload calls reload every time the process receives SIGHUP, after
+// re-reading CONFIG_FILE. The returned function stops watching the signal.
+func NotifyReload(reload func()) (stop func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				log.Printf("[CONFIG] SIGHUP received, reloading configuration...")
+				if err := LoadFromFile(); err != nil {
+					log.Printf("[CONFIG] Reload aborted: %v", err)
+					continue
+				}
+				reload()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel.env")
+	content := "# comentário\n\nTEST_RELOAD_SAMPLER=ratio\nTEST_RELOAD_KEY = \"abc\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("TEST_RELOAD_SAMPLER")
+	defer os.Unsetenv("TEST_RELOAD_KEY")
+
+	if err := LoadEnvFile(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := os.Getenv("TEST_RELOAD_SAMPLER"); got != "ratio" {
+		t.Errorf("Expected TEST_RELOAD_SAMPLER to be 'ratio', got %q", got)
+	}
+	if got := os.Getenv("TEST_RELOAD_KEY"); got != "abc" {
+		t.Errorf("Expected TEST_RELOAD_KEY to be 'abc', got %q", got)
+	}
+}
+
+func TestLoadEnvFile_InvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel.env")
+	if err := os.WriteFile(path, []byte("NOT_A_PAIR\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadEnvFile(path); err == nil {
+		t.Error("Expected error for line without '='")
+	}
+}
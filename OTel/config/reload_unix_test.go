@@ -0,0 +1,39 @@
+//go:build unix
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel.env")
+	if err := os.WriteFile(path, []byte("WEATHER_API_KEY=rotated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigFileEnv, path)
+	t.Setenv("WEATHER_API_KEY", "original")
+
+	reloaded := make(chan string, 1)
+	stop := NotifyReload(func() {
+		reloaded <- New().WeatherAPIKey
+	})
+	defer stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case key := <-reloaded:
+		if key != "rotated" {
+			t.Errorf("Expected reloaded API key 'rotated', got %q", key)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected reload callback after SIGHUP")
+	}
+}
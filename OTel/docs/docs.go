@@ -23,9 +23,57 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/alerts/{cep}": {
+            "get": {
+                "description": "Retorna os alertas oficiais vigentes (tempestades, enchentes, etc.) para a cidade do CEP. A lista é vazia quando não há alertas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Obter alertas meteorológicos por CEP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"01310100\"",
+                        "description": "CEP brasileiro (8 dígitos)",
+                        "name": "cep",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Alertas vigentes",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AlertsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "CEP não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "CEP inválido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro ao consultar os alertas",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/cep": {
             "post": {
-                "description": "Validates CEP input and forwards to orchestration service",
+                "description": "Validates CEP input and forwards to orchestration service\nWith callback_url, answers 202 immediately and POSTs a signed CallbackPayload to the URL when done",
                 "consumes": [
                     "application/json"
                 ],
@@ -55,12 +103,30 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "202": {
+                        "description": "Accepted in callback mode",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.CallbackAccepted"
+                        }
+                    },
                     "400": {
                         "description": "Bad request",
                         "schema": {
                             "$ref": "#/definitions/gateway.ErrorResponse"
                         }
                     },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
                     "422": {
                         "description": "Invalid zipcode",
                         "schema": {
@@ -72,6 +138,129 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/gateway.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Orchestration service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cep/full": {
+            "post": {
+                "description": "Validates the CEP, then resolves its address and fetches its temperatures in parallel",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gateway"
+                ],
+                "summary": "Process CEP with address",
+                "parameters": [
+                    {
+                        "description": "CEP input (callback_url is not supported)",
+                        "name": "cep",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gateway.CEPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Address and temperatures",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.FullCEPResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Zipcode not found",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid zipcode",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Address or weather lookup failed",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "No orchestrator available",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cep/{cep}/stream": {
+            "get": {
+                "description": "Proxies the orchestrator's Server-Sent Events stream (or a WebSocket upgrade) for the CEP, flushing every event as it arrives",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "gateway"
+                ],
+                "summary": "Stream weather updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CEP (8 digits)",
+                        "name": "cep",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream from the orchestration service",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid zipcode",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Orchestration service unreachable",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "No orchestrator available",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Orchestration service did not answer before the idle timeout",
+                        "schema": {
+                            "$ref": "#/definitions/gateway.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -96,6 +285,135 @@ const docTemplate = `{
                 }
             }
         },
+        "/health/ready": {
+            "get": {
+                "description": "Informa se o serviço consegue responder consultas de clima, considerando a cota da WeatherAPI.\n\"degraded\" indica cota em economia ou esgotada com provedor de fallback disponível.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "Pronto (ready ou degraded)",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReadinessResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Cota esgotada sem fallback",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReadinessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quota": {
+            "get": {
+                "description": "Retorna as chamadas e o saldo das cotas mensal e diária da WeatherAPI, a projeção para o fim do mês e quantas consultas cada provedor respondeu",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Consumo da cota da WeatherAPI",
+                "responses": {
+                    "200": {
+                        "description": "Consumo da cota",
+                        "schema": {
+                            "$ref": "#/definitions/service.QuotaStats"
+                        }
+                    },
+                    "404": {
+                        "description": "Contagem da cota desabilitada",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slo": {
+            "get": {
+                "description": "Retorna a disponibilidade (respostas sem 5xx) e a latência (respostas bem-sucedidas dentro do limite)\ndas rotas monitoradas na janela móvel, comparadas aos objetivos configurados e com o error budget restante",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Objetivos de nível de serviço (SLO)",
+                "responses": {
+                    "200": {
+                        "description": "Estado dos objetivos na janela",
+                        "schema": {
+                            "$ref": "#/definitions/slo.Report"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro ao ler as métricas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "Retorna o total de consultas, erros, latência p95, consultas por provedor e as cidades mais consultadas na janela informada",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Estatísticas de consultas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "24h",
+                        "description": "Janela de tempo (duração Go, ex.: 1h, 24h)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Quantidade de cidades no ranking (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estatísticas da janela",
+                        "schema": {
+                            "$ref": "#/definitions/analytics.Stats"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro ao consultar o banco de analytics",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/weather/{cep}": {
             "get": {
                 "description": "Recebe um CEP brasileiro válido (já validado pelo Gateway) e retorna a temperatura atual em Celsius, Fahrenheit e Kelvin",
@@ -117,6 +435,28 @@ const docTemplate = `{
                         "name": "cep",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de uma resposta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "weatherapi",
+                            "open-meteo"
+                        ],
+                        "type": "string",
+                        "description": "Provedor fixo para a consulta",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provedor fixo, usado quando o parâmetro provider não é informado",
+                        "name": "X-Weather-Provider",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -126,12 +466,69 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.WeatherResponse"
                         }
                     },
+                    "304": {
+                        "description": "Temperatura inalterada desde o ETag informado"
+                    },
+                    "400": {
+                        "description": "Provedor desconhecido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "CEP não encontrado",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Temperatura implausível retornada pelo provedor",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/{cep}/stream": {
+            "get": {
+                "description": "Mantém uma conexão Server-Sent Events e envia a temperatura atualizada a cada intervalo de atualização (STREAM_REFRESH_INTERVAL)\nEventos: \"weather\" (domain.WeatherResponse) e \"error\" (domain.ErrorResponse) quando uma atualização falha; a conexão continua aberta",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Acompanhar temperatura por CEP (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"01310100\"",
+                        "description": "CEP brasileiro (8 dígitos, já validado)",
+                        "name": "cep",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fluxo de eventos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "CEP não encontrado (antes de abrir o fluxo)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Erro interno do servidor",
                         "schema": {
@@ -143,6 +540,76 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "analytics.CityStats": {
+            "type": "object",
+            "properties": {
+                "avg_temp_C": {
+                    "type": "number",
+                    "example": 24.3
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "p95_latency_ms": {
+                    "type": "number",
+                    "example": 180.5
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "analytics.Stats": {
+            "description": "Estatísticas das consultas de CEP processadas",
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "p95_latency_ms": {
+                    "type": "number",
+                    "example": 210.4
+                },
+                "providers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 1500
+                },
+                "since": {
+                    "type": "string"
+                },
+                "top_cities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analytics.CityStats"
+                    }
+                }
+            }
+        },
+        "domain.AlertsResponse": {
+            "description": "Alertas meteorológicos vigentes na cidade do CEP",
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WeatherAlert"
+                    }
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                }
+            }
+        },
         "domain.ErrorResponse": {
             "description": "Resposta de erro da API",
             "type": "object",
@@ -150,6 +617,60 @@ const docTemplate = `{
                 "message": {
                     "type": "string",
                     "example": "invalid zipcode"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                }
+            }
+        },
+        "domain.WeatherAlert": {
+            "description": "Alerta emitido pelo órgão meteorológico para a região",
+            "type": "object",
+            "properties": {
+                "areas": {
+                    "type": "string",
+                    "example": "Grande São Paulo"
+                },
+                "category": {
+                    "type": "string",
+                    "example": "Met"
+                },
+                "certainty": {
+                    "type": "string",
+                    "example": "Likely"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Chuva entre 30 e 60 mm/h"
+                },
+                "effective": {
+                    "type": "string",
+                    "example": "2025-01-10T12:00:00-03:00"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "Tempestade"
+                },
+                "expires": {
+                    "type": "string",
+                    "example": "2025-01-10T23:59:00-03:00"
+                },
+                "headline": {
+                    "type": "string",
+                    "example": "Alerta de tempestade"
+                },
+                "instruction": {
+                    "type": "string",
+                    "example": "Evite áreas alagadas"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "Moderate"
+                },
+                "urgency": {
+                    "type": "string",
+                    "example": "Expected"
                 }
             }
         },
@@ -175,19 +696,255 @@ const docTemplate = `{
                 }
             }
         },
+        "gateway.Address": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "neighborhood": {
+                    "type": "string",
+                    "example": "Bela Vista"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Sudeste"
+                },
+                "street": {
+                    "type": "string",
+                    "example": "Avenida Paulista"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
         "gateway.CEPRequest": {
             "type": "object",
             "properties": {
+                "callback_url": {
+                    "description": "CallbackURL switches to callback mode: the gateway answers 202 and POSTs the result there",
+                    "type": "string"
+                },
                 "cep": {
                     "type": "string"
                 }
             }
         },
+        "gateway.CallbackAccepted": {
+            "type": "object",
+            "properties": {
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "gateway.ErrorResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
+                },
+                "trace_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "gateway.FullCEPResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/gateway.Address"
+                },
+                "weather": {
+                    "$ref": "#/definitions/domain.WeatherResponse"
+                }
+            }
+        },
+        "handler.ReadinessResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ready"
+                },
+                "weather_api_quota": {
+                    "$ref": "#/definitions/service.QuotaStats"
+                }
+            }
+        },
+        "service.QuotaStats": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "conserving": {
+                    "type": "boolean"
+                },
+                "daily_calls": {
+                    "type": "integer"
+                },
+                "daily_quota": {
+                    "type": "integer"
+                },
+                "daily_remaining": {
+                    "type": "integer"
+                },
+                "estimated_cost": {
+                    "type": "number"
+                },
+                "fallback_available": {
+                    "type": "boolean"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "monthly_quota": {
+                    "type": "integer"
+                },
+                "projected_calls": {
+                    "type": "integer"
+                },
+                "projected_remaining": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "reported_remaining": {
+                    "type": "integer"
+                },
+                "served_by_cache": {
+                    "type": "integer"
+                },
+                "served_by_fallback": {
+                    "type": "integer"
+                },
+                "served_by_weatherapi": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "throttled": {
+                    "type": "integer"
+                }
+            }
+        },
+        "slo.LatencyObjective": {
+            "type": "object",
+            "properties": {
+                "error_budget_remaining": {
+                    "description": "ErrorBudgetRemaining is the unspent fraction of the budget; negative once overspent",
+                    "type": "number",
+                    "example": 0.74
+                },
+                "good": {
+                    "type": "integer",
+                    "example": 1498
+                },
+                "objective": {
+                    "type": "number",
+                    "example": 0.995
+                },
+                "sli": {
+                    "description": "SLI is the fraction of good requests; 1 without requests",
+                    "type": "number",
+                    "example": 0.9987
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "threshold_ms": {
+                    "type": "number",
+                    "example": 500
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1500
+                }
+            }
+        },
+        "slo.Objective": {
+            "type": "object",
+            "properties": {
+                "error_budget_remaining": {
+                    "description": "ErrorBudgetRemaining is the unspent fraction of the budget; negative once overspent",
+                    "type": "number",
+                    "example": 0.74
+                },
+                "good": {
+                    "type": "integer",
+                    "example": 1498
+                },
+                "objective": {
+                    "type": "number",
+                    "example": 0.995
+                },
+                "sli": {
+                    "description": "SLI is the fraction of good requests; 1 without requests",
+                    "type": "number",
+                    "example": 0.9987
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1500
+                }
+            }
+        },
+        "slo.Report": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "$ref": "#/definitions/slo.Objective"
+                },
+                "latency": {
+                    "$ref": "#/definitions/slo.LatencyObjective"
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "/weather/{cep}"
+                    ]
+                },
+                "status": {
+                    "description": "Status is breached if any objective is breached",
+                    "type": "string",
+                    "example": "ok"
+                },
+                "window": {
+                    "type": "string",
+                    "example": "1h0m0s"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
                 }
             }
         }
@@ -200,6 +957,10 @@ const docTemplate = `{
         {
             "description": "Health check da aplicação",
             "name": "health"
+        },
+        {
+            "description": "Estatísticas das consultas processadas",
+            "name": "stats"
         }
     ]
 }`
@@ -0,0 +1,108 @@
+package analytics
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maxBatchSize  = 100
+	flushInterval = time.Second
+)
+
+// inserter is the part of Store used by Recorder
+type inserter interface {
+	Insert(ctx context.Context, queries []Query) error
+}
+
+// Recorder buffers queries and writes them in batches off the request path.
+// Queries are dropped (and counted) when the buffer is full.
+type Recorder struct {
+	store   inserter
+	queue   chan Query
+	dropped atomic.Int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewRecorder starts a background writer with room for bufferSize pending queries
+func NewRecorder(store inserter, bufferSize int) *Recorder {
+	r := &Recorder{
+		store: store,
+		queue: make(chan Query, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record enqueues a query without blocking
+func (r *Recorder) Record(q Query) {
+	if q.Timestamp.IsZero() {
+		q.Timestamp = time.Now()
+	}
+	select {
+	case r.queue <- q:
+	default:
+		if r.dropped.Add(1)%100 == 1 {
+			log.Printf("[ORCHESTRATOR] Analytics buffer full, dropping queries (%d dropped so far)", r.dropped.Load())
+		}
+	}
+}
+
+// Dropped returns how many queries were discarded because the buffer was full
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close waits until the pending queries are written or ctx expires. Call it
+// after the servers have stopped: Record must not be called afterwards.
+func (r *Recorder) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() { close(r.queue) })
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Query, 0, maxBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := r.store.Insert(ctx, batch); err != nil {
+			log.Printf("[ORCHESTRATOR] Failed to persist %d analytics queries: %v", len(batch), err)
+		}
+		cancel()
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case q, ok := <-r.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, q)
+			if len(batch) == maxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeInserter collects inserted batches, optionally blocking until released
+type fakeInserter struct {
+	mu      sync.Mutex
+	queries []Query
+	batches int
+	// started is signalled when Insert starts; Insert then waits for release
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeInserter) Insert(ctx context.Context, queries []Query) error {
+	if f.release != nil {
+		f.started <- struct{}{}
+		<-f.release
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, queries...)
+	f.batches++
+	return nil
+}
+
+func TestRecorder_FlushesOnClose(t *testing.T) {
+	store := &fakeInserter{}
+	recorder := NewRecorder(store, 10)
+
+	recorder.Record(Query{CEP: "01310100", Status: 200})
+	recorder.Record(Query{CEP: "20040020", Status: 404})
+
+	if err := recorder.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(store.queries) != 2 {
+		t.Fatalf("Expected 2 persisted queries, got %d", len(store.queries))
+	}
+	if store.queries[0].Timestamp.IsZero() {
+		t.Error("Expected timestamp to be set")
+	}
+}
+
+func TestRecorder_BatchesBySize(t *testing.T) {
+	store := &fakeInserter{}
+	recorder := NewRecorder(store, 500)
+
+	for i := 0; i < maxBatchSize*2+1; i++ {
+		recorder.Record(Query{Status: 200})
+	}
+	recorder.Close(context.Background())
+
+	if len(store.queries) != maxBatchSize*2+1 || store.batches != 3 {
+		t.Errorf("Expected %d queries in 3 batches, got %d in %d", maxBatchSize*2+1, len(store.queries), store.batches)
+	}
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	store := &fakeInserter{started: make(chan struct{}, 10), release: make(chan struct{})}
+	recorder := NewRecorder(store, 2)
+
+	// The periodic flush of the first query keeps the writer busy in Insert
+	recorder.Record(Query{Status: 200})
+	<-store.started
+
+	for i := 0; i < 5; i++ {
+		recorder.Record(Query{Status: 200})
+	}
+	if recorder.Dropped() != 3 {
+		t.Errorf("Expected 3 dropped queries, got %d", recorder.Dropped())
+	}
+
+	close(store.release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := recorder.Close(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(store.queries) != 3 {
+		t.Errorf("Expected 3 persisted queries, got %d", len(store.queries))
+	}
+}
@@ -0,0 +1,241 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"  // postgres driver
+	_ "modernc.org/sqlite" // sqlite driver (pure Go)
+)
+
+// Supported drivers
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// ErrUnknownDriver is returned by Open for drivers other than sqlite and postgres
+var ErrUnknownDriver = errors.New("analytics driver must be sqlite or postgres")
+
+// Query is one CEP lookup processed by the orchestrator
+type Query struct {
+	CEP       string
+	City      string
+	TempC     float64
+	Latency   time.Duration
+	Provider  string
+	Status    int
+	Timestamp time.Time
+}
+
+// CityStats aggregates the lookups of one city
+type CityStats struct {
+	City         string  `json:"city" example:"São Paulo"`
+	Requests     int64   `json:"requests" example:"120"`
+	AvgTempC     float64 `json:"avg_temp_C" example:"24.3"`
+	P95LatencyMs float64 `json:"p95_latency_ms" example:"180.5"`
+}
+
+// Stats summarizes the lookups of a time window
+// @Description Estatísticas das consultas de CEP processadas
+type Stats struct {
+	Since        time.Time        `json:"since"`
+	Requests     int64            `json:"requests" example:"1500"`
+	Errors       int64            `json:"errors" example:"12"`
+	P95LatencyMs float64          `json:"p95_latency_ms" example:"210.4"`
+	Providers    map[string]int64 `json:"providers"`
+	TopCities    []CityStats      `json:"top_cities"`
+}
+
+// Store persists queries in SQLite or Postgres
+type Store struct {
+	db     *sql.DB
+	driver string
+}
+
+// Open connects to the database and creates the cep_queries table if needed
+func Open(ctx context.Context, driver, dsn string) (*Store, error) {
+	if driver != DriverSQLite && driver != DriverPostgres {
+		return nil, ErrUnknownDriver
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open analytics database: %w", err)
+	}
+	if driver == DriverSQLite {
+		// SQLite allows a single writer; serialize access instead of failing with SQLITE_BUSY
+		db.SetMaxOpenConns(1)
+	}
+
+	s := &Store{db: db, driver: driver}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) migrate(ctx context.Context) error {
+	id := "INTEGER PRIMARY KEY AUTOINCREMENT"
+	if s.driver == DriverPostgres {
+		id = "BIGSERIAL PRIMARY KEY"
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS cep_queries (
+			id ` + id + `,
+			cep TEXT NOT NULL,
+			city TEXT NOT NULL,
+			temp_c DOUBLE PRECISION NOT NULL,
+			latency_ms DOUBLE PRECISION NOT NULL,
+			provider TEXT NOT NULL,
+			status INTEGER NOT NULL,
+			created_at BIGINT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_cep_queries_created_at ON cep_queries (created_at)`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to migrate analytics database: %w", err)
+		}
+	}
+	return nil
+}
+
+// Insert stores a batch of queries in a single transaction
+func (s *Store) Insert(ctx context.Context, queries []Query) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(
+		`INSERT INTO cep_queries (cep, city, temp_c, latency_ms, provider, status, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, q := range queries {
+		if _, err := stmt.ExecContext(ctx, q.CEP, q.City, q.TempC, millis(q.Latency), q.Provider, q.Status, q.Timestamp.UnixMilli()); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Stats aggregates the queries since the given time, listing the top cities by request count
+func (s *Store) Stats(ctx context.Context, since time.Time, topCities int) (*Stats, error) {
+	from := since.UnixMilli()
+	stats := &Stats{Since: since.UTC(), Providers: map[string]int64{}, TopCities: []CityStats{}}
+
+	err := s.db.QueryRowContext(ctx, s.rebind(
+		`SELECT COUNT(*), COALESCE(SUM(CASE WHEN status >= 400 THEN 1 ELSE 0 END), 0) FROM cep_queries WHERE created_at >= ?`),
+		from).Scan(&stats.Requests, &stats.Errors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count queries: %w", err)
+	}
+	if stats.Requests == 0 {
+		return stats, nil
+	}
+
+	if stats.P95LatencyMs, err = s.p95(ctx, "created_at >= ?", stats.Requests, from); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(
+		`SELECT provider, COUNT(*) FROM cep_queries WHERE created_at >= ? AND provider <> '' GROUP BY provider`), from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count providers: %w", err)
+	}
+	for rows.Next() {
+		var provider string
+		var count int64
+		if err := rows.Scan(&provider, &count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.Providers[provider] = count
+	}
+	rows.Close()
+
+	rows, err = s.db.QueryContext(ctx, s.rebind(
+		`SELECT city, COUNT(*), AVG(temp_c) FROM cep_queries
+		WHERE created_at >= ? AND status = 200
+		GROUP BY city ORDER BY COUNT(*) DESC, city LIMIT ?`), from, topCities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank cities: %w", err)
+	}
+	for rows.Next() {
+		var city CityStats
+		if err := rows.Scan(&city.City, &city.Requests, &city.AvgTempC); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		city.AvgTempC = round(city.AvgTempC)
+		stats.TopCities = append(stats.TopCities, city)
+	}
+	rows.Close()
+
+	for i := range stats.TopCities {
+		city := &stats.TopCities[i]
+		if city.P95LatencyMs, err = s.p95(ctx, "created_at >= ? AND status = 200 AND city = ?", city.Requests, from, city.City); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+// p95 returns the nearest-rank 95th percentile latency of the count rows
+// matching where, using ORDER BY/OFFSET so it works on both databases
+func (s *Store) p95(ctx context.Context, where string, count int64, args ...any) (float64, error) {
+	offset := int64(math.Ceil(0.95*float64(count))) - 1
+	args = append(args, offset)
+
+	var latency float64
+	err := s.db.QueryRowContext(ctx, s.rebind(
+		`SELECT latency_ms FROM cep_queries WHERE `+where+` ORDER BY latency_ms LIMIT 1 OFFSET ?`), args...).Scan(&latency)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute p95 latency: %w", err)
+	}
+	return round(latency), nil
+}
+
+// rebind converts ? placeholders to $n for Postgres
+func (s *Store) rebind(query string) string {
+	if s.driver != DriverPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,130 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(context.Background(), DriverSQLite, filepath.Join(t.TempDir(), "analytics.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestOpen_UnknownDriver(t *testing.T) {
+	if _, err := Open(context.Background(), "mysql", "dsn"); err != ErrUnknownDriver {
+		t.Errorf("Expected ErrUnknownDriver, got %v", err)
+	}
+}
+
+func TestStoreStats(t *testing.T) {
+	store := openTestStore(t)
+	now := time.Now()
+
+	var queries []Query
+	for i := 1; i <= 20; i++ {
+		queries = append(queries, Query{
+			CEP: "01310100", City: "São Paulo", TempC: 20 + float64(i%2)*2,
+			Latency: time.Duration(i) * time.Millisecond, Provider: "weatherapi", Status: 200, Timestamp: now,
+		})
+	}
+	for i := 1; i <= 5; i++ {
+		queries = append(queries, Query{
+			CEP: "20040020", City: "Rio de Janeiro", TempC: 30,
+			Latency: time.Duration(100*i) * time.Millisecond, Provider: "cache", Status: 200, Timestamp: now,
+		})
+	}
+	queries = append(queries,
+		Query{CEP: "99999999", Latency: 5 * time.Millisecond, Status: 404, Timestamp: now},
+		// Outside the window
+		Query{CEP: "30112000", City: "Belo Horizonte", TempC: 25, Latency: time.Millisecond,
+			Provider: "weatherapi", Status: 200, Timestamp: now.Add(-2 * time.Hour)},
+	)
+
+	if err := store.Insert(context.Background(), queries); err != nil {
+		t.Fatalf("Failed to insert queries: %v", err)
+	}
+
+	stats, err := store.Stats(context.Background(), now.Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Failed to compute stats: %v", err)
+	}
+
+	if stats.Requests != 26 || stats.Errors != 1 {
+		t.Errorf("Expected 26 requests and 1 error, got %d and %d", stats.Requests, stats.Errors)
+	}
+	if stats.Providers["weatherapi"] != 20 || stats.Providers["cache"] != 5 || len(stats.Providers) != 2 {
+		t.Errorf("Unexpected providers: %v", stats.Providers)
+	}
+	// 26 latencies: 1..20, 5, 100..500 -> 25th smallest is 400ms
+	if stats.P95LatencyMs != 400 {
+		t.Errorf("Expected p95 of 400ms, got %v", stats.P95LatencyMs)
+	}
+
+	if len(stats.TopCities) != 2 {
+		t.Fatalf("Expected 2 cities, got %+v", stats.TopCities)
+	}
+	sp := stats.TopCities[0]
+	if sp.City != "São Paulo" || sp.Requests != 20 || sp.AvgTempC != 21 || sp.P95LatencyMs != 19 {
+		t.Errorf("Unexpected São Paulo stats: %+v", sp)
+	}
+	rio := stats.TopCities[1]
+	if rio.City != "Rio de Janeiro" || rio.P95LatencyMs != 500 {
+		t.Errorf("Unexpected Rio de Janeiro stats: %+v", rio)
+	}
+}
+
+func TestStoreStats_Empty(t *testing.T) {
+	store := openTestStore(t)
+
+	stats, err := store.Stats(context.Background(), time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Failed to compute stats: %v", err)
+	}
+	if stats.Requests != 0 || stats.TopCities == nil || stats.Providers == nil {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestStoreStats_TopCitiesLimit(t *testing.T) {
+	store := openTestStore(t)
+	now := time.Now()
+
+	var queries []Query
+	for i := 0; i < 5; i++ {
+		for j := 0; j <= i; j++ {
+			queries = append(queries, Query{City: fmt.Sprintf("City %d", i), Status: 200, Latency: time.Millisecond, Timestamp: now})
+		}
+	}
+	if err := store.Insert(context.Background(), queries); err != nil {
+		t.Fatalf("Failed to insert queries: %v", err)
+	}
+
+	stats, err := store.Stats(context.Background(), now.Add(-time.Minute), 2)
+	if err != nil {
+		t.Fatalf("Failed to compute stats: %v", err)
+	}
+	if len(stats.TopCities) != 2 || stats.TopCities[0].City != "City 4" || stats.TopCities[1].City != "City 3" {
+		t.Errorf("Unexpected ranking: %+v", stats.TopCities)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	postgres := &Store{driver: DriverPostgres}
+	sqlite := &Store{driver: DriverSQLite}
+	query := "SELECT * FROM t WHERE a = ? AND b = ?"
+
+	if got := postgres.rebind(query); got != "SELECT * FROM t WHERE a = $1 AND b = $2" {
+		t.Errorf("Unexpected postgres query: %s", got)
+	}
+	if got := sqlite.rebind(query); got != query {
+		t.Errorf("Unexpected sqlite query: %s", got)
+	}
+}
@@ -1,17 +1,24 @@
 package domain
 
+import "context"
+
 // WeatherService define a interface para serviços de clima
 type WeatherService interface {
-	GetLocationByCEP(cep string) (*ViaCEPResponse, error)
-	GetWeatherByLocation(location string) (*WeatherAPIResponse, error)
+	GetLocationByCEP(ctx context.Context, cep string) (*ViaCEPResponse, error)
+	GetWeatherByLocation(ctx context.Context, location string) (*WeatherAPIResponse, error)
 }
 
 // LocationService define a interface para serviços de localização
 type LocationService interface {
-	GetLocationByCEP(cep string) (*ViaCEPResponse, error)
+	GetLocationByCEP(ctx context.Context, cep string) (*ViaCEPResponse, error)
 }
 
 // WeatherDataService define a interface para dados meteorológicos
 type WeatherDataService interface {
-	GetWeatherByLocation(location string) (*WeatherAPIResponse, error)
+	GetWeatherByLocation(ctx context.Context, location string) (*WeatherAPIResponse, error)
+}
+
+// AlertDataService define a interface para alertas meteorológicos
+type AlertDataService interface {
+	GetAlertsByLocation(ctx context.Context, location string) ([]WeatherAlert, error)
 }
@@ -7,12 +7,18 @@ type WeatherResponse struct {
 	TempC float64 `json:"temp_C" example:"28.5" description:"Temperatura em Celsius"`
 	TempF float64 `json:"temp_F" example:"83.3" description:"Temperatura em Fahrenheit"`
 	TempK float64 `json:"temp_K" example:"301.5" description:"Temperatura em Kelvin"`
+
+	// Stale marks a cached reading served in place of an anomalous one
+	Stale bool `json:"-"`
+	// Provider names where the reading came from (weatherapi, open-meteo, cache)
+	Provider string `json:"-"`
 }
 
 // ErrorResponse representa uma resposta de erro
 // @Description Resposta de erro da API
 type ErrorResponse struct {
 	Message string `json:"message" example:"invalid zipcode" description:"Mensagem de erro"`
+	TraceID string `json:"trace_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736" description:"ID do trace no Zipkin"`
 }
 
 // ViaCEPResponse representa a resposta da API ViaCEP
@@ -30,6 +36,51 @@ type WeatherAPIResponse struct {
 	Current struct {
 		TempC float64 `json:"temp_c"`
 	} `json:"current"`
+
+	// Provider names where the reading came from; set by the repositories
+	Provider string `json:"-"`
+}
+
+// WeatherAlert representa um alerta meteorológico oficial
+// @Description Alerta emitido pelo órgão meteorológico para a região
+type WeatherAlert struct {
+	Headline    string `json:"headline" example:"Alerta de tempestade" description:"Título do alerta"`
+	Event       string `json:"event" example:"Tempestade" description:"Tipo de evento"`
+	Severity    string `json:"severity" example:"Moderate" description:"Severidade (Minor, Moderate, Severe, Extreme)"`
+	Urgency     string `json:"urgency" example:"Expected" description:"Urgência"`
+	Certainty   string `json:"certainty" example:"Likely" description:"Certeza"`
+	Category    string `json:"category" example:"Met" description:"Categoria"`
+	Areas       string `json:"areas" example:"Grande São Paulo" description:"Áreas afetadas"`
+	Description string `json:"description" example:"Chuva entre 30 e 60 mm/h" description:"Descrição do alerta"`
+	Instruction string `json:"instruction,omitempty" example:"Evite áreas alagadas" description:"Instruções à população"`
+	Effective   string `json:"effective" example:"2025-01-10T12:00:00-03:00" description:"Início da vigência (ISO 8601)"`
+	Expires     string `json:"expires" example:"2025-01-10T23:59:00-03:00" description:"Fim da vigência (ISO 8601)"`
+}
+
+// AlertsResponse representa a resposta com os alertas vigentes para um CEP
+// @Description Alertas meteorológicos vigentes na cidade do CEP
+type AlertsResponse struct {
+	City   string         `json:"city" example:"São Paulo" description:"Nome da cidade"`
+	Alerts []WeatherAlert `json:"alerts" description:"Alertas vigentes (vazio quando não há alertas)"`
+}
+
+// WeatherAPIAlertsResponse representa os alertas da API de clima (forecast.json com alerts=yes)
+type WeatherAPIAlertsResponse struct {
+	Alerts struct {
+		Alert []struct {
+			Headline    string `json:"headline"`
+			Severity    string `json:"severity"`
+			Urgency     string `json:"urgency"`
+			Areas       string `json:"areas"`
+			Category    string `json:"category"`
+			Certainty   string `json:"certainty"`
+			Event       string `json:"event"`
+			Effective   string `json:"effective"`
+			Expires     string `json:"expires"`
+			Desc        string `json:"desc"`
+			Instruction string `json:"instruction"`
+		} `json:"alert"`
+	} `json:"alerts"`
 }
 
 // Location representa uma localização
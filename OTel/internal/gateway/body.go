@@ -0,0 +1,100 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the default request body limit; a CEP payload is a few dozen bytes
+const DefaultMaxBodyBytes = 4 << 10
+
+var (
+	// ErrEmptyBody is returned when the request has no JSON document
+	ErrEmptyBody = errors.New("empty request body")
+
+	// ErrTrailingData is returned when the JSON document is followed by more data
+	ErrTrailingData = errors.New("unexpected data after JSON object")
+)
+
+// RequestGuard rejects bodies larger than maxBytes with 413 and non-JSON
+// content types with 415 before the handler decodes anything. Bodies without
+// a Content-Length are capped while being read.
+func RequestGuard(maxBytes int64) func(http.Handler) http.Handler {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+
+	log.Printf("[GATEWAY] Initializing request guard - max body: %d bytes", maxBytes)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isJSONContentType(r.Header.Get("Content-Type")) {
+				log.Printf("[GATEWAY] Rejecting %s %s: unsupported content type %q", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+				writeGuardError(w, http.StatusUnsupportedMediaType, "content type must be application/json")
+				return
+			}
+			if r.ContentLength > maxBytes {
+				log.Printf("[GATEWAY] Rejecting %s %s: body of %d bytes exceeds %d", r.Method, r.URL.Path, r.ContentLength, maxBytes)
+				writeGuardError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isJSONContentType accepts application/json and structured syntax suffixes such as application/problem+json
+func isJSONContentType(contentType string) bool {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if charset, ok := params["charset"]; ok && !strings.EqualFold(charset, "utf-8") {
+		return false
+	}
+	return mediaType == "application/json" ||
+		(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
+
+func writeGuardError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Message: message})
+}
+
+// DecodeCEPRequest parses exactly one JSON object from body. The "cep" field
+// must be a string; unknown fields are ignored but trailing data is rejected.
+func DecodeCEPRequest(body io.Reader) (CEPRequest, error) {
+	var req CEPRequest
+
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&req); err != nil {
+		if errors.Is(err, io.EOF) {
+			return CEPRequest{}, ErrEmptyBody
+		}
+		return CEPRequest{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	// Anything after the object other than whitespace is an error
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		if isBodyTooLarge(err) {
+			return CEPRequest{}, err
+		}
+		return CEPRequest{}, ErrTrailingData
+	}
+	return req, nil
+}
+
+// isBodyTooLarge reports whether err comes from a body capped by RequestGuard
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRequestGuard(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		chunked        bool
+		expectedStatus int
+	}{
+		{"JSON body", "application/json", `{"cep":"29902555"}`, false, http.StatusOK},
+		{"JSON with charset", "application/json; charset=UTF-8", `{"cep":"29902555"}`, false, http.StatusOK},
+		{"JSON suffix", "application/vnd.cep+json", `{"cep":"29902555"}`, false, http.StatusOK},
+		{"Missing content type", "", `{"cep":"29902555"}`, false, http.StatusUnsupportedMediaType},
+		{"Form content type", "application/x-www-form-urlencoded", "cep=29902555", false, http.StatusUnsupportedMediaType},
+		{"Non UTF-8 charset", "application/json; charset=latin1", `{"cep":"29902555"}`, false, http.StatusUnsupportedMediaType},
+		{"Oversized body", "application/json", `{"cep":"` + strings.Repeat("1", 64) + `"}`, false, http.StatusRequestEntityTooLarge},
+		{"Oversized chunked body", "application/json", `{"cep":"` + strings.Repeat("1", 64) + `"}`, true, http.StatusRequestEntityTooLarge},
+	}
+
+	handler := RequestGuard(32)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := DecodeCEPRequest(r.Body); isBodyTooLarge(err) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/cep", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestGatewayHandler_ProcessCEP_BodyTooLarge(t *testing.T) {
+	handler := RequestGuard(16)(http.HandlerFunc(NewGatewayHandler("http://localhost:8080").ProcessCEP))
+
+	req := httptest.NewRequest("POST", "/cep", strings.NewReader(`{"cep":"29902555","padding":"xxxxxxxx"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, got %d", http.StatusRequestEntityTooLarge, rr.Code)
+	}
+}
+
+func TestDecodeCEPRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		expectedCEP string
+		expectedErr error
+		expectError bool
+	}{
+		{"Valid object", `{"cep":"29902555"}`, "29902555", nil, false},
+		{"Unknown fields ignored", `{"cep":"29902555","source":"web"}`, "29902555", nil, false},
+		{"Trailing whitespace", "{\"cep\":\"29902555\"}\n", "29902555", nil, false},
+		{"Empty body", "", "", ErrEmptyBody, true},
+		{"Trailing object", `{"cep":"29902555"}{"cep":"01001000"}`, "", ErrTrailingData, true},
+		{"Trailing garbage", `{"cep":"29902555"} x`, "", ErrTrailingData, true},
+		{"Numeric CEP", `{"cep":29902555}`, "", nil, true},
+		{"Array", `["29902555"]`, "", nil, true},
+		{"Truncated", `{"cep":"2990`, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := DecodeCEPRequest(strings.NewReader(tt.body))
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("Expected error, got request %+v", req)
+				}
+				if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if req.CEP != tt.expectedCEP {
+				t.Errorf("Expected CEP %q, got %q", tt.expectedCEP, req.CEP)
+			}
+		})
+	}
+}
+
+func FuzzDecodeCEPRequest(f *testing.F) {
+	f.Add(`{"cep":"29902555"}`)
+	f.Add(`{"cep":"29902-555"}`)
+	f.Add(`{"cep":"29"}`)
+	f.Add(`{"cep":null}`)
+	f.Add(`null`)
+	f.Add(`{"cep":"29902555"}{}`)
+	f.Add(`[[[[[[[[[[`)
+	f.Add("\xff\xfe")
+
+	f.Fuzz(func(t *testing.T, body string) {
+		req, err := DecodeCEPRequest(strings.NewReader(body))
+		if err != nil {
+			if req.CEP != "" {
+				t.Errorf("Expected empty request on error, got %+v", req)
+			}
+			return
+		}
+		if !utf8.ValidString(req.CEP) {
+			t.Errorf("Decoded CEP is not valid UTF-8: %q", req.CEP)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"otel/pkg/httpclient"
+)
+
+// Headers sent with every callback delivery
+const (
+	CallbackSignatureHeader = "X-Callback-Signature"
+	CallbackTimestampHeader = "X-Callback-Timestamp"
+	CallbackRequestIDHeader = "X-Callback-Request-Id"
+)
+
+var (
+	// ErrInvalidCallbackURL is returned for callback URLs that are not absolute http(s) URLs
+	ErrInvalidCallbackURL = errors.New("invalid callback_url")
+
+	// ErrCallbackHostNotAllowed is returned when the callback host is not in the allowlist
+	ErrCallbackHostNotAllowed = errors.New("callback_url host is not allowed")
+)
+
+// CallbackPayload is POSTed to the callback URL once the CEP is processed
+type CallbackPayload struct {
+	RequestID  string          `json:"request_id"`
+	CEP        string          `json:"cep"`
+	StatusCode int             `json:"status_code"`
+	Weather    json.RawMessage `json:"weather,omitempty" swaggertype:"object"`
+	Error      *ErrorResponse  `json:"error,omitempty"`
+}
+
+// CallbackAccepted is the 202 response of a request in callback mode
+type CallbackAccepted struct {
+	RequestID string `json:"request_id"`
+	Status    string `json:"status"`
+}
+
+// CallbackDispatcher delivers signed callbacks in the background, retrying
+// network errors, 429 and 5xx responses with exponential backoff
+type CallbackDispatcher struct {
+	client       *http.Client
+	secret       []byte
+	maxAttempts  int
+	backoff      time.Duration
+	allowedHosts map[string]bool
+
+	pending chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewCallbackDispatcher creates a dispatcher signing payloads with secret.
+// At most maxPending callbacks are processed at once.
+func NewCallbackDispatcher(secret string, maxAttempts int, backoff time.Duration, maxPending int) *CallbackDispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	if maxPending <= 0 {
+		maxPending = 1
+	}
+
+	log.Printf("[GATEWAY] Initializing callback dispatcher - max attempts: %d, backoff: %v, max pending: %d", maxAttempts, backoff, maxPending)
+
+	return &CallbackDispatcher{
+		client:      httpclient.New(httpclient.WithTimeout(10 * time.Second)),
+		secret:      []byte(secret),
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		pending:     make(chan struct{}, maxPending),
+	}
+}
+
+// WithAllowedHosts restricts callbacks to the given hosts (empty allows any host)
+func (d *CallbackDispatcher) WithAllowedHosts(hosts []string) *CallbackDispatcher {
+	d.allowedHosts = make(map[string]bool)
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			d.allowedHosts[host] = true
+		}
+	}
+	return d
+}
+
+// Validate checks that rawURL is an acceptable callback URL
+func (d *CallbackDispatcher) Validate(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidCallbackURL
+	}
+	if len(d.allowedHosts) > 0 && !d.allowedHosts[strings.ToLower(parsed.Hostname())] {
+		return ErrCallbackHostNotAllowed
+	}
+	return nil
+}
+
+// Go runs fn in the background, or returns false when too many callbacks are pending
+func (d *CallbackDispatcher) Go(fn func()) bool {
+	select {
+	case d.pending <- struct{}{}:
+	default:
+		return false
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer func() {
+			<-d.pending
+			d.wg.Done()
+		}()
+		fn()
+	}()
+	return true
+}
+
+// Wait blocks until pending callbacks finish or ctx is done
+func (d *CallbackDispatcher) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Deliver POSTs payload to callbackURL, retrying until it is accepted,
+// a non-retryable status is returned or the attempts run out
+func (d *CallbackDispatcher) Deliver(ctx context.Context, callbackURL string, payload CallbackPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode callback payload: %w", err)
+	}
+
+	backoff := d.backoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := d.send(ctx, callbackURL, payload.RequestID, body)
+		if err == nil {
+			log.Printf("[GATEWAY] Callback %s delivered to %s on attempt %d", payload.RequestID, callbackURL, attempt)
+			return nil
+		}
+		if !retryable || attempt >= d.maxAttempts {
+			return fmt.Errorf("callback delivery failed after %d attempts: %w", attempt, err)
+		}
+
+		log.Printf("[GATEWAY] Callback %s attempt %d failed: %v, retrying in %v", payload.RequestID, attempt, err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send makes one delivery attempt and reports whether a failure may be retried
+func (d *CallbackDispatcher) send(ctx context.Context, callbackURL, requestID string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CallbackRequestIDHeader, requestID)
+	req.Header.Set(CallbackTimestampHeader, timestamp)
+	req.Header.Set(CallbackSignatureHeader, SignCallback(d.secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("callback returned status %d", resp.StatusCode)
+}
+
+// SignCallback returns the signature header value for a callback body:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>".
+// Receivers should recompute it and reject old timestamps to prevent replays.
+func SignCallback(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newRequestID returns a random identifier for a callback request
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,201 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSignCallback(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"cep":"29902555"}`)
+
+	sig := SignCallback(secret, "1700000000", body)
+	if len(sig) != len("sha256=")+64 || sig[:7] != "sha256=" {
+		t.Fatalf("unexpected signature format: %q", sig)
+	}
+	if sig != SignCallback(secret, "1700000000", body) {
+		t.Error("signature is not deterministic")
+	}
+	if sig == SignCallback(secret, "1700000001", body) {
+		t.Error("signature does not cover the timestamp")
+	}
+	if sig == SignCallback([]byte("other"), "1700000000", body) {
+		t.Error("signature does not depend on the secret")
+	}
+}
+
+func TestCallbackDispatcher_Validate(t *testing.T) {
+	dispatcher := NewCallbackDispatcher("secret", 1, 0, 1)
+	allowlisted := NewCallbackDispatcher("secret", 1, 0, 1).WithAllowedHosts([]string{" Hooks.Example.com ", ""})
+
+	tests := []struct {
+		name       string
+		dispatcher *CallbackDispatcher
+		url        string
+		want       error
+	}{
+		{"https url", dispatcher, "https://example.com/hook", nil},
+		{"http url", dispatcher, "http://localhost:9000/hook", nil},
+		{"relative url", dispatcher, "/hook", ErrInvalidCallbackURL},
+		{"unsupported scheme", dispatcher, "ftp://example.com/hook", ErrInvalidCallbackURL},
+		{"malformed url", dispatcher, "http://[::1", ErrInvalidCallbackURL},
+		{"allowed host", allowlisted, "https://hooks.example.com:8443/hook", nil},
+		{"host not allowed", allowlisted, "https://evil.example.com/hook", ErrCallbackHostNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.dispatcher.Validate(tt.url); !errors.Is(err, tt.want) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.url, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallbackDispatcher_DeliverRetriesServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := SignCallback([]byte("secret"), r.Header.Get(CallbackTimestampHeader), body)
+		if got := r.Header.Get(CallbackSignatureHeader); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if r.Header.Get(CallbackRequestIDHeader) != "req-1" {
+			t.Errorf("unexpected request id header %q", r.Header.Get(CallbackRequestIDHeader))
+		}
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	dispatcher := NewCallbackDispatcher("secret", 5, time.Millisecond, 1)
+	err := dispatcher.Deliver(context.Background(), receiver.URL, CallbackPayload{RequestID: "req-1", CEP: "29902555", StatusCode: http.StatusOK})
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestCallbackDispatcher_DeliverStopsOnClientError(t *testing.T) {
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+
+	dispatcher := NewCallbackDispatcher("secret", 5, time.Millisecond, 1)
+	if err := dispatcher.Deliver(context.Background(), receiver.URL, CallbackPayload{RequestID: "req-1"}); err == nil {
+		t.Fatal("expected delivery error")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestCallbackDispatcher_GoRejectsWhenFull(t *testing.T) {
+	dispatcher := NewCallbackDispatcher("secret", 1, 0, 1)
+	release := make(chan struct{})
+
+	if !dispatcher.Go(func() { <-release }) {
+		t.Fatal("first callback should be accepted")
+	}
+	if dispatcher.Go(func() {}) {
+		t.Error("second callback should be rejected while the first is pending")
+	}
+
+	close(release)
+	if err := dispatcher.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+	if !dispatcher.Go(func() {}) {
+		t.Error("callback should be accepted after the pending one finished")
+	}
+}
+
+func TestGatewayHandler_ProcessCEP_CallbackMode(t *testing.T) {
+	orchestration := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"city": "Linhares", "temp_C": 25.0})
+	}))
+	defer orchestration.Close()
+
+	received := make(chan CallbackPayload, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(CallbackSignatureHeader) != SignCallback([]byte("secret"), r.Header.Get(CallbackTimestampHeader), body) {
+			t.Error("callback signature does not match")
+		}
+		var payload CallbackPayload
+		json.Unmarshal(body, &payload)
+		received <- payload
+	}))
+	defer receiver.Close()
+
+	dispatcher := NewCallbackDispatcher("secret", 3, time.Millisecond, 10)
+	handler := NewGatewayHandler(orchestration.URL).WithCallbacks(dispatcher)
+
+	body, _ := json.Marshal(CEPRequest{CEP: "29902555", CallbackURL: receiver.URL})
+	req := httptest.NewRequest("POST", "/cep", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.ProcessCEP(rr, req)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var accepted CallbackAccepted
+	if err := json.Unmarshal(rr.Body.Bytes(), &accepted); err != nil || accepted.RequestID == "" {
+		t.Fatalf("unexpected accepted response %q: %v", rr.Body.String(), err)
+	}
+
+	select {
+	case payload := <-received:
+		if payload.RequestID != accepted.RequestID || payload.StatusCode != http.StatusOK || payload.CEP != "29902555" {
+			t.Errorf("unexpected payload: %+v", payload)
+		}
+		var weather map[string]interface{}
+		if err := json.Unmarshal(payload.Weather, &weather); err != nil || weather["city"] != "Linhares" {
+			t.Errorf("unexpected weather in payload: %s", payload.Weather)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+}
+
+func TestGatewayHandler_ProcessCEP_CallbackRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    *GatewayHandler
+		url        string
+		wantStatus int
+	}{
+		{"callback mode disabled", NewGatewayHandler("http://localhost:8080"), "https://example.com/hook", http.StatusBadRequest},
+		{"invalid callback url", NewGatewayHandler("http://localhost:8080").WithCallbacks(NewCallbackDispatcher("secret", 1, 0, 1)), "not-a-url", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(CEPRequest{CEP: "29902555", CallbackURL: tt.url})
+			req := httptest.NewRequest("POST", "/cep", bytes.NewBuffer(body))
+			rr := httptest.NewRecorder()
+			tt.handler.ProcessCEP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"context"
+	"log"
+
+	"otel/pkg/featureflag"
+	"otel/pkg/validator"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// forwardDeduplicated forwards the CEP, sharing a single orchestrator call
+// among concurrent requests for the same CEP unless the request_dedup flag is off.
+//
+// The shared call runs detached from the cancellation of the request that
+// started it, so a client going away does not fail the others; each caller
+// still stops waiting when its own context ends.
+func (h *GatewayHandler) forwardDeduplicated(ctx context.Context, cep string) (*OrchestrationResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	if !h.flags.Enabled(featureflag.RequestDedup) {
+		span.SetAttributes(attribute.Bool("dedup.enabled", false))
+		return h.forward(ctx, cep)
+	}
+
+	key := validator.CleanCEP(cep)
+	leader := false
+	results := h.inflight.DoChan(key, func() (any, error) {
+		leader = true
+		return h.forward(context.WithoutCancel(ctx), cep)
+	})
+
+	select {
+	case res := <-results:
+		// leader is written before the result is sent, so reading it here is safe
+		span.SetAttributes(
+			attribute.Bool("dedup.enabled", true),
+			attribute.Bool("dedup.shared", res.Shared),
+			attribute.Bool("dedup.leader", leader),
+		)
+		if res.Shared && !leader {
+			log.Printf("[GATEWAY] Reusing in-flight orchestrator call for CEP %s", key)
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*OrchestrationResponse), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
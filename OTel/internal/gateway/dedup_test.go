@@ -0,0 +1,147 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"otel/pkg/featureflag"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// blockingOrchestrator counts calls and holds each answer until release is closed
+func blockingOrchestrator(release chan struct{}) (*httptest.Server, *atomic.Int32) {
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"city":"São Paulo","temp_C":25,"temp_F":77,"temp_K":298}`))
+	}))
+	return server, calls
+}
+
+// forwardConcurrently starts n traced forwardDeduplicated calls cycling through ceps
+func forwardConcurrently(t *testing.T, h *GatewayHandler, n int, ceps ...string) (*sync.WaitGroup, []error) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, span := h.tracer.Start(context.Background(), "gateway.process_cep")
+			defer span.End()
+			_, errs[i] = h.forwardDeduplicated(ctx, ceps[i%len(ceps)])
+		}(i)
+	}
+	return &wg, errs
+}
+
+func TestForwardDeduplicated_SharesInFlightCall(t *testing.T) {
+	release := make(chan struct{})
+	orchestrator, calls := blockingOrchestrator(release)
+	defer orchestrator.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	h := NewGatewayHandler(orchestrator.URL)
+	h.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	wg, errs := forwardConcurrently(t, h, 5, "01310100", "01310-100")
+	// Let every request join the call before the orchestrator answers
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Request %d: expected no error, got %v", i, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 orchestrator call, got %d", got)
+	}
+
+	var shared, leaders int
+	for _, span := range recorder.Ended() {
+		if span.Name() != "gateway.process_cep" {
+			continue
+		}
+		for _, attr := range span.Attributes() {
+			switch {
+			case attr.Key == "dedup.shared" && attr.Value.AsBool():
+				shared++
+			case attr.Key == "dedup.leader" && attr.Value.AsBool():
+				leaders++
+			}
+		}
+	}
+	if shared != 5 || leaders != 1 {
+		t.Errorf("Expected 5 shared results and 1 leader, got %d shared and %d leaders", shared, leaders)
+	}
+}
+
+func TestForwardDeduplicated_Disabled(t *testing.T) {
+	t.Setenv(featureflag.EnvPrefix+"REQUEST_DEDUP", "false")
+	flags, err := featureflag.New(featureflag.Defaults())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	release := make(chan struct{})
+	close(release)
+	orchestrator, calls := blockingOrchestrator(release)
+	defer orchestrator.Close()
+
+	h := NewGatewayHandler(orchestrator.URL).WithFlags(flags)
+	wg, _ := forwardConcurrently(t, h, 3, "01310100")
+	wg.Wait()
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected 3 orchestrator calls with deduplication disabled, got %d", got)
+	}
+}
+
+func TestForwardDeduplicated_CallerCancellationDoesNotFailOthers(t *testing.T) {
+	release := make(chan struct{})
+	orchestrator, calls := blockingOrchestrator(release)
+	defer orchestrator.Close()
+
+	h := NewGatewayHandler(orchestrator.URL)
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := h.forwardDeduplicated(leaderCtx, "01310100")
+		leaderErr <- err
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := h.forwardDeduplicated(context.Background(), "01310100")
+		followerErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelLeader()
+	if err := <-leaderErr; err != context.Canceled {
+		t.Errorf("Expected the cancelled caller to get context.Canceled, got %v", err)
+	}
+
+	close(release)
+	if err := <-followerErr; err != nil {
+		t.Errorf("Expected the other caller to get the shared result, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 orchestrator call, got %d", got)
+	}
+}
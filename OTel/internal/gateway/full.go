@@ -0,0 +1,199 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"otel/internal/domain"
+	"otel/internal/repository"
+	"otel/pkg/telemetry"
+	"otel/pkg/validator"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Address is the address of a CEP as resolved by ViaCEP
+type Address struct {
+	CEP          string `json:"cep" example:"01310-100"`
+	Street       string `json:"street" example:"Avenida Paulista"`
+	Neighborhood string `json:"neighborhood" example:"Bela Vista"`
+	City         string `json:"city" example:"São Paulo"`
+	UF           string `json:"uf" example:"SP"`
+	Region       string `json:"region" example:"Sudeste"`
+}
+
+// FullCEPResponse combines the address and the orchestrator's weather payload
+type FullCEPResponse struct {
+	Address Address                `json:"address"`
+	Weather domain.WeatherResponse `json:"weather"`
+}
+
+// WithAddressLookup enables POST /cep/full, resolving addresses with lookup
+func (h *GatewayHandler) WithAddressLookup(lookup domain.LocationService) *GatewayHandler {
+	h.addresses = lookup
+	return h
+}
+
+// ProcessCEPFull returns the address and the temperatures of a CEP in one payload
+// @Summary Process CEP with address
+// @Description Validates the CEP, then resolves its address and fetches its temperatures in parallel
+// @Tags gateway
+// @Accept json
+// @Produce json
+// @Param cep body CEPRequest true "CEP input (callback_url is not supported)"
+// @Success 200 {object} FullCEPResponse "Address and temperatures"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "Zipcode not found"
+// @Failure 422 {object} ErrorResponse "Invalid zipcode"
+// @Failure 502 {object} ErrorResponse "Address or weather lookup failed"
+// @Failure 503 {object} ErrorResponse "No orchestrator available"
+// @Router /cep/full [post]
+func (h *GatewayHandler) ProcessCEPFull(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	ctx, span := h.tracer.Start(r.Context(), "gateway.process_cep_full")
+	defer span.End()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if h.addresses == nil {
+		span.SetStatus(codes.Error, "Address lookup disabled")
+		h.writeError(ctx, w, http.StatusNotFound, "address lookup is not enabled")
+		return
+	}
+
+	req, err := DecodeCEPRequest(r.Body)
+	if isBodyTooLarge(err) {
+		span.SetStatus(codes.Error, "Request body too large")
+		h.writeError(ctx, w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse request body")
+		span.RecordError(err)
+		h.writeError(ctx, w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.CallbackURL != "" {
+		span.SetStatus(codes.Error, "Callback mode not supported")
+		h.writeError(ctx, w, http.StatusBadRequest, "callback_url is not supported on /cep/full")
+		return
+	}
+	span.SetAttributes(attribute.String("cep.input", req.CEP))
+
+	cepInfo, err := validator.ValidateCEPWithInfo(req.CEP)
+	if err != nil {
+		log.Printf("[GATEWAY] Invalid CEP %s on /cep/full: %v", req.CEP, err)
+		span.SetStatus(codes.Error, err.Error())
+		h.writeError(ctx, w, http.StatusUnprocessableEntity, "invalid zipcode")
+		return
+	}
+	span.SetAttributes(
+		attribute.String("cep.uf", cepInfo.UF),
+		attribute.String("cep.region", cepInfo.Region),
+	)
+
+	if bagCtx, err := telemetry.WithRequestBaggage(ctx, cepInfo.CEP, r.Header.Get("X-Client-ID")); err != nil {
+		log.Printf("[GATEWAY] Failed to set request baggage: %v", err)
+	} else {
+		ctx = bagCtx
+	}
+
+	// Both lookups run under the request span, so their spans are siblings in the trace
+	var (
+		wg          sync.WaitGroup
+		weatherResp *OrchestrationResponse
+		weatherErr  error
+		address     *domain.ViaCEPResponse
+		addressErr  error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		weatherResp, weatherErr = h.forwardDeduplicated(ctx, req.CEP)
+	}()
+	go func() {
+		defer wg.Done()
+		address, addressErr = h.lookupAddress(ctx, cepInfo.CEP)
+	}()
+	wg.Wait()
+
+	// The orchestrator's answer decides the status; the address only adds to it
+	switch {
+	case errors.Is(weatherErr, ErrOrchestrationUnavailable):
+		span.SetStatus(codes.Error, "Orchestration service unavailable")
+		w.Header().Set("Retry-After", "1")
+		h.writeError(ctx, w, http.StatusServiceUnavailable, weatherErr.Error())
+		return
+	case weatherErr != nil:
+		log.Printf("[GATEWAY] Failed to fetch weather for CEP %s: %v", req.CEP, weatherErr)
+		span.SetStatus(codes.Error, "Failed to fetch weather")
+		span.RecordError(weatherErr)
+		h.writeError(ctx, w, http.StatusInternalServerError, "failed to process request")
+		return
+	case weatherResp.StatusCode != http.StatusOK:
+		span.SetAttributes(attribute.Int("orchestration.status_code", weatherResp.StatusCode))
+		span.SetStatus(codes.Error, fmt.Sprintf("Orchestration service returned status %d", weatherResp.StatusCode))
+		w.WriteHeader(weatherResp.StatusCode)
+		w.Write(weatherResp.Body)
+		return
+	case errors.Is(addressErr, repository.ErrCEPNotFound):
+		span.SetStatus(codes.Error, "Address not found")
+		h.writeError(ctx, w, http.StatusNotFound, "can not find zipcode")
+		return
+	case addressErr != nil:
+		span.SetStatus(codes.Error, "Address lookup failed")
+		h.writeError(ctx, w, http.StatusBadGateway, "failed to resolve address")
+		return
+	}
+
+	var weather domain.WeatherResponse
+	if err := json.Unmarshal(weatherResp.Body, &weather); err != nil {
+		span.SetStatus(codes.Error, "Invalid orchestration response")
+		span.RecordError(err)
+		h.writeError(ctx, w, http.StatusBadGateway, "invalid response from orchestration service")
+		return
+	}
+
+	duration := time.Since(startTime)
+	log.Printf("[GATEWAY] Successfully processed full CEP %s in %v", req.CEP, duration)
+	span.SetAttributes(attribute.Int64("request.duration_ms", duration.Milliseconds()))
+	span.SetStatus(codes.Ok, "Request processed successfully")
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(FullCEPResponse{
+		Address: Address{
+			CEP:          address.CEP,
+			Street:       address.Logradouro,
+			Neighborhood: address.Bairro,
+			City:         address.Localidade,
+			UF:           address.UF,
+			Region:       cepInfo.Region,
+		},
+		Weather: weather,
+	})
+}
+
+// lookupAddress resolves the address of cep in its own span
+func (h *GatewayHandler) lookupAddress(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	ctx, span := h.tracer.Start(ctx, "gateway.lookup_address")
+	defer span.End()
+
+	start := time.Now()
+	address, err := h.addresses.GetLocationByCEP(ctx, cep)
+	span.SetAttributes(attribute.Int64("lookup.duration_ms", time.Since(start).Milliseconds()))
+	if err != nil {
+		log.Printf("[GATEWAY] Address lookup failed for CEP %s: %v", cep, err)
+		span.SetStatus(codes.Error, "Address lookup failed")
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("address.city", address.Localidade))
+	return address, nil
+}
@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"otel/internal/domain"
+	"otel/internal/repository"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// stubAddressLookup answers with a fixed address or error after delay
+type stubAddressLookup struct {
+	address *domain.ViaCEPResponse
+	err     error
+	delay   time.Duration
+}
+
+func (s *stubAddressLookup) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	time.Sleep(s.delay)
+	return s.address, s.err
+}
+
+func newWeatherOrchestrator(status int, body string, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func postFull(h *GatewayHandler, cep string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(CEPRequest{CEP: cep})
+	req := httptest.NewRequest(http.MethodPost, "/cep/full", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	h.ProcessCEPFull(rr, req)
+	return rr
+}
+
+func TestProcessCEPFull_MergesAddressAndWeatherInParallel(t *testing.T) {
+	orchestrator := newWeatherOrchestrator(http.StatusOK, `{"city":"São Paulo","temp_C":25,"temp_F":77,"temp_K":298}`, 150*time.Millisecond)
+	defer orchestrator.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	h := NewGatewayHandler(orchestrator.URL).WithAddressLookup(&stubAddressLookup{
+		address: &domain.ViaCEPResponse{CEP: "01310-100", Logradouro: "Avenida Paulista", Bairro: "Bela Vista", Localidade: "São Paulo", UF: "SP"},
+		delay:   150 * time.Millisecond,
+	})
+	h.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	start := time.Now()
+	rr := postFull(h, "01310100")
+	elapsed := time.Since(start)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if elapsed >= 300*time.Millisecond {
+		t.Errorf("Expected both lookups to run in parallel, took %v", elapsed)
+	}
+
+	var response FullCEPResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Address.Street != "Avenida Paulista" || response.Address.Region != "Sudeste" {
+		t.Errorf("Unexpected address: %+v", response.Address)
+	}
+	if response.Weather.TempC != 25 || response.Weather.City != "São Paulo" {
+		t.Errorf("Unexpected weather: %+v", response.Weather)
+	}
+
+	// Both lookups hang off the request span
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	root := spans["gateway.process_cep_full"]
+	if root == nil {
+		t.Fatal("Expected the request span")
+	}
+	for _, name := range []string{"gateway.lookup_address", "gateway.call_orchestration_service"} {
+		child := spans[name]
+		if child == nil || child.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("Expected %s to be a child of the request span", name)
+		}
+	}
+}
+
+func TestProcessCEPFull_ForwardsOrchestratorErrors(t *testing.T) {
+	orchestrator := newWeatherOrchestrator(http.StatusNotFound, `{"message":"can not find zipcode"}`, 0)
+	defer orchestrator.Close()
+
+	h := NewGatewayHandler(orchestrator.URL).WithAddressLookup(&stubAddressLookup{err: repository.ErrCEPNotFound})
+
+	rr := postFull(h, "01310100")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rr.Code)
+	}
+}
+
+func TestProcessCEPFull_AddressLookupFailure(t *testing.T) {
+	orchestrator := newWeatherOrchestrator(http.StatusOK, `{"city":"São Paulo","temp_C":25,"temp_F":77,"temp_K":298}`, 0)
+	defer orchestrator.Close()
+
+	h := NewGatewayHandler(orchestrator.URL).WithAddressLookup(&stubAddressLookup{err: errors.New("viacep down")})
+
+	rr := postFull(h, "01310100")
+	if rr.Code != http.StatusBadGateway {
+		t.Errorf("Expected 502, got %d", rr.Code)
+	}
+}
+
+func TestProcessCEPFull_InvalidCEP(t *testing.T) {
+	h := NewGatewayHandler("http://localhost:0").WithAddressLookup(&stubAddressLookup{})
+
+	rr := postFull(h, "123")
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d", rr.Code)
+	}
+}
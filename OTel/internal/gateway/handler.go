@@ -3,31 +3,43 @@ package gateway
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
+	"otel/internal/domain"
+	"otel/pkg/featureflag"
+	"otel/pkg/httpclient"
 	"otel/pkg/telemetry"
 	"otel/pkg/validator"
 
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 )
 
 // CEPRequest represents the input request structure
 type CEPRequest struct {
 	CEP string `json:"cep"`
+	// CallbackURL switches to callback mode: the gateway answers 202 and POSTs the result there
+	CallbackURL string `json:"callback_url,omitempty"`
 }
 
 // ErrorResponse represents the error response structure
 type ErrorResponse struct {
 	Message string `json:"message"`
+	TraceID string `json:"trace_id,omitempty"`
 }
 
+// ErrOrchestrationUnavailable is returned when every eligible orchestrator has an open breaker
+var ErrOrchestrationUnavailable = errors.New("orchestration service unavailable")
+
 // OrchestrationResponse represents a response from the orchestration service
 type OrchestrationResponse struct {
 	Body       []byte
@@ -37,8 +49,21 @@ type OrchestrationResponse struct {
 // GatewayHandler handles HTTP requests for the gateway service
 type GatewayHandler struct {
 	orchestrationServiceURL string
+	router                  *RegionRouter
+	callbacks               *CallbackDispatcher
 	tracer                  trace.Tracer
-	httpClient              *http.Client
+	traceIDInErrors         bool
+	streamIdleTimeout       time.Duration
+	flags                   *featureflag.Flags
+	addresses               domain.LocationService
+	retry                   RetryPolicy
+
+	// inflight shares orchestrator calls among concurrent requests for the same CEP
+	inflight singleflight.Group
+
+	// httpClient is swapped by SetTimeout; guarded by mu
+	mu         sync.RWMutex
+	httpClient *http.Client
 }
 
 // NewGatewayHandler creates a new gateway handler
@@ -46,29 +71,89 @@ func NewGatewayHandler(orchestrationServiceURL string) *GatewayHandler {
 	log.Printf("[GATEWAY] Initializing gateway handler with orchestration URL: %s", orchestrationServiceURL)
 
 	// Create HTTP client with OpenTelemetry instrumentation
-	httpClient := &http.Client{
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
-		Timeout:   30 * time.Second,
-	}
+	httpClient := httpclient.New(httpclient.WithTimeout(30 * time.Second))
 
 	return &GatewayHandler{
 		orchestrationServiceURL: orchestrationServiceURL,
 		tracer:                  telemetry.GetTracer("otel-gateway"),
 		httpClient:              httpClient,
+		streamIdleTimeout:       DefaultStreamIdleTimeout,
 	}
 }
 
+// WithTraceIDInErrors enables returning the trace ID in error response bodies
+func (h *GatewayHandler) WithTraceIDInErrors(enabled bool) *GatewayHandler {
+	h.traceIDInErrors = enabled
+	return h
+}
+
+// WithRoutes routes CEPs to regional orchestrators by prefix, with the
+// orchestration URL as default upstream. Each upstream gets a circuit breaker
+// opening after threshold consecutive failures for cooldown.
+func (h *GatewayHandler) WithRoutes(routes []Route, threshold int, cooldown time.Duration) *GatewayHandler {
+	h.router = NewRegionRouter(h.orchestrationServiceURL, routes, threshold, cooldown)
+	return h
+}
+
+// WithCallbacks enables callback mode, delivering results through dispatcher
+func (h *GatewayHandler) WithCallbacks(dispatcher *CallbackDispatcher) *GatewayHandler {
+	h.callbacks = dispatcher
+	return h
+}
+
+// WithFlags lets callback mode be switched off at runtime
+func (h *GatewayHandler) WithFlags(flags *featureflag.Flags) *GatewayHandler {
+	h.flags = flags
+	return h
+}
+
+// WithTLSConfig sets the TLS configuration used to call the orchestration service,
+// e.g. a private CA and the client certificate required for mutual TLS
+func (h *GatewayHandler) WithTLSConfig(tlsConfig *tls.Config) *GatewayHandler {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.httpClient = httpclient.New(
+		httpclient.WithTimeout(h.httpClient.Timeout),
+		httpclient.WithTLSConfig(tlsConfig),
+	)
+	return h
+}
+
+// SetTimeout replaces the timeout used for calls to the orchestration service.
+// Calls already in flight keep the previous timeout.
+func (h *GatewayHandler) SetTimeout(timeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client := *h.httpClient
+	client.Timeout = timeout
+	h.httpClient = &client
+	log.Printf("[GATEWAY] Orchestration timeout set to %v", timeout)
+}
+
+func (h *GatewayHandler) client() *http.Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.httpClient
+}
+
 // ProcessCEP handles the CEP input validation and forwarding
 // @Summary Process CEP input
 // @Description Validates CEP input and forwards to orchestration service
+// @Description With callback_url, answers 202 immediately and POSTs a signed CallbackPayload to the URL when done
 // @Tags gateway
 // @Accept json
 // @Produce json
 // @Param cep body CEPRequest true "CEP input"
 // @Success 200 {object} map[string]interface{} "Success response from orchestration service"
+// @Success 202 {object} CallbackAccepted "Accepted in callback mode"
 // @Failure 422 {object} ErrorResponse "Invalid zipcode"
 // @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content type is not application/json"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Orchestration service unavailable"
 // @Router /cep [post]
 func (h *GatewayHandler) ProcessCEP(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
@@ -93,13 +178,19 @@ func (h *GatewayHandler) ProcessCEP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	// Parse request body
-	var req CEPRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req, err := DecodeCEPRequest(r.Body)
+	if isBodyTooLarge(err) {
+		log.Printf("[GATEWAY] Request body from %s exceeds limit: %v", clientIP, err)
+		span.SetStatus(codes.Error, "Request body too large")
+		span.RecordError(err)
+		h.writeError(ctx, w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	if err != nil {
 		log.Printf("[GATEWAY] Failed to parse request body from %s: %v", clientIP, err)
 		span.SetStatus(codes.Error, "Failed to parse request body")
 		span.RecordError(err)
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Message: "invalid request body"})
+		h.writeError(ctx, w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
@@ -110,35 +201,62 @@ func (h *GatewayHandler) ProcessCEP(w http.ResponseWriter, r *http.Request) {
 	_, validationSpan := h.tracer.Start(ctx, "gateway.validate_cep")
 	validationStart := time.Now()
 
-	// Validate CEP
-	if !validator.ValidateCEP(req.CEP) {
-		validationSpan.SetStatus(codes.Error, "Invalid CEP format")
+	// Validate CEP format and reject prefixes not assigned to any state
+	cepInfo, err := validator.ValidateCEPWithInfo(req.CEP)
+	if err != nil {
+		validationSpan.SetStatus(codes.Error, err.Error())
 		validationSpan.End()
-		log.Printf("[GATEWAY] Invalid CEP format: %s from %s", req.CEP, clientIP)
-		span.SetStatus(codes.Error, "Invalid CEP format")
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		json.NewEncoder(w).Encode(ErrorResponse{Message: "invalid zipcode"})
+		log.Printf("[GATEWAY] Invalid CEP %s from %s: %v", req.CEP, clientIP, err)
+		span.SetStatus(codes.Error, err.Error())
+		h.writeError(ctx, w, http.StatusUnprocessableEntity, "invalid zipcode")
 		return
 	}
 
 	validationDuration := time.Since(validationStart)
+	regionAttrs := []attribute.KeyValue{
+		attribute.String("cep.uf", cepInfo.UF),
+		attribute.String("cep.region", cepInfo.Region),
+	}
+	validationSpan.SetAttributes(regionAttrs...)
 	validationSpan.SetAttributes(
 		attribute.String("cep.validated", req.CEP),
 		attribute.Int64("validation.duration_ms", validationDuration.Milliseconds()),
 	)
+	span.SetAttributes(regionAttrs...)
 	validationSpan.SetStatus(codes.Ok, "CEP validation successful")
 	validationSpan.End()
 
 	log.Printf("[GATEWAY] CEP validation successful: %s", req.CEP)
 
+	// Propagate the validated CEP and client ID to downstream services via baggage
+	clientID := r.Header.Get("X-Client-ID")
+	if bagCtx, err := telemetry.WithRequestBaggage(ctx, validator.CleanCEP(req.CEP), clientID); err != nil {
+		log.Printf("[GATEWAY] Failed to set request baggage: %v", err)
+	} else {
+		ctx = bagCtx
+	}
+
+	// Callback mode: answer now and deliver the result asynchronously
+	if req.CallbackURL != "" {
+		h.acceptCallback(ctx, w, req)
+		return
+	}
+
 	// Forward to orchestration service
-	orchestrationResp, err := h.forwardToOrchestrationService(ctx, req.CEP)
+	orchestrationResp, err := h.forwardDeduplicated(ctx, req.CEP)
+	if errors.Is(err, ErrOrchestrationUnavailable) {
+		log.Printf("[GATEWAY] No orchestrator available for CEP %s", req.CEP)
+		span.SetStatus(codes.Error, "Orchestration service unavailable")
+		span.RecordError(err)
+		w.Header().Set("Retry-After", "1")
+		h.writeError(ctx, w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
 	if err != nil {
 		log.Printf("[GATEWAY] Failed to forward request to orchestration service: %v", err)
 		span.SetStatus(codes.Error, "Failed to forward request to orchestration service")
 		span.RecordError(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(ErrorResponse{Message: "failed to process request"})
+		h.writeError(ctx, w, http.StatusInternalServerError, "failed to process request")
 		return
 	}
 
@@ -168,10 +286,155 @@ func (h *GatewayHandler) ProcessCEP(w http.ResponseWriter, r *http.Request) {
 	w.Write(orchestrationResp.Body)
 }
 
-// forwardToOrchestrationService forwards the CEP to the orchestration service
-func (h *GatewayHandler) forwardToOrchestrationService(ctx context.Context, cep string) (*OrchestrationResponse, error) {
+// acceptCallback validates the callback URL and schedules the CEP for background processing
+func (h *GatewayHandler) acceptCallback(ctx context.Context, w http.ResponseWriter, req CEPRequest) {
+	span := trace.SpanFromContext(ctx)
+
+	if h.callbacks == nil || !h.flags.Enabled(featureflag.Callbacks) {
+		span.SetStatus(codes.Error, "Callback mode disabled")
+		h.writeError(ctx, w, http.StatusBadRequest, "callback mode is not enabled")
+		return
+	}
+	if err := h.callbacks.Validate(req.CallbackURL); err != nil {
+		log.Printf("[GATEWAY] Rejecting callback_url %q: %v", req.CallbackURL, err)
+		span.SetStatus(codes.Error, "Invalid callback URL")
+		h.writeError(ctx, w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requestID := newRequestID()
+	span.SetAttributes(attribute.String("callback.request_id", requestID))
+
+	// Keep the trace and baggage but not the cancellation of the client request
+	bgCtx := context.WithoutCancel(ctx)
+	if !h.callbacks.Go(func() { h.processCallback(bgCtx, req, requestID) }) {
+		log.Printf("[GATEWAY] Too many pending callbacks, rejecting CEP %s", req.CEP)
+		span.SetStatus(codes.Error, "Too many pending callbacks")
+		w.Header().Set("Retry-After", "1")
+		h.writeError(ctx, w, http.StatusServiceUnavailable, "too many pending callbacks, try again later")
+		return
+	}
+
+	log.Printf("[GATEWAY] Accepted CEP %s in callback mode, request %s", req.CEP, requestID)
+	span.SetStatus(codes.Ok, "Accepted for callback")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(CallbackAccepted{RequestID: requestID, Status: "accepted"})
+}
+
+// processCallback forwards the CEP and delivers the outcome to the callback URL
+func (h *GatewayHandler) processCallback(ctx context.Context, req CEPRequest, requestID string) {
+	ctx, span := h.tracer.Start(ctx, "gateway.process_callback")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("callback.request_id", requestID),
+		attribute.String("callback.url", req.CallbackURL),
+	)
+
+	payload := CallbackPayload{RequestID: requestID, CEP: req.CEP}
+	resp, err := h.forwardDeduplicated(ctx, req.CEP)
+	switch {
+	case errors.Is(err, ErrOrchestrationUnavailable):
+		payload.StatusCode = http.StatusServiceUnavailable
+		payload.Error = &ErrorResponse{Message: err.Error()}
+	case err != nil:
+		log.Printf("[GATEWAY] Failed to forward callback request %s: %v", requestID, err)
+		payload.StatusCode = http.StatusInternalServerError
+		payload.Error = &ErrorResponse{Message: "failed to process request"}
+	case resp.StatusCode == http.StatusOK && json.Valid(resp.Body):
+		payload.StatusCode = http.StatusOK
+		payload.Weather = resp.Body
+	default:
+		payload.StatusCode = resp.StatusCode
+		var errorResponse ErrorResponse
+		if json.Unmarshal(resp.Body, &errorResponse) != nil || errorResponse.Message == "" {
+			errorResponse.Message = http.StatusText(resp.StatusCode)
+		}
+		payload.Error = &errorResponse
+	}
+	if payload.Error != nil && h.traceIDInErrors {
+		payload.Error.TraceID = telemetry.TraceID(ctx)
+	}
+	span.SetAttributes(attribute.Int("callback.status_code", payload.StatusCode))
+
+	if err := h.callbacks.Deliver(ctx, req.CallbackURL, payload); err != nil {
+		log.Printf("[GATEWAY] Callback %s to %s failed: %v", requestID, req.CallbackURL, err)
+		span.SetStatus(codes.Error, "Callback delivery failed")
+		span.RecordError(err)
+		return
+	}
+	span.SetStatus(codes.Ok, "Callback delivered")
+}
+
+// writeError sends an error response, including the trace ID when enabled
+func (h *GatewayHandler) writeError(ctx context.Context, w http.ResponseWriter, statusCode int, message string) {
+	errorResponse := ErrorResponse{Message: message}
+	if h.traceIDInErrors {
+		errorResponse.TraceID = telemetry.TraceID(ctx)
+	}
+
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(errorResponse)
+}
+
+// forward sends the CEP to its regional orchestrator when routing is enabled.
+// A region with an open breaker or that fails to answer is replaced by the default upstream.
+func (h *GatewayHandler) forward(ctx context.Context, cep string) (*OrchestrationResponse, error) {
+	if h.router == nil {
+		return h.forwardToOrchestrationService(ctx, h.orchestrationServiceURL, DefaultRegion, cep)
+	}
+
+	target := h.router.resolve(cep)
+	if target != h.router.fallback {
+		if target.breaker.Allow() {
+			resp, err := h.callUpstream(ctx, target, cep)
+			if !upstreamFailed(resp, err) {
+				return resp, nil
+			}
+		} else {
+			h.router.record(ctx, target.region, routeOutcomeOpen)
+		}
+		log.Printf("[GATEWAY] Region %s unavailable, falling back to %s", target.region, DefaultRegion)
+		h.router.record(ctx, target.region, routeOutcomeFallback)
+	}
+
+	if !h.router.fallback.breaker.Allow() {
+		h.router.record(ctx, DefaultRegion, routeOutcomeOpen)
+		return nil, ErrOrchestrationUnavailable
+	}
+	return h.callUpstream(ctx, h.router.fallback, cep)
+}
+
+// callUpstream forwards to u and feeds the outcome to its breaker and metrics
+func (h *GatewayHandler) callUpstream(ctx context.Context, u *upstream, cep string) (*OrchestrationResponse, error) {
+	resp, err := h.forwardToOrchestrationService(ctx, u.url, u.region, cep)
+	if upstreamFailed(resp, err) {
+		u.breaker.Failure()
+		h.router.record(ctx, u.region, routeOutcomeFailure)
+	} else {
+		u.breaker.Success()
+		h.router.record(ctx, u.region, routeOutcomeSuccess)
+	}
+	return resp, err
+}
+
+// upstreamFailed reports whether the orchestrator itself was unreachable or
+// unhealthy; application errors such as 404 and 500 mean it is up
+func upstreamFailed(resp *OrchestrationResponse, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// forwardToOrchestrationService forwards the CEP to the orchestration service at baseURL,
+// retrying connection errors and 502/503 answers per the retry policy
+func (h *GatewayHandler) forwardToOrchestrationService(ctx context.Context, baseURL, region, cep string) (*OrchestrationResponse, error) {
 	// Start span for orchestration service call
-	_, span := h.tracer.Start(ctx, "gateway.call_orchestration_service")
+	ctx, span := h.tracer.Start(ctx, "gateway.call_orchestration_service")
 	defer span.End()
 
 	// Format CEP for the orchestration service (add hyphen if needed)
@@ -179,14 +442,76 @@ func (h *GatewayHandler) forwardToOrchestrationService(ctx context.Context, cep
 	log.Printf("[GATEWAY] Formatted CEP: %s -> %s", cep, formattedCEP)
 
 	// Create the URL for the orchestration service
-	url := fmt.Sprintf("%s/weather/%s", h.orchestrationServiceURL, formattedCEP)
+	url := fmt.Sprintf("%s/weather/%s", baseURL, formattedCEP)
 	log.Printf("[GATEWAY] Calling orchestration service at: %s", url)
 
 	span.SetAttributes(
 		attribute.String("orchestration.url", url),
+		attribute.String("orchestration.region", region),
 		attribute.String("cep.formatted", formattedCEP),
 	)
 
+	// Retries share the deadline a single call would have had
+	client := h.client()
+	if _, ok := ctx.Deadline(); !ok && client.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.Timeout)
+		defer cancel()
+	}
+
+	var (
+		resp *OrchestrationResponse
+		err  error
+	)
+	attempt := 0
+	for ; ; attempt++ {
+		resp, err = h.attemptOrchestration(ctx, client, url, attempt)
+		if !retryableResponse(ctx, resp, err) || attempt >= h.retry.MaxRetries {
+			break
+		}
+		delay := h.retry.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			log.Printf("[GATEWAY] Not retrying %s: request deadline would pass during backoff", url)
+			break
+		}
+		log.Printf("[GATEWAY] Retrying orchestration call in %v (attempt %d of %d)", delay, attempt+2, h.retry.MaxRetries+1)
+		if !sleepContext(ctx, delay) {
+			break
+		}
+	}
+	span.SetAttributes(attribute.Int("orchestration.attempts", attempt+1))
+
+	if err != nil {
+		log.Printf("[GATEWAY] HTTP request to orchestration service failed: %v", err)
+		span.SetStatus(codes.Error, "HTTP request failed")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// If orchestration service returns an error, forward it
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("[GATEWAY] Orchestration service returned error status %d: %s", resp.StatusCode, resp.Body)
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		span.SetStatus(codes.Error, fmt.Sprintf("Orchestration service error: %d", resp.StatusCode))
+		return resp, nil
+	}
+
+	span.SetAttributes(
+		attribute.Int("http.status_code", resp.StatusCode),
+		attribute.Int("response.size_bytes", len(resp.Body)),
+	)
+	span.SetStatus(codes.Ok, "Successfully received response from orchestration service")
+
+	log.Printf("[GATEWAY] Successfully received response from orchestration service: %d bytes", len(resp.Body))
+	return resp, nil
+}
+
+// attemptOrchestration makes one call to url in its own child span
+func (h *GatewayHandler) attemptOrchestration(ctx context.Context, client *http.Client, url string, attempt int) (*OrchestrationResponse, error) {
+	ctx, span := h.tracer.Start(ctx, "gateway.orchestration_attempt")
+	defer span.End()
+	span.SetAttributes(attribute.Int("orchestration.attempt", attempt+1))
+
 	// Create HTTP request with context
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -197,9 +522,8 @@ func (h *GatewayHandler) forwardToOrchestrationService(ctx context.Context, cep
 
 	// Make HTTP request to orchestration service
 	requestStart := time.Now()
-	resp, err := h.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("[GATEWAY] HTTP request to orchestration service failed: %v", err)
 		span.SetStatus(codes.Error, "HTTP request failed")
 		span.RecordError(err)
 		return nil, fmt.Errorf("failed to call orchestration service: %w", err)
@@ -224,20 +548,9 @@ func (h *GatewayHandler) forwardToOrchestrationService(ctx context.Context, cep
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// If orchestration service returns an error, forward it
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[GATEWAY] Orchestration service returned error status %d: %s", resp.StatusCode, buf.String())
 		span.SetStatus(codes.Error, fmt.Sprintf("Orchestration service error: %d", resp.StatusCode))
-		return &OrchestrationResponse{
-			Body:       buf.Bytes(),
-			StatusCode: resp.StatusCode,
-		}, nil
 	}
-
-	span.SetAttributes(attribute.Int("response.size_bytes", buf.Len()))
-	span.SetStatus(codes.Ok, "Successfully received response from orchestration service")
-
-	log.Printf("[GATEWAY] Successfully received response from orchestration service: %d bytes", buf.Len())
 	return &OrchestrationResponse{
 		Body:       buf.Bytes(),
 		StatusCode: resp.StatusCode,
@@ -249,7 +562,7 @@ func (h *GatewayHandler) forwardToOrchestrationService(ctx context.Context, cep
 // @Description Check if the gateway service is healthy
 // @Tags health
 // @Produce json
-// @Success 200 {object} map[string]string "Service is healthy"
+// @Success 200 {object} map[string]interface{} "Service is healthy, with breaker states when regional routing is enabled"
 // @Router /health [get]
 func (h *GatewayHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	clientIP := r.RemoteAddr
@@ -259,12 +572,17 @@ func (h *GatewayHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("[GATEWAY] Health check requested from %s", clientIP)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
+	health := map[string]interface{}{
 		"status":  "healthy",
 		"service": "otel-gateway",
-	})
+	}
+	if h.router != nil {
+		health["breakers"] = h.router.BreakerStates()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(health)
 
 	log.Printf("[GATEWAY] Health check response sent to %s", clientIP)
 }
@@ -2,10 +2,13 @@ package gateway
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestGatewayHandler_ProcessCEP_ValidCEP(t *testing.T) {
@@ -75,6 +78,31 @@ func TestGatewayHandler_ProcessCEP_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestGatewayHandler_ProcessCEP_UnassignedPrefix(t *testing.T) {
+	called := false
+	mockOrchestration := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer mockOrchestration.Close()
+
+	handler := NewGatewayHandler(mockOrchestration.URL)
+
+	// Well-formed, but no state uses the 00xxx prefix
+	body, _ := json.Marshal(CEPRequest{CEP: "00999999"})
+	req := httptest.NewRequest("POST", "/cep", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler.ProcessCEP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnprocessableEntity {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnprocessableEntity)
+	}
+	if called {
+		t.Error("expected the orchestration service not to be called")
+	}
+}
+
 func TestGatewayHandler_HealthCheck(t *testing.T) {
 	handler := NewGatewayHandler("http://localhost:8080")
 
@@ -100,3 +128,28 @@ func TestGatewayHandler_HealthCheck(t *testing.T) {
 		t.Errorf("unexpected service name: got %v want %v", response["service"], "otel-gateway")
 	}
 }
+
+func TestGatewayHandler_ProcessCEP_TraceIDInErrors(t *testing.T) {
+	handler := NewGatewayHandler("http://localhost:8080").WithTraceIDInErrors(true)
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	req := httptest.NewRequest("POST", "/cep", bytes.NewBuffer([]byte(`{"cep": "123"}`))).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	handler.ProcessCEP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnprocessableEntity {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnprocessableEntity)
+	}
+
+	var response ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	if response.TraceID != span.SpanContext().TraceID().String() {
+		t.Errorf("unexpected trace id: got %v want %v", response.TraceID, span.SpanContext().TraceID().String())
+	}
+}
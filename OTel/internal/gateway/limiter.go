@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ConcurrencyLimiter sheds load once the number of in-flight requests reaches its limit.
+// Requests over the limit wait up to queueTimeout for a free slot before being rejected with 503.
+type ConcurrencyLimiter struct {
+	mu           sync.RWMutex
+	slots        chan struct{}
+	queueTimeout time.Duration
+}
+
+// NewConcurrencyLimiter creates a limiter allowing maxInFlight concurrent requests
+func NewConcurrencyLimiter(maxInFlight int, queueTimeout time.Duration) *ConcurrencyLimiter {
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+
+	log.Printf("[GATEWAY] Initializing concurrency limiter - max in-flight: %d, queue timeout: %v", maxInFlight, queueTimeout)
+
+	return &ConcurrencyLimiter{
+		slots:        make(chan struct{}, maxInFlight),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Middleware wraps next with the concurrency limit
+func (l *ConcurrencyLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slots, ok := l.acquire(r)
+		if !ok {
+			log.Printf("[GATEWAY] Load shedding: rejecting %s %s, %d requests in flight", r.Method, r.URL.Path, l.InFlight())
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(ErrorResponse{Message: "service overloaded, try again later"})
+			return
+		}
+		defer func() { <-slots }()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SetLimits replaces the limits at runtime. Requests already in flight keep
+// their slot in the previous pool, so they are not counted against the new limit.
+func (l *ConcurrencyLimiter) SetLimits(maxInFlight int, queueTimeout time.Duration) {
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if cap(l.slots) != maxInFlight {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+	l.queueTimeout = queueTimeout
+
+	log.Printf("[GATEWAY] Concurrency limiter reloaded - max in-flight: %d, queue timeout: %v", maxInFlight, queueTimeout)
+}
+
+// InFlight returns the number of requests currently holding a slot
+func (l *ConcurrencyLimiter) InFlight() int {
+	slots, _ := l.limits()
+	return len(slots)
+}
+
+func (l *ConcurrencyLimiter) limits() (chan struct{}, time.Duration) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.slots, l.queueTimeout
+}
+
+// acquire takes a slot and returns the pool it belongs to, so it is released
+// to the same pool even if the limits change in the meantime
+func (l *ConcurrencyLimiter) acquire(r *http.Request) (chan struct{}, bool) {
+	slots, queueTimeout := l.limits()
+
+	select {
+	case slots <- struct{}{}:
+		return slots, true
+	default:
+	}
+
+	if queueTimeout <= 0 {
+		return nil, false
+	}
+
+	timer := time.NewTimer(queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return slots, true
+	case <-timer.C:
+		return nil, false
+	case <-r.Context().Done():
+		return nil, false
+	}
+}
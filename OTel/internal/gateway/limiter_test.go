@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimiter_AllowsRequestsUnderLimit(t *testing.T) {
+	limiter := NewConcurrencyLimiter(2, 0)
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/cep", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if limiter.InFlight() != 0 {
+		t.Errorf("Expected slot to be released, got %d in flight", limiter.InFlight())
+	}
+}
+
+func TestConcurrencyLimiter_RejectsWhenSaturated(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1, 20*time.Millisecond)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/cep", nil))
+	}()
+	<-started
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/cep", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header on shed request")
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimiter_QueuedRequestGetsSlot(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1, time.Second)
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		if r.URL.Path == "/slow" {
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/slow", nil))
+	<-started
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/cep", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected queued request to succeed, got %d", rr.Code)
+	}
+}
+
+func TestConcurrencyLimiter_SetLimitsAppliesToNewRequests(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/cep", nil))
+	}()
+	<-started
+
+	limiter.SetLimits(2, 0)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/cep", nil))
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected request to be admitted after raising the limit, got %d", rr.Code)
+		}
+	}()
+	<-started
+
+	close(release)
+	wg.Wait()
+
+	if limiter.InFlight() != 0 {
+		t.Errorf("Expected all slots to be released, got %d in flight", limiter.InFlight())
+	}
+}
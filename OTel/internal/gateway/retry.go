@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy bounds the retries of calls to the orchestration service after
+// connection errors and 502/503 answers, e.g. while an orchestrator restarts.
+// Calls are GETs, so retrying them is safe.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt (0 disables retries)
+	MaxRetries int
+	// BaseDelay is the wait before the first retry, doubled on each retry
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries twice, after 100ms and 200ms
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	BaseDelay:  100 * time.Millisecond,
+	MaxDelay:   time.Second,
+}
+
+// WithRetry retries failed calls to the orchestration service per policy.
+// Retries share the deadline of the request, or ORCHESTRATION_TIMEOUT when it has none.
+func (h *GatewayHandler) WithRetry(policy RetryPolicy) *GatewayHandler {
+	h.retry = policy
+	return h
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// retryableResponse reports whether another attempt may succeed: the
+// orchestrator was unreachable or answered 502/503, and the caller is still waiting
+func retryableResponse(ctx context.Context, resp *OrchestrationResponse, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// sleepContext waits for d, returning false if ctx ends first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
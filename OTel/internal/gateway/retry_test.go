@@ -0,0 +1,133 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// flakyOrchestrator answers failStatus to the first failures calls, then 200
+func flakyOrchestrator(failures int32, failStatus int) (*httptest.Server, *atomic.Int32) {
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(failStatus)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"city":"São Paulo","temp_C":25,"temp_F":77,"temp_K":298}`))
+	}))
+	return server, calls
+}
+
+func TestForwardToOrchestrationService_RetriesTransientFailures(t *testing.T) {
+	orchestrator, calls := flakyOrchestrator(2, http.StatusServiceUnavailable)
+	defer orchestrator.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	h := NewGatewayHandler(orchestrator.URL).WithRetry(RetryPolicy{MaxRetries: 2, BaseDelay: 10 * time.Millisecond})
+	h.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	resp, err := h.forwardToOrchestrationService(context.Background(), orchestrator.URL, "", "01310100")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 after retries, got %d", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected 3 calls, got %d", got)
+	}
+
+	var parent sdktrace.ReadOnlySpan
+	attempts := 0
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "gateway.call_orchestration_service":
+			parent = span
+		case "gateway.orchestration_attempt":
+			attempts++
+		}
+	}
+	if parent == nil || attempts != 3 {
+		t.Fatalf("Expected a parent span and 3 attempt spans, got parent=%v attempts=%d", parent != nil, attempts)
+	}
+	for _, span := range recorder.Ended() {
+		if span.Name() == "gateway.orchestration_attempt" && span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Expected attempt spans to be children of %s", parent.Name())
+		}
+	}
+}
+
+func TestForwardToOrchestrationService_DoesNotRetryClientErrors(t *testing.T) {
+	orchestrator, calls := flakyOrchestrator(1, http.StatusNotFound)
+	defer orchestrator.Close()
+
+	h := NewGatewayHandler(orchestrator.URL).WithRetry(RetryPolicy{MaxRetries: 2, BaseDelay: 10 * time.Millisecond})
+
+	resp, err := h.forwardToOrchestrationService(context.Background(), orchestrator.URL, "", "01310100")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound || calls.Load() != 1 {
+		t.Errorf("Expected a single 404, got %d after %d calls", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestForwardToOrchestrationService_RetriesConnectionErrors(t *testing.T) {
+	orchestrator, _ := flakyOrchestrator(0, 0)
+	url := orchestrator.URL
+	orchestrator.Close()
+
+	h := NewGatewayHandler(url).WithRetry(RetryPolicy{MaxRetries: 2, BaseDelay: 10 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := h.forwardToOrchestrationService(context.Background(), url, "", "01310100"); err == nil {
+		t.Fatal("Expected an error from a closed orchestrator, got nil")
+	}
+	// Two backoffs of 10ms and 20ms
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Expected the connection error to be retried, returned after %v", elapsed)
+	}
+}
+
+func TestForwardToOrchestrationService_StopsAtDeadline(t *testing.T) {
+	orchestrator, calls := flakyOrchestrator(10, http.StatusBadGateway)
+	defer orchestrator.Close()
+
+	h := NewGatewayHandler(orchestrator.URL).WithRetry(RetryPolicy{MaxRetries: 5, BaseDelay: 200 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := h.forwardToOrchestrationService(ctx, orchestrator.URL, "", "01310100")
+	if err != nil {
+		t.Fatalf("Expected the last 502 to be returned, got %v", err)
+	}
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected 502, got %d", resp.StatusCode)
+	}
+	// 200ms fits, the next 400ms backoff does not
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected 2 calls within the deadline, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("Expected retries to end before the deadline, took %v", elapsed)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		if got := p.backoff(attempt); got != want {
+			t.Errorf("backoff(%d): expected %v, got %v", attempt, want, got)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes("sudeste=01, 02@http://orch-se:8081/; sul=8,9@http://orch-sul:8081")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].Region != "sudeste" || routes[0].URL != "http://orch-se:8081" {
+		t.Errorf("Unexpected first route: %+v", routes[0])
+	}
+	if len(routes[0].Prefixes) != 2 || routes[0].Prefixes[1] != "02" {
+		t.Errorf("Unexpected prefixes: %v", routes[0].Prefixes)
+	}
+
+	invalid := []string{
+		"sudeste@http://orch-se:8081",
+		"sudeste=01",
+		"sudeste=0a@http://orch-se:8081",
+		"sudeste=@http://orch-se:8081",
+		"default=01@http://orch:8081",
+	}
+	for _, spec := range invalid {
+		if _, err := ParseRoutes(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestRegionRouter_ResolveLongestPrefix(t *testing.T) {
+	router := NewRegionRouter("http://default", []Route{
+		{Region: "sp", Prefixes: []string{"0"}, URL: "http://sp"},
+		{Region: "sp-centro", Prefixes: []string{"010"}, URL: "http://sp-centro"},
+	}, 5, time.Minute)
+
+	tests := map[string]string{
+		"01001-000": "sp-centro",
+		"02000000":  "sp",
+		"29902555":  DefaultRegion,
+	}
+	for cep, region := range tests {
+		if got := router.resolve(cep).region; got != region {
+			t.Errorf("resolve(%s) = %s, want %s", cep, got, region)
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker(2, 20*time.Millisecond)
+
+	breaker.Failure()
+	if !breaker.Allow() {
+		t.Fatal("Expected breaker to stay closed below the threshold")
+	}
+	breaker.Failure()
+	if breaker.Allow() || breaker.State() != BreakerOpen {
+		t.Fatalf("Expected breaker to open at the threshold, state %s", breaker.State())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !breaker.Allow() {
+		t.Fatal("Expected a trial request after the cooldown")
+	}
+	if breaker.Allow() {
+		t.Fatal("Expected a single trial request while half-open")
+	}
+	breaker.Success()
+	if breaker.State() != BreakerClosed {
+		t.Errorf("Expected breaker to close after a successful trial, state %s", breaker.State())
+	}
+}
+
+func TestGatewayHandler_RegionalRouting_FallsBackToDefault(t *testing.T) {
+	var regionalCalls, defaultCalls int32
+	regional := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&regionalCalls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer regional.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&defaultCalls, 1)
+		w.Write([]byte(`{"city":"Vitória"}`))
+	}))
+	defer fallback.Close()
+
+	handler := NewGatewayHandler(fallback.URL).WithRoutes([]Route{
+		{Region: "es", Prefixes: []string{"29"}, URL: regional.URL},
+	}, 1, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		body, _ := json.Marshal(CEPRequest{CEP: "29902555"})
+		rr := httptest.NewRecorder()
+		handler.ProcessCEP(rr, httptest.NewRequest("POST", "/cep", bytes.NewBuffer(body)))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected 200 from the default upstream, got %d", i, rr.Code)
+		}
+	}
+
+	// The first failure opens the regional breaker, so the second request skips the region
+	if regionalCalls != 1 || defaultCalls != 2 {
+		t.Errorf("Expected 1 regional and 2 default calls, got %d and %d", regionalCalls, defaultCalls)
+	}
+	if state := handler.router.BreakerStates()["es"]; state != BreakerOpen {
+		t.Errorf("Expected es breaker to be open, got %s", state)
+	}
+}
+
+func TestGatewayHandler_RegionalRouting_AllUpstreamsOpen(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+
+	handler := NewGatewayHandler(down.URL).WithRoutes(nil, 1, time.Minute)
+
+	body, _ := json.Marshal(CEPRequest{CEP: "29902555"})
+	rr := httptest.NewRecorder()
+	handler.ProcessCEP(rr, httptest.NewRequest("POST", "/cep", bytes.NewBuffer(body)))
+	if rr.Code != http.StatusBadGateway {
+		t.Fatalf("Expected upstream 502 to be forwarded, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ProcessCEP(rr, httptest.NewRequest("POST", "/cep", bytes.NewBuffer(body)))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 with the default breaker open, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,244 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"otel/pkg/validator"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// DefaultStreamIdleTimeout closes streams without traffic for this long; the
+// orchestrator sends an SSE heartbeat every 15s, so healthy streams never hit it
+const DefaultStreamIdleTimeout = 60 * time.Second
+
+// Who ended a proxied stream, recorded in the stream.closed_by span attribute
+const (
+	streamClosedByUpstream = "upstream"
+	streamClosedByClient   = "client"
+	streamClosedByIdle     = "idle_timeout"
+)
+
+// WithStreamIdleTimeout sets how long a proxied stream may go without traffic before it is closed
+func (h *GatewayHandler) WithStreamIdleTimeout(timeout time.Duration) *GatewayHandler {
+	h.streamIdleTimeout = timeout
+	return h
+}
+
+// StreamCEP proxies the orchestrator's weather stream for a CEP
+// @Summary Stream weather updates
+// @Description Proxies the orchestrator's Server-Sent Events stream (or a WebSocket upgrade) for the CEP, flushing every event as it arrives
+// @Tags gateway
+// @Produce text/event-stream
+// @Param cep path string true "CEP (8 digits)"
+// @Success 200 {string} string "Event stream from the orchestration service"
+// @Failure 422 {object} ErrorResponse "Invalid zipcode"
+// @Failure 502 {object} ErrorResponse "Orchestration service unreachable"
+// @Failure 503 {object} ErrorResponse "No orchestrator available"
+// @Failure 504 {object} ErrorResponse "Orchestration service did not answer before the idle timeout"
+// @Router /cep/{cep}/stream [get]
+func (h *GatewayHandler) StreamCEP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "gateway.stream_cep")
+	defer span.End()
+
+	cep := mux.Vars(r)["cep"]
+	protocol := "sse"
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		protocol = "websocket"
+	}
+	span.SetAttributes(
+		attribute.String("cep.input", cep),
+		attribute.String("stream.protocol", protocol),
+	)
+
+	cepInfo, err := validator.ValidateCEPWithInfo(cep)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		w.Header().Set("Content-Type", "application/json")
+		h.writeError(ctx, w, http.StatusUnprocessableEntity, "invalid zipcode")
+		return
+	}
+	span.SetAttributes(
+		attribute.String("cep.uf", cepInfo.UF),
+		attribute.String("cep.region", cepInfo.Region),
+	)
+
+	target, err := h.streamUpstream(cep)
+	if err != nil {
+		log.Printf("[GATEWAY] No orchestrator available to stream CEP %s", cep)
+		span.SetStatus(codes.Error, "Orchestration service unavailable")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "1")
+		h.writeError(ctx, w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	streamURL, err := url.Parse(fmt.Sprintf("%s/weather/%s/stream", target.url, validator.FormatCEP(cep)))
+	if err != nil {
+		span.SetStatus(codes.Error, "Invalid orchestration URL")
+		span.RecordError(err)
+		w.Header().Set("Content-Type", "application/json")
+		h.writeError(ctx, w, http.StatusInternalServerError, "failed to process request")
+		return
+	}
+	span.SetAttributes(
+		attribute.String("orchestration.url", streamURL.String()),
+		attribute.String("orchestration.region", target.region),
+	)
+
+	// The idle timer cancels the stream, and is pushed back by traffic in either direction
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var idled atomic.Bool
+	idleTimeout := h.streamIdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultStreamIdleTimeout
+	}
+	idle := time.AfterFunc(idleTimeout, func() {
+		idled.Store(true)
+		cancel()
+	})
+	defer idle.Stop()
+
+	var transferred atomic.Int64
+	touch := func(n int) {
+		transferred.Add(int64(n))
+		idle.Reset(idleTimeout)
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL = streamURL
+			pr.Out.Host = ""
+			pr.SetXForwarded()
+		},
+		Transport: h.client().Transport,
+		// Flush every write so events reach the client as soon as the orchestrator sends them
+		FlushInterval: -1,
+		ModifyResponse: func(resp *http.Response) error {
+			span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+			h.recordStreamUpstream(ctx, target, upstreamFailed(&OrchestrationResponse{StatusCode: resp.StatusCode}, nil))
+			resp.Body = newActivityBody(resp.Body, touch, &idled)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if r.Context().Err() != nil && !idled.Load() {
+				return
+			}
+			log.Printf("[GATEWAY] Failed to open stream for CEP %s: %v", cep, err)
+			span.SetStatus(codes.Error, "Failed to open stream")
+			span.RecordError(err)
+			h.recordStreamUpstream(ctx, target, true)
+			w.Header().Set("Content-Type", "application/json")
+			if idled.Load() {
+				h.writeError(ctx, w, http.StatusGatewayTimeout, "orchestration service did not open the stream in time")
+				return
+			}
+			h.writeError(ctx, w, http.StatusBadGateway, "failed to open stream")
+		},
+	}
+
+	start := time.Now()
+	log.Printf("[GATEWAY] Streaming CEP %s (%s) from %s", cep, protocol, streamURL)
+	defer func() {
+		closedBy := streamClosedByUpstream
+		switch {
+		case idled.Load():
+			closedBy = streamClosedByIdle
+		case r.Context().Err() != nil:
+			closedBy = streamClosedByClient
+		}
+		duration := time.Since(start)
+		span.SetAttributes(
+			attribute.Int64("stream.bytes", transferred.Load()),
+			attribute.Int64("stream.duration_ms", duration.Milliseconds()),
+			attribute.String("stream.closed_by", closedBy),
+		)
+		log.Printf("[GATEWAY] Stream for CEP %s closed by %s after %v, %d bytes", cep, closedBy, duration, transferred.Load())
+	}()
+
+	proxy.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// streamUpstream picks the regional orchestrator for cep, or the default one
+// when routing is disabled or the region's breaker is open
+func (h *GatewayHandler) streamUpstream(cep string) (*upstream, error) {
+	if h.router == nil {
+		return &upstream{region: DefaultRegion, url: h.orchestrationServiceURL}, nil
+	}
+	if target := h.router.resolve(cep); target != h.router.fallback && target.breaker.Allow() {
+		return target, nil
+	}
+	if !h.router.fallback.breaker.Allow() {
+		return nil, ErrOrchestrationUnavailable
+	}
+	return h.router.fallback, nil
+}
+
+// recordStreamUpstream feeds whether a stream could be opened to the upstream's breaker
+func (h *GatewayHandler) recordStreamUpstream(ctx context.Context, u *upstream, failed bool) {
+	if h.router == nil || u.breaker == nil {
+		return
+	}
+	if failed {
+		u.breaker.Failure()
+		h.router.record(ctx, u.region, routeOutcomeFailure)
+		return
+	}
+	u.breaker.Success()
+	h.router.record(ctx, u.region, routeOutcomeSuccess)
+}
+
+// activityBody reports the bytes moved through an upstream response body.
+// After an idle timeout the read error is turned into EOF so the stream ends
+// cleanly instead of aborting the client connection.
+type activityBody struct {
+	io.ReadCloser
+	touch func(n int)
+	idled *atomic.Bool
+}
+
+// activityConn is the body of a 101 response, which ReverseProxy uses as the
+// upstream side of the WebSocket tunnel
+type activityConn struct {
+	*activityBody
+	conn io.ReadWriteCloser
+}
+
+func newActivityBody(body io.ReadCloser, touch func(n int), idled *atomic.Bool) io.ReadCloser {
+	b := &activityBody{ReadCloser: body, touch: touch, idled: idled}
+	if conn, ok := body.(io.ReadWriteCloser); ok {
+		return &activityConn{activityBody: b, conn: conn}
+	}
+	return b
+}
+
+func (b *activityBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.touch(n)
+	}
+	if err != nil && !errors.Is(err, io.EOF) && b.idled.Load() {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (c *activityConn) Write(p []byte) (int, error) {
+	n, err := c.conn.Write(p)
+	if n > 0 {
+		c.touch(n)
+	}
+	return n, err
+}
@@ -0,0 +1,157 @@
+package gateway
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func newStreamGateway(h *GatewayHandler) *httptest.Server {
+	r := mux.NewRouter()
+	r.HandleFunc("/cep/{cep}/stream", h.StreamCEP).Methods("GET")
+	return httptest.NewServer(r)
+}
+
+func TestStreamCEP_FlushesEventsAsTheyArrive(t *testing.T) {
+	release := make(chan struct{})
+	var upstreamPath string
+	orchestrator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamPath = r.URL.Path
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: weather\ndata: {\"temp_C\":25}\n\n")
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer orchestrator.Close()
+	defer close(release)
+
+	gw := newStreamGateway(NewGatewayHandler(orchestrator.URL))
+	defer gw.Close()
+
+	resp, err := http.Get(gw.URL + "/cep/29902555/stream")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %q", ct)
+	}
+
+	// The upstream is still open, so this only succeeds if the event was flushed
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Expected the first event before the stream ends, got %v", err)
+	}
+	if line != "event: weather\n" {
+		t.Errorf("Unexpected line %q", line)
+	}
+	if upstreamPath != "/weather/29902-555/stream" {
+		t.Errorf("Expected the orchestrator stream path, got %q", upstreamPath)
+	}
+}
+
+func TestStreamCEP_ClosesIdleStreams(t *testing.T) {
+	orchestrator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "retry: 30000\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer orchestrator.Close()
+
+	gw := newStreamGateway(NewGatewayHandler(orchestrator.URL).WithStreamIdleTimeout(100 * time.Millisecond))
+	defer gw.Close()
+
+	resp, err := http.Get(gw.URL + "/cep/29902555/stream")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(resp.Body)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected the stream to end cleanly, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the idle stream to be closed")
+	}
+}
+
+func TestStreamCEP_InvalidCEP(t *testing.T) {
+	gw := newStreamGateway(NewGatewayHandler("http://localhost:0"))
+	defer gw.Close()
+
+	resp, err := http.Get(gw.URL + "/cep/123/stream")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d", resp.StatusCode)
+	}
+}
+
+func TestStreamCEP_TunnelsWebSocketUpgrades(t *testing.T) {
+	// Minimal upgrade endpoint echoing bytes back, standing in for a WebSocket server
+	orchestrator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		rw.Flush()
+		io.Copy(conn, rw)
+	}))
+	defer orchestrator.Close()
+
+	gw := newStreamGateway(NewGatewayHandler(orchestrator.URL))
+	defer gw.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(gw.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	fmt.Fprint(conn, "GET /cep/29902555/stream HTTP/1.1\r\nHost: gateway\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("Expected an upgrade response, got %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected 101, got %d", resp.StatusCode)
+	}
+
+	fmt.Fprint(conn, "ping")
+	echo := make([]byte, 4)
+	if _, err := io.ReadFull(reader, echo); err != nil {
+		t.Fatalf("Expected the echo through the tunnel, got %v", err)
+	}
+	if string(echo) != "ping" {
+		t.Errorf("Expected ping, got %q", echo)
+	}
+}
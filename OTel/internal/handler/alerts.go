@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"otel/internal/domain"
+	"otel/internal/service"
+	"otel/pkg/telemetry"
+	"otel/pkg/validator"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AlertsHandler handles HTTP requests for weather alerts
+type AlertsHandler struct {
+	alertService    *service.AlertService
+	tracer          trace.Tracer
+	traceIDInErrors bool
+}
+
+// NewAlertsHandler creates a new alerts handler
+func NewAlertsHandler(alertService *service.AlertService) *AlertsHandler {
+	log.Printf("[ORCHESTRATOR] Initializing alerts handler")
+	return &AlertsHandler{
+		alertService: alertService,
+		tracer:       telemetry.GetTracer("otel-orchestration"),
+	}
+}
+
+// WithTraceIDInErrors enables returning the trace ID in error response bodies
+func (h *AlertsHandler) WithTraceIDInErrors(enabled bool) *AlertsHandler {
+	h.traceIDInErrors = enabled
+	return h
+}
+
+// GetAlertsByCEP godoc
+// @Summary Obter alertas meteorológicos por CEP
+// @Description Retorna os alertas oficiais vigentes (tempestades, enchentes, etc.) para a cidade do CEP. A lista é vazia quando não há alertas.
+// @Tags weather
+// @Produce json
+// @Param cep path string true "CEP brasileiro (8 dígitos)" example("01310100")
+// @Success 200 {object} domain.AlertsResponse "Alertas vigentes"
+// @Failure 404 {object} domain.ErrorResponse "CEP não encontrado"
+// @Failure 422 {object} domain.ErrorResponse "CEP inválido"
+// @Failure 500 {object} domain.ErrorResponse "Erro ao consultar os alertas"
+// @Router /alerts/{cep} [get]
+func (h *AlertsHandler) GetAlertsByCEP(w http.ResponseWriter, r *http.Request) {
+	cep := mux.Vars(r)["cep"]
+
+	ctx, span := h.tracer.Start(r.Context(), "orchestration.get_alerts_by_cep")
+	defer span.End()
+	span.SetAttributes(attribute.String("cep.input", cep))
+
+	// Unlike /weather, clients reach this route directly, without the gateway validation
+	if !validator.ValidateCEP(cep) {
+		span.SetStatus(codes.Error, "Invalid CEP")
+		h.sendError(w, r, http.StatusUnprocessableEntity, "invalid zipcode")
+		return
+	}
+
+	alerts, err := h.alertService.GetAlertsByCEP(ctx, validator.CleanCEP(cep))
+	if err != nil {
+		span.SetStatus(codes.Error, "Error fetching alerts")
+		span.RecordError(err)
+		switch {
+		case errors.Is(err, service.ErrCEPNotFound):
+			h.sendError(w, r, http.StatusNotFound, service.ErrCEPNotFound.Error())
+		default:
+			h.sendError(w, r, http.StatusInternalServerError, service.ErrAlertsUnavailable.Error())
+		}
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("weather.city", alerts.City),
+		attribute.Int("weather.alerts.count", len(alerts.Alerts)),
+	)
+	span.SetStatus(codes.Ok, "Alerts request processed successfully")
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+func (h *AlertsHandler) sendError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	errorResponse := domain.ErrorResponse{Message: message}
+	if h.traceIDInErrors {
+		errorResponse.TraceID = telemetry.TraceID(r.Context())
+	}
+	log.Printf("[ORCHESTRATOR] Sending error response - Status: %d, Message: %s", statusCode, message)
+	writeJSON(w, statusCode, errorResponse)
+}
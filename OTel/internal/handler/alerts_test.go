@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"otel/internal/domain"
+	"otel/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+type stubLocationRepo struct{}
+
+func (stubLocationRepo) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	if cep == "01310100" {
+		return &domain.ViaCEPResponse{Localidade: "São Paulo", UF: "SP"}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type stubAlertsRepo struct{ err error }
+
+func (s stubAlertsRepo) GetAlertsByLocation(ctx context.Context, location string) ([]domain.WeatherAlert, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []domain.WeatherAlert{{Event: "Chuvas Intensas", Severity: "Severe"}}, nil
+}
+
+func TestAlertsHandler_GetAlertsByCEP(t *testing.T) {
+	tests := []struct {
+		name     string
+		cep      string
+		repoErr  error
+		status   int
+		expected string
+	}{
+		{name: "alerts found", cep: "01310100", status: http.StatusOK},
+		{name: "formatted cep", cep: "01310-100", status: http.StatusOK},
+		{name: "invalid cep", cep: "123", status: http.StatusUnprocessableEntity, expected: "invalid zipcode"},
+		{name: "cep not found", cep: "99999999", status: http.StatusNotFound, expected: service.ErrCEPNotFound.Error()},
+		{name: "provider failure", cep: "01310100", repoErr: errors.New("status 500"), status: http.StatusInternalServerError, expected: service.ErrAlertsUnavailable.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAlertsHandler(service.NewAlertService(stubLocationRepo{}, stubAlertsRepo{err: tt.repoErr}))
+			r := mux.NewRouter()
+			r.HandleFunc("/alerts/{cep}", h.GetAlertsByCEP)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/alerts/"+tt.cep, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			if tt.status != http.StatusOK {
+				var errResp domain.ErrorResponse
+				json.Unmarshal(rec.Body.Bytes(), &errResp)
+				if errResp.Message != tt.expected {
+					t.Errorf("Expected message %q, got %q", tt.expected, errResp.Message)
+				}
+				return
+			}
+
+			var resp domain.AlertsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.City != "São Paulo" || len(resp.Alerts) != 1 || resp.Alerts[0].Event != "Chuvas Intensas" {
+				t.Errorf("Unexpected response: %+v", resp)
+			}
+		})
+	}
+}
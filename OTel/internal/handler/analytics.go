@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"time"
+
+	"otel/internal/analytics"
+	"otel/internal/domain"
+	"otel/pkg/validator"
+)
+
+// recordQuery hands a processed lookup to the analytics recorder, if any
+func recordQuery(recorder *analytics.Recorder, cep string, weather *domain.WeatherResponse, statusCode int, latency time.Duration) {
+	if recorder == nil {
+		return
+	}
+
+	query := analytics.Query{
+		CEP:     validator.CleanCEP(cep),
+		Latency: latency,
+		Status:  statusCode,
+	}
+	if weather != nil {
+		query.City = weather.City
+		query.TempC = weather.TempC
+		query.Provider = weather.Provider
+	}
+	recorder.Record(query)
+}
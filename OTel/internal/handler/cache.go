@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CachePolicy controls the caching headers emitted on weather responses so the
+// orchestrator can sit behind a CDN or the gateway's cache.
+type CachePolicy struct {
+	// MaxAge is the max-age sent to end clients
+	MaxAge time.Duration
+	// SuccessTTL is the s-maxage for shared caches on 200 responses
+	SuccessTTL time.Duration
+	// NotFoundTTL is the s-maxage for shared caches on 404 responses
+	NotFoundTTL time.Duration
+	// Vary is the value of the Vary header (empty disables it)
+	Vary string
+}
+
+// apply sets Cache-Control and adds Vary for a response with the given status code.
+// Only 200 and 404 are cacheable; a zero TTL disables caching for that status.
+func (p CachePolicy) apply(w http.ResponseWriter, statusCode int) {
+	if p.Vary != "" {
+		w.Header().Add("Vary", p.Vary)
+	}
+
+	var sharedTTL time.Duration
+	switch statusCode {
+	case http.StatusOK:
+		sharedTTL = p.SuccessTTL
+	case http.StatusNotFound:
+		sharedTTL = p.NotFoundTTL
+	}
+
+	if sharedTTL <= 0 {
+		w.Header().Set("Cache-Control", "no-store")
+		return
+	}
+
+	maxAge := p.MaxAge
+	if maxAge > sharedTTL {
+		maxAge = sharedTTL
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d",
+		int(maxAge.Seconds()), int(sharedTTL.Seconds())))
+}
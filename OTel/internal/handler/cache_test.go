@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCachePolicy_Apply(t *testing.T) {
+	policy := CachePolicy{
+		MaxAge:      time.Minute,
+		SuccessTTL:  5 * time.Minute,
+		NotFoundTTL: 30 * time.Second,
+		Vary:        "Accept-Encoding",
+	}
+
+	tests := []struct {
+		name          string
+		statusCode    int
+		expectedCache string
+	}{
+		{"Success is cached by shared caches", http.StatusOK, "public, max-age=60, s-maxage=300"},
+		{"Not found uses its own TTL and caps max-age", http.StatusNotFound, "public, max-age=30, s-maxage=30"},
+		{"Server errors are not cached", http.StatusInternalServerError, "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			policy.apply(rr, tt.statusCode)
+
+			if got := rr.Header().Get("Cache-Control"); got != tt.expectedCache {
+				t.Errorf("Expected Cache-Control %q, got %q", tt.expectedCache, got)
+			}
+			if got := rr.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Expected Vary Accept-Encoding, got %q", got)
+			}
+		})
+	}
+}
+
+func TestCachePolicy_ZeroTTLDisablesCaching(t *testing.T) {
+	rr := httptest.NewRecorder()
+	CachePolicy{}.apply(rr, http.StatusOK)
+
+	if got := rr.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Expected no-store, got %q", got)
+	}
+	if got := rr.Header().Get("Vary"); got != "" {
+		t.Errorf("Expected no Vary header, got %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPayloadCacheEntries bounds the payload cache; new CEPs are not cached once it is full
+const maxPayloadCacheEntries = 10000
+
+// weakETag derives a weak validator from a response body. It is weak because
+// equivalent readings re-encoded by the orchestrator need not be byte-identical.
+func weakETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag using the
+// weak comparison required for GET requests (RFC 9110, section 13.1.2)
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// cachedPayload is an encoded weather response and its ETag
+type cachedPayload struct {
+	body     []byte
+	etag     string
+	storedAt time.Time
+}
+
+// payloadCache keeps recent weather payloads by CEP so conditional requests
+// can be answered with 304 without calling ViaCEP and WeatherAPI
+type payloadCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedPayload
+	now     func() time.Time
+}
+
+func newPayloadCache(ttl time.Duration) *payloadCache {
+	return &payloadCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedPayload),
+		now:     time.Now,
+	}
+}
+
+// get returns the payload stored for cep if it is still fresh
+func (c *payloadCache) get(cep string) (cachedPayload, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	payload, ok := c.entries[cep]
+	if !ok {
+		return cachedPayload{}, false
+	}
+	if c.now().Sub(payload.storedAt) > c.ttl {
+		delete(c.entries, cep)
+		return cachedPayload{}, false
+	}
+	return payload, true
+}
+
+// put stores the payload for cep, evicting expired entries when the cache is full
+func (c *payloadCache) put(cep string, body []byte, etag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[cep]; !exists && len(c.entries) >= maxPayloadCacheEntries {
+		for key, payload := range c.entries {
+			if now.Sub(payload.storedAt) > c.ttl {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= maxPayloadCacheEntries {
+			return
+		}
+	}
+	c.entries[cep] = cachedPayload{body: body, etag: etag, storedAt: now}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeakETag(t *testing.T) {
+	etag := weakETag([]byte(`{"city":"São Paulo","temp_C":28.5}`))
+	if etag[:3] != `W/"` || etag[len(etag)-1] != '"' {
+		t.Errorf("Expected weak ETag, got %s", etag)
+	}
+	if etag != weakETag([]byte(`{"city":"São Paulo","temp_C":28.5}`)) {
+		t.Error("Expected the same payload to produce the same ETag")
+	}
+	if etag == weakETag([]byte(`{"city":"São Paulo","temp_C":28.6}`)) {
+		t.Error("Expected a different payload to produce a different ETag")
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `W/"0123456789abcdef"`
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		expected    bool
+	}{
+		{"Empty header", "", false},
+		{"Exact match", `W/"0123456789abcdef"`, true},
+		{"Strong form matches weakly", `"0123456789abcdef"`, true},
+		{"List with match", `W/"other", W/"0123456789abcdef"`, true},
+		{"Wildcard", "*", true},
+		{"No match", `W/"fedcba9876543210"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.ifNoneMatch, etag); got != tt.expected {
+				t.Errorf("etagMatches(%q) = %v, want %v", tt.ifNoneMatch, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPayloadCache_Expires(t *testing.T) {
+	now := time.Now()
+	cache := newPayloadCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.put("01310100", []byte("{}\n"), `W/"a"`)
+	if payload, ok := cache.get("01310100"); !ok || payload.etag != `W/"a"` {
+		t.Fatalf("Expected fresh payload, got %+v, %v", payload, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.get("01310100"); ok {
+		t.Error("Expected payload to expire after the TTL")
+	}
+}
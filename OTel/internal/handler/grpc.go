@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"otel/internal/analytics"
+	"otel/internal/service"
+	"otel/pkg/api/weatherv1"
+	"otel/pkg/telemetry"
+	"otel/pkg/validator"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WeatherGRPCServer serves the weather lookup over gRPC
+type WeatherGRPCServer struct {
+	weatherv1.UnimplementedWeatherServiceServer
+	weatherService *service.WeatherService
+	tracer         trace.Tracer
+	analytics      *analytics.Recorder
+}
+
+// NewWeatherGRPCServer creates a new gRPC weather server
+func NewWeatherGRPCServer(weatherService *service.WeatherService) *WeatherGRPCServer {
+	log.Printf("[ORCHESTRATOR] Initializing gRPC weather server")
+	return &WeatherGRPCServer{
+		weatherService: weatherService,
+		tracer:         telemetry.GetTracer("otel-orchestration"),
+	}
+}
+
+// WithAnalytics records every lookup that reaches the weather service
+func (s *WeatherGRPCServer) WithAnalytics(recorder *analytics.Recorder) *WeatherGRPCServer {
+	s.analytics = recorder
+	return s
+}
+
+// GetWeatherByCEP returns the current temperature for a CEP.
+// Unlike the REST route, the CEP is validated here since gRPC clients do not go through the gateway.
+func (s *WeatherGRPCServer) GetWeatherByCEP(ctx context.Context, req *weatherv1.GetWeatherByCEPRequest) (*weatherv1.GetWeatherByCEPResponse, error) {
+	startTime := time.Now()
+	cep := req.GetCep()
+
+	ctx, span := s.tracer.Start(ctx, "orchestration.grpc.get_weather_by_cep")
+	defer span.End()
+	span.SetAttributes(attribute.String("cep.input", cep))
+
+	baggageCEP, clientID := telemetry.RequestBaggage(ctx)
+	log.Printf("[ORCHESTRATOR] Received gRPC weather request for CEP: %s (baggage cep=%q client_id=%q)", cep, baggageCEP, clientID)
+
+	if !validator.ValidateCEP(cep) {
+		span.SetStatus(otelcodes.Error, "Invalid CEP")
+		return nil, status.Error(codes.InvalidArgument, "invalid zipcode")
+	}
+
+	weather, err := s.weatherService.GetWeatherByCEP(ctx, validator.CleanCEP(cep))
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Error processing gRPC request for CEP %s: %v", cep, err)
+		span.SetStatus(otelcodes.Error, "Error processing CEP")
+		span.RecordError(err)
+		grpcErr := grpcError(err)
+		recordQuery(s.analytics, cep, nil, httpStatusFromCode(status.Code(grpcErr)), time.Since(startTime))
+		return nil, grpcErr
+	}
+
+	duration := time.Since(startTime)
+	recordQuery(s.analytics, cep, weather, http.StatusOK, duration)
+	log.Printf("[ORCHESTRATOR] Successfully processed gRPC weather request for CEP: %s in %v", cep, duration)
+	span.SetAttributes(
+		attribute.String("weather.city", weather.City),
+		attribute.Float64("weather.temp_c", weather.TempC),
+		attribute.Int64("request.duration_ms", duration.Milliseconds()),
+	)
+	span.SetStatus(otelcodes.Ok, "Weather request processed successfully")
+
+	return &weatherv1.GetWeatherByCEPResponse{
+		City:  weather.City,
+		TempC: weather.TempC,
+		TempF: weather.TempF,
+		TempK: weather.TempK,
+		Stale: weather.Stale,
+	}, nil
+}
+
+// httpStatusFromCode maps the codes returned by grpcError back to HTTP statuses for analytics
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// grpcError maps a service error to a gRPC status, mirroring the REST status codes
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrCEPNotFound):
+		return status.Error(codes.NotFound, service.ErrCEPNotFound.Error())
+	case errors.Is(err, service.ErrWeatherDataUnavailable):
+		return status.Error(codes.Unavailable, service.ErrWeatherDataUnavailable.Error())
+	case errors.Is(err, service.ErrAnomalousReading):
+		return status.Error(codes.Unavailable, service.ErrAnomalousReading.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
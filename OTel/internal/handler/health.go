@@ -3,10 +3,33 @@ package handler
 import (
 	"log"
 	"net/http"
+
+	"otel/internal/domain"
+	"otel/internal/service"
+)
+
+// Readiness states reported by /health/ready
+const (
+	readinessReady    = "ready"
+	readinessDegraded = "degraded"
+	readinessNotReady = "not_ready"
 )
 
+// QuotaStatsProvider reports the WeatherAPI budget
+type QuotaStatsProvider interface {
+	Stats() service.QuotaStats
+}
+
+// ReadinessResponse is the body of /health/ready
+type ReadinessResponse struct {
+	Status string              `json:"status" example:"ready"`
+	Quota  *service.QuotaStats `json:"weather_api_quota,omitempty"`
+}
+
 // HealthHandler handles health check requests
-type HealthHandler struct{}
+type HealthHandler struct {
+	quota QuotaStatsProvider
+}
 
 // NewHealthHandler creates a new health handler
 func NewHealthHandler() *HealthHandler {
@@ -34,3 +57,54 @@ func (h *HealthHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("[ORCHESTRATOR] Health check response sent to %s", clientIP)
 }
+
+// WithQuota makes readiness depend on the WeatherAPI budget
+func (h *HealthHandler) WithQuota(quota QuotaStatsProvider) *HealthHandler {
+	h.quota = quota
+	return h
+}
+
+// ReadinessCheck godoc
+// @Summary Readiness check
+// @Description Informa se o serviço consegue responder consultas de clima, considerando a cota da WeatherAPI.
+// @Description "degraded" indica cota em economia ou esgotada com provedor de fallback disponível.
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadinessResponse "Pronto (ready ou degraded)"
+// @Failure 503 {object} ReadinessResponse "Cota esgotada sem fallback"
+// @Router /health/ready [get]
+func (h *HealthHandler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	if h.quota == nil {
+		writeJSON(w, http.StatusOK, ReadinessResponse{Status: readinessReady})
+		return
+	}
+
+	stats := h.quota.Stats()
+	response := ReadinessResponse{Status: readinessReady, Quota: &stats}
+	statusCode := http.StatusOK
+	switch {
+	case stats.State == service.QuotaStateExhausted && !stats.FallbackAvailable:
+		response.Status = readinessNotReady
+		statusCode = http.StatusServiceUnavailable
+		log.Printf("[ORCHESTRATOR] Not ready: WeatherAPI quota exhausted and no fallback provider")
+	case stats.State != service.QuotaStateOK:
+		response.Status = readinessDegraded
+	}
+	writeJSON(w, statusCode, response)
+}
+
+// GetQuota godoc
+// @Summary Consumo da cota da WeatherAPI
+// @Description Retorna as chamadas e o saldo das cotas mensal e diária da WeatherAPI, a projeção para o fim do mês e quantas consultas cada provedor respondeu
+// @Tags health
+// @Produce json
+// @Success 200 {object} service.QuotaStats "Consumo da cota"
+// @Failure 404 {object} domain.ErrorResponse "Contagem da cota desabilitada"
+// @Router /quota [get]
+func (h *HealthHandler) GetQuota(w http.ResponseWriter, r *http.Request) {
+	if h.quota == nil {
+		writeJSON(w, http.StatusNotFound, domain.ErrorResponse{Message: "quota accounting disabled"})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.quota.Stats())
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"otel/internal/service"
+)
+
+// fixedQuota reports fixed quota stats
+type fixedQuota struct {
+	stats service.QuotaStats
+}
+
+func (q fixedQuota) Stats() service.QuotaStats {
+	return q.stats
+}
+
+func TestReadinessCheck(t *testing.T) {
+	testCases := []struct {
+		name           string
+		stats          service.QuotaStats
+		expectedCode   int
+		expectedStatus string
+	}{
+		{"budget available", service.QuotaStats{State: service.QuotaStateOK}, http.StatusOK, readinessReady},
+		{"conserving", service.QuotaStats{State: service.QuotaStateConserving}, http.StatusOK, readinessDegraded},
+		{"exhausted with fallback", service.QuotaStats{State: service.QuotaStateExhausted, FallbackAvailable: true}, http.StatusOK, readinessDegraded},
+		{"exhausted without fallback", service.QuotaStats{State: service.QuotaStateExhausted}, http.StatusServiceUnavailable, readinessNotReady},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHealthHandler().WithQuota(fixedQuota{stats: tc.stats})
+			rr := httptest.NewRecorder()
+			h.ReadinessCheck(rr, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+			if rr.Code != tc.expectedCode {
+				t.Errorf("Expected %d, got %d", tc.expectedCode, rr.Code)
+			}
+			var response ReadinessResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Status != tc.expectedStatus || response.Quota == nil || response.Quota.State != tc.stats.State {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestGetQuota(t *testing.T) {
+	h := NewHealthHandler().WithQuota(fixedQuota{stats: service.QuotaStats{Calls: 300, Remaining: 700, State: service.QuotaStateOK}})
+	rr := httptest.NewRecorder()
+	h.GetQuota(rr, httptest.NewRequest(http.MethodGet, "/quota", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rr.Code)
+	}
+	var stats service.QuotaStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if stats.Calls != 300 || stats.Remaining != 700 || stats.State != service.QuotaStateOK {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	rr = httptest.NewRecorder()
+	NewHealthHandler().GetQuota(rr, httptest.NewRequest(http.MethodGet, "/quota", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without quota accounting, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"otel/internal/analytics"
+	"otel/internal/domain"
+)
+
+const (
+	defaultStatsWindow = 24 * time.Hour
+	defaultTopCities   = 10
+	maxTopCities       = 100
+)
+
+// StatsHandler serves aggregated query analytics
+type StatsHandler struct {
+	store *analytics.Store
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(store *analytics.Store) *StatsHandler {
+	log.Printf("[ORCHESTRATOR] Initializing stats handler")
+	return &StatsHandler{store: store}
+}
+
+// GetStats godoc
+// @Summary Estatísticas de consultas
+// @Description Retorna o total de consultas, erros, latência p95, consultas por provedor e as cidades mais consultadas na janela informada
+// @Tags stats
+// @Produce json
+// @Param window query string false "Janela de tempo (duração Go, ex.: 1h, 24h)" default(24h)
+// @Param limit query int false "Quantidade de cidades no ranking (1-100)" default(10)
+// @Success 200 {object} analytics.Stats "Estatísticas da janela"
+// @Failure 400 {object} domain.ErrorResponse "Parâmetros inválidos"
+// @Failure 500 {object} domain.ErrorResponse "Erro ao consultar o banco de analytics"
+// @Router /stats [get]
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	window := defaultStatsWindow
+	if value := r.URL.Query().Get("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			writeJSON(w, http.StatusBadRequest, domain.ErrorResponse{Message: "invalid window"})
+			return
+		}
+		window = parsed
+	}
+
+	limit := defaultTopCities
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTopCities {
+			writeJSON(w, http.StatusBadRequest, domain.ErrorResponse{Message: "invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+
+	stats, err := h.store.Stats(r.Context(), time.Now().Add(-window), limit)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Error aggregating stats: %v", err)
+		writeJSON(w, http.StatusInternalServerError, domain.ErrorResponse{Message: "internal server error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// writeJSON sends an uncacheable JSON response
+func writeJSON(w http.ResponseWriter, statusCode int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Printf("[ORCHESTRATOR] Error encoding JSON response: %v", err)
+	}
+}
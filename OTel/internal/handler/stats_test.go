@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otel/internal/analytics"
+)
+
+func TestStatsHandler_GetStats(t *testing.T) {
+	store, err := analytics.Open(context.Background(), analytics.DriverSQLite, filepath.Join(t.TempDir(), "analytics.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	err = store.Insert(context.Background(), []analytics.Query{
+		{CEP: "01310100", City: "São Paulo", TempC: 25, Latency: 10 * time.Millisecond, Provider: "weatherapi", Status: 200, Timestamp: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert query: %v", err)
+	}
+
+	h := NewStatsHandler(store)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{name: "defaults", query: "", status: http.StatusOK},
+		{name: "custom window and limit", query: "?window=1h&limit=5", status: http.StatusOK},
+		{name: "invalid window", query: "?window=yesterday", status: http.StatusBadRequest},
+		{name: "negative window", query: "?window=-1h", status: http.StatusBadRequest},
+		{name: "limit too high", query: "?limit=1000", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.GetStats(rec, httptest.NewRequest(http.MethodGet, "/stats"+tt.query, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var stats analytics.Stats
+			if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+				t.Fatalf("Failed to decode stats: %v", err)
+			}
+			if stats.Requests != 1 || len(stats.TopCities) != 1 || stats.TopCities[0].City != "São Paulo" {
+				t.Errorf("Unexpected stats: %+v", stats)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"otel/internal/domain"
+	"otel/internal/service"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// streamHeartbeat keeps idle SSE connections open through proxies
+const streamHeartbeat = 15 * time.Second
+
+// WithRefresher enables the SSE stream endpoint backed by refresher
+func (h *WeatherHandler) WithRefresher(refresher *service.WeatherRefresher) *WeatherHandler {
+	h.refresher = refresher
+	return h
+}
+
+// StreamWeatherByCEP godoc
+// @Summary Acompanhar temperatura por CEP (SSE)
+// @Description Mantém uma conexão Server-Sent Events e envia a temperatura atualizada a cada intervalo de atualização (STREAM_REFRESH_INTERVAL)
+// @Description Eventos: "weather" (domain.WeatherResponse) e "error" (domain.ErrorResponse) quando uma atualização falha; a conexão continua aberta
+// @Tags weather
+// @Produce text/event-stream
+// @Param cep path string true "CEP brasileiro (8 dígitos, já validado)" example("01310100")
+// @Success 200 {string} string "Fluxo de eventos"
+// @Failure 404 {object} domain.ErrorResponse "CEP não encontrado (antes de abrir o fluxo)"
+// @Failure 500 {object} domain.ErrorResponse "Erro interno do servidor"
+// @Router /weather/{cep}/stream [get]
+func (h *WeatherHandler) StreamWeatherByCEP(w http.ResponseWriter, r *http.Request) {
+	cep := mux.Vars(r)["cep"]
+
+	ctx, span := h.tracer.Start(r.Context(), "orchestration.stream_weather_by_cep")
+	defer span.End()
+	span.SetAttributes(attribute.String("cep.input", cep))
+
+	if h.refresher == nil {
+		span.SetStatus(codes.Error, "Streaming disabled")
+		h.sendJSON(w, http.StatusNotFound, domain.ErrorResponse{Message: "streaming is not enabled"})
+		return
+	}
+
+	updates, unsubscribe := h.refresher.Subscribe(cep)
+	defer unsubscribe()
+
+	// The first update decides the status code, so an unknown CEP is a plain 404
+	var first service.WeatherUpdate
+	select {
+	case first = <-updates:
+	case <-ctx.Done():
+		return
+	}
+	if first.Err != nil {
+		log.Printf("[ORCHESTRATOR] Not opening stream for CEP %s: %v", cep, first.Err)
+		span.SetStatus(codes.Error, "Error processing CEP")
+		span.RecordError(first.Err)
+		h.handleError(ctx, w, first.Err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	log.Printf("[ORCHESTRATOR] Streaming weather for CEP %s every %v", cep, h.refresher.Interval())
+	fmt.Fprintf(w, "retry: %d\n\n", h.refresher.Interval().Milliseconds())
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	sent := 0
+	defer func() { span.SetAttributes(attribute.Int("stream.events", sent)) }()
+
+	if err := h.writeUpdate(ctx, w, rc, first); err != nil {
+		log.Printf("[ORCHESTRATOR] Stream for CEP %s closed: %v", cep, err)
+		return
+	}
+	sent++
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("[ORCHESTRATOR] Client left stream for CEP %s after %d events", cep, sent)
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			if err := h.writeUpdate(ctx, w, rc, update); err != nil {
+				log.Printf("[ORCHESTRATOR] Stream for CEP %s closed: %v", cep, err)
+				return
+			}
+			sent++
+		case <-heartbeat.C:
+			// Comments are ignored by EventSource clients
+			if err := writeEvent(w, rc, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeUpdate sends update as a "weather" event, or an "error" event when the refresh failed
+func (h *WeatherHandler) writeUpdate(ctx context.Context, w http.ResponseWriter, rc *http.ResponseController, update service.WeatherUpdate) error {
+	event, payload := "weather", any(update.Weather)
+	if update.Err != nil {
+		_, errorResponse := h.errorResponse(ctx, update.Err)
+		event, payload = "error", errorResponse
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return writeEvent(w, rc, fmt.Sprintf("event: %s\ndata: %s\n\n", event, data))
+}
+
+// writeEvent writes a raw SSE frame and flushes it to the client
+func writeEvent(w http.ResponseWriter, rc *http.ResponseController, frame string) error {
+	if _, err := fmt.Fprint(w, frame); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
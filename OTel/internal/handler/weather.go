@@ -1,15 +1,20 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"otel/internal/analytics"
 	"otel/internal/domain"
 	"otel/internal/service"
+	"otel/pkg/featureflag"
 	"otel/pkg/telemetry"
+	"otel/pkg/validator"
 
 	"github.com/gorilla/mux"
 	"go.opentelemetry.io/otel/attribute"
@@ -19,19 +24,63 @@ import (
 
 // WeatherHandler handles HTTP requests for weather endpoints
 type WeatherHandler struct {
-	weatherService *service.WeatherService
-	tracer         trace.Tracer
+	weatherService  *service.WeatherService
+	cachePolicy     CachePolicy
+	tracer          trace.Tracer
+	traceIDInErrors bool
+	payloads        *payloadCache
+	refresher       *service.WeatherRefresher
+	analytics       *analytics.Recorder
+	providers       *service.ProviderSelector
+	flags           *featureflag.Flags
 }
 
+// providerHeader lets clients pin the weather provider when the query parameter is absent
+const providerHeader = "X-Weather-Provider"
+
 // NewWeatherHandler creates a new weather handler
-func NewWeatherHandler(weatherService *service.WeatherService) *WeatherHandler {
+func NewWeatherHandler(weatherService *service.WeatherService, cachePolicy CachePolicy) *WeatherHandler {
 	log.Printf("[ORCHESTRATOR] Initializing weather handler")
 	return &WeatherHandler{
 		weatherService: weatherService,
+		cachePolicy:    cachePolicy,
 		tracer:         telemetry.GetTracer("otel-orchestration"),
 	}
 }
 
+// WithTraceIDInErrors enables returning the trace ID in error response bodies
+func (h *WeatherHandler) WithTraceIDInErrors(enabled bool) *WeatherHandler {
+	h.traceIDInErrors = enabled
+	return h
+}
+
+// WithETagCache keeps weather payloads for ttl so requests whose If-None-Match
+// matches a fresh payload get a 304 without calling the upstream APIs
+func (h *WeatherHandler) WithETagCache(ttl time.Duration) *WeatherHandler {
+	if ttl > 0 {
+		h.payloads = newPayloadCache(ttl)
+	}
+	return h
+}
+
+// WithAnalytics records every lookup that reaches the weather service
+func (h *WeatherHandler) WithAnalytics(recorder *analytics.Recorder) *WeatherHandler {
+	h.analytics = recorder
+	return h
+}
+
+// WithProviders lets clients pin a weather provider with ?provider= or X-Weather-Provider
+func (h *WeatherHandler) WithProviders(selector *service.ProviderSelector) *WeatherHandler {
+	h.providers = selector
+	return h
+}
+
+// WithFlags turns the ETag cache and provider pinning on and off at runtime
+func (h *WeatherHandler) WithFlags(flags *featureflag.Flags) *WeatherHandler {
+	h.flags = flags
+	return h
+}
+
 // GetWeatherByCEP godoc
 // @Summary Obter temperatura por CEP
 // @Description Recebe um CEP brasileiro válido (já validado pelo Gateway) e retorna a temperatura atual em Celsius, Fahrenheit e Kelvin
@@ -39,9 +88,15 @@ func NewWeatherHandler(weatherService *service.WeatherService) *WeatherHandler {
 // @Accept json
 // @Produce json
 // @Param cep path string true "CEP brasileiro (8 dígitos, já validado)" example("01310100")
+// @Param If-None-Match header string false "ETag de uma resposta anterior"
+// @Param provider query string false "Provedor fixo para a consulta" Enums(weatherapi, open-meteo)
+// @Param X-Weather-Provider header string false "Provedor fixo, usado quando o parâmetro provider não é informado"
 // @Success 200 {object} domain.WeatherResponse "Informações de temperatura"
+// @Success 304 "Temperatura inalterada desde o ETag informado"
+// @Failure 400 {object} domain.ErrorResponse "Provedor desconhecido"
 // @Failure 404 {object} domain.ErrorResponse "CEP não encontrado"
 // @Failure 500 {object} domain.ErrorResponse "Erro interno do servidor"
+// @Failure 502 {object} domain.ErrorResponse "Temperatura implausível retornada pelo provedor"
 // @Router /weather/{cep} [get]
 func (h *WeatherHandler) GetWeatherByCEP(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
@@ -65,18 +120,67 @@ func (h *WeatherHandler) GetWeatherByCEP(w http.ResponseWriter, r *http.Request)
 		attribute.String("http.url", r.URL.String()),
 	)
 
-	log.Printf("[ORCHESTRATOR] Received weather request for CEP: %s from %s", cep, clientIP)
+	baggageCEP, clientID := telemetry.RequestBaggage(ctx)
+	log.Printf("[ORCHESTRATOR] Received weather request for CEP: %s from %s (baggage cep=%q client_id=%q)",
+		cep, clientIP, baggageCEP, clientID)
+
+	// Pin the provider when the client asks for one
+	pinning := h.flags.Enabled(featureflag.ProviderPinning)
+	var provider string
+	if pinning {
+		provider = r.URL.Query().Get("provider")
+		if provider == "" {
+			provider = r.Header.Get(providerHeader)
+		}
+	}
+	if h.providers != nil && pinning {
+		w.Header().Add("Vary", providerHeader)
+	}
+	if provider != "" {
+		if h.providers == nil || !h.providers.Supports(provider) {
+			log.Printf("[ORCHESTRATOR] Unknown weather provider %q requested by %s", provider, clientIP)
+			span.SetStatus(codes.Error, "Unknown weather provider")
+			h.handleError(ctx, w, service.ErrUnknownProvider)
+			return
+		}
+		span.SetAttributes(attribute.String("weather.provider.requested", provider))
+		ctx = service.WithProvider(ctx, provider)
+	}
+
+	// Pinned readings are cached apart from the default selection
+	payloadKey := validator.CleanCEP(cep)
+	if provider != "" {
+		payloadKey += "@" + provider
+	}
+
+	// Answer conditional requests from a fresh payload without calling upstream
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	payloads := h.payloads
+	if !h.flags.Enabled(featureflag.ETagCache) {
+		payloads = nil
+	}
+	if payloads != nil && ifNoneMatch != "" {
+		if payload, ok := payloads.get(payloadKey); ok && etagMatches(ifNoneMatch, payload.etag) {
+			log.Printf("[ORCHESTRATOR] ETag %s still valid for CEP %s, skipping upstream calls", payload.etag, cep)
+			span.SetAttributes(attribute.Bool("cache.etag_hit", true))
+			span.SetStatus(codes.Ok, "Not modified")
+			h.sendNotModified(w, payload.etag)
+			return
+		}
+	}
 
 	weather, err := h.weatherService.GetWeatherByCEP(ctx, cep)
 	if err != nil {
 		log.Printf("[ORCHESTRATOR] Error processing CEP %s from %s: %v", cep, clientIP, err)
 		span.SetStatus(codes.Error, "Error processing CEP")
 		span.RecordError(err)
-		h.handleError(w, err)
+		statusCode := h.handleError(ctx, w, err)
+		recordQuery(h.analytics, cep, nil, statusCode, time.Since(startTime))
 		return
 	}
 
 	duration := time.Since(startTime)
+	recordQuery(h.analytics, cep, weather, http.StatusOK, duration)
 	log.Printf("[ORCHESTRATOR] Successfully processed weather request for CEP: %s from %s in %v", cep, clientIP, duration)
 
 	span.SetAttributes(
@@ -87,11 +191,54 @@ func (h *WeatherHandler) GetWeatherByCEP(w http.ResponseWriter, r *http.Request)
 	)
 	span.SetStatus(codes.Ok, "Weather request processed successfully")
 
-	h.sendJSON(w, http.StatusOK, weather)
+	body, err := json.Marshal(weather)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Error encoding JSON response: %v", err)
+		h.handleError(ctx, w, err)
+		return
+	}
+	body = append(body, '\n')
+	etag := weakETag(body)
+
+	// Stale readings replace anomalies and must not be revalidated later
+	if payloads != nil && !weather.Stale {
+		payloads.put(payloadKey, body, etag)
+	}
+
+	if etagMatches(ifNoneMatch, etag) {
+		log.Printf("[ORCHESTRATOR] Weather for CEP %s unchanged, sending 304", cep)
+		h.sendNotModified(w, etag)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	if weather.Stale {
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+	}
+	log.Printf("[ORCHESTRATOR] Sending JSON response - Status: %d", http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	h.cachePolicy.apply(w, http.StatusOK)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// sendNotModified answers a conditional request whose ETag is still current
+func (h *WeatherHandler) sendNotModified(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	h.cachePolicy.apply(w, http.StatusOK)
+	w.WriteHeader(http.StatusNotModified)
 }
 
-// handleError handles different types of errors and sends appropriate HTTP responses
-func (h *WeatherHandler) handleError(w http.ResponseWriter, err error) {
+// handleError handles different types of errors, sends appropriate HTTP responses and returns the status code
+func (h *WeatherHandler) handleError(ctx context.Context, w http.ResponseWriter, err error) int {
+	statusCode, errorResponse := h.errorResponse(ctx, err)
+	log.Printf("[ORCHESTRATOR] Sending error response - Status: %d, Message: %s", statusCode, errorResponse.Message)
+	h.sendJSON(w, statusCode, errorResponse)
+	return statusCode
+}
+
+// errorResponse maps a service error to its status code and response body
+func (h *WeatherHandler) errorResponse(ctx context.Context, err error) (int, domain.ErrorResponse) {
 	var statusCode int
 	var message string
 
@@ -101,6 +248,12 @@ func (h *WeatherHandler) handleError(w http.ResponseWriter, err error) {
 	//	statusCode = http.StatusUnprocessableEntity
 	//	message = service.ErrInvalidCEP.Error()
 	//	log.Printf("[ORCHESTRATOR] Invalid CEP error: %v", err)
+	case errors.Is(err, service.ErrUnknownProvider):
+		statusCode = http.StatusBadRequest
+		message = service.ErrUnknownProvider.Error()
+		if h.providers != nil {
+			message += "; supported: " + strings.Join(h.providers.Providers(), ", ")
+		}
 	case errors.Is(err, service.ErrCEPNotFound):
 		statusCode = http.StatusNotFound
 		message = service.ErrCEPNotFound.Error()
@@ -109,21 +262,28 @@ func (h *WeatherHandler) handleError(w http.ResponseWriter, err error) {
 		statusCode = http.StatusInternalServerError
 		message = service.ErrWeatherDataUnavailable.Error()
 		log.Printf("[ORCHESTRATOR] Weather data unavailable error: %v", err)
+	case errors.Is(err, service.ErrAnomalousReading):
+		statusCode = http.StatusBadGateway
+		message = service.ErrAnomalousReading.Error()
+		log.Printf("[ORCHESTRATOR] Anomalous reading error: %v", err)
 	default:
 		statusCode = http.StatusInternalServerError
 		message = "internal server error"
 		log.Printf("[ORCHESTRATOR] Unexpected error: %v", err)
 	}
 
-	log.Printf("[ORCHESTRATOR] Sending error response - Status: %d, Message: %s", statusCode, message)
 	errorResponse := domain.ErrorResponse{Message: message}
-	h.sendJSON(w, statusCode, errorResponse)
+	if h.traceIDInErrors {
+		errorResponse.TraceID = telemetry.TraceID(ctx)
+	}
+	return statusCode, errorResponse
 }
 
 // sendJSON sends a JSON response
 func (h *WeatherHandler) sendJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	log.Printf("[ORCHESTRATOR] Sending JSON response - Status: %d", statusCode)
 	w.Header().Set("Content-Type", "application/json")
+	h.cachePolicy.apply(w, statusCode)
 	w.WriteHeader(statusCode)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		log.Printf("[ORCHESTRATOR] Error encoding JSON response: %v", err)
@@ -0,0 +1,104 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Config describes a constant-rate run against the gateway
+type Config struct {
+	Target      string        // URL of the gateway POST /cep endpoint
+	Rate        int           // requests per second
+	Duration    time.Duration // how long to keep sending requests
+	MaxInFlight int           // requests beyond this are dropped instead of queued
+	Timeout     time.Duration // per-request timeout
+	CEPs        []string      // request bodies rotate through these CEPs
+	Accept      []int         // status codes counted as success
+}
+
+// Sample is the outcome of a single request
+type Sample struct {
+	Latency    time.Duration
+	StatusCode int // 0 when the request failed before a response
+	Err        error
+}
+
+// Run sends requests at cfg.Rate for cfg.Duration and returns one sample per
+// request sent, plus the number of requests dropped because MaxInFlight was reached.
+// Ticks are not made up when the process falls behind, so the sent rate is an upper bound.
+func Run(ctx context.Context, cfg Config, client *http.Client) ([]Sample, int) {
+	interval := time.Second / time.Duration(cfg.Rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	deadline := time.NewTimer(cfg.Duration)
+	defer deadline.Stop()
+
+	var (
+		mu      sync.Mutex
+		samples = make([]Sample, 0, int(cfg.Duration.Seconds()+1)*cfg.Rate)
+		wg      sync.WaitGroup
+		dropped int
+	)
+	inFlight := make(chan struct{}, cfg.MaxInFlight)
+
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return samples, dropped
+		case <-deadline.C:
+			wg.Wait()
+			return samples, dropped
+		case <-ticker.C:
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			dropped++
+			continue
+		}
+
+		cep := cfg.CEPs[i%len(cfg.CEPs)]
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-inFlight
+				wg.Done()
+			}()
+			sample := hit(ctx, client, cfg, cep)
+			mu.Lock()
+			samples = append(samples, sample)
+			mu.Unlock()
+		}()
+	}
+}
+
+// hit sends a single POST /cep request and measures it until the body is read
+func hit(ctx context.Context, client *http.Client, cfg Config, cep string) Sample {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	body, _ := json.Marshal(map[string]string{"cep": cep})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Target, bytes.NewReader(body))
+	if err != nil {
+		return Sample{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return Sample{Latency: time.Since(start), Err: err}
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return Sample{Latency: time.Since(start), StatusCode: resp.StatusCode}
+}
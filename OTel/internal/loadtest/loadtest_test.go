@@ -0,0 +1,200 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{p: 0, want: time.Millisecond},
+		{p: 50, want: 50 * time.Millisecond},
+		{p: 95, want: 95 * time.Millisecond},
+		{p: 99, want: 99 * time.Millisecond},
+		{p: 100, want: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	cfg := Config{Target: "http://gateway/cep", Rate: 10, Duration: time.Second, Accept: []int{200, 404}}
+	samples := []Sample{
+		{Latency: 10 * time.Millisecond, StatusCode: http.StatusOK},
+		{Latency: 20 * time.Millisecond, StatusCode: http.StatusOK},
+		{Latency: 30 * time.Millisecond, StatusCode: http.StatusNotFound},
+		{Latency: 40 * time.Millisecond, StatusCode: http.StatusServiceUnavailable},
+		{Latency: 50 * time.Millisecond, Err: errors.New("connection refused")},
+	}
+
+	report := Summarize(cfg, samples, 5, time.Second)
+
+	if report.Requests != 5 || report.Successes != 3 || report.Errors != 2 || report.Dropped != 5 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if report.ErrorRate != 0.7 {
+		t.Errorf("error rate = %v, want 0.7", report.ErrorRate)
+	}
+	if report.StatusCodes["200"] != 2 || report.StatusCodes["503"] != 1 || report.StatusCodes["error"] != 1 {
+		t.Errorf("unexpected status codes: %v", report.StatusCodes)
+	}
+	if report.Latency.Min != 10 || report.Latency.Mean != 30 || report.Latency.P50 != 30 || report.Latency.Max != 50 {
+		t.Errorf("unexpected latency: %+v", report.Latency)
+	}
+	if report.Throughput != 5 {
+		t.Errorf("throughput = %v, want 5", report.Throughput)
+	}
+}
+
+func TestReportCheck(t *testing.T) {
+	report := func() *Report {
+		return &Report{
+			Requests:   100,
+			ErrorRate:  0.02,
+			Latency:    Latency{P95: 120, P99: 300},
+			Violations: []string{},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		report     *Report
+		budget     Budget
+		violations int
+	}{
+		{
+			name:   "within budget",
+			report: report(),
+			budget: Budget{P95: 200 * time.Millisecond, P99: 500 * time.Millisecond, MaxErrorRate: 0.05},
+		},
+		{
+			name:       "latency over budget",
+			report:     report(),
+			budget:     Budget{P95: 100 * time.Millisecond, P99: 250 * time.Millisecond, MaxErrorRate: 0.05},
+			violations: 2,
+		},
+		{
+			name:       "error rate over budget",
+			report:     report(),
+			budget:     Budget{MaxErrorRate: 0.01},
+			violations: 1,
+		},
+		{
+			name:       "no requests",
+			report:     &Report{Violations: []string{}},
+			budget:     Budget{MaxErrorRate: 1},
+			violations: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.report.Check(tt.budget)
+			if len(tt.report.Violations) != tt.violations {
+				t.Errorf("violations = %v, want %d", tt.report.Violations, tt.violations)
+			}
+			if tt.report.Passed != (tt.violations == 0) {
+				t.Errorf("passed = %v", tt.report.Passed)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		ceps = map[string]int{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			CEP string `json:"cep"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		ceps[body.CEP]++
+		mu.Unlock()
+
+		if body.CEP == "00000000" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Target:      server.URL,
+		Rate:        100,
+		Duration:    300 * time.Millisecond,
+		MaxInFlight: 10,
+		Timeout:     time.Second,
+		CEPs:        []string{"01310100", "00000000"},
+		Accept:      []int{http.StatusOK},
+	}
+
+	samples, dropped := Run(context.Background(), cfg, server.Client())
+
+	if dropped != 0 {
+		t.Errorf("dropped = %d, want 0", dropped)
+	}
+	if len(samples) < 10 || len(samples) > 31 {
+		t.Fatalf("unexpected number of samples: %d", len(samples))
+	}
+	if ceps["01310100"] == 0 || ceps["00000000"] == 0 {
+		t.Errorf("CEPs were not rotated: %v", ceps)
+	}
+
+	report := Summarize(cfg, samples, dropped, cfg.Duration)
+	if report.Successes != ceps["01310100"] || report.Errors != ceps["00000000"] {
+		t.Errorf("unexpected report counts: %+v (server saw %v)", report, ceps)
+	}
+}
+
+func TestRun_DropsWhenSaturated(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := Config{
+		Target:      server.URL,
+		Rate:        100,
+		Duration:    200 * time.Millisecond,
+		MaxInFlight: 1,
+		Timeout:     400 * time.Millisecond,
+		CEPs:        []string{"01310100"},
+		Accept:      []int{http.StatusOK},
+	}
+
+	samples, dropped := Run(context.Background(), cfg, server.Client())
+
+	if len(samples) != 1 {
+		t.Fatalf("samples = %d, want 1", len(samples))
+	}
+	if dropped == 0 {
+		t.Error("expected requests to be dropped while saturated")
+	}
+	if samples[0].Err == nil {
+		t.Error("expected the stalled request to time out")
+	}
+}
@@ -0,0 +1,158 @@
+package loadtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Latency holds latency statistics in milliseconds
+type Latency struct {
+	Min  float64 `json:"min_ms"`
+	Mean float64 `json:"mean_ms"`
+	P50  float64 `json:"p50_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+// Budget is the latency and error budget a run must stay within; zero latencies are not checked
+type Budget struct {
+	P95          time.Duration
+	P99          time.Duration
+	MaxErrorRate float64
+}
+
+// BudgetReport is the JSON form of a Budget
+type BudgetReport struct {
+	P95          float64 `json:"p95_ms,omitempty"`
+	P99          float64 `json:"p99_ms,omitempty"`
+	MaxErrorRate float64 `json:"max_error_rate"`
+}
+
+// Report is the JSON summary of a run
+type Report struct {
+	Target      string         `json:"target"`
+	Rate        int            `json:"rate"`
+	Duration    string         `json:"duration"`
+	Requests    int            `json:"requests"`
+	Successes   int            `json:"successes"`
+	Errors      int            `json:"errors"`
+	Dropped     int            `json:"dropped"`
+	ErrorRate   float64        `json:"error_rate"`
+	Throughput  float64        `json:"throughput_rps"`
+	Latency     Latency        `json:"latency"`
+	StatusCodes map[string]int `json:"status_codes"`
+	Budget      BudgetReport   `json:"budget"`
+	Violations  []string       `json:"violations"`
+	Passed      bool           `json:"passed"`
+}
+
+// Summarize builds the report of a run. Requests failing before a response or
+// answered with a status outside cfg.Accept count as errors; dropped requests
+// count as errors too, since the target could not keep up with the rate.
+func Summarize(cfg Config, samples []Sample, dropped int, elapsed time.Duration) *Report {
+	accepted := make(map[int]bool, len(cfg.Accept))
+	for _, code := range cfg.Accept {
+		accepted[code] = true
+	}
+
+	report := &Report{
+		Target:      cfg.Target,
+		Rate:        cfg.Rate,
+		Duration:    cfg.Duration.String(),
+		Requests:    len(samples),
+		Dropped:     dropped,
+		StatusCodes: map[string]int{},
+		Violations:  []string{},
+	}
+
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		latencies = append(latencies, s.Latency)
+		if s.Err != nil {
+			report.StatusCodes["error"]++
+			report.Errors++
+			continue
+		}
+		report.StatusCodes[strconv.Itoa(s.StatusCode)]++
+		if accepted[s.StatusCode] {
+			report.Successes++
+		} else {
+			report.Errors++
+		}
+	}
+
+	if total := len(samples) + dropped; total > 0 {
+		report.ErrorRate = float64(report.Errors+dropped) / float64(total)
+	}
+	if elapsed > 0 {
+		report.Throughput = round(float64(len(samples)) / elapsed.Seconds())
+	}
+	report.Latency = summarizeLatency(latencies)
+	return report
+}
+
+// Check compares the report against budget, recording violations and the verdict
+func (r *Report) Check(budget Budget) {
+	r.Budget = BudgetReport{
+		P95:          millis(budget.P95),
+		P99:          millis(budget.P99),
+		MaxErrorRate: budget.MaxErrorRate,
+	}
+
+	if budget.P95 > 0 && r.Latency.P95 > r.Budget.P95 {
+		r.Violations = append(r.Violations, fmt.Sprintf("p95 latency %.2fms exceeds budget %.2fms", r.Latency.P95, r.Budget.P95))
+	}
+	if budget.P99 > 0 && r.Latency.P99 > r.Budget.P99 {
+		r.Violations = append(r.Violations, fmt.Sprintf("p99 latency %.2fms exceeds budget %.2fms", r.Latency.P99, r.Budget.P99))
+	}
+	if r.ErrorRate > budget.MaxErrorRate {
+		r.Violations = append(r.Violations, fmt.Sprintf("error rate %.4f exceeds budget %.4f", r.ErrorRate, budget.MaxErrorRate))
+	}
+	if r.Requests == 0 {
+		r.Violations = append(r.Violations, "no requests completed")
+	}
+	r.Passed = len(r.Violations) == 0
+}
+
+// summarizeLatency computes nearest-rank percentiles over the latencies
+func summarizeLatency(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+
+	return Latency{
+		Min:  millis(latencies[0]),
+		Mean: millis(total / time.Duration(len(latencies))),
+		P50:  millis(percentile(latencies, 50)),
+		P95:  millis(percentile(latencies, 95)),
+		P99:  millis(percentile(latencies, 99)),
+		Max:  millis(latencies[len(latencies)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func millis(d time.Duration) float64 {
+	return round(float64(d) / float64(time.Millisecond))
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"otel/internal/domain"
+	"otel/pkg/httpclient"
+)
+
+// ProviderOpenMeteo identifies readings fetched from Open-Meteo
+const ProviderOpenMeteo = "open-meteo"
+
+// OpenMeteoRepository fetches current temperatures from Open-Meteo, a keyless
+// provider used as a fallback when the WeatherAPI quota runs low
+type OpenMeteoRepository struct {
+	mu           sync.RWMutex
+	client       *http.Client
+	geocodingURL string
+	forecastURL  string
+}
+
+// NewOpenMeteoRepository creates a new Open-Meteo repository
+func NewOpenMeteoRepository() *OpenMeteoRepository {
+	return &OpenMeteoRepository{
+		client:       httpclient.New(httpclient.WithTimeout(10 * time.Second)),
+		geocodingURL: "https://geocoding-api.open-meteo.com/v1/search",
+		forecastURL:  "https://api.open-meteo.com/v1/forecast",
+	}
+}
+
+// SetTimeout replaces the timeout used by subsequent requests
+func (r *OpenMeteoRepository) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client := *r.client
+	client.Timeout = timeout
+	r.client = &client
+}
+
+type openMeteoGeocoding struct {
+	Results []struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"results"`
+}
+
+type openMeteoForecast struct {
+	Current struct {
+		Temperature float64 `json:"temperature_2m"`
+	} `json:"current"`
+}
+
+// GetWeatherByLocation geocodes the "City,UF" location in Brazil and fetches its current temperature
+func (r *OpenMeteoRepository) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	r.mu.RLock()
+	client := r.client
+	r.mu.RUnlock()
+
+	city, _, _ := strings.Cut(location, ",")
+
+	var geocoding openMeteoGeocoding
+	query := url.Values{"name": {city}, "count": {"1"}, "language": {"pt"}, "countryCode": {"BR"}}
+	if err := r.getJSON(ctx, client, r.geocodingURL+"?"+query.Encode(), &geocoding); err != nil {
+		return nil, fmt.Errorf("failed to geocode location: %w", err)
+	}
+	if len(geocoding.Results) == 0 {
+		return nil, fmt.Errorf("open-meteo found no coordinates for location: %s", location)
+	}
+
+	coords := geocoding.Results[0]
+	var forecast openMeteoForecast
+	query = url.Values{
+		"latitude":  {fmt.Sprintf("%.4f", coords.Latitude)},
+		"longitude": {fmt.Sprintf("%.4f", coords.Longitude)},
+		"current":   {"temperature_2m"},
+	}
+	if err := r.getJSON(ctx, client, r.forecastURL+"?"+query.Encode(), &forecast); err != nil {
+		return nil, fmt.Errorf("failed to fetch weather data: %w", err)
+	}
+
+	var weatherResp domain.WeatherAPIResponse
+	weatherResp.Current.TempC = forecast.Current.Temperature
+	weatherResp.Provider = ProviderOpenMeteo
+	return &weatherResp, nil
+}
+
+func (r *OpenMeteoRepository) getJSON(ctx context.Context, client *http.Client, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("open-meteo returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
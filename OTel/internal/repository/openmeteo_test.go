@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenMeteoGetWeatherByLocation(t *testing.T) {
+	var geocodingQuery, forecastQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search":
+			geocodingQuery = r.URL.RawQuery
+			w.Write([]byte(`{"results":[{"latitude":-23.5475,"longitude":-46.63611}]}`))
+		case "/forecast":
+			forecastQuery = r.URL.RawQuery
+			w.Write([]byte(`{"current":{"temperature_2m":22.4}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo := NewOpenMeteoRepository()
+	repo.client = server.Client()
+	repo.geocodingURL = server.URL + "/search"
+	repo.forecastURL = server.URL + "/forecast"
+
+	resp, err := repo.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Current.TempC != 22.4 {
+		t.Errorf("Expected 22.4°C, got %v", resp.Current.TempC)
+	}
+	if !strings.Contains(geocodingQuery, "name=S%C3%A3o+Paulo") || !strings.Contains(geocodingQuery, "countryCode=BR") {
+		t.Errorf("Unexpected geocoding query: %s", geocodingQuery)
+	}
+	if !strings.Contains(forecastQuery, "latitude=-23.5475") || !strings.Contains(forecastQuery, "current=temperature_2m") {
+		t.Errorf("Unexpected forecast query: %s", forecastQuery)
+	}
+}
+
+func TestOpenMeteoGetWeatherByLocation_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		geocoding string
+		status    int
+	}{
+		{name: "location not found", geocoding: `{}`, status: http.StatusOK},
+		{name: "upstream error", geocoding: `{}`, status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.geocoding))
+			}))
+			defer server.Close()
+
+			repo := NewOpenMeteoRepository()
+			repo.client = server.Client()
+			repo.geocodingURL = server.URL + "/search"
+			repo.forecastURL = server.URL + "/forecast"
+
+			if _, err := repo.GetWeatherByLocation(context.Background(), "Lugar Nenhum,XX"); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -3,17 +3,23 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"otel/internal/domain"
-
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"otel/pkg/httpclient"
 )
 
+// ErrCEPNotFound is returned when ViaCEP has no address for the CEP
+var ErrCEPNotFound = errors.New("CEP not found")
+
 // ViaCEPRepository handles communication with ViaCEP API
 type ViaCEPRepository struct {
+	// client can be replaced at runtime; guarded by mu
+	mu      sync.RWMutex
 	client  *http.Client
 	baseURL string
 }
@@ -21,25 +27,37 @@ type ViaCEPRepository struct {
 // NewViaCEPRepository creates a new ViaCEP repository
 func NewViaCEPRepository() *ViaCEPRepository {
 	return &ViaCEPRepository{
-		client: &http.Client{
-			Transport: otelhttp.NewTransport(http.DefaultTransport),
-			Timeout:   10 * time.Second,
-		},
+		client:  httpclient.New(httpclient.WithTimeout(10 * time.Second)),
 		baseURL: "https://viacep.com.br/ws",
 	}
 }
 
+// SetTimeout replaces the timeout used by subsequent requests
+func (r *ViaCEPRepository) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client := *r.client
+	client.Timeout = timeout
+	r.client = &client
+}
+
+func (r *ViaCEPRepository) httpClient() *http.Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.client
+}
+
 // GetLocationByCEP fetches location data from ViaCEP API
-func (r *ViaCEPRepository) GetLocationByCEP(cep string) (*domain.ViaCEPResponse, error) {
+func (r *ViaCEPRepository) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
 	url := fmt.Sprintf("%s/%s/json/", r.baseURL, cep)
 
-	// Create request with context for tracing
-	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	// Create request with the caller's context so the client span joins its trace
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := r.client.Do(req)
+	resp, err := r.httpClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch location data: %w", err)
 	}
@@ -55,7 +73,7 @@ func (r *ViaCEPRepository) GetLocationByCEP(cep string) (*domain.ViaCEPResponse,
 	}
 
 	if viacepResp.Erro {
-		return nil, fmt.Errorf("CEP not found")
+		return nil, ErrCEPNotFound
 	}
 
 	return &viacepResp, nil
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,10 @@ import (
 	"testing"
 
 	"otel/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestNewViaCEPRepository(t *testing.T) {
@@ -50,7 +55,7 @@ func TestGetLocationByCEP_Success(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	result, err := repo.GetLocationByCEP("01310100")
+	result, err := repo.GetLocationByCEP(context.Background(), "01310100")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -88,7 +93,7 @@ func TestGetLocationByCEP_NotFound(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetLocationByCEP("99999999")
+	_, err := repo.GetLocationByCEP(context.Background(), "99999999")
 	if err == nil {
 		t.Fatal("Expected error for CEP not found")
 	}
@@ -111,7 +116,7 @@ func TestGetLocationByCEP_HTTPError(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetLocationByCEP("01310100")
+	_, err := repo.GetLocationByCEP(context.Background(), "01310100")
 	if err == nil {
 		t.Fatal("Expected error for HTTP 500 response")
 	}
@@ -134,7 +139,7 @@ func TestGetLocationByCEP_InvalidJSON(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetLocationByCEP("01310100")
+	_, err := repo.GetLocationByCEP(context.Background(), "01310100")
 	if err == nil {
 		t.Fatal("Expected error for invalid JSON response")
 	}
@@ -151,7 +156,7 @@ func TestGetLocationByCEP_NetworkError(t *testing.T) {
 		baseURL: "http://invalid-url-that-does-not-exist.local",
 	}
 
-	_, err := repo.GetLocationByCEP("01310100")
+	_, err := repo.GetLocationByCEP(context.Background(), "01310100")
 	if err == nil {
 		t.Fatal("Expected network error")
 	}
@@ -202,7 +207,7 @@ func TestGetLocationByCEP_URLConstruction(t *testing.T) {
 				baseURL: server.URL,
 			}
 
-			_, err := repo.GetLocationByCEP(tc.cep)
+			_, err := repo.GetLocationByCEP(context.Background(), tc.cep)
 			if err != nil {
 				t.Fatalf("Expected no error, got %v", err)
 			}
@@ -213,3 +218,34 @@ func TestGetLocationByCEP_URLConstruction(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLocationByCEP_PropagatesTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		json.NewEncoder(w).Encode(domain.ViaCEPResponse{CEP: "01310-100", Localidade: "São Paulo", UF: "SP"})
+	}))
+	defer server.Close()
+
+	repo := NewViaCEPRepository()
+	repo.baseURL = server.URL
+
+	if _, err := repo.GetLocationByCEP(ctx, "01310100"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(traceparent, traceID.String()) {
+		t.Errorf("Expected traceparent to carry trace %s, got %q", traceID, traceparent)
+	}
+}
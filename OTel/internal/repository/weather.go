@@ -6,49 +6,103 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"otel/internal/domain"
-
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"otel/pkg/httpclient"
 )
 
+// ProviderWeatherAPI identifies readings fetched from WeatherAPI
+const ProviderWeatherAPI = "weatherapi"
+
+// QuotaRemainingHeader carries the remaining monthly calls, when WeatherAPI
+// (or a proxy in front of it) reports them
+const QuotaRemainingHeader = "X-RateLimit-Remaining"
+
 // WeatherAPIRepository handles communication with Weather API
 type WeatherAPIRepository struct {
+	// client and apiKey can be replaced at runtime; guarded by mu
+	mu      sync.RWMutex
 	client  *http.Client
 	apiKey  string
 	baseURL string
+
+	// quotaRemaining is the last QuotaRemainingHeader seen, valid once quotaKnown is set
+	quotaRemaining atomic.Int64
+	quotaKnown     atomic.Bool
 }
 
 // NewWeatherAPIRepository creates a new Weather API repository
 func NewWeatherAPIRepository(apiKey string) *WeatherAPIRepository {
 	return &WeatherAPIRepository{
-		client: &http.Client{
-			Transport: otelhttp.NewTransport(http.DefaultTransport),
-			Timeout:   10 * time.Second,
-		},
+		client:  httpclient.New(httpclient.WithTimeout(10 * time.Second)),
 		apiKey:  apiKey,
 		baseURL: "https://api.weatherapi.com/v1",
 	}
 }
 
+// QuotaRemaining returns the remaining calls reported by the last response, if any
+func (r *WeatherAPIRepository) QuotaRemaining() (int64, bool) {
+	if !r.quotaKnown.Load() {
+		return 0, false
+	}
+	return r.quotaRemaining.Load(), true
+}
+
+// recordQuota keeps the remaining quota reported in header, if present
+func (r *WeatherAPIRepository) recordQuota(header http.Header) {
+	remaining, err := strconv.ParseInt(strings.TrimSpace(header.Get(QuotaRemainingHeader)), 10, 64)
+	if err == nil && remaining >= 0 {
+		r.quotaRemaining.Store(remaining)
+		r.quotaKnown.Store(true)
+	}
+}
+
+// SetAPIKey replaces the WeatherAPI key used by subsequent requests
+func (r *WeatherAPIRepository) SetAPIKey(apiKey string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiKey = apiKey
+}
+
+// SetTimeout replaces the timeout used by subsequent requests
+func (r *WeatherAPIRepository) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client := *r.client
+	client.Timeout = timeout
+	r.client = &client
+}
+
+func (r *WeatherAPIRepository) current() (*http.Client, string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.client, r.apiKey
+}
+
 // GetWeatherByLocation fetches weather data from Weather API
-func (r *WeatherAPIRepository) GetWeatherByLocation(location string) (*domain.WeatherAPIResponse, error) {
+func (r *WeatherAPIRepository) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
 	// URL encode the location to handle special characters
 	encodedLocation := url.QueryEscape(location)
-	url := fmt.Sprintf("%s/current.json?key=%s&q=%s&aqi=no", r.baseURL, r.apiKey, encodedLocation)
+	client, apiKey := r.current()
+	url := fmt.Sprintf("%s/current.json?key=%s&q=%s&aqi=no", r.baseURL, apiKey, encodedLocation)
 
-	// Create request with context for tracing
-	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	// Create request with the caller's context so the client span joins its trace
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := r.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch weather data: %w", err)
 	}
 	defer resp.Body.Close()
+	r.recordQuota(resp.Header)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("weather API returned status %d for location: %s", resp.StatusCode, location)
@@ -58,6 +112,52 @@ func (r *WeatherAPIRepository) GetWeatherByLocation(location string) (*domain.We
 	if err := json.NewDecoder(resp.Body).Decode(&weatherResp); err != nil {
 		return nil, fmt.Errorf("failed to decode weather response: %w", err)
 	}
+	weatherResp.Provider = ProviderWeatherAPI
 
 	return &weatherResp, nil
 }
+
+// GetAlertsByLocation fetches the official weather alerts for a location from Weather API
+func (r *WeatherAPIRepository) GetAlertsByLocation(ctx context.Context, location string) ([]domain.WeatherAlert, error) {
+	client, apiKey := r.current()
+	url := fmt.Sprintf("%s/forecast.json?key=%s&q=%s&days=1&aqi=no&alerts=yes", r.baseURL, apiKey, url.QueryEscape(location))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weather alerts: %w", err)
+	}
+	defer resp.Body.Close()
+	r.recordQuota(resp.Header)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weather API returned status %d for location: %s", resp.StatusCode, location)
+	}
+
+	var alertsResp domain.WeatherAPIAlertsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&alertsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode weather alerts: %w", err)
+	}
+
+	alerts := make([]domain.WeatherAlert, 0, len(alertsResp.Alerts.Alert))
+	for _, a := range alertsResp.Alerts.Alert {
+		alerts = append(alerts, domain.WeatherAlert{
+			Headline:    a.Headline,
+			Event:       a.Event,
+			Severity:    a.Severity,
+			Urgency:     a.Urgency,
+			Certainty:   a.Certainty,
+			Category:    a.Category,
+			Areas:       a.Areas,
+			Description: a.Desc,
+			Instruction: a.Instruction,
+			Effective:   a.Effective,
+			Expires:     a.Expires,
+		})
+	}
+	return alerts, nil
+}
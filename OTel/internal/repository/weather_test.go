@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"otel/internal/domain"
 )
@@ -57,7 +59,7 @@ func TestGetWeatherByLocation_URLEncoding(t *testing.T) {
 
 	// Test with location containing special characters (São Paulo)
 	location := "São Paulo,SP"
-	_, err := repo.GetWeatherByLocation(location)
+	_, err := repo.GetWeatherByLocation(context.Background(), location)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -104,7 +106,7 @@ func TestGetWeatherByLocation_Success(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	result, err := repo.GetWeatherByLocation("Test Location")
+	result, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -128,7 +130,7 @@ func TestGetWeatherByLocation_HTTPError(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetWeatherByLocation("Test Location")
+	_, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
 	if err == nil {
 		t.Fatal("Expected error for HTTP 401 response")
 	}
@@ -156,7 +158,7 @@ func TestGetWeatherByLocation_InvalidJSON(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetWeatherByLocation("Test Location")
+	_, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
 	if err == nil {
 		t.Fatal("Expected error for invalid JSON response")
 	}
@@ -174,7 +176,7 @@ func TestGetWeatherByLocation_NetworkError(t *testing.T) {
 		baseURL: "http://invalid-url-that-does-not-exist.local",
 	}
 
-	_, err := repo.GetWeatherByLocation("Test Location")
+	_, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
 	if err == nil {
 		t.Fatal("Expected network error")
 	}
@@ -251,7 +253,7 @@ func TestGetWeatherByLocation_SpecialCharactersEncoding(t *testing.T) {
 				baseURL: server.URL,
 			}
 
-			_, err := repo.GetWeatherByLocation(tc.location)
+			_, err := repo.GetWeatherByLocation(context.Background(), tc.location)
 			if err != nil {
 				t.Fatalf("Expected no error, got %v", err)
 			}
@@ -262,3 +264,119 @@ func TestGetWeatherByLocation_SpecialCharactersEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestWeatherAPIRepository_SetAPIKey(t *testing.T) {
+	var capturedKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedKey = r.URL.Query().Get("key")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"current":{"temp_c":20.0}}`))
+	}))
+	defer server.Close()
+
+	repo := &WeatherAPIRepository{
+		client:  &http.Client{},
+		apiKey:  "old_key",
+		baseURL: server.URL,
+	}
+
+	repo.SetAPIKey("new_key")
+	repo.SetTimeout(5 * time.Second)
+
+	if _, err := repo.GetWeatherByLocation(context.Background(), "Recife,PE"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if capturedKey != "new_key" {
+		t.Errorf("Expected request to use the new API key, got %q", capturedKey)
+	}
+	if repo.client.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout to be 5s, got %v", repo.client.Timeout)
+	}
+}
+
+func TestGetAlertsByLocation(t *testing.T) {
+	var capturedQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedQuery = r.URL.RawQuery
+		if r.URL.Path != "/forecast.json" {
+			t.Errorf("Expected /forecast.json, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"current": {"temp_c": 21.0},
+			"alerts": {"alert": [{
+				"headline": "Alerta de chuvas intensas",
+				"severity": "Severe",
+				"urgency": "Immediate",
+				"areas": "Grande São Paulo",
+				"category": "Met",
+				"certainty": "Observed",
+				"event": "Chuvas Intensas",
+				"effective": "2025-01-10T12:00:00-03:00",
+				"expires": "2025-01-10T23:59:00-03:00",
+				"desc": "Chuva superior a 60 mm/h",
+				"instruction": "Evite áreas alagadas"
+			}]}
+		}`))
+	}))
+	defer server.Close()
+
+	repo := &WeatherAPIRepository{client: &http.Client{}, apiKey: "test_key", baseURL: server.URL}
+
+	alerts, err := repo.GetAlertsByLocation(context.Background(), "São Paulo,SP")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(capturedQuery, "alerts=yes") || !strings.Contains(capturedQuery, "q=S%C3%A3o+Paulo%2CSP") {
+		t.Errorf("Unexpected query: %s", capturedQuery)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts))
+	}
+	alert := alerts[0]
+	if alert.Event != "Chuvas Intensas" || alert.Severity != "Severe" || alert.Description != "Chuva superior a 60 mm/h" ||
+		alert.Expires != "2025-01-10T23:59:00-03:00" {
+		t.Errorf("Unexpected alert: %+v", alert)
+	}
+}
+
+func TestGetAlertsByLocation_NoAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"alerts": {"alert": []}}`))
+	}))
+	defer server.Close()
+
+	repo := &WeatherAPIRepository{client: &http.Client{}, apiKey: "test_key", baseURL: server.URL}
+
+	alerts, err := repo.GetAlertsByLocation(context.Background(), "Curitiba,PR")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if alerts == nil || len(alerts) != 0 {
+		t.Errorf("Expected empty non-nil alerts, got %#v", alerts)
+	}
+}
+
+func TestGetWeatherByLocation_RecordsReportedQuota(t *testing.T) {
+	remaining := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if remaining != "" {
+			w.Header().Set(QuotaRemainingHeader, remaining)
+		}
+		w.Write([]byte(`{"current": {"temp_c": 20}}`))
+	}))
+	defer server.Close()
+
+	repo := &WeatherAPIRepository{client: &http.Client{}, apiKey: "test_key", baseURL: server.URL}
+
+	repo.GetWeatherByLocation(context.Background(), "Curitiba,PR")
+	if _, ok := repo.QuotaRemaining(); ok {
+		t.Errorf("Expected no reported quota without the header")
+	}
+
+	remaining = "42"
+	repo.GetWeatherByLocation(context.Background(), "Curitiba,PR")
+	if value, ok := repo.QuotaRemaining(); !ok || value != 42 {
+		t.Errorf("Expected 42 remaining calls, got %d, %v", value, ok)
+	}
+}
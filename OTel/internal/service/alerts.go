@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"otel/internal/domain"
+	"otel/pkg/cityname"
+	"otel/pkg/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AlertService looks up the official weather alerts for a CEP
+type AlertService struct {
+	locationRepo domain.LocationService
+	alertsRepo   domain.AlertDataService
+	tracer       trace.Tracer
+	cities       *cityQueries
+}
+
+// NewAlertService creates a new alert service
+func NewAlertService(locationRepo domain.LocationService, alertsRepo domain.AlertDataService) *AlertService {
+	log.Printf("[ORCHESTRATOR] Initializing alert service")
+	return &AlertService{
+		locationRepo: locationRepo,
+		alertsRepo:   alertsRepo,
+		tracer:       telemetry.GetTracer("weather-service"),
+		cities:       newCityQueries(cityname.New(cityname.Options{})),
+	}
+}
+
+// WithCityNormalizer replaces how city names are rewritten before querying WeatherAPI
+func (s *AlertService) WithCityNormalizer(normalizer *cityname.Normalizer) *AlertService {
+	s.cities = newCityQueries(normalizer)
+	return s
+}
+
+// GetAlertsByCEP returns the alerts in force for the city of a CEP
+func (s *AlertService) GetAlertsByCEP(ctx context.Context, cep string) (*domain.AlertsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "alert_service.get_alerts_by_cep")
+	defer span.End()
+	span.SetAttributes(attribute.String("cep.input", cep))
+
+	location, err := s.locationRepo.GetLocationByCEP(ctx, cep)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Error fetching location for CEP %s: %v", cep, err)
+		span.SetStatus(codes.Error, "Failed to fetch location")
+		span.RecordError(err)
+		return nil, ErrCEPNotFound
+	}
+
+	alertsCtx, alertsSpan := s.tracer.Start(ctx, "alert_service.get_alerts_by_location")
+	locationQuery := s.cities.query(ctx, alertsSpan, location)
+	alertsSpan.SetAttributes(attribute.String("weather.location_query", locationQuery))
+
+	alerts, err := s.alertsRepo.GetAlertsByLocation(alertsCtx, locationQuery)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Error fetching alerts for location %s: %v", locationQuery, err)
+		alertsSpan.SetStatus(codes.Error, "Failed to fetch weather alerts")
+		alertsSpan.RecordError(err)
+		alertsSpan.End()
+		span.SetStatus(codes.Error, "Failed to fetch weather alerts")
+		span.RecordError(err)
+		return nil, ErrAlertsUnavailable
+	}
+	alertsSpan.SetAttributes(attribute.Int("weather.alerts.count", len(alerts)))
+	alertsSpan.SetStatus(codes.Ok, "Weather alerts fetched successfully")
+	alertsSpan.End()
+
+	log.Printf("[ORCHESTRATOR] %d weather alerts in force for %s", len(alerts), locationQuery)
+	span.SetStatus(codes.Ok, "Alert service completed successfully")
+	return &domain.AlertsResponse{City: location.Localidade, Alerts: alerts}, nil
+}
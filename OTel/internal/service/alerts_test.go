@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"otel/internal/domain"
+)
+
+// MockAlertsRepo returns fixed alerts per location
+type MockAlertsRepo struct {
+	err error
+}
+
+func (m *MockAlertsRepo) GetAlertsByLocation(ctx context.Context, location string) ([]domain.WeatherAlert, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if location == "Rio de Janeiro,RJ" {
+		return []domain.WeatherAlert{{Event: "Tempestade", Severity: "Severe"}}, nil
+	}
+	return []domain.WeatherAlert{}, nil
+}
+
+func TestAlertService_GetAlertsByCEP(t *testing.T) {
+	service := NewAlertService(&MockLocationRepo{}, &MockAlertsRepo{})
+
+	resp, err := service.GetAlertsByCEP(context.Background(), "20040020")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.City != "Rio de Janeiro" || len(resp.Alerts) != 1 || resp.Alerts[0].Event != "Tempestade" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+
+	resp, err = service.GetAlertsByCEP(context.Background(), "01310100")
+	if err != nil || len(resp.Alerts) != 0 {
+		t.Errorf("Expected no alerts, got %+v, %v", resp, err)
+	}
+}
+
+func TestAlertService_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cep     string
+		repoErr error
+		want    error
+	}{
+		{name: "cep not found", cep: "99999999", want: ErrCEPNotFound},
+		{name: "provider failure", cep: "01310100", repoErr: errors.New("status 500"), want: ErrAlertsUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAlertService(&MockLocationRepo{}, &MockAlertsRepo{err: tt.repoErr})
+			if _, err := service.GetAlertsByCEP(context.Background(), tt.cep); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"otel/internal/domain"
+	"otel/pkg/temperature"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Anomaly outcomes recorded in the anomaly counter
+const (
+	anomalyActionPassed       = "passed"
+	anomalyActionServedCached = "served_cached"
+	anomalyActionRejected     = "rejected"
+)
+
+// AnomalyPolicy defines which upstream temperatures are plausible and what to
+// do with the ones that are not
+type AnomalyPolicy struct {
+	MinTempC float64
+	MaxTempC float64
+	// Reject answers anomalous readings with the last plausible reading for the
+	// location, or ErrAnomalousReading when none is cached; otherwise they are
+	// only counted and tagged
+	Reject bool
+	// CacheMaxAge bounds how old a cached reading may be to replace an anomaly
+	CacheMaxAge time.Duration
+}
+
+// DefaultAnomalyPolicy counts readings outside the plausible range without rejecting them
+func DefaultAnomalyPolicy() AnomalyPolicy {
+	return AnomalyPolicy{
+		MinTempC:    temperature.MinPlausibleCelsius,
+		MaxTempC:    temperature.MaxPlausibleCelsius,
+		CacheMaxAge: time.Hour,
+	}
+}
+
+type cachedReading struct {
+	response domain.WeatherResponse
+	storedAt time.Time
+}
+
+// anomalyDetector checks upstream readings and keeps the last plausible one
+// per location to fall back on
+type anomalyDetector struct {
+	policy  AnomalyPolicy
+	counter metric.Int64Counter
+
+	mu       sync.Mutex
+	lastGood map[string]cachedReading
+}
+
+func newAnomalyDetector(policy AnomalyPolicy) *anomalyDetector {
+	counter, err := otel.Meter("weather-service").Int64Counter(
+		"weather.upstream.anomalies",
+		metric.WithDescription("Upstream temperature readings outside the plausible range"),
+		metric.WithUnit("{reading}"),
+	)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Failed to create anomaly counter: %v", err)
+	}
+	return &anomalyDetector{
+		policy:   policy,
+		counter:  counter,
+		lastGood: make(map[string]cachedReading),
+	}
+}
+
+// check returns the anomaly reason for tempC, tagging span and counting it when anomalous
+func (d *anomalyDetector) check(span trace.Span, location string, tempC float64) string {
+	reason := temperature.CheckCelsius(tempC, d.policy.MinTempC, d.policy.MaxTempC)
+	span.SetAttributes(attribute.Bool("weather.anomaly", reason != temperature.AnomalyNone))
+	if reason == temperature.AnomalyNone {
+		return reason
+	}
+
+	log.Printf("[ORCHESTRATOR] Anomalous temperature from upstream - location=%q temp_c=%v reason=%s",
+		location, tempC, reason)
+	span.SetAttributes(attribute.String("weather.anomaly.reason", reason))
+	span.AddEvent("temperature_anomaly", trace.WithAttributes(
+		attribute.String("weather.location_query", location),
+		attribute.Float64("weather.temp_c_raw", tempC),
+		attribute.String("weather.anomaly.reason", reason),
+	))
+	return reason
+}
+
+// record increments the anomaly counter with the reason and the action taken
+func (d *anomalyDetector) record(ctx context.Context, reason, action string) {
+	if d.counter == nil {
+		return
+	}
+	d.counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("reason", reason),
+		attribute.String("action", action),
+	))
+}
+
+func (d *anomalyDetector) remember(location string, response domain.WeatherResponse) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastGood[location] = cachedReading{response: response, storedAt: time.Now()}
+}
+
+func (d *anomalyDetector) cached(location string) (*domain.WeatherResponse, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	reading, ok := d.lastGood[location]
+	if !ok || time.Since(reading.storedAt) > d.policy.CacheMaxAge {
+		return nil, false
+	}
+	response := reading.response
+	response.Stale = true
+	response.Provider = providerCache
+	return &response, true
+}
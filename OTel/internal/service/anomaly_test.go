@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"otel/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// sequenceWeatherRepo returns the given temperatures in order
+type sequenceWeatherRepo struct {
+	temps []float64
+}
+
+func (m *sequenceWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	resp := &domain.WeatherAPIResponse{}
+	resp.Current.TempC = m.temps[0]
+	m.temps = m.temps[1:]
+	return resp, nil
+}
+
+func setupMeter(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	previous := otel.GetMeterProvider()
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+	return reader
+}
+
+// anomalyCount sums the anomaly counter for the given action
+func anomalyCount(t *testing.T, reader *sdkmetric.ManualReader, action string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Failed to collect metrics: %v", err)
+	}
+
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "weather.upstream.anomalies" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if v, _ := dp.Attributes.Value(attribute.Key("action")); v.AsString() == action {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	return total
+}
+
+func TestWeatherService_Anomaly_PassedThroughByDefault(t *testing.T) {
+	reader := setupMeter(t)
+	service := NewWeatherService(&MockLocationRepo{}, &sequenceWeatherRepo{temps: []float64{298}})
+
+	result, err := service.GetWeatherByCEP(context.TODO(), "01310100")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.TempC != 298 || result.Stale {
+		t.Errorf("Expected the raw reading to pass through, got %+v", result)
+	}
+	if got := anomalyCount(t, reader, anomalyActionPassed); got != 1 {
+		t.Errorf("Expected 1 passed anomaly, got %d", got)
+	}
+}
+
+func TestWeatherService_Anomaly_RejectedWithoutCache(t *testing.T) {
+	reader := setupMeter(t)
+	policy := DefaultAnomalyPolicy()
+	policy.Reject = true
+	service := NewWeatherService(&MockLocationRepo{}, &sequenceWeatherRepo{temps: []float64{-150}}).
+		WithAnomalyPolicy(policy)
+
+	_, err := service.GetWeatherByCEP(context.TODO(), "01310100")
+	if !errors.Is(err, ErrAnomalousReading) {
+		t.Errorf("Expected ErrAnomalousReading, got %v", err)
+	}
+	if got := anomalyCount(t, reader, anomalyActionRejected); got != 1 {
+		t.Errorf("Expected 1 rejected anomaly, got %d", got)
+	}
+}
+
+func TestWeatherService_Anomaly_ServesCachedReading(t *testing.T) {
+	reader := setupMeter(t)
+	policy := DefaultAnomalyPolicy()
+	policy.Reject = true
+	service := NewWeatherService(&MockLocationRepo{}, &sequenceWeatherRepo{temps: []float64{24, 500}}).
+		WithAnomalyPolicy(policy)
+
+	if _, err := service.GetWeatherByCEP(context.TODO(), "01310100"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result, err := service.GetWeatherByCEP(context.TODO(), "01310100")
+	if err != nil {
+		t.Fatalf("Expected cached reading, got %v", err)
+	}
+	if result.TempC != 24 || !result.Stale {
+		t.Errorf("Expected stale cached reading of 24°C, got %+v", result)
+	}
+	if got := anomalyCount(t, reader, anomalyActionServedCached); got != 1 {
+		t.Errorf("Expected 1 served_cached anomaly, got %d", got)
+	}
+}
+
+func TestWeatherService_Anomaly_ExpiredCacheIsNotServed(t *testing.T) {
+	setupMeter(t)
+	policy := DefaultAnomalyPolicy()
+	policy.Reject = true
+	policy.CacheMaxAge = time.Nanosecond
+	service := NewWeatherService(&MockLocationRepo{}, &sequenceWeatherRepo{temps: []float64{24, 500}}).
+		WithAnomalyPolicy(policy)
+
+	if _, err := service.GetWeatherByCEP(context.TODO(), "01310100"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	if _, err := service.GetWeatherByCEP(context.TODO(), "01310100"); !errors.Is(err, ErrAnomalousReading) {
+		t.Errorf("Expected ErrAnomalousReading, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"otel/internal/domain"
+	"otel/pkg/cityname"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// cityQueries builds WeatherAPI queries from ViaCEP locations and counts the rewrites
+type cityQueries struct {
+	normalizer *cityname.Normalizer
+	rewrites   metric.Int64Counter
+}
+
+func newCityQueries(normalizer *cityname.Normalizer) *cityQueries {
+	rewrites, err := otel.Meter("weather-service").Int64Counter(
+		"weather.location_query.rewrites",
+		metric.WithDescription("WeatherAPI location queries rewritten by the city-name normalizer, by rule"),
+		metric.WithUnit("{query}"),
+	)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Failed to create location query rewrite counter: %v", err)
+	}
+	return &cityQueries{normalizer: normalizer, rewrites: rewrites}
+}
+
+// query returns the WeatherAPI query for location, tagging span and counting
+// each rule applied when it differs from the plain "City,UF" form
+func (q *cityQueries) query(ctx context.Context, span trace.Span, location *domain.ViaCEPResponse) string {
+	query, rules := q.normalizer.Query(location.Localidade, location.UF)
+	if len(rules) == 0 {
+		return query
+	}
+
+	original := fmt.Sprintf("%s,%s", location.Localidade, location.UF)
+	log.Printf("[ORCHESTRATOR] Location query rewritten - original=%q query=%q rules=%s",
+		original, query, strings.Join(rules, ","))
+	span.SetAttributes(
+		attribute.String("weather.location_query.original", original),
+		attribute.StringSlice("weather.location_query.rules", rules),
+	)
+	if q.rewrites != nil {
+		for _, rule := range rules {
+			q.rewrites.Add(ctx, 1, metric.WithAttributes(attribute.String("rule", rule)))
+		}
+	}
+	return query
+}
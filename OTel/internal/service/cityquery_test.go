@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"otel/internal/domain"
+	"otel/pkg/cityname"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// recordingWeatherRepo keeps the location queries it receives
+type recordingWeatherRepo struct {
+	queries []string
+}
+
+func (m *recordingWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	m.queries = append(m.queries, location)
+	resp := &domain.WeatherAPIResponse{}
+	resp.Current.TempC = 20
+	return resp, nil
+}
+
+// rewriteCount sums the location query rewrite counter for the given rule
+func rewriteCount(t *testing.T, reader *sdkmetric.ManualReader, rule string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Failed to collect metrics: %v", err)
+	}
+
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "weather.location_query.rewrites" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if v, _ := dp.Attributes.Value(attribute.Key("rule")); v.AsString() == rule {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	return total
+}
+
+func TestWeatherService_CityNormalizer_RewritesQuery(t *testing.T) {
+	reader := setupMeter(t)
+	weatherRepo := &recordingWeatherRepo{}
+	service := NewWeatherService(&MockLocationRepo{}, weatherRepo).
+		WithCityNormalizer(cityname.New(cityname.Options{StripAccents: true, DisambiguateState: true}))
+
+	if _, err := service.GetWeatherByCEP(context.TODO(), "01310100"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(weatherRepo.queries) != 1 || weatherRepo.queries[0] != "Sao Paulo,Sao Paulo,Brazil" {
+		t.Errorf("Expected normalized query, got %v", weatherRepo.queries)
+	}
+	if got := rewriteCount(t, reader, cityname.RuleStripAccents); got != 1 {
+		t.Errorf("Expected 1 strip_accents rewrite, got %d", got)
+	}
+	if got := rewriteCount(t, reader, cityname.RuleState); got != 1 {
+		t.Errorf("Expected 1 state rewrite, got %d", got)
+	}
+}
+
+func TestWeatherService_CityNormalizer_DefaultKeepsQuery(t *testing.T) {
+	reader := setupMeter(t)
+	weatherRepo := &recordingWeatherRepo{}
+	service := NewWeatherService(&MockLocationRepo{}, weatherRepo)
+
+	if _, err := service.GetWeatherByCEP(context.TODO(), "01310100"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(weatherRepo.queries) != 1 || weatherRepo.queries[0] != "São Paulo,SP" {
+		t.Errorf("Expected plain City,UF query, got %v", weatherRepo.queries)
+	}
+	if got := rewriteCount(t, reader, cityname.RuleStripAccents); got != 0 {
+		t.Errorf("Expected no rewrites, got %d", got)
+	}
+}
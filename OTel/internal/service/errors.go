@@ -12,4 +12,25 @@ var (
 
 	// ErrWeatherDataUnavailable is returned when weather data cannot be retrieved
 	ErrWeatherDataUnavailable = errors.New("error fetching weather data")
+
+	// ErrAnomalousReading is returned when the upstream temperature is implausible
+	// and no cached reading can replace it
+	ErrAnomalousReading = errors.New("upstream returned an implausible temperature")
+
+	// ErrQuotaExhausted is returned when the monthly or daily WeatherAPI quota is
+	// used up and neither a cached reading nor the fallback provider can answer
+	ErrQuotaExhausted = errors.New("weather API quota exhausted")
+
+	// ErrQuotaThrottled is returned when a WeatherAPI call past the quota threshold
+	// comes sooner than the throttle allows
+	ErrQuotaThrottled = errors.New("weather API calls throttled to preserve the quota")
+
+	// ErrUnknownProvider is returned when the pinned weather provider is not configured
+	ErrUnknownProvider = errors.New("unknown weather provider")
+
+	// ErrAlertsUnsupported is returned when the weather provider cannot serve alerts
+	ErrAlertsUnsupported = errors.New("weather provider does not support alerts")
+
+	// ErrAlertsUnavailable is returned when weather alerts cannot be retrieved
+	ErrAlertsUnavailable = errors.New("error fetching weather alerts")
 )
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"otel/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// providerDefault labels metrics of lookups that failed without a pinned provider
+const providerDefault = "default"
+
+// providerKey is the context key of the provider pinned by the client
+type providerKey struct{}
+
+// WithProvider pins the weather lookups made with ctx to the named provider
+func WithProvider(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, providerKey{}, name)
+}
+
+// ProviderFromContext returns the pinned provider, or "" for the default selection
+func ProviderFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(providerKey{}).(string)
+	return name
+}
+
+// ProviderSelector sends weather lookups to the provider pinned in the context,
+// or to the default chain (quota, cache and fallback) otherwise, and measures
+// requests and latency per provider
+type ProviderSelector struct {
+	defaultRepo domain.WeatherDataService
+	providers   map[string]domain.WeatherDataService
+	requests    metric.Int64Counter
+	duration    metric.Float64Histogram
+}
+
+// NewProviderSelector routes unpinned lookups to defaultRepo and pinned ones to providers
+func NewProviderSelector(defaultRepo domain.WeatherDataService, providers map[string]domain.WeatherDataService) *ProviderSelector {
+	meter := otel.Meter("weather-service")
+	requests, err := meter.Int64Counter(
+		"weather.provider.requests",
+		metric.WithDescription("Weather lookups by provider, selection mode and outcome"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Failed to create provider counter: %v", err)
+	}
+	duration, err := meter.Float64Histogram(
+		"weather.provider.duration",
+		metric.WithDescription("Weather lookup latency by provider"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Failed to create provider latency histogram: %v", err)
+	}
+
+	return &ProviderSelector{
+		defaultRepo: defaultRepo,
+		providers:   providers,
+		requests:    requests,
+		duration:    duration,
+	}
+}
+
+// Supports reports whether clients may pin the named provider
+func (s *ProviderSelector) Supports(name string) bool {
+	_, ok := s.providers[name]
+	return ok
+}
+
+// Providers returns the names clients may pin, sorted
+func (s *ProviderSelector) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetWeatherByLocation fetches the reading from the pinned provider, or from the default chain
+func (s *ProviderSelector) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	repo := s.defaultRepo
+	pinned := ProviderFromContext(ctx)
+	if pinned != "" {
+		var ok bool
+		if repo, ok = s.providers[pinned]; !ok {
+			return nil, ErrUnknownProvider
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("weather.provider.pinned", pinned))
+	}
+
+	start := time.Now()
+	weather, err := repo.GetWeatherByLocation(ctx, location)
+	elapsed := time.Since(start)
+
+	// Unpinned lookups are attributed to whoever answered them
+	provider := pinned
+	if provider == "" {
+		provider = providerDefault
+		if err == nil && weather.Provider != "" {
+			provider = weather.Provider
+		}
+	}
+	s.record(ctx, provider, pinned != "", err, elapsed)
+	return weather, err
+}
+
+func (s *ProviderSelector) record(ctx context.Context, provider string, pinned bool, err error, elapsed time.Duration) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("provider", provider),
+		attribute.Bool("pinned", pinned),
+		attribute.String("outcome", outcome),
+	)
+	if s.requests != nil {
+		s.requests.Add(ctx, 1, attrs)
+	}
+	if s.duration != nil {
+		s.duration.Record(ctx, float64(elapsed.Microseconds())/1000, attrs)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"otel/internal/domain"
+)
+
+func TestProviderSelector_Routing(t *testing.T) {
+	defaultRepo := &countingWeatherRepo{tempC: 20}
+	openMeteo := &countingWeatherRepo{tempC: 22}
+	selector := NewProviderSelector(defaultRepo, map[string]domain.WeatherDataService{
+		"open-meteo": openMeteo,
+	})
+
+	resp, err := selector.GetWeatherByLocation(context.Background(), "A,SP")
+	if err != nil || resp.Current.TempC != 20 {
+		t.Fatalf("Expected default reading, got %v, %v", resp, err)
+	}
+
+	ctx := WithProvider(context.Background(), "open-meteo")
+	resp, err = selector.GetWeatherByLocation(ctx, "A,SP")
+	if err != nil || resp.Current.TempC != 22 {
+		t.Fatalf("Expected pinned reading, got %v, %v", resp, err)
+	}
+
+	if defaultRepo.calls != 1 || openMeteo.calls != 1 {
+		t.Errorf("Expected 1 call each, got %d and %d", defaultRepo.calls, openMeteo.calls)
+	}
+}
+
+func TestProviderSelector_UnknownProvider(t *testing.T) {
+	selector := NewProviderSelector(&countingWeatherRepo{}, map[string]domain.WeatherDataService{
+		"weatherapi": &countingWeatherRepo{},
+		"open-meteo": &countingWeatherRepo{},
+	})
+
+	ctx := WithProvider(context.Background(), "openweather")
+	if _, err := selector.GetWeatherByLocation(ctx, "A,SP"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("Expected ErrUnknownProvider, got %v", err)
+	}
+	if selector.Supports("openweather") || !selector.Supports("weatherapi") {
+		t.Error("Unexpected Supports result")
+	}
+	if got := selector.Providers(); !reflect.DeepEqual(got, []string{"open-meteo", "weatherapi"}) {
+		t.Errorf("Unexpected providers: %v", got)
+	}
+}
@@ -0,0 +1,486 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"otel/internal/domain"
+	"otel/pkg/featureflag"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Providers reported on the span and in QuotaStats
+const (
+	providerPrimary  = "weatherapi"
+	providerCache    = "cache"
+	providerFallback = "fallback"
+)
+
+// Budget states reported in QuotaStats.State and on /health/ready
+const (
+	QuotaStateOK         = "ok"
+	QuotaStateConserving = "conserving"
+	QuotaStateExhausted  = "exhausted"
+)
+
+// QuotaReporter is implemented by providers that learn the remaining quota
+// from their responses; the guard trusts it when it is lower than its own count
+type QuotaReporter interface {
+	// QuotaRemaining returns the remaining monthly calls reported by the last response
+	QuotaRemaining() (int64, bool)
+}
+
+// QuotaPolicy bounds WeatherAPI usage per calendar month and day (UTC)
+type QuotaPolicy struct {
+	// MonthlyQuota is the number of WeatherAPI calls allowed per month (0 only counts calls)
+	MonthlyQuota int64
+	// DailyQuota is the number of WeatherAPI calls allowed per day (0 disables the daily limit)
+	DailyQuota int64
+	// Threshold is the fraction of MonthlyQuota or DailyQuota after which cached
+	// readings and the fallback provider are preferred over WeatherAPI
+	Threshold float64
+	// Throttle spaces WeatherAPI calls past the threshold so the remaining
+	// budget lasts until it resets, rejecting calls that come too soon
+	Throttle bool
+	// CostPerCall is the price of one WeatherAPI call, used for cost estimates
+	CostPerCall float64
+	// CacheMaxAge bounds how old a cached reading may be to replace a WeatherAPI call
+	CacheMaxAge time.Duration
+}
+
+// QuotaStats is the usage snapshot published on the stats endpoint.
+// Remaining, DailyRemaining and ReportedRemaining are -1 when unknown.
+type QuotaStats struct {
+	Month              string  `json:"month"`
+	Calls              int64   `json:"calls"`
+	MonthlyQuota       int64   `json:"monthly_quota"`
+	Remaining          int64   `json:"remaining"`
+	DailyCalls         int64   `json:"daily_calls"`
+	DailyQuota         int64   `json:"daily_quota"`
+	DailyRemaining     int64   `json:"daily_remaining"`
+	ReportedRemaining  int64   `json:"reported_remaining"`
+	Threshold          float64 `json:"threshold"`
+	Conserving         bool    `json:"conserving"`
+	State              string  `json:"state"`
+	FallbackAvailable  bool    `json:"fallback_available"`
+	EstimatedCost      float64 `json:"estimated_cost"`
+	ProjectedCalls     int64   `json:"projected_calls"`
+	ProjectedRemaining int64   `json:"projected_remaining"`
+	ServedByPrimary    int64   `json:"served_by_weatherapi"`
+	ServedByCache      int64   `json:"served_by_cache"`
+	ServedByFallback   int64   `json:"served_by_fallback"`
+	Rejected           int64   `json:"rejected"`
+	Throttled          int64   `json:"throttled"`
+}
+
+// QuotaGuard counts WeatherAPI calls against monthly and daily quotas. Past
+// the threshold it answers from recent readings or the fallback provider,
+// optionally throttles WeatherAPI, and stops calling it once a quota is used up.
+type QuotaGuard struct {
+	primary  domain.WeatherDataService
+	fallback domain.WeatherDataService
+	policy   QuotaPolicy
+	flags    *featureflag.Flags
+	now      func() time.Time
+
+	mu          sync.Mutex
+	month       time.Time
+	day         time.Time
+	calls       int64
+	dailyCalls  int64
+	reported    int64
+	lastPrimary time.Time
+	served      map[string]int64
+	rejected    int64
+	throttled   int64
+	conserving  bool
+	readings    map[string]cachedWeather
+}
+
+// maxCachedReadings bounds how many cities the guard keeps a reading for
+const maxCachedReadings = 1000
+
+type cachedWeather struct {
+	weather  domain.WeatherAPIResponse
+	storedAt time.Time
+}
+
+// NewQuotaGuard wraps primary with quota accounting; fallback may be nil
+func NewQuotaGuard(primary, fallback domain.WeatherDataService, policy QuotaPolicy) *QuotaGuard {
+	g := &QuotaGuard{
+		primary:  primary,
+		fallback: fallback,
+		policy:   policy,
+		now:      time.Now,
+		reported: -1,
+		served:   make(map[string]int64),
+		readings: make(map[string]cachedWeather),
+	}
+	g.month = monthStart(g.now())
+	g.day = dayStart(g.now())
+	g.registerMetrics()
+	return g
+}
+
+// registerMetrics publishes the remaining budget and its state as gauges
+func (g *QuotaGuard) registerMetrics() {
+	meter := otel.Meter("weather-service")
+	remaining, err := meter.Int64ObservableGauge(
+		"weather_api.quota.remaining",
+		metric.WithDescription("WeatherAPI calls left by quota period; -1 when unlimited"),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Failed to create quota gauge: %v", err)
+		return
+	}
+	state, err := meter.Int64ObservableGauge(
+		"weather_api.quota.state",
+		metric.WithDescription("WeatherAPI budget state: 0 ok, 1 conserving, 2 exhausted"),
+	)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Failed to create quota state gauge: %v", err)
+		return
+	}
+
+	states := map[string]int64{QuotaStateOK: 0, QuotaStateConserving: 1, QuotaStateExhausted: 2}
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		stats := g.Stats()
+		o.ObserveInt64(remaining, stats.Remaining, metric.WithAttributes(attribute.String("quota.period", "month")))
+		o.ObserveInt64(remaining, stats.DailyRemaining, metric.WithAttributes(attribute.String("quota.period", "day")))
+		o.ObserveInt64(state, states[stats.State])
+		return nil
+	}, remaining, state)
+	if err != nil {
+		log.Printf("[ORCHESTRATOR] Failed to register quota gauges: %v", err)
+	}
+}
+
+// WithFlags lets the fallback provider be switched off at runtime
+func (g *QuotaGuard) WithFlags(flags *featureflag.Flags) *QuotaGuard {
+	g.flags = flags
+	return g
+}
+
+// GetWeatherByLocation serves the reading from WeatherAPI while under the
+// threshold, then from cache, fallback, or WeatherAPI until the quota is exhausted
+func (g *QuotaGuard) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	if !g.overThreshold() {
+		return g.callPrimary(ctx, span, location)
+	}
+
+	if weather, ok := g.cached(location); ok {
+		g.record(span, providerCache)
+		return weather, nil
+	}
+
+	if g.fallback != nil && g.flags.Enabled(featureflag.FallbackProvider) {
+		weather, err := g.fallback.GetWeatherByLocation(ctx, location)
+		if err == nil {
+			g.remember(location, weather)
+			g.record(span, providerFallback)
+			return weather, nil
+		}
+		log.Printf("[ORCHESTRATOR] Fallback weather provider failed for %s: %v", location, err)
+		span.RecordError(err)
+	}
+
+	return g.callPrimary(ctx, span, location)
+}
+
+// callPrimary reserves a call from the quota and forwards it to WeatherAPI
+func (g *QuotaGuard) callPrimary(ctx context.Context, span trace.Span, location string) (*domain.WeatherAPIResponse, error) {
+	calls, err := g.reserve(span)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.Int64("weather.quota.calls", calls),
+		attribute.Float64("weather.upstream.cost", g.policy.CostPerCall),
+	)
+
+	weather, err := g.primary.GetWeatherByLocation(ctx, location)
+	g.syncReported()
+	if err != nil {
+		return nil, err
+	}
+	g.remember(location, weather)
+	g.record(span, providerPrimary)
+	return weather, nil
+}
+
+// PrimaryOnly returns a view of the guard that always calls WeatherAPI,
+// still counting calls against the quota, for requests pinned to WeatherAPI
+func (g *QuotaGuard) PrimaryOnly() domain.WeatherDataService {
+	return primaryOnly{guard: g}
+}
+
+type primaryOnly struct {
+	guard *QuotaGuard
+}
+
+func (p primaryOnly) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	return p.guard.callPrimary(ctx, trace.SpanFromContext(ctx), location)
+}
+
+// GetAlertsByLocation forwards alert lookups to WeatherAPI, counting them
+// against the quota; alerts have no cached or fallback substitute
+func (g *QuotaGuard) GetAlertsByLocation(ctx context.Context, location string) ([]domain.WeatherAlert, error) {
+	alerts, ok := g.primary.(domain.AlertDataService)
+	if !ok {
+		return nil, ErrAlertsUnsupported
+	}
+
+	span := trace.SpanFromContext(ctx)
+	calls, err := g.reserve(span)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.Int64("weather.quota.calls", calls),
+		attribute.Float64("weather.upstream.cost", g.policy.CostPerCall),
+	)
+	result, err := alerts.GetAlertsByLocation(ctx, location)
+	g.syncReported()
+	return result, err
+}
+
+// reserve counts one WeatherAPI call, failing with ErrQuotaExhausted when a
+// quota is used up or ErrQuotaThrottled when the call comes too soon past the
+// threshold. Every attempt counts, since WeatherAPI bills failed requests too.
+func (g *QuotaGuard) reserve(span trace.Span) (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+
+	remaining, resetIn, limited := g.remainingLocked()
+	if limited && remaining <= 0 {
+		g.rejected++
+		span.SetAttributes(attribute.Bool("weather.quota.exhausted", true))
+		return g.calls, ErrQuotaExhausted
+	}
+
+	// Spread what is left evenly until the budget resets
+	now := g.now()
+	if g.policy.Throttle && limited && g.overThresholdLocked() {
+		interval := resetIn / time.Duration(remaining)
+		if wait := g.lastPrimary.Add(interval).Sub(now); wait > 0 {
+			g.throttled++
+			span.SetAttributes(attribute.Int64("weather.quota.throttle_wait_ms", wait.Milliseconds()))
+			return g.calls, ErrQuotaThrottled
+		}
+	}
+
+	g.calls++
+	g.dailyCalls++
+	g.lastPrimary = now
+	if g.reported > 0 {
+		g.reported--
+	}
+	return g.calls, nil
+}
+
+// remainingLocked returns the tightest remaining budget and how long until it
+// resets; limited is false when no quota applies. Callers hold mu.
+func (g *QuotaGuard) remainingLocked() (remaining int64, resetIn time.Duration, limited bool) {
+	now := g.now()
+	consider := func(left int64, reset time.Time) {
+		if !limited || left < remaining {
+			remaining, resetIn, limited = max(left, 0), reset.Sub(now), true
+		}
+	}
+	if g.policy.MonthlyQuota > 0 {
+		consider(g.policy.MonthlyQuota-g.calls, g.month.AddDate(0, 1, 0))
+	}
+	if g.reported >= 0 {
+		consider(g.reported, g.month.AddDate(0, 1, 0))
+	}
+	if g.policy.DailyQuota > 0 {
+		consider(g.policy.DailyQuota-g.dailyCalls, g.day.AddDate(0, 0, 1))
+	}
+	return remaining, resetIn, limited
+}
+
+// syncReported adopts the remaining quota reported by the primary provider
+func (g *QuotaGuard) syncReported() {
+	reporter, ok := g.primary.(QuotaReporter)
+	if !ok {
+		return
+	}
+	if remaining, ok := reporter.QuotaRemaining(); ok {
+		g.mu.Lock()
+		g.reported = remaining
+		g.mu.Unlock()
+	}
+}
+
+func (g *QuotaGuard) overThreshold() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+
+	over := g.overThresholdLocked()
+	if over && !g.conserving {
+		log.Printf("[ORCHESTRATOR] WeatherAPI quota threshold reached (%d/%d calls this month, %d/%d today), preferring cached and fallback readings",
+			g.calls, g.policy.MonthlyQuota, g.dailyCalls, g.policy.DailyQuota)
+	}
+	g.conserving = over
+	return over
+}
+
+// overThresholdLocked reports whether any budget is past the threshold; callers hold mu
+func (g *QuotaGuard) overThresholdLocked() bool {
+	over := false
+	if g.policy.MonthlyQuota > 0 {
+		used := g.calls
+		if g.reported >= 0 {
+			used = max(used, g.policy.MonthlyQuota-g.reported)
+		}
+		over = float64(used) >= g.policy.Threshold*float64(g.policy.MonthlyQuota)
+	}
+	if g.policy.DailyQuota > 0 && float64(g.dailyCalls) >= g.policy.Threshold*float64(g.policy.DailyQuota) {
+		over = true
+	}
+	if g.reported == 0 {
+		over = true
+	}
+	return over
+}
+
+// rollover resets the counters when a new day or month starts; callers hold mu
+func (g *QuotaGuard) rollover() {
+	now := g.now()
+	if day := dayStart(now); day.After(g.day) {
+		g.day = day
+		g.dailyCalls = 0
+		g.conserving = false
+	}
+	if month := monthStart(now); month.After(g.month) {
+		g.month = month
+		g.calls = 0
+		g.reported = -1
+		g.rejected = 0
+		g.throttled = 0
+		g.conserving = false
+		g.served = make(map[string]int64)
+	}
+}
+
+func (g *QuotaGuard) record(span trace.Span, provider string) {
+	span.SetAttributes(attribute.String("weather.provider", provider))
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.served[provider]++
+}
+
+// remember keeps the reading of location for the cache. When maxCachedReadings
+// cities are kept, readings older than CacheMaxAge are dropped first and then,
+// if none expired, the oldest one.
+func (g *QuotaGuard) remember(location string, weather *domain.WeatherAPIResponse) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	if _, ok := g.readings[location]; !ok && len(g.readings) >= maxCachedReadings {
+		g.evictLocked(now)
+	}
+	g.readings[location] = cachedWeather{weather: *weather, storedAt: now}
+}
+
+// evictLocked drops the expired readings, or the oldest one when none expired; callers hold mu
+func (g *QuotaGuard) evictLocked(now time.Time) {
+	var oldest string
+	for location, reading := range g.readings {
+		if now.Sub(reading.storedAt) > g.policy.CacheMaxAge {
+			delete(g.readings, location)
+			continue
+		}
+		if oldest == "" || reading.storedAt.Before(g.readings[oldest].storedAt) {
+			oldest = location
+		}
+	}
+	if len(g.readings) >= maxCachedReadings {
+		delete(g.readings, oldest)
+	}
+}
+
+func (g *QuotaGuard) cached(location string) (*domain.WeatherAPIResponse, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	reading, ok := g.readings[location]
+	if !ok || g.now().Sub(reading.storedAt) > g.policy.CacheMaxAge {
+		return nil, false
+	}
+	weather := reading.weather
+	weather.Provider = providerCache
+	return &weather, true
+}
+
+// Stats returns the usage of the current month and day and the projected usage at month end
+func (g *QuotaGuard) Stats() QuotaStats {
+	fallbackAvailable := g.fallback != nil && g.flags.Enabled(featureflag.FallbackProvider)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+
+	now := g.now()
+	stats := QuotaStats{
+		Month:             g.month.Format("2006-01"),
+		Calls:             g.calls,
+		MonthlyQuota:      g.policy.MonthlyQuota,
+		Remaining:         -1,
+		DailyCalls:        g.dailyCalls,
+		DailyQuota:        g.policy.DailyQuota,
+		DailyRemaining:    -1,
+		ReportedRemaining: g.reported,
+		Threshold:         g.policy.Threshold,
+		Conserving:        g.overThresholdLocked(),
+		State:             QuotaStateOK,
+		FallbackAvailable: fallbackAvailable,
+		EstimatedCost:     float64(g.calls) * g.policy.CostPerCall,
+		ServedByPrimary:   g.served[providerPrimary],
+		ServedByCache:     g.served[providerCache],
+		ServedByFallback:  g.served[providerFallback],
+		Rejected:          g.rejected,
+		Throttled:         g.throttled,
+		ProjectedCalls:    g.calls,
+	}
+
+	monthLength := g.month.AddDate(0, 1, 0).Sub(g.month)
+	if elapsed := now.Sub(g.month); elapsed > 0 {
+		stats.ProjectedCalls = int64(float64(g.calls) * float64(monthLength) / float64(elapsed))
+	}
+	if g.policy.MonthlyQuota > 0 {
+		stats.Remaining = max(g.policy.MonthlyQuota-g.calls, 0)
+		stats.ProjectedRemaining = g.policy.MonthlyQuota - stats.ProjectedCalls
+	}
+	if g.policy.DailyQuota > 0 {
+		stats.DailyRemaining = max(g.policy.DailyQuota-g.dailyCalls, 0)
+	}
+
+	if remaining, _, limited := g.remainingLocked(); limited && remaining <= 0 {
+		stats.State = QuotaStateExhausted
+	} else if stats.Conserving {
+		stats.State = QuotaStateConserving
+	}
+	return stats
+}
+
+func dayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"otel/internal/domain"
+	"otel/pkg/featureflag"
+)
+
+// countingWeatherRepo returns a fixed temperature and counts calls
+type countingWeatherRepo struct {
+	tempC float64
+	err   error
+	calls int
+}
+
+func (r *countingWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	var resp domain.WeatherAPIResponse
+	resp.Current.TempC = r.tempC
+	return &resp, nil
+}
+
+func newTestQuotaGuard(primary, fallback domain.WeatherDataService, policy QuotaPolicy, now *time.Time) *QuotaGuard {
+	g := NewQuotaGuard(primary, fallback, policy)
+	g.now = func() time.Time { return *now }
+	g.month = monthStart(*now)
+	g.day = dayStart(*now)
+	return g
+}
+
+func TestQuotaGuard_UnderThresholdUsesPrimary(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{tempC: 25}
+	fallback := &countingWeatherRepo{tempC: 30}
+	guard := newTestQuotaGuard(primary, fallback, QuotaPolicy{MonthlyQuota: 10, Threshold: 0.5, CacheMaxAge: time.Hour}, &now)
+
+	for i := 0; i < 4; i++ {
+		resp, err := guard.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+		if err != nil || resp.Current.TempC != 25 {
+			t.Fatalf("Expected primary reading, got %v, %v", resp, err)
+		}
+	}
+
+	if primary.calls != 4 || fallback.calls != 0 {
+		t.Errorf("Expected 4 primary and 0 fallback calls, got %d and %d", primary.calls, fallback.calls)
+	}
+	if stats := guard.Stats(); stats.Calls != 4 || stats.Remaining != 6 || stats.Conserving {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestQuotaGuard_OverThresholdPrefersCacheThenFallback(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{tempC: 25}
+	fallback := &countingWeatherRepo{tempC: 30}
+	guard := newTestQuotaGuard(primary, fallback, QuotaPolicy{MonthlyQuota: 10, Threshold: 0.2, CacheMaxAge: time.Hour}, &now)
+
+	guard.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+	guard.GetWeatherByLocation(context.Background(), "Rio de Janeiro,RJ")
+
+	// Threshold reached: cached location is served without calling any provider
+	resp, err := guard.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+	if err != nil || resp.Current.TempC != 25 {
+		t.Fatalf("Expected cached reading, got %v, %v", resp, err)
+	}
+
+	// Unknown location goes to the fallback provider
+	resp, err = guard.GetWeatherByLocation(context.Background(), "Belo Horizonte,MG")
+	if err != nil || resp.Current.TempC != 30 {
+		t.Fatalf("Expected fallback reading, got %v, %v", resp, err)
+	}
+
+	// Expired cache entries are not served
+	now = now.Add(2 * time.Hour)
+	guard.GetWeatherByLocation(context.Background(), "Rio de Janeiro,RJ")
+
+	if primary.calls != 2 || fallback.calls != 2 {
+		t.Errorf("Expected 2 primary and 2 fallback calls, got %d and %d", primary.calls, fallback.calls)
+	}
+
+	stats := guard.Stats()
+	if !stats.Conserving || stats.ServedByCache != 1 || stats.ServedByFallback != 2 || stats.ServedByPrimary != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestQuotaGuard_BoundsCachedReadings(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	guard := newTestQuotaGuard(&countingWeatherRepo{tempC: 25}, nil, QuotaPolicy{CacheMaxAge: time.Hour}, &now)
+
+	for i := 0; i <= maxCachedReadings; i++ {
+		guard.GetWeatherByLocation(context.Background(), fmt.Sprintf("city-%d", i))
+		now = now.Add(time.Second)
+	}
+	if len(guard.readings) != maxCachedReadings {
+		t.Fatalf("Expected %d cached readings, got %d", maxCachedReadings, len(guard.readings))
+	}
+	if _, ok := guard.readings["city-0"]; ok {
+		t.Errorf("Expected the oldest reading to be evicted")
+	}
+
+	// Once they expire, the old readings make room all at once
+	now = now.Add(2 * time.Hour)
+	guard.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+	if len(guard.readings) != 1 {
+		t.Errorf("Expected only the new reading after expiry, got %d", len(guard.readings))
+	}
+}
+
+func TestQuotaGuard_FallbackFlagOff(t *testing.T) {
+	t.Setenv(featureflag.EnvPrefix+"FALLBACK_PROVIDER", "off")
+	flags, err := featureflag.New(featureflag.Defaults())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{tempC: 25}
+	fallback := &countingWeatherRepo{tempC: 30}
+	guard := newTestQuotaGuard(primary, fallback, QuotaPolicy{MonthlyQuota: 10, Threshold: 0.1, CacheMaxAge: time.Hour}, &now).
+		WithFlags(flags)
+
+	guard.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+	resp, err := guard.GetWeatherByLocation(context.Background(), "Belo Horizonte,MG")
+	if err != nil || resp.Current.TempC != 25 {
+		t.Fatalf("Expected WeatherAPI reading, got %v, %v", resp, err)
+	}
+	if fallback.calls != 0 {
+		t.Errorf("Expected no fallback calls, got %d", fallback.calls)
+	}
+}
+
+func TestQuotaGuard_ExhaustedQuota(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{tempC: 25}
+	fallback := &countingWeatherRepo{err: errors.New("fallback down")}
+	guard := newTestQuotaGuard(primary, fallback, QuotaPolicy{MonthlyQuota: 2, Threshold: 0.5, CacheMaxAge: time.Hour}, &now)
+
+	// Past the threshold with a failing fallback, WeatherAPI is still used until the quota runs out
+	guard.GetWeatherByLocation(context.Background(), "A,SP")
+	guard.GetWeatherByLocation(context.Background(), "B,SP")
+
+	if _, err := guard.GetWeatherByLocation(context.Background(), "C,SP"); !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("Expected ErrQuotaExhausted, got %v", err)
+	}
+	if primary.calls != 2 {
+		t.Errorf("Expected 2 primary calls, got %d", primary.calls)
+	}
+	if stats := guard.Stats(); stats.Remaining != 0 || stats.Rejected != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestQuotaGuard_CountsFailedCalls(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{err: errors.New("status 500")}
+	guard := newTestQuotaGuard(primary, nil, QuotaPolicy{Threshold: 0.9, CostPerCall: 0.5}, &now)
+
+	if _, err := guard.GetWeatherByLocation(context.Background(), "A,SP"); err == nil {
+		t.Fatal("Expected primary error")
+	}
+
+	stats := guard.Stats()
+	if stats.Calls != 1 || stats.EstimatedCost != 0.5 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.Remaining != -1 {
+		t.Errorf("Expected remaining -1 without a quota, got %d", stats.Remaining)
+	}
+}
+
+func TestQuotaGuard_MonthRollover(t *testing.T) {
+	now := time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{tempC: 25}
+	guard := newTestQuotaGuard(primary, nil, QuotaPolicy{MonthlyQuota: 1, Threshold: 1, CacheMaxAge: time.Hour}, &now)
+
+	guard.GetWeatherByLocation(context.Background(), "A,SP")
+	if _, err := guard.GetWeatherByLocation(context.Background(), "B,SP"); !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("Expected ErrQuotaExhausted, got %v", err)
+	}
+
+	now = time.Date(2025, 4, 1, 0, 30, 0, 0, time.UTC)
+	if _, err := guard.GetWeatherByLocation(context.Background(), "B,SP"); err != nil {
+		t.Fatalf("Expected quota to reset in the new month, got %v", err)
+	}
+	if stats := guard.Stats(); stats.Month != "2025-04" || stats.Calls != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestQuotaGuard_Projection(t *testing.T) {
+	// Halfway through a 30-day month
+	now := time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC)
+	guard := newTestQuotaGuard(&countingWeatherRepo{}, nil, QuotaPolicy{MonthlyQuota: 1000, Threshold: 1}, &now)
+	guard.calls = 300
+
+	stats := guard.Stats()
+	if stats.ProjectedCalls != 600 || stats.ProjectedRemaining != 400 {
+		t.Errorf("Expected 600 projected calls and 400 remaining, got %+v", stats)
+	}
+}
+
+func TestQuotaGuard_PrimaryOnly(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{tempC: 25}
+	fallback := &countingWeatherRepo{tempC: 30}
+	guard := newTestQuotaGuard(primary, fallback, QuotaPolicy{MonthlyQuota: 2, Threshold: 0.1, CacheMaxAge: time.Hour}, &now)
+
+	// Past the threshold, pinned lookups still reach WeatherAPI until the quota runs out
+	pinned := guard.PrimaryOnly()
+	for i := 0; i < 2; i++ {
+		if resp, err := pinned.GetWeatherByLocation(context.Background(), "A,SP"); err != nil || resp.Current.TempC != 25 {
+			t.Fatalf("Expected WeatherAPI reading, got %v, %v", resp, err)
+		}
+	}
+	if _, err := pinned.GetWeatherByLocation(context.Background(), "A,SP"); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Expected ErrQuotaExhausted, got %v", err)
+	}
+	if fallback.calls != 0 || guard.Stats().Calls != 2 {
+		t.Errorf("Expected no fallback calls and 2 counted calls, got %d and %+v", fallback.calls, guard.Stats())
+	}
+}
+
+// alertingWeatherRepo also serves alerts
+type alertingWeatherRepo struct {
+	countingWeatherRepo
+}
+
+func (r *alertingWeatherRepo) GetAlertsByLocation(ctx context.Context, location string) ([]domain.WeatherAlert, error) {
+	r.calls++
+	return []domain.WeatherAlert{{Event: "Tempestade"}}, nil
+}
+
+func TestQuotaGuard_Alerts(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	primary := &alertingWeatherRepo{}
+	guard := newTestQuotaGuard(primary, nil, QuotaPolicy{MonthlyQuota: 1, Threshold: 1}, &now)
+
+	alerts, err := guard.GetAlertsByLocation(context.Background(), "A,SP")
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %v, %v", alerts, err)
+	}
+	if _, err := guard.GetAlertsByLocation(context.Background(), "A,SP"); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Expected ErrQuotaExhausted, got %v", err)
+	}
+	if guard.Stats().Calls != 1 {
+		t.Errorf("Expected alert calls to count against the quota")
+	}
+
+	withoutAlerts := newTestQuotaGuard(&countingWeatherRepo{}, nil, QuotaPolicy{}, &now)
+	if _, err := withoutAlerts.GetAlertsByLocation(context.Background(), "A,SP"); !errors.Is(err, ErrAlertsUnsupported) {
+		t.Errorf("Expected ErrAlertsUnsupported, got %v", err)
+	}
+}
+
+func TestQuotaGuard_DailyQuota(t *testing.T) {
+	now := time.Date(2025, 3, 10, 23, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{tempC: 25}
+	guard := newTestQuotaGuard(primary, nil, QuotaPolicy{MonthlyQuota: 100, DailyQuota: 2, Threshold: 1}, &now)
+
+	guard.GetWeatherByLocation(context.Background(), "A,SP")
+	guard.GetWeatherByLocation(context.Background(), "B,SP")
+	if _, err := guard.GetWeatherByLocation(context.Background(), "C,SP"); !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("Expected ErrQuotaExhausted, got %v", err)
+	}
+	if stats := guard.Stats(); stats.DailyRemaining != 0 || stats.Remaining != 98 || stats.State != QuotaStateExhausted {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	now = time.Date(2025, 3, 11, 0, 30, 0, 0, time.UTC)
+	if _, err := guard.GetWeatherByLocation(context.Background(), "C,SP"); err != nil {
+		t.Fatalf("Expected the daily quota to reset, got %v", err)
+	}
+	if stats := guard.Stats(); stats.DailyCalls != 1 || stats.Calls != 3 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+// reportingWeatherRepo reports a remaining quota like a WeatherAPI response header
+type reportingWeatherRepo struct {
+	countingWeatherRepo
+	remaining int64
+}
+
+func (r *reportingWeatherRepo) QuotaRemaining() (int64, bool) {
+	return r.remaining, true
+}
+
+func TestQuotaGuard_ReportedRemaining(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	primary := &reportingWeatherRepo{countingWeatherRepo: countingWeatherRepo{tempC: 25}, remaining: 5}
+	guard := newTestQuotaGuard(primary, nil, QuotaPolicy{MonthlyQuota: 100, Threshold: 0.9}, &now)
+
+	// The provider knows of calls made elsewhere, so its lower count wins
+	guard.GetWeatherByLocation(context.Background(), "A,SP")
+	stats := guard.Stats()
+	if stats.ReportedRemaining != 5 || stats.State != QuotaStateConserving {
+		t.Errorf("Expected the reported budget to trigger conservation, got %+v", stats)
+	}
+
+	primary.remaining = 0
+	guard.GetWeatherByLocation(context.Background(), "B,SP")
+	if _, err := guard.GetWeatherByLocation(context.Background(), "C,SP"); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Expected ErrQuotaExhausted once the provider reports 0, got %v", err)
+	}
+}
+
+func TestQuotaGuard_Throttle(t *testing.T) {
+	// 10 calls left with 10 days to go: about one call per day
+	now := time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC)
+	primary := &countingWeatherRepo{tempC: 25}
+	guard := newTestQuotaGuard(primary, nil, QuotaPolicy{MonthlyQuota: 100, Threshold: 0.5, Throttle: true}, &now)
+	guard.calls = 90
+
+	if _, err := guard.GetWeatherByLocation(context.Background(), "A,SP"); err != nil {
+		t.Fatalf("Expected the first call to pass, got %v", err)
+	}
+	if _, err := guard.GetWeatherByLocation(context.Background(), "B,SP"); !errors.Is(err, ErrQuotaThrottled) {
+		t.Fatalf("Expected ErrQuotaThrottled, got %v", err)
+	}
+
+	now = now.Add(27 * time.Hour)
+	if _, err := guard.GetWeatherByLocation(context.Background(), "B,SP"); err != nil {
+		t.Fatalf("Expected a call after the interval to pass, got %v", err)
+	}
+	if stats := guard.Stats(); stats.Throttled != 1 || primary.calls != 2 {
+		t.Errorf("Expected 1 throttled and 2 primary calls, got %+v and %d", stats, primary.calls)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"otel/internal/domain"
+	"otel/pkg/validator"
+)
+
+// WeatherUpdate is one refresh of the weather for a CEP
+type WeatherUpdate struct {
+	Weather   *domain.WeatherResponse
+	Err       error
+	FetchedAt time.Time
+}
+
+// WeatherRefresher periodically refreshes the weather of the CEPs that have
+// subscribers. Subscribers of the same CEP share one refresh loop, so the
+// upstream APIs are called once per interval regardless of how many listen.
+type WeatherRefresher struct {
+	service  *WeatherService
+	interval time.Duration
+
+	mu    sync.Mutex
+	feeds map[string]*weatherFeed
+}
+
+// weatherFeed is the refresh loop of one CEP and its subscribers
+type weatherFeed struct {
+	subscribers map[chan WeatherUpdate]struct{}
+	last        *WeatherUpdate
+	cancel      context.CancelFunc
+}
+
+// NewWeatherRefresher creates a refresher fetching every interval
+func NewWeatherRefresher(service *WeatherService, interval time.Duration) *WeatherRefresher {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	log.Printf("[ORCHESTRATOR] Initializing weather refresher - interval: %v", interval)
+
+	return &WeatherRefresher{
+		service:  service,
+		interval: interval,
+		feeds:    make(map[string]*weatherFeed),
+	}
+}
+
+// Interval returns the time between refreshes
+func (r *WeatherRefresher) Interval() time.Duration {
+	return r.interval
+}
+
+// Subscribe returns a channel receiving the updates of cep, starting with the
+// latest known one, and a function to unsubscribe. Slow subscribers only
+// keep the most recent update. The channel is closed on unsubscribe.
+func (r *WeatherRefresher) Subscribe(cep string) (<-chan WeatherUpdate, func()) {
+	key := validator.CleanCEP(cep)
+	updates := make(chan WeatherUpdate, 1)
+
+	r.mu.Lock()
+	feed, ok := r.feeds[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		feed = &weatherFeed{subscribers: make(map[chan WeatherUpdate]struct{}), cancel: cancel}
+		r.feeds[key] = feed
+		go r.run(ctx, cep, feed)
+		log.Printf("[ORCHESTRATOR] Started weather refresh loop for CEP %s", key)
+	}
+	feed.subscribers[updates] = struct{}{}
+	if feed.last != nil {
+		updates <- *feed.last
+	}
+	r.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() { r.unsubscribe(key, feed, updates) })
+	}
+	return updates, unsubscribe
+}
+
+func (r *WeatherRefresher) unsubscribe(key string, feed *weatherFeed, updates chan WeatherUpdate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(feed.subscribers, updates)
+	close(updates)
+
+	if len(feed.subscribers) == 0 {
+		feed.cancel()
+		delete(r.feeds, key)
+		log.Printf("[ORCHESTRATOR] Stopped weather refresh loop for CEP %s", key)
+	}
+}
+
+// run fetches immediately and then every interval until ctx is cancelled
+func (r *WeatherRefresher) run(ctx context.Context, cep string, feed *weatherFeed) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		weather, err := r.service.GetWeatherByCEP(ctx, cep)
+		if ctx.Err() != nil {
+			return
+		}
+		r.publish(feed, WeatherUpdate{Weather: weather, Err: err, FetchedAt: time.Now()})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish delivers update to every subscriber, replacing any update they have not read yet
+func (r *WeatherRefresher) publish(feed *weatherFeed, update WeatherUpdate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	feed.last = &update
+	for updates := range feed.subscribers {
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- update
+	}
+}
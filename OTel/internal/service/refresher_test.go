@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"otel/internal/domain"
+)
+
+// countingLocationRepo counts lookups to verify subscribers share one refresh loop
+type countingLocationRepo struct {
+	MockLocationRepo
+	mu    sync.Mutex
+	calls int
+}
+
+func (m *countingLocationRepo) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+	return m.MockLocationRepo.GetLocationByCEP(ctx, cep)
+}
+
+func (m *countingLocationRepo) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+func receiveUpdate(t *testing.T, updates <-chan WeatherUpdate) WeatherUpdate {
+	t.Helper()
+	select {
+	case update := <-updates:
+		return update
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for weather update")
+		return WeatherUpdate{}
+	}
+}
+
+func TestWeatherRefresher_SharesRefreshLoop(t *testing.T) {
+	locationRepo := &countingLocationRepo{}
+	refresher := NewWeatherRefresher(NewWeatherService(locationRepo, &MockWeatherRepo{}), time.Hour)
+
+	first, unsubscribeFirst := refresher.Subscribe("01310100")
+	update := receiveUpdate(t, first)
+	if update.Err != nil || update.Weather.City != "São Paulo" {
+		t.Fatalf("Expected São Paulo weather, got %+v", update)
+	}
+
+	// A second subscriber gets the latest update without a new fetch
+	second, unsubscribeSecond := refresher.Subscribe("01310-100")
+	if update := receiveUpdate(t, second); update.Weather == nil || update.Weather.TempC != 25.5 {
+		t.Errorf("Expected latest update for second subscriber, got %+v", update)
+	}
+	if calls := locationRepo.Calls(); calls != 1 {
+		t.Errorf("Expected 1 upstream lookup, got %d", calls)
+	}
+
+	unsubscribeFirst()
+	unsubscribeSecond()
+	unsubscribeSecond()
+
+	if _, ok := <-first; ok {
+		t.Error("Expected channel to be closed after unsubscribe")
+	}
+	refresher.mu.Lock()
+	defer refresher.mu.Unlock()
+	if len(refresher.feeds) != 0 {
+		t.Errorf("Expected refresh loop to stop without subscribers, %d running", len(refresher.feeds))
+	}
+}
+
+func TestWeatherRefresher_RefreshesOnInterval(t *testing.T) {
+	locationRepo := &countingLocationRepo{}
+	refresher := NewWeatherRefresher(NewWeatherService(locationRepo, &MockWeatherRepo{}), 10*time.Millisecond)
+
+	updates, unsubscribe := refresher.Subscribe("20040020")
+	defer unsubscribe()
+
+	receiveUpdate(t, updates)
+	receiveUpdate(t, updates)
+	if calls := locationRepo.Calls(); calls < 2 {
+		t.Errorf("Expected at least 2 upstream lookups, got %d", calls)
+	}
+}
+
+func TestWeatherRefresher_PublishesErrors(t *testing.T) {
+	refresher := NewWeatherRefresher(NewWeatherService(&MockLocationRepo{shouldFail: true}, &MockWeatherRepo{}), time.Hour)
+
+	updates, unsubscribe := refresher.Subscribe("01310100")
+	defer unsubscribe()
+
+	if update := receiveUpdate(t, updates); !errors.Is(update.Err, ErrCEPNotFound) {
+		t.Errorf("Expected ErrCEPNotFound, got %+v", update)
+	}
+}
@@ -2,11 +2,11 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
 	"otel/internal/domain"
+	"otel/pkg/cityname"
 	"otel/pkg/telemetry"
 	"otel/pkg/temperature"
 
@@ -20,6 +20,8 @@ type WeatherService struct {
 	locationRepo    domain.LocationService
 	weatherDataRepo domain.WeatherDataService
 	tracer          trace.Tracer
+	anomalies       *anomalyDetector
+	cities          *cityQueries
 }
 
 // NewWeatherService creates a new weather service
@@ -29,9 +31,23 @@ func NewWeatherService(locationRepo domain.LocationService, weatherDataRepo doma
 		locationRepo:    locationRepo,
 		weatherDataRepo: weatherDataRepo,
 		tracer:          telemetry.GetTracer("weather-service"),
+		anomalies:       newAnomalyDetector(DefaultAnomalyPolicy()),
+		cities:          newCityQueries(cityname.New(cityname.Options{})),
 	}
 }
 
+// WithAnomalyPolicy replaces the plausibility bounds and rejection behaviour
+func (s *WeatherService) WithAnomalyPolicy(policy AnomalyPolicy) *WeatherService {
+	s.anomalies = newAnomalyDetector(policy)
+	return s
+}
+
+// WithCityNormalizer replaces how city names are rewritten before querying WeatherAPI
+func (s *WeatherService) WithCityNormalizer(normalizer *cityname.Normalizer) *WeatherService {
+	s.cities = newCityQueries(normalizer)
+	return s
+}
+
 // GetWeatherByCEP gets weather information for a given CEP
 func (s *WeatherService) GetWeatherByCEP(ctx context.Context, cep string) (*domain.WeatherResponse, error) {
 	// Start span for the entire weather service operation
@@ -47,9 +63,9 @@ func (s *WeatherService) GetWeatherByCEP(ctx context.Context, cep string) (*doma
 	// Get location by CEP
 	log.Printf("[ORCHESTRATOR] Fetching location for CEP: %s", cep)
 	locationStart := time.Now()
-	_, locationSpan := s.tracer.Start(ctx, "weather_service.get_location_by_cep")
+	locationCtx, locationSpan := s.tracer.Start(ctx, "weather_service.get_location_by_cep")
 
-	location, err := s.locationRepo.GetLocationByCEP(cep)
+	location, err := s.locationRepo.GetLocationByCEP(locationCtx, cep)
 	locationDuration := time.Since(locationStart)
 
 	if err != nil {
@@ -73,13 +89,12 @@ func (s *WeatherService) GetWeatherByCEP(ctx context.Context, cep string) (*doma
 	log.Printf("[ORCHESTRATOR] Location found: %s, %s", location.Localidade, location.UF)
 
 	// Get weather data for the location
-	locationQuery := fmt.Sprintf("%s,%s", location.Localidade, location.UF)
-	log.Printf("[ORCHESTRATOR] Fetching weather for location: %s", locationQuery)
-
 	weatherStart := time.Now()
-	_, weatherSpan := s.tracer.Start(ctx, "weather_service.get_weather_by_location")
+	weatherCtx, weatherSpan := s.tracer.Start(ctx, "weather_service.get_weather_by_location")
+	locationQuery := s.cities.query(ctx, weatherSpan, location)
+	log.Printf("[ORCHESTRATOR] Fetching weather for location: %s", locationQuery)
 
-	weather, err := s.weatherDataRepo.GetWeatherByLocation(locationQuery)
+	weather, err := s.weatherDataRepo.GetWeatherByLocation(weatherCtx, locationQuery)
 	weatherDuration := time.Since(weatherStart)
 
 	if err != nil {
@@ -97,6 +112,30 @@ func (s *WeatherService) GetWeatherByCEP(ctx context.Context, cep string) (*doma
 		attribute.Float64("weather.temp_c_raw", weather.Current.TempC),
 		attribute.Int64("weather.fetch_duration_ms", weatherDuration.Milliseconds()),
 	)
+
+	// Guard consumers against corrupt provider readings
+	anomaly := s.anomalies.check(weatherSpan, locationQuery, weather.Current.TempC)
+	if anomaly != temperature.AnomalyNone {
+		span.SetAttributes(attribute.String("weather.anomaly.reason", anomaly))
+		if s.anomalies.policy.Reject {
+			if cached, ok := s.anomalies.cached(locationQuery); ok {
+				s.anomalies.record(ctx, anomaly, anomalyActionServedCached)
+				weatherSpan.SetStatus(codes.Error, "Anomalous temperature, served cached reading")
+				weatherSpan.End()
+				span.SetAttributes(attribute.Bool("response.stale", true))
+				log.Printf("[ORCHESTRATOR] Serving cached reading for %s instead of anomalous temperature", locationQuery)
+				return cached, nil
+			}
+			s.anomalies.record(ctx, anomaly, anomalyActionRejected)
+			weatherSpan.SetStatus(codes.Error, "Anomalous temperature rejected")
+			weatherSpan.End()
+			span.SetStatus(codes.Error, "Anomalous temperature rejected")
+			span.RecordError(ErrAnomalousReading)
+			return nil, ErrAnomalousReading
+		}
+		s.anomalies.record(ctx, anomaly, anomalyActionPassed)
+	}
+
 	weatherSpan.SetStatus(codes.Ok, "Weather data fetched successfully")
 	weatherSpan.End()
 
@@ -119,10 +158,15 @@ func (s *WeatherService) GetWeatherByCEP(ctx context.Context, cep string) (*doma
 	log.Printf("[ORCHESTRATOR] Temperature conversions - C: %.1f, F: %.1f, K: %.1f", tempC, tempF, tempK)
 
 	response := &domain.WeatherResponse{
-		City:  location.Localidade,
-		TempC: tempC,
-		TempF: tempF,
-		TempK: tempK,
+		City:     location.Localidade,
+		TempC:    tempC,
+		TempF:    tempF,
+		TempK:    tempK,
+		Provider: weather.Provider,
+	}
+
+	if anomaly == temperature.AnomalyNone {
+		s.anomalies.remember(locationQuery, *response)
 	}
 
 	span.SetAttributes(
@@ -12,7 +12,7 @@ type MockLocationRepo struct {
 	shouldFail bool
 }
 
-func (m *MockLocationRepo) GetLocationByCEP(cep string) (*domain.ViaCEPResponse, error) {
+func (m *MockLocationRepo) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
 	if m.shouldFail {
 		return nil, ErrCEPNotFound
 	}
@@ -49,7 +49,7 @@ type MockWeatherRepo struct {
 	shouldFail bool
 }
 
-func (m *MockWeatherRepo) GetWeatherByLocation(location string) (*domain.WeatherAPIResponse, error) {
+func (m *MockWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
 	if m.shouldFail {
 		return nil, ErrWeatherDataUnavailable
 	}
@@ -0,0 +1,143 @@
+// Package cityname rewrites ViaCEP city names into queries WeatherAPI resolves.
+package cityname
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Rewrite rules reported by Query
+const (
+	RuleAlias        = "alias"
+	RuleStripAccents = "strip_accents"
+	RuleState        = "state"
+)
+
+// DefaultAliases maps cities whose ViaCEP name WeatherAPI does not index to the
+// name it does, keyed by "City/UF"
+var DefaultAliases = map[string]string{
+	"Embu das Artes/SP":     "Embu",
+	"Paraty/RJ":             "Parati",
+	"Armação dos Búzios/RJ": "Buzios",
+	"Mogi Guaçu/SP":         "Moji Guacu",
+	"Mogi Mirim/SP":         "Moji Mirim",
+}
+
+// states holds the full state name for each UF
+var states = map[string]string{
+	"AC": "Acre", "AL": "Alagoas", "AP": "Amapá", "AM": "Amazonas", "BA": "Bahia",
+	"CE": "Ceará", "DF": "Distrito Federal", "ES": "Espírito Santo", "GO": "Goiás",
+	"MA": "Maranhão", "MT": "Mato Grosso", "MS": "Mato Grosso do Sul", "MG": "Minas Gerais",
+	"PA": "Pará", "PB": "Paraíba", "PR": "Paraná", "PE": "Pernambuco", "PI": "Piauí",
+	"RJ": "Rio de Janeiro", "RN": "Rio Grande do Norte", "RS": "Rio Grande do Sul",
+	"RO": "Rondônia", "RR": "Roraima", "SC": "Santa Catarina", "SP": "São Paulo",
+	"SE": "Sergipe", "TO": "Tocantins",
+}
+
+// Options selects the rewrites applied by a Normalizer
+type Options struct {
+	// StripAccents removes diacritics, e.g. "São Paulo" becomes "Sao Paulo"
+	StripAccents bool
+	// DisambiguateState replaces the UF with the state name and country, so
+	// "Palmas,TO" cannot resolve to a homonym abroad
+	DisambiguateState bool
+	// Aliases replace city names, keyed by "City/UF" or "City" for any state;
+	// keys are matched ignoring case and accents and take precedence over DefaultAliases
+	Aliases map[string]string
+}
+
+// Normalizer builds WeatherAPI queries from city and state
+type Normalizer struct {
+	opts    Options
+	aliases map[string]string
+}
+
+// New creates a Normalizer with DefaultAliases extended by opts.Aliases
+func New(opts Options) *Normalizer {
+	aliases := make(map[string]string, len(DefaultAliases)+len(opts.Aliases))
+	for key, city := range DefaultAliases {
+		aliases[fold(key)] = city
+	}
+	for key, city := range opts.Aliases {
+		aliases[fold(key)] = city
+	}
+	return &Normalizer{opts: opts, aliases: aliases}
+}
+
+// Query returns the WeatherAPI query for city in uf and the rules that changed
+// it from the plain "City,UF" form, in the order they were applied
+func (n *Normalizer) Query(city, uf string) (string, []string) {
+	city = strings.Join(strings.Fields(city), " ")
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+
+	var rules []string
+	if alias, ok := n.alias(city, uf); ok && alias != city {
+		city = alias
+		rules = append(rules, RuleAlias)
+	}
+
+	state := uf
+	if name, ok := states[uf]; ok && n.opts.DisambiguateState {
+		state = name + ",Brazil"
+		rules = append(rules, RuleState)
+	}
+
+	query := fmt.Sprintf("%s,%s", city, state)
+	if n.opts.StripAccents {
+		if stripped := StripAccents(query); stripped != query {
+			query = stripped
+			rules = append(rules, RuleStripAccents)
+		}
+	}
+	return query, rules
+}
+
+func (n *Normalizer) alias(city, uf string) (string, bool) {
+	if alias, ok := n.aliases[fold(city+"/"+uf)]; ok {
+		return alias, true
+	}
+	alias, ok := n.aliases[fold(city)]
+	return alias, ok
+}
+
+// ParseAliases reads aliases in the "City/UF=Alias;City=Alias" form
+func ParseAliases(value string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	for _, pair := range strings.Split(value, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, alias, ok := strings.Cut(pair, "=")
+		key, alias = strings.TrimSpace(key), strings.TrimSpace(alias)
+		if !ok || key == "" || alias == "" {
+			return nil, fmt.Errorf("invalid city alias %q (expected City/UF=Alias)", pair)
+		}
+		aliases[key] = alias
+	}
+	return aliases, nil
+}
+
+// StripAccents removes diacritics from s
+func StripAccents(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return stripped
+}
+
+// fold is the comparison key of an alias: accents removed, lower case, single spaces
+func fold(s string) string {
+	city, uf, hasUF := strings.Cut(s, "/")
+	key := strings.ToLower(strings.Join(strings.Fields(StripAccents(city)), " "))
+	if hasUF {
+		key += "/" + strings.ToUpper(strings.TrimSpace(uf))
+	}
+	return key
+}
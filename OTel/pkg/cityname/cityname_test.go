@@ -0,0 +1,89 @@
+package cityname
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          Options
+		city, uf      string
+		expectedQuery string
+		expectedRules []string
+	}{
+		{"unchanged by default", Options{}, "São Paulo", "SP", "São Paulo,SP", nil},
+		{"whitespace collapsed", Options{}, "  Belo   Horizonte ", " mg", "Belo Horizonte,MG", nil},
+		{"default alias", Options{}, "Embu das Artes", "SP", "Embu,SP", []string{RuleAlias}},
+		{"alias ignores case and accents", Options{}, "mogi guacu", "SP", "Moji Guacu,SP", []string{RuleAlias}},
+		{"alias scoped to state", Options{}, "Paraty", "SP", "Paraty,SP", nil},
+		{"strip accents", Options{StripAccents: true}, "Florianópolis", "SC", "Florianopolis,SC", []string{RuleStripAccents}},
+		{"strip accents without accents", Options{StripAccents: true}, "Curitiba", "PR", "Curitiba,PR", nil},
+		{"state disambiguation", Options{DisambiguateState: true}, "Palmas", "TO", "Palmas,Tocantins,Brazil", []string{RuleState}},
+		{"unknown state kept", Options{DisambiguateState: true}, "Atlantis", "XX", "Atlantis,XX", nil},
+		{
+			"all rules",
+			Options{StripAccents: true, DisambiguateState: true},
+			"Armação dos Búzios", "RJ",
+			"Buzios,Rio de Janeiro,Brazil",
+			[]string{RuleAlias, RuleState},
+		},
+		{
+			"state name accents stripped",
+			Options{StripAccents: true, DisambiguateState: true},
+			"Campinas", "SP",
+			"Campinas,Sao Paulo,Brazil",
+			[]string{RuleState, RuleStripAccents},
+		},
+		{
+			"custom alias for any state overrides default",
+			Options{Aliases: map[string]string{"Embu das Artes": "Embu-Guacu"}},
+			"Embu das Artes", "SP",
+			"Embu,SP",
+			[]string{RuleAlias},
+		},
+		{
+			"custom alias for state",
+			Options{Aliases: map[string]string{"Embu das Artes/SP": "Embu das Artes"}},
+			"Embu das Artes", "SP",
+			"Embu das Artes,SP",
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, rules := New(tc.opts).Query(tc.city, tc.uf)
+			if query != tc.expectedQuery {
+				t.Errorf("Expected query %q, got %q", tc.expectedQuery, query)
+			}
+			if !reflect.DeepEqual(rules, tc.expectedRules) {
+				t.Errorf("Expected rules %v, got %v", tc.expectedRules, rules)
+			}
+		})
+	}
+}
+
+func TestParseAliases(t *testing.T) {
+	aliases, err := ParseAliases(" Embu das Artes/SP = Embu ; ;Paraty=Parati")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := map[string]string{"Embu das Artes/SP": "Embu", "Paraty": "Parati"}
+	if !reflect.DeepEqual(aliases, expected) {
+		t.Errorf("Expected %v, got %v", expected, aliases)
+	}
+
+	for _, invalid := range []string{"Embu", "=Embu", "Embu das Artes/SP="} {
+		if _, err := ParseAliases(invalid); err == nil {
+			t.Errorf("Expected error for %q, got nil", invalid)
+		}
+	}
+}
+
+func TestStripAccents(t *testing.T) {
+	if got := StripAccents("São João d'Aliança, Goiás"); got != "Sao Joao d'Alianca, Goias" {
+		t.Errorf("Expected accents removed, got %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+package debug
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ErrIncompleteCredentials is returned when only one of DEBUG_USERNAME and DEBUG_PASSWORD is set
+var ErrIncompleteCredentials = errors.New("DEBUG_USERNAME and DEBUG_PASSWORD must be set together")
+
+var (
+	startedAt   = time.Now()
+	publishOnce sync.Once
+)
+
+// Config holds the optional debug endpoint settings
+type Config struct {
+	Enabled bool
+	// Username and Password protect the endpoints with basic auth when set
+	Username string
+	Password string
+}
+
+// FromEnv reads DEBUG_ENDPOINTS_ENABLED, DEBUG_USERNAME and DEBUG_PASSWORD
+func FromEnv() (Config, error) {
+	cfg := Config{
+		Enabled:  os.Getenv("DEBUG_ENDPOINTS_ENABLED") == "true",
+		Username: os.Getenv("DEBUG_USERNAME"),
+		Password: os.Getenv("DEBUG_PASSWORD"),
+	}
+
+	if (cfg.Username == "") != (cfg.Password == "") {
+		return Config{}, ErrIncompleteCredentials
+	}
+	return cfg, nil
+}
+
+// Register mounts /debug/pprof/ and /debug/vars on the router when enabled.
+// /debug/vars serves expvar, including the "runtime" stats published here and
+// any variable the service publishes itself.
+func Register(r *mux.Router, cfg Config) {
+	if !cfg.Enabled {
+		return
+	}
+	publishOnce.Do(func() {
+		expvar.Publish("runtime", expvar.Func(runtimeStats))
+	})
+
+	sub := r.PathPrefix("/debug/").Subrouter()
+	if cfg.Username != "" {
+		sub.Use(basicAuth(cfg.Username, cfg.Password))
+	}
+
+	sub.HandleFunc("/pprof/cmdline", pprof.Cmdline)
+	sub.HandleFunc("/pprof/profile", pprof.Profile)
+	sub.HandleFunc("/pprof/symbol", pprof.Symbol)
+	sub.HandleFunc("/pprof/trace", pprof.Trace)
+	sub.PathPrefix("/pprof/").HandlerFunc(pprof.Index)
+	sub.Handle("/vars", expvar.Handler()).Methods("GET")
+}
+
+// runtimeStats is a compact view of the Go runtime for /debug/vars
+func runtimeStats() any {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return map[string]any{
+		"go_version":       runtime.Version(),
+		"uptime_seconds":   int64(time.Since(startedAt).Seconds()),
+		"goroutines":       runtime.NumGoroutine(),
+		"num_cpu":          runtime.NumCPU(),
+		"gomaxprocs":       runtime.GOMAXPROCS(0),
+		"heap_alloc_bytes": mem.HeapAlloc,
+		"heap_objects":     mem.HeapObjects,
+		"sys_bytes":        mem.Sys,
+		"num_gc":           mem.NumGC,
+		"gc_pause_total":   time.Duration(mem.PauseTotalNs).String(),
+	}
+}
+
+// basicAuth rejects requests without the configured credentials. Hashing both
+// sides first keeps the comparison constant-time regardless of length.
+func basicAuth(username, password string) mux.MiddlewareFunc {
+	wantUser := sha256.Sum256([]byte(username))
+	wantPass := sha256.Sum256([]byte(password))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			gotUser := sha256.Sum256([]byte(user))
+			gotPass := sha256.Sum256([]byte(pass))
+
+			if !ok ||
+				subtle.ConstantTimeCompare(gotUser[:], wantUser[:]) != 1 ||
+				subtle.ConstantTimeCompare(gotPass[:], wantPass[:]) != 1 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="debug"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
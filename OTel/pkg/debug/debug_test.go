@@ -0,0 +1,115 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("DEBUG_ENDPOINTS_ENABLED", "true")
+	t.Setenv("DEBUG_USERNAME", "admin")
+	t.Setenv("DEBUG_PASSWORD", "")
+
+	if _, err := FromEnv(); err != ErrIncompleteCredentials {
+		t.Errorf("Expected ErrIncompleteCredentials, got %v", err)
+	}
+
+	t.Setenv("DEBUG_PASSWORD", "secret")
+	cfg, err := FromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Enabled || cfg.Username != "admin" || cfg.Password != "secret" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+func TestRegister_Disabled(t *testing.T) {
+	r := mux.NewRouter()
+	Register(r, Config{})
+
+	for _, path := range []string{"/debug/pprof/", "/debug/vars"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestRegister_Enabled(t *testing.T) {
+	r := mux.NewRouter()
+	Register(r, Config{Enabled: true})
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/debug/pprof/", http.StatusOK},
+		{"/debug/pprof/goroutine?debug=1", http.StatusOK},
+		{"/debug/pprof/cmdline", http.StatusOK},
+		{"/debug/vars", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.want, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	var vars struct {
+		Runtime struct {
+			Goroutines int `json:"goroutines"`
+		} `json:"runtime"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("Failed to decode /debug/vars: %v", err)
+	}
+	if vars.Runtime.Goroutines == 0 {
+		t.Error("Expected runtime stats in /debug/vars")
+	}
+}
+
+func TestRegister_BasicAuth(t *testing.T) {
+	r := mux.NewRouter()
+	Register(r, Config{Enabled: true, Username: "admin", Password: "secret"})
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		setAuth  bool
+		want     int
+	}{
+		{name: "missing credentials", want: http.StatusUnauthorized},
+		{name: "wrong password", user: "admin", password: "wrong", setAuth: true, want: http.StatusUnauthorized},
+		{name: "wrong user", user: "root", password: "secret", setAuth: true, want: http.StatusUnauthorized},
+		{name: "valid credentials", user: "admin", password: "secret", setAuth: true, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+			if tt.setAuth {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, rec.Code)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected WWW-Authenticate header")
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package featureflag
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Flags known to the services. All of them are enabled unless turned off.
+const (
+	// ETagCache answers If-None-Match from cached payloads in the orchestrator
+	ETagCache = "etag_cache"
+	// FallbackProvider lets the quota guard answer from the fallback weather provider
+	FallbackProvider = "fallback_provider"
+	// ProviderPinning lets clients choose the weather provider per request
+	ProviderPinning = "provider_pinning"
+	// WeatherStream enables the SSE stream in the orchestrator and its proxy in the gateway
+	WeatherStream = "weather_stream"
+	// Callbacks enables callback mode in the gateway
+	Callbacks = "callbacks"
+	// RequestDedup shares one orchestrator call among concurrent gateway requests for the same CEP
+	RequestDedup = "request_dedup"
+)
+
+// EnvPrefix prefixes the environment variable of each flag, e.g. FEATURE_ETAG_CACHE=false
+const EnvPrefix = "FEATURE_"
+
+// FileEnv names the environment variable pointing to an optional flags file
+// with one name=value line per flag; the file overrides the environment
+const FileEnv = "FEATURE_FLAGS_FILE"
+
+// Defaults returns the default value of every known flag
+func Defaults() map[string]bool {
+	return map[string]bool{
+		ETagCache:        true,
+		FallbackProvider: true,
+		ProviderPinning:  true,
+		WeatherStream:    true,
+		Callbacks:        true,
+		RequestDedup:     true,
+	}
+}
+
+// Flags holds the current value of each flag. A nil *Flags reports every flag
+// as enabled, so components work unchanged when flags are not wired.
+type Flags struct {
+	defaults map[string]bool
+
+	mu     sync.RWMutex
+	values map[string]bool
+}
+
+// New loads the flags in defaults from the environment and FEATURE_FLAGS_FILE
+func New(defaults map[string]bool) (*Flags, error) {
+	f := &Flags{defaults: defaults}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Reload re-reads the environment and FEATURE_FLAGS_FILE. On error the
+// current values are kept.
+func (f *Flags) Reload() error {
+	values := make(map[string]bool, len(f.defaults))
+	for name, value := range f.defaults {
+		values[name] = value
+		if raw, ok := os.LookupEnv(EnvPrefix + strings.ToUpper(name)); ok {
+			parsed, err := parseValue(raw)
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %w", EnvPrefix, strings.ToUpper(name), err)
+			}
+			values[name] = parsed
+		}
+	}
+
+	if path := os.Getenv(FileEnv); path != "" {
+		if err := f.loadFile(path, values); err != nil {
+			return err
+		}
+	}
+
+	f.mu.Lock()
+	f.values = values
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *Flags) loadFile(path string, values map[string]bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open feature flags file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, raw, found := strings.Cut(line, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !found || name == "" {
+			return fmt.Errorf("invalid feature flag line %d: %q", lineNumber, line)
+		}
+		if _, known := f.defaults[name]; !known {
+			return fmt.Errorf("unknown feature flag %q on line %d", name, lineNumber)
+		}
+		parsed, err := parseValue(raw)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s on line %d: %w", name, lineNumber, err)
+		}
+		values[name] = parsed
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read feature flags file: %w", err)
+	}
+	return nil
+}
+
+func parseValue(raw string) (bool, error) {
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(raw), `"`)) {
+	case "1", "true", "on", "yes":
+		return true, nil
+	case "0", "false", "off", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a boolean", raw)
+}
+
+// Enabled reports whether the flag is on; unknown flags are off
+func (f *Flags) Enabled(name string) bool {
+	if f == nil {
+		return true
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.values[name]
+}
+
+// String lists the flags as name=value, sorted by name
+func (f *Flags) String() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.values))
+	for name := range f.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, f.values[name]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// Require answers 404 while the flag is off, so disabled endpoints look absent
+func (f *Flags) Require(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.Enabled(name) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"message": "feature " + name + " is disabled"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,98 @@
+package featureflag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewDefaultsAndEnv(t *testing.T) {
+	t.Setenv("FEATURE_ETAG_CACHE", "off")
+
+	flags, err := New(Defaults())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if flags.Enabled(ETagCache) {
+		t.Error("Expected etag_cache to be disabled by the environment")
+	}
+	if !flags.Enabled(Callbacks) {
+		t.Error("Expected callbacks to keep its default")
+	}
+	if flags.Enabled("unknown") {
+		t.Error("Expected unknown flags to be disabled")
+	}
+}
+
+func TestFileOverridesEnvAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags")
+	os.WriteFile(path, []byte("# staging\nweather_stream=false\n"), 0o644)
+	t.Setenv(FileEnv, path)
+	t.Setenv("FEATURE_WEATHER_STREAM", "true")
+
+	flags, err := New(Defaults())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if flags.Enabled(WeatherStream) {
+		t.Error("Expected the file to override the environment")
+	}
+
+	os.WriteFile(path, []byte("weather_stream=on\n"), 0o644)
+	if err := flags.Reload(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !flags.Enabled(WeatherStream) {
+		t.Error("Expected weather_stream to be enabled after reload")
+	}
+}
+
+func TestReloadKeepsValuesOnError(t *testing.T) {
+	flags, err := New(Defaults())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Setenv("FEATURE_CALLBACKS", "maybe")
+	if err := flags.Reload(); err == nil {
+		t.Error("Expected an error for an invalid value")
+	}
+	if !flags.Enabled(Callbacks) {
+		t.Error("Expected the previous value to be kept")
+	}
+
+	path := filepath.Join(t.TempDir(), "flags")
+	os.WriteFile(path, []byte("batch=on\n"), 0o644)
+	t.Setenv("FEATURE_CALLBACKS", "on")
+	t.Setenv(FileEnv, path)
+	if err := flags.Reload(); err == nil {
+		t.Error("Expected an error for an unknown flag in the file")
+	}
+}
+
+func TestNilFlagsAreEnabled(t *testing.T) {
+	var flags *Flags
+	if !flags.Enabled(ETagCache) {
+		t.Error("Expected a nil *Flags to enable every flag")
+	}
+}
+
+func TestRequire(t *testing.T) {
+	t.Setenv("FEATURE_WEATHER_STREAM", "0")
+	flags, _ := New(Defaults())
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	rr := httptest.NewRecorder()
+	flags.Require(WeatherStream, ok).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a disabled feature, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	flags.Require(Callbacks, ok).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected 200 for an enabled feature, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Defaults tuned for a few upstream hosts called many times: the standard
+// library keeps only 2 idle connections per host, which forces new TCP and TLS
+// handshakes under load
+const (
+	DefaultTimeout             = 10 * time.Second
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 20
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultKeepAlive           = 30 * time.Second
+	DefaultDialTimeout         = 5 * time.Second
+)
+
+// Options holds the settings of a client built by New
+type Options struct {
+	// Timeout bounds each call, including reading the body (zero disables it)
+	Timeout             time.Duration
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// KeepAlive is the TCP keep-alive period (negative disables keep-alives)
+	KeepAlive   time.Duration
+	DialTimeout time.Duration
+	TLSConfig   *tls.Config
+}
+
+// Option customizes a client built by New
+type Option func(*Options)
+
+// WithTimeout sets the timeout of each call made with the client
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) { o.Timeout = timeout }
+}
+
+// WithMaxIdleConnsPerHost sets how many idle connections are kept per upstream host
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(o *Options) { o.MaxIdleConnsPerHost = n }
+}
+
+// WithIdleConnTimeout sets how long an idle connection stays in the pool
+func WithIdleConnTimeout(timeout time.Duration) Option {
+	return func(o *Options) { o.IdleConnTimeout = timeout }
+}
+
+// WithKeepAlive sets the TCP keep-alive period; a negative value disables keep-alives
+func WithKeepAlive(period time.Duration) Option {
+	return func(o *Options) { o.KeepAlive = period }
+}
+
+// WithTLSConfig sets the TLS configuration, e.g. a private CA or a client certificate
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *Options) { o.TLSConfig = tlsConfig }
+}
+
+// DefaultOptions returns the settings used when no option overrides them
+func DefaultOptions() Options {
+	return Options{
+		Timeout:             DefaultTimeout,
+		MaxIdleConns:        DefaultMaxIdleConns,
+		MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:     DefaultIdleConnTimeout,
+		KeepAlive:           DefaultKeepAlive,
+		DialTimeout:         DefaultDialTimeout,
+	}
+}
+
+// New returns a client with a pooled transport instrumented with otelhttp, so
+// every outgoing call gets a client span and propagates the trace context
+func New(opts ...Option) *http.Client {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &http.Client{
+		Transport: otelhttp.NewTransport(NewTransport(o)),
+		Timeout:   o.Timeout,
+	}
+}
+
+// NewTransport returns the uninstrumented transport configured by o
+func NewTransport(o Options) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   o.DialTimeout,
+		KeepAlive: o.KeepAlive,
+	}).DialContext
+	transport.MaxIdleConns = o.MaxIdleConns
+	transport.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = o.IdleConnTimeout
+	transport.DisableKeepAlives = o.KeepAlive < 0
+	if o.TLSConfig != nil {
+		transport.TLSClientConfig = o.TLSConfig
+	}
+	return transport
+}
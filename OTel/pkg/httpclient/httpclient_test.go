@@ -0,0 +1,73 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewTransportDefaults(t *testing.T) {
+	transport := NewTransport(DefaultOptions())
+
+	if transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Errorf("Expected %d idle connections per host, got %d", DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != DefaultIdleConnTimeout {
+		t.Errorf("Expected idle timeout %v, got %v", DefaultIdleConnTimeout, transport.IdleConnTimeout)
+	}
+	if transport.DisableKeepAlives {
+		t.Error("Expected keep-alives to be enabled")
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "orchestrator"}
+	client := New(WithTimeout(3*time.Second), WithTLSConfig(tlsConfig))
+
+	if client.Timeout != 3*time.Second {
+		t.Errorf("Expected timeout 3s, got %v", client.Timeout)
+	}
+
+	o := DefaultOptions()
+	WithKeepAlive(-1)(&o)
+	WithTLSConfig(tlsConfig)(&o)
+	transport := NewTransport(o)
+	if !transport.DisableKeepAlives {
+		t.Error("Expected keep-alives to be disabled")
+	}
+	if transport.TLSClientConfig != tlsConfig {
+		t.Error("Expected the TLS configuration to be used")
+	}
+}
+
+func TestNewPropagatesTraceContext(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := New().Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if traceparent == "" {
+		t.Error("Expected the traceparent header to be propagated")
+	}
+}
@@ -0,0 +1,32 @@
+package slo
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// ServeHTTP godoc
+// @Summary Objetivos de nível de serviço (SLO)
+// @Description Retorna a disponibilidade (respostas sem 5xx) e a latência (respostas bem-sucedidas dentro do limite)
+// @Description das rotas monitoradas na janela móvel, comparadas aos objetivos configurados e com o error budget restante
+// @Tags health
+// @Produce json
+// @Success 200 {object} slo.Report "Estado dos objetivos na janela"
+// @Failure 500 {object} map[string]string "Erro ao ler as métricas"
+// @Router /slo [get]
+func (c *Calculator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	report, err := c.Snapshot(r.Context())
+	if err != nil {
+		log.Printf("[SLO] Failed to read request metrics: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"message": "internal server error"})
+		return
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("[SLO] Error encoding report: %v", err)
+	}
+}
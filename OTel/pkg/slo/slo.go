@@ -0,0 +1,359 @@
+// Package slo computes rolling availability and latency SLIs from the
+// http.server.request.duration histogram recorded by otelmux, so each service
+// can report its objectives on GET /slo without a metrics backend.
+package slo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// MetricName is the otelmux request duration histogram, in seconds
+const MetricName = "http.server.request.duration"
+
+const (
+	routeKey  = attribute.Key("http.route")
+	statusKey = attribute.Key("http.response.status_code")
+)
+
+// Status values of an objective
+const (
+	StatusOK       = "ok"
+	StatusBreached = "breached"
+	StatusNoData   = "no_data"
+)
+
+var (
+	// ErrInvalidWindow is returned when SLO_WINDOW is not positive
+	ErrInvalidWindow = errors.New("SLO_WINDOW must be positive")
+
+	// ErrInvalidObjective is returned when SLO_AVAILABILITY_OBJECTIVE or SLO_LATENCY_OBJECTIVE is not in (0, 1)
+	ErrInvalidObjective = errors.New("SLO_AVAILABILITY_OBJECTIVE and SLO_LATENCY_OBJECTIVE must be between 0 and 1")
+
+	// ErrInvalidLatencyThreshold is returned when SLO_LATENCY_THRESHOLD is not positive
+	ErrInvalidLatencyThreshold = errors.New("SLO_LATENCY_THRESHOLD must be positive")
+)
+
+// defaultBoundaries are the otelmux histogram buckets, in seconds; the latency
+// threshold is added so requests below it can be counted exactly
+var defaultBoundaries = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// Config holds the objectives and the routes they apply to
+type Config struct {
+	// Window is the rolling period the SLIs are computed over
+	Window time.Duration
+	// AvailabilityObjective is the fraction of requests that must not fail with 5xx
+	AvailabilityObjective float64
+	// LatencyThreshold and LatencyObjective: that fraction of successful
+	// requests must finish within the threshold
+	LatencyThreshold time.Duration
+	LatencyObjective float64
+	// Routes are the mux path templates counted; streams and health checks are left out
+	Routes []string
+}
+
+// FromEnv reads SLO_WINDOW, SLO_AVAILABILITY_OBJECTIVE, SLO_LATENCY_THRESHOLD,
+// SLO_LATENCY_OBJECTIVE and SLO_ROUTES, using defaultRoutes when SLO_ROUTES is empty
+func FromEnv(defaultRoutes ...string) (Config, error) {
+	cfg := Config{
+		Window:                time.Hour,
+		AvailabilityObjective: 0.995,
+		LatencyThreshold:      500 * time.Millisecond,
+		LatencyObjective:      0.95,
+		Routes:                defaultRoutes,
+	}
+
+	var err error
+	if cfg.Window, err = durationEnv("SLO_WINDOW", cfg.Window); err != nil {
+		return Config{}, err
+	}
+	if cfg.LatencyThreshold, err = durationEnv("SLO_LATENCY_THRESHOLD", cfg.LatencyThreshold); err != nil {
+		return Config{}, err
+	}
+	if cfg.AvailabilityObjective, err = floatEnv("SLO_AVAILABILITY_OBJECTIVE", cfg.AvailabilityObjective); err != nil {
+		return Config{}, err
+	}
+	if cfg.LatencyObjective, err = floatEnv("SLO_LATENCY_OBJECTIVE", cfg.LatencyObjective); err != nil {
+		return Config{}, err
+	}
+	if value := os.Getenv("SLO_ROUTES"); value != "" {
+		cfg.Routes = nil
+		for _, route := range strings.Split(value, ",") {
+			if route = strings.TrimSpace(route); route != "" {
+				cfg.Routes = append(cfg.Routes, route)
+			}
+		}
+	}
+
+	if cfg.Window <= 0 {
+		return Config{}, ErrInvalidWindow
+	}
+	if cfg.LatencyThreshold <= 0 {
+		return Config{}, ErrInvalidLatencyThreshold
+	}
+	if !validObjective(cfg.AvailabilityObjective) || !validObjective(cfg.LatencyObjective) {
+		return Config{}, ErrInvalidObjective
+	}
+	return cfg, nil
+}
+
+func validObjective(objective float64) bool {
+	return objective > 0 && objective < 1
+}
+
+func durationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func floatEnv(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// RouteAttributes adds the mux path template as http.route to otelmux
+// metrics, via otelmux.WithMetricAttributesFn, so routes can be told apart
+func RouteAttributes(r *http.Request) []attribute.KeyValue {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return nil
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return nil
+	}
+	return []attribute.KeyValue{routeKey.String(template)}
+}
+
+// counts are cumulative request counts since the process started
+type counts struct {
+	total  uint64
+	errors uint64
+	// fast counts successful requests within the latency threshold
+	fast uint64
+}
+
+type snapshot struct {
+	at time.Time
+	counts
+}
+
+// Calculator reads the request histogram through its own metric reader and
+// keeps periodic snapshots of it, so the SLIs of any window are the
+// difference between the latest snapshot and the one taken a window earlier
+type Calculator struct {
+	cfg    Config
+	reader *sdkmetric.ManualReader
+	now    func() time.Time
+
+	mu        sync.Mutex
+	snapshots []snapshot
+}
+
+// New creates a calculator; its MeterProviderOptions must be given to the
+// global meter provider before otelmux records requests
+func New(cfg Config) *Calculator {
+	c := &Calculator{
+		cfg:    cfg,
+		reader: sdkmetric.NewManualReader(),
+		now:    time.Now,
+	}
+	c.snapshots = []snapshot{{at: c.now()}}
+	return c
+}
+
+// MeterProviderOptions registers the calculator's reader and a view adding
+// the latency threshold to the request histogram buckets
+func (c *Calculator) MeterProviderOptions() []sdkmetric.Option {
+	threshold := c.cfg.LatencyThreshold.Seconds()
+	boundaries := slices.Clone(defaultBoundaries)
+	if !slices.Contains(boundaries, threshold) {
+		boundaries = append(boundaries, threshold)
+		slices.Sort(boundaries)
+	}
+	view := sdkmetric.NewView(
+		sdkmetric.Instrument{Name: MetricName},
+		sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: boundaries}},
+	)
+	return []sdkmetric.Option{sdkmetric.WithReader(c.reader), sdkmetric.WithView(view)}
+}
+
+// Run takes a snapshot every 1/60 of the window until ctx is done, so the
+// start of the window is available even if /slo is not polled
+func (c *Calculator) Run(ctx context.Context) {
+	interval := max(c.cfg.Window/60, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.Snapshot(ctx); err != nil {
+				log.Printf("[SLO] Failed to read request metrics: %v", err)
+			}
+		}
+	}
+}
+
+// Snapshot collects the request histogram and stores its counts, dropping
+// snapshots no longer needed as the start of a window
+func (c *Calculator) Snapshot(ctx context.Context) (Report, error) {
+	var rm metricdata.ResourceMetrics
+	if err := c.reader.Collect(ctx, &rm); err != nil {
+		return Report{}, err
+	}
+	current := snapshot{at: c.now(), counts: c.count(rm)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.snapshots = append(c.snapshots, current)
+
+	// Keep the newest snapshot taken at or before the window start as baseline
+	windowStart := current.at.Add(-c.cfg.Window)
+	first := 0
+	for i, s := range c.snapshots {
+		if !s.at.After(windowStart) {
+			first = i
+		}
+	}
+	c.snapshots = c.snapshots[first:]
+
+	return c.report(c.snapshots[0], current), nil
+}
+
+// count sums the data points of the configured routes
+func (c *Calculator) count(rm metricdata.ResourceMetrics) counts {
+	var result counts
+	threshold := c.cfg.LatencyThreshold.Seconds()
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != MetricName {
+				continue
+			}
+			histogram, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok {
+				continue
+			}
+			for _, point := range histogram.DataPoints {
+				route, _ := point.Attributes.Value(routeKey)
+				if len(c.cfg.Routes) > 0 && !slices.Contains(c.cfg.Routes, route.AsString()) {
+					continue
+				}
+				result.total += point.Count
+				status, _ := point.Attributes.Value(statusKey)
+				if status.AsInt64() >= 500 {
+					result.errors += point.Count
+					continue
+				}
+				for i, bound := range point.Bounds {
+					if bound > threshold {
+						break
+					}
+					result.fast += point.BucketCounts[i]
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Objective reports one SLI against its objective
+type Objective struct {
+	Objective float64 `json:"objective" example:"0.995"`
+	// SLI is the fraction of good requests; 1 without requests
+	SLI   float64 `json:"sli" example:"0.9987"`
+	Good  uint64  `json:"good" example:"1498"`
+	Total uint64  `json:"total" example:"1500"`
+	// ErrorBudgetRemaining is the unspent fraction of the budget; negative once overspent
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining" example:"0.74"`
+	Status               string  `json:"status" example:"ok"`
+}
+
+// LatencyObjective is the latency SLI, counted over successful requests
+type LatencyObjective struct {
+	ThresholdMS float64 `json:"threshold_ms" example:"500"`
+	Objective
+}
+
+// Report is the SLO state of the rolling window
+type Report struct {
+	Window      string    `json:"window" example:"1h0m0s"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	Routes      []string  `json:"routes" example:"/weather/{cep}"`
+	// Status is breached if any objective is breached
+	Status       string           `json:"status" example:"ok"`
+	Availability Objective        `json:"availability"`
+	Latency      LatencyObjective `json:"latency"`
+}
+
+func (c *Calculator) report(start, end snapshot) Report {
+	total := end.total - start.total
+	failed := end.errors - start.errors
+	succeeded := total - failed
+	fast := end.fast - start.fast
+
+	report := Report{
+		Window:       c.cfg.Window.String(),
+		WindowStart:  start.at,
+		WindowEnd:    end.at,
+		Routes:       c.cfg.Routes,
+		Availability: objective(c.cfg.AvailabilityObjective, succeeded, total),
+		Latency: LatencyObjective{
+			ThresholdMS: float64(c.cfg.LatencyThreshold) / float64(time.Millisecond),
+			Objective:   objective(c.cfg.LatencyObjective, fast, succeeded),
+		},
+	}
+	switch {
+	case report.Availability.Status == StatusBreached || report.Latency.Status == StatusBreached:
+		report.Status = StatusBreached
+	case total == 0:
+		report.Status = StatusNoData
+	default:
+		report.Status = StatusOK
+	}
+	return report
+}
+
+func objective(target float64, good, total uint64) Objective {
+	o := Objective{Objective: target, SLI: 1, Good: good, Total: total, ErrorBudgetRemaining: 1, Status: StatusNoData}
+	if total == 0 {
+		return o
+	}
+	o.SLI = float64(good) / float64(total)
+	o.ErrorBudgetRemaining = 1 - (1-o.SLI)/(1-target)
+	o.Status = StatusOK
+	if o.SLI < target {
+		o.Status = StatusBreached
+	}
+	return o
+}
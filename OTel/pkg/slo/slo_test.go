@@ -0,0 +1,183 @@
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func testConfig() Config {
+	return Config{
+		Window:                time.Hour,
+		AvailabilityObjective: 0.9,
+		LatencyThreshold:      200 * time.Millisecond,
+		LatencyObjective:      0.5,
+		Routes:                []string{"/weather/{cep}"},
+	}
+}
+
+// newTestCalculator returns a calculator on a fake clock and the request histogram it reads
+func newTestCalculator(t *testing.T, cfg Config) (*Calculator, metric.Float64Histogram, *time.Time) {
+	t.Helper()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := New(cfg)
+	c.now = func() time.Time { return now }
+	c.snapshots = []snapshot{{at: now}}
+
+	provider := sdkmetric.NewMeterProvider(c.MeterProviderOptions()...)
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	histogram, err := provider.Meter("test").Float64Histogram(MetricName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, histogram, &now
+}
+
+func record(histogram metric.Float64Histogram, route string, status int, seconds float64, times int) {
+	attrs := metric.WithAttributes(routeKey.String(route), statusKey.Int(status))
+	for i := 0; i < times; i++ {
+		histogram.Record(context.Background(), seconds, attrs)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	cfg, err := FromEnv("/weather/{cep}")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Window != time.Hour || cfg.AvailabilityObjective != 0.995 || cfg.LatencyThreshold != 500*time.Millisecond ||
+		cfg.LatencyObjective != 0.95 || len(cfg.Routes) != 1 {
+		t.Errorf("Unexpected defaults: %+v", cfg)
+	}
+
+	t.Setenv("SLO_ROUTES", "/cep, /cep/full")
+	t.Setenv("SLO_WINDOW", "30m")
+	cfg, err = FromEnv("/weather/{cep}")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Window != 30*time.Minute || len(cfg.Routes) != 2 || cfg.Routes[1] != "/cep/full" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	t.Setenv("SLO_AVAILABILITY_OBJECTIVE", "1")
+	if _, err := FromEnv(); err != ErrInvalidObjective {
+		t.Errorf("Expected ErrInvalidObjective, got %v", err)
+	}
+	t.Setenv("SLO_AVAILABILITY_OBJECTIVE", "0.99")
+	t.Setenv("SLO_LATENCY_THRESHOLD", "0s")
+	if _, err := FromEnv(); err != ErrInvalidLatencyThreshold {
+		t.Errorf("Expected ErrInvalidLatencyThreshold, got %v", err)
+	}
+}
+
+func TestSnapshot_ComputesSLIs(t *testing.T) {
+	c, histogram, _ := newTestCalculator(t, testConfig())
+
+	record(histogram, "/weather/{cep}", 200, 0.1, 6)
+	record(histogram, "/weather/{cep}", 200, 0.2, 1) // exactly at the threshold
+	record(histogram, "/weather/{cep}", 404, 0.9, 1)
+	record(histogram, "/weather/{cep}", 502, 0.05, 2)
+	record(histogram, "/health", 500, 0.01, 5) // not monitored
+
+	report, err := c.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Availability.Total != 10 || report.Availability.Good != 8 {
+		t.Errorf("Expected 8 of 10 available, got %d of %d", report.Availability.Good, report.Availability.Total)
+	}
+	if report.Availability.Status != StatusBreached {
+		t.Errorf("Expected availability 0.8 to breach 0.9, got %s", report.Availability.Status)
+	}
+	if budget := report.Availability.ErrorBudgetRemaining; budget > -0.99 || budget < -1.01 {
+		t.Errorf("Expected the budget to be overspent by 100%%, got %v", budget)
+	}
+	if report.Latency.Total != 8 || report.Latency.Good != 7 {
+		t.Errorf("Expected 7 of 8 successful requests within 200ms, got %d of %d", report.Latency.Good, report.Latency.Total)
+	}
+	if report.Latency.Status != StatusOK || report.Latency.ThresholdMS != 200 {
+		t.Errorf("Unexpected latency objective: %+v", report.Latency)
+	}
+	if report.Status != StatusBreached {
+		t.Errorf("Expected the report to be breached, got %s", report.Status)
+	}
+}
+
+func TestSnapshot_RollingWindow(t *testing.T) {
+	c, histogram, now := newTestCalculator(t, testConfig())
+
+	report, _ := c.Snapshot(context.Background())
+	if report.Status != StatusNoData || report.Availability.SLI != 1 {
+		t.Errorf("Expected no data without requests, got %+v", report)
+	}
+
+	record(histogram, "/weather/{cep}", 500, 0.1, 4)
+	*now = now.Add(30 * time.Minute)
+	c.Snapshot(context.Background())
+
+	record(histogram, "/weather/{cep}", 200, 0.1, 10)
+	*now = now.Add(61 * time.Minute)
+	report, _ = c.Snapshot(context.Background())
+
+	// The failures happened before the snapshot that now starts the window
+	if report.Availability.Total != 10 || report.Availability.Good != 10 {
+		t.Errorf("Expected only the last 10 requests, got %d of %d", report.Availability.Good, report.Availability.Total)
+	}
+	if !report.WindowStart.Equal(now.Add(-61 * time.Minute)) {
+		t.Errorf("Expected the window to start at the snapshot 61m ago, got %v", report.WindowStart)
+	}
+	if len(c.snapshots) != 2 {
+		t.Errorf("Expected older snapshots to be dropped, got %d", len(c.snapshots))
+	}
+}
+
+func TestCalculator_WithOtelmux(t *testing.T) {
+	c := New(testConfig())
+	provider := sdkmetric.NewMeterProvider(c.MeterProviderOptions()...)
+	defer provider.Shutdown(context.Background())
+
+	r := mux.NewRouter()
+	r.Use(otelmux.Middleware("test", otelmux.WithMeterProvider(provider), otelmux.WithMetricAttributesFn(RouteAttributes)))
+	r.HandleFunc("/weather/{cep}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["cep"] == "00000000" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	r.Handle("/slo", c)
+
+	for _, cep := range []string{"01310100", "01310100", "01310100", "00000000"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/weather/"+cep, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slo", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Availability.Total != 4 || report.Availability.Good != 3 {
+		t.Errorf("Expected 3 of 4 requests available, got %d of %d", report.Availability.Good, report.Availability.Total)
+	}
+	if report.Latency.Good != 3 {
+		t.Errorf("Expected the 3 successful requests within the threshold, got %d", report.Latency.Good)
+	}
+}
+
+func TestRouteAttributes_WithoutRoute(t *testing.T) {
+	if attrs := RouteAttributes(httptest.NewRequest(http.MethodGet, "/", nil)); attrs != nil {
+		t.Errorf("Expected no attributes outside a mux route, got %v", attrs)
+	}
+}
@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Baggage keys propagated from the gateway to downstream services
+const (
+	BaggageCEP      = "cep"
+	BaggageClientID = "client.id"
+)
+
+// WithRequestBaggage stores the validated CEP and the client ID in the context baggage.
+// Empty values are skipped.
+func WithRequestBaggage(ctx context.Context, cep, clientID string) (context.Context, error) {
+	bag := baggage.FromContext(ctx)
+
+	for key, value := range map[string]string{BaggageCEP: cep, BaggageClientID: clientID} {
+		if value == "" {
+			continue
+		}
+		member, err := baggage.NewMember(key, value)
+		if err != nil {
+			return ctx, fmt.Errorf("invalid baggage member %s: %w", key, err)
+		}
+		bag, err = bag.SetMember(member)
+		if err != nil {
+			return ctx, fmt.Errorf("failed to set baggage member %s: %w", key, err)
+		}
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// RequestBaggage returns the CEP and client ID carried in the context baggage
+func RequestBaggage(ctx context.Context) (cep, clientID string) {
+	bag := baggage.FromContext(ctx)
+	return bag.Member(BaggageCEP).Value(), bag.Member(BaggageClientID).Value()
+}
+
+// baggageSpanProcessor copies the request baggage into the attributes of every span
+// started in the process, so spans can be filtered by CEP without re-parsing requests.
+type baggageSpanProcessor struct{}
+
+func (baggageSpanProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	cep, clientID := RequestBaggage(ctx)
+	if cep != "" {
+		span.SetAttributes(attribute.String("baggage."+BaggageCEP, cep))
+	}
+	if clientID != "" {
+		span.SetAttributes(attribute.String("baggage."+BaggageClientID, clientID))
+	}
+}
+
+func (baggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (baggageSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (baggageSpanProcessor) ForceFlush(context.Context) error { return nil }
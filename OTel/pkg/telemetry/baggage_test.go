@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRequestBaggage_RoundTrip(t *testing.T) {
+	ctx, err := WithRequestBaggage(context.Background(), "01310100", "mobile-app")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	cep, clientID := RequestBaggage(ctx)
+	if cep != "01310100" {
+		t.Errorf("Expected CEP 01310100, got %q", cep)
+	}
+	if clientID != "mobile-app" {
+		t.Errorf("Expected client ID mobile-app, got %q", clientID)
+	}
+}
+
+func TestRequestBaggage_SkipsEmptyClientID(t *testing.T) {
+	ctx, err := WithRequestBaggage(context.Background(), "01310100", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, clientID := RequestBaggage(ctx)
+	if clientID != "" {
+		t.Errorf("Expected empty client ID, got %q", clientID)
+	}
+}
+
+func TestBaggageSpanProcessor_CopiesBaggageToSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(baggageSpanProcessor{}),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+
+	ctx, _ := WithRequestBaggage(context.Background(), "01310100", "mobile-app")
+	_, span := tp.Tracer("test").Start(ctx, "child")
+	span.End()
+
+	attrs := map[string]string{}
+	for _, kv := range recorder.Ended()[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+
+	if attrs["baggage.cep"] != "01310100" {
+		t.Errorf("Expected baggage.cep attribute, got %v", attrs)
+	}
+	if attrs["baggage.client.id"] != "mobile-app" {
+		t.Errorf("Expected baggage.client.id attribute, got %v", attrs)
+	}
+}
@@ -0,0 +1,139 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Batch span processor defaults, matching the OpenTelemetry SDK
+const (
+	DefaultMaxQueueSize       = sdktrace.DefaultMaxQueueSize
+	DefaultMaxExportBatchSize = sdktrace.DefaultMaxExportBatchSize
+	DefaultBatchTimeout       = sdktrace.DefaultScheduleDelay * time.Millisecond
+	DefaultExportTimeout      = sdktrace.DefaultExportTimeout * time.Millisecond
+	// DefaultShutdownTimeout bounds the final flush when the service stops
+	DefaultShutdownTimeout = 5 * time.Second
+)
+
+// BatchOptions tunes the batch span processor. Spans beyond MaxQueueSize are
+// dropped, so high-traffic deployments should raise it along with MaxExportBatchSize.
+type BatchOptions struct {
+	// MaxQueueSize is how many ended spans may wait for export
+	MaxQueueSize int
+	// MaxExportBatchSize is the largest batch sent to the exporter at once
+	MaxExportBatchSize int
+	// BatchTimeout is the longest a span waits before its batch is sent
+	BatchTimeout time.Duration
+	// ExportTimeout bounds a single export call
+	ExportTimeout time.Duration
+	// ShutdownTimeout bounds flushing the queue when the service stops
+	ShutdownTimeout time.Duration
+}
+
+// DefaultBatchOptions returns the SDK defaults
+func DefaultBatchOptions() BatchOptions {
+	return BatchOptions{
+		MaxQueueSize:       DefaultMaxQueueSize,
+		MaxExportBatchSize: DefaultMaxExportBatchSize,
+		BatchTimeout:       DefaultBatchTimeout,
+		ExportTimeout:      DefaultExportTimeout,
+		ShutdownTimeout:    DefaultShutdownTimeout,
+	}
+}
+
+// BatchOptionsFromEnv reads TRACE_BATCH_MAX_QUEUE_SIZE, TRACE_BATCH_MAX_EXPORT_SIZE,
+// TRACE_BATCH_TIMEOUT, TRACE_EXPORT_TIMEOUT and TRACE_SHUTDOWN_TIMEOUT over the defaults
+func BatchOptionsFromEnv() (BatchOptions, error) {
+	opts := DefaultBatchOptions()
+	if err := envInt("TRACE_BATCH_MAX_QUEUE_SIZE", &opts.MaxQueueSize); err != nil {
+		return opts, err
+	}
+	if err := envInt("TRACE_BATCH_MAX_EXPORT_SIZE", &opts.MaxExportBatchSize); err != nil {
+		return opts, err
+	}
+	if err := envDuration("TRACE_BATCH_TIMEOUT", &opts.BatchTimeout); err != nil {
+		return opts, err
+	}
+	if err := envDuration("TRACE_EXPORT_TIMEOUT", &opts.ExportTimeout); err != nil {
+		return opts, err
+	}
+	if err := envDuration("TRACE_SHUTDOWN_TIMEOUT", &opts.ShutdownTimeout); err != nil {
+		return opts, err
+	}
+	return opts, opts.Validate()
+}
+
+// Validate checks that every setting is positive and batches fit in the queue
+func (o BatchOptions) Validate() error {
+	if o.MaxQueueSize <= 0 || o.MaxExportBatchSize <= 0 {
+		return errors.New("batch queue and export sizes must be positive")
+	}
+	if o.MaxExportBatchSize > o.MaxQueueSize {
+		return fmt.Errorf("max export batch size %d exceeds the queue size %d", o.MaxExportBatchSize, o.MaxQueueSize)
+	}
+	if o.BatchTimeout <= 0 || o.ExportTimeout <= 0 || o.ShutdownTimeout <= 0 {
+		return errors.New("batch, export and shutdown timeouts must be positive")
+	}
+	return nil
+}
+
+func (o BatchOptions) processorOptions() []sdktrace.BatchSpanProcessorOption {
+	return []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithMaxQueueSize(o.MaxQueueSize),
+		sdktrace.WithMaxExportBatchSize(o.MaxExportBatchSize),
+		sdktrace.WithBatchTimeout(o.BatchTimeout),
+		sdktrace.WithExportTimeout(o.ExportTimeout),
+	}
+}
+
+// shutdownWithFlush flushes queued spans and stops tp, giving up after timeout
+// so a stuck exporter cannot hold the process
+func shutdownWithFlush(tp *sdktrace.TracerProvider, timeout time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		start := time.Now()
+		if err := tp.ForceFlush(ctx); err != nil {
+			log.Printf("[TELEMETRY] Failed to flush spans before shutdown: %v", err)
+		}
+		if err := tp.Shutdown(ctx); err != nil {
+			return fmt.Errorf("tracer shutdown after %v: %w", time.Since(start), err)
+		}
+		log.Printf("[TELEMETRY] Spans flushed and tracer shut down in %v", time.Since(start))
+		return nil
+	}
+}
+
+func envInt(key string, target *int) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func envDuration(key string, target *time.Duration) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	*target = parsed
+	return nil
+}
@@ -0,0 +1,106 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestBatchOptionsFromEnv(t *testing.T) {
+	t.Setenv("TRACE_BATCH_MAX_QUEUE_SIZE", "8192")
+	t.Setenv("TRACE_BATCH_MAX_EXPORT_SIZE", "1024")
+	t.Setenv("TRACE_EXPORT_TIMEOUT", "10s")
+
+	opts, err := BatchOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if opts.MaxQueueSize != 8192 || opts.MaxExportBatchSize != 1024 || opts.ExportTimeout != 10*time.Second {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	if opts.BatchTimeout != DefaultBatchTimeout || opts.ShutdownTimeout != DefaultShutdownTimeout {
+		t.Errorf("Expected defaults for unset variables, got %+v", opts)
+	}
+}
+
+func TestBatchOptionsFromEnv_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"Non-numeric queue size", "TRACE_BATCH_MAX_QUEUE_SIZE", "lots"},
+		{"Zero queue size", "TRACE_BATCH_MAX_QUEUE_SIZE", "0"},
+		{"Batch larger than queue", "TRACE_BATCH_MAX_EXPORT_SIZE", "4096"},
+		{"Invalid timeout", "TRACE_BATCH_TIMEOUT", "soon"},
+		{"Negative shutdown timeout", "TRACE_SHUTDOWN_TIMEOUT", "-1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			if _, err := BatchOptionsFromEnv(); err == nil {
+				t.Errorf("Expected error for %s=%s, got nil", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+// recordingExporter keeps exported span names, even after shutdown
+type recordingExporter struct {
+	names []string
+}
+
+func (e *recordingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		e.names = append(e.names, span.Name())
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(ctx context.Context) error { return nil }
+
+func TestShutdownWithFlush_ExportsPendingSpans(t *testing.T) {
+	exporter := &recordingExporter{}
+	// A long batch timeout keeps spans queued until shutdown
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(time.Hour)))
+
+	_, span := tp.Tracer("test").Start(context.Background(), "pending")
+	span.End()
+
+	if err := shutdownWithFlush(tp, time.Second)(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(exporter.names) != 1 || exporter.names[0] != "pending" {
+		t.Errorf("Expected the pending span to be exported, got %v", exporter.names)
+	}
+}
+
+// blockingExporter never finishes an export until its context ends
+type blockingExporter struct{}
+
+func (blockingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingExporter) Shutdown(ctx context.Context) error { return nil }
+
+func TestShutdownWithFlush_RespectsDeadline(t *testing.T) {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(blockingExporter{}, sdktrace.WithBatchTimeout(time.Hour)))
+
+	_, span := tp.Tracer("test").Start(context.Background(), "stuck")
+	span.End()
+
+	start := time.Now()
+	err := shutdownWithFlush(tp, 100*time.Millisecond)(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected shutdown to give up after the deadline, took %v", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Span exporters accepted by TRACE_EXPORTER
+const (
+	ExporterZipkin = "zipkin"
+	ExporterOTLP   = "otlp"
+	ExporterJaeger = "jaeger"
+	ExporterStdout = "stdout"
+	ExporterNoop   = "noop"
+)
+
+// defaultJaegerEndpoint is Jaeger's OTLP/HTTP traces endpoint
+const defaultJaegerEndpoint = "http://localhost:4318/v1/traces"
+
+// NewExporter builds the span exporter for kind. The noop exporter returns a
+// nil exporter: spans are still created (trace IDs keep working) but dropped.
+//
+//   - zipkin sends to zipkinURL
+//   - otlp uses OTLP/HTTP configured by the standard OTEL_EXPORTER_OTLP_* variables
+//   - jaeger uses OTLP/HTTP to JAEGER_ENDPOINT, as Jaeger ingests OTLP natively
+//   - stdout pretty-prints spans to standard output
+func NewExporter(ctx context.Context, kind, zipkinURL string) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "", ExporterZipkin:
+		return zipkin.New(zipkinURL)
+	case ExporterOTLP:
+		return otlptracehttp.New(ctx)
+	case ExporterJaeger:
+		endpoint := os.Getenv("JAEGER_ENDPOINT")
+		if endpoint == "" {
+			endpoint = defaultJaegerEndpoint
+		}
+		return otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterNoop:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q (expected %s, %s, %s, %s or %s)",
+			kind, ExporterZipkin, ExporterOTLP, ExporterJaeger, ExporterStdout, ExporterNoop)
+	}
+}
@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewExporter(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        string
+		expectNil   bool
+		expectError bool
+	}{
+		{"Default is zipkin", "", false, false},
+		{"Zipkin", "zipkin", false, false},
+		{"OTLP", "otlp", false, false},
+		{"Jaeger", "Jaeger", false, false},
+		{"Stdout", "stdout", false, false},
+		{"Noop", "noop", true, false},
+		{"Unknown exporter", "datadog", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := NewExporter(context.Background(), tt.kind, "http://localhost:9411/api/v2/spans")
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for exporter %q, got nil", tt.kind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if (exporter == nil) != tt.expectNil {
+				t.Errorf("Expected nil exporter %v, got %v", tt.expectNil, exporter)
+			}
+			if exporter != nil {
+				exporter.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func TestInitTracer_Noop(t *testing.T) {
+	t.Setenv("TRACE_EXPORTER", "noop")
+
+	shutdown, err := InitTracer("test-service", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+}
+
+func TestInitTracer_UnknownExporter(t *testing.T) {
+	t.Setenv("TRACE_EXPORTER", "datadog")
+
+	if _, err := InitTracer("test-service", ""); err == nil {
+		t.Error("Expected error for unknown exporter, got nil")
+	}
+}
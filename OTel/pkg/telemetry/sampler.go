@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Sampling strategies accepted by TRACE_SAMPLER
+const (
+	SamplerAlways = "always"
+	SamplerNever  = "never"
+	SamplerRatio  = "ratio"
+)
+
+// defaultSamplePercentage is used by the ratio strategy when TRACE_SAMPLE_PERCENTAGE is not set
+const defaultSamplePercentage = 10.0
+
+// NewSampler builds a sampler for the given strategy.
+// The ratio strategy is parent-based, so a sampling decision taken upstream
+// (e.g. by the gateway) is honored by downstream services.
+func NewSampler(strategy string, percentage float64) (sdktrace.Sampler, error) {
+	switch strings.ToLower(strings.TrimSpace(strategy)) {
+	case "", SamplerAlways:
+		return sdktrace.AlwaysSample(), nil
+	case SamplerNever:
+		return sdktrace.NeverSample(), nil
+	case SamplerRatio:
+		if percentage < 0 || percentage > 100 {
+			return nil, fmt.Errorf("sample percentage must be between 0 and 100, got %v", percentage)
+		}
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(percentage / 100)), nil
+	default:
+		return nil, fmt.Errorf("unknown trace sampler %q (expected %s, %s or %s)",
+			strategy, SamplerAlways, SamplerNever, SamplerRatio)
+	}
+}
+
+// SamplerFromEnv builds a sampler from TRACE_SAMPLER and TRACE_SAMPLE_PERCENTAGE
+func SamplerFromEnv() (sdktrace.Sampler, error) {
+	percentage := defaultSamplePercentage
+	if value := os.Getenv("TRACE_SAMPLE_PERCENTAGE"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRACE_SAMPLE_PERCENTAGE %q: %w", value, err)
+		}
+		percentage = parsed
+	}
+
+	return NewSampler(os.Getenv("TRACE_SAMPLER"), percentage)
+}
+
+// reloadableSampler delegates to a sampler that can be swapped at runtime,
+// so the sampling strategy can change without recreating the tracer provider.
+type reloadableSampler struct {
+	mu      sync.RWMutex
+	sampler sdktrace.Sampler
+}
+
+// activeSampler is the sampler installed by InitTracer
+var activeSampler = &reloadableSampler{sampler: sdktrace.AlwaysSample()}
+
+func (s *reloadableSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sampler.ShouldSample(p)
+}
+
+func (s *reloadableSampler) Description() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sampler.Description()
+}
+
+func (s *reloadableSampler) set(sampler sdktrace.Sampler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sampler = sampler
+}
+
+// ReloadSampler rebuilds the sampler from the environment and swaps it into the
+// running tracer provider. On error the current sampler is kept.
+func ReloadSampler() error {
+	sampler, err := SamplerFromEnv()
+	if err != nil {
+		return err
+	}
+
+	activeSampler.set(sampler)
+	log.Printf("[TELEMETRY] Trace sampler reloaded: %s", sampler.Description())
+	return nil
+}
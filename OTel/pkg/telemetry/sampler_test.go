@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewSampler(t *testing.T) {
+	tests := []struct {
+		name        string
+		strategy    string
+		percentage  float64
+		expected    string
+		expectError bool
+	}{
+		{"Default strategy", "", 0, "AlwaysOnSampler", false},
+		{"Always", "always", 0, "AlwaysOnSampler", false},
+		{"Never", "NEVER", 0, "AlwaysOffSampler", false},
+		{"Ratio", "ratio", 25, "ParentBased{root:TraceIDRatioBased{0.25}", false},
+		{"Ratio above 100", "ratio", 150, "", true},
+		{"Negative ratio", "ratio", -1, "", true},
+		{"Unknown strategy", "sometimes", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampler, err := NewSampler(tt.strategy, tt.percentage)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for strategy %q, got nil", tt.strategy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got := sampler.Description(); !strings.HasPrefix(got, tt.expected) {
+				t.Errorf("Expected sampler %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSamplerFromEnv(t *testing.T) {
+	os.Setenv("TRACE_SAMPLER", "ratio")
+	os.Setenv("TRACE_SAMPLE_PERCENTAGE", "invalid")
+	defer os.Unsetenv("TRACE_SAMPLER")
+	defer os.Unsetenv("TRACE_SAMPLE_PERCENTAGE")
+
+	if _, err := SamplerFromEnv(); err == nil {
+		t.Error("Expected error for invalid TRACE_SAMPLE_PERCENTAGE")
+	}
+
+	os.Setenv("TRACE_SAMPLE_PERCENTAGE", "5")
+	sampler, err := SamplerFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(sampler.Description(), "ParentBased{root:TraceIDRatioBased{0.05}") {
+		t.Errorf("Unexpected sampler: %s", sampler.Description())
+	}
+}
+
+func TestReloadSampler(t *testing.T) {
+	os.Setenv("TRACE_SAMPLER", "never")
+	defer os.Unsetenv("TRACE_SAMPLER")
+	defer activeSampler.set(sdktrace.AlwaysSample())
+
+	if err := ReloadSampler(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := activeSampler.Description(); got != "AlwaysOffSampler" {
+		t.Errorf("Expected reloaded sampler AlwaysOffSampler, got %q", got)
+	}
+
+	os.Setenv("TRACE_SAMPLER", "sometimes")
+	if err := ReloadSampler(); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+	if got := activeSampler.Description(); got != "AlwaysOffSampler" {
+		t.Errorf("Expected previous sampler to be kept, got %q", got)
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/zipkin"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -14,15 +14,39 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-// InitTracer initializes OpenTelemetry tracing with Zipkin exporter
+// InitTracer initializes OpenTelemetry tracing with the exporter selected by
+// TRACE_EXPORTER (zipkin by default, sending to zipkinURL) and the batch
+// settings from BatchOptionsFromEnv. The returned function flushes pending
+// spans and shuts down within TRACE_SHUTDOWN_TIMEOUT.
 func InitTracer(serviceName, zipkinURL string) (func(context.Context) error, error) {
 	log.Printf("[TELEMETRY] Initializing OpenTelemetry tracer for service: %s", serviceName)
-	log.Printf("[TELEMETRY] Zipkin URL: %s", zipkinURL)
 
-	// Create Zipkin exporter
-	exporter, err := zipkin.New(zipkinURL)
+	// Select sampling strategy from environment
+	sampler, err := SamplerFromEnv()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Zipkin exporter: %w", err)
+		return nil, fmt.Errorf("failed to configure trace sampler: %w", err)
+	}
+	log.Printf("[TELEMETRY] Trace sampler: %s", sampler.Description())
+	activeSampler.set(sampler)
+
+	batchOpts, err := BatchOptionsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure batch span processor: %w", err)
+	}
+
+	// Create span exporter
+	exporterKind := os.Getenv("TRACE_EXPORTER")
+	exporter, err := NewExporter(context.Background(), exporterKind, zipkinURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	if exporterKind == "" {
+		exporterKind = ExporterZipkin
+	}
+	if exporterKind == ExporterZipkin {
+		log.Printf("[TELEMETRY] Trace exporter: zipkin (%s)", zipkinURL)
+	} else {
+		log.Printf("[TELEMETRY] Trace exporter: %s", exporterKind)
 	}
 
 	// Create resource with service information
@@ -33,11 +57,17 @@ func InitTracer(serviceName, zipkinURL string) (func(context.Context) error, err
 	)
 
 	// Create trace provider
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSpanProcessor(baggageSpanProcessor{}),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-	)
+		sdktrace.WithSampler(activeSampler),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter, batchOpts.processorOptions()...))
+		log.Printf("[TELEMETRY] Batch span processor: queue=%d batch=%d timeout=%v export_timeout=%v",
+			batchOpts.MaxQueueSize, batchOpts.MaxExportBatchSize, batchOpts.BatchTimeout, batchOpts.ExportTimeout)
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
 
 	// Set global trace provider
 	otel.SetTracerProvider(tp)
@@ -51,7 +81,7 @@ func InitTracer(serviceName, zipkinURL string) (func(context.Context) error, err
 	log.Printf("[TELEMETRY] OpenTelemetry tracer initialized successfully for %s", serviceName)
 
 	// Return shutdown function
-	return tp.Shutdown, nil
+	return shutdownWithFlush(tp, batchOpts.ShutdownTimeout), nil
 }
 
 // GetTracer returns a tracer for the given name
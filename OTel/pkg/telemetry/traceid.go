@@ -0,0 +1,31 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader is the response header carrying the current trace ID
+const TraceIDHeader = "X-Trace-Id"
+
+// TraceID returns the trace ID of the span in ctx, or an empty string when there is none
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// TraceIDMiddleware sets the X-Trace-Id response header so clients can quote it in bug reports.
+// It must run after the OpenTelemetry middleware that starts the server span.
+func TraceIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if traceID := TraceID(r.Context()); traceID != "" {
+			w.Header().Set(TraceIDHeader, traceID)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
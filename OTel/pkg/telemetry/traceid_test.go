@@ -0,0 +1,37 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestTraceIDMiddleware_SetsHeader(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(httptest.NewRequest("GET", "/", nil).Context(), "request")
+	defer span.End()
+
+	req := httptest.NewRequest("GET", "/health", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	TraceIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rr, req)
+
+	expected := span.SpanContext().TraceID().String()
+	if got := rr.Header().Get(TraceIDHeader); got != expected {
+		t.Errorf("Expected %s header %q, got %q", TraceIDHeader, expected, got)
+	}
+}
+
+func TestTraceIDMiddleware_NoSpan(t *testing.T) {
+	rr := httptest.NewRecorder()
+	TraceIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+
+	if got := rr.Header().Get(TraceIDHeader); got != "" {
+		t.Errorf("Expected no %s header, got %q", TraceIDHeader, got)
+	}
+}
@@ -1,6 +1,9 @@
 package temperature
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestConvertCelsiusToFahrenheit(t *testing.T) {
 	tests := []struct {
@@ -48,3 +51,28 @@ func TestConvertCelsiusToKelvin(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckCelsius(t *testing.T) {
+	tests := []struct {
+		name     string
+		celsius  float64
+		expected string
+	}{
+		{"Typical reading", 25, AnomalyNone},
+		{"Lower bound", MinPlausibleCelsius, AnomalyNone},
+		{"Upper bound", MaxPlausibleCelsius, AnomalyNone},
+		{"Too cold", -120, AnomalyBelowMin},
+		{"Kelvin sent as Celsius", 298, AnomalyAboveMax},
+		{"NaN", math.NaN(), AnomalyNotANumber},
+		{"Infinity", math.Inf(1), AnomalyNotANumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckCelsius(tt.celsius, MinPlausibleCelsius, MaxPlausibleCelsius)
+			if result != tt.expected {
+				t.Errorf("CheckCelsius(%v) = %q, want %q", tt.celsius, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package temperature
+
+import "math"
+
+// Plausible surface air temperature range in Celsius, slightly wider than the
+// recorded extremes on Earth (-89.2°C and 56.7°C)
+const (
+	MinPlausibleCelsius = -90.0
+	MaxPlausibleCelsius = 60.0
+)
+
+// Anomaly reasons reported by CheckCelsius
+const (
+	AnomalyNone       = ""
+	AnomalyNotANumber = "not_a_number"
+	AnomalyBelowMin   = "below_min"
+	AnomalyAboveMax   = "above_max"
+)
+
+// CheckCelsius returns why celsius falls outside [min, max], or AnomalyNone
+func CheckCelsius(celsius, min, max float64) string {
+	switch {
+	case math.IsNaN(celsius) || math.IsInf(celsius, 0):
+		return AnomalyNotANumber
+	case celsius < min:
+		return AnomalyBelowMin
+	case celsius > max:
+		return AnomalyAboveMax
+	}
+	return AnomalyNone
+}
@@ -0,0 +1,150 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testPKI writes a CA plus server and client certificates signed by it
+type testPKI struct {
+	caFile, serverCert, serverKey, clientCert, clientKey string
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "otel-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	pki := testPKI{caFile: filepath.Join(dir, "ca.crt")}
+	writePEM(t, pki.caFile, "CERTIFICATE", caDER)
+
+	issue := func(name string, serial int64, usage x509.ExtKeyUsage) (string, string) {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, _ := x509.MarshalECPrivateKey(key)
+
+		certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+		writePEM(t, certFile, "CERTIFICATE", der)
+		writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+		return certFile, keyFile
+	}
+
+	pki.serverCert, pki.serverKey = issue("server", 2, x509.ExtKeyUsageServerAuth)
+	pki.clientCert, pki.clientKey = issue("client", 3, x509.ExtKeyUsageClientAuth)
+	return pki
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+
+	serverCfg := Config{CertFile: pki.serverCert, KeyFile: pki.serverKey, ClientCAFile: pki.caFile}
+	serverTLS, err := serverCfg.ServerTLS()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = serverTLS
+	server.StartTLS()
+	defer server.Close()
+
+	call := func(cfg ClientConfig) error {
+		clientTLS, err := cfg.TLSConfig()
+		if err != nil {
+			t.Fatalf("Expected no error building client config, got %v", err)
+		}
+		// httptest serves its own certificate, so trust it alongside the test CA
+		clientTLS.RootCAs.AddCert(server.Certificate())
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	t.Run("client with certificate is accepted", func(t *testing.T) {
+		cfg := ClientConfig{CAFile: pki.caFile, CertFile: pki.clientCert, KeyFile: pki.clientKey}
+		if err := call(cfg); err != nil {
+			t.Errorf("Expected request to succeed, got %v", err)
+		}
+	})
+
+	t.Run("client without certificate is rejected", func(t *testing.T) {
+		cfg := ClientConfig{CAFile: pki.caFile}
+		if err := call(cfg); err == nil {
+			t.Error("Expected request without client certificate to fail")
+		}
+	})
+}
+
+func TestFromEnv_ClientCAWithoutTLS(t *testing.T) {
+	t.Setenv("TLS_CERT_FILE", "")
+	t.Setenv("TLS_KEY_FILE", "")
+	t.Setenv("TLS_CLIENT_CA_FILE", "/certs/ca.crt")
+
+	if _, err := FromEnv(); err != ErrClientCAWithoutTLS {
+		t.Errorf("Expected ErrClientCAWithoutTLS, got %v", err)
+	}
+}
+
+func TestClientFromEnv(t *testing.T) {
+	t.Setenv("ORCHESTRATION_CA_FILE", "/certs/ca.crt")
+	t.Setenv("ORCHESTRATION_CLIENT_CERT_FILE", "/certs/client.crt")
+	t.Setenv("ORCHESTRATION_CLIENT_KEY_FILE", "")
+
+	if _, err := ClientFromEnv("ORCHESTRATION"); err == nil {
+		t.Error("Expected error for client certificate without key")
+	}
+}
@@ -0,0 +1,195 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+)
+
+var (
+	// ErrIncompleteKeyPair is returned when only one of TLS_CERT_FILE and TLS_KEY_FILE is set
+	ErrIncompleteKeyPair = errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+
+	// ErrClientCAWithoutTLS is returned when client certificate verification is requested without TLS
+	ErrClientCAWithoutTLS = errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+)
+
+// Config holds the optional TLS settings of an HTTP server
+type Config struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables mutual TLS: clients must present a certificate signed by this CA
+	ClientCAFile string
+	// RedirectPort is the plain HTTP port redirected to HTTPS (empty disables the redirect)
+	RedirectPort string
+}
+
+// FromEnv reads TLS_CERT_FILE, TLS_KEY_FILE, TLS_CLIENT_CA_FILE and TLS_REDIRECT_PORT
+func FromEnv() (Config, error) {
+	cfg := Config{
+		CertFile:     os.Getenv("TLS_CERT_FILE"),
+		KeyFile:      os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+		RedirectPort: os.Getenv("TLS_REDIRECT_PORT"),
+	}
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return Config{}, ErrIncompleteKeyPair
+	}
+	if cfg.ClientCAFile != "" && !cfg.Enabled() {
+		return Config{}, ErrClientCAWithoutTLS
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether a certificate and key were configured
+func (c Config) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// ServerTLSConfig returns a server configuration restricted to TLS 1.2+ with
+// forward-secret AEAD cipher suites (TLS 1.3 suites are not configurable and always secure)
+func ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+}
+
+// ServerTLS returns the server configuration, requiring and verifying client
+// certificates when ClientCAFile is set
+func (c Config) ServerTLS() (*tls.Config, error) {
+	cfg := ServerTLSConfig()
+	if c.ClientCAFile == "" {
+		return cfg, nil
+	}
+
+	pool, err := loadCertPool(c.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return cfg, nil
+}
+
+// ListenAndServe starts srv over HTTPS when TLS is enabled, or plain HTTP otherwise
+func (c Config) ListenAndServe(srv *http.Server) error {
+	if !c.Enabled() {
+		return srv.ListenAndServe()
+	}
+
+	if srv.TLSConfig == nil {
+		cfg, err := c.ServerTLS()
+		if err != nil {
+			return err
+		}
+		srv.TLSConfig = cfg
+	}
+	return srv.ListenAndServeTLS(c.CertFile, c.KeyFile)
+}
+
+// RedirectServer returns a server on RedirectPort that redirects every request
+// to HTTPS on httpsPort, or nil when TLS or the redirect is disabled
+func (c Config) RedirectServer(httpsPort string) *http.Server {
+	if !c.Enabled() || c.RedirectPort == "" {
+		return nil
+	}
+
+	return &http.Server{
+		Addr:    ":" + c.RedirectPort,
+		Handler: RedirectHandler(httpsPort),
+	}
+}
+
+// RedirectHandler permanently redirects requests to the same host and path over HTTPS
+func RedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
+
+// ClientConfig holds the TLS settings used to call an internal service
+type ClientConfig struct {
+	// CAFile verifies the server certificate (system roots when empty)
+	CAFile string
+	// CertFile and KeyFile are the client certificate presented for mutual TLS
+	CertFile string
+	KeyFile  string
+}
+
+// ClientFromEnv reads <prefix>_CA_FILE, <prefix>_CLIENT_CERT_FILE and <prefix>_CLIENT_KEY_FILE
+func ClientFromEnv(prefix string) (ClientConfig, error) {
+	cfg := ClientConfig{
+		CAFile:   os.Getenv(prefix + "_CA_FILE"),
+		CertFile: os.Getenv(prefix + "_CLIENT_CERT_FILE"),
+		KeyFile:  os.Getenv(prefix + "_CLIENT_KEY_FILE"),
+	}
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return ClientConfig{}, fmt.Errorf("%s_CLIENT_CERT_FILE and %s_CLIENT_KEY_FILE must be set together", prefix, prefix)
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether any client TLS setting was configured
+func (c ClientConfig) Enabled() bool {
+	return c.CAFile != "" || c.CertFile != ""
+}
+
+// TLSConfig builds the client configuration
+func (c ClientConfig) TLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
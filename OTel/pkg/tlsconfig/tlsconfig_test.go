@@ -0,0 +1,77 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("TLS_CERT_FILE", "/certs/server.crt")
+	t.Setenv("TLS_KEY_FILE", "")
+
+	if _, err := FromEnv(); err != ErrIncompleteKeyPair {
+		t.Errorf("Expected ErrIncompleteKeyPair, got %v", err)
+	}
+
+	t.Setenv("TLS_KEY_FILE", "/certs/server.key")
+	cfg, err := FromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Enabled() {
+		t.Error("Expected TLS to be enabled")
+	}
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	cfg := ServerTLSConfig()
+
+	if cfg.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected minimum version TLS 1.2, got %x", cfg.MinVersion)
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		for _, id := range cfg.CipherSuites {
+			if id == suite.ID {
+				t.Errorf("Insecure cipher suite enabled: %s", suite.Name)
+			}
+		}
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		host      string
+		expected  string
+	}{
+		{"Custom port", "8443", "localhost:8080", "https://localhost:8443/weather/01310100?x=1"},
+		{"Default HTTPS port", "443", "example.com", "https://example.com/weather/01310100?x=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather/01310100?x=1", nil)
+			req.Host = tt.host
+			rr := httptest.NewRecorder()
+
+			RedirectHandler(tt.httpsPort).ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusMovedPermanently {
+				t.Errorf("Expected status %d, got %d", http.StatusMovedPermanently, rr.Code)
+			}
+			if got := rr.Header().Get("Location"); got != tt.expected {
+				t.Errorf("Expected Location %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRedirectServer_DisabledWithoutTLS(t *testing.T) {
+	cfg := Config{RedirectPort: "8080"}
+	if cfg.RedirectServer("8443") != nil {
+		t.Error("Expected no redirect server when TLS is disabled")
+	}
+}
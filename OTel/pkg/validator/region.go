@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	// ErrInvalidCEPFormat is returned when the CEP does not have 8 digits
+	ErrInvalidCEPFormat = errors.New("invalid CEP format")
+
+	// ErrUnassignedCEPRange is returned when no state uses the CEP prefix
+	ErrUnassignedCEPRange = errors.New("CEP prefix is not assigned to any state")
+)
+
+// Regiões do IBGE
+const (
+	RegionNorte       = "Norte"
+	RegionNordeste    = "Nordeste"
+	RegionCentroOeste = "Centro-Oeste"
+	RegionSudeste     = "Sudeste"
+	RegionSul         = "Sul"
+)
+
+// CEPInfo holds the state and region inferred from a CEP prefix
+type CEPInfo struct {
+	CEP    string
+	UF     string
+	Region string
+}
+
+// cepRange maps an inclusive range of 5-digit CEP prefixes to a state
+type cepRange struct {
+	from, to int
+	uf       string
+}
+
+// cepRanges follows the Correios prefix table, sorted by prefix
+var cepRanges = []cepRange{
+	{1000, 19999, "SP"},
+	{20000, 28999, "RJ"},
+	{29000, 29999, "ES"},
+	{30000, 39999, "MG"},
+	{40000, 48999, "BA"},
+	{49000, 49999, "SE"},
+	{50000, 56999, "PE"},
+	{57000, 57999, "AL"},
+	{58000, 58999, "PB"},
+	{59000, 59999, "RN"},
+	{60000, 63999, "CE"},
+	{64000, 64999, "PI"},
+	{65000, 65999, "MA"},
+	{66000, 68899, "PA"},
+	{68900, 68999, "AP"},
+	{69000, 69299, "AM"},
+	{69300, 69399, "RR"},
+	{69400, 69899, "AM"},
+	{69900, 69999, "AC"},
+	{70000, 72799, "DF"},
+	{72800, 72999, "GO"},
+	{73000, 73699, "DF"},
+	{73700, 76799, "GO"},
+	{76800, 76999, "RO"},
+	{77000, 77999, "TO"},
+	{78000, 78899, "MT"},
+	{78900, 78999, "RO"},
+	{79000, 79999, "MS"},
+	{80000, 87999, "PR"},
+	{88000, 89999, "SC"},
+	{90000, 99999, "RS"},
+}
+
+var ufRegions = map[string]string{
+	"AC": RegionNorte, "AP": RegionNorte, "AM": RegionNorte, "PA": RegionNorte,
+	"RO": RegionNorte, "RR": RegionNorte, "TO": RegionNorte,
+	"AL": RegionNordeste, "BA": RegionNordeste, "CE": RegionNordeste, "MA": RegionNordeste,
+	"PB": RegionNordeste, "PE": RegionNordeste, "PI": RegionNordeste, "RN": RegionNordeste,
+	"SE": RegionNordeste,
+	"DF": RegionCentroOeste, "GO": RegionCentroOeste, "MT": RegionCentroOeste, "MS": RegionCentroOeste,
+	"ES": RegionSudeste, "MG": RegionSudeste, "RJ": RegionSudeste, "SP": RegionSudeste,
+	"PR": RegionSul, "RS": RegionSul, "SC": RegionSul,
+}
+
+// ValidateCEPWithInfo validates the CEP and infers its state and region from the prefix
+func ValidateCEPWithInfo(cep string) (CEPInfo, error) {
+	if !ValidateCEP(cep) {
+		return CEPInfo{}, ErrInvalidCEPFormat
+	}
+
+	cleaned := CleanCEP(cep)
+	prefix, _ := strconv.Atoi(cleaned[:5])
+	for _, r := range cepRanges {
+		if prefix < r.from {
+			break
+		}
+		if prefix <= r.to {
+			return CEPInfo{CEP: cleaned, UF: r.uf, Region: ufRegions[r.uf]}, nil
+		}
+	}
+	return CEPInfo{CEP: cleaned}, ErrUnassignedCEPRange
+}
@@ -0,0 +1,52 @@
+package validator
+
+import "testing"
+
+func TestValidateCEPWithInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		cep    string
+		uf     string
+		region string
+		err    error
+	}{
+		{"São Paulo", "01310-100", "SP", RegionSudeste, nil},
+		{"Rio de Janeiro", "20040020", "RJ", RegionSudeste, nil},
+		{"Salvador", "40010000", "BA", RegionNordeste, nil},
+		{"Boa Vista", "69301000", "RR", RegionNorte, nil},
+		{"Manaus", "69005000", "AM", RegionNorte, nil},
+		{"Brasília", "70040010", "DF", RegionCentroOeste, nil},
+		{"Luziânia", "72800000", "GO", RegionCentroOeste, nil},
+		{"Porto Alegre", "90010000", "RS", RegionSul, nil},
+		{"Unassigned prefix", "00999999", "", "", ErrUnassignedCEPRange},
+		{"Invalid format", "0131010A", "", "", ErrInvalidCEPFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ValidateCEPWithInfo(tt.cep)
+			if err != tt.err {
+				t.Fatalf("ValidateCEPWithInfo(%q) error = %v, want %v", tt.cep, err, tt.err)
+			}
+			if info.UF != tt.uf || info.Region != tt.region {
+				t.Errorf("ValidateCEPWithInfo(%q) = %+v, want UF %q and region %q", tt.cep, info, tt.uf, tt.region)
+			}
+		})
+	}
+}
+
+func TestCEPRangesCoverAllStates(t *testing.T) {
+	seen := map[string]bool{}
+	for i, r := range cepRanges {
+		if r.from > r.to || (i > 0 && r.from <= cepRanges[i-1].to) {
+			t.Errorf("Range %d (%05d-%05d) is unsorted or overlapping", i, r.from, r.to)
+		}
+		if _, ok := ufRegions[r.uf]; !ok {
+			t.Errorf("State %s has no region", r.uf)
+		}
+		seen[r.uf] = true
+	}
+	if len(seen) != 27 {
+		t.Errorf("Expected 27 federative units, got %d", len(seen))
+	}
+}
@@ -2,17 +2,31 @@ package main
 
 import (
 	"auctionService/configuration/database/mongodb"
+	"auctionService/internal/entity/currency_entity"
+	"auctionService/internal/entity/moderation_entity"
 	"auctionService/internal/infra/api/web/controller/auction_controller"
 	"auctionService/internal/infra/api/web/controller/bid_controller"
+	"auctionService/internal/infra/api/web/controller/moderation_controller"
+	"auctionService/internal/infra/api/web/controller/privacy_controller"
+	"auctionService/internal/infra/api/web/controller/seller_controller"
 	"auctionService/internal/infra/api/web/controller/user_controller"
+	"auctionService/internal/infra/content_validation"
 	"auctionService/internal/infra/database/auction"
 	"auctionService/internal/infra/database/bid"
+	"auctionService/internal/infra/database/ledger"
+	"auctionService/internal/infra/database/moderation"
+	"auctionService/internal/infra/database/privacy"
 	"auctionService/internal/infra/database/user"
+	"auctionService/internal/infra/exchange_rate"
 	"auctionService/internal/usecase/auction_usecase"
 	"auctionService/internal/usecase/bid_usecase"
+	"auctionService/internal/usecase/moderation_usecase"
+	"auctionService/internal/usecase/privacy_usecase"
+	"auctionService/internal/usecase/seller_usecase"
 	"auctionService/internal/usecase/user_usecase"
 	"context"
 	"log"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -35,15 +49,23 @@ func main() {
 
 	router := gin.Default()
 
-	userController, bidController, auctionsController := initDependencies(databaseConnection)
+	userController, bidController, auctionsController, moderationController, sellerController, privacyController := initDependencies(databaseConnection)
 
 	router.GET("/auction", auctionsController.FindAuctions)
+	router.GET("/auction/facets", auctionsController.FindAuctionFacets)
 	router.GET("/auction/:auctionId", auctionsController.FindAuctionById)
 	router.POST("/auction", auctionsController.CreateAuction)
 	router.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
 	router.POST("/bid", bidController.CreateBid)
 	router.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
+	router.GET("/admin/bid-increments", bidController.FindBidIncrements)
+	router.PUT("/admin/bid-increments", bidController.UpdateBidIncrements)
 	router.GET("/user/:userId", userController.FindUserById)
+	router.GET("/user/:userId/export", privacyController.ExportUserData)
+	router.POST("/user/:userId/erasure", privacyController.EraseUserData)
+	router.GET("/privacy/requests", privacyController.FindPrivacyRequests)
+	router.GET("/moderation/decisions", moderationController.FindDecisions)
+	router.GET("/sellers/:sellerId/dashboard", sellerController.FindSellerDashboard)
 
 	router.Run(":8080")
 }
@@ -51,17 +73,47 @@ func main() {
 func initDependencies(database *mongo.Database) (
 	userController *user_controller.UserController,
 	bidController *bid_controller.BidController,
-	auctionController *auction_controller.AuctionController) {
+	auctionController *auction_controller.AuctionController,
+	moderationController *moderation_controller.ModerationController,
+	sellerController *seller_controller.SellerController,
+	privacyController *privacy_controller.PrivacyController) {
 
+	ledgerRepository := ledger.NewLedgerRepository(database)
 	auctionRepository := auction.NewAuctionRepository(database)
+	auctionRepository.Ledger = ledgerRepository
 	bidRepository := bid.NewBidRepository(database, auctionRepository)
+	bidRepository.Ledger = ledgerRepository
+	incrementTableRepository := bid.NewIncrementTableRepository(database)
 	userRepository := user.NewUserRepository(database)
+	decisionRepository := moderation.NewDecisionRepository(database)
+	privacyRequestRepository := privacy.NewPrivacyRequestRepository(database)
+	contentValidator := moderation_entity.NewPipeline(
+		content_validation.NewWordlistValidatorFromEnv())
 
+	// Conversão de moedas e snapshot da cotação no encerramento, apenas com o
+	// serviço de cotação configurado
+	var rateProvider currency_entity.RateProvider
+	if quoteService := exchange_rate.NewQuoteServiceProviderFromEnv(); quoteService != nil {
+		rateProvider = quoteService
+		auctionRepository.Rates = quoteService
+		auctionRepository.SettlementCurrency = currency_entity.Normalize(os.Getenv("SETTLEMENT_CURRENCY"))
+		if err := currency_entity.Validate(auctionRepository.SettlementCurrency); err != nil {
+			log.Fatal("Invalid SETTLEMENT_CURRENCY: " + err.Message)
+		}
+	}
+
+	bidUseCase := bid_usecase.NewBidUseCase(bidRepository, incrementTableRepository)
 	userController = user_controller.NewUserController(
 		user_usecase.NewUserUseCase(userRepository))
 	auctionController = auction_controller.NewAuctionController(
-		auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository))
-	bidController = bid_controller.NewBidController(bid_usecase.NewBidUseCase(bidRepository))
+		auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository, contentValidator, decisionRepository, incrementTableRepository, bidUseCase, rateProvider))
+	bidController = bid_controller.NewBidController(bidUseCase)
+	moderationController = moderation_controller.NewModerationController(
+		moderation_usecase.NewModerationUseCase(decisionRepository))
+	sellerController = seller_controller.NewSellerController(
+		seller_usecase.NewSellerUseCase(auctionRepository, seller_usecase.GetDashboardCacheTTL()))
+	privacyController = privacy_controller.NewPrivacyController(
+		privacy_usecase.NewPrivacyUseCase(userRepository, auctionRepository, bidRepository, ledgerRepository, privacyRequestRepository, bidUseCase))
 
 	return
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"auctionService/configuration/database/mongodb"
+	"auctionService/internal/infra/database/auction"
+	"auctionService/internal/infra/database/bid"
+	"auctionService/internal/infra/database/ledger"
+	"auctionService/internal/usecase/ledger_usecase"
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// replay reconstrói o estado dos leilões a partir do ledger e lista as
+// divergências com as coleções atuais. Com -apply, corrige status e maior lance.
+// Sai com código 1 quando restam divergências, para uso em auditorias agendadas.
+func main() {
+	auctionId := flag.String("auction", "", "id do leilão (vazio processa todos)")
+	apply := flag.Bool("apply", false, "corrige status e maior lance divergentes")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	if err := godotenv.Load("cmd/auction/.env"); err != nil {
+		log.Fatal("Error trying to load env variables")
+		return
+	}
+
+	database, err := mongodb.NewMongoDBConnection(ctx)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	auctionRepository := auction.NewAuctionRepository(database)
+	ledgerUseCase := ledger_usecase.NewLedgerUseCase(
+		ledger.NewLedgerRepository(database),
+		auctionRepository,
+		bid.NewBidRepository(database, auctionRepository))
+
+	replay := ledgerUseCase.ReplayLedger
+	if *apply {
+		replay = ledgerUseCase.RestoreFromLedger
+	}
+
+	states, replayErr := replay(ctx, *auctionId)
+	if replayErr != nil {
+		log.Fatal(replayErr.Error())
+		return
+	}
+
+	pending := 0
+	encoder := json.NewEncoder(os.Stdout)
+	for _, state := range states {
+		if len(state.Discrepancies) > 0 && !state.Restored {
+			pending++
+		}
+		encoder.Encode(state)
+	}
+
+	log.Printf("Replayed %d auctions, %d with unresolved discrepancies", len(states), pending)
+	if pending > 0 {
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,8 @@
 package auction_entity
 
 import (
+	"auctionService/internal/entity/currency_entity"
+	"auctionService/internal/entity/moderation_entity"
 	"auctionService/internal/internal_error"
 	"context"
 	"time"
@@ -9,14 +11,16 @@ import (
 )
 
 func CreateAuction(
-	productName, category, description string,
-	condition ProductCondition) (*Auction, *internal_error.InternalError) {
+	sellerId, productName, category, description string,
+	condition ProductCondition, currency string) (*Auction, *internal_error.InternalError) {
 	auction := &Auction{
 		Id:          uuid.New().String(),
+		SellerId:    sellerId,
 		ProductName: productName,
 		Category:    category,
 		Description: description,
 		Condition:   condition,
+		Currency:    currency_entity.Normalize(currency),
 		Status:      Active,
 		Timestamp:   time.Now(),
 	}
@@ -37,17 +41,73 @@ func (au *Auction) Validate() *internal_error.InternalError {
 		return internal_error.NewBadRequestError("invalid auction object")
 	}
 
-	return nil
+	if au.SellerId != "" {
+		if err := uuid.Validate(au.SellerId); err != nil {
+			return internal_error.NewBadRequestError("SellerId is not a valid id")
+		}
+	}
+
+	return currency_entity.Validate(au.Currency)
 }
 
 type Auction struct {
 	Id          string
+	SellerId    string
 	ProductName string
 	Category    string
 	Description string
 	Condition   ProductCondition
-	Status      AuctionStatus
-	Timestamp   time.Time
+	// Currency é a moeda dos lances do leilão
+	Currency   string
+	Status     AuctionStatus
+	Moderation moderation_entity.Verdict
+	Timestamp  time.Time
+	// SettlementRate é a cotação de Currency para a moeda de liquidação gravada
+	// no encerramento, para auditoria; nil enquanto ativo ou sem provedor
+	SettlementRate *currency_entity.Rate
+}
+
+type FacetCount struct {
+	Value string
+	Count int64
+}
+
+type AuctionFacets struct {
+	Categories   []FacetCount
+	Conditions   []FacetCount
+	PriceBuckets []FacetCount
+}
+
+type RevenueInterval string
+
+const (
+	RevenueByDay   RevenueInterval = "day"
+	RevenueByWeek  RevenueInterval = "week"
+	RevenueByMonth RevenueInterval = "month"
+)
+
+type RevenueBucket struct {
+	Start    time.Time
+	Revenue  float64
+	Auctions int64
+}
+
+// SellerDashboard resume os leilões de um vendedor. Um leilão concluído com
+// ao menos um lance conta como vendido pelo maior lance.
+type SellerDashboard struct {
+	ActiveAuctions    int64
+	CompletedAuctions int64
+	SoldAuctions      int64
+	TotalBids         int64
+	Revenue           []RevenueBucket
+}
+
+// ConversionRate é a fração dos leilões concluídos que terminaram vendidos.
+func (d *SellerDashboard) ConversionRate() float64 {
+	if d.CompletedAuctions == 0 {
+		return 0
+	}
+	return float64(d.SoldAuctions) / float64(d.CompletedAuctions)
 }
 
 type ProductCondition int
@@ -75,4 +135,22 @@ type AuctionRepositoryInterface interface {
 
 	FindAuctionById(
 		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
+
+	FindAuctionFacets(
+		ctx context.Context, query string) (*AuctionFacets, *internal_error.InternalError)
+
+	FindSellerDashboard(
+		ctx context.Context,
+		sellerId string,
+		interval RevenueInterval) (*SellerDashboard, *internal_error.InternalError)
+
+	RestoreAuctionStatus(
+		ctx context.Context, auctionId string, status AuctionStatus) *internal_error.InternalError
+
+	FindAuctionsBySellerId(
+		ctx context.Context, sellerId string) ([]Auction, *internal_error.InternalError)
+
+	// AnonymizeSeller troca o vendedor dos leilões por pseudonym e retorna quantos foram alterados
+	AnonymizeSeller(
+		ctx context.Context, sellerId, pseudonym string) (int64, *internal_error.InternalError)
 }
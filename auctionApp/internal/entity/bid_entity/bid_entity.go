@@ -54,4 +54,15 @@ type BidEntityRepository interface {
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	RestoreBid(
+		ctx context.Context, bid Bid) *internal_error.InternalError
+
+	FindBidsByUserId(
+		ctx context.Context, userId string) ([]Bid, *internal_error.InternalError)
+
+	// AnonymizeBidsByUserId troca o usuário dos lances por pseudonym, mantendo
+	// valores e horários, e retorna quantos lances foram alterados
+	AnonymizeBidsByUserId(
+		ctx context.Context, userId, pseudonym string) (int64, *internal_error.InternalError)
 }
@@ -0,0 +1,78 @@
+package bid_entity
+
+import (
+	"auctionService/internal/internal_error"
+	"context"
+	"fmt"
+	"math"
+)
+
+// IncrementBand é o incremento mínimo exigido enquanto o maior lance está
+// abaixo de UpTo. A última faixa não tem limite e usa UpTo 0.
+type IncrementBand struct {
+	UpTo      float64
+	Increment float64
+}
+
+// IncrementTable são as faixas de incremento em ordem crescente de UpTo
+type IncrementTable []IncrementBand
+
+// DefaultIncrementTable vale enquanto nenhuma tabela foi cadastrada
+var DefaultIncrementTable = IncrementTable{
+	{UpTo: 100, Increment: 1},
+	{UpTo: 1000, Increment: 5},
+	{UpTo: 0, Increment: 10},
+}
+
+func (t IncrementTable) Validate() *internal_error.InternalError {
+	if len(t) == 0 {
+		return internal_error.NewBadRequestError("bid increment table must have at least one band")
+	}
+
+	for i, band := range t {
+		if band.Increment <= 0 {
+			return internal_error.NewBadRequestError(
+				fmt.Sprintf("band %d: increment must be positive", i))
+		}
+
+		last := i == len(t)-1
+		if last && band.UpTo != 0 {
+			return internal_error.NewBadRequestError("the last band must be unbounded (up_to 0)")
+		}
+		if !last && band.UpTo <= 0 {
+			return internal_error.NewBadRequestError(
+				fmt.Sprintf("band %d: only the last band can be unbounded", i))
+		}
+		if !last && i > 0 && band.UpTo <= t[i-1].UpTo {
+			return internal_error.NewBadRequestError(
+				fmt.Sprintf("band %d: up_to must be greater than the previous band", i))
+		}
+	}
+
+	return nil
+}
+
+// IncrementFor retorna o incremento da faixa em que currentBid se encontra
+func (t IncrementTable) IncrementFor(currentBid float64) float64 {
+	for _, band := range t {
+		if band.UpTo == 0 || currentBid < band.UpTo {
+			return band.Increment
+		}
+	}
+	return 0
+}
+
+// MinimumNextBid é o menor lance aceito sobre currentBid (0 se não há lances),
+// arredondado em centavos
+func (t IncrementTable) MinimumNextBid(currentBid float64) float64 {
+	return math.Round((currentBid+t.IncrementFor(currentBid))*100) / 100
+}
+
+type IncrementTableRepositoryInterface interface {
+	// FindIncrementTable retorna a tabela cadastrada ou DefaultIncrementTable
+	FindIncrementTable(
+		ctx context.Context) (IncrementTable, *internal_error.InternalError)
+
+	UpdateIncrementTable(
+		ctx context.Context, table IncrementTable) *internal_error.InternalError
+}
@@ -0,0 +1,62 @@
+package bid_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrementTable_Validate(t *testing.T) {
+	t.Run("should accept the default table", func(t *testing.T) {
+		assert.Nil(t, DefaultIncrementTable.Validate())
+	})
+
+	t.Run("should reject invalid tables", func(t *testing.T) {
+		tables := map[string]IncrementTable{
+			"empty":              {},
+			"zero increment":     {{UpTo: 100, Increment: 0}, {Increment: 5}},
+			"bounded last band":  {{UpTo: 100, Increment: 1}},
+			"unbounded in place": {{Increment: 1}, {Increment: 5}},
+			"out of order":       {{UpTo: 1000, Increment: 5}, {UpTo: 100, Increment: 1}, {Increment: 10}},
+		}
+
+		for name, table := range tables {
+			err := table.Validate()
+			assert.NotNil(t, err, name)
+			if err != nil {
+				assert.Equal(t, "bad_request", err.Err, name)
+			}
+		}
+	})
+}
+
+func TestIncrementTable_MinimumNextBid(t *testing.T) {
+	t.Run("should apply the band of the current bid", func(t *testing.T) {
+		// Arrange
+		cases := []struct {
+			currentBid float64
+			expected   float64
+		}{
+			{0, 1},
+			{99.99, 100.99},
+			{100, 105},
+			{999, 1004},
+			{1000, 1010},
+			{25000, 25010},
+		}
+
+		for _, c := range cases {
+			// Act
+			minimum := DefaultIncrementTable.MinimumNextBid(c.currentBid)
+
+			// Assert
+			assert.Equal(t, c.expected, minimum, "current bid %v", c.currentBid)
+		}
+	})
+
+	t.Run("should round to cents", func(t *testing.T) {
+		table := IncrementTable{{UpTo: 0, Increment: 0.1}}
+
+		assert.Equal(t, 0.3, table.MinimumNextBid(0.2))
+	})
+}
@@ -0,0 +1,77 @@
+package currency_entity
+
+import (
+	"auctionService/internal/internal_error"
+	"context"
+	"math"
+	"strings"
+	"time"
+)
+
+// Moedas aceitas nos leilões (ISO 4217). O serviço de cotação do
+// clientServerAPI cobre o par USD-BRL.
+const (
+	BRL = "BRL"
+	USD = "USD"
+)
+
+// DefaultCurrency vale para leilões criados sem moeda e para os anteriores a ela
+const DefaultCurrency = BRL
+
+// IdentitySource identifica a cotação de uma moeda para ela mesma
+const IdentitySource = "identity"
+
+var supported = map[string]bool{BRL: true, USD: true}
+
+// Normalize converte code para o código ISO em maiúsculas, usando a moeda
+// padrão quando vazio
+func Normalize(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
+
+func IsSupported(code string) bool {
+	return supported[code]
+}
+
+func Validate(code string) *internal_error.InternalError {
+	if !IsSupported(code) {
+		return internal_error.NewBadRequestError("unsupported currency " + code + " (expected BRL or USD)")
+	}
+	return nil
+}
+
+// Rate é a cotação de From para To informada por Source em Timestamp: uma
+// unidade de From vale Value unidades de To
+type Rate struct {
+	From      string
+	To        string
+	Value     float64
+	Source    string
+	Timestamp time.Time
+}
+
+// IdentityRate converte code para ele mesmo
+func IdentityRate(code string, at time.Time) *Rate {
+	return &Rate{From: code, To: code, Value: 1, Source: IdentitySource, Timestamp: at}
+}
+
+// Convert aplica a cotação a amount, arredondando para centavos
+func (r *Rate) Convert(amount float64) float64 {
+	return math.Round(amount*r.Value*100) / 100
+}
+
+// Inverse é a cotação de To para From
+func (r *Rate) Inverse() *Rate {
+	return &Rate{From: r.To, To: r.From, Value: 1 / r.Value, Source: r.Source, Timestamp: r.Timestamp}
+}
+
+// RateProvider fornece cotações entre moedas. O serviço de cotação do
+// clientServerAPI, uma API externa ou uma tabela fixa se conectam implementando-o.
+type RateProvider interface {
+	FindRate(
+		ctx context.Context, from, to string) (*Rate, *internal_error.InternalError)
+}
@@ -0,0 +1,48 @@
+package currency_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, BRL, Normalize(""))
+	assert.Equal(t, USD, Normalize(" usd "))
+	assert.Equal(t, "EUR", Normalize("eur"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate(BRL))
+	assert.Nil(t, Validate(USD))
+
+	err := Validate("EUR")
+	assert.NotNil(t, err)
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestRate_Convert(t *testing.T) {
+	rate := &Rate{From: USD, To: BRL, Value: 5.1234}
+
+	assert.Equal(t, 512.34, rate.Convert(100))
+	assert.Equal(t, 0.0, rate.Convert(0))
+}
+
+func TestRate_Inverse(t *testing.T) {
+	at := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	rate := &Rate{From: USD, To: BRL, Value: 5, Source: "quote_service", Timestamp: at}
+
+	inverse := rate.Inverse()
+
+	assert.Equal(t, &Rate{From: BRL, To: USD, Value: 0.2, Source: "quote_service", Timestamp: at}, inverse)
+	assert.Equal(t, 20.0, inverse.Convert(100))
+}
+
+func TestIdentityRate(t *testing.T) {
+	rate := IdentityRate(BRL, time.Now())
+
+	assert.Equal(t, 1.0, rate.Value)
+	assert.Equal(t, IdentitySource, rate.Source)
+	assert.Equal(t, 150.5, rate.Convert(150.5))
+}
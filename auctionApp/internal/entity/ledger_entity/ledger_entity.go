@@ -0,0 +1,137 @@
+package ledger_entity
+
+import (
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/bid_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	AuctionCreated       EventType = "auction_created"
+	BidPlaced            EventType = "bid_placed"
+	AuctionStatusChanged EventType = "auction_status_changed"
+)
+
+// Event é uma entrada imutável do ledger. Lances e mudanças de status de um
+// leilão são anexados na ordem em que acontecem e nunca alterados.
+type Event struct {
+	Id        string
+	Type      EventType
+	AuctionId string
+	BidId     string
+	UserId    string
+	Amount    float64
+	Status    auction_entity.AuctionStatus
+	Timestamp time.Time
+}
+
+func NewAuctionCreatedEvent(auction *auction_entity.Auction) Event {
+	return Event{
+		Id:        uuid.New().String(),
+		Type:      AuctionCreated,
+		AuctionId: auction.Id,
+		Status:    auction.Status,
+		Timestamp: auction.Timestamp,
+	}
+}
+
+func NewBidPlacedEvent(bid bid_entity.Bid) Event {
+	return Event{
+		Id:        uuid.New().String(),
+		Type:      BidPlaced,
+		AuctionId: bid.AuctionId,
+		BidId:     bid.Id,
+		UserId:    bid.UserId,
+		Amount:    bid.Amount,
+		Timestamp: bid.Timestamp,
+	}
+}
+
+func NewAuctionStatusChangedEvent(auctionId string, status auction_entity.AuctionStatus) Event {
+	return Event{
+		Id:        uuid.New().String(),
+		Type:      AuctionStatusChanged,
+		AuctionId: auctionId,
+		Status:    status,
+		Timestamp: time.Now(),
+	}
+}
+
+// AuctionState é o estado de um leilão reconstruído a partir do ledger
+type AuctionState struct {
+	AuctionId  string
+	Status     auction_entity.AuctionStatus
+	HighestBid *bid_entity.Bid
+	BidCount   int64
+	LastEvent  time.Time
+}
+
+// Replay reconstrói o estado de cada leilão aplicando os eventos em ordem.
+// Lances só contam enquanto o leilão está ativo e precisam superar o maior
+// lance; em caso de empate vence o lance mais antigo.
+func Replay(events []Event) []AuctionState {
+	states := make(map[string]*AuctionState)
+	var order []string
+
+	for _, event := range events {
+		state, ok := states[event.AuctionId]
+		if !ok {
+			state = &AuctionState{AuctionId: event.AuctionId, Status: auction_entity.Active}
+			states[event.AuctionId] = state
+			order = append(order, event.AuctionId)
+		}
+		state.LastEvent = event.Timestamp
+
+		switch event.Type {
+		case AuctionCreated, AuctionStatusChanged:
+			state.Status = event.Status
+		case BidPlaced:
+			if state.Status != auction_entity.Active {
+				continue
+			}
+			state.BidCount++
+			if state.HighestBid == nil || event.Amount > state.HighestBid.Amount {
+				state.HighestBid = &bid_entity.Bid{
+					Id:        event.BidId,
+					UserId:    event.UserId,
+					AuctionId: event.AuctionId,
+					Amount:    event.Amount,
+					Timestamp: event.Timestamp,
+				}
+			}
+		}
+	}
+
+	result := make([]AuctionState, 0, len(order))
+	for _, auctionId := range order {
+		result = append(result, *states[auctionId])
+	}
+	return result
+}
+
+type LedgerWriter interface {
+	AppendEvent(
+		ctx context.Context, event Event) *internal_error.InternalError
+}
+
+type LedgerRepositoryInterface interface {
+	LedgerWriter
+
+	// FindEvents retorna os eventos em ordem de ocorrência; auctionId vazio retorna todos
+	FindEvents(
+		ctx context.Context, auctionId string) ([]Event, *internal_error.InternalError)
+
+	FindEventsByUserId(
+		ctx context.Context, userId string) ([]Event, *internal_error.InternalError)
+
+	// AnonymizeUserEvents troca o usuário dos eventos por pseudonym. É a única
+	// alteração permitida no ledger e não muda o resultado do Replay.
+	AnonymizeUserEvents(
+		ctx context.Context, userId, pseudonym string) (int64, *internal_error.InternalError)
+}
@@ -0,0 +1,75 @@
+package ledger_entity
+
+import (
+	"auctionService/internal/entity/auction_entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bidEvent(auctionId, bidId string, amount float64, at time.Time) Event {
+	return Event{Type: BidPlaced, AuctionId: auctionId, BidId: bidId, UserId: "user-" + bidId, Amount: amount, Timestamp: at}
+}
+
+func TestReplay(t *testing.T) {
+	start := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should rebuild highest bid and status", func(t *testing.T) {
+		// Arrange
+		events := []Event{
+			{Type: AuctionCreated, AuctionId: "a1", Status: auction_entity.Active, Timestamp: start},
+			bidEvent("a1", "b1", 100, start.Add(time.Second)),
+			bidEvent("a1", "b2", 250, start.Add(2*time.Second)),
+			bidEvent("a1", "b3", 250, start.Add(3*time.Second)),
+			bidEvent("a1", "b4", 200, start.Add(4*time.Second)),
+			{Type: AuctionStatusChanged, AuctionId: "a1", Status: auction_entity.Completed, Timestamp: start.Add(5 * time.Second)},
+		}
+
+		// Act
+		states := Replay(events)
+
+		// Assert
+		assert.Len(t, states, 1)
+		assert.Equal(t, auction_entity.Completed, states[0].Status)
+		assert.Equal(t, int64(4), states[0].BidCount)
+		assert.Equal(t, "b2", states[0].HighestBid.Id, "ties keep the earliest bid")
+		assert.Equal(t, "user-b2", states[0].HighestBid.UserId)
+		assert.Equal(t, start.Add(5*time.Second), states[0].LastEvent)
+	})
+
+	t.Run("should ignore bids after completion", func(t *testing.T) {
+		// Arrange
+		events := []Event{
+			{Type: AuctionCreated, AuctionId: "a1", Status: auction_entity.Active, Timestamp: start},
+			bidEvent("a1", "b1", 100, start.Add(time.Second)),
+			{Type: AuctionStatusChanged, AuctionId: "a1", Status: auction_entity.Completed, Timestamp: start.Add(2 * time.Second)},
+			bidEvent("a1", "late", 500, start.Add(3*time.Second)),
+		}
+
+		// Act
+		states := Replay(events)
+
+		// Assert
+		assert.Equal(t, int64(1), states[0].BidCount)
+		assert.Equal(t, "b1", states[0].HighestBid.Id)
+	})
+
+	t.Run("should keep auctions in order of first event", func(t *testing.T) {
+		// Arrange
+		events := []Event{
+			{Type: AuctionCreated, AuctionId: "a2", Timestamp: start},
+			{Type: AuctionCreated, AuctionId: "a1", Timestamp: start.Add(time.Second)},
+			bidEvent("a2", "b1", 10, start.Add(2*time.Second)),
+		}
+
+		// Act
+		states := Replay(events)
+
+		// Assert
+		assert.Len(t, states, 2)
+		assert.Equal(t, "a2", states[0].AuctionId)
+		assert.Equal(t, "a1", states[1].AuctionId)
+		assert.Nil(t, states[1].HighestBid)
+	})
+}
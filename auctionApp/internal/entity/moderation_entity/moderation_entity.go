@@ -0,0 +1,85 @@
+package moderation_entity
+
+import (
+	"auctionService/internal/internal_error"
+	"context"
+	"time"
+)
+
+type Verdict int
+
+const (
+	Approved Verdict = iota
+	Flagged
+	Rejected
+)
+
+// Content is the user supplied text of a listing that goes through moderation
+type Content struct {
+	ProductName string
+	Description string
+}
+
+type ValidationResult struct {
+	Verdict Verdict
+	Reasons []string
+}
+
+// ContentValidator is a single moderation step. Wordlists, external moderation
+// APIs and any other check plug into the pipeline by implementing it.
+type ContentValidator interface {
+	Validate(
+		ctx context.Context, content Content) (*ValidationResult, *internal_error.InternalError)
+}
+
+// Pipeline runs validators in order and keeps the most severe verdict along
+// with every reason given. It stops at the first rejection.
+type Pipeline struct {
+	validators []ContentValidator
+}
+
+func NewPipeline(validators ...ContentValidator) *Pipeline {
+	return &Pipeline{validators: validators}
+}
+
+func (p *Pipeline) Validate(
+	ctx context.Context, content Content) (*ValidationResult, *internal_error.InternalError) {
+	result := &ValidationResult{Verdict: Approved}
+
+	for _, validator := range p.validators {
+		stepResult, err := validator.Validate(ctx, content)
+		if err != nil {
+			return nil, err
+		}
+
+		if stepResult.Verdict > result.Verdict {
+			result.Verdict = stepResult.Verdict
+		}
+		result.Reasons = append(result.Reasons, stepResult.Reasons...)
+
+		if result.Verdict == Rejected {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Decision records a non-approved verdict so moderators can review it
+type Decision struct {
+	Id          string
+	AuctionId   string
+	ProductName string
+	Description string
+	Verdict     Verdict
+	Reasons     []string
+	Timestamp   time.Time
+}
+
+type DecisionRepositoryInterface interface {
+	CreateDecision(
+		ctx context.Context, decision *Decision) *internal_error.InternalError
+
+	FindDecisions(
+		ctx context.Context, verdict Verdict) ([]Decision, *internal_error.InternalError)
+}
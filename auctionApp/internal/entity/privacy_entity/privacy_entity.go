@@ -0,0 +1,66 @@
+package privacy_entity
+
+import (
+	"auctionService/internal/internal_error"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RequestType string
+
+const (
+	DataExport  RequestType = "export"
+	DataErasure RequestType = "erasure"
+)
+
+// PrivacyRequest registra uma exportação ou eliminação de dados (LGPD/GDPR)
+// já processada. Guarda só o hash do id do titular e quantos registros foram
+// afetados, nunca o id, os dados em si nem o pseudônimo usado na anonimização.
+type PrivacyRequest struct {
+	Id        string
+	UserHash  string
+	Type      RequestType
+	Auctions  int64
+	Bids      int64
+	Events    int64
+	Timestamp time.Time
+}
+
+func NewPrivacyRequest(userId string, requestType RequestType, auctions, bids, events int64) *PrivacyRequest {
+	return &PrivacyRequest{
+		Id:        uuid.New().String(),
+		UserHash:  HashUserId(userId),
+		Type:      requestType,
+		Auctions:  auctions,
+		Bids:      bids,
+		Events:    events,
+		Timestamp: time.Now(),
+	}
+}
+
+// HashUserId identifica o titular na trilha de auditoria: quem tem o id
+// encontra as solicitações dele, mas o registro sozinho não revela o id
+func HashUserId(userId string) string {
+	sum := sha256.Sum256([]byte(userId))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewPseudonym gera o id aleatório que substitui o do titular na eliminação.
+// O mesmo pseudônimo é usado em todos os registros de uma eliminação, então os
+// lances continuam atribuídos a um único participante e o vencedor não muda.
+func NewPseudonym() string {
+	return uuid.New().String()
+}
+
+type PrivacyRequestRepositoryInterface interface {
+	CreatePrivacyRequest(
+		ctx context.Context, request *PrivacyRequest) *internal_error.InternalError
+
+	// FindPrivacyRequests lista as solicitações mais recentes primeiro; userHash vazio retorna todas
+	FindPrivacyRequests(
+		ctx context.Context, userHash string) ([]PrivacyRequest, *internal_error.InternalError)
+}
@@ -13,4 +13,8 @@ type User struct {
 type UserRepositoryInterface interface {
 	FindUserById(
 		ctx context.Context, userId string) (*User, *internal_error.InternalError)
+
+	// DeleteUser remove o cadastro; retorna false se ele não existia
+	DeleteUser(
+		ctx context.Context, userId string) (bool, *internal_error.InternalError)
 }
@@ -0,0 +1,44 @@
+package bid_controller
+
+import (
+	"auctionService/configuration/rest_err"
+	"auctionService/internal/infra/api/web/validation"
+	"auctionService/internal/usecase/bid_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *BidController) FindBidIncrements(c *gin.Context) {
+	bands, err := u.bidUseCase.FindBidIncrements(context.Background())
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, bands)
+}
+
+// UpdateBidIncrements substitui a tabela inteira de incrementos
+func (u *BidController) UpdateBidIncrements(c *gin.Context) {
+	var bands []bid_usecase.BidIncrementBandDTO
+
+	if err := c.ShouldBindJSON(&bands); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	updatedBands, err := u.bidUseCase.UpdateBidIncrements(context.Background(), bands)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedBands)
+}
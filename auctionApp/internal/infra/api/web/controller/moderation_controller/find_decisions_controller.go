@@ -0,0 +1,47 @@
+package moderation_controller
+
+import (
+	"auctionService/configuration/rest_err"
+	"auctionService/internal/usecase/moderation_usecase"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationController struct {
+	moderationUseCase moderation_usecase.ModerationUseCaseInterface
+}
+
+func NewModerationController(moderationUseCase moderation_usecase.ModerationUseCaseInterface) *ModerationController {
+	return &ModerationController{
+		moderationUseCase: moderationUseCase,
+	}
+}
+
+func (mc *ModerationController) FindDecisions(c *gin.Context) {
+	verdict := c.Query("verdict")
+
+	// Sem verdict retorna todas as decisões (-1 indica "sem filtro")
+	verdictNumber := -1
+	if verdict != "" {
+		var errConv error
+		verdictNumber, errConv = strconv.Atoi(verdict)
+		if errConv != nil || verdictNumber < 0 {
+			errRest := rest_err.NewBadRequestError("Error trying to validate moderation verdict param")
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+	}
+
+	decisions, err := mc.moderationUseCase.FindDecisions(context.Background(),
+		moderation_usecase.ModerationVerdict(verdictNumber))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, decisions)
+}
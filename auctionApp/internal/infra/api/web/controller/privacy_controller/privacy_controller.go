@@ -0,0 +1,85 @@
+package privacy_controller
+
+import (
+	"auctionService/configuration/rest_err"
+	"auctionService/internal/usecase/privacy_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PrivacyController struct {
+	privacyUseCase privacy_usecase.PrivacyUseCaseInterface
+}
+
+func NewPrivacyController(privacyUseCase privacy_usecase.PrivacyUseCaseInterface) *PrivacyController {
+	return &PrivacyController{
+		privacyUseCase: privacyUseCase,
+	}
+}
+
+func (pc *PrivacyController) ExportUserData(c *gin.Context) {
+	userId, ok := validUserId(c, c.Param("userId"))
+	if !ok {
+		return
+	}
+
+	export, err := pc.privacyUseCase.ExportUserData(context.Background(), userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+func (pc *PrivacyController) EraseUserData(c *gin.Context) {
+	userId, ok := validUserId(c, c.Param("userId"))
+	if !ok {
+		return
+	}
+
+	request, err := pc.privacyUseCase.EraseUserData(context.Background(), userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+func (pc *PrivacyController) FindPrivacyRequests(c *gin.Context) {
+	userId := c.Query("userId")
+	if userId != "" {
+		if _, ok := validUserId(c, userId); !ok {
+			return
+		}
+	}
+
+	requests, err := pc.privacyUseCase.FindPrivacyRequests(context.Background(), userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+func validUserId(c *gin.Context, userId string) (string, bool) {
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return userId, true
+}
@@ -0,0 +1,58 @@
+package seller_controller
+
+import (
+	"auctionService/configuration/rest_err"
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/usecase/seller_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SellerController struct {
+	sellerUseCase seller_usecase.SellerUseCaseInterface
+}
+
+func NewSellerController(sellerUseCase seller_usecase.SellerUseCaseInterface) *SellerController {
+	return &SellerController{
+		sellerUseCase: sellerUseCase,
+	}
+}
+
+func (sc *SellerController) FindSellerDashboard(c *gin.Context) {
+	sellerId := c.Param("sellerId")
+
+	if err := uuid.Validate(sellerId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "sellerId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	// Sem interval a receita é agrupada por dia
+	interval := auction_entity.RevenueInterval(c.DefaultQuery("interval", string(auction_entity.RevenueByDay)))
+	switch interval {
+	case auction_entity.RevenueByDay, auction_entity.RevenueByWeek, auction_entity.RevenueByMonth:
+	default:
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "interval",
+			Message: "interval must be one of day, week or month",
+		})
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	dashboard, err := sc.sellerUseCase.FindSellerDashboard(context.Background(), sellerId, interval)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
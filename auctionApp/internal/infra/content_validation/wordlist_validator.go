@@ -0,0 +1,83 @@
+package content_validation
+
+import (
+	"auctionService/internal/entity/moderation_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// WordlistValidator rejects or flags listings containing configured words or
+// phrases. Matching is case-insensitive and on whole words only, so "class"
+// does not match "ass".
+type WordlistValidator struct {
+	blocked []string
+	flagged []string
+}
+
+func NewWordlistValidator(blocked, flagged []string) *WordlistValidator {
+	return &WordlistValidator{
+		blocked: normalizeTerms(blocked),
+		flagged: normalizeTerms(flagged),
+	}
+}
+
+// NewWordlistValidatorFromEnv reads comma separated lists from
+// MODERATION_BLOCKED_WORDS and MODERATION_FLAGGED_WORDS
+func NewWordlistValidatorFromEnv() *WordlistValidator {
+	return NewWordlistValidator(
+		strings.Split(os.Getenv("MODERATION_BLOCKED_WORDS"), ","),
+		strings.Split(os.Getenv("MODERATION_FLAGGED_WORDS"), ","))
+}
+
+func (wv *WordlistValidator) Validate(
+	ctx context.Context,
+	content moderation_entity.Content) (*moderation_entity.ValidationResult, *internal_error.InternalError) {
+	text := normalizeText(content.ProductName + " " + content.Description)
+	result := &moderation_entity.ValidationResult{Verdict: moderation_entity.Approved}
+
+	for _, term := range wv.blocked {
+		if containsTerm(text, term) {
+			result.Verdict = moderation_entity.Rejected
+			result.Reasons = append(result.Reasons, fmt.Sprintf("blocked term %q", term))
+		}
+	}
+	if result.Verdict == moderation_entity.Rejected {
+		return result, nil
+	}
+
+	for _, term := range wv.flagged {
+		if containsTerm(text, term) {
+			result.Verdict = moderation_entity.Flagged
+			result.Reasons = append(result.Reasons, fmt.Sprintf("flagged term %q", term))
+		}
+	}
+
+	return result, nil
+}
+
+// normalizeText lowercases text and collapses everything that is not a letter
+// or digit into single spaces, padded on both ends for whole-word lookups
+func normalizeText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return " " + strings.Join(words, " ") + " "
+}
+
+func normalizeTerms(terms []string) []string {
+	var normalized []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(normalizeText(term)); term != "" {
+			normalized = append(normalized, term)
+		}
+	}
+	return normalized
+}
+
+func containsTerm(text, term string) bool {
+	return strings.Contains(text, " "+term+" ")
+}
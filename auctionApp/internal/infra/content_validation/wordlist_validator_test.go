@@ -0,0 +1,126 @@
+package content_validation
+
+import (
+	"auctionService/internal/entity/moderation_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordlistValidator_Validate(t *testing.T) {
+	validator := NewWordlistValidator([]string{"scam", "counterfeit goods"}, []string{"replica", ""})
+
+	tests := []struct {
+		name     string
+		content  moderation_entity.Content
+		expected moderation_entity.Verdict
+		reasons  []string
+	}{
+		{
+			name:     "clean listing is approved",
+			content:  moderation_entity.Content{ProductName: "iPhone 15", Description: "Sealed box, never used"},
+			expected: moderation_entity.Approved,
+		},
+		{
+			name:     "blocked word is rejected regardless of case and punctuation",
+			content:  moderation_entity.Content{ProductName: "Great deal", Description: "Not a SCAM!"},
+			expected: moderation_entity.Rejected,
+			reasons:  []string{`blocked term "scam"`},
+		},
+		{
+			name:     "blocked phrase is matched across whitespace",
+			content:  moderation_entity.Content{ProductName: "Bag", Description: "Counterfeit\n goods, cheap"},
+			expected: moderation_entity.Rejected,
+			reasons:  []string{`blocked term "counterfeit goods"`},
+		},
+		{
+			name:     "flagged word is flagged",
+			content:  moderation_entity.Content{ProductName: "Watch replica", Description: "Looks like the real one"},
+			expected: moderation_entity.Flagged,
+			reasons:  []string{`flagged term "replica"`},
+		},
+		{
+			name:     "partial words do not match",
+			content:  moderation_entity.Content{ProductName: "Scampi recipe book", Description: "Replicator themed cookbook"},
+			expected: moderation_entity.Approved,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result, err := validator.Validate(context.Background(), tt.content)
+
+			// Assert
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, result.Verdict)
+			assert.Equal(t, tt.reasons, result.Reasons)
+		})
+	}
+}
+
+type stubValidator struct {
+	result *moderation_entity.ValidationResult
+	err    *internal_error.InternalError
+	calls  int
+}
+
+func (s *stubValidator) Validate(
+	ctx context.Context,
+	content moderation_entity.Content) (*moderation_entity.ValidationResult, *internal_error.InternalError) {
+	s.calls++
+	return s.result, s.err
+}
+
+func TestPipeline_Validate(t *testing.T) {
+	t.Run("should keep the most severe verdict and all reasons", func(t *testing.T) {
+		// Arrange
+		pipeline := moderation_entity.NewPipeline(
+			&stubValidator{result: &moderation_entity.ValidationResult{Verdict: moderation_entity.Flagged, Reasons: []string{"a"}}},
+			&stubValidator{result: &moderation_entity.ValidationResult{Verdict: moderation_entity.Approved}},
+			&stubValidator{result: &moderation_entity.ValidationResult{Verdict: moderation_entity.Flagged, Reasons: []string{"b"}}},
+		)
+
+		// Act
+		result, err := pipeline.Validate(context.Background(), moderation_entity.Content{})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, moderation_entity.Flagged, result.Verdict)
+		assert.Equal(t, []string{"a", "b"}, result.Reasons)
+	})
+
+	t.Run("should stop at the first rejection", func(t *testing.T) {
+		// Arrange
+		next := &stubValidator{result: &moderation_entity.ValidationResult{Verdict: moderation_entity.Approved}}
+		pipeline := moderation_entity.NewPipeline(
+			&stubValidator{result: &moderation_entity.ValidationResult{Verdict: moderation_entity.Rejected, Reasons: []string{"blocked"}}},
+			next,
+		)
+
+		// Act
+		result, err := pipeline.Validate(context.Background(), moderation_entity.Content{})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, moderation_entity.Rejected, result.Verdict)
+		assert.Equal(t, 0, next.calls)
+	})
+
+	t.Run("should propagate validator errors", func(t *testing.T) {
+		// Arrange
+		pipeline := moderation_entity.NewPipeline(
+			&stubValidator{err: internal_error.NewInternalServerError("moderation API unavailable")},
+		)
+
+		// Act
+		result, err := pipeline.Validate(context.Background(), moderation_entity.Content{})
+
+		// Assert
+		assert.Nil(t, result)
+		assert.NotNil(t, err)
+		assert.Equal(t, "moderation API unavailable", err.Message)
+	})
+}
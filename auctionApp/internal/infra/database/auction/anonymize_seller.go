@@ -0,0 +1,25 @@
+package auction
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AnonymizeSeller altera apenas seller_id; os leilões, seus lances e o
+// status continuam intactos
+func (ar *AuctionRepository) AnonymizeSeller(
+	ctx context.Context, sellerId, pseudonym string) (int64, *internal_error.InternalError) {
+	filter := bson.M{"seller_id": sellerId}
+	update := bson.M{"$set": bson.M{"seller_id": pseudonym}}
+
+	result, err := ar.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to anonymize seller auctions", err)
+		return 0, internal_error.NewInternalServerError("Error trying to anonymize seller auctions")
+	}
+
+	return result.ModifiedCount, nil
+}
@@ -3,6 +3,9 @@ package auction
 import (
 	"auctionService/configuration/logger"
 	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/currency_entity"
+	"auctionService/internal/entity/ledger_entity"
+	"auctionService/internal/entity/moderation_entity"
 	"auctionService/internal/internal_error"
 	"context"
 	"os"
@@ -15,25 +18,36 @@ import (
 
 type AuctionEntityMongo struct {
 	Id          string                          `bson:"_id"`
+	SellerId    string                          `bson:"seller_id,omitempty"`
 	ProductName string                          `bson:"product_name"`
 	Category    string                          `bson:"category"`
 	Description string                          `bson:"description"`
 	Condition   auction_entity.ProductCondition `bson:"condition"`
+	Currency    string                          `bson:"currency,omitempty"`
 	Status      auction_entity.AuctionStatus    `bson:"status"`
+	Moderation  moderation_entity.Verdict       `bson:"moderation"`
 	Timestamp   int64                           `bson:"timestamp"`
+	// SettlementRate é gravado no encerramento quando há provedor de cotação
+	SettlementRate *ExchangeRateMongo `bson:"settlement_rate,omitempty"`
 }
 type AuctionRepository struct {
-	Collection      *mongo.Collection
-	ctx             context.Context
-	auctionInterval time.Duration
+	Collection *mongo.Collection
+	Ledger     ledger_entity.LedgerWriter
+	// Rates, quando configurado, fornece a cotação gravada no encerramento
+	// dos leilões para SettlementCurrency
+	Rates              currency_entity.RateProvider
+	SettlementCurrency string
+	ctx                context.Context
+	auctionInterval    time.Duration
 }
 
 func NewAuctionRepository(database *mongo.Database) *AuctionRepository {
 	ctx := context.Background()
 	return &AuctionRepository{
-		Collection:      database.Collection("auctions"),
-		ctx:             ctx,
-		auctionInterval: getAuctionInterval(),
+		Collection:         database.Collection("auctions"),
+		SettlementCurrency: currency_entity.DefaultCurrency,
+		ctx:                ctx,
+		auctionInterval:    getAuctionInterval(),
 	}
 }
 
@@ -41,11 +55,14 @@ func (ar *AuctionRepository) CreateAuction(
 	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
 	auctionEntityMongo := &AuctionEntityMongo{
 		Id:          auctionEntity.Id,
+		SellerId:    auctionEntity.SellerId,
 		ProductName: auctionEntity.ProductName,
 		Category:    auctionEntity.Category,
 		Description: auctionEntity.Description,
 		Condition:   auctionEntity.Condition,
+		Currency:    auctionEntity.Currency,
 		Status:      auctionEntity.Status,
+		Moderation:  auctionEntity.Moderation,
 		Timestamp:   auctionEntity.Timestamp.Unix(),
 	}
 	_, err := ar.Collection.InsertOne(ar.ctx, auctionEntityMongo)
@@ -53,12 +70,12 @@ func (ar *AuctionRepository) CreateAuction(
 		logger.Error("Error trying to insert auction", err)
 		return internal_error.NewInternalServerError("Error trying to insert auction")
 	}
+	ar.appendToLedger(ledger_entity.NewAuctionCreatedEvent(auctionEntity))
 
 	go func() {
 		select {
 		case <-time.After(ar.auctionInterval):
-			ar.updateAuctionStatus(auctionEntityMongo.Id, auction_entity.Completed)
-			if err != nil {
+			if err := ar.completeAuction(auctionEntity); err != nil {
 				logger.Error("Error trying to update auction status to completed", err)
 				return
 			}
@@ -81,11 +98,37 @@ func getAuctionInterval() time.Duration {
 	return duration
 }
 
-func (ar *AuctionRepository) updateAuctionStatus(auctionId string, status auction_entity.AuctionStatus) *internal_error.InternalError {
+// updateAuctionStatus grava o novo status, junto com fields, e o registra no ledger
+func (ar *AuctionRepository) updateAuctionStatus(
+	auctionId string, status auction_entity.AuctionStatus, fields ...bson.E) *internal_error.InternalError {
+	set := bson.M{"status": status}
+	for _, field := range fields {
+		set[field.Key] = field.Value
+	}
+	if err := ar.setAuctionFields(ar.ctx, auctionId, set); err != nil {
+		return err
+	}
+	ar.appendToLedger(ledger_entity.NewAuctionStatusChangedEvent(auctionId, status))
+	return nil
+}
+
+// RestoreAuctionStatus grava o status reconstruído a partir do ledger, sem anexar um novo evento
+func (ar *AuctionRepository) RestoreAuctionStatus(
+	ctx context.Context, auctionId string, status auction_entity.AuctionStatus) *internal_error.InternalError {
+	return ar.setAuctionStatus(ctx, auctionId, status)
+}
+
+func (ar *AuctionRepository) setAuctionStatus(
+	ctx context.Context, auctionId string, status auction_entity.AuctionStatus) *internal_error.InternalError {
+	return ar.setAuctionFields(ctx, auctionId, bson.M{"status": status})
+}
+
+func (ar *AuctionRepository) setAuctionFields(
+	ctx context.Context, auctionId string, fields bson.M) *internal_error.InternalError {
 	filter := bson.M{"_id": auctionId}
-	update := bson.M{"$set": bson.M{"status": status}}
+	update := bson.M{"$set": fields}
 
-	result, err := ar.Collection.UpdateOne(ar.ctx, filter, update)
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		logger.Error("Error updating auction status in database", err)
 		return internal_error.NewInternalServerError("Error updating auction status")
@@ -98,3 +141,15 @@ func (ar *AuctionRepository) updateAuctionStatus(auctionId string, status auctio
 
 	return nil
 }
+
+// appendToLedger registra o evento quando há ledger configurado. Uma falha é
+// apenas logada: a escrita principal já foi feita e não deve ser desfeita.
+func (ar *AuctionRepository) appendToLedger(event ledger_entity.Event) {
+	if ar.Ledger == nil {
+		return
+	}
+	if err := ar.Ledger.AppendEvent(ar.ctx, event); err != nil {
+		logger.Error("Error trying to append auction event to the ledger", err,
+			zap.String("auction_id", event.AuctionId), zap.String("event_type", string(event.Type)))
+	}
+}
@@ -3,6 +3,7 @@ package auction
 import (
 	"auctionService/configuration/logger"
 	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/currency_entity"
 	"auctionService/internal/internal_error"
 	"context"
 	"fmt"
@@ -24,12 +25,17 @@ func (ar *AuctionRepository) FindAuctionById(
 
 	return &auction_entity.Auction{
 		Id:          auctionEntityMongo.Id,
+		SellerId:    auctionEntityMongo.SellerId,
 		ProductName: auctionEntityMongo.ProductName,
 		Category:    auctionEntityMongo.Category,
 		Description: auctionEntityMongo.Description,
 		Condition:   auctionEntityMongo.Condition,
+		Currency:    currency_entity.Normalize(auctionEntityMongo.Currency),
 		Status:      auctionEntityMongo.Status,
+		Moderation:  auctionEntityMongo.Moderation,
 		Timestamp:   time.Unix(auctionEntityMongo.Timestamp, 0),
+
+		SettlementRate: auctionEntityMongo.SettlementRate.toRate(),
 	}, nil
 }
 
@@ -71,12 +77,55 @@ func (repo *AuctionRepository) FindAuctions(
 	for _, auction := range auctionsMongo {
 		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
 			Id:          auction.Id,
+			SellerId:    auction.SellerId,
 			ProductName: auction.ProductName,
 			Category:    auction.Category,
 			Status:      auction.Status,
+			Moderation:  auction.Moderation,
 			Description: auction.Description,
 			Condition:   auction.Condition,
+			Currency:    currency_entity.Normalize(auction.Currency),
 			Timestamp:   time.Unix(auction.Timestamp, 0),
+
+			SettlementRate: auction.SettlementRate.toRate(),
+		})
+	}
+
+	return auctionsEntity, nil
+}
+
+func (ar *AuctionRepository) FindAuctionsBySellerId(
+	ctx context.Context, sellerId string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	filter := bson.M{"seller_id": sellerId}
+
+	cursor, err := ar.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding auctions by sellerId", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions by sellerId")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding auctions by sellerId", err)
+		return nil, internal_error.NewInternalServerError("Error decoding auctions by sellerId")
+	}
+
+	auctionsEntity := make([]auction_entity.Auction, 0, len(auctionsMongo))
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
+			Id:          auction.Id,
+			SellerId:    auction.SellerId,
+			ProductName: auction.ProductName,
+			Category:    auction.Category,
+			Description: auction.Description,
+			Condition:   auction.Condition,
+			Currency:    currency_entity.Normalize(auction.Currency),
+			Status:      auction.Status,
+			Moderation:  auction.Moderation,
+			Timestamp:   time.Unix(auction.Timestamp, 0),
+
+			SettlementRate: auction.SettlementRate.toRate(),
 		})
 	}
 
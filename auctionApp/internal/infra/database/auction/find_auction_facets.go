@@ -0,0 +1,148 @@
+package auction
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Limites dos buckets de preço, calculados sobre o maior lance de cada leilão.
+// Leilões sem lances caem no primeiro bucket.
+var priceBucketBoundaries = []float64{0, 100, 500, 1000, 5000}
+
+const priceBucketOverflow = "5000+"
+
+type facetCountMongo struct {
+	Id    interface{} `bson:"_id"`
+	Count int64       `bson:"count"`
+}
+
+type auctionFacetsMongo struct {
+	Categories   []facetCountMongo `bson:"categories"`
+	Conditions   []facetCountMongo `bson:"conditions"`
+	PriceBuckets []facetCountMongo `bson:"price_buckets"`
+}
+
+func (ar *AuctionRepository) FindAuctionFacets(
+	ctx context.Context, query string) (*auction_entity.AuctionFacets, *internal_error.InternalError) {
+	cursor, err := ar.Collection.Aggregate(ctx, buildFacetsPipeline(query))
+	if err != nil {
+		logger.Error("Error trying to aggregate auction facets", err)
+		return nil, internal_error.NewInternalServerError("Error trying to aggregate auction facets")
+	}
+	defer cursor.Close(ctx)
+
+	var results []auctionFacetsMongo
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error("Error decoding auction facets", err)
+		return nil, internal_error.NewInternalServerError("Error decoding auction facets")
+	}
+
+	facets := &auction_entity.AuctionFacets{
+		Categories:   []auction_entity.FacetCount{},
+		Conditions:   []auction_entity.FacetCount{},
+		PriceBuckets: []auction_entity.FacetCount{},
+	}
+	if len(results) == 0 {
+		return facets, nil
+	}
+
+	for _, c := range results[0].Categories {
+		facets.Categories = append(facets.Categories, auction_entity.FacetCount{
+			Value: fmt.Sprint(c.Id),
+			Count: c.Count,
+		})
+	}
+
+	for _, c := range results[0].Conditions {
+		facets.Conditions = append(facets.Conditions, auction_entity.FacetCount{
+			Value: fmt.Sprint(c.Id),
+			Count: c.Count,
+		})
+	}
+
+	for _, c := range results[0].PriceBuckets {
+		facets.PriceBuckets = append(facets.PriceBuckets, auction_entity.FacetCount{
+			Value: priceBucketLabel(c.Id),
+			Count: c.Count,
+		})
+	}
+
+	return facets, nil
+}
+
+func buildFacetsPipeline(query string) mongo.Pipeline {
+	match := bson.M{}
+	if query != "" {
+		pattern := regexp.QuoteMeta(query)
+		match["$or"] = bson.A{
+			bson.M{"product_name": primitive.Regex{Pattern: pattern, Options: "i"}},
+			bson.M{"description": primitive.Regex{Pattern: pattern, Options: "i"}},
+		}
+	}
+
+	boundaries := bson.A{}
+	for _, b := range priceBucketBoundaries {
+		boundaries = append(boundaries, b)
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "bids",
+			"localField":   "_id",
+			"foreignField": "auction_id",
+			"as":           "bids",
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"price": bson.M{"$ifNull": bson.A{bson.M{"$max": "$bids.amount"}, 0}},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"categories": bson.A{
+				bson.M{"$group": bson.M{"_id": "$category", "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			},
+			"conditions": bson.A{
+				bson.M{"$group": bson.M{"_id": "$condition", "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+			"price_buckets": bson.A{
+				bson.M{"$bucket": bson.M{
+					"groupBy":    "$price",
+					"boundaries": boundaries,
+					"default":    priceBucketOverflow,
+					"output":     bson.M{"count": bson.M{"$sum": 1}},
+				}},
+			},
+		}}},
+	}
+}
+
+func priceBucketLabel(id interface{}) string {
+	var lower float64
+	switch v := id.(type) {
+	case float64:
+		lower = v
+	case int32:
+		lower = float64(v)
+	case int64:
+		lower = float64(v)
+	default:
+		return fmt.Sprint(id)
+	}
+
+	for i := 0; i < len(priceBucketBoundaries)-1; i++ {
+		if priceBucketBoundaries[i] == lower {
+			return fmt.Sprintf("%g-%g", lower, priceBucketBoundaries[i+1])
+		}
+	}
+
+	return fmt.Sprint(id)
+}
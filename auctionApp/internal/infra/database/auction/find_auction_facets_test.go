@@ -0,0 +1,81 @@
+package auction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestAuctionRepository_FindAuctionFacets(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should map facet counts from aggregation result", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.auctions", mtest.FirstBatch, bson.D{
+			{Key: "categories", Value: bson.A{
+				bson.D{{Key: "_id", Value: "Electronics"}, {Key: "count", Value: int64(3)}},
+			}},
+			{Key: "conditions", Value: bson.A{
+				bson.D{{Key: "_id", Value: int32(1)}, {Key: "count", Value: int64(2)}},
+			}},
+			{Key: "price_buckets", Value: bson.A{
+				bson.D{{Key: "_id", Value: float64(100)}, {Key: "count", Value: int64(1)}},
+				bson.D{{Key: "_id", Value: priceBucketOverflow}, {Key: "count", Value: int64(2)}},
+			}},
+		}))
+
+		// Act
+		facets, err := repo.FindAuctionFacets(context.Background(), "phone")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "Electronics", facets.Categories[0].Value)
+		assert.Equal(t, int64(3), facets.Categories[0].Count)
+		assert.Equal(t, "1", facets.Conditions[0].Value)
+		assert.Equal(t, "100-500", facets.PriceBuckets[0].Value)
+		assert.Equal(t, "5000+", facets.PriceBuckets[1].Value)
+		assert.Equal(t, int64(2), facets.PriceBuckets[1].Count)
+	})
+
+	mt.Run("should return error when aggregation fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "aggregation error",
+		}))
+
+		// Act
+		facets, err := repo.FindAuctionFacets(context.Background(), "")
+
+		// Assert
+		assert.Nil(t, facets)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error trying to aggregate auction facets")
+	})
+}
+
+func TestBuildFacetsPipeline(t *testing.T) {
+	t.Run("should escape query and match name or description", func(t *testing.T) {
+		// Act
+		pipeline := buildFacetsPipeline("a+b")
+
+		// Assert
+		match := pipeline[0][0].Value.(bson.M)
+		assert.Len(t, match["$or"], 2)
+	})
+
+	t.Run("should match everything when query is empty", func(t *testing.T) {
+		// Act
+		pipeline := buildFacetsPipeline("")
+
+		// Assert
+		assert.Empty(t, pipeline[0][0].Value.(bson.M))
+	})
+}
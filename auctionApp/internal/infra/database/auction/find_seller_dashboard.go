@@ -0,0 +1,131 @@
+package auction
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+type sellerStatusMongo struct {
+	Status auction_entity.AuctionStatus `bson:"_id"`
+	Count  int64                        `bson:"count"`
+	Bids   int64                        `bson:"bids"`
+}
+
+type revenueBucketMongo struct {
+	Start    time.Time `bson:"_id"`
+	Revenue  float64   `bson:"revenue"`
+	Auctions int64     `bson:"auctions"`
+}
+
+type sellerDashboardMongo struct {
+	Statuses []sellerStatusMongo  `bson:"statuses"`
+	Revenue  []revenueBucketMongo `bson:"revenue"`
+}
+
+func (ar *AuctionRepository) FindSellerDashboard(
+	ctx context.Context,
+	sellerId string,
+	interval auction_entity.RevenueInterval) (*auction_entity.SellerDashboard, *internal_error.InternalError) {
+	cursor, err := ar.Collection.Aggregate(ctx, buildSellerDashboardPipeline(sellerId, interval, ar.auctionInterval))
+	if err != nil {
+		logger.Error("Error trying to aggregate seller dashboard", err, zap.String("seller_id", sellerId))
+		return nil, internal_error.NewInternalServerError("Error trying to aggregate seller dashboard")
+	}
+	defer cursor.Close(ctx)
+
+	var results []sellerDashboardMongo
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error("Error decoding seller dashboard", err)
+		return nil, internal_error.NewInternalServerError("Error decoding seller dashboard")
+	}
+
+	dashboard := &auction_entity.SellerDashboard{
+		Revenue: []auction_entity.RevenueBucket{},
+	}
+	if len(results) == 0 {
+		return dashboard, nil
+	}
+
+	for _, s := range results[0].Statuses {
+		switch s.Status {
+		case auction_entity.Active:
+			dashboard.ActiveAuctions = s.Count
+		case auction_entity.Completed:
+			dashboard.CompletedAuctions = s.Count
+		}
+		dashboard.TotalBids += s.Bids
+	}
+
+	for _, r := range results[0].Revenue {
+		dashboard.SoldAuctions += r.Auctions
+		dashboard.Revenue = append(dashboard.Revenue, auction_entity.RevenueBucket{
+			Start:    r.Start,
+			Revenue:  r.Revenue,
+			Auctions: r.Auctions,
+		})
+	}
+
+	return dashboard, nil
+}
+
+// buildSellerDashboardPipeline conta os leilões por status e soma a receita dos
+// vendidos (maior lance) agrupada pelo horário de encerramento, em buckets de
+// dia, semana (começando na segunda) ou mês.
+func buildSellerDashboardPipeline(
+	sellerId string,
+	interval auction_entity.RevenueInterval,
+	auctionInterval time.Duration) mongo.Pipeline {
+	endTime := bson.M{"$toDate": bson.M{"$multiply": bson.A{
+		bson.M{"$add": bson.A{"$timestamp", int64(auctionInterval.Seconds())}},
+		1000,
+	}}}
+
+	dateTrunc := bson.M{"date": endTime, "unit": string(interval)}
+	if interval == auction_entity.RevenueByWeek {
+		dateTrunc["startOfWeek"] = "monday"
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"seller_id": sellerId}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "bids",
+			"localField":   "_id",
+			"foreignField": "auction_id",
+			"as":           "bids",
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"status":    1,
+			"timestamp": 1,
+			"bid_count": bson.M{"$size": "$bids"},
+			"price":     bson.M{"$max": "$bids.amount"},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"statuses": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   "$status",
+					"count": bson.M{"$sum": 1},
+					"bids":  bson.M{"$sum": "$bid_count"},
+				}},
+			},
+			"revenue": bson.A{
+				bson.M{"$match": bson.M{
+					"status":    auction_entity.Completed,
+					"bid_count": bson.M{"$gt": 0},
+				}},
+				bson.M{"$group": bson.M{
+					"_id":      bson.M{"$dateTrunc": dateTrunc},
+					"revenue":  bson.M{"$sum": "$price"},
+					"auctions": bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+		}}},
+	}
+}
@@ -0,0 +1,107 @@
+package auction
+
+import (
+	"auctionService/internal/entity/auction_entity"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestAuctionRepository_FindSellerDashboard(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should map status counts and revenue buckets", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+		day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.auctions", mtest.FirstBatch, bson.D{
+			{Key: "statuses", Value: bson.A{
+				bson.D{{Key: "_id", Value: int32(auction_entity.Active)}, {Key: "count", Value: int64(2)}, {Key: "bids", Value: int64(3)}},
+				bson.D{{Key: "_id", Value: int32(auction_entity.Completed)}, {Key: "count", Value: int64(4)}, {Key: "bids", Value: int64(7)}},
+			}},
+			{Key: "revenue", Value: bson.A{
+				bson.D{{Key: "_id", Value: primitive.NewDateTimeFromTime(day)}, {Key: "revenue", Value: 150.5}, {Key: "auctions", Value: int64(2)}},
+				bson.D{{Key: "_id", Value: primitive.NewDateTimeFromTime(day.AddDate(0, 0, 1))}, {Key: "revenue", Value: 80.0}, {Key: "auctions", Value: int64(1)}},
+			}},
+		}))
+
+		// Act
+		dashboard, err := repo.FindSellerDashboard(context.Background(), "seller-id", auction_entity.RevenueByDay)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), dashboard.ActiveAuctions)
+		assert.Equal(t, int64(4), dashboard.CompletedAuctions)
+		assert.Equal(t, int64(10), dashboard.TotalBids)
+		assert.Equal(t, int64(3), dashboard.SoldAuctions)
+		assert.Equal(t, 0.75, dashboard.ConversionRate())
+		assert.Len(t, dashboard.Revenue, 2)
+		assert.True(t, day.Equal(dashboard.Revenue[0].Start))
+		assert.Equal(t, 150.5, dashboard.Revenue[0].Revenue)
+	})
+
+	mt.Run("should return empty dashboard for seller without auctions", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.auctions", mtest.FirstBatch, bson.D{
+			{Key: "statuses", Value: bson.A{}},
+			{Key: "revenue", Value: bson.A{}},
+		}))
+
+		// Act
+		dashboard, err := repo.FindSellerDashboard(context.Background(), "seller-id", auction_entity.RevenueByMonth)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), dashboard.CompletedAuctions)
+		assert.Equal(t, float64(0), dashboard.ConversionRate())
+		assert.NotNil(t, dashboard.Revenue)
+	})
+
+	mt.Run("should return error when aggregation fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "aggregation error",
+		}))
+
+		// Act
+		dashboard, err := repo.FindSellerDashboard(context.Background(), "seller-id", auction_entity.RevenueByDay)
+
+		// Assert
+		assert.Nil(t, dashboard)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error trying to aggregate seller dashboard")
+	})
+}
+
+func TestBuildSellerDashboardPipeline(t *testing.T) {
+	t.Run("should match the seller", func(t *testing.T) {
+		// Act
+		pipeline := buildSellerDashboardPipeline("seller-id", auction_entity.RevenueByDay, time.Minute)
+
+		// Assert
+		assert.Equal(t, bson.M{"seller_id": "seller-id"}, pipeline[0][0].Value)
+	})
+
+	t.Run("should start weeks on monday", func(t *testing.T) {
+		// Act
+		pipeline := buildSellerDashboardPipeline("seller-id", auction_entity.RevenueByWeek, time.Minute)
+
+		// Assert
+		revenue := pipeline[3][0].Value.(bson.M)["revenue"].(bson.A)
+		group := revenue[1].(bson.M)["$group"].(bson.M)
+		dateTrunc := group["_id"].(bson.M)["$dateTrunc"].(bson.M)
+		assert.Equal(t, "week", dateTrunc["unit"])
+		assert.Equal(t, "monday", dateTrunc["startOfWeek"])
+	})
+}
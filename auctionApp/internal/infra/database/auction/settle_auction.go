@@ -0,0 +1,74 @@
+package auction
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/currency_entity"
+	"auctionService/internal/internal_error"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+type ExchangeRateMongo struct {
+	From      string  `bson:"from"`
+	To        string  `bson:"to"`
+	Rate      float64 `bson:"rate"`
+	Source    string  `bson:"source"`
+	Timestamp int64   `bson:"timestamp"`
+}
+
+func (r *ExchangeRateMongo) toRate() *currency_entity.Rate {
+	if r == nil {
+		return nil
+	}
+	return &currency_entity.Rate{
+		From:      r.From,
+		To:        r.To,
+		Value:     r.Rate,
+		Source:    r.Source,
+		Timestamp: time.Unix(r.Timestamp, 0),
+	}
+}
+
+// completeAuction encerra o leilão gravando, junto com o status, a cotação da
+// moeda do leilão para a moeda de liquidação naquele instante
+func (ar *AuctionRepository) completeAuction(auction *auction_entity.Auction) *internal_error.InternalError {
+	rate := ar.findSettlementRate(auction)
+	if rate == nil {
+		return ar.updateAuctionStatus(auction.Id, auction_entity.Completed)
+	}
+
+	return ar.updateAuctionStatus(auction.Id, auction_entity.Completed, bson.E{
+		Key: "settlement_rate",
+		Value: &ExchangeRateMongo{
+			From:      rate.From,
+			To:        rate.To,
+			Rate:      rate.Value,
+			Source:    rate.Source,
+			Timestamp: rate.Timestamp.Unix(),
+		},
+	})
+}
+
+// findSettlementRate retorna nil sem provedor configurado. Uma falha na
+// cotação é apenas logada: o leilão encerra mesmo sem o snapshot.
+func (ar *AuctionRepository) findSettlementRate(auction *auction_entity.Auction) *currency_entity.Rate {
+	if ar.Rates == nil {
+		return nil
+	}
+
+	to := currency_entity.Normalize(ar.SettlementCurrency)
+	if auction.Currency == to {
+		return currency_entity.IdentityRate(to, time.Now())
+	}
+
+	rate, err := ar.Rates.FindRate(ar.ctx, auction.Currency, to)
+	if err != nil {
+		logger.Error("Error trying to find settlement rate", err,
+			zap.String("auction_id", auction.Id), zap.String("from", auction.Currency), zap.String("to", to))
+		return nil
+	}
+	return rate
+}
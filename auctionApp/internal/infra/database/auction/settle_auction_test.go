@@ -0,0 +1,160 @@
+package auction
+
+import (
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/currency_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+type fixedRateProvider struct {
+	rate *currency_entity.Rate
+	err  *internal_error.InternalError
+}
+
+func (p *fixedRateProvider) FindRate(
+	ctx context.Context, from, to string) (*currency_entity.Rate, *internal_error.InternalError) {
+	return p.rate, p.err
+}
+
+func successfulUpdate() bson.D {
+	return bson.D{
+		{Key: "ok", Value: 1},
+		{Key: "n", Value: 1},
+		{Key: "nModified", Value: 1},
+	}
+}
+
+// updateSet devolve o $set do último update enviado ao banco
+func updateSet(mt *mtest.T) bson.Raw {
+	command := mt.GetStartedEvent().Command
+	return command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+}
+
+func TestAuctionRepository_CompleteAuction(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	auction := &auction_entity.Auction{Id: "auction-id", Currency: currency_entity.USD}
+
+	mt.Run("should store the settlement rate with the status", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+		repo.Rates = &fixedRateProvider{rate: &currency_entity.Rate{
+			From: "USD", To: "BRL", Value: 5.1234, Source: "quote_service", Timestamp: time.Unix(1736510400, 0),
+		}}
+		mt.AddMockResponses(successfulUpdate())
+
+		// Act
+		err := repo.completeAuction(auction)
+
+		// Assert
+		assert.Nil(t, err)
+		set := updateSet(mt)
+		assert.Equal(t, int32(auction_entity.Completed), set.Lookup("status").Int32())
+		rate := set.Lookup("settlement_rate").Document()
+		assert.Equal(t, "USD", rate.Lookup("from").StringValue())
+		assert.Equal(t, "BRL", rate.Lookup("to").StringValue())
+		assert.Equal(t, 5.1234, rate.Lookup("rate").Double())
+		assert.Equal(t, "quote_service", rate.Lookup("source").StringValue())
+		assert.Equal(t, int64(1736510400), rate.Lookup("timestamp").Int64())
+	})
+
+	mt.Run("should store an identity rate for the settlement currency", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+		repo.Rates = &fixedRateProvider{err: internal_error.NewInternalServerError("must not be called")}
+		repo.SettlementCurrency = currency_entity.USD
+		mt.AddMockResponses(successfulUpdate())
+
+		// Act
+		err := repo.completeAuction(auction)
+
+		// Assert
+		assert.Nil(t, err)
+		rate := updateSet(mt).Lookup("settlement_rate").Document()
+		assert.Equal(t, 1.0, rate.Lookup("rate").Double())
+		assert.Equal(t, currency_entity.IdentitySource, rate.Lookup("source").StringValue())
+	})
+
+	mt.Run("should complete without snapshot when the rate is unavailable", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+		repo.Rates = &fixedRateProvider{err: internal_error.NewInternalServerError("quote service down")}
+		mt.AddMockResponses(successfulUpdate())
+
+		// Act
+		err := repo.completeAuction(auction)
+
+		// Assert
+		assert.Nil(t, err)
+		_, lookupErr := updateSet(mt).LookupErr("settlement_rate")
+		assert.NotNil(t, lookupErr)
+	})
+
+	mt.Run("should complete without snapshot when no provider is configured", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+		mt.AddMockResponses(successfulUpdate())
+
+		// Act
+		err := repo.completeAuction(auction)
+
+		// Assert
+		assert.Nil(t, err)
+		_, lookupErr := updateSet(mt).LookupErr("settlement_rate")
+		assert.NotNil(t, lookupErr)
+	})
+}
+
+func TestAuctionRepository_FindAuctionById_Currency(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should map currency and settlement rate", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.auctions", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "auction-id"},
+			{Key: "currency", Value: "USD"},
+			{Key: "status", Value: auction_entity.Completed},
+			{Key: "settlement_rate", Value: bson.D{
+				{Key: "from", Value: "USD"},
+				{Key: "to", Value: "BRL"},
+				{Key: "rate", Value: 5.1234},
+				{Key: "source", Value: "quote_service"},
+				{Key: "timestamp", Value: int64(1736510400)},
+			}},
+		}))
+
+		// Act
+		auction, err := repo.FindAuctionById(context.Background(), "auction-id")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, currency_entity.USD, auction.Currency)
+		assert.Equal(t, &currency_entity.Rate{
+			From: "USD", To: "BRL", Value: 5.1234, Source: "quote_service", Timestamp: time.Unix(1736510400, 0),
+		}, auction.SettlementRate)
+	})
+
+	mt.Run("should default legacy auctions to BRL", func(mt *mtest.T) {
+		// Arrange
+		repo := NewAuctionRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.auctions", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "auction-id"},
+			{Key: "status", Value: auction_entity.Active},
+		}))
+
+		// Act
+		auction, err := repo.FindAuctionById(context.Background(), "auction-id")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, currency_entity.BRL, auction.Currency)
+		assert.Nil(t, auction.SettlementRate)
+	})
+}
@@ -0,0 +1,25 @@
+package bid
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AnonymizeBidsByUserId altera apenas user_id: valor e horário continuam
+// iguais, então o lance vencedor de cada leilão não muda
+func (bd *BidRepository) AnonymizeBidsByUserId(
+	ctx context.Context, userId, pseudonym string) (int64, *internal_error.InternalError) {
+	filter := bson.M{"user_id": userId}
+	update := bson.M{"$set": bson.M{"user_id": pseudonym}}
+
+	result, err := bd.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to anonymize bids", err)
+		return 0, internal_error.NewInternalServerError("Error trying to anonymize bids")
+	}
+
+	return result.ModifiedCount, nil
+}
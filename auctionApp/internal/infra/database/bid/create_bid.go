@@ -4,6 +4,7 @@ import (
 	"auctionService/configuration/logger"
 	"auctionService/internal/entity/auction_entity"
 	"auctionService/internal/entity/bid_entity"
+	"auctionService/internal/entity/ledger_entity"
 	"auctionService/internal/infra/database/auction"
 	"auctionService/internal/internal_error"
 	"context"
@@ -11,7 +12,10 @@ import (
 	"sync"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 )
 
 type BidEntityMongo struct {
@@ -25,6 +29,7 @@ type BidEntityMongo struct {
 type BidRepository struct {
 	Collection            *mongo.Collection
 	AuctionRepository     *auction.AuctionRepository
+	Ledger                ledger_entity.LedgerWriter
 	auctionInterval       time.Duration
 	auctionStatusMap      map[string]auction_entity.AuctionStatus
 	auctionEndTimeMap     map[string]time.Time
@@ -79,6 +84,7 @@ func (bd *BidRepository) CreateBid(
 					logger.Error("Error trying to insert bid", err)
 					return
 				}
+				bd.appendToLedger(ctx, bidValue)
 
 				return
 			}
@@ -104,12 +110,44 @@ func (bd *BidRepository) CreateBid(
 				logger.Error("Error trying to insert bid", err)
 				return
 			}
+			bd.appendToLedger(ctx, bidValue)
 		}(bid)
 	}
 	wg.Wait()
 	return nil
 }
 
+// RestoreBid grava um lance reconstruído a partir do ledger, substituindo o
+// documento de mesmo id se existir
+func (bd *BidRepository) RestoreBid(
+	ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	bidEntityMongo := &BidEntityMongo{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    bid.Amount,
+		Timestamp: bid.Timestamp.Unix(),
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := bd.Collection.ReplaceOne(ctx, bson.M{"_id": bid.Id}, bidEntityMongo, opts); err != nil {
+		logger.Error("Error trying to restore bid", err, zap.String("bid_id", bid.Id))
+		return internal_error.NewInternalServerError("Error trying to restore bid")
+	}
+
+	return nil
+}
+
+// appendToLedger registra o lance aceito quando há ledger configurado
+func (bd *BidRepository) appendToLedger(ctx context.Context, bid bid_entity.Bid) {
+	if bd.Ledger == nil {
+		return
+	}
+	if err := bd.Ledger.AppendEvent(ctx, ledger_entity.NewBidPlacedEvent(bid)); err != nil {
+		logger.Error("Error trying to append bid to the ledger", err, zap.String("bid_id", bid.Id))
+	}
+}
+
 func getAuctionInterval() time.Duration {
 	auctionInterval := os.Getenv("AUCTION_INTERVAL")
 	duration, err := time.ParseDuration(auctionInterval)
@@ -5,10 +5,12 @@ import (
 	"auctionService/internal/entity/bid_entity"
 	"auctionService/internal/internal_error"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -52,7 +54,12 @@ func (bd *BidRepository) FindWinningBidByAuctionId(
 
 	var bidEntityMongo BidEntityMongo
 	opts := options.FindOne().SetSort(bson.D{{Key: "amount", Value: -1}})
-	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo); err != nil {
+	err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("No bids found for auctionId %s", auctionId))
+	}
+	if err != nil {
 		logger.Error("Error trying to find the auction winner", err)
 		return nil, internal_error.NewInternalServerError("Error trying to find the auction winner")
 	}
@@ -65,3 +72,35 @@ func (bd *BidRepository) FindWinningBidByAuctionId(
 		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
 	}, nil
 }
+
+func (bd *BidRepository) FindBidsByUserId(
+	ctx context.Context, userId string) ([]bid_entity.Bid, *internal_error.InternalError) {
+	filter := bson.M{"user_id": userId}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+	cursor, err := bd.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error trying to find bids by userId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find bids by userId")
+	}
+	defer cursor.Close(ctx)
+
+	var bidEntitiesMongo []BidEntityMongo
+	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
+		logger.Error("Error trying to decode bids by userId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find bids by userId")
+	}
+
+	bidEntities := make([]bid_entity.Bid, 0, len(bidEntitiesMongo))
+	for _, bidEntityMongo := range bidEntitiesMongo {
+		bidEntities = append(bidEntities, bid_entity.Bid{
+			Id:        bidEntityMongo.Id,
+			UserId:    bidEntityMongo.UserId,
+			AuctionId: bidEntityMongo.AuctionId,
+			Amount:    bidEntityMongo.Amount,
+			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+		})
+	}
+
+	return bidEntities, nil
+}
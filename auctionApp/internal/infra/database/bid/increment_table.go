@@ -0,0 +1,78 @@
+package bid
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/bid_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// incrementTableId é o documento único que guarda a tabela de incrementos
+const incrementTableId = "default"
+
+type IncrementBandMongo struct {
+	UpTo      float64 `bson:"up_to"`
+	Increment float64 `bson:"increment"`
+}
+
+type IncrementTableMongo struct {
+	Id        string               `bson:"_id"`
+	Bands     []IncrementBandMongo `bson:"bands"`
+	UpdatedAt int64                `bson:"updated_at"`
+}
+
+type IncrementTableRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewIncrementTableRepository(database *mongo.Database) *IncrementTableRepository {
+	return &IncrementTableRepository{
+		Collection: database.Collection("bid_increments"),
+	}
+}
+
+func (ir *IncrementTableRepository) FindIncrementTable(
+	ctx context.Context) (bid_entity.IncrementTable, *internal_error.InternalError) {
+	var tableMongo IncrementTableMongo
+	err := ir.Collection.FindOne(ctx, bson.M{"_id": incrementTableId}).Decode(&tableMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return bid_entity.DefaultIncrementTable, nil
+	}
+	if err != nil {
+		logger.Error("Error trying to find bid increment table", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find bid increment table")
+	}
+
+	table := make(bid_entity.IncrementTable, 0, len(tableMongo.Bands))
+	for _, band := range tableMongo.Bands {
+		table = append(table, bid_entity.IncrementBand{UpTo: band.UpTo, Increment: band.Increment})
+	}
+
+	return table, nil
+}
+
+func (ir *IncrementTableRepository) UpdateIncrementTable(
+	ctx context.Context, table bid_entity.IncrementTable) *internal_error.InternalError {
+	tableMongo := &IncrementTableMongo{
+		Id:        incrementTableId,
+		Bands:     make([]IncrementBandMongo, 0, len(table)),
+		UpdatedAt: time.Now().Unix(),
+	}
+	for _, band := range table {
+		tableMongo.Bands = append(tableMongo.Bands, IncrementBandMongo{UpTo: band.UpTo, Increment: band.Increment})
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := ir.Collection.ReplaceOne(ctx, bson.M{"_id": incrementTableId}, tableMongo, opts); err != nil {
+		logger.Error("Error trying to update bid increment table", err)
+		return internal_error.NewInternalServerError("Error trying to update bid increment table")
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package bid
+
+import (
+	"auctionService/internal/entity/bid_entity"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestIncrementTableRepository_FindIncrementTable(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should map stored bands", func(mt *mtest.T) {
+		// Arrange
+		repo := NewIncrementTableRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.bid_increments", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "default"},
+			{Key: "bands", Value: bson.A{
+				bson.D{{Key: "up_to", Value: 50.0}, {Key: "increment", Value: 0.5}},
+				bson.D{{Key: "up_to", Value: 0.0}, {Key: "increment", Value: 2.0}},
+			}},
+		}))
+
+		// Act
+		table, err := repo.FindIncrementTable(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, bid_entity.IncrementTable{{UpTo: 50, Increment: 0.5}, {UpTo: 0, Increment: 2}}, table)
+		assert.Equal(t, "bid_increments", repo.Collection.Name())
+	})
+
+	mt.Run("should fall back to the default table", func(mt *mtest.T) {
+		// Arrange
+		repo := NewIncrementTableRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.bid_increments", mtest.FirstBatch))
+
+		// Act
+		table, err := repo.FindIncrementTable(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, bid_entity.DefaultIncrementTable, table)
+	})
+
+	mt.Run("should return error when find fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewIncrementTableRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "find error",
+		}))
+
+		// Act
+		table, err := repo.FindIncrementTable(context.Background())
+
+		// Assert
+		assert.Nil(t, table)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error trying to find bid increment table")
+	})
+}
+
+func TestIncrementTableRepository_UpdateIncrementTable(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should upsert the table", func(mt *mtest.T) {
+		// Arrange
+		repo := NewIncrementTableRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		// Act
+		err := repo.UpdateIncrementTable(context.Background(), bid_entity.DefaultIncrementTable)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+
+	mt.Run("should return error when replace fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewIncrementTableRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "replace error",
+		}))
+
+		// Act
+		err := repo.UpdateIncrementTable(context.Background(), bid_entity.DefaultIncrementTable)
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error trying to update bid increment table")
+	})
+}
@@ -0,0 +1,122 @@
+package ledger
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/ledger_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventEntityMongo guarda o timestamp em nanossegundos para manter a ordem
+// de lances feitos no mesmo segundo
+type EventEntityMongo struct {
+	Id        string                       `bson:"_id"`
+	Type      ledger_entity.EventType      `bson:"type"`
+	AuctionId string                       `bson:"auction_id"`
+	BidId     string                       `bson:"bid_id,omitempty"`
+	UserId    string                       `bson:"user_id,omitempty"`
+	Amount    float64                      `bson:"amount,omitempty"`
+	Status    auction_entity.AuctionStatus `bson:"status"`
+	Timestamp int64                        `bson:"timestamp"`
+}
+
+// LedgerRepository só insere e lê eventos; a única atualização é a troca do
+// usuário por um pseudônimo na eliminação de dados, e nada remove entradas
+type LedgerRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewLedgerRepository(database *mongo.Database) *LedgerRepository {
+	return &LedgerRepository{
+		Collection: database.Collection("ledger"),
+	}
+}
+
+func (lr *LedgerRepository) AppendEvent(
+	ctx context.Context, event ledger_entity.Event) *internal_error.InternalError {
+	eventEntityMongo := &EventEntityMongo{
+		Id:        event.Id,
+		Type:      event.Type,
+		AuctionId: event.AuctionId,
+		BidId:     event.BidId,
+		UserId:    event.UserId,
+		Amount:    event.Amount,
+		Status:    event.Status,
+		Timestamp: event.Timestamp.UnixNano(),
+	}
+
+	if _, err := lr.Collection.InsertOne(ctx, eventEntityMongo); err != nil {
+		logger.Error("Error trying to append ledger event", err)
+		return internal_error.NewInternalServerError("Error trying to append ledger event")
+	}
+
+	return nil
+}
+
+func (lr *LedgerRepository) FindEvents(
+	ctx context.Context, auctionId string) ([]ledger_entity.Event, *internal_error.InternalError) {
+	filter := bson.M{}
+	if auctionId != "" {
+		filter["auction_id"] = auctionId
+	}
+
+	return lr.findEvents(ctx, filter)
+}
+
+func (lr *LedgerRepository) FindEventsByUserId(
+	ctx context.Context, userId string) ([]ledger_entity.Event, *internal_error.InternalError) {
+	return lr.findEvents(ctx, bson.M{"user_id": userId})
+}
+
+func (lr *LedgerRepository) AnonymizeUserEvents(
+	ctx context.Context, userId, pseudonym string) (int64, *internal_error.InternalError) {
+	filter := bson.M{"user_id": userId}
+	update := bson.M{"$set": bson.M{"user_id": pseudonym}}
+
+	result, err := lr.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to anonymize ledger events", err)
+		return 0, internal_error.NewInternalServerError("Error trying to anonymize ledger events")
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (lr *LedgerRepository) findEvents(
+	ctx context.Context, filter bson.M) ([]ledger_entity.Event, *internal_error.InternalError) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+	cursor, err := lr.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error finding ledger events", err)
+		return nil, internal_error.NewInternalServerError("Error finding ledger events")
+	}
+	defer cursor.Close(ctx)
+
+	var eventsMongo []EventEntityMongo
+	if err := cursor.All(ctx, &eventsMongo); err != nil {
+		logger.Error("Error decoding ledger events", err)
+		return nil, internal_error.NewInternalServerError("Error decoding ledger events")
+	}
+
+	events := make([]ledger_entity.Event, 0, len(eventsMongo))
+	for _, event := range eventsMongo {
+		events = append(events, ledger_entity.Event{
+			Id:        event.Id,
+			Type:      event.Type,
+			AuctionId: event.AuctionId,
+			BidId:     event.BidId,
+			UserId:    event.UserId,
+			Amount:    event.Amount,
+			Status:    event.Status,
+			Timestamp: time.Unix(0, event.Timestamp),
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,139 @@
+package ledger
+
+import (
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/ledger_entity"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestLedgerRepository_AppendEvent(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should append event successfully", func(mt *mtest.T) {
+		// Arrange
+		repo := NewLedgerRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.AppendEvent(context.Background(), ledger_entity.Event{
+			Id:        "event-id",
+			Type:      ledger_entity.BidPlaced,
+			AuctionId: "auction-id",
+			BidId:     "bid-id",
+			UserId:    "user-id",
+			Amount:    150,
+			Timestamp: time.Now(),
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "ledger", repo.Collection.Name())
+	})
+
+	mt.Run("should return error when database insert fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewLedgerRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		// Act
+		err := repo.AppendEvent(context.Background(), ledger_entity.Event{Id: "event-id"})
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error trying to append ledger event")
+	})
+}
+
+func TestLedgerRepository_FindEvents(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should map events from cursor keeping nanosecond timestamps", func(mt *mtest.T) {
+		// Arrange
+		repo := NewLedgerRepository(mt.DB)
+		timestamp := time.Date(2025, 3, 10, 12, 0, 0, 123456789, time.UTC)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.ledger", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: "event-1"},
+				{Key: "type", Value: string(ledger_entity.AuctionStatusChanged)},
+				{Key: "auction_id", Value: "auction-id"},
+				{Key: "status", Value: int32(auction_entity.Completed)},
+				{Key: "timestamp", Value: timestamp.UnixNano()},
+			}))
+
+		// Act
+		events, err := repo.FindEvents(context.Background(), "auction-id")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, ledger_entity.AuctionStatusChanged, events[0].Type)
+		assert.Equal(t, auction_entity.Completed, events[0].Status)
+		assert.True(t, timestamp.Equal(events[0].Timestamp))
+	})
+
+	mt.Run("should return error when find fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewLedgerRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "bad query",
+		}))
+
+		// Act
+		events, err := repo.FindEvents(context.Background(), "")
+
+		// Assert
+		assert.Nil(t, events)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error finding ledger events")
+	})
+}
+
+func TestLedgerRepository_AnonymizeUserEvents(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should return the number of anonymized events", func(mt *mtest.T) {
+		// Arrange
+		repo := NewLedgerRepository(mt.DB)
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: int32(2)},
+			{Key: "nModified", Value: int32(2)},
+		})
+
+		// Act
+		count, err := repo.AnonymizeUserEvents(context.Background(), "user-id", "pseudonym")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	mt.Run("should return error when update fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewLedgerRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "update error",
+		}))
+
+		// Act
+		count, err := repo.AnonymizeUserEvents(context.Background(), "user-id", "pseudonym")
+
+		// Assert
+		assert.Zero(t, count)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error trying to anonymize ledger events")
+	})
+}
@@ -0,0 +1,92 @@
+package moderation
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/moderation_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DecisionEntityMongo struct {
+	Id          string                    `bson:"_id"`
+	AuctionId   string                    `bson:"auction_id"`
+	ProductName string                    `bson:"product_name"`
+	Description string                    `bson:"description"`
+	Verdict     moderation_entity.Verdict `bson:"verdict"`
+	Reasons     []string                  `bson:"reasons"`
+	Timestamp   int64                     `bson:"timestamp"`
+}
+
+type DecisionRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewDecisionRepository(database *mongo.Database) *DecisionRepository {
+	return &DecisionRepository{
+		Collection: database.Collection("moderation_decisions"),
+	}
+}
+
+func (dr *DecisionRepository) CreateDecision(
+	ctx context.Context, decision *moderation_entity.Decision) *internal_error.InternalError {
+	decisionEntityMongo := &DecisionEntityMongo{
+		Id:          decision.Id,
+		AuctionId:   decision.AuctionId,
+		ProductName: decision.ProductName,
+		Description: decision.Description,
+		Verdict:     decision.Verdict,
+		Reasons:     decision.Reasons,
+		Timestamp:   decision.Timestamp.Unix(),
+	}
+
+	if _, err := dr.Collection.InsertOne(ctx, decisionEntityMongo); err != nil {
+		logger.Error("Error trying to insert moderation decision", err)
+		return internal_error.NewInternalServerError("Error trying to insert moderation decision")
+	}
+
+	return nil
+}
+
+// FindDecisions lists decisions newest first; a negative verdict returns all of them
+func (dr *DecisionRepository) FindDecisions(
+	ctx context.Context,
+	verdict moderation_entity.Verdict) ([]moderation_entity.Decision, *internal_error.InternalError) {
+	filter := bson.M{}
+	if verdict >= 0 {
+		filter["verdict"] = verdict
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	cursor, err := dr.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error finding moderation decisions", err)
+		return nil, internal_error.NewInternalServerError("Error finding moderation decisions")
+	}
+	defer cursor.Close(ctx)
+
+	var decisionsMongo []DecisionEntityMongo
+	if err := cursor.All(ctx, &decisionsMongo); err != nil {
+		logger.Error("Error decoding moderation decisions", err)
+		return nil, internal_error.NewInternalServerError("Error decoding moderation decisions")
+	}
+
+	decisions := make([]moderation_entity.Decision, 0, len(decisionsMongo))
+	for _, decision := range decisionsMongo {
+		decisions = append(decisions, moderation_entity.Decision{
+			Id:          decision.Id,
+			AuctionId:   decision.AuctionId,
+			ProductName: decision.ProductName,
+			Description: decision.Description,
+			Verdict:     decision.Verdict,
+			Reasons:     decision.Reasons,
+			Timestamp:   time.Unix(decision.Timestamp, 0),
+		})
+	}
+
+	return decisions, nil
+}
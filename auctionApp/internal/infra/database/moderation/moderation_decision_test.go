@@ -0,0 +1,102 @@
+package moderation
+
+import (
+	"auctionService/internal/entity/moderation_entity"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestDecisionRepository_CreateDecision(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should insert decision successfully", func(mt *mtest.T) {
+		// Arrange
+		repo := NewDecisionRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.CreateDecision(context.Background(), &moderation_entity.Decision{
+			Id:          "decision-id",
+			AuctionId:   "auction-id",
+			ProductName: "Watch replica",
+			Description: "Looks like the real one",
+			Verdict:     moderation_entity.Flagged,
+			Reasons:     []string{`flagged term "replica"`},
+			Timestamp:   time.Now(),
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "moderation_decisions", repo.Collection.Name())
+	})
+
+	mt.Run("should return error when database insert fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewDecisionRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		// Act
+		err := repo.CreateDecision(context.Background(), &moderation_entity.Decision{Id: "decision-id"})
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error trying to insert moderation decision")
+	})
+}
+
+func TestDecisionRepository_FindDecisions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should map decisions from cursor", func(mt *mtest.T) {
+		// Arrange
+		repo := NewDecisionRepository(mt.DB)
+		timestamp := time.Now().Unix()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.moderation_decisions", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "decision-id"},
+			{Key: "auction_id", Value: "auction-id"},
+			{Key: "product_name", Value: "Great deal"},
+			{Key: "description", Value: "Not a scam"},
+			{Key: "verdict", Value: int32(moderation_entity.Rejected)},
+			{Key: "reasons", Value: bson.A{`blocked term "scam"`}},
+			{Key: "timestamp", Value: timestamp},
+		}))
+
+		// Act
+		decisions, err := repo.FindDecisions(context.Background(), moderation_entity.Rejected)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, decisions, 1)
+		assert.Equal(t, "auction-id", decisions[0].AuctionId)
+		assert.Equal(t, moderation_entity.Rejected, decisions[0].Verdict)
+		assert.Equal(t, []string{`blocked term "scam"`}, decisions[0].Reasons)
+		assert.Equal(t, timestamp, decisions[0].Timestamp.Unix())
+	})
+
+	mt.Run("should return error when find fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewDecisionRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "find error",
+		}))
+
+		// Act
+		decisions, err := repo.FindDecisions(context.Background(), -1)
+
+		// Assert
+		assert.Nil(t, decisions)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error finding moderation decisions")
+	})
+}
@@ -0,0 +1,92 @@
+package privacy
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/privacy_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PrivacyRequestEntityMongo struct {
+	Id        string                     `bson:"_id"`
+	UserHash  string                     `bson:"user_hash"`
+	Type      privacy_entity.RequestType `bson:"type"`
+	Auctions  int64                      `bson:"auctions"`
+	Bids      int64                      `bson:"bids"`
+	Events    int64                      `bson:"events"`
+	Timestamp int64                      `bson:"timestamp"`
+}
+
+// PrivacyRequestRepository é a trilha de auditoria das solicitações de
+// titulares: as entradas só são inseridas e lidas
+type PrivacyRequestRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPrivacyRequestRepository(database *mongo.Database) *PrivacyRequestRepository {
+	return &PrivacyRequestRepository{
+		Collection: database.Collection("privacy_requests"),
+	}
+}
+
+func (pr *PrivacyRequestRepository) CreatePrivacyRequest(
+	ctx context.Context, request *privacy_entity.PrivacyRequest) *internal_error.InternalError {
+	requestEntityMongo := &PrivacyRequestEntityMongo{
+		Id:        request.Id,
+		UserHash:  request.UserHash,
+		Type:      request.Type,
+		Auctions:  request.Auctions,
+		Bids:      request.Bids,
+		Events:    request.Events,
+		Timestamp: request.Timestamp.Unix(),
+	}
+
+	if _, err := pr.Collection.InsertOne(ctx, requestEntityMongo); err != nil {
+		logger.Error("Error trying to insert privacy request", err)
+		return internal_error.NewInternalServerError("Error trying to insert privacy request")
+	}
+
+	return nil
+}
+
+func (pr *PrivacyRequestRepository) FindPrivacyRequests(
+	ctx context.Context, userHash string) ([]privacy_entity.PrivacyRequest, *internal_error.InternalError) {
+	filter := bson.M{}
+	if userHash != "" {
+		filter["user_hash"] = userHash
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	cursor, err := pr.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error finding privacy requests", err)
+		return nil, internal_error.NewInternalServerError("Error finding privacy requests")
+	}
+	defer cursor.Close(ctx)
+
+	var requestsMongo []PrivacyRequestEntityMongo
+	if err := cursor.All(ctx, &requestsMongo); err != nil {
+		logger.Error("Error decoding privacy requests", err)
+		return nil, internal_error.NewInternalServerError("Error decoding privacy requests")
+	}
+
+	requests := make([]privacy_entity.PrivacyRequest, 0, len(requestsMongo))
+	for _, request := range requestsMongo {
+		requests = append(requests, privacy_entity.PrivacyRequest{
+			Id:        request.Id,
+			UserHash:  request.UserHash,
+			Type:      request.Type,
+			Auctions:  request.Auctions,
+			Bids:      request.Bids,
+			Events:    request.Events,
+			Timestamp: time.Unix(request.Timestamp, 0),
+		})
+	}
+
+	return requests, nil
+}
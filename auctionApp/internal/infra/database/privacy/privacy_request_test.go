@@ -0,0 +1,95 @@
+package privacy
+
+import (
+	"auctionService/internal/entity/privacy_entity"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestPrivacyRequestRepository_CreatePrivacyRequest(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should insert request successfully", func(mt *mtest.T) {
+		// Arrange
+		repo := NewPrivacyRequestRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.CreatePrivacyRequest(context.Background(),
+			privacy_entity.NewPrivacyRequest("user-id", privacy_entity.DataErasure, 1, 3, 4))
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "privacy_requests", repo.Collection.Name())
+	})
+
+	mt.Run("should return error when database insert fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewPrivacyRequestRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		// Act
+		err := repo.CreatePrivacyRequest(context.Background(), &privacy_entity.PrivacyRequest{Id: "request-id"})
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error trying to insert privacy request")
+	})
+}
+
+func TestPrivacyRequestRepository_FindPrivacyRequests(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("should map requests from cursor", func(mt *mtest.T) {
+		// Arrange
+		repo := NewPrivacyRequestRepository(mt.DB)
+		timestamp := time.Now().Unix()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "auctions.privacy_requests", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "request-id"},
+			{Key: "user_hash", Value: privacy_entity.HashUserId("user-id")},
+			{Key: "type", Value: "erasure"},
+			{Key: "auctions", Value: int64(1)},
+			{Key: "bids", Value: int64(3)},
+			{Key: "events", Value: int64(4)},
+			{Key: "timestamp", Value: timestamp},
+		}))
+
+		// Act
+		requests, err := repo.FindPrivacyRequests(context.Background(), privacy_entity.HashUserId("user-id"))
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, requests, 1)
+		assert.Equal(t, privacy_entity.HashUserId("user-id"), requests[0].UserHash)
+		assert.Equal(t, privacy_entity.DataErasure, requests[0].Type)
+		assert.Equal(t, int64(3), requests[0].Bids)
+		assert.Equal(t, timestamp, requests[0].Timestamp.Unix())
+	})
+
+	mt.Run("should return error when find fails", func(mt *mtest.T) {
+		// Arrange
+		repo := NewPrivacyRequestRepository(mt.DB)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    2,
+			Message: "find error",
+		}))
+
+		// Act
+		requests, err := repo.FindPrivacyRequests(context.Background(), "")
+
+		// Assert
+		assert.Nil(t, requests)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Message, "Error finding privacy requests")
+	})
+}
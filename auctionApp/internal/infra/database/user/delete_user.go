@@ -0,0 +1,21 @@
+package user
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+func (ur *UserRepository) DeleteUser(
+	ctx context.Context, userId string) (bool, *internal_error.InternalError) {
+	result, err := ur.Collection.DeleteOne(ctx, bson.M{"_id": userId})
+	if err != nil {
+		logger.Error("Error trying to delete user", err, zap.String("user_id", userId))
+		return false, internal_error.NewInternalServerError("Error trying to delete user")
+	}
+
+	return result.DeletedCount > 0, nil
+}
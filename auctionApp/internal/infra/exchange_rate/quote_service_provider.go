@@ -0,0 +1,138 @@
+package exchange_rate
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/currency_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// QuoteServiceSource identifica as cotações obtidas do serviço de cotação
+const QuoteServiceSource = "quote_service"
+
+const defaultCacheTTL = time.Minute
+
+type quoteResponse struct {
+	Bid string `json:"bid"`
+}
+
+// QuoteServiceProvider obtém a cotação USD-BRL do serviço de cotação do
+// clientServerAPI (GET /cotacao) e deriva BRL-USD pelo inverso. A cotação fica
+// em cache por cacheTTL para não consultar o serviço a cada exibição.
+type QuoteServiceProvider struct {
+	url      string
+	client   *http.Client
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	cached *currency_entity.Rate
+}
+
+func NewQuoteServiceProvider(url string, cacheTTL time.Duration) *QuoteServiceProvider {
+	return &QuoteServiceProvider{
+		url:      url,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		cacheTTL: cacheTTL,
+	}
+}
+
+// NewQuoteServiceProviderFromEnv lê QUOTE_SERVICE_URL e QUOTE_CACHE_TTL
+// (padrão 1m). Retorna nil quando a URL não está configurada.
+func NewQuoteServiceProviderFromEnv() *QuoteServiceProvider {
+	url := os.Getenv("QUOTE_SERVICE_URL")
+	if url == "" {
+		return nil
+	}
+
+	cacheTTL := defaultCacheTTL
+	if value := os.Getenv("QUOTE_CACHE_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			logger.Error("Error parsing QUOTE_CACHE_TTL, using default 1 minute", err)
+		} else {
+			cacheTTL = parsed
+		}
+	}
+
+	return NewQuoteServiceProvider(url, cacheTTL)
+}
+
+func (p *QuoteServiceProvider) FindRate(
+	ctx context.Context, from, to string) (*currency_entity.Rate, *internal_error.InternalError) {
+	switch {
+	case from == to:
+		return currency_entity.IdentityRate(from, time.Now()), nil
+	case from == currency_entity.USD && to == currency_entity.BRL:
+		return p.usdToBrl(ctx)
+	case from == currency_entity.BRL && to == currency_entity.USD:
+		rate, err := p.usdToBrl(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return rate.Inverse(), nil
+	default:
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("conversion from %s to %s is not supported", from, to))
+	}
+}
+
+func (p *QuoteServiceProvider) usdToBrl(ctx context.Context) (*currency_entity.Rate, *internal_error.InternalError) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cached != nil && time.Since(p.cached.Timestamp) < p.cacheTTL {
+		return p.cached, nil
+	}
+
+	bid, err := p.fetchBid(ctx)
+	if err != nil {
+		logger.Error("Error trying to fetch quote", err, zap.String("url", p.url))
+		return nil, internal_error.NewInternalServerError("Error trying to fetch exchange rate")
+	}
+
+	p.cached = &currency_entity.Rate{
+		From:      currency_entity.USD,
+		To:        currency_entity.BRL,
+		Value:     bid,
+		Source:    QuoteServiceSource,
+		Timestamp: time.Now(),
+	}
+	return p.cached, nil
+}
+
+func (p *QuoteServiceProvider) fetchBid(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("quote service returned status %d", resp.StatusCode)
+	}
+
+	var quote quoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		return 0, err
+	}
+
+	bid, err := strconv.ParseFloat(quote.Bid, 64)
+	if err != nil || bid <= 0 {
+		return 0, fmt.Errorf("invalid quote bid %q", quote.Bid)
+	}
+	return bid, nil
+}
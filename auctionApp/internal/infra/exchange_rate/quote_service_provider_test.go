@@ -0,0 +1,146 @@
+package exchange_rate
+
+import (
+	"auctionService/internal/entity/currency_entity"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newQuoteServer(t *testing.T, status int, body string, calls *int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestQuoteServiceProvider_FindRate(t *testing.T) {
+	t.Run("should return the USD-BRL bid", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		server := newQuoteServer(t, http.StatusOK, `{"bid":"5.1234"}`, &calls)
+		provider := NewQuoteServiceProvider(server.URL, time.Minute)
+
+		// Act
+		rate, err := provider.FindRate(context.Background(), currency_entity.USD, currency_entity.BRL)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, currency_entity.USD, rate.From)
+		assert.Equal(t, currency_entity.BRL, rate.To)
+		assert.Equal(t, 5.1234, rate.Value)
+		assert.Equal(t, QuoteServiceSource, rate.Source)
+	})
+
+	t.Run("should invert the bid for BRL-USD", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		server := newQuoteServer(t, http.StatusOK, `{"bid":"5.0000"}`, &calls)
+		provider := NewQuoteServiceProvider(server.URL, time.Minute)
+
+		// Act
+		rate, err := provider.FindRate(context.Background(), currency_entity.BRL, currency_entity.USD)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, currency_entity.BRL, rate.From)
+		assert.Equal(t, currency_entity.USD, rate.To)
+		assert.Equal(t, 0.2, rate.Value)
+	})
+
+	t.Run("should cache the quote for the ttl", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		server := newQuoteServer(t, http.StatusOK, `{"bid":"5.1234"}`, &calls)
+		provider := NewQuoteServiceProvider(server.URL, time.Minute)
+
+		// Act
+		provider.FindRate(context.Background(), currency_entity.USD, currency_entity.BRL)
+		provider.FindRate(context.Background(), currency_entity.BRL, currency_entity.USD)
+
+		// Assert
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should not call the service for the same currency", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		server := newQuoteServer(t, http.StatusOK, `{"bid":"5.1234"}`, &calls)
+		provider := NewQuoteServiceProvider(server.URL, time.Minute)
+
+		// Act
+		rate, err := provider.FindRate(context.Background(), currency_entity.BRL, currency_entity.BRL)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 1.0, rate.Value)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("should reject unsupported pairs", func(t *testing.T) {
+		// Arrange
+		provider := NewQuoteServiceProvider("http://unused", time.Minute)
+
+		// Act
+		rate, err := provider.FindRate(context.Background(), "EUR", currency_entity.BRL)
+
+		// Assert
+		assert.Nil(t, rate)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+
+	t.Run("should return error when the service fails", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		server := newQuoteServer(t, http.StatusInternalServerError, "Failed to fetch exchange rate", &calls)
+		provider := NewQuoteServiceProvider(server.URL, time.Minute)
+
+		// Act
+		rate, err := provider.FindRate(context.Background(), currency_entity.USD, currency_entity.BRL)
+
+		// Assert
+		assert.Nil(t, rate)
+		assert.Equal(t, "internal_server_error", err.Err)
+	})
+
+	t.Run("should return error for an invalid bid", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		server := newQuoteServer(t, http.StatusOK, `{"bid":"abc"}`, &calls)
+		provider := NewQuoteServiceProvider(server.URL, time.Minute)
+
+		// Act
+		_, err := provider.FindRate(context.Background(), currency_entity.USD, currency_entity.BRL)
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
+
+func TestNewQuoteServiceProviderFromEnv(t *testing.T) {
+	t.Run("should return nil without QUOTE_SERVICE_URL", func(t *testing.T) {
+		os.Unsetenv("QUOTE_SERVICE_URL")
+
+		assert.Nil(t, NewQuoteServiceProviderFromEnv())
+	})
+
+	t.Run("should read the url and cache ttl", func(t *testing.T) {
+		os.Setenv("QUOTE_SERVICE_URL", "http://quote:8080/cotacao")
+		os.Setenv("QUOTE_CACHE_TTL", "30s")
+		defer os.Unsetenv("QUOTE_SERVICE_URL")
+		defer os.Unsetenv("QUOTE_CACHE_TTL")
+
+		provider := NewQuoteServiceProviderFromEnv()
+
+		assert.Equal(t, "http://quote:8080/cotacao", provider.url)
+		assert.Equal(t, 30*time.Second, provider.cacheTTL)
+	})
+}
@@ -3,27 +3,64 @@ package auction_usecase
 import (
 	"auctionService/internal/entity/auction_entity"
 	"auctionService/internal/entity/bid_entity"
+	"auctionService/internal/entity/currency_entity"
+	"auctionService/internal/entity/moderation_entity"
 	"auctionService/internal/internal_error"
 	"auctionService/internal/usecase/bid_usecase"
 	"context"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type AuctionInputDTO struct {
+	SellerId    string           `json:"seller_id" binding:"omitempty,uuid"`
 	ProductName string           `json:"product_name" binding:"required,min=1"`
 	Category    string           `json:"category" binding:"required,min=2"`
 	Description string           `json:"description" binding:"required,min=10,max=200"`
 	Condition   ProductCondition `json:"condition" binding:"oneof=0 1 2"`
+	// Currency é a moeda dos lances (BRL ou USD); padrão BRL
+	Currency string `json:"currency" binding:"omitempty,len=3"`
 }
 
 type AuctionOutputDTO struct {
-	Id          string           `json:"id"`
-	ProductName string           `json:"product_name"`
-	Category    string           `json:"category"`
-	Description string           `json:"description"`
-	Condition   ProductCondition `json:"condition"`
-	Status      AuctionStatus    `json:"status"`
-	Timestamp   time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Id          string            `json:"id"`
+	SellerId    string            `json:"seller_id,omitempty"`
+	ProductName string            `json:"product_name"`
+	Category    string            `json:"category"`
+	Description string            `json:"description"`
+	Condition   ProductCondition  `json:"condition"`
+	Currency    string            `json:"currency"`
+	Status      AuctionStatus     `json:"status"`
+	Moderation  ModerationVerdict `json:"moderation"`
+	Timestamp   time.Time         `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+
+	// Cotação gravada no encerramento, para auditoria
+	SettlementRate *ExchangeRateDTO `json:"settlement_rate,omitempty"`
+	// Valores na moeda pedida em ?currency=, apenas para exibição
+	Converted *ConvertedAmountsDTO `json:"converted,omitempty"`
+
+	// Preenchidos apenas no detalhe de um leilão ativo, para sugerir lances válidos
+	CurrentBid     float64                           `json:"current_bid,omitempty"`
+	MinimumNextBid float64                           `json:"minimum_next_bid,omitempty"`
+	BidIncrements  []bid_usecase.BidIncrementBandDTO `json:"bid_increments,omitempty"`
+}
+
+type ExchangeRateDTO struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      float64   `json:"rate"`
+	Source    string    `json:"source"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type ConvertedAmountsDTO struct {
+	Currency       string          `json:"currency"`
+	ExchangeRate   ExchangeRateDTO `json:"exchange_rate"`
+	CurrentBid     float64         `json:"current_bid,omitempty"`
+	MinimumNextBid float64         `json:"minimum_next_bid,omitempty"`
+	WinningBid     float64         `json:"winning_bid,omitempty"`
 }
 
 type WinningInfoOutputDTO struct {
@@ -33,20 +70,38 @@ type WinningInfoOutputDTO struct {
 
 func NewAuctionUseCase(
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
-	bidRepositoryInterface bid_entity.BidEntityRepository) AuctionUseCaseInterface {
+	bidRepositoryInterface bid_entity.BidEntityRepository,
+	contentValidator moderation_entity.ContentValidator,
+	decisionRepositoryInterface moderation_entity.DecisionRepositoryInterface,
+	incrementTableRepositoryInterface bid_entity.IncrementTableRepositoryInterface,
+	currentBidFinder CurrentBidFinder,
+	rateProvider currency_entity.RateProvider) AuctionUseCaseInterface {
 	return &AuctionUseCase{
-		auctionRepositoryInterface: auctionRepositoryInterface,
-		bidRepositoryInterface:     bidRepositoryInterface,
+		auctionRepositoryInterface:        auctionRepositoryInterface,
+		bidRepositoryInterface:            bidRepositoryInterface,
+		contentValidator:                  contentValidator,
+		decisionRepositoryInterface:       decisionRepositoryInterface,
+		incrementTableRepositoryInterface: incrementTableRepositoryInterface,
+		currentBidFinder:                  currentBidFinder,
+		rateProvider:                      rateProvider,
 	}
 }
 
+// CurrentBidFinder informa o maior lance de um leilão, incluindo os que ainda
+// aguardam no lote de inserção, o mesmo usado para validar novos lances
+type CurrentBidFinder interface {
+	CurrentBid(
+		ctx context.Context, auctionId string) (float64, *internal_error.InternalError)
+}
+
 type AuctionUseCaseInterface interface {
 	CreateAuction(
 		ctx context.Context,
 		auctionInput AuctionInputDTO) *internal_error.InternalError
 
+	// FindAuctionById converte os valores para currency quando informada
 	FindAuctionById(
-		ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError)
+		ctx context.Context, id, currency string) (*AuctionOutputDTO, *internal_error.InternalError)
 
 	FindAuctions(
 		ctx context.Context,
@@ -55,29 +110,45 @@ type AuctionUseCaseInterface interface {
 
 	FindWinningBidByAuctionId(
 		ctx context.Context,
-		auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError)
+		auctionId, currency string) (*WinningInfoOutputDTO, *internal_error.InternalError)
+
+	FindAuctionFacets(
+		ctx context.Context, query string) (*AuctionFacetsOutputDTO, *internal_error.InternalError)
 }
 
 type ProductCondition int64
 type AuctionStatus int64
+type ModerationVerdict int64
 
 type AuctionUseCase struct {
-	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
-	bidRepositoryInterface     bid_entity.BidEntityRepository
+	auctionRepositoryInterface        auction_entity.AuctionRepositoryInterface
+	bidRepositoryInterface            bid_entity.BidEntityRepository
+	contentValidator                  moderation_entity.ContentValidator
+	decisionRepositoryInterface       moderation_entity.DecisionRepositoryInterface
+	incrementTableRepositoryInterface bid_entity.IncrementTableRepositoryInterface
+	currentBidFinder                  CurrentBidFinder
+	// rateProvider é opcional; sem ele os valores não são convertidos
+	rateProvider currency_entity.RateProvider
 }
 
 func (au *AuctionUseCase) CreateAuction(
 	ctx context.Context,
 	auctionInput AuctionInputDTO) *internal_error.InternalError {
 	auction, err := auction_entity.CreateAuction(
+		auctionInput.SellerId,
 		auctionInput.ProductName,
 		auctionInput.Category,
 		auctionInput.Description,
-		auction_entity.ProductCondition(auctionInput.Condition))
+		auction_entity.ProductCondition(auctionInput.Condition),
+		auctionInput.Currency)
 	if err != nil {
 		return err
 	}
 
+	if err := au.moderate(ctx, auction); err != nil {
+		return err
+	}
+
 	if err := au.auctionRepositoryInterface.CreateAuction(
 		auction); err != nil {
 		return err
@@ -85,3 +156,42 @@ func (au *AuctionUseCase) CreateAuction(
 
 	return nil
 }
+
+// moderate runs the content validation pipeline and records every flagged or
+// rejected listing for moderators. Flagged auctions are still created, marked
+// for review; rejected ones are refused.
+func (au *AuctionUseCase) moderate(
+	ctx context.Context, auction *auction_entity.Auction) *internal_error.InternalError {
+	result, err := au.contentValidator.Validate(ctx, moderation_entity.Content{
+		ProductName: auction.ProductName,
+		Description: auction.Description,
+	})
+	if err != nil {
+		return err
+	}
+
+	auction.Moderation = result.Verdict
+	if result.Verdict == moderation_entity.Approved {
+		return nil
+	}
+
+	decision := &moderation_entity.Decision{
+		Id:          uuid.New().String(),
+		AuctionId:   auction.Id,
+		ProductName: auction.ProductName,
+		Description: auction.Description,
+		Verdict:     result.Verdict,
+		Reasons:     result.Reasons,
+		Timestamp:   time.Now(),
+	}
+	if err := au.decisionRepositoryInterface.CreateDecision(ctx, decision); err != nil {
+		return err
+	}
+
+	if result.Verdict == moderation_entity.Rejected {
+		return internal_error.NewBadRequestError(
+			"auction content rejected: " + strings.Join(result.Reasons, "; "))
+	}
+
+	return nil
+}
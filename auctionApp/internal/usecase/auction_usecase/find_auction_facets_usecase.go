@@ -0,0 +1,44 @@
+package auction_usecase
+
+import (
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/internal_error"
+	"context"
+)
+
+type FacetCountOutputDTO struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type AuctionFacetsOutputDTO struct {
+	Categories   []FacetCountOutputDTO `json:"categories"`
+	Conditions   []FacetCountOutputDTO `json:"conditions"`
+	PriceBuckets []FacetCountOutputDTO `json:"price_buckets"`
+}
+
+func (au *AuctionUseCase) FindAuctionFacets(
+	ctx context.Context, query string) (*AuctionFacetsOutputDTO, *internal_error.InternalError) {
+	facets, err := au.auctionRepositoryInterface.FindAuctionFacets(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuctionFacetsOutputDTO{
+		Categories:   convertFacetCounts(facets.Categories),
+		Conditions:   convertFacetCounts(facets.Conditions),
+		PriceBuckets: convertFacetCounts(facets.PriceBuckets),
+	}, nil
+}
+
+func convertFacetCounts(counts []auction_entity.FacetCount) []FacetCountOutputDTO {
+	output := make([]FacetCountOutputDTO, 0, len(counts))
+	for _, c := range counts {
+		output = append(output, FacetCountOutputDTO{
+			Value: c.Value,
+			Count: c.Count,
+		})
+	}
+
+	return output
+}
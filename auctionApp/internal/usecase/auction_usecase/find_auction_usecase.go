@@ -3,27 +3,62 @@ package auction_usecase
 import (
 	"auctionService/configuration/logger"
 	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/currency_entity"
 	"auctionService/internal/internal_error"
 	"auctionService/internal/usecase/bid_usecase"
 	"context"
+	"time"
+
+	"go.uber.org/zap"
 )
 
 func (au *AuctionUseCase) FindAuctionById(
-	ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError) {
+	ctx context.Context, id, currency string) (*AuctionOutputDTO, *internal_error.InternalError) {
 	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AuctionOutputDTO{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   ProductCondition(auctionEntity.Condition),
-		Status:      AuctionStatus(auctionEntity.Status),
-		Timestamp:   auctionEntity.Timestamp,
-	}, nil
+	auctionOutput := toAuctionOutputDTO(auctionEntity)
+
+	if auctionEntity.Status == auction_entity.Active {
+		au.fillBidIncrements(ctx, &auctionOutput)
+	}
+
+	if currency != "" {
+		rate, err := au.findExchangeRate(ctx, auctionEntity, currency)
+		if err != nil {
+			return nil, err
+		}
+		auctionOutput.Converted = &ConvertedAmountsDTO{
+			Currency:       rate.To,
+			ExchangeRate:   toExchangeRateDTO(rate),
+			CurrentBid:     rate.Convert(auctionOutput.CurrentBid),
+			MinimumNextBid: rate.Convert(auctionOutput.MinimumNextBid),
+		}
+	}
+
+	return &auctionOutput, nil
+}
+
+// fillBidIncrements adds the current bid, the minimum next bid and the increment
+// table to the auction. Failures are logged and leave the fields empty.
+func (au *AuctionUseCase) fillBidIncrements(ctx context.Context, auctionOutput *AuctionOutputDTO) {
+	table, err := au.incrementTableRepositoryInterface.FindIncrementTable(ctx)
+	if err != nil {
+		logger.Error("Error trying to find bid increments", err, zap.String("auction_id", auctionOutput.Id))
+		return
+	}
+
+	currentBid, err := au.currentBidFinder.CurrentBid(ctx, auctionOutput.Id)
+	if err != nil {
+		logger.Error("Error trying to find current bid", err, zap.String("auction_id", auctionOutput.Id))
+		return
+	}
+	auctionOutput.CurrentBid = currentBid
+
+	auctionOutput.MinimumNextBid = table.MinimumNextBid(auctionOutput.CurrentBid)
+	auctionOutput.BidIncrements = bid_usecase.ToBidIncrementDTOs(table)
 }
 
 func (au *AuctionUseCase) FindAuctions(
@@ -37,16 +72,8 @@ func (au *AuctionUseCase) FindAuctions(
 	}
 
 	var auctionOutputs []AuctionOutputDTO
-	for _, value := range auctionEntities {
-		auctionOutputs = append(auctionOutputs, AuctionOutputDTO{
-			Id:          value.Id,
-			ProductName: value.ProductName,
-			Category:    value.Category,
-			Description: value.Description,
-			Condition:   ProductCondition(value.Condition),
-			Status:      AuctionStatus(value.Status),
-			Timestamp:   value.Timestamp,
-		})
+	for i := range auctionEntities {
+		auctionOutputs = append(auctionOutputs, toAuctionOutputDTO(&auctionEntities[i]))
 	}
 
 	return auctionOutputs, nil
@@ -54,20 +81,24 @@ func (au *AuctionUseCase) FindAuctions(
 
 func (au *AuctionUseCase) FindWinningBidByAuctionId(
 	ctx context.Context,
-	auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError) {
+	auctionId, currency string) (*WinningInfoOutputDTO, *internal_error.InternalError) {
 	auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
 	if err != nil {
 		return nil, err
 	}
 
-	auctionOutputDTO := AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   ProductCondition(auction.Condition),
-		Status:      AuctionStatus(auction.Status),
-		Timestamp:   auction.Timestamp,
+	auctionOutputDTO := toAuctionOutputDTO(auction)
+
+	var rate *currency_entity.Rate
+	if currency != "" {
+		rate, err = au.findExchangeRate(ctx, auction, currency)
+		if err != nil {
+			return nil, err
+		}
+		auctionOutputDTO.Converted = &ConvertedAmountsDTO{
+			Currency:     rate.To,
+			ExchangeRate: toExchangeRateDTO(rate),
+		}
 	}
 
 	bidWinning, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auction.Id)
@@ -86,9 +117,68 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		Amount:    bidWinning.Amount,
 		Timestamp: bidWinning.Timestamp,
 	}
+	if rate != nil {
+		auctionOutputDTO.Converted.WinningBid = rate.Convert(bidWinning.Amount)
+	}
 
 	return &WinningInfoOutputDTO{
 		Auction: auctionOutputDTO,
 		Bid:     bidOutputDTO,
 	}, nil
 }
+
+func toAuctionOutputDTO(auction *auction_entity.Auction) AuctionOutputDTO {
+	output := AuctionOutputDTO{
+		Id:          auction.Id,
+		SellerId:    auction.SellerId,
+		ProductName: auction.ProductName,
+		Category:    auction.Category,
+		Description: auction.Description,
+		Condition:   ProductCondition(auction.Condition),
+		Currency:    auction.Currency,
+		Status:      AuctionStatus(auction.Status),
+		Moderation:  ModerationVerdict(auction.Moderation),
+		Timestamp:   auction.Timestamp,
+	}
+	if auction.SettlementRate != nil {
+		rate := toExchangeRateDTO(auction.SettlementRate)
+		output.SettlementRate = &rate
+	}
+	return output
+}
+
+func toExchangeRateDTO(rate *currency_entity.Rate) ExchangeRateDTO {
+	return ExchangeRateDTO{
+		From:      rate.From,
+		To:        rate.To,
+		Rate:      rate.Value,
+		Source:    rate.Source,
+		Timestamp: rate.Timestamp,
+	}
+}
+
+// findExchangeRate retorna a cotação da moeda do leilão para currency. Um
+// leilão encerrado é exibido pela cotação gravada na liquidação, quando ela é
+// para a mesma moeda; os demais usam a cotação atual do provedor.
+func (au *AuctionUseCase) findExchangeRate(
+	ctx context.Context,
+	auction *auction_entity.Auction,
+	currency string) (*currency_entity.Rate, *internal_error.InternalError) {
+	currency = currency_entity.Normalize(currency)
+	if err := currency_entity.Validate(currency); err != nil {
+		return nil, err
+	}
+
+	if auction.Currency == currency {
+		return currency_entity.IdentityRate(currency, time.Now()), nil
+	}
+	if settlement := auction.SettlementRate; settlement != nil &&
+		settlement.From == auction.Currency && settlement.To == currency {
+		return settlement, nil
+	}
+	if au.rateProvider == nil {
+		return nil, internal_error.NewBadRequestError("currency conversion is not available")
+	}
+
+	return au.rateProvider.FindRate(ctx, auction.Currency, currency)
+}
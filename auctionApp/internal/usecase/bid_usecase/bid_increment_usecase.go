@@ -0,0 +1,151 @@
+package bid_usecase
+
+import (
+	"auctionService/internal/entity/bid_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// pendingBidRetention é por quanto tempo o maior lance de um leilão continua
+// guardado depois da gravação do lote, cobrindo validações que leram o banco antes dela
+const pendingBidRetention = time.Minute
+
+// pendingBid é o maior lance aceito de um leilão; insertedAt fica zerado até o
+// lote dele ser gravado
+type pendingBid struct {
+	amount     float64
+	insertedAt time.Time
+}
+
+// BidIncrementBandDTO é uma faixa da tabela de incrementos; up_to 0 marca a última faixa, sem limite
+type BidIncrementBandDTO struct {
+	UpTo      float64 `json:"up_to"`
+	Increment float64 `json:"increment"`
+}
+
+func ToBidIncrementDTOs(table bid_entity.IncrementTable) []BidIncrementBandDTO {
+	bands := make([]BidIncrementBandDTO, 0, len(table))
+	for _, band := range table {
+		bands = append(bands, BidIncrementBandDTO{UpTo: band.UpTo, Increment: band.Increment})
+	}
+	return bands
+}
+
+func (bu *BidUseCase) FindBidIncrements(
+	ctx context.Context) ([]BidIncrementBandDTO, *internal_error.InternalError) {
+	table, err := bu.IncrementTableRepository.FindIncrementTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToBidIncrementDTOs(table), nil
+}
+
+func (bu *BidUseCase) UpdateBidIncrements(
+	ctx context.Context,
+	bands []BidIncrementBandDTO) ([]BidIncrementBandDTO, *internal_error.InternalError) {
+	table := make(bid_entity.IncrementTable, 0, len(bands))
+	for _, band := range bands {
+		table = append(table, bid_entity.IncrementBand{UpTo: band.UpTo, Increment: band.Increment})
+	}
+
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+	if err := bu.IncrementTableRepository.UpdateIncrementTable(ctx, table); err != nil {
+		return nil, err
+	}
+
+	return ToBidIncrementDTOs(table), nil
+}
+
+// CurrentBid retorna o maior lance do leilão: o gravado ou, se for maior, o
+// último aceito que ainda aguarda no lote. É o valor que validateIncrement usa,
+// então o mínimo calculado a partir dele é aceito por CreateBid.
+func (bu *BidUseCase) CurrentBid(
+	ctx context.Context, auctionId string) (float64, *internal_error.InternalError) {
+	savedBid, readAt, err := bu.findSavedBid(ctx, auctionId)
+	if err != nil {
+		return 0, err
+	}
+
+	bu.pendingMutex.Lock()
+	defer bu.pendingMutex.Unlock()
+	return bu.currentBidLocked(auctionId, savedBid, readAt), nil
+}
+
+// findSavedBid retorna o maior lance gravado do leilão (0 sem lances) e quando o banco foi lido
+func (bu *BidUseCase) findSavedBid(
+	ctx context.Context, auctionId string) (float64, time.Time, *internal_error.InternalError) {
+	readAt := time.Now()
+	winningBid, err := bu.BidRepository.FindWinningBidByAuctionId(ctx, auctionId)
+	if err != nil && err.Err != "not_found" {
+		return 0, readAt, err
+	}
+	if winningBid == nil {
+		return 0, readAt, nil
+	}
+	return winningBid.Amount, readAt, nil
+}
+
+// currentBidLocked combina o lance gravado, lido em readAt, com o pendente; callers hold pendingMutex
+func (bu *BidUseCase) currentBidLocked(auctionId string, savedBid float64, readAt time.Time) float64 {
+	pending, ok := bu.pendingHighest[auctionId]
+	// Gravado antes da leitura, o lance pendente já está em savedBid
+	if !ok || (!pending.insertedAt.IsZero() && !readAt.Before(pending.insertedAt)) {
+		return savedBid
+	}
+	return math.Max(savedBid, pending.amount)
+}
+
+// validateIncrement recusa lances abaixo de CurrentBid mais o incremento da
+// faixa dele; o lance aprovado passa a ser o maior do leilão.
+func (bu *BidUseCase) validateIncrement(
+	ctx context.Context, bid *bid_entity.Bid) *internal_error.InternalError {
+	table, err := bu.IncrementTableRepository.FindIncrementTable(ctx)
+	if err != nil {
+		return err
+	}
+
+	savedBid, readAt, err := bu.findSavedBid(ctx, bid.AuctionId)
+	if err != nil {
+		return err
+	}
+
+	// A checagem e o registro do novo maior lance acontecem sob o mesmo lock
+	bu.pendingMutex.Lock()
+	defer bu.pendingMutex.Unlock()
+	currentBid := bu.currentBidLocked(bid.AuctionId, savedBid, readAt)
+
+	minimum := table.MinimumNextBid(currentBid)
+	if math.Round(bid.Amount*100) < math.Round(minimum*100) {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("Amount must be at least %.2f", minimum))
+	}
+
+	bu.pendingHighest[bid.AuctionId] = pendingBid{amount: bid.Amount}
+	return nil
+}
+
+// settlePendingBids marca como gravados os maiores lances que estavam em batch
+// e descarta os gravados há mais de pendingBidRetention. Um lote que falhou
+// também é marcado, já que os lances dele não vão mais chegar ao banco.
+func (bu *BidUseCase) settlePendingBids(batch []bid_entity.Bid) {
+	now := time.Now()
+	bu.pendingMutex.Lock()
+	defer bu.pendingMutex.Unlock()
+	for _, bid := range batch {
+		if pending, ok := bu.pendingHighest[bid.AuctionId]; ok && pending.insertedAt.IsZero() && pending.amount == bid.Amount {
+			pending.insertedAt = now
+			bu.pendingHighest[bid.AuctionId] = pending
+		}
+	}
+	for auctionId, pending := range bu.pendingHighest {
+		if !pending.insertedAt.IsZero() && now.Sub(pending.insertedAt) > pendingBidRetention {
+			delete(bu.pendingHighest, auctionId)
+		}
+	}
+}
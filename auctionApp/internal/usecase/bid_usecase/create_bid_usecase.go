@@ -7,6 +7,7 @@ import (
 	"context"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -25,24 +26,42 @@ type BidOutputDTO struct {
 }
 
 type BidUseCase struct {
-	BidRepository bid_entity.BidEntityRepository
+	BidRepository            bid_entity.BidEntityRepository
+	IncrementTableRepository bid_entity.IncrementTableRepositoryInterface
 
 	timer               *time.Timer
 	maxBatchSize        int
 	batchInsertInterval time.Duration
 	bidChannel          chan bid_entity.Bid
+	flushRequests       chan chan *internal_error.InternalError
+
+	// heldUsers são os usuários cujos lances estão bloqueados durante a eliminação
+	// dos dados; CreateBid lê com RLock até o lance entrar no canal
+	holdMutex sync.RWMutex
+	heldUsers map[string]struct{}
+
+	// pendingHighest é o maior lance aceito de cada leilão que ainda pode não
+	// estar gravado; ver validateIncrement
+	pendingMutex   sync.Mutex
+	pendingHighest map[string]pendingBid
 }
 
-func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInterface {
+func NewBidUseCase(
+	bidRepository bid_entity.BidEntityRepository,
+	incrementTableRepository bid_entity.IncrementTableRepositoryInterface) BidUseCaseInterface {
 	maxSizeInterval := getMaxBatchSizeInterval()
 	maxBatchSize := getMaxBatchSize()
 
 	bidUseCase := &BidUseCase{
-		BidRepository:       bidRepository,
-		maxBatchSize:        maxBatchSize,
-		batchInsertInterval: maxSizeInterval,
-		timer:               time.NewTimer(maxSizeInterval),
-		bidChannel:          make(chan bid_entity.Bid, maxBatchSize),
+		BidRepository:            bidRepository,
+		IncrementTableRepository: incrementTableRepository,
+		maxBatchSize:             maxBatchSize,
+		batchInsertInterval:      maxSizeInterval,
+		timer:                    time.NewTimer(maxSizeInterval),
+		bidChannel:               make(chan bid_entity.Bid, maxBatchSize),
+		flushRequests:            make(chan chan *internal_error.InternalError),
+		heldUsers:                make(map[string]struct{}),
+		pendingHighest:           make(map[string]pendingBid),
 	}
 
 	bidUseCase.triggerCreateRoutine(context.Background())
@@ -62,6 +81,20 @@ type BidUseCaseInterface interface {
 
 	FindBidByAuctionId(
 		ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError)
+
+	FindBidIncrements(
+		ctx context.Context) ([]BidIncrementBandDTO, *internal_error.InternalError)
+
+	UpdateBidIncrements(
+		ctx context.Context,
+		bands []BidIncrementBandDTO) ([]BidIncrementBandDTO, *internal_error.InternalError)
+
+	CurrentBid(
+		ctx context.Context, auctionId string) (float64, *internal_error.InternalError)
+
+	HoldUserBids(userId string) (release func())
+
+	FlushBids(ctx context.Context) *internal_error.InternalError
 }
 
 func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
@@ -83,24 +116,70 @@ func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
 				bidBatch = append(bidBatch, bidEntity)
 
 				if len(bidBatch) >= bu.maxBatchSize {
-					if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-						logger.Error("error trying to process bid batch list", err)
-					}
-
-					bidBatch = nil
+					bu.insertBatch(ctx)
 					bu.timer.Reset(bu.batchInsertInterval)
 				}
 			case <-bu.timer.C:
-				if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-					logger.Error("error trying to process bid batch list", err)
+				bu.insertBatch(ctx)
+				bu.timer.Reset(bu.batchInsertInterval)
+			case done := <-bu.flushRequests:
+				// Lances que já estão no canal entram neste lote
+				for len(bu.bidChannel) > 0 {
+					bidBatch = append(bidBatch, <-bu.bidChannel)
 				}
-				bidBatch = nil
+				done <- bu.insertBatch(ctx)
 				bu.timer.Reset(bu.batchInsertInterval)
 			}
 		}
 	}()
 }
 
+// insertBatch grava e esvazia o lote; só é chamado pela rotina do lote
+func (bu *BidUseCase) insertBatch(ctx context.Context) *internal_error.InternalError {
+	if len(bidBatch) == 0 {
+		return nil
+	}
+	err := bu.BidRepository.CreateBid(ctx, bidBatch)
+	if err != nil {
+		logger.Error("error trying to process bid batch list", err)
+	}
+	bu.settlePendingBids(bidBatch)
+	bidBatch = nil
+	return err
+}
+
+// FlushBids grava na hora os lances que aguardam no lote, inclusive os que
+// ainda estão no canal, e espera a gravação terminar
+func (bu *BidUseCase) FlushBids(ctx context.Context) *internal_error.InternalError {
+	done := make(chan *internal_error.InternalError, 1)
+	select {
+	case bu.flushRequests <- done:
+	case <-ctx.Done():
+		return internal_error.NewInternalServerError("timeout waiting for the bid batch")
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return internal_error.NewInternalServerError("timeout waiting for the bid batch")
+	}
+}
+
+// HoldUserBids recusa novos lances do usuário até release ser chamado. Ao
+// retornar, todo lance aceito antes já está no canal do lote, então um
+// FlushBids em seguida grava todos eles.
+func (bu *BidUseCase) HoldUserBids(userId string) (release func()) {
+	bu.holdMutex.Lock()
+	bu.heldUsers[userId] = struct{}{}
+	bu.holdMutex.Unlock()
+
+	return func() {
+		bu.holdMutex.Lock()
+		delete(bu.heldUsers, userId)
+		bu.holdMutex.Unlock()
+	}
+}
+
 func (bu *BidUseCase) CreateBid(
 	ctx context.Context,
 	bidInputDTO BidInputDTO) *internal_error.InternalError {
@@ -110,6 +189,17 @@ func (bu *BidUseCase) CreateBid(
 		return err
 	}
 
+	bu.holdMutex.RLock()
+	defer bu.holdMutex.RUnlock()
+	if _, held := bu.heldUsers[bidEntity.UserId]; held {
+		return internal_error.NewBadRequestError("User data is being erased, bids are not accepted")
+	}
+
+	// Um lance aceito aqui sempre entra no lote, então já conta como o maior do leilão
+	if err := bu.validateIncrement(ctx, bidEntity); err != nil {
+		return err
+	}
+
 	bu.bidChannel <- *bidEntity
 
 	return nil
@@ -0,0 +1,173 @@
+package ledger_usecase
+
+import (
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/bid_entity"
+	"auctionService/internal/entity/ledger_entity"
+	"auctionService/internal/internal_error"
+	"auctionService/internal/usecase/bid_usecase"
+	"context"
+	"fmt"
+	"time"
+)
+
+type AuctionStateOutputDTO struct {
+	AuctionId     string                    `json:"auction_id"`
+	Status        int64                     `json:"status"`
+	BidCount      int64                     `json:"bid_count"`
+	HighestBid    *bid_usecase.BidOutputDTO `json:"highest_bid,omitempty"`
+	LastEvent     time.Time                 `json:"last_event" time_format:"2006-01-02 15:04:05"`
+	Discrepancies []string                  `json:"discrepancies,omitempty"`
+	Restored      bool                      `json:"restored,omitempty"`
+}
+
+func NewLedgerUseCase(
+	ledgerRepositoryInterface ledger_entity.LedgerRepositoryInterface,
+	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
+	bidRepositoryInterface bid_entity.BidEntityRepository) LedgerUseCaseInterface {
+	return &LedgerUseCase{
+		ledgerRepositoryInterface:  ledgerRepositoryInterface,
+		auctionRepositoryInterface: auctionRepositoryInterface,
+		bidRepositoryInterface:     bidRepositoryInterface,
+	}
+}
+
+type LedgerUseCaseInterface interface {
+	// ReplayLedger reconstrói o estado dos leilões a partir do ledger e aponta
+	// divergências com as coleções de leilões e lances; auctionId vazio audita todos
+	ReplayLedger(
+		ctx context.Context, auctionId string) ([]AuctionStateOutputDTO, *internal_error.InternalError)
+
+	// RestoreFromLedger faz o replay e corrige status e maior lance divergentes
+	RestoreFromLedger(
+		ctx context.Context, auctionId string) ([]AuctionStateOutputDTO, *internal_error.InternalError)
+}
+
+type LedgerUseCase struct {
+	ledgerRepositoryInterface  ledger_entity.LedgerRepositoryInterface
+	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
+	bidRepositoryInterface     bid_entity.BidEntityRepository
+}
+
+// audit guarda o que precisa ser corrigido em um leilão
+type audit struct {
+	state         ledger_entity.AuctionState
+	discrepancies []string
+	restoreStatus bool
+	restoreBid    bool
+}
+
+func (lu *LedgerUseCase) ReplayLedger(
+	ctx context.Context, auctionId string) ([]AuctionStateOutputDTO, *internal_error.InternalError) {
+	audits, err := lu.audit(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]AuctionStateOutputDTO, 0, len(audits))
+	for _, a := range audits {
+		output = append(output, toOutputDTO(a, false))
+	}
+	return output, nil
+}
+
+func (lu *LedgerUseCase) RestoreFromLedger(
+	ctx context.Context, auctionId string) ([]AuctionStateOutputDTO, *internal_error.InternalError) {
+	audits, err := lu.audit(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]AuctionStateOutputDTO, 0, len(audits))
+	for _, a := range audits {
+		restored := false
+		if a.restoreStatus {
+			if err := lu.auctionRepositoryInterface.RestoreAuctionStatus(ctx, a.state.AuctionId, a.state.Status); err != nil {
+				return nil, err
+			}
+			restored = true
+		}
+		if a.restoreBid {
+			if err := lu.bidRepositoryInterface.RestoreBid(ctx, *a.state.HighestBid); err != nil {
+				return nil, err
+			}
+			restored = true
+		}
+		output = append(output, toOutputDTO(a, restored))
+	}
+	return output, nil
+}
+
+func (lu *LedgerUseCase) audit(ctx context.Context, auctionId string) ([]audit, *internal_error.InternalError) {
+	events, err := lu.ledgerRepositoryInterface.FindEvents(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+	if auctionId != "" && len(events) == 0 {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("No ledger events found for auction %s", auctionId))
+	}
+
+	states := ledger_entity.Replay(events)
+	audits := make([]audit, 0, len(states))
+	for _, state := range states {
+		audits = append(audits, lu.compare(ctx, state))
+	}
+	return audits, nil
+}
+
+// compare confronta o estado do ledger com o que está gravado hoje
+func (lu *LedgerUseCase) compare(ctx context.Context, state ledger_entity.AuctionState) audit {
+	a := audit{state: state}
+
+	auction, err := lu.auctionRepositoryInterface.FindAuctionById(ctx, state.AuctionId)
+	if err != nil {
+		a.discrepancies = append(a.discrepancies, "auction not found in the auctions collection")
+	} else if auction.Status != state.Status {
+		a.discrepancies = append(a.discrepancies,
+			fmt.Sprintf("status is %d, ledger says %d", auction.Status, state.Status))
+		a.restoreStatus = true
+	}
+
+	// FindWinningBidByAuctionId também falha quando não há lances
+	winningBid, _ := lu.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, state.AuctionId)
+	switch {
+	case state.HighestBid == nil && winningBid != nil:
+		a.discrepancies = append(a.discrepancies,
+			fmt.Sprintf("bid %s is not in the ledger", winningBid.Id))
+	case state.HighestBid != nil && winningBid == nil:
+		a.discrepancies = append(a.discrepancies,
+			fmt.Sprintf("highest bid %s is missing from the bids collection", state.HighestBid.Id))
+		a.restoreBid = true
+	case state.HighestBid != nil && winningBid.Amount < state.HighestBid.Amount:
+		a.discrepancies = append(a.discrepancies,
+			fmt.Sprintf("highest bid is %.2f, ledger says %.2f", winningBid.Amount, state.HighestBid.Amount))
+		a.restoreBid = true
+	case state.HighestBid != nil && winningBid.Amount > state.HighestBid.Amount:
+		a.discrepancies = append(a.discrepancies,
+			fmt.Sprintf("bid %s of %.2f is not in the ledger", winningBid.Id, winningBid.Amount))
+	}
+
+	return a
+}
+
+func toOutputDTO(a audit, restored bool) AuctionStateOutputDTO {
+	output := AuctionStateOutputDTO{
+		AuctionId:     a.state.AuctionId,
+		Status:        int64(a.state.Status),
+		BidCount:      a.state.BidCount,
+		LastEvent:     a.state.LastEvent,
+		Discrepancies: a.discrepancies,
+		Restored:      restored,
+	}
+	if bid := a.state.HighestBid; bid != nil {
+		output.HighestBid = &bid_usecase.BidOutputDTO{
+			Id:        bid.Id,
+			UserId:    bid.UserId,
+			AuctionId: bid.AuctionId,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+		}
+	}
+	return output
+}
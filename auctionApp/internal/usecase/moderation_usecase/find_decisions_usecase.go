@@ -0,0 +1,60 @@
+package moderation_usecase
+
+import (
+	"auctionService/internal/entity/moderation_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"time"
+)
+
+type ModerationVerdict int64
+
+type DecisionOutputDTO struct {
+	Id          string            `json:"id"`
+	AuctionId   string            `json:"auction_id"`
+	ProductName string            `json:"product_name"`
+	Description string            `json:"description"`
+	Verdict     ModerationVerdict `json:"verdict"`
+	Reasons     []string          `json:"reasons"`
+	Timestamp   time.Time         `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+func NewModerationUseCase(
+	decisionRepositoryInterface moderation_entity.DecisionRepositoryInterface) ModerationUseCaseInterface {
+	return &ModerationUseCase{
+		decisionRepositoryInterface: decisionRepositoryInterface,
+	}
+}
+
+type ModerationUseCaseInterface interface {
+	FindDecisions(
+		ctx context.Context, verdict ModerationVerdict) ([]DecisionOutputDTO, *internal_error.InternalError)
+}
+
+type ModerationUseCase struct {
+	decisionRepositoryInterface moderation_entity.DecisionRepositoryInterface
+}
+
+func (mu *ModerationUseCase) FindDecisions(
+	ctx context.Context, verdict ModerationVerdict) ([]DecisionOutputDTO, *internal_error.InternalError) {
+	decisions, err := mu.decisionRepositoryInterface.FindDecisions(
+		ctx, moderation_entity.Verdict(verdict))
+	if err != nil {
+		return nil, err
+	}
+
+	decisionOutputs := make([]DecisionOutputDTO, 0, len(decisions))
+	for _, value := range decisions {
+		decisionOutputs = append(decisionOutputs, DecisionOutputDTO{
+			Id:          value.Id,
+			AuctionId:   value.AuctionId,
+			ProductName: value.ProductName,
+			Description: value.Description,
+			Verdict:     ModerationVerdict(value.Verdict),
+			Reasons:     value.Reasons,
+			Timestamp:   value.Timestamp,
+		})
+	}
+
+	return decisionOutputs, nil
+}
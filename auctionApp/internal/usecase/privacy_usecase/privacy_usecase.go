@@ -0,0 +1,234 @@
+package privacy_usecase
+
+import (
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/entity/bid_entity"
+	"auctionService/internal/entity/ledger_entity"
+	"auctionService/internal/entity/privacy_entity"
+	"auctionService/internal/entity/user_entity"
+	"auctionService/internal/internal_error"
+	"auctionService/internal/usecase/auction_usecase"
+	"auctionService/internal/usecase/bid_usecase"
+	"auctionService/internal/usecase/user_usecase"
+	"context"
+	"time"
+)
+
+type ActivityOutputDTO struct {
+	Type      string    `json:"type"`
+	AuctionId string    `json:"auction_id"`
+	BidId     string    `json:"bid_id,omitempty"`
+	Amount    float64   `json:"amount,omitempty"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+// UserDataExportOutputDTO reúne tudo o que o sistema guarda sobre o usuário.
+// User é nulo quando não há cadastro, mas ainda existem leilões ou lances.
+type UserDataExportOutputDTO struct {
+	User       *user_usecase.UserOutputDTO        `json:"user"`
+	Auctions   []auction_usecase.AuctionOutputDTO `json:"auctions"`
+	Bids       []bid_usecase.BidOutputDTO         `json:"bids"`
+	Activity   []ActivityOutputDTO                `json:"activity"`
+	ExportedAt time.Time                          `json:"exported_at"`
+}
+
+type PrivacyRequestOutputDTO struct {
+	Id        string    `json:"id"`
+	UserHash  string    `json:"user_hash"`
+	Type      string    `json:"type"`
+	Auctions  int64     `json:"auctions"`
+	Bids      int64     `json:"bids"`
+	Events    int64     `json:"events"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+func NewPrivacyUseCase(
+	userRepositoryInterface user_entity.UserRepositoryInterface,
+	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
+	bidRepositoryInterface bid_entity.BidEntityRepository,
+	ledgerRepositoryInterface ledger_entity.LedgerRepositoryInterface,
+	privacyRequestRepositoryInterface privacy_entity.PrivacyRequestRepositoryInterface,
+	pendingBids PendingBidsInterface) PrivacyUseCaseInterface {
+	return &PrivacyUseCase{
+		userRepositoryInterface:           userRepositoryInterface,
+		auctionRepositoryInterface:        auctionRepositoryInterface,
+		bidRepositoryInterface:            bidRepositoryInterface,
+		ledgerRepositoryInterface:         ledgerRepositoryInterface,
+		privacyRequestRepositoryInterface: privacyRequestRepositoryInterface,
+		pendingBids:                       pendingBids,
+	}
+}
+
+// PendingBidsInterface controla os lances que ainda aguardam no lote de
+// inserção, que a anonimização no banco não alcançaria
+type PendingBidsInterface interface {
+	HoldUserBids(userId string) (release func())
+
+	FlushBids(ctx context.Context) *internal_error.InternalError
+}
+
+type PrivacyUseCaseInterface interface {
+	ExportUserData(
+		ctx context.Context, userId string) (*UserDataExportOutputDTO, *internal_error.InternalError)
+
+	EraseUserData(
+		ctx context.Context, userId string) (*PrivacyRequestOutputDTO, *internal_error.InternalError)
+
+	FindPrivacyRequests(
+		ctx context.Context, userId string) ([]PrivacyRequestOutputDTO, *internal_error.InternalError)
+}
+
+type PrivacyUseCase struct {
+	userRepositoryInterface           user_entity.UserRepositoryInterface
+	auctionRepositoryInterface        auction_entity.AuctionRepositoryInterface
+	bidRepositoryInterface            bid_entity.BidEntityRepository
+	ledgerRepositoryInterface         ledger_entity.LedgerRepositoryInterface
+	privacyRequestRepositoryInterface privacy_entity.PrivacyRequestRepositoryInterface
+	pendingBids                       PendingBidsInterface
+}
+
+// ExportUserData monta a exportação e a registra na trilha de auditoria;
+// sem o registro, a exportação não é entregue
+func (pu *PrivacyUseCase) ExportUserData(
+	ctx context.Context, userId string) (*UserDataExportOutputDTO, *internal_error.InternalError) {
+	export := &UserDataExportOutputDTO{ExportedAt: time.Now()}
+
+	userEntity, err := pu.userRepositoryInterface.FindUserById(ctx, userId)
+	if err != nil && err.Err != "not_found" {
+		return nil, err
+	}
+	if userEntity != nil {
+		export.User = &user_usecase.UserOutputDTO{Id: userEntity.Id, Name: userEntity.Name}
+	}
+
+	auctions, err := pu.auctionRepositoryInterface.FindAuctionsBySellerId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	export.Auctions = make([]auction_usecase.AuctionOutputDTO, 0, len(auctions))
+	for _, auction := range auctions {
+		export.Auctions = append(export.Auctions, auction_usecase.AuctionOutputDTO{
+			Id:          auction.Id,
+			SellerId:    auction.SellerId,
+			ProductName: auction.ProductName,
+			Category:    auction.Category,
+			Description: auction.Description,
+			Condition:   auction_usecase.ProductCondition(auction.Condition),
+			Status:      auction_usecase.AuctionStatus(auction.Status),
+			Moderation:  auction_usecase.ModerationVerdict(auction.Moderation),
+			Timestamp:   auction.Timestamp,
+		})
+	}
+
+	bids, err := pu.bidRepositoryInterface.FindBidsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	export.Bids = make([]bid_usecase.BidOutputDTO, 0, len(bids))
+	for _, bid := range bids {
+		export.Bids = append(export.Bids, bid_usecase.BidOutputDTO{
+			Id:        bid.Id,
+			UserId:    bid.UserId,
+			AuctionId: bid.AuctionId,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+		})
+	}
+
+	events, err := pu.ledgerRepositoryInterface.FindEventsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	export.Activity = make([]ActivityOutputDTO, 0, len(events))
+	for _, event := range events {
+		export.Activity = append(export.Activity, ActivityOutputDTO{
+			Type:      string(event.Type),
+			AuctionId: event.AuctionId,
+			BidId:     event.BidId,
+			Amount:    event.Amount,
+			Timestamp: event.Timestamp,
+		})
+	}
+
+	request := privacy_entity.NewPrivacyRequest(userId, privacy_entity.DataExport,
+		int64(len(export.Auctions)), int64(len(export.Bids)), int64(len(export.Activity)))
+	if err := pu.privacyRequestRepositoryInterface.CreatePrivacyRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// EraseUserData troca o id do usuário por um pseudônimo em lances, leilões e
+// ledger e remove o cadastro. Valores, horários e status ficam intactos, então
+// vencedores e o replay do ledger não mudam. Repetir a eliminação é seguro.
+// Novos lances do usuário são recusados durante a eliminação, e os que
+// aguardam no lote são gravados antes, para também serem anonimizados.
+func (pu *PrivacyUseCase) EraseUserData(
+	ctx context.Context, userId string) (*PrivacyRequestOutputDTO, *internal_error.InternalError) {
+	release := pu.pendingBids.HoldUserBids(userId)
+	defer release()
+	if err := pu.pendingBids.FlushBids(ctx); err != nil {
+		return nil, err
+	}
+
+	pseudonym := privacy_entity.NewPseudonym()
+
+	bids, err := pu.bidRepositoryInterface.AnonymizeBidsByUserId(ctx, userId, pseudonym)
+	if err != nil {
+		return nil, err
+	}
+
+	auctions, err := pu.auctionRepositoryInterface.AnonymizeSeller(ctx, userId, pseudonym)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := pu.ledgerRepositoryInterface.AnonymizeUserEvents(ctx, userId, pseudonym)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := pu.userRepositoryInterface.DeleteUser(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	request := privacy_entity.NewPrivacyRequest(userId, privacy_entity.DataErasure, auctions, bids, events)
+	if err := pu.privacyRequestRepositoryInterface.CreatePrivacyRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	output := toPrivacyRequestOutput(*request)
+	return &output, nil
+}
+
+func (pu *PrivacyUseCase) FindPrivacyRequests(
+	ctx context.Context, userId string) ([]PrivacyRequestOutputDTO, *internal_error.InternalError) {
+	userHash := ""
+	if userId != "" {
+		userHash = privacy_entity.HashUserId(userId)
+	}
+	requests, err := pu.privacyRequestRepositoryInterface.FindPrivacyRequests(ctx, userHash)
+	if err != nil {
+		return nil, err
+	}
+
+	requestOutputs := make([]PrivacyRequestOutputDTO, 0, len(requests))
+	for _, request := range requests {
+		requestOutputs = append(requestOutputs, toPrivacyRequestOutput(request))
+	}
+
+	return requestOutputs, nil
+}
+
+func toPrivacyRequestOutput(request privacy_entity.PrivacyRequest) PrivacyRequestOutputDTO {
+	return PrivacyRequestOutputDTO{
+		Id:        request.Id,
+		UserHash:  request.UserHash,
+		Type:      string(request.Type),
+		Auctions:  request.Auctions,
+		Bids:      request.Bids,
+		Events:    request.Events,
+		Timestamp: request.Timestamp,
+	}
+}
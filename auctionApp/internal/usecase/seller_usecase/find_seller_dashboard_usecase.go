@@ -0,0 +1,152 @@
+package seller_usecase
+
+import (
+	"auctionService/configuration/logger"
+	"auctionService/internal/entity/auction_entity"
+	"auctionService/internal/internal_error"
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+const maxCachedDashboards = 1000
+
+type RevenueBucketOutputDTO struct {
+	Start    time.Time `json:"start"`
+	Revenue  float64   `json:"revenue"`
+	Auctions int64     `json:"auctions"`
+}
+
+type SellerDashboardOutputDTO struct {
+	SellerId          string                   `json:"seller_id"`
+	ActiveAuctions    int64                    `json:"active_auctions"`
+	CompletedAuctions int64                    `json:"completed_auctions"`
+	TotalBids         int64                    `json:"total_bids"`
+	ConversionRate    float64                  `json:"conversion_rate"`
+	Interval          string                   `json:"interval"`
+	Revenue           []RevenueBucketOutputDTO `json:"revenue"`
+	GeneratedAt       time.Time                `json:"generated_at"`
+}
+
+func NewSellerUseCase(
+	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
+	cacheTTL time.Duration) SellerUseCaseInterface {
+	return &SellerUseCase{
+		auctionRepositoryInterface: auctionRepositoryInterface,
+		cacheTTL:                   cacheTTL,
+		cache:                      make(map[dashboardKey]cachedDashboard),
+	}
+}
+
+type SellerUseCaseInterface interface {
+	FindSellerDashboard(
+		ctx context.Context,
+		sellerId string,
+		interval auction_entity.RevenueInterval) (*SellerDashboardOutputDTO, *internal_error.InternalError)
+}
+
+type dashboardKey struct {
+	sellerId string
+	interval auction_entity.RevenueInterval
+}
+
+type cachedDashboard struct {
+	dashboard SellerDashboardOutputDTO
+	expiresAt time.Time
+}
+
+type SellerUseCase struct {
+	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
+	cacheTTL                   time.Duration
+
+	cacheMutex sync.Mutex
+	cache      map[dashboardKey]cachedDashboard
+}
+
+// FindSellerDashboard devolve o painel do vendedor, reaproveitando o resultado
+// da agregação por cacheTTL (0 desativa o cache).
+func (su *SellerUseCase) FindSellerDashboard(
+	ctx context.Context,
+	sellerId string,
+	interval auction_entity.RevenueInterval) (*SellerDashboardOutputDTO, *internal_error.InternalError) {
+	key := dashboardKey{sellerId: sellerId, interval: interval}
+	if dashboard, ok := su.cached(key); ok {
+		return &dashboard, nil
+	}
+
+	dashboardEntity, err := su.auctionRepositoryInterface.FindSellerDashboard(ctx, sellerId, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	revenue := make([]RevenueBucketOutputDTO, 0, len(dashboardEntity.Revenue))
+	for _, bucket := range dashboardEntity.Revenue {
+		revenue = append(revenue, RevenueBucketOutputDTO{
+			Start:    bucket.Start,
+			Revenue:  bucket.Revenue,
+			Auctions: bucket.Auctions,
+		})
+	}
+
+	dashboard := SellerDashboardOutputDTO{
+		SellerId:          sellerId,
+		ActiveAuctions:    dashboardEntity.ActiveAuctions,
+		CompletedAuctions: dashboardEntity.CompletedAuctions,
+		TotalBids:         dashboardEntity.TotalBids,
+		ConversionRate:    dashboardEntity.ConversionRate(),
+		Interval:          string(interval),
+		Revenue:           revenue,
+		GeneratedAt:       time.Now(),
+	}
+	su.store(key, dashboard)
+
+	return &dashboard, nil
+}
+
+func (su *SellerUseCase) cached(key dashboardKey) (SellerDashboardOutputDTO, bool) {
+	su.cacheMutex.Lock()
+	defer su.cacheMutex.Unlock()
+
+	entry, ok := su.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return SellerDashboardOutputDTO{}, false
+	}
+	return entry.dashboard, true
+}
+
+func (su *SellerUseCase) store(key dashboardKey, dashboard SellerDashboardOutputDTO) {
+	if su.cacheTTL <= 0 {
+		return
+	}
+
+	su.cacheMutex.Lock()
+	defer su.cacheMutex.Unlock()
+
+	now := time.Now()
+	if len(su.cache) >= maxCachedDashboards {
+		for k, entry := range su.cache {
+			if now.After(entry.expiresAt) {
+				delete(su.cache, k)
+			}
+		}
+		if len(su.cache) >= maxCachedDashboards {
+			return
+		}
+	}
+	su.cache[key] = cachedDashboard{dashboard: dashboard, expiresAt: now.Add(su.cacheTTL)}
+}
+
+// GetDashboardCacheTTL lê SELLER_DASHBOARD_CACHE_TTL, usando 1 minuto por padrão.
+func GetDashboardCacheTTL() time.Duration {
+	value := os.Getenv("SELLER_DASHBOARD_CACHE_TTL")
+	if value == "" {
+		return time.Minute
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Error("Error parsing SELLER_DASHBOARD_CACHE_TTL, using default 1 minute", err)
+		return time.Minute
+	}
+	return duration
+}
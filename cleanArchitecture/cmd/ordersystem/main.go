@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net"
@@ -13,8 +14,13 @@ import (
 	"cleanarch/internal/infra/graph"
 	"cleanarch/internal/infra/grpc/pb"
 	"cleanarch/internal/infra/grpc/service"
+	"cleanarch/internal/infra/idgen"
+	"cleanarch/internal/infra/messaging"
+	"cleanarch/internal/infra/payment"
+	"cleanarch/internal/infra/tracing"
 	"cleanarch/internal/infra/web"
 	"cleanarch/internal/infra/web/webserver"
+	"cleanarch/internal/infra/webhook"
 	"cleanarch/internal/usecase"
 	"cleanarch/pkg/events"
 
@@ -34,31 +40,93 @@ func main() {
 		panic(err)
 	}
 
-	db, err := sql.Open(configs.DBDriver, fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", configs.DBUser, configs.DBPassword, configs.DBHost, configs.DBPort, configs.DBName))
+	db, err := sql.Open(configs.DBDriver, fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", configs.DBUser, configs.DBPassword, configs.DBHost, configs.DBPort, configs.DBName))
 	if err != nil {
 		panic(err)
 	}
 	defer db.Close()
 
-	rabbitMQChannel := getRabbitMQChannel(configs.RabbitMQURL)
+	shutdownTracing, err := tracing.Setup(configs.TracingExporter)
+	if err != nil {
+		panic(err)
+	}
+	defer shutdownTracing(context.Background())
+
+	rabbitMQConnection := getRabbitMQConnection(configs.RabbitMQURL)
+	defer rabbitMQConnection.Close()
+	rabbitMQChannel := getRabbitMQChannel(rabbitMQConnection)
+
+	// OrderCreated messages that are rejected or expire land in the DLQ and are recorded for redelivery
+	orderQueueTopology := messaging.NewOrderQueueTopology(configs.OrdersQueue, configs.OrdersMessageTTL)
+	if err := orderQueueTopology.Declare(rabbitMQChannel); err != nil {
+		panic(err)
+	}
+	deadLetterRepository := database.NewDeadLetterRepository(db)
+	deadLetterConsumer := messaging.NewDeadLetterConsumer(getRabbitMQChannel(rabbitMQConnection),
+		orderQueueTopology.DeadLetterQueue(), usecase.NewRecordDeadLetterUseCase(deadLetterRepository))
+	if err := deadLetterConsumer.Start(context.Background()); err != nil {
+		panic(err)
+	}
 
 	eventDispatcher := events.NewEventDispatcher()
 	eventDispatcher.Register("OrderCreated", &handler.OrderCreatedHandler{
 		RabbitMQChannel: rabbitMQChannel,
 	})
 
+	webhookSubscriptionRepository := database.NewWebhookSubscriptionRepository(db)
+	dispatchWebhooksUseCase := usecase.NewDispatchWebhooksUseCase(webhookSubscriptionRepository, webhook.NewHTTPSender())
+	eventDispatcher.Register("OrderCreated", handler.NewWebhookHandler(dispatchWebhooksUseCase))
+
+	orderIDGenerator, err := idgen.New(configs.OrderIDStrategy, db, configs.OrderIDPrefix, configs.OrderIDNode)
+	if err != nil {
+		panic(err)
+	}
+	orderIDPolicy := usecase.OrderIDPolicy{Generator: orderIDGenerator, AllowClientID: configs.AllowClientIDs}
+
+	paymentGateway := payment.NewStubGateway(configs.PaymentStubMaxAmount)
+
 	orderRepository := database.NewOrderRepository(db)
-	orderCreatedEvent := event.NewOrderCreated()
-	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, orderCreatedEvent, eventDispatcher)
+	orderCreatedEvent := event.NewOrderCreatedFactory()
+	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, orderCreatedEvent, eventDispatcher, orderIDPolicy)
+	createOrderUseCase.PaymentGateway = paymentGateway
 	listOrdersUseCase := usecase.NewListOrdersUseCase(orderRepository)
 
+	importJobRepository := database.NewImportJobRepository(db)
+	importOrdersUseCase := usecase.NewImportOrdersUseCase(importJobRepository, *createOrderUseCase)
+	importStatusUseCase := usecase.NewImportStatusUseCase(importJobRepository)
+	importOrdersUseCase.StartWorker(context.Background())
+
+	// With rate plans enabled, the order APIs need an API key and share one counter per plan
+	ratePlanRepository := database.NewRatePlanRepository(db)
+	enforceRatePlanUseCase := usecase.NewEnforceRatePlanUseCase(ratePlanRepository)
+	rateLimit := func(handler http.HandlerFunc) http.HandlerFunc { return handler }
+	var grpcOptions []grpc.ServerOption
+	if configs.RatePlansEnabled {
+		rateLimit = web.NewRateLimitMiddleware(enforceRatePlanUseCase).Wrap
+		grpcOptions = append(grpcOptions, grpc.UnaryInterceptor(service.RateLimitUnaryInterceptor(enforceRatePlanUseCase)))
+	}
+
 	webserver := webserver.NewWebServer(configs.WebServerPort)
-	webOrderHandler := web.NewWebOrderHandler(eventDispatcher, orderRepository, orderCreatedEvent)
-	webserver.AddHandler("/order", webOrderHandler.OrderHandler)
+	webOrderHandler := web.NewWebOrderHandler(eventDispatcher, orderRepository, orderCreatedEvent, orderIDPolicy)
+	webOrderHandler.PaymentGateway = paymentGateway
+	webserver.AddHandler("/order", rateLimit(webOrderHandler.OrderHandler))
+	webWebhookHandler := web.NewWebWebhookHandler(webhookSubscriptionRepository, dispatchWebhooksUseCase)
+	webserver.AddHandler("/webhooks", rateLimit(webWebhookHandler.WebhookHandler))
+	webserver.AddHandler("/webhooks/{id}/test", rateLimit(webWebhookHandler.Test))
+	webDeadLetterHandler := web.NewWebDeadLetterHandler(
+		usecase.NewListDeadLettersUseCase(deadLetterRepository),
+		usecase.NewRedeliverDeadLetterUseCase(deadLetterRepository, messaging.NewPublisher(rabbitMQChannel)),
+		configs.AdminToken)
+	webserver.AddHandler("/admin/dead-letters", webDeadLetterHandler.List)
+	webserver.AddHandler("/admin/dead-letters/{id}", webDeadLetterHandler.Get)
+	webserver.AddHandler("/admin/dead-letters/{id}/redeliver", webDeadLetterHandler.Redeliver)
+	webRatePlanHandler := web.NewWebRatePlanHandler(ratePlanRepository, enforceRatePlanUseCase, configs.AdminToken)
+	webserver.AddHandler("/admin/rate-plans", webRatePlanHandler.RatePlansHandler)
+	webserver.AddHandler("/admin/rate-plans/{id}", webRatePlanHandler.RatePlanHandler)
 	fmt.Println("Starting web server on port", configs.WebServerPort)
 	go webserver.Start()
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpcOptions...)
 	createOrderService := service.NewOrderService(*createOrderUseCase, orderRepository)
 	pb.RegisterOrderServiceServer(grpcServer, createOrderService)
 	reflection.Register(grpcServer)
@@ -71,22 +139,28 @@ func main() {
 	go grpcServer.Serve(lis)
 
 	srv := graphql_handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
-		CreateOrderUseCase: *createOrderUseCase,
-		ListOrdersUseCase:  *listOrdersUseCase,
-		OrderRepository:    orderRepository,
+		CreateOrderUseCase:  *createOrderUseCase,
+		ListOrdersUseCase:   *listOrdersUseCase,
+		ImportOrdersUseCase: importOrdersUseCase,
+		ImportStatusUseCase: *importStatusUseCase,
+		OrderRepository:     orderRepository,
 	}}))
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	http.Handle("/query", rateLimit(srv.ServeHTTP))
 
 	fmt.Println("Starting GraphQL server on port", configs.GraphQLServerPort)
 	http.ListenAndServe(":"+configs.GraphQLServerPort, nil)
 }
 
-func getRabbitMQChannel(rabbitmqURL string) *amqp.Channel {
+func getRabbitMQConnection(rabbitmqURL string) *amqp.Connection {
 	conn, err := amqp.Dial(rabbitmqURL)
 	if err != nil {
 		panic(err)
 	}
+	return conn
+}
+
+func getRabbitMQChannel(conn *amqp.Connection) *amqp.Channel {
 	ch, err := conn.Channel()
 	if err != nil {
 		panic(err)
@@ -22,17 +22,15 @@ var setOrderRepositoryDependency = wire.NewSet(
 
 var setEventDispatcherDependency = wire.NewSet(
 	events.NewEventDispatcher,
-	event.NewOrderCreated,
-	wire.Bind(new(events.EventInterface), new(*event.OrderCreated)),
+	event.NewOrderCreatedFactory,
 	wire.Bind(new(events.EventDispatcherInterface), new(*events.EventDispatcher)),
 )
 
 var setOrderCreatedEvent = wire.NewSet(
-	event.NewOrderCreated,
-	wire.Bind(new(events.EventInterface), new(*event.OrderCreated)),
+	event.NewOrderCreatedFactory,
 )
 
-func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
+func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface, orderIDPolicy usecase.OrderIDPolicy) *usecase.CreateOrderUseCase {
 	wire.Build(
 		setOrderRepositoryDependency,
 		setOrderCreatedEvent,
@@ -49,7 +47,7 @@ func NewListOrdersUseCase(db *sql.DB) *usecase.ListOrdersUseCase {
 	return &usecase.ListOrdersUseCase{}
 }
 
-func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
+func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface, orderIDPolicy usecase.OrderIDPolicy) *web.WebOrderHandler {
 	wire.Build(
 		setOrderRepositoryDependency,
 		setOrderCreatedEvent,
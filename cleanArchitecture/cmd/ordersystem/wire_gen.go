@@ -23,10 +23,10 @@ import (
 
 // Injectors from wire.go:
 
-func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
+func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface, orderIDPolicy usecase.OrderIDPolicy) *usecase.CreateOrderUseCase {
 	orderRepository := database.NewOrderRepository(db)
-	orderCreated := event.NewOrderCreated()
-	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, orderCreated, eventDispatcher)
+	eventFactory := event.NewOrderCreatedFactory()
+	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, eventFactory, eventDispatcher, orderIDPolicy)
 	return createOrderUseCase
 }
 
@@ -36,10 +36,10 @@ func NewListOrdersUseCase(db *sql.DB) *usecase.ListOrdersUseCase {
 	return listOrdersUseCase
 }
 
-func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
+func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface, orderIDPolicy usecase.OrderIDPolicy) *web.WebOrderHandler {
 	orderRepository := database.NewOrderRepository(db)
-	orderCreated := event.NewOrderCreated()
-	webOrderHandler := web.NewWebOrderHandler(eventDispatcher, orderRepository, orderCreated)
+	eventFactory := event.NewOrderCreatedFactory()
+	webOrderHandler := web.NewWebOrderHandler(eventDispatcher, orderRepository, eventFactory, orderIDPolicy)
 	return webOrderHandler
 }
 
@@ -47,6 +47,6 @@ func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterf
 
 var setOrderRepositoryDependency = wire.NewSet(database.NewOrderRepository, wire.Bind(new(entity.OrderRepositoryInterface), new(*database.OrderRepository)))
 
-var setEventDispatcherDependency = wire.NewSet(events.NewEventDispatcher, event.NewOrderCreated, wire.Bind(new(events.EventInterface), new(*event.OrderCreated)), wire.Bind(new(events.EventDispatcherInterface), new(*events.EventDispatcher)))
+var setEventDispatcherDependency = wire.NewSet(events.NewEventDispatcher, event.NewOrderCreatedFactory, wire.Bind(new(events.EventDispatcherInterface), new(*events.EventDispatcher)))
 
-var setOrderCreatedEvent = wire.NewSet(event.NewOrderCreated, wire.Bind(new(events.EventInterface), new(*event.OrderCreated)))
+var setOrderCreatedEvent = wire.NewSet(event.NewOrderCreatedFactory)
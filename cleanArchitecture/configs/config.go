@@ -1,6 +1,14 @@
 package configs
 
-import "github.com/spf13/viper"
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ErrAdminTokenRequired is returned when rate plans are enabled without ADMIN_API_TOKEN
+var ErrAdminTokenRequired = errors.New("ADMIN_API_TOKEN is required when RATE_PLANS_ENABLED is true")
 
 type conf struct {
 	DBDriver          string `mapstructure:"DB_DRIVER"`
@@ -13,6 +21,19 @@ type conf struct {
 	GRPCServerPort    string `mapstructure:"GRPC_SERVER_PORT"`
 	GraphQLServerPort string `mapstructure:"GRAPHQL_SERVER_PORT"`
 	RabbitMQURL       string `mapstructure:"RABBITMQ_URL"`
+	OrderIDStrategy   string `mapstructure:"ORDER_ID_STRATEGY"`
+	OrderIDPrefix     string `mapstructure:"ORDER_ID_PREFIX"`
+	OrderIDNode       int64  `mapstructure:"ORDER_ID_NODE"`
+	AllowClientIDs    bool   `mapstructure:"ALLOW_CLIENT_ORDER_IDS"`
+	// PaymentStubMaxAmount makes the payment stub decline orders above it (0 accepts any amount)
+	PaymentStubMaxAmount float64 `mapstructure:"PAYMENT_STUB_MAX_AMOUNT"`
+	TracingExporter      string  `mapstructure:"TRACING_EXPORTER"`
+	// OrdersQueue receives OrderCreated; its dead letters go to "<queue>.dlq"
+	OrdersQueue      string        `mapstructure:"RABBITMQ_ORDERS_QUEUE"`
+	OrdersMessageTTL time.Duration `mapstructure:"RABBITMQ_ORDERS_MESSAGE_TTL"`
+	AdminToken       string        `mapstructure:"ADMIN_API_TOKEN"`
+	// RatePlansEnabled requires an API key with a rate plan on the order APIs
+	RatePlansEnabled bool `mapstructure:"RATE_PLANS_ENABLED"`
 }
 
 func LoadConfig(path string) (*conf, error) {
@@ -22,6 +43,16 @@ func LoadConfig(path string) (*conf, error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
+	viper.SetDefault("ORDER_ID_STRATEGY", "uuidv7")
+	viper.SetDefault("ORDER_ID_PREFIX", "ORD-")
+	viper.SetDefault("ORDER_ID_NODE", 0)
+	viper.SetDefault("ALLOW_CLIENT_ORDER_IDS", false)
+	viper.SetDefault("PAYMENT_STUB_MAX_AMOUNT", 0)
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("RABBITMQ_ORDERS_QUEUE", "orders")
+	viper.SetDefault("RABBITMQ_ORDERS_MESSAGE_TTL", 0)
+	viper.SetDefault("ADMIN_API_TOKEN", "")
+	viper.SetDefault("RATE_PLANS_ENABLED", false)
 	err := viper.ReadInConfig()
 	if err != nil {
 		panic(err)
@@ -30,5 +61,9 @@ func LoadConfig(path string) (*conf, error) {
 	if err != nil {
 		panic(err)
 	}
+	// Without a token the admin routes are open, and anyone could create or raise a rate plan
+	if cfg.RatePlansEnabled && cfg.AdminToken == "" {
+		return nil, ErrAdminTokenRequired
+	}
 	return cfg, err
 }
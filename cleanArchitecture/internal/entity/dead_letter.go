@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DeadLetterPending     = "PENDING"
+	DeadLetterRedelivered = "REDELIVERED"
+)
+
+var (
+	ErrDeadLetterNotFound           = errors.New("dead letter not found")
+	ErrDeadLetterAlreadyRedelivered = errors.New("dead letter already redelivered")
+)
+
+// DeadLetter is a message the broker moved to the dead-letter queue, kept
+// with enough of its origin to publish it again once the cause is fixed.
+type DeadLetter struct {
+	ID         string
+	Queue      string
+	Exchange   string
+	RoutingKey string
+	// Reason is the broker's reason for dead-lettering: rejected, expired or maxlen
+	Reason      string
+	DeathCount  int64
+	ContentType string
+	Body        []byte
+	Status      string
+	CreatedAt   time.Time
+	// RedeliveredAt is set once the message has been published again
+	RedeliveredAt *time.Time
+}
+
+func NewDeadLetter(id, queue, exchange, routingKey, reason string, deathCount int64, contentType string, body []byte) (*DeadLetter, error) {
+	deadLetter := &DeadLetter{
+		ID:          id,
+		Queue:       queue,
+		Exchange:    exchange,
+		RoutingKey:  routingKey,
+		Reason:      reason,
+		DeathCount:  deathCount,
+		ContentType: contentType,
+		Body:        body,
+		Status:      DeadLetterPending,
+		CreatedAt:   time.Now(),
+	}
+	if err := deadLetter.IsValid(); err != nil {
+		return nil, err
+	}
+	return deadLetter, nil
+}
+
+func (d *DeadLetter) IsValid() error {
+	if d.ID == "" {
+		return errors.New("invalid id")
+	}
+	if d.Queue == "" {
+		return errors.New("invalid queue")
+	}
+	return nil
+}
+
+// MarkRedelivered records a successful republish; a message is redelivered at most once
+func (d *DeadLetter) MarkRedelivered(at time.Time) error {
+	if d.Status == DeadLetterRedelivered {
+		return ErrDeadLetterAlreadyRedelivered
+	}
+	d.Status = DeadLetterRedelivered
+	d.RedeliveredAt = &at
+	return nil
+}
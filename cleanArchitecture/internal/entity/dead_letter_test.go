@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnEmptyQueue_WhenCreateADeadLetter_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := NewDeadLetter("dl-1", "", "amq.direct", "", "rejected", 1, "application/json", []byte("{}"))
+	assert.EqualError(t, err, "invalid queue")
+}
+
+func TestGivenAPendingDeadLetter_WhenMarkRedeliveredTwice_ThenShouldReceiveAnError(t *testing.T) {
+	deadLetter, err := NewDeadLetter("dl-1", "orders", "amq.direct", "", "rejected", 1, "application/json", []byte("{}"))
+	assert.NoError(t, err)
+	assert.Equal(t, DeadLetterPending, deadLetter.Status)
+
+	assert.NoError(t, deadLetter.MarkRedelivered(time.Now()))
+	assert.Equal(t, DeadLetterRedelivered, deadLetter.Status)
+	assert.NotNil(t, deadLetter.RedeliveredAt)
+
+	assert.ErrorIs(t, deadLetter.MarkRedelivered(time.Now()), ErrDeadLetterAlreadyRedelivered)
+}
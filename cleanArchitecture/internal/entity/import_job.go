@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ImportJobPending   = "PENDING"
+	ImportJobRunning   = "RUNNING"
+	ImportJobCompleted = "COMPLETED"
+	ImportJobFailed    = "FAILED"
+)
+
+var ErrImportJobNotFound = errors.New("import job not found")
+
+type ImportJob struct {
+	ID        string
+	Status    string
+	Total     int
+	Processed int
+	Failed    int
+	Errors    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewImportJob(id string, total int) (*ImportJob, error) {
+	now := time.Now()
+	job := &ImportJob{
+		ID:        id,
+		Status:    ImportJobPending,
+		Total:     total,
+		Errors:    []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err := job.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (j *ImportJob) IsValid() error {
+	if j.ID == "" {
+		return errors.New("invalid id")
+	}
+	if j.Total <= 0 {
+		return errors.New("invalid total")
+	}
+	return nil
+}
+
+func (j *ImportJob) Start() {
+	j.Status = ImportJobRunning
+	j.UpdatedAt = time.Now()
+}
+
+func (j *ImportJob) RecordSuccess() {
+	j.Processed++
+	j.UpdatedAt = time.Now()
+}
+
+func (j *ImportJob) RecordFailure(message string) {
+	j.Processed++
+	j.Failed++
+	j.Errors = append(j.Errors, message)
+	j.UpdatedAt = time.Now()
+}
+
+// Finish marks the job as completed, or failed when no order could be imported.
+func (j *ImportJob) Finish() {
+	j.Status = ImportJobCompleted
+	if j.Failed == j.Total {
+		j.Status = ImportJobFailed
+	}
+	j.UpdatedAt = time.Now()
+}
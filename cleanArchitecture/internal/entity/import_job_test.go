@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnEmptyTotal_WhenCreateANewImportJob_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := NewImportJob("job-1", 0)
+	assert.Error(t, err, "invalid total")
+}
+
+func TestGivenAJobWithSomeFailures_WhenFinish_ThenShouldBeCompleted(t *testing.T) {
+	job, err := NewImportJob("job-1", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, ImportJobPending, job.Status)
+
+	job.Start()
+	job.RecordSuccess()
+	job.RecordFailure("order-2: invalid tax")
+	job.Finish()
+
+	assert.Equal(t, ImportJobCompleted, job.Status)
+	assert.Equal(t, 2, job.Processed)
+	assert.Equal(t, 1, job.Failed)
+	assert.Equal(t, []string{"order-2: invalid tax"}, job.Errors)
+}
+
+func TestGivenAJobWhereEveryOrderFails_WhenFinish_ThenShouldBeFailed(t *testing.T) {
+	job, err := NewImportJob("job-1", 1)
+	assert.Nil(t, err)
+
+	job.Start()
+	job.RecordFailure("order-1: invalid price")
+	job.Finish()
+
+	assert.Equal(t, ImportJobFailed, job.Status)
+}
@@ -1,6 +1,71 @@
 package entity
 
+import (
+	"context"
+	"errors"
+	"time"
+)
+
 type OrderRepositoryInterface interface {
 	Save(order *Order) error
 	FindAll() ([]Order, error)
+	// Delete removes an order; used to compensate a save when order creation fails later on
+	Delete(id string) error
+}
+
+// ErrPaymentDeclined is returned by payment gateways that refuse a reservation.
+var ErrPaymentDeclined = errors.New("payment declined")
+
+// PaymentGatewayInterface reserves the amount of an order before it is persisted.
+// Cancel releases a reservation when the order cannot be completed.
+type PaymentGatewayInterface interface {
+	Reserve(ctx context.Context, orderID string, amount float64) (reservationID string, err error)
+	Cancel(ctx context.Context, reservationID string) error
+}
+
+// OrderIDGeneratorInterface produces identifiers for new orders.
+type OrderIDGeneratorInterface interface {
+	Generate() (string, error)
+}
+
+type ImportJobRepositoryInterface interface {
+	Save(job *ImportJob) error
+	Update(job *ImportJob) error
+	FindByID(id string) (*ImportJob, error)
+}
+
+type WebhookSubscriptionRepositoryInterface interface {
+	Save(subscription *WebhookSubscription) error
+	FindAll() ([]WebhookSubscription, error)
+	FindByID(id string) (*WebhookSubscription, error)
+	// RecordDelivery increments the delivery stats of a subscription after an attempt
+	RecordDelivery(id string, statusCode int, deliveryErr error, at time.Time) error
+}
+
+// WebhookSenderInterface delivers a JSON body to a webhook URL and returns the response status code.
+type WebhookSenderInterface interface {
+	Send(url string, body []byte) (int, error)
+}
+
+type DeadLetterRepositoryInterface interface {
+	Save(deadLetter *DeadLetter) error
+	FindByID(id string) (*DeadLetter, error)
+	// FindAll lists dead letters oldest first; an empty status returns all of them
+	FindAll(status string) ([]DeadLetter, error)
+	// MarkRedelivered flips a pending dead letter to redelivered, failing with
+	// ErrDeadLetterAlreadyRedelivered if another request got there first
+	MarkRedelivered(id string, at time.Time) error
+}
+
+// MessagePublisherInterface publishes a message body to a broker exchange.
+type MessagePublisherInterface interface {
+	Publish(exchange, routingKey, contentType string, body []byte) error
+}
+
+type RatePlanRepositoryInterface interface {
+	Save(plan *RatePlan) error
+	FindAll() ([]RatePlan, error)
+	FindByID(id string) (*RatePlan, error)
+	FindByAPIKeyHash(apiKeyHash string) (*RatePlan, error)
+	UpdateRequestsPerMinute(id string, requestsPerMinute int) error
 }
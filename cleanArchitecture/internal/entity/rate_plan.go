@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrRatePlanNotFound = errors.New("rate plan not found")
+	// ErrRateLimitExceeded is returned when an API key used up its requests for the current minute
+	ErrRateLimitExceeded = errors.New("rate limit exceeded")
+)
+
+// RatePlan grants one API key, usually one per tenant or client, a number of
+// requests per minute across the REST, GraphQL and gRPC APIs.
+type RatePlan struct {
+	ID   string
+	Name string
+	// APIKeyHash is the SHA-256 of the API key; the key itself is never stored
+	APIKeyHash        string
+	RequestsPerMinute int
+	CreatedAt         time.Time
+}
+
+func NewRatePlan(id, name, apiKey string, requestsPerMinute int) (*RatePlan, error) {
+	if apiKey == "" {
+		return nil, errors.New("invalid api key")
+	}
+	plan := &RatePlan{
+		ID:                id,
+		Name:              name,
+		APIKeyHash:        HashAPIKey(apiKey),
+		RequestsPerMinute: requestsPerMinute,
+		CreatedAt:         time.Now(),
+	}
+	err := plan.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (p *RatePlan) IsValid() error {
+	if p.ID == "" {
+		return errors.New("invalid id")
+	}
+	if p.Name == "" {
+		return errors.New("invalid name")
+	}
+	if p.APIKeyHash == "" {
+		return errors.New("invalid api key")
+	}
+	if p.RequestsPerMinute <= 0 {
+		return errors.New("invalid requests per minute")
+	}
+	return nil
+}
+
+// HashAPIKey returns the hex SHA-256 used to store and look up API keys.
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
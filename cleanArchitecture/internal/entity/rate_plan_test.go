@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAValidRatePlan_WhenCreate_ThenShouldStoreOnlyTheKeyHash(t *testing.T) {
+	plan, err := NewRatePlan("plan-1", "acme", "secret-key", 60)
+	assert.NoError(t, err)
+	assert.Equal(t, HashAPIKey("secret-key"), plan.APIKeyHash)
+	assert.NotEqual(t, "secret-key", plan.APIKeyHash)
+	assert.Len(t, plan.APIKeyHash, 64)
+}
+
+func TestGivenAnInvalidRatePlan_WhenCreate_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := NewRatePlan("plan-1", "", "secret-key", 60)
+	assert.EqualError(t, err, "invalid name")
+
+	_, err = NewRatePlan("plan-1", "acme", "", 60)
+	assert.EqualError(t, err, "invalid api key")
+
+	_, err = NewRatePlan("plan-1", "acme", "secret-key", 0)
+	assert.EqualError(t, err, "invalid requests per minute")
+}
@@ -0,0 +1,148 @@
+package entity
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	FilterGreaterThan     = "gt"
+	FilterGreaterThanOrEq = "gte"
+	FilterLessThan        = "lt"
+	FilterLessThanOrEq    = "lte"
+	FilterEqual           = "eq"
+)
+
+const (
+	WebhookFieldPrice      = "price"
+	WebhookFieldTax        = "tax"
+	WebhookFieldFinalPrice = "final_price"
+)
+
+var ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+
+// WebhookFilter is a condition on an order attribute, e.g. price gt 100.
+type WebhookFilter struct {
+	Field    string  `json:"field"`
+	Operator string  `json:"operator"`
+	Value    float64 `json:"value"`
+}
+
+type WebhookSubscription struct {
+	ID         string
+	URL        string
+	EventTypes []string
+	Filters    []WebhookFilter
+
+	// Delivery stats
+	Delivered      int
+	Failed         int
+	LastStatusCode int
+	LastError      string
+	LastDeliveryAt *time.Time
+	CreatedAt      time.Time
+}
+
+func NewWebhookSubscription(id, callbackURL string, eventTypes []string, filters []WebhookFilter) (*WebhookSubscription, error) {
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	if filters == nil {
+		filters = []WebhookFilter{}
+	}
+	subscription := &WebhookSubscription{
+		ID:         id,
+		URL:        callbackURL,
+		EventTypes: eventTypes,
+		Filters:    filters,
+		CreatedAt:  time.Now(),
+	}
+	err := subscription.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *WebhookSubscription) IsValid() error {
+	if s.ID == "" {
+		return errors.New("invalid id")
+	}
+	parsed, err := url.ParseRequestURI(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("invalid url")
+	}
+	for _, filter := range s.Filters {
+		if err := filter.IsValid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f WebhookFilter) IsValid() error {
+	switch f.Field {
+	case WebhookFieldPrice, WebhookFieldTax, WebhookFieldFinalPrice:
+	default:
+		return errors.New("invalid filter field")
+	}
+	switch f.Operator {
+	case FilterGreaterThan, FilterGreaterThanOrEq, FilterLessThan, FilterLessThanOrEq, FilterEqual:
+	default:
+		return errors.New("invalid filter operator")
+	}
+	return nil
+}
+
+// Matches reports whether the subscription wants eventName for the given order.
+// An empty event type list subscribes to every event; all filters must match.
+func (s *WebhookSubscription) Matches(eventName string, order Order) bool {
+	if len(s.EventTypes) > 0 {
+		subscribed := false
+		for _, eventType := range s.EventTypes {
+			if eventType == eventName {
+				subscribed = true
+				break
+			}
+		}
+		if !subscribed {
+			return false
+		}
+	}
+
+	for _, filter := range s.Filters {
+		if !filter.matches(order) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f WebhookFilter) matches(order Order) bool {
+	var value float64
+	switch f.Field {
+	case WebhookFieldPrice:
+		value = order.Price
+	case WebhookFieldTax:
+		value = order.Tax
+	case WebhookFieldFinalPrice:
+		value = order.FinalPrice
+	default:
+		return false
+	}
+
+	switch f.Operator {
+	case FilterGreaterThan:
+		return value > f.Value
+	case FilterGreaterThanOrEq:
+		return value >= f.Value
+	case FilterLessThan:
+		return value < f.Value
+	case FilterLessThanOrEq:
+		return value <= f.Value
+	case FilterEqual:
+		return value == f.Value
+	}
+	return false
+}
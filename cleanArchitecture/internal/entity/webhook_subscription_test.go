@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnInvalidURL_WhenCreateASubscription_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := NewWebhookSubscription("sub-1", "ftp://example.com", nil, nil)
+	assert.EqualError(t, err, "invalid url")
+}
+
+func TestGivenAnInvalidFilter_WhenCreateASubscription_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := NewWebhookSubscription("sub-1", "http://example.com/hook", nil, []WebhookFilter{
+		{Field: "customer", Operator: FilterEqual, Value: 1},
+	})
+	assert.EqualError(t, err, "invalid filter field")
+
+	_, err = NewWebhookSubscription("sub-1", "http://example.com/hook", nil, []WebhookFilter{
+		{Field: WebhookFieldPrice, Operator: "between", Value: 1},
+	})
+	assert.EqualError(t, err, "invalid filter operator")
+}
+
+func TestGivenFilters_WhenMatches_ThenShouldEvaluateEventTypeAndAttributes(t *testing.T) {
+	subscription, err := NewWebhookSubscription("sub-1", "http://example.com/hook", []string{"OrderCreated"}, []WebhookFilter{
+		{Field: WebhookFieldPrice, Operator: FilterGreaterThan, Value: 100},
+		{Field: WebhookFieldTax, Operator: FilterLessThanOrEq, Value: 10},
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, subscription.Matches("OrderCreated", Order{ID: "1", Price: 150, Tax: 10}))
+	assert.False(t, subscription.Matches("OrderCreated", Order{ID: "2", Price: 100, Tax: 10}))
+	assert.False(t, subscription.Matches("OrderCreated", Order{ID: "3", Price: 150, Tax: 11}))
+	assert.False(t, subscription.Matches("OrderCancelled", Order{ID: "4", Price: 150, Tax: 10}))
+}
+
+func TestGivenNoEventTypes_WhenMatches_ThenShouldAcceptAnyEvent(t *testing.T) {
+	subscription, err := NewWebhookSubscription("sub-1", "http://example.com/hook", nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, subscription.Matches("OrderCreated", Order{ID: "1", Price: 1, Tax: 1}))
+}
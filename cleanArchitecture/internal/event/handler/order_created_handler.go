@@ -19,17 +19,20 @@ func NewOrderCreatedHandler(rabbitMQChannel *amqp.Channel) *OrderCreatedHandler
 	}
 }
 
-func (h *OrderCreatedHandler) Handle(event events.EventInterface, wg *sync.WaitGroup) {
+func (h *OrderCreatedHandler) Handle(event events.EventInterface, wg *sync.WaitGroup) error {
 	defer wg.Done()
 	fmt.Printf("Order created: %v", event.GetPayload())
-	jsonOutput, _ := json.Marshal(event.GetPayload())
+	jsonOutput, err := json.Marshal(event.GetPayload())
+	if err != nil {
+		return err
+	}
 
 	msgRabbitmq := amqp.Publishing{
 		ContentType: "application/json",
 		Body:        jsonOutput,
 	}
 
-	h.RabbitMQChannel.Publish(
+	return h.RabbitMQChannel.Publish(
 		"amq.direct", // exchange
 		"",           // key name
 		false,        // mandatory
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+
+	"cleanarch/internal/usecase"
+	"cleanarch/pkg/events"
+)
+
+type WebhookHandler struct {
+	DispatchWebhooksUseCase *usecase.DispatchWebhooksUseCase
+}
+
+func NewWebhookHandler(dispatchWebhooksUseCase *usecase.DispatchWebhooksUseCase) *WebhookHandler {
+	return &WebhookHandler{
+		DispatchWebhooksUseCase: dispatchWebhooksUseCase,
+	}
+}
+
+// Handle captures the event data and delivers it in the background, so slow
+// subscribers never hold up the order that triggered the event. Delivery
+// failures are only logged, so it never fails the event itself.
+func (h *WebhookHandler) Handle(event events.EventInterface, wg *sync.WaitGroup) error {
+	defer wg.Done()
+	name, payload, occurredAt := event.GetName(), event.GetPayload(), event.GetDateTime()
+	go func() {
+		if err := h.DispatchWebhooksUseCase.Execute(name, payload, occurredAt); err != nil {
+			fmt.Printf("Webhook dispatch failed: %v\n", err)
+		}
+	}()
+	return nil
+}
@@ -1,6 +1,10 @@
 package event
 
-import "time"
+import (
+	"time"
+
+	"cleanarch/pkg/events"
+)
 
 type OrderCreated struct {
 	Name    string
@@ -13,6 +17,14 @@ func NewOrderCreated() *OrderCreated {
 	}
 }
 
+// NewOrderCreatedFactory returns a factory of OrderCreated events, so every
+// order gets its own event and payload
+func NewOrderCreatedFactory() events.EventFactory {
+	return func() events.EventInterface {
+		return NewOrderCreated()
+	}
+}
+
 func (e *OrderCreated) GetName() string {
 	return e.Name
 }
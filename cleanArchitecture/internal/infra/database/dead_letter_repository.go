@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"cleanarch/internal/entity"
+)
+
+type DeadLetterRepository struct {
+	Db *sql.DB
+}
+
+func NewDeadLetterRepository(db *sql.DB) *DeadLetterRepository {
+	return &DeadLetterRepository{Db: db}
+}
+
+const selectDeadLetter = "SELECT id, queue, exchange, routing_key, reason, death_count, content_type, body, status, created_at, redelivered_at FROM dead_letters"
+
+func (r *DeadLetterRepository) Save(deadLetter *entity.DeadLetter) error {
+	stmt, err := r.Db.Prepare("INSERT INTO dead_letters (id, queue, exchange, routing_key, reason, death_count, content_type, body, status, created_at, redelivered_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(deadLetter.ID, deadLetter.Queue, deadLetter.Exchange, deadLetter.RoutingKey, deadLetter.Reason,
+		deadLetter.DeathCount, deadLetter.ContentType, deadLetter.Body, deadLetter.Status, deadLetter.CreatedAt,
+		deadLetter.RedeliveredAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *DeadLetterRepository) FindByID(id string) (*entity.DeadLetter, error) {
+	deadLetter, err := scanDeadLetter(r.Db.QueryRow(selectDeadLetter+" WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deadLetter, nil
+}
+
+func (r *DeadLetterRepository) FindAll(status string) ([]entity.DeadLetter, error) {
+	query, args := selectDeadLetter, []interface{}{}
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	rows, err := r.Db.Query(query+" ORDER BY created_at", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadLetters []entity.DeadLetter
+	for rows.Next() {
+		deadLetter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, *deadLetter)
+	}
+	return deadLetters, rows.Err()
+}
+
+func (r *DeadLetterRepository) MarkRedelivered(id string, at time.Time) error {
+	stmt, err := r.Db.Prepare("UPDATE dead_letters SET status = ?, redelivered_at = ? WHERE id = ? AND status = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(entity.DeadLetterRedelivered, at, id, entity.DeadLetterPending)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		if _, err := r.FindByID(id); err != nil {
+			return err
+		}
+		return entity.ErrDeadLetterAlreadyRedelivered
+	}
+	return nil
+}
+
+func scanDeadLetter(row rowScanner) (*entity.DeadLetter, error) {
+	var deadLetter entity.DeadLetter
+	var redeliveredAt sql.NullTime
+	err := row.Scan(&deadLetter.ID, &deadLetter.Queue, &deadLetter.Exchange, &deadLetter.RoutingKey, &deadLetter.Reason,
+		&deadLetter.DeathCount, &deadLetter.ContentType, &deadLetter.Body, &deadLetter.Status, &deadLetter.CreatedAt,
+		&redeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	if redeliveredAt.Valid {
+		deadLetter.RedeliveredAt = &redeliveredAt.Time
+	}
+	return &deadLetter, nil
+}
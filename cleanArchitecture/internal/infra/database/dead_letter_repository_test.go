@@ -0,0 +1,73 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"cleanarch/internal/entity"
+	"github.com/stretchr/testify/suite"
+
+	// sqlite3
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type DeadLetterRepositoryTestSuite struct {
+	suite.Suite
+	Db *sql.DB
+}
+
+func (suite *DeadLetterRepositoryTestSuite) SetupTest() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	suite.NoError(err)
+	db.Exec("CREATE TABLE dead_letters (id varchar(255) NOT NULL, queue varchar(255) NOT NULL, exchange varchar(255) NOT NULL, routing_key varchar(255) NOT NULL, reason varchar(50) NOT NULL, death_count int NOT NULL, content_type varchar(255) NOT NULL, body blob NOT NULL, status varchar(20) NOT NULL, created_at datetime NOT NULL, redelivered_at datetime NULL, PRIMARY KEY (id))")
+	suite.Db = db
+}
+
+func (suite *DeadLetterRepositoryTestSuite) TearDownTest() {
+	suite.Db.Close()
+}
+
+func TestDeadLetterRepositorySuite(t *testing.T) {
+	suite.Run(t, new(DeadLetterRepositoryTestSuite))
+}
+
+func (suite *DeadLetterRepositoryTestSuite) TestGivenADeadLetter_WhenSaveAndFind_ThenShouldPersistMessage() {
+	deadLetter, err := entity.NewDeadLetter("dl-1", "orders", "amq.direct", "", "rejected", 2, "application/json", []byte(`{"id":"1"}`))
+	suite.NoError(err)
+	repo := NewDeadLetterRepository(suite.Db)
+	suite.NoError(repo.Save(deadLetter))
+
+	result, err := repo.FindByID("dl-1")
+	suite.NoError(err)
+	suite.Equal("orders", result.Queue)
+	suite.Equal("rejected", result.Reason)
+	suite.Equal(int64(2), result.DeathCount)
+	suite.Equal([]byte(`{"id":"1"}`), result.Body)
+	suite.Equal(entity.DeadLetterPending, result.Status)
+	suite.Nil(result.RedeliveredAt)
+
+	_, err = repo.FindByID("unknown")
+	suite.ErrorIs(err, entity.ErrDeadLetterNotFound)
+}
+
+func (suite *DeadLetterRepositoryTestSuite) TestGivenAPendingDeadLetter_WhenMarkRedelivered_ThenShouldOnlySucceedOnce() {
+	deadLetter, err := entity.NewDeadLetter("dl-1", "orders", "amq.direct", "", "expired", 1, "application/json", []byte("{}"))
+	suite.NoError(err)
+	repo := NewDeadLetterRepository(suite.Db)
+	suite.NoError(repo.Save(deadLetter))
+
+	suite.NoError(repo.MarkRedelivered("dl-1", time.Now()))
+	suite.ErrorIs(repo.MarkRedelivered("dl-1", time.Now()), entity.ErrDeadLetterAlreadyRedelivered)
+	suite.ErrorIs(repo.MarkRedelivered("unknown", time.Now()), entity.ErrDeadLetterNotFound)
+
+	pending, err := repo.FindAll(entity.DeadLetterPending)
+	suite.NoError(err)
+	suite.Len(pending, 0)
+
+	all, err := repo.FindAll("")
+	suite.NoError(err)
+	suite.Len(all, 1)
+	suite.Equal(entity.DeadLetterRedelivered, all[0].Status)
+	suite.NotNil(all[0].RedeliveredAt)
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"cleanarch/internal/entity"
+)
+
+type ImportJobRepository struct {
+	Db *sql.DB
+}
+
+func NewImportJobRepository(db *sql.DB) *ImportJobRepository {
+	return &ImportJobRepository{Db: db}
+}
+
+func (r *ImportJobRepository) Save(job *entity.ImportJob) error {
+	stmt, err := r.Db.Prepare("INSERT INTO import_jobs (id, status, total, processed, failed, errors, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	jobErrors, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(job.ID, job.Status, job.Total, job.Processed, job.Failed, string(jobErrors), job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) Update(job *entity.ImportJob) error {
+	stmt, err := r.Db.Prepare("UPDATE import_jobs SET status = ?, processed = ?, failed = ?, errors = ?, updated_at = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	jobErrors, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(job.Status, job.Processed, job.Failed, string(jobErrors), job.UpdatedAt, job.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) FindByID(id string) (*entity.ImportJob, error) {
+	var job entity.ImportJob
+	var jobErrors string
+	err := r.Db.QueryRow("SELECT id, status, total, processed, failed, errors, created_at, updated_at FROM import_jobs WHERE id = ?", id).
+		Scan(&job.ID, &job.Status, &job.Total, &job.Processed, &job.Failed, &jobErrors, &job.CreatedAt, &job.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrImportJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(jobErrors), &job.Errors); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
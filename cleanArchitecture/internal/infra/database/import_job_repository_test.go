@@ -0,0 +1,59 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"cleanarch/internal/entity"
+	"github.com/stretchr/testify/suite"
+
+	// sqlite3
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type ImportJobRepositoryTestSuite struct {
+	suite.Suite
+	Db *sql.DB
+}
+
+func (suite *ImportJobRepositoryTestSuite) SetupSuite() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	suite.NoError(err)
+	db.Exec("CREATE TABLE import_jobs (id varchar(255) NOT NULL, status varchar(20) NOT NULL, total int NOT NULL, processed int NOT NULL, failed int NOT NULL, errors text NOT NULL, created_at datetime NOT NULL, updated_at datetime NOT NULL, PRIMARY KEY (id))")
+	suite.Db = db
+}
+
+func (suite *ImportJobRepositoryTestSuite) TearDownSuite() {
+	suite.Db.Close()
+}
+
+func TestImportJobRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ImportJobRepositoryTestSuite))
+}
+
+func (suite *ImportJobRepositoryTestSuite) TestGivenAJob_WhenSaveAndUpdate_ThenShouldPersistProgress() {
+	job, err := entity.NewImportJob("job-1", 2)
+	suite.NoError(err)
+	repo := NewImportJobRepository(suite.Db)
+	suite.NoError(repo.Save(job))
+
+	job.Start()
+	job.RecordSuccess()
+	job.RecordFailure("order-2: invalid price")
+	job.Finish()
+	suite.NoError(repo.Update(job))
+
+	result, err := repo.FindByID("job-1")
+	suite.NoError(err)
+	suite.Equal(entity.ImportJobCompleted, result.Status)
+	suite.Equal(2, result.Total)
+	suite.Equal(2, result.Processed)
+	suite.Equal(1, result.Failed)
+	suite.Equal([]string{"order-2: invalid price"}, result.Errors)
+}
+
+func (suite *ImportJobRepositoryTestSuite) TestGivenAnUnknownID_WhenFindByID_ThenShouldReturnNotFound() {
+	repo := NewImportJobRepository(suite.Db)
+	_, err := repo.FindByID("unknown")
+	suite.ErrorIs(err, entity.ErrImportJobNotFound)
+}
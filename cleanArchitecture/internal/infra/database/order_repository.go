@@ -26,6 +26,11 @@ func (r *OrderRepository) Save(order *entity.Order) error {
 	return nil
 }
 
+func (r *OrderRepository) Delete(id string) error {
+	_, err := r.Db.Exec("DELETE FROM orders WHERE id = ?", id)
+	return err
+}
+
 func (r *OrderRepository) GetTotal() (int, error) {
 	var total int
 	err := r.Db.QueryRow("Select count(*) from orders").Scan(&total)
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	"cleanarch/internal/entity"
+)
+
+type RatePlanRepository struct {
+	Db *sql.DB
+}
+
+func NewRatePlanRepository(db *sql.DB) *RatePlanRepository {
+	return &RatePlanRepository{Db: db}
+}
+
+const selectRatePlan = "SELECT id, name, api_key_hash, requests_per_minute, created_at FROM rate_plans"
+
+func (r *RatePlanRepository) Save(plan *entity.RatePlan) error {
+	stmt, err := r.Db.Prepare("INSERT INTO rate_plans (id, name, api_key_hash, requests_per_minute, created_at) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(plan.ID, plan.Name, plan.APIKeyHash, plan.RequestsPerMinute, plan.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *RatePlanRepository) UpdateRequestsPerMinute(id string, requestsPerMinute int) error {
+	stmt, err := r.Db.Prepare("UPDATE rate_plans SET requests_per_minute = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(requestsPerMinute, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// MySQL reports 0 rows when the value did not change, so check the plan exists
+		if _, err := r.FindByID(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RatePlanRepository) FindByID(id string) (*entity.RatePlan, error) {
+	return r.findOne(" WHERE id = ?", id)
+}
+
+func (r *RatePlanRepository) FindByAPIKeyHash(apiKeyHash string) (*entity.RatePlan, error) {
+	return r.findOne(" WHERE api_key_hash = ?", apiKeyHash)
+}
+
+func (r *RatePlanRepository) findOne(where string, arg string) (*entity.RatePlan, error) {
+	plan, err := scanRatePlan(r.Db.QueryRow(selectRatePlan+where, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrRatePlanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (r *RatePlanRepository) FindAll() ([]entity.RatePlan, error) {
+	rows, err := r.Db.Query(selectRatePlan + " ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []entity.RatePlan
+	for rows.Next() {
+		plan, err := scanRatePlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+	return plans, rows.Err()
+}
+
+func scanRatePlan(row rowScanner) (*entity.RatePlan, error) {
+	var plan entity.RatePlan
+	err := row.Scan(&plan.ID, &plan.Name, &plan.APIKeyHash, &plan.RequestsPerMinute, &plan.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"cleanarch/internal/entity"
+	"github.com/stretchr/testify/suite"
+
+	// sqlite3
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type RatePlanRepositoryTestSuite struct {
+	suite.Suite
+	Db *sql.DB
+}
+
+func (suite *RatePlanRepositoryTestSuite) SetupTest() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	suite.NoError(err)
+	db.Exec("CREATE TABLE rate_plans (id varchar(255) NOT NULL, name varchar(255) NOT NULL, api_key_hash char(64) NOT NULL UNIQUE, requests_per_minute int NOT NULL, created_at datetime NOT NULL, PRIMARY KEY (id))")
+	suite.Db = db
+}
+
+func (suite *RatePlanRepositoryTestSuite) TearDownTest() {
+	suite.Db.Close()
+}
+
+func TestRatePlanRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RatePlanRepositoryTestSuite))
+}
+
+func (suite *RatePlanRepositoryTestSuite) TestGivenARatePlan_WhenSave_ThenShouldFindItByIDAndKeyHash() {
+	plan, err := entity.NewRatePlan("plan-1", "acme", "secret-key", 60)
+	suite.NoError(err)
+	repo := NewRatePlanRepository(suite.Db)
+	suite.NoError(repo.Save(plan))
+
+	result, err := repo.FindByAPIKeyHash(entity.HashAPIKey("secret-key"))
+	suite.NoError(err)
+	suite.Equal("plan-1", result.ID)
+	suite.Equal("acme", result.Name)
+	suite.Equal(60, result.RequestsPerMinute)
+
+	_, err = repo.FindByAPIKeyHash(entity.HashAPIKey("other-key"))
+	suite.ErrorIs(err, entity.ErrRatePlanNotFound)
+	_, err = repo.FindByID("unknown")
+	suite.ErrorIs(err, entity.ErrRatePlanNotFound)
+
+	plans, err := repo.FindAll()
+	suite.NoError(err)
+	suite.Len(plans, 1)
+}
+
+func (suite *RatePlanRepositoryTestSuite) TestGivenARatePlan_WhenUpdateRequestsPerMinute_ThenShouldPersistTheNewLimit() {
+	plan, err := entity.NewRatePlan("plan-1", "acme", "secret-key", 60)
+	suite.NoError(err)
+	repo := NewRatePlanRepository(suite.Db)
+	suite.NoError(repo.Save(plan))
+
+	suite.NoError(repo.UpdateRequestsPerMinute("plan-1", 120))
+	result, err := repo.FindByID("plan-1")
+	suite.NoError(err)
+	suite.Equal(120, result.RequestsPerMinute)
+
+	suite.ErrorIs(repo.UpdateRequestsPerMinute("unknown", 10), entity.ErrRatePlanNotFound)
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"cleanarch/internal/entity"
+)
+
+type WebhookSubscriptionRepository struct {
+	Db *sql.DB
+}
+
+func NewWebhookSubscriptionRepository(db *sql.DB) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{Db: db}
+}
+
+const selectWebhookSubscription = "SELECT id, url, event_types, filters, delivered, failed, last_status_code, last_error, last_delivery_at, created_at FROM webhook_subscriptions"
+
+func (r *WebhookSubscriptionRepository) Save(subscription *entity.WebhookSubscription) error {
+	stmt, err := r.Db.Prepare("INSERT INTO webhook_subscriptions (id, url, event_types, filters, delivered, failed, last_status_code, last_error, last_delivery_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return err
+	}
+	filters, err := json.Marshal(subscription.Filters)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(subscription.ID, subscription.URL, string(eventTypes), string(filters),
+		subscription.Delivered, subscription.Failed, subscription.LastStatusCode, subscription.LastError,
+		subscription.LastDeliveryAt, subscription.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) RecordDelivery(id string, statusCode int, deliveryErr error, at time.Time) error {
+	delivered, failed, lastError := 1, 0, ""
+	if deliveryErr != nil {
+		delivered, failed, lastError = 0, 1, deliveryErr.Error()
+	}
+	stmt, err := r.Db.Prepare("UPDATE webhook_subscriptions SET delivered = delivered + ?, failed = failed + ?, last_status_code = ?, last_error = ?, last_delivery_at = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(delivered, failed, statusCode, lastError, at, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) FindByID(id string) (*entity.WebhookSubscription, error) {
+	subscription, err := scanWebhookSubscription(r.Db.QueryRow(selectWebhookSubscription+" WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrWebhookSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) FindAll() ([]entity.WebhookSubscription, error) {
+	rows, err := r.Db.Query(selectWebhookSubscription + " ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []entity.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhookSubscription(row rowScanner) (*entity.WebhookSubscription, error) {
+	var subscription entity.WebhookSubscription
+	var eventTypes, filters string
+	var lastDeliveryAt sql.NullTime
+	err := row.Scan(&subscription.ID, &subscription.URL, &eventTypes, &filters,
+		&subscription.Delivered, &subscription.Failed, &subscription.LastStatusCode, &subscription.LastError,
+		&lastDeliveryAt, &subscription.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(eventTypes), &subscription.EventTypes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(filters), &subscription.Filters); err != nil {
+		return nil, err
+	}
+	if lastDeliveryAt.Valid {
+		subscription.LastDeliveryAt = &lastDeliveryAt.Time
+	}
+	return &subscription, nil
+}
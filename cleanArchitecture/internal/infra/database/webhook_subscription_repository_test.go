@@ -0,0 +1,67 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"cleanarch/internal/entity"
+	"github.com/stretchr/testify/suite"
+
+	// sqlite3
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type WebhookSubscriptionRepositoryTestSuite struct {
+	suite.Suite
+	Db *sql.DB
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) SetupSuite() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	suite.NoError(err)
+	db.Exec("CREATE TABLE webhook_subscriptions (id varchar(255) NOT NULL, url varchar(2048) NOT NULL, event_types text NOT NULL, filters text NOT NULL, delivered int NOT NULL, failed int NOT NULL, last_status_code int NOT NULL, last_error text NOT NULL, last_delivery_at datetime NULL, created_at datetime NOT NULL, PRIMARY KEY (id))")
+	suite.Db = db
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) TearDownSuite() {
+	suite.Db.Close()
+}
+
+func TestWebhookSubscriptionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WebhookSubscriptionRepositoryTestSuite))
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) TestGivenASubscription_WhenSaveAndRecordDelivery_ThenShouldPersistFiltersAndStats() {
+	subscription, err := entity.NewWebhookSubscription("sub-1", "http://example.com/hook", []string{"OrderCreated"}, []entity.WebhookFilter{
+		{Field: entity.WebhookFieldPrice, Operator: entity.FilterGreaterThan, Value: 100},
+	})
+	suite.NoError(err)
+	repo := NewWebhookSubscriptionRepository(suite.Db)
+	suite.NoError(repo.Save(subscription))
+
+	suite.NoError(repo.RecordDelivery("sub-1", 200, nil, time.Now()))
+	suite.NoError(repo.RecordDelivery("sub-1", 502, errors.New("unexpected status 502"), time.Now()))
+
+	result, err := repo.FindByID("sub-1")
+	suite.NoError(err)
+	suite.Equal("http://example.com/hook", result.URL)
+	suite.Equal([]string{"OrderCreated"}, result.EventTypes)
+	suite.Equal(subscription.Filters, result.Filters)
+	suite.Equal(1, result.Delivered)
+	suite.Equal(1, result.Failed)
+	suite.Equal(502, result.LastStatusCode)
+	suite.Equal("unexpected status 502", result.LastError)
+	suite.NotNil(result.LastDeliveryAt)
+
+	all, err := repo.FindAll()
+	suite.NoError(err)
+	suite.Len(all, 1)
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) TestGivenAnUnknownID_WhenFindByID_ThenShouldReturnNotFound() {
+	repo := NewWebhookSubscriptionRepository(suite.Db)
+	_, err := repo.FindByID("unknown")
+	suite.ErrorIs(err, entity.ErrWebhookSubscriptionNotFound)
+}
@@ -2,6 +2,15 @@
 
 package model
 
+type ImportJob struct {
+	ID        string   `json:"id"`
+	Status    string   `json:"status"`
+	Total     int      `json:"total"`
+	Processed int      `json:"processed"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors"`
+}
+
 type Order struct {
 	ID         string  `json:"id"`
 	Price      float64 `json:"Price"`
@@ -10,7 +19,7 @@ type Order struct {
 }
 
 type OrderInput struct {
-	ID    string  `json:"id"`
+	ID    *string `json:"id"`
 	Price float64 `json:"Price"`
 	Tax   float64 `json:"Tax"`
 }
@@ -2,6 +2,7 @@ package graph
 
 import (
 	"cleanarch/internal/entity"
+	"cleanarch/internal/infra/graph/model"
 	"cleanarch/internal/usecase"
 )
 
@@ -10,7 +11,31 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	CreateOrderUseCase usecase.CreateOrderUseCase
-	ListOrdersUseCase  usecase.ListOrdersUseCase
-	OrderRepository    entity.OrderRepositoryInterface
+	CreateOrderUseCase  usecase.CreateOrderUseCase
+	ListOrdersUseCase   usecase.ListOrdersUseCase
+	ImportOrdersUseCase *usecase.ImportOrdersUseCase
+	ImportStatusUseCase usecase.ImportStatusUseCase
+	OrderRepository     entity.OrderRepositoryInterface
+}
+
+func toImportJobModel(dto usecase.ImportJobOutputDTO) *model.ImportJob {
+	return &model.ImportJob{
+		ID:        dto.ID,
+		Status:    dto.Status,
+		Total:     dto.Total,
+		Processed: dto.Processed,
+		Failed:    dto.Failed,
+		Errors:    dto.Errors,
+	}
+}
+
+func toOrderInputDTO(input *model.OrderInput) usecase.OrderInputDTO {
+	dto := usecase.OrderInputDTO{
+		Price: input.Price,
+		Tax:   input.Tax,
+	}
+	if input.ID != nil {
+		dto.ID = *input.ID
+	}
+	return dto
 }
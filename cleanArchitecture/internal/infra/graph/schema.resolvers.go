@@ -5,18 +5,16 @@ package graph
 // Code generated by github.com/99designs/gqlgen version v0.17.22
 
 import (
+	"cleanarch/internal/entity"
 	"cleanarch/internal/infra/graph/model"
 	"cleanarch/internal/usecase"
 	"context"
+	"errors"
 )
 
 // CreateOrder is the resolver for the createOrder field.
 func (r *mutationResolver) CreateOrder(ctx context.Context, input *model.OrderInput) (*model.Order, error) {
-	dto, err := r.CreateOrderUseCase.Execute(usecase.OrderInputDTO{
-		ID:    input.ID,
-		Price: input.Price,
-		Tax:   input.Tax,
-	})
+	dto, err := r.CreateOrderUseCase.ExecuteContext(ctx, toOrderInputDTO(input))
 	if err != nil {
 		return nil, err
 	}
@@ -29,6 +27,24 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input *model.OrderIn
 	}, nil
 }
 
+// ImportOrders is the resolver for the importOrders field.
+func (r *mutationResolver) ImportOrders(ctx context.Context, input []*model.OrderInput, async *bool) (*model.ImportJob, error) {
+	orders := make([]usecase.OrderInputDTO, 0, len(input))
+	for _, order := range input {
+		orders = append(orders, toOrderInputDTO(order))
+	}
+
+	dto, err := r.ImportOrdersUseCase.Execute(usecase.ImportOrdersInputDTO{
+		Orders: orders,
+		Async:  async != nil && *async,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toImportJobModel(dto), nil
+}
+
 // Orders is the resolver for the orders field.
 func (r *queryResolver) Orders(ctx context.Context) ([]*model.Order, error) {
 	orders, err := r.ListOrdersUseCase.Execute()
@@ -49,6 +65,19 @@ func (r *queryResolver) Orders(ctx context.Context) ([]*model.Order, error) {
 	return result, nil
 }
 
+// ImportStatus is the resolver for the importStatus field.
+func (r *queryResolver) ImportStatus(ctx context.Context, jobID string) (*model.ImportJob, error) {
+	dto, err := r.ImportStatusUseCase.Execute(jobID)
+	if errors.Is(err, entity.ErrImportJobNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toImportJobModel(dto), nil
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
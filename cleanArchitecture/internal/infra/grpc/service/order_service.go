@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"cleanarch/internal/entity"
 	"cleanarch/internal/infra/grpc/pb"
 	"cleanarch/internal/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type OrderService struct {
@@ -27,7 +31,13 @@ func (s *OrderService) CreateOrder(ctx context.Context, in *pb.CreateOrderReques
 		Price: float64(in.Price),
 		Tax:   float64(in.Tax),
 	}
-	output, err := s.CreateOrderUseCase.Execute(dto)
+	output, err := s.CreateOrderUseCase.ExecuteContext(ctx, dto)
+	if errors.Is(err, usecase.ErrClientOrderIDNotAllowed) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, entity.ErrPaymentDeclined) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+
+	"cleanarch/internal/entity"
+	"cleanarch/internal/usecase"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadata is the metadata key carrying the API key of a call
+const APIKeyMetadata = "x-api-key"
+
+// RateLimitUnaryInterceptor counts each unary call against the rate plan of its
+// API key, failing with Unauthenticated for missing or unknown keys and
+// ResourceExhausted once the plan is used up for the current minute
+func RateLimitUnaryInterceptor(enforceRatePlanUseCase *usecase.EnforceRatePlanUseCase) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var apiKey string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(APIKeyMetadata); len(values) > 0 {
+				apiKey = values[0]
+			}
+		}
+
+		decision, err := enforceRatePlanUseCase.Execute(apiKey)
+		switch {
+		case errors.Is(err, usecase.ErrMissingAPIKey), errors.Is(err, entity.ErrRatePlanNotFound):
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		case errors.Is(err, entity.ErrRateLimitExceeded):
+			retryAfter := strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds())))
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter))
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case err != nil:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		grpc.SetHeader(ctx, metadata.Pairs("x-ratelimit-remaining", strconv.Itoa(decision.Remaining)))
+		return handler(ctx, req)
+	}
+}
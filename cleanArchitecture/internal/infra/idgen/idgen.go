@@ -0,0 +1,30 @@
+package idgen
+
+import (
+	"database/sql"
+	"fmt"
+
+	"cleanarch/internal/entity"
+)
+
+// Supported values for ORDER_ID_STRATEGY.
+const (
+	StrategyUUIDv7    = "uuidv7"
+	StrategySnowflake = "snowflake"
+	StrategySequence  = "sequence"
+)
+
+// New builds the order ID generator for strategy. An empty strategy defaults to UUIDv7.
+// node is only used by snowflake; db and prefix only by sequence.
+func New(strategy string, db *sql.DB, prefix string, node int64) (entity.OrderIDGeneratorInterface, error) {
+	switch strategy {
+	case "", StrategyUUIDv7:
+		return NewUUIDv7Generator(), nil
+	case StrategySnowflake:
+		return NewSnowflakeGenerator(node)
+	case StrategySequence:
+		return NewSequenceGenerator(db, prefix), nil
+	default:
+		return nil, fmt.Errorf("unknown order id strategy %q", strategy)
+	}
+}
@@ -0,0 +1,109 @@
+package idgen
+
+import (
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	// sqlite3
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestGivenAnUnknownStrategy_WhenNew_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := New("autoincrement", nil, "", 0)
+	assert.Error(t, err)
+}
+
+func TestGivenAnEmptyStrategy_WhenNew_ThenShouldDefaultToUUIDv7(t *testing.T) {
+	generator, err := New("", nil, "", 0)
+	require.NoError(t, err)
+	assert.IsType(t, &UUIDv7Generator{}, generator)
+}
+
+func TestGivenUUIDv7Generator_WhenGenerate_ThenShouldReturnVersion7UUIDs(t *testing.T) {
+	generator := NewUUIDv7Generator()
+
+	first, err := generator.Generate()
+	require.NoError(t, err)
+	second, err := generator.Generate()
+	require.NoError(t, err)
+
+	parsed, err := uuid.Parse(first)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), parsed.Version())
+	assert.NotEqual(t, first, second)
+}
+
+func TestGivenAnInvalidNode_WhenNewSnowflakeGenerator_ThenShouldReceiveAnError(t *testing.T) {
+	_, err := NewSnowflakeGenerator(snowflakeMaxNode + 1)
+	assert.Error(t, err)
+	_, err = NewSnowflakeGenerator(-1)
+	assert.Error(t, err)
+}
+
+func TestGivenSnowflakeGenerator_WhenGenerateInTheSameMillisecond_ThenShouldReturnIncreasingIDs(t *testing.T) {
+	generator, err := NewSnowflakeGenerator(7)
+	require.NoError(t, err)
+	fixed := snowflakeEpoch.Add(time.Hour)
+	generator.now = func() time.Time { return fixed }
+
+	var last int64
+	seen := make(map[string]bool)
+	for i := 0; i < snowflakeMaxSequence+10; i++ {
+		id, err := generator.Generate()
+		require.NoError(t, err)
+		assert.False(t, seen[id], "duplicated id %s", id)
+		seen[id] = true
+
+		n, err := strconv.ParseInt(id, 10, 64)
+		require.NoError(t, err)
+		assert.Greater(t, n, last)
+		assert.Equal(t, int64(7), n>>snowflakeSequenceBits&snowflakeMaxNode)
+		last = n
+	}
+}
+
+func TestGivenSnowflakeGenerator_WhenClockMovesBackwards_ThenShouldNotRepeatIDs(t *testing.T) {
+	generator, err := NewSnowflakeGenerator(1)
+	require.NoError(t, err)
+	now := snowflakeEpoch.Add(time.Hour)
+	generator.now = func() time.Time { return now }
+
+	first, err := generator.Generate()
+	require.NoError(t, err)
+	now = now.Add(-time.Second)
+	second, err := generator.Generate()
+	require.NoError(t, err)
+
+	a, _ := strconv.ParseInt(first, 10, 64)
+	b, _ := strconv.ParseInt(second, 10, 64)
+	assert.Greater(t, b, a)
+}
+
+func TestGivenSequenceGenerator_WhenGenerate_ThenShouldReturnPrefixedSequentialIDs(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE order_sequence (id INTEGER PRIMARY KEY AUTOINCREMENT, stub CHAR(1) NOT NULL UNIQUE)")
+	require.NoError(t, err)
+
+	generator, err := New(StrategySequence, db, "ORD-", 0)
+	require.NoError(t, err)
+
+	first, err := generator.Generate()
+	require.NoError(t, err)
+	second, err := generator.Generate()
+	require.NoError(t, err)
+
+	assert.Equal(t, "ORD-00000001", first)
+	assert.Equal(t, "ORD-00000002", second)
+
+	var rows int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM order_sequence").Scan(&rows))
+	assert.Equal(t, 1, rows)
+}
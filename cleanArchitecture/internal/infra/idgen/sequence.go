@@ -0,0 +1,30 @@
+package idgen
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// SequenceGenerator generates human-friendly IDs such as "ORD-00000042" from
+// an auto-increment table, so numbers are sequential across instances. The
+// table keeps a single row: REPLACE swaps it for a new one with the next id.
+type SequenceGenerator struct {
+	Db     *sql.DB
+	Prefix string
+}
+
+func NewSequenceGenerator(db *sql.DB, prefix string) *SequenceGenerator {
+	return &SequenceGenerator{Db: db, Prefix: prefix}
+}
+
+func (g *SequenceGenerator) Generate() (string, error) {
+	result, err := g.Db.Exec("REPLACE INTO order_sequence (stub) VALUES ('o')")
+	if err != nil {
+		return "", err
+	}
+	n, err := result.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%08d", g.Prefix, n), nil
+}
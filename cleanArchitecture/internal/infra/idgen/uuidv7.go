@@ -0,0 +1,18 @@
+package idgen
+
+import "github.com/google/uuid"
+
+// UUIDv7Generator generates time-ordered UUIDs (RFC 9562 version 7).
+type UUIDv7Generator struct{}
+
+func NewUUIDv7Generator() *UUIDv7Generator {
+	return &UUIDv7Generator{}
+}
+
+func (g *UUIDv7Generator) Generate() (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
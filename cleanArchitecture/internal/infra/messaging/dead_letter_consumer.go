@@ -0,0 +1,94 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cleanarch/internal/usecase"
+	"github.com/streadway/amqp"
+)
+
+// retryDelay spaces out attempts to record a dead letter while the database is unavailable
+const retryDelay = 5 * time.Second
+
+type DeadLetterConsumer struct {
+	Channel                 *amqp.Channel
+	Queue                   string
+	RecordDeadLetterUseCase *usecase.RecordDeadLetterUseCase
+}
+
+func NewDeadLetterConsumer(ch *amqp.Channel, queue string, recordDeadLetterUseCase *usecase.RecordDeadLetterUseCase) *DeadLetterConsumer {
+	return &DeadLetterConsumer{
+		Channel:                 ch,
+		Queue:                   queue,
+		RecordDeadLetterUseCase: recordDeadLetterUseCase,
+	}
+}
+
+// Start consumes the DLQ until ctx ends or the channel closes. A message is
+// acked only after it is stored, so nothing is lost if recording fails.
+func (c *DeadLetterConsumer) Start(ctx context.Context) error {
+	deliveries, err := c.Channel.Consume(c.Queue, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case delivery, ok := <-deliveries:
+				if !ok {
+					return
+				}
+				c.handle(ctx, delivery)
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *DeadLetterConsumer) handle(ctx context.Context, delivery amqp.Delivery) {
+	output, err := c.RecordDeadLetterUseCase.Execute(deadLetterInputFromDelivery(delivery))
+	if err != nil {
+		fmt.Printf("Failed to record dead letter: %v\n", err)
+		select {
+		case <-time.After(retryDelay):
+		case <-ctx.Done():
+		}
+		delivery.Nack(false, true)
+		return
+	}
+	fmt.Printf("Recorded dead letter %s from queue %s (%s)\n", output.ID, output.Queue, output.Reason)
+	delivery.Ack(false)
+}
+
+// deadLetterInputFromDelivery reads the origin of the message from the most
+// recent x-death entry the broker adds when dead-lettering
+func deadLetterInputFromDelivery(delivery amqp.Delivery) usecase.DeadLetterInputDTO {
+	input := usecase.DeadLetterInputDTO{
+		Exchange:    delivery.Exchange,
+		RoutingKey:  delivery.RoutingKey,
+		ContentType: delivery.ContentType,
+		Body:        delivery.Body,
+	}
+
+	deaths, _ := delivery.Headers["x-death"].([]interface{})
+	if len(deaths) == 0 {
+		input.Queue = delivery.RoutingKey
+		return input
+	}
+	death, _ := deaths[0].(amqp.Table)
+
+	input.Queue, _ = death["queue"].(string)
+	input.Reason, _ = death["reason"].(string)
+	input.Exchange, _ = death["exchange"].(string)
+	input.DeathCount, _ = death["count"].(int64)
+	input.RoutingKey = ""
+	if routingKeys, _ := death["routing-keys"].([]interface{}); len(routingKeys) > 0 {
+		input.RoutingKey, _ = routingKeys[0].(string)
+	}
+	return input
+}
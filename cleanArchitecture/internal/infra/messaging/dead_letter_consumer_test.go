@@ -0,0 +1,52 @@
+package messaging
+
+import (
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnXDeathHeader_WhenReadingADeadLetter_ThenShouldUseTheOriginalQueueAndRoute(t *testing.T) {
+	delivery := amqp.Delivery{
+		Exchange:    "orders.dlx",
+		RoutingKey:  "",
+		ContentType: "application/json",
+		Body:        []byte(`{"id":"1"}`),
+		Headers: amqp.Table{
+			"x-death": []interface{}{
+				amqp.Table{
+					"queue":        "orders",
+					"reason":       "rejected",
+					"exchange":     "amq.direct",
+					"routing-keys": []interface{}{"orders.created"},
+					"count":        int64(3),
+				},
+			},
+		},
+	}
+
+	input := deadLetterInputFromDelivery(delivery)
+
+	assert.Equal(t, "orders", input.Queue)
+	assert.Equal(t, "rejected", input.Reason)
+	assert.Equal(t, "amq.direct", input.Exchange)
+	assert.Equal(t, "orders.created", input.RoutingKey)
+	assert.Equal(t, int64(3), input.DeathCount)
+	assert.Equal(t, []byte(`{"id":"1"}`), input.Body)
+}
+
+func TestGivenNoXDeathHeader_WhenReadingADeadLetter_ThenShouldKeepTheDeliveryRoute(t *testing.T) {
+	input := deadLetterInputFromDelivery(amqp.Delivery{Exchange: "orders.dlx", RoutingKey: "orders"})
+
+	assert.Equal(t, "orders", input.Queue)
+	assert.Equal(t, "orders.dlx", input.Exchange)
+	assert.Empty(t, input.Reason)
+}
+
+func TestGivenATopology_WhenNamingTheDeadLetterResources_ThenShouldDeriveThemFromTheQueue(t *testing.T) {
+	topology := NewOrderQueueTopology("orders", 0)
+
+	assert.Equal(t, "orders.dlx", topology.DeadLetterExchange())
+	assert.Equal(t, "orders.dlq", topology.DeadLetterQueue())
+}
@@ -0,0 +1,74 @@
+package messaging
+
+import (
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// OrderQueueTopology describes the queue that receives OrderCreated and the
+// dead-letter exchange and queue that collect the messages it gives up on.
+type OrderQueueTopology struct {
+	Exchange   string
+	RoutingKey string
+	Queue      string
+	// MessageTTL dead-letters messages nobody consumed in time (0 keeps them forever)
+	MessageTTL time.Duration
+}
+
+func NewOrderQueueTopology(queue string, messageTTL time.Duration) OrderQueueTopology {
+	return OrderQueueTopology{
+		Exchange:   "amq.direct",
+		RoutingKey: "",
+		Queue:      queue,
+		MessageTTL: messageTTL,
+	}
+}
+
+func (t OrderQueueTopology) DeadLetterExchange() string {
+	return t.Queue + ".dlx"
+}
+
+func (t OrderQueueTopology) DeadLetterQueue() string {
+	return t.Queue + ".dlq"
+}
+
+// Declare creates the order queue bound to the order exchange, with messages
+// that are rejected without requeue, expire or overflow routed to the DLQ.
+// Declaring is idempotent, but an existing queue with other arguments makes the
+// broker close the channel, so such a queue must be deleted first.
+func (t OrderQueueTopology) Declare(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(t.DeadLetterExchange(), "fanout", true, false, false, false, nil); err != nil {
+		return err
+	}
+	if _, err := ch.QueueDeclare(t.DeadLetterQueue(), true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.QueueBind(t.DeadLetterQueue(), "", t.DeadLetterExchange(), false, nil); err != nil {
+		return err
+	}
+
+	args := amqp.Table{"x-dead-letter-exchange": t.DeadLetterExchange()}
+	if t.MessageTTL > 0 {
+		args["x-message-ttl"] = t.MessageTTL.Milliseconds()
+	}
+	if _, err := ch.QueueDeclare(t.Queue, true, false, false, false, args); err != nil {
+		return err
+	}
+	return ch.QueueBind(t.Queue, t.RoutingKey, t.Exchange, false, nil)
+}
+
+type Publisher struct {
+	Channel *amqp.Channel
+}
+
+func NewPublisher(ch *amqp.Channel) *Publisher {
+	return &Publisher{Channel: ch}
+}
+
+func (p *Publisher) Publish(exchange, routingKey, contentType string, body []byte) error {
+	return p.Channel.Publish(exchange, routingKey, false, false, amqp.Publishing{
+		ContentType: contentType,
+		Body:        body,
+	})
+}
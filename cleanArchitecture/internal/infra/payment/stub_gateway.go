@@ -0,0 +1,60 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"cleanarch/internal/entity"
+	"github.com/google/uuid"
+)
+
+// ErrReservationNotFound is returned when cancelling an unknown reservation.
+var ErrReservationNotFound = errors.New("payment reservation not found")
+
+// StubGateway keeps reservations in memory until a real payment provider is
+// integrated. Amounts above MaxAmount are declined so the compensation path
+// can be exercised; a zero MaxAmount accepts any amount.
+type StubGateway struct {
+	MaxAmount float64
+
+	mu           sync.Mutex
+	reservations map[string]float64
+}
+
+func NewStubGateway(maxAmount float64) *StubGateway {
+	return &StubGateway{
+		MaxAmount:    maxAmount,
+		reservations: make(map[string]float64),
+	}
+}
+
+func (g *StubGateway) Reserve(ctx context.Context, orderID string, amount float64) (string, error) {
+	if g.MaxAmount > 0 && amount > g.MaxAmount {
+		return "", fmt.Errorf("%w: %.2f exceeds the limit of %.2f", entity.ErrPaymentDeclined, amount, g.MaxAmount)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	id := uuid.New().String()
+	g.reservations[id] = amount
+	return id, nil
+}
+
+func (g *StubGateway) Cancel(ctx context.Context, reservationID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.reservations[reservationID]; !ok {
+		return ErrReservationNotFound
+	}
+	delete(g.reservations, reservationID)
+	return nil
+}
+
+// Reserved returns how many reservations are currently held.
+func (g *StubGateway) Reserved() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.reservations)
+}
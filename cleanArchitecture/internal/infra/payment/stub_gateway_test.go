@@ -0,0 +1,30 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"cleanarch/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnAmountAboveTheLimit_WhenReserve_ThenShouldDecline(t *testing.T) {
+	gateway := NewStubGateway(100)
+
+	_, err := gateway.Reserve(context.Background(), "order-1", 150)
+
+	assert.ErrorIs(t, err, entity.ErrPaymentDeclined)
+	assert.Equal(t, 0, gateway.Reserved())
+}
+
+func TestGivenAReservation_WhenCancel_ThenShouldReleaseIt(t *testing.T) {
+	gateway := NewStubGateway(0)
+
+	id, err := gateway.Reserve(context.Background(), "order-1", 150)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, gateway.Reserved())
+
+	assert.NoError(t, gateway.Cancel(context.Background(), id))
+	assert.Equal(t, 0, gateway.Reserved())
+	assert.ErrorIs(t, gateway.Cancel(context.Background(), id), ErrReservationNotFound)
+}
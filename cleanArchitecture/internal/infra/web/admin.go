@@ -0,0 +1,28 @@
+package web
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// authorizeAdmin checks the method and, when adminToken is set, the
+// "Authorization: Bearer <token>" header of an admin route
+func authorizeAdmin(w http.ResponseWriter, r *http.Request, adminToken string, methods ...string) bool {
+	allowed := false
+	for _, method := range methods {
+		if r.Method == method {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	expected := []byte("Bearer " + adminToken)
+	if adminToken != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"cleanarch/internal/entity"
+	"cleanarch/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+type WebDeadLetterHandler struct {
+	ListDeadLettersUseCase     *usecase.ListDeadLettersUseCase
+	RedeliverDeadLetterUseCase *usecase.RedeliverDeadLetterUseCase
+	// AdminToken, when set, must be sent as "Authorization: Bearer <token>"
+	AdminToken string
+}
+
+func NewWebDeadLetterHandler(
+	ListDeadLettersUseCase *usecase.ListDeadLettersUseCase,
+	RedeliverDeadLetterUseCase *usecase.RedeliverDeadLetterUseCase,
+	AdminToken string,
+) *WebDeadLetterHandler {
+	return &WebDeadLetterHandler{
+		ListDeadLettersUseCase:     ListDeadLettersUseCase,
+		RedeliverDeadLetterUseCase: RedeliverDeadLetterUseCase,
+		AdminToken:                 AdminToken,
+	}
+}
+
+// List returns the dead letters, optionally only those with ?status=PENDING or REDELIVERED
+func (h *WebDeadLetterHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != entity.DeadLetterPending && status != entity.DeadLetterRedelivered {
+		http.Error(w, "invalid status", http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.ListDeadLettersUseCase.Execute(status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeDeadLetterJSON(w, output)
+}
+
+func (h *WebDeadLetterHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	output, err := h.ListDeadLettersUseCase.FindByID(chi.URLParam(r, "id"))
+	if errors.Is(err, entity.ErrDeadLetterNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeDeadLetterJSON(w, output)
+}
+
+// Redeliver publishes a pending dead letter again to its original exchange
+func (h *WebDeadLetterHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPost) {
+		return
+	}
+
+	output, err := h.RedeliverDeadLetterUseCase.Execute(chi.URLParam(r, "id"))
+	if errors.Is(err, entity.ErrDeadLetterNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, entity.ErrDeadLetterAlreadyRedelivered) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeDeadLetterJSON(w, output)
+}
+
+func (h *WebDeadLetterHandler) authorize(w http.ResponseWriter, r *http.Request, method string) bool {
+	return authorizeAdmin(w, r, h.AdminToken, method)
+}
+
+func writeDeadLetterJSON(w http.ResponseWriter, output interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(output); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
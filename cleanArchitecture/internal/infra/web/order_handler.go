@@ -2,6 +2,7 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"cleanarch/internal/entity"
@@ -12,18 +13,22 @@ import (
 type WebOrderHandler struct {
 	EventDispatcher   events.EventDispatcherInterface
 	OrderRepository   entity.OrderRepositoryInterface
-	OrderCreatedEvent events.EventInterface
+	OrderCreatedEvent events.EventFactory
+	OrderIDPolicy     usecase.OrderIDPolicy
+	PaymentGateway    entity.PaymentGatewayInterface
 }
 
 func NewWebOrderHandler(
 	EventDispatcher events.EventDispatcherInterface,
 	OrderRepository entity.OrderRepositoryInterface,
-	OrderCreatedEvent events.EventInterface,
+	OrderCreatedEvent events.EventFactory,
+	OrderIDPolicy usecase.OrderIDPolicy,
 ) *WebOrderHandler {
 	return &WebOrderHandler{
 		EventDispatcher:   EventDispatcher,
 		OrderRepository:   OrderRepository,
 		OrderCreatedEvent: OrderCreatedEvent,
+		OrderIDPolicy:     OrderIDPolicy,
 	}
 }
 
@@ -35,8 +40,17 @@ func (h *WebOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createOrder := usecase.NewCreateOrderUseCase(h.OrderRepository, h.OrderCreatedEvent, h.EventDispatcher)
-	output, err := createOrder.Execute(dto)
+	createOrder := usecase.NewCreateOrderUseCase(h.OrderRepository, h.OrderCreatedEvent, h.EventDispatcher, h.OrderIDPolicy)
+	createOrder.PaymentGateway = h.PaymentGateway
+	output, err := createOrder.ExecuteContext(r.Context(), dto)
+	if errors.Is(err, usecase.ErrClientOrderIDNotAllowed) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, entity.ErrPaymentDeclined) {
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -0,0 +1,49 @@
+package web
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"cleanarch/internal/entity"
+	"cleanarch/internal/usecase"
+)
+
+// APIKeyHeader carries the API key whose rate plan a request counts against
+const APIKeyHeader = "X-API-Key"
+
+type RateLimitMiddleware struct {
+	EnforceRatePlanUseCase *usecase.EnforceRatePlanUseCase
+}
+
+func NewRateLimitMiddleware(EnforceRatePlanUseCase *usecase.EnforceRatePlanUseCase) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		EnforceRatePlanUseCase: EnforceRatePlanUseCase,
+	}
+}
+
+// Wrap answers 401 for missing or unknown API keys and 429 with Retry-After
+// once the key's plan is used up for the current minute
+func (m *RateLimitMiddleware) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decision, err := m.EnforceRatePlanUseCase.Execute(r.Header.Get(APIKeyHeader))
+		switch {
+		case errors.Is(err, usecase.ErrMissingAPIKey), errors.Is(err, entity.ErrRatePlanNotFound):
+			http.Error(w, "invalid api key", http.StatusUnauthorized)
+			return
+		case errors.Is(err, entity.ErrRateLimitExceeded):
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		next(w, r)
+	}
+}
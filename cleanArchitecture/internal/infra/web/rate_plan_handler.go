@@ -0,0 +1,107 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"cleanarch/internal/entity"
+	"cleanarch/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+type WebRatePlanHandler struct {
+	RatePlanRepository     entity.RatePlanRepositoryInterface
+	EnforceRatePlanUseCase *usecase.EnforceRatePlanUseCase
+	// AdminToken, when set, must be sent as "Authorization: Bearer <token>"
+	AdminToken string
+}
+
+func NewWebRatePlanHandler(
+	RatePlanRepository entity.RatePlanRepositoryInterface,
+	EnforceRatePlanUseCase *usecase.EnforceRatePlanUseCase,
+	AdminToken string,
+) *WebRatePlanHandler {
+	return &WebRatePlanHandler{
+		RatePlanRepository:     RatePlanRepository,
+		EnforceRatePlanUseCase: EnforceRatePlanUseCase,
+		AdminToken:             AdminToken,
+	}
+}
+
+// RatePlansHandler lists the plans with their usage (GET) or creates a plan (POST)
+func (h *WebRatePlanHandler) RatePlansHandler(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r, h.AdminToken, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if r.Method == http.MethodPost {
+		h.create(w, r)
+		return
+	}
+
+	output, err := usecase.NewListRatePlansUseCase(h.RatePlanRepository, h.EnforceRatePlanUseCase).Execute()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeRatePlanJSON(w, http.StatusOK, output)
+}
+
+func (h *WebRatePlanHandler) create(w http.ResponseWriter, r *http.Request) {
+	var dto usecase.RatePlanInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := usecase.NewCreateRatePlanUseCase(h.RatePlanRepository).Execute(dto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeRatePlanJSON(w, http.StatusCreated, output)
+}
+
+// RatePlanHandler returns the usage of one plan (GET) or changes its requests per minute (PUT)
+func (h *WebRatePlanHandler) RatePlanHandler(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r, h.AdminToken, http.MethodGet, http.MethodPut) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	if r.Method == http.MethodPut {
+		var dto usecase.RatePlanInputDTO
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err := usecase.NewUpdateRatePlanUseCase(h.RatePlanRepository).Execute(id, dto.RequestsPerMinute)
+		if errors.Is(err, entity.ErrRatePlanNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	output, err := usecase.NewListRatePlansUseCase(h.RatePlanRepository, h.EnforceRatePlanUseCase).FindByID(id)
+	if errors.Is(err, entity.ErrRatePlanNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeRatePlanJSON(w, http.StatusOK, output)
+}
+
+func writeRatePlanJSON(w http.ResponseWriter, status int, output interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(output); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
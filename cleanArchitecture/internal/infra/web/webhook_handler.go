@@ -0,0 +1,99 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"cleanarch/internal/entity"
+	"cleanarch/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+type WebWebhookHandler struct {
+	WebhookSubscriptionRepository entity.WebhookSubscriptionRepositoryInterface
+	DispatchWebhooksUseCase       *usecase.DispatchWebhooksUseCase
+}
+
+func NewWebWebhookHandler(
+	WebhookSubscriptionRepository entity.WebhookSubscriptionRepositoryInterface,
+	DispatchWebhooksUseCase *usecase.DispatchWebhooksUseCase,
+) *WebWebhookHandler {
+	return &WebWebhookHandler{
+		WebhookSubscriptionRepository: WebhookSubscriptionRepository,
+		DispatchWebhooksUseCase:       DispatchWebhooksUseCase,
+	}
+}
+
+func (h *WebWebhookHandler) WebhookHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Create(w, r)
+	case http.MethodGet:
+		h.List(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *WebWebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var dto usecase.WebhookSubscriptionInputDTO
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	createSubscription := usecase.NewCreateWebhookSubscriptionUseCase(h.WebhookSubscriptionRepository)
+	output, err := createSubscription.Execute(dto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(output)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *WebWebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	listSubscriptions := usecase.NewListWebhookSubscriptionsUseCase(h.WebhookSubscriptionRepository)
+	output, err := listSubscriptions.Execute()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(output)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Test sends a sample event to one subscription and reports the receiver's answer
+func (h *WebWebhookHandler) Test(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	output, err := h.DispatchWebhooksUseCase.Test(chi.URLParam(r, "id"))
+	if errors.Is(err, entity.ErrWebhookSubscriptionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(output)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultTimeout = 5 * time.Second
+
+type HTTPSender struct {
+	Client *http.Client
+}
+
+func NewHTTPSender() *HTTPSender {
+	return &HTTPSender{
+		Client: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Send posts body as JSON to url. Any status outside 2xx is reported as an error
+// alongside the status code.
+func (s *HTTPSender) Send(url string, body []byte) (int, error) {
+	resp, err := s.Client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -1,10 +1,18 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+
 	"cleanarch/internal/entity"
 	"cleanarch/pkg/events"
+	"cleanarch/pkg/saga"
 )
 
+// ErrClientOrderIDNotAllowed is returned when a client sends an order id while
+// ids are generated by the server.
+var ErrClientOrderIDNotAllowed = errors.New("order id is generated by the server and must not be sent")
+
 type OrderInputDTO struct {
 	ID    string  `json:"id"`
 	Price float64 `json:"price"`
@@ -18,32 +26,55 @@ type OrderOutputDTO struct {
 	FinalPrice float64 `json:"final_price"`
 }
 
+// OrderIDPolicy decides where order ids come from. Without a Generator the
+// client-supplied id is used as is; with one, ids are generated and client ids
+// are only accepted when AllowClientID is set (compatibility mode).
+type OrderIDPolicy struct {
+	Generator     entity.OrderIDGeneratorInterface
+	AllowClientID bool
+}
+
 type CreateOrderUseCase struct {
 	OrderRepository entity.OrderRepositoryInterface
-	OrderCreated    events.EventInterface
+	OrderCreated    events.EventFactory
 	EventDispatcher events.EventDispatcherInterface
+	OrderIDPolicy   OrderIDPolicy
+	// PaymentGateway reserves the final price before the order is saved; nil skips the reservation
+	PaymentGateway entity.PaymentGatewayInterface
 }
 
 func NewCreateOrderUseCase(
 	OrderRepository entity.OrderRepositoryInterface,
-	OrderCreated events.EventInterface,
+	OrderCreated events.EventFactory,
 	EventDispatcher events.EventDispatcherInterface,
+	OrderIDPolicy OrderIDPolicy,
 ) *CreateOrderUseCase {
 	return &CreateOrderUseCase{
 		OrderRepository: OrderRepository,
 		OrderCreated:    OrderCreated,
 		EventDispatcher: EventDispatcher,
+		OrderIDPolicy:   OrderIDPolicy,
 	}
 }
 
 func (c *CreateOrderUseCase) Execute(input OrderInputDTO) (OrderOutputDTO, error) {
+	return c.ExecuteContext(context.Background(), input)
+}
+
+// ExecuteContext creates the order as a saga: reserve payment, persist the order
+// and publish OrderCreated. When a step fails the previous ones are undone
+// (order deleted, reservation cancelled) and a *saga.Error is returned.
+func (c *CreateOrderUseCase) ExecuteContext(ctx context.Context, input OrderInputDTO) (OrderOutputDTO, error) {
+	id, err := c.orderID(input.ID)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
 	order := entity.Order{
-		ID:    input.ID,
+		ID:    id,
 		Price: input.Price,
 		Tax:   input.Tax,
 	}
-	order.CalculateFinalPrice()
-	if err := c.OrderRepository.Save(&order); err != nil {
+	if err := order.CalculateFinalPrice(); err != nil {
 		return OrderOutputDTO{}, err
 	}
 
@@ -51,11 +82,64 @@ func (c *CreateOrderUseCase) Execute(input OrderInputDTO) (OrderOutputDTO, error
 		ID:         order.ID,
 		Price:      order.Price,
 		Tax:        order.Tax,
-		FinalPrice: order.Price + order.Tax,
+		FinalPrice: order.FinalPrice,
 	}
 
-	c.OrderCreated.SetPayload(dto)
-	c.EventDispatcher.Dispatch(c.OrderCreated)
+	var reservationID string
+	err = saga.NewOrchestrator().Run(ctx, "create-order",
+		saga.Step{
+			Name: "reserve-payment",
+			Action: func(ctx context.Context) error {
+				if c.PaymentGateway == nil {
+					return nil
+				}
+				id, err := c.PaymentGateway.Reserve(ctx, order.ID, order.FinalPrice)
+				reservationID = id
+				return err
+			},
+			Compensate: func(ctx context.Context) error {
+				if reservationID == "" {
+					return nil
+				}
+				return c.PaymentGateway.Cancel(ctx, reservationID)
+			},
+		},
+		saga.Step{
+			Name: "persist-order",
+			Action: func(ctx context.Context) error {
+				return c.OrderRepository.Save(&order)
+			},
+			Compensate: func(ctx context.Context) error {
+				return c.OrderRepository.Delete(order.ID)
+			},
+		},
+		saga.Step{
+			Name: "publish-event",
+			Action: func(ctx context.Context) error {
+				orderCreated := c.OrderCreated()
+				orderCreated.SetPayload(dto)
+				return c.EventDispatcher.Dispatch(orderCreated)
+			},
+		},
+	)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
 
 	return dto, nil
 }
+
+// orderID resolves the id of a new order according to the policy.
+func (c *CreateOrderUseCase) orderID(clientID string) (string, error) {
+	policy := c.OrderIDPolicy
+	if policy.Generator == nil {
+		return clientID, nil
+	}
+	if clientID != "" {
+		if !policy.AllowClientID {
+			return "", ErrClientOrderIDNotAllowed
+		}
+		return clientID, nil
+	}
+	return policy.Generator.Generate()
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"time"
+
+	"cleanarch/internal/entity"
+	"github.com/google/uuid"
+)
+
+type DeadLetterInputDTO struct {
+	Queue       string
+	Exchange    string
+	RoutingKey  string
+	Reason      string
+	DeathCount  int64
+	ContentType string
+	Body        []byte
+}
+
+type DeadLetterOutputDTO struct {
+	ID            string     `json:"id"`
+	Queue         string     `json:"queue"`
+	Exchange      string     `json:"exchange"`
+	RoutingKey    string     `json:"routing_key"`
+	Reason        string     `json:"reason"`
+	DeathCount    int64      `json:"death_count"`
+	ContentType   string     `json:"content_type"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	RedeliveredAt *time.Time `json:"redelivered_at,omitempty"`
+}
+
+func newDeadLetterOutputDTO(deadLetter *entity.DeadLetter) DeadLetterOutputDTO {
+	return DeadLetterOutputDTO{
+		ID:            deadLetter.ID,
+		Queue:         deadLetter.Queue,
+		Exchange:      deadLetter.Exchange,
+		RoutingKey:    deadLetter.RoutingKey,
+		Reason:        deadLetter.Reason,
+		DeathCount:    deadLetter.DeathCount,
+		ContentType:   deadLetter.ContentType,
+		Body:          string(deadLetter.Body),
+		Status:        deadLetter.Status,
+		CreatedAt:     deadLetter.CreatedAt,
+		RedeliveredAt: deadLetter.RedeliveredAt,
+	}
+}
+
+type RecordDeadLetterUseCase struct {
+	DeadLetterRepository entity.DeadLetterRepositoryInterface
+}
+
+func NewRecordDeadLetterUseCase(
+	DeadLetterRepository entity.DeadLetterRepositoryInterface,
+) *RecordDeadLetterUseCase {
+	return &RecordDeadLetterUseCase{
+		DeadLetterRepository: DeadLetterRepository,
+	}
+}
+
+func (u *RecordDeadLetterUseCase) Execute(input DeadLetterInputDTO) (DeadLetterOutputDTO, error) {
+	deadLetter, err := entity.NewDeadLetter(uuid.New().String(), input.Queue, input.Exchange, input.RoutingKey,
+		input.Reason, input.DeathCount, input.ContentType, input.Body)
+	if err != nil {
+		return DeadLetterOutputDTO{}, err
+	}
+	if err := u.DeadLetterRepository.Save(deadLetter); err != nil {
+		return DeadLetterOutputDTO{}, err
+	}
+	return newDeadLetterOutputDTO(deadLetter), nil
+}
+
+type ListDeadLettersUseCase struct {
+	DeadLetterRepository entity.DeadLetterRepositoryInterface
+}
+
+func NewListDeadLettersUseCase(
+	DeadLetterRepository entity.DeadLetterRepositoryInterface,
+) *ListDeadLettersUseCase {
+	return &ListDeadLettersUseCase{
+		DeadLetterRepository: DeadLetterRepository,
+	}
+}
+
+func (u *ListDeadLettersUseCase) Execute(status string) ([]DeadLetterOutputDTO, error) {
+	deadLetters, err := u.DeadLetterRepository.FindAll(status)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]DeadLetterOutputDTO, 0, len(deadLetters))
+	for i := range deadLetters {
+		output = append(output, newDeadLetterOutputDTO(&deadLetters[i]))
+	}
+	return output, nil
+}
+
+// FindByID returns a single dead letter for inspection
+func (u *ListDeadLettersUseCase) FindByID(id string) (DeadLetterOutputDTO, error) {
+	deadLetter, err := u.DeadLetterRepository.FindByID(id)
+	if err != nil {
+		return DeadLetterOutputDTO{}, err
+	}
+	return newDeadLetterOutputDTO(deadLetter), nil
+}
+
+type RedeliverDeadLetterUseCase struct {
+	DeadLetterRepository entity.DeadLetterRepositoryInterface
+	Publisher            entity.MessagePublisherInterface
+}
+
+func NewRedeliverDeadLetterUseCase(
+	DeadLetterRepository entity.DeadLetterRepositoryInterface,
+	Publisher entity.MessagePublisherInterface,
+) *RedeliverDeadLetterUseCase {
+	return &RedeliverDeadLetterUseCase{
+		DeadLetterRepository: DeadLetterRepository,
+		Publisher:            Publisher,
+	}
+}
+
+// Execute publishes the message again to the exchange and routing key it was
+// originally sent to. The message is published before it is marked, so a crash
+// in between can deliver it twice; consumers of OrderCreated must be idempotent.
+func (u *RedeliverDeadLetterUseCase) Execute(id string) (DeadLetterOutputDTO, error) {
+	deadLetter, err := u.DeadLetterRepository.FindByID(id)
+	if err != nil {
+		return DeadLetterOutputDTO{}, err
+	}
+	if deadLetter.Status == entity.DeadLetterRedelivered {
+		return DeadLetterOutputDTO{}, entity.ErrDeadLetterAlreadyRedelivered
+	}
+
+	if err := u.Publisher.Publish(deadLetter.Exchange, deadLetter.RoutingKey, deadLetter.ContentType, deadLetter.Body); err != nil {
+		return DeadLetterOutputDTO{}, err
+	}
+
+	now := time.Now()
+	if err := u.DeadLetterRepository.MarkRedelivered(deadLetter.ID, now); err != nil {
+		return DeadLetterOutputDTO{}, err
+	}
+	deadLetter.MarkRedelivered(now)
+	return newDeadLetterOutputDTO(deadLetter), nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"cleanarch/internal/entity"
+)
+
+// WebhookTestEvent is the event name sent by the test-delivery endpoint
+const WebhookTestEvent = "WebhookTest"
+
+type WebhookDeliveryDTO struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Payload    interface{} `json:"payload"`
+}
+
+type WebhookTestOutputDTO struct {
+	SubscriptionID string `json:"subscription_id"`
+	StatusCode     int    `json:"status_code"`
+	Error          string `json:"error,omitempty"`
+}
+
+type DispatchWebhooksUseCase struct {
+	WebhookSubscriptionRepository entity.WebhookSubscriptionRepositoryInterface
+	WebhookSender                 entity.WebhookSenderInterface
+}
+
+func NewDispatchWebhooksUseCase(
+	WebhookSubscriptionRepository entity.WebhookSubscriptionRepositoryInterface,
+	WebhookSender entity.WebhookSenderInterface,
+) *DispatchWebhooksUseCase {
+	return &DispatchWebhooksUseCase{
+		WebhookSubscriptionRepository: WebhookSubscriptionRepository,
+		WebhookSender:                 WebhookSender,
+	}
+}
+
+// Execute delivers the event to every subscription whose event types and
+// filters match the order in the payload, evaluated against the subscriptions
+// as they are stored right now. Deliveries run concurrently and Execute waits
+// for all of them to be recorded.
+func (u *DispatchWebhooksUseCase) Execute(eventName string, payload interface{}, occurredAt time.Time) error {
+	subscriptions, err := u.WebhookSubscriptionRepository.FindAll()
+	if err != nil {
+		return err
+	}
+
+	order := orderFromPayload(payload)
+	body, err := json.Marshal(WebhookDeliveryDTO{Event: eventName, OccurredAt: occurredAt, Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i := range subscriptions {
+		subscription := subscriptions[i]
+		if !subscription.Matches(eventName, order) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.deliver(&subscription, body)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// Test sends a sample order to a single subscription, ignoring its filters,
+// and reports the outcome. The attempt counts towards the delivery stats.
+func (u *DispatchWebhooksUseCase) Test(subscriptionID string) (WebhookTestOutputDTO, error) {
+	subscription, err := u.WebhookSubscriptionRepository.FindByID(subscriptionID)
+	if err != nil {
+		return WebhookTestOutputDTO{}, err
+	}
+
+	body, err := json.Marshal(WebhookDeliveryDTO{
+		Event:      WebhookTestEvent,
+		OccurredAt: time.Now(),
+		Payload:    OrderOutputDTO{ID: "test-order", Price: 100, Tax: 10, FinalPrice: 110},
+	})
+	if err != nil {
+		return WebhookTestOutputDTO{}, err
+	}
+
+	statusCode, deliveryErr := u.deliver(subscription, body)
+	output := WebhookTestOutputDTO{SubscriptionID: subscription.ID, StatusCode: statusCode}
+	if deliveryErr != nil {
+		output.Error = deliveryErr.Error()
+	}
+	return output, nil
+}
+
+func (u *DispatchWebhooksUseCase) deliver(subscription *entity.WebhookSubscription, body []byte) (int, error) {
+	statusCode, deliveryErr := u.WebhookSender.Send(subscription.URL, body)
+	if err := u.WebhookSubscriptionRepository.RecordDelivery(subscription.ID, statusCode, deliveryErr, time.Now()); err != nil {
+		log.Printf("failed to record webhook delivery for %s: %v", subscription.ID, err)
+	}
+	return statusCode, deliveryErr
+}
+
+func orderFromPayload(payload interface{}) entity.Order {
+	switch p := payload.(type) {
+	case OrderOutputDTO:
+		return entity.Order{ID: p.ID, Price: p.Price, Tax: p.Tax, FinalPrice: p.FinalPrice}
+	case *OrderOutputDTO:
+		return entity.Order{ID: p.ID, Price: p.Price, Tax: p.Tax, FinalPrice: p.FinalPrice}
+	}
+	return entity.Order{}
+}
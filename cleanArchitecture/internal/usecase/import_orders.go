@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cleanarch/internal/entity"
+	"github.com/google/uuid"
+)
+
+const importQueueSize = 100
+
+var ErrImportQueueFull = errors.New("import queue is full, try again later")
+
+type ImportOrdersInputDTO struct {
+	Orders []OrderInputDTO `json:"orders"`
+	Async  bool            `json:"async"`
+}
+
+type ImportJobOutputDTO struct {
+	ID        string   `json:"id"`
+	Status    string   `json:"status"`
+	Total     int      `json:"total"`
+	Processed int      `json:"processed"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors"`
+}
+
+type importRequest struct {
+	job    *entity.ImportJob
+	orders []OrderInputDTO
+}
+
+type ImportOrdersUseCase struct {
+	ImportJobRepository entity.ImportJobRepositoryInterface
+	CreateOrderUseCase  CreateOrderUseCase
+	queue               chan importRequest
+}
+
+func NewImportOrdersUseCase(
+	ImportJobRepository entity.ImportJobRepositoryInterface,
+	CreateOrderUseCase CreateOrderUseCase,
+) *ImportOrdersUseCase {
+	return &ImportOrdersUseCase{
+		ImportJobRepository: ImportJobRepository,
+		CreateOrderUseCase:  CreateOrderUseCase,
+		queue:               make(chan importRequest, importQueueSize),
+	}
+}
+
+// Execute registers an import job. In async mode the orders are handed to the
+// background worker and the pending job is returned right away; otherwise the
+// import runs inline and the finished job is returned.
+func (u *ImportOrdersUseCase) Execute(input ImportOrdersInputDTO) (ImportJobOutputDTO, error) {
+	job, err := entity.NewImportJob(uuid.New().String(), len(input.Orders))
+	if err != nil {
+		return ImportJobOutputDTO{}, err
+	}
+	if err := u.ImportJobRepository.Save(job); err != nil {
+		return ImportJobOutputDTO{}, err
+	}
+
+	if !input.Async {
+		u.process(importRequest{job: job, orders: input.Orders})
+		return newImportJobOutputDTO(job), nil
+	}
+
+	select {
+	case u.queue <- importRequest{job: job, orders: input.Orders}:
+	default:
+		job.RecordFailure(ErrImportQueueFull.Error())
+		job.Status = entity.ImportJobFailed
+		u.ImportJobRepository.Update(job)
+		return ImportJobOutputDTO{}, ErrImportQueueFull
+	}
+
+	return newImportJobOutputDTO(job), nil
+}
+
+// StartWorker consumes queued import jobs until ctx is cancelled.
+func (u *ImportOrdersUseCase) StartWorker(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case request := <-u.queue:
+				u.process(request)
+			}
+		}
+	}()
+}
+
+func (u *ImportOrdersUseCase) process(request importRequest) {
+	job := request.job
+	job.Start()
+	u.updateProgress(job)
+
+	// Rows are validated by CreateOrderUseCase once the id is resolved, and
+	// errors are keyed by row since generated ids are unknown to the client
+	for i, input := range request.orders {
+		if _, err := u.CreateOrderUseCase.Execute(input); err != nil {
+			job.RecordFailure(fmt.Sprintf("orders[%d]: %s", i, err))
+			u.updateProgress(job)
+			continue
+		}
+		job.RecordSuccess()
+		u.updateProgress(job)
+	}
+
+	job.Finish()
+	u.updateProgress(job)
+}
+
+func (u *ImportOrdersUseCase) updateProgress(job *entity.ImportJob) {
+	if err := u.ImportJobRepository.Update(job); err != nil {
+		fmt.Printf("Error updating import job %s: %v\n", job.ID, err)
+	}
+}
+
+func newImportJobOutputDTO(job *entity.ImportJob) ImportJobOutputDTO {
+	return ImportJobOutputDTO{
+		ID:        job.ID,
+		Status:    job.Status,
+		Total:     job.Total,
+		Processed: job.Processed,
+		Failed:    job.Failed,
+		Errors:    job.Errors,
+	}
+}
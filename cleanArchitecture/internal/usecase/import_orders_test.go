@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cleanarch/internal/entity"
+	"cleanarch/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryOrderRepository struct {
+	orders []entity.Order
+}
+
+func (r *memoryOrderRepository) Save(order *entity.Order) error {
+	r.orders = append(r.orders, *order)
+	return nil
+}
+
+func (r *memoryOrderRepository) FindAll() ([]entity.Order, error) {
+	return r.orders, nil
+}
+
+func (r *memoryOrderRepository) Delete(id string) error {
+	return nil
+}
+
+type memoryImportJobRepository struct{}
+
+func (r *memoryImportJobRepository) Save(job *entity.ImportJob) error   { return nil }
+func (r *memoryImportJobRepository) Update(job *entity.ImportJob) error { return nil }
+func (r *memoryImportJobRepository) FindByID(id string) (*entity.ImportJob, error) {
+	return nil, errors.New("not found")
+}
+
+type fixedIDGenerator struct {
+	id string
+}
+
+func (g fixedIDGenerator) Generate() (string, error) {
+	return g.id, nil
+}
+
+type testEvent struct {
+	payload interface{}
+}
+
+func (e *testEvent) GetName() string                { return "OrderCreated" }
+func (e *testEvent) GetPayload() interface{}        { return e.payload }
+func (e *testEvent) SetPayload(payload interface{}) { e.payload = payload }
+func (e *testEvent) GetDateTime() time.Time         { return time.Now() }
+
+func newTestImportOrdersUseCase(orders *memoryOrderRepository, policy OrderIDPolicy) *ImportOrdersUseCase {
+	createOrder := NewCreateOrderUseCase(orders, func() events.EventInterface { return &testEvent{} }, events.NewEventDispatcher(), policy)
+	return NewImportOrdersUseCase(&memoryImportJobRepository{}, *createOrder)
+}
+
+func TestGivenAnIDGenerator_WhenProcess_ThenShouldImportOrdersWithoutIDs(t *testing.T) {
+	orders := &memoryOrderRepository{}
+	importOrders := newTestImportOrdersUseCase(orders, OrderIDPolicy{Generator: fixedIDGenerator{id: "generated"}})
+	job, err := entity.NewImportJob("job", 3)
+	require.NoError(t, err)
+
+	importOrders.process(importRequest{job: job, orders: []OrderInputDTO{
+		{Price: 100.0, Tax: 10.0},
+		{ID: "client-id", Price: 100.0, Tax: 10.0},
+		{Price: 0, Tax: 10.0},
+	}})
+
+	assert.Equal(t, entity.ImportJobCompleted, job.Status)
+	assert.Equal(t, 3, job.Processed)
+	assert.Equal(t, 2, job.Failed)
+	assert.Equal(t, []string{
+		"orders[1]: " + ErrClientOrderIDNotAllowed.Error(),
+		"orders[2]: invalid price",
+	}, job.Errors)
+	require.Len(t, orders.orders, 1)
+	assert.Equal(t, "generated", orders.orders[0].ID)
+	assert.Equal(t, 110.0, orders.orders[0].FinalPrice)
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import "cleanarch/internal/entity"
+
+type ImportStatusUseCase struct {
+	ImportJobRepository entity.ImportJobRepositoryInterface
+}
+
+func NewImportStatusUseCase(importJobRepository entity.ImportJobRepositoryInterface) *ImportStatusUseCase {
+	return &ImportStatusUseCase{
+		ImportJobRepository: importJobRepository,
+	}
+}
+
+func (u *ImportStatusUseCase) Execute(jobID string) (ImportJobOutputDTO, error) {
+	job, err := u.ImportJobRepository.FindByID(jobID)
+	if err != nil {
+		return ImportJobOutputDTO{}, err
+	}
+
+	return newImportJobOutputDTO(job), nil
+}
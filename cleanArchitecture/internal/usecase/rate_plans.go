@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"cleanarch/internal/entity"
+	"github.com/google/uuid"
+)
+
+var ErrMissingAPIKey = errors.New("missing api key")
+
+type RatePlanInputDTO struct {
+	Name string `json:"name"`
+	// APIKey is generated when left empty
+	APIKey            string `json:"api_key"`
+	RequestsPerMinute int    `json:"requests_per_minute"`
+}
+
+type RatePlanUsageDTO struct {
+	WindowStart time.Time `json:"window_start"`
+	Used        int       `json:"used"`
+	Remaining   int       `json:"remaining"`
+	// Allowed and Rejected count requests since the server started
+	Allowed  int64 `json:"allowed"`
+	Rejected int64 `json:"rejected"`
+}
+
+type RatePlanOutputDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// APIKey is only returned when the plan is created
+	APIKey            string           `json:"api_key,omitempty"`
+	RequestsPerMinute int              `json:"requests_per_minute"`
+	CreatedAt         time.Time        `json:"created_at"`
+	Usage             RatePlanUsageDTO `json:"usage"`
+}
+
+// RateDecisionDTO is the outcome of checking one request against its plan
+type RateDecisionDTO struct {
+	PlanID     string
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// rateCounter counts the requests of one plan in fixed one-minute windows
+type rateCounter struct {
+	windowStart time.Time
+	used        int
+	allowed     int64
+	rejected    int64
+}
+
+// EnforceRatePlanUseCase checks requests against the plan of their API key.
+// A single instance is shared by the web and gRPC servers so both count
+// towards the same limit.
+type EnforceRatePlanUseCase struct {
+	RatePlanRepository entity.RatePlanRepositoryInterface
+	Now                func() time.Time
+
+	mu       sync.Mutex
+	counters map[string]*rateCounter
+}
+
+func NewEnforceRatePlanUseCase(
+	RatePlanRepository entity.RatePlanRepositoryInterface,
+) *EnforceRatePlanUseCase {
+	return &EnforceRatePlanUseCase{
+		RatePlanRepository: RatePlanRepository,
+		Now:                time.Now,
+		counters:           make(map[string]*rateCounter),
+	}
+}
+
+// Execute counts one request for apiKey. It fails with ErrMissingAPIKey,
+// entity.ErrRatePlanNotFound for unknown keys or entity.ErrRateLimitExceeded,
+// in which case the decision carries how long to wait.
+func (u *EnforceRatePlanUseCase) Execute(apiKey string) (RateDecisionDTO, error) {
+	if apiKey == "" {
+		return RateDecisionDTO{}, ErrMissingAPIKey
+	}
+	plan, err := u.RatePlanRepository.FindByAPIKeyHash(entity.HashAPIKey(apiKey))
+	if err != nil {
+		return RateDecisionDTO{}, err
+	}
+
+	now := u.Now()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	counter := u.counter(plan.ID, now)
+	decision := RateDecisionDTO{PlanID: plan.ID, Limit: plan.RequestsPerMinute}
+	if counter.used >= plan.RequestsPerMinute {
+		counter.rejected++
+		decision.RetryAfter = counter.windowStart.Add(time.Minute).Sub(now)
+		return decision, entity.ErrRateLimitExceeded
+	}
+	counter.used++
+	counter.allowed++
+	decision.Remaining = plan.RequestsPerMinute - counter.used
+	return decision, nil
+}
+
+// Usage reports the current window and the totals of a plan
+func (u *EnforceRatePlanUseCase) Usage(plan *entity.RatePlan) RatePlanUsageDTO {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	counter := u.counter(plan.ID, u.Now())
+	remaining := plan.RequestsPerMinute - counter.used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return RatePlanUsageDTO{
+		WindowStart: counter.windowStart,
+		Used:        counter.used,
+		Remaining:   remaining,
+		Allowed:     counter.allowed,
+		Rejected:    counter.rejected,
+	}
+}
+
+// counter returns the counter of a plan, starting a new window when the minute changed.
+// Callers must hold u.mu.
+func (u *EnforceRatePlanUseCase) counter(planID string, now time.Time) *rateCounter {
+	window := now.Truncate(time.Minute)
+	counter, ok := u.counters[planID]
+	if !ok {
+		counter = &rateCounter{windowStart: window}
+		u.counters[planID] = counter
+	}
+	if counter.windowStart.Before(window) {
+		counter.windowStart = window
+		counter.used = 0
+	}
+	return counter
+}
+
+type CreateRatePlanUseCase struct {
+	RatePlanRepository entity.RatePlanRepositoryInterface
+}
+
+func NewCreateRatePlanUseCase(
+	RatePlanRepository entity.RatePlanRepositoryInterface,
+) *CreateRatePlanUseCase {
+	return &CreateRatePlanUseCase{
+		RatePlanRepository: RatePlanRepository,
+	}
+}
+
+func (u *CreateRatePlanUseCase) Execute(input RatePlanInputDTO) (RatePlanOutputDTO, error) {
+	apiKey := input.APIKey
+	if apiKey == "" {
+		generated, err := generateAPIKey()
+		if err != nil {
+			return RatePlanOutputDTO{}, err
+		}
+		apiKey = generated
+	}
+	plan, err := entity.NewRatePlan(uuid.New().String(), input.Name, apiKey, input.RequestsPerMinute)
+	if err != nil {
+		return RatePlanOutputDTO{}, err
+	}
+	if err := u.RatePlanRepository.Save(plan); err != nil {
+		return RatePlanOutputDTO{}, err
+	}
+	return RatePlanOutputDTO{
+		ID:                plan.ID,
+		Name:              plan.Name,
+		APIKey:            apiKey,
+		RequestsPerMinute: plan.RequestsPerMinute,
+		CreatedAt:         plan.CreatedAt,
+		Usage:             RatePlanUsageDTO{Remaining: plan.RequestsPerMinute},
+	}, nil
+}
+
+func generateAPIKey() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+type UpdateRatePlanUseCase struct {
+	RatePlanRepository entity.RatePlanRepositoryInterface
+}
+
+func NewUpdateRatePlanUseCase(
+	RatePlanRepository entity.RatePlanRepositoryInterface,
+) *UpdateRatePlanUseCase {
+	return &UpdateRatePlanUseCase{
+		RatePlanRepository: RatePlanRepository,
+	}
+}
+
+// Execute changes the requests per minute of a plan; it applies from the next request on
+func (u *UpdateRatePlanUseCase) Execute(id string, requestsPerMinute int) error {
+	if requestsPerMinute <= 0 {
+		return errors.New("invalid requests per minute")
+	}
+	return u.RatePlanRepository.UpdateRequestsPerMinute(id, requestsPerMinute)
+}
+
+type ListRatePlansUseCase struct {
+	RatePlanRepository     entity.RatePlanRepositoryInterface
+	EnforceRatePlanUseCase *EnforceRatePlanUseCase
+}
+
+func NewListRatePlansUseCase(
+	RatePlanRepository entity.RatePlanRepositoryInterface,
+	EnforceRatePlanUseCase *EnforceRatePlanUseCase,
+) *ListRatePlansUseCase {
+	return &ListRatePlansUseCase{
+		RatePlanRepository:     RatePlanRepository,
+		EnforceRatePlanUseCase: EnforceRatePlanUseCase,
+	}
+}
+
+func (u *ListRatePlansUseCase) Execute() ([]RatePlanOutputDTO, error) {
+	plans, err := u.RatePlanRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]RatePlanOutputDTO, 0, len(plans))
+	for i := range plans {
+		output = append(output, u.newRatePlanOutputDTO(&plans[i]))
+	}
+	return output, nil
+}
+
+func (u *ListRatePlansUseCase) FindByID(id string) (RatePlanOutputDTO, error) {
+	plan, err := u.RatePlanRepository.FindByID(id)
+	if err != nil {
+		return RatePlanOutputDTO{}, err
+	}
+	return u.newRatePlanOutputDTO(plan), nil
+}
+
+func (u *ListRatePlansUseCase) newRatePlanOutputDTO(plan *entity.RatePlan) RatePlanOutputDTO {
+	return RatePlanOutputDTO{
+		ID:                plan.ID,
+		Name:              plan.Name,
+		RequestsPerMinute: plan.RequestsPerMinute,
+		CreatedAt:         plan.CreatedAt,
+		Usage:             u.EnforceRatePlanUseCase.Usage(plan),
+	}
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"time"
+
+	"cleanarch/internal/entity"
+	"github.com/google/uuid"
+)
+
+type WebhookSubscriptionInputDTO struct {
+	ID         string                 `json:"id"`
+	URL        string                 `json:"url"`
+	EventTypes []string               `json:"event_types"`
+	Filters    []entity.WebhookFilter `json:"filters"`
+}
+
+type WebhookSubscriptionOutputDTO struct {
+	ID             string                 `json:"id"`
+	URL            string                 `json:"url"`
+	EventTypes     []string               `json:"event_types"`
+	Filters        []entity.WebhookFilter `json:"filters"`
+	Delivered      int                    `json:"delivered"`
+	Failed         int                    `json:"failed"`
+	LastStatusCode int                    `json:"last_status_code,omitempty"`
+	LastError      string                 `json:"last_error,omitempty"`
+	LastDeliveryAt *time.Time             `json:"last_delivery_at,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+func newWebhookSubscriptionOutputDTO(subscription *entity.WebhookSubscription) WebhookSubscriptionOutputDTO {
+	return WebhookSubscriptionOutputDTO{
+		ID:             subscription.ID,
+		URL:            subscription.URL,
+		EventTypes:     subscription.EventTypes,
+		Filters:        subscription.Filters,
+		Delivered:      subscription.Delivered,
+		Failed:         subscription.Failed,
+		LastStatusCode: subscription.LastStatusCode,
+		LastError:      subscription.LastError,
+		LastDeliveryAt: subscription.LastDeliveryAt,
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
+
+type CreateWebhookSubscriptionUseCase struct {
+	WebhookSubscriptionRepository entity.WebhookSubscriptionRepositoryInterface
+}
+
+func NewCreateWebhookSubscriptionUseCase(
+	WebhookSubscriptionRepository entity.WebhookSubscriptionRepositoryInterface,
+) *CreateWebhookSubscriptionUseCase {
+	return &CreateWebhookSubscriptionUseCase{
+		WebhookSubscriptionRepository: WebhookSubscriptionRepository,
+	}
+}
+
+func (u *CreateWebhookSubscriptionUseCase) Execute(input WebhookSubscriptionInputDTO) (WebhookSubscriptionOutputDTO, error) {
+	id := input.ID
+	if id == "" {
+		id = uuid.New().String()
+	}
+	subscription, err := entity.NewWebhookSubscription(id, input.URL, input.EventTypes, input.Filters)
+	if err != nil {
+		return WebhookSubscriptionOutputDTO{}, err
+	}
+	if err := u.WebhookSubscriptionRepository.Save(subscription); err != nil {
+		return WebhookSubscriptionOutputDTO{}, err
+	}
+	return newWebhookSubscriptionOutputDTO(subscription), nil
+}
+
+type ListWebhookSubscriptionsUseCase struct {
+	WebhookSubscriptionRepository entity.WebhookSubscriptionRepositoryInterface
+}
+
+func NewListWebhookSubscriptionsUseCase(
+	WebhookSubscriptionRepository entity.WebhookSubscriptionRepositoryInterface,
+) *ListWebhookSubscriptionsUseCase {
+	return &ListWebhookSubscriptionsUseCase{
+		WebhookSubscriptionRepository: WebhookSubscriptionRepository,
+	}
+}
+
+func (u *ListWebhookSubscriptionsUseCase) Execute() ([]WebhookSubscriptionOutputDTO, error) {
+	subscriptions, err := u.WebhookSubscriptionRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]WebhookSubscriptionOutputDTO, 0, len(subscriptions))
+	for i := range subscriptions {
+		output = append(output, newWebhookSubscriptionOutputDTO(&subscriptions[i]))
+	}
+	return output, nil
+}
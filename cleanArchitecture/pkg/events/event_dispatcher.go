@@ -17,16 +17,30 @@ func NewEventDispatcher() *EventDispatcher {
 	}
 }
 
+// Dispatch runs every handler of the event concurrently and returns the
+// errors of those that failed, joined
 func (ev *EventDispatcher) Dispatch(event EventInterface) error {
-	if handlers, ok := ev.handlers[event.GetName()]; ok {
-		wg := &sync.WaitGroup{}
-		for _, handler := range handlers {
-			wg.Add(1)
-			go handler.Handle(event, wg)
+	handlers, ok := ev.handlers[event.GetName()]
+	if !ok {
+		return nil
+	}
+	wg := &sync.WaitGroup{}
+	results := make(chan error, len(handlers))
+	for _, handler := range handlers {
+		wg.Add(1)
+		go func(handler EventHandlerInterface) {
+			results <- handler.Handle(event, wg)
+		}(handler)
+	}
+	wg.Wait()
+
+	var errs []error
+	for range handlers {
+		if err := <-results; err != nil {
+			errs = append(errs, err)
 		}
-		wg.Wait()
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 func (ed *EventDispatcher) Register(eventName string, handler EventHandlerInterface) error {
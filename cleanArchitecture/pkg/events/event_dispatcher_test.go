@@ -1,6 +1,7 @@
 package events
 
 import (
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -35,7 +36,8 @@ type TestEventHandler struct {
 	ID int
 }
 
-func (h *TestEventHandler) Handle(event EventInterface, wg *sync.WaitGroup) {
+func (h *TestEventHandler) Handle(event EventInterface, wg *sync.WaitGroup) error {
+	return nil
 }
 
 type EventDispatcherTestSuite struct {
@@ -150,28 +152,45 @@ type MockHandler struct {
 	mock.Mock
 }
 
-func (m *MockHandler) Handle(event EventInterface, wg *sync.WaitGroup) {
-	m.Called(event)
-	wg.Done()
+func (m *MockHandler) Handle(event EventInterface, wg *sync.WaitGroup) error {
+	defer wg.Done()
+	return m.Called(event).Error(0)
 }
 
 func (suite *EventDispatcherTestSuite) TestEventDispatch_Dispatch() {
 	eh := &MockHandler{}
-	eh.On("Handle", &suite.event)
+	eh.On("Handle", &suite.event).Return(nil)
 
 	eh2 := &MockHandler{}
-	eh2.On("Handle", &suite.event)
+	eh2.On("Handle", &suite.event).Return(nil)
 
 	suite.eventDispatcher.Register(suite.event.GetName(), eh)
 	suite.eventDispatcher.Register(suite.event.GetName(), eh2)
 
-	suite.eventDispatcher.Dispatch(&suite.event)
+	err := suite.eventDispatcher.Dispatch(&suite.event)
+	suite.Nil(err)
 	eh.AssertExpectations(suite.T())
 	eh2.AssertExpectations(suite.T())
 	eh.AssertNumberOfCalls(suite.T(), "Handle", 1)
 	eh2.AssertNumberOfCalls(suite.T(), "Handle", 1)
 }
 
+func (suite *EventDispatcherTestSuite) TestEventDispatch_Dispatch_ReturnsHandlerErrors() {
+	failure := errors.New("broker unavailable")
+	eh := &MockHandler{}
+	eh.On("Handle", &suite.event).Return(failure)
+
+	eh2 := &MockHandler{}
+	eh2.On("Handle", &suite.event).Return(nil)
+
+	suite.eventDispatcher.Register(suite.event.GetName(), eh)
+	suite.eventDispatcher.Register(suite.event.GetName(), eh2)
+
+	err := suite.eventDispatcher.Dispatch(&suite.event)
+	suite.ErrorIs(err, failure)
+	eh2.AssertNumberOfCalls(suite.T(), "Handle", 1)
+}
+
 func TestSuite(t *testing.T) {
 	suite.Run(t, new(EventDispatcherTestSuite))
 }
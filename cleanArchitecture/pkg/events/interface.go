@@ -12,8 +12,11 @@ type EventInterface interface {
 	SetPayload(payload interface{})
 }
 
+// EventFactory builds a new event, so concurrent publishers never share one
+type EventFactory func() EventInterface
+
 type EventHandlerInterface interface {
-	Handle(event EventInterface, wg *sync.WaitGroup)
+	Handle(event EventInterface, wg *sync.WaitGroup) error
 }
 
 type EventDispatcherInterface interface {
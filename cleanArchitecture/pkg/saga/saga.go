@@ -0,0 +1,111 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "cleanarch/pkg/saga"
+
+// Step is one local transaction of a saga. Compensate undoes Action and is
+// only called when a later step fails; it may be nil for steps with nothing to undo.
+type Step struct {
+	Name       string
+	Action     func(ctx context.Context) error
+	Compensate func(ctx context.Context) error
+}
+
+// Error reports the step that failed and any compensation that could not be completed.
+type Error struct {
+	Saga          string
+	Step          string
+	Err           error
+	Compensations []error
+}
+
+func (e *Error) Error() string {
+	if len(e.Compensations) > 0 {
+		return fmt.Sprintf("saga %s failed at %s: %v (compensation failed: %v)",
+			e.Saga, e.Step, e.Err, errors.Join(e.Compensations...))
+	}
+	return fmt.Sprintf("saga %s failed at %s: %v", e.Saga, e.Step, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Compensated reports whether every completed step was undone.
+func (e *Error) Compensated() bool {
+	return len(e.Compensations) == 0
+}
+
+type Orchestrator struct {
+	tracer trace.Tracer
+}
+
+func NewOrchestrator() *Orchestrator {
+	return &Orchestrator{tracer: otel.Tracer(tracerName)}
+}
+
+// Run executes the steps in order. When a step fails, the steps already
+// completed are compensated in reverse order and an *Error is returned.
+// The saga and each step get their own span.
+func (o *Orchestrator) Run(ctx context.Context, name string, steps ...Step) error {
+	ctx, span := o.tracer.Start(ctx, "saga "+name, trace.WithAttributes(
+		attribute.String("saga.name", name),
+		attribute.Int("saga.steps", len(steps)),
+	))
+	defer span.End()
+
+	for i, step := range steps {
+		if err := o.runStep(ctx, "saga.step", step.Name, step.Action); err != nil {
+			sagaErr := &Error{Saga: name, Step: step.Name, Err: err}
+			sagaErr.Compensations = o.compensate(ctx, steps[:i])
+
+			span.SetAttributes(
+				attribute.String("saga.failed_step", step.Name),
+				attribute.Bool("saga.compensated", sagaErr.Compensated()),
+			)
+			span.SetStatus(codes.Error, sagaErr.Error())
+			return sagaErr
+		}
+	}
+	return nil
+}
+
+func (o *Orchestrator) compensate(ctx context.Context, completed []Step) []error {
+	// Compensations must run even if the caller gave up on the request
+	ctx = context.WithoutCancel(ctx)
+	var errs []error
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.Compensate == nil {
+			continue
+		}
+		if err := o.runStep(ctx, "saga.compensate", step.Name, step.Compensate); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, err))
+		}
+	}
+	return errs
+}
+
+func (o *Orchestrator) runStep(ctx context.Context, kind, name string, fn func(ctx context.Context) error) error {
+	ctx, span := o.tracer.Start(ctx, kind+" "+name, trace.WithAttributes(
+		attribute.String("saga.step", name),
+	))
+	defer span.End()
+
+	if err := fn(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordingStep(name string, calls *[]string, actionErr, compensateErr error) Step {
+	return Step{
+		Name: name,
+		Action: func(ctx context.Context) error {
+			*calls = append(*calls, name)
+			return actionErr
+		},
+		Compensate: func(ctx context.Context) error {
+			*calls = append(*calls, "undo "+name)
+			return compensateErr
+		},
+	}
+}
+
+func TestGivenAllStepsSucceed_WhenRun_ThenShouldNotCompensate(t *testing.T) {
+	var calls []string
+	err := NewOrchestrator().Run(context.Background(), "test",
+		recordingStep("a", &calls, nil, nil),
+		recordingStep("b", &calls, nil, nil),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, calls)
+}
+
+func TestGivenAFailingStep_WhenRun_ThenShouldCompensateCompletedStepsInReverse(t *testing.T) {
+	failure := errors.New("boom")
+	var calls []string
+	err := NewOrchestrator().Run(context.Background(), "test",
+		recordingStep("a", &calls, nil, nil),
+		recordingStep("b", &calls, nil, nil),
+		recordingStep("c", &calls, failure, nil),
+	)
+
+	var sagaErr *Error
+	assert.ErrorAs(t, err, &sagaErr)
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, "c", sagaErr.Step)
+	assert.True(t, sagaErr.Compensated())
+	assert.Equal(t, []string{"a", "b", "c", "undo b", "undo a"}, calls)
+}
+
+func TestGivenAFailingCompensation_WhenRun_ThenShouldKeepCompensatingAndReportIt(t *testing.T) {
+	var calls []string
+	steps := []Step{
+		recordingStep("a", &calls, nil, nil),
+		recordingStep("b", &calls, nil, errors.New("undo failed")),
+		{Name: "no-undo", Action: func(ctx context.Context) error { return nil }},
+		recordingStep("c", &calls, errors.New("boom"), nil),
+	}
+	err := NewOrchestrator().Run(context.Background(), "test", steps...)
+
+	var sagaErr *Error
+	assert.ErrorAs(t, err, &sagaErr)
+	assert.False(t, sagaErr.Compensated())
+	assert.Len(t, sagaErr.Compensations, 1)
+	assert.Equal(t, []string{"a", "b", "c", "undo b", "undo a"}, calls)
+}
+
+func TestGivenATracerProvider_WhenRun_ThenShouldRecordSagaAndStepSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	var calls []string
+	NewOrchestrator().Run(context.Background(), "create-order",
+		recordingStep("a", &calls, nil, nil),
+		recordingStep("b", &calls, errors.New("boom"), nil),
+	)
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	assert.Equal(t, []string{"saga.step a", "saga.step b", "saga.compensate a", "saga create-order"}, names)
+}
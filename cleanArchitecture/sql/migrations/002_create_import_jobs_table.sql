@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id VARCHAR(255) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL,
+    total INT NOT NULL,
+    processed INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    errors TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
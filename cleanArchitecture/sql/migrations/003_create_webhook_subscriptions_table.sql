@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id VARCHAR(255) PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    event_types TEXT NOT NULL,
+    filters TEXT NOT NULL,
+    delivered INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL,
+    last_delivery_at DATETIME NULL,
+    created_at DATETIME NOT NULL
+);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS order_sequence (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    stub CHAR(1) NOT NULL UNIQUE
+);
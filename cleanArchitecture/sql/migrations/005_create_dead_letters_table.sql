@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id VARCHAR(255) PRIMARY KEY,
+    queue VARCHAR(255) NOT NULL,
+    exchange VARCHAR(255) NOT NULL,
+    routing_key VARCHAR(255) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    death_count INT NOT NULL DEFAULT 0,
+    content_type VARCHAR(255) NOT NULL,
+    body MEDIUMBLOB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at DATETIME NOT NULL,
+    redelivered_at DATETIME NULL
+);
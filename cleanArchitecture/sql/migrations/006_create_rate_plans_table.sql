@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS rate_plans (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    api_key_hash CHAR(64) NOT NULL UNIQUE,
+    requests_per_minute INT NOT NULL,
+    created_at DATETIME NOT NULL
+);
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -18,6 +19,8 @@ type Quote struct {
 
 const maxRetries = 5
 
+const quotePrefix = "Dólar: "
+
 func fetchQuoteFromServer() (*Quote, error) {
 	var lastErr error
 
@@ -115,17 +118,20 @@ func isValidQuote(quote *Quote) bool {
 	return true
 }
 
-func saveQuoteToFile(bid string) error {
-	content := fmt.Sprintf("Dólar: %s", bid)
-
+func outputFilePath() string {
 	// Use different paths for Docker vs local development
 	filePath := "./cotacao.txt" // Default for local development
 	if _, err := os.Stat("/data"); err == nil {
 		// /data directory exists, we're in Docker
 		filePath = "/data/cotacao.txt"
 	}
+	return filePath
+}
 
-	err := os.WriteFile(filePath, []byte(content), 0644)
+func saveQuoteToFile(bid string) error {
+	content := quotePrefix + bid
+
+	err := os.WriteFile(outputFilePath(), []byte(content), 0644)
 	if err != nil {
 		log.Printf("Error saving quote to file: %v", err)
 		return err
@@ -135,14 +141,58 @@ func saveQuoteToFile(bid string) error {
 	return nil
 }
 
+// loadStaleQuote reads the last quote saved to cotacao.txt, using the file
+// modification time as the quote age. Quotes older than maxAge are rejected.
+func loadStaleQuote(maxAge time.Duration) (*Quote, time.Duration, error) {
+	filePath := outputFilePath()
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("no cached quote available: %v", err)
+	}
+
+	age := time.Since(info.ModTime())
+	if age > maxAge {
+		return nil, age, fmt.Errorf("cached quote is %v old, older than allowed %v", age.Round(time.Second), maxAge)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, age, fmt.Errorf("failed to read cached quote: %v", err)
+	}
+
+	quote := &Quote{Bid: strings.TrimSpace(strings.TrimPrefix(string(content), quotePrefix))}
+	if !isValidQuote(quote) {
+		return nil, age, fmt.Errorf("cached quote is invalid: %q", string(content))
+	}
+
+	return quote, age, nil
+}
+
 func main() {
+	allowStale := flag.Duration("allow-stale", 0, "when the server is unreachable, report the last saved quote if it is newer than this age (e.g. 30m); 0 disables the fallback")
+	flag.Parse()
+
 	log.Println("Starting client to fetch USD/BRL exchange rate...")
 
 	quote, err := fetchQuoteFromServer()
 	if err != nil {
 		log.Printf("Failed to fetch quote from server after %d attempts: %v", maxRetries, err)
-		log.Fatal("Exiting due to repeated failures")
-		os.Exit(1)
+
+		if *allowStale <= 0 {
+			log.Fatal("Exiting due to repeated failures")
+		}
+
+		staleQuote, age, staleErr := loadStaleQuote(*allowStale)
+		if staleErr != nil {
+			log.Printf("Offline fallback unavailable: %v", staleErr)
+			log.Fatal("Exiting due to repeated failures")
+		}
+
+		log.Printf("WARNING: server unreachable, using cached quote from %v ago", age.Round(time.Second))
+		fmt.Printf("Current USD/BRL exchange rate: %s (STALE, %v old)\n", staleQuote.Bid, age.Round(time.Second))
+		log.Println("Client completed with stale data")
+		return
 	}
 
 	log.Printf("Successfully obtained exchange rate: %s", quote.Bid)
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+)
+
+// Provider endpoints; the base currency or the pair is appended to each
+const (
+	exchangeRateAPIBaseURL = "https://api.exchangerate-api.com/v4/latest/"
+	awesomeAPIBaseURL      = "https://economia.awesomeapi.com.br/json/last/"
+)
+
+// defaultPair is served by /cotacao and stored for quotes saved before pairs existed
+const defaultPair = "USD-BRL"
+
+// currencyPair is a quotable pair such as USD-BRL: the price of one Base in Quote
+type currencyPair struct {
+	Code  string
+	Base  string
+	Quote string
+	// ExchangeRateAPI is false for pairs the primary provider does not quote
+	// (it has no cryptocurrencies), which go straight to AwesomeAPI
+	ExchangeRateAPI bool
+}
+
+// supportedPairs is the pair registry, in the order reports list them
+var supportedPairs = []currencyPair{
+	{Code: "USD-BRL", Base: "USD", Quote: "BRL", ExchangeRateAPI: true},
+	{Code: "EUR-BRL", Base: "EUR", Quote: "BRL", ExchangeRateAPI: true},
+	{Code: "BTC-BRL", Base: "BTC", Quote: "BRL"},
+}
+
+// lookupPair finds a supported pair by its code, ignoring case
+func lookupPair(code string) (currencyPair, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for _, pair := range supportedPairs {
+		if pair.Code == code {
+			return pair, true
+		}
+	}
+	return currencyPair{}, false
+}
+
+// pairCodes lists the codes of the supported pairs, e.g. for error messages
+func pairCodes() []string {
+	codes := make([]string, len(supportedPairs))
+	for i, pair := range supportedPairs {
+		codes[i] = pair.Code
+	}
+	return codes
+}
+
+// exchangeRateAPIURL returns the ExchangeRate-API endpoint with the rates of the base currency
+func (p currencyPair) exchangeRateAPIURL() string {
+	return exchangeRateAPIBaseURL + p.Base
+}
+
+// awesomeAPIURL returns the AwesomeAPI endpoint of the pair
+func (p currencyPair) awesomeAPIURL() string {
+	return awesomeAPIBaseURL + p.Code
+}
+
+// awesomeAPIKey is the field of the AwesomeAPI response holding the pair, e.g. USDBRL
+func (p currencyPair) awesomeAPIKey() string {
+	return p.Base + p.Quote
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// defaultPollInterval is how often the poller refreshes every pair when
+// QUOTE_POLL_INTERVAL is not set
+const defaultPollInterval = 30 * time.Second
+
+// pollIntervalFromEnv reads QUOTE_POLL_INTERVAL as a Go duration (default 30s);
+// 0 disables the poller and /cotacao fetches every quote on demand
+func pollIntervalFromEnv() (time.Duration, error) {
+	value := os.Getenv("QUOTE_POLL_INTERVAL")
+	if value == "" {
+		return defaultPollInterval, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return 0, fmt.Errorf("QUOTE_POLL_INTERVAL must be a non-negative duration such as 30s")
+	}
+	if interval > 0 && interval < time.Second {
+		return 0, fmt.Errorf("QUOTE_POLL_INTERVAL must be at least 1s")
+	}
+	return interval, nil
+}
+
+// refreshQuote fetches the quote of pair, publishes it to hub, which keeps it
+// as the latest quote of the pair, and stores it
+func refreshQuote(store *quoteStore, hub *quoteHub, pair currencyPair) (QuoteEvent, error) {
+	exchangeData, err := fetchExchangeRateWithFallback(pair)
+	if err != nil {
+		return QuoteEvent{}, err
+	}
+
+	// Convert float64 to string with 4 decimal places
+	bid := fmt.Sprintf("%.4f", exchangeData.Rates[pair.Quote])
+	log.Printf("Successfully fetched %s bid: %s", pair.Code, bid)
+
+	event := QuoteEvent{Pair: pair.Code, Bid: bid, Timestamp: time.Now().UTC()}
+	hub.publish(event)
+
+	// Save to database (with timeout handling)
+	if err := store.save(pair.Code, bid); err != nil {
+		log.Printf("Error saving quote to database: %v", err)
+		// The quote is still served even if DB save fails
+	} else {
+		log.Printf("Successfully saved quote to database: %s %s", pair.Code, bid)
+	}
+	return event, nil
+}
+
+// runQuotePoller refreshes every supported pair right away and then on each
+// interval until ctx is done. A pair that fails keeps its previous quote,
+// which /cotacao reports as stale once it is too old.
+func runQuotePoller(ctx context.Context, store *quoteStore, hub *quoteHub, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, pair := range supportedPairs {
+			if _, err := refreshQuote(store, hub, pair); err != nil {
+				log.Printf("Quote poller failed to refresh %s, keeping the previous quote: %v", pair.Code, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// openPostgres opens a Postgres pool whose sessions run in UTC, so that
+// CURRENT_TIMESTAMP and the "YYYY-MM-DD HH:MM:SS" bounds of the queries are
+// UTC like the timestamps SQLite stores
+func openPostgres(dsn string) (*sql.DB, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(utcConnector{connector}), nil
+}
+
+// utcConnector sets the time zone of every new connection to UTC
+type utcConnector struct {
+	driver.Connector
+}
+
+func (c utcConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("postgres connection cannot set its time zone")
+	}
+	if _, err := execer.ExecContext(ctx, "SET TIME ZONE 'UTC'", nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// postgresRepository stores the quotes in Postgres
+type postgresRepository struct {
+	sqlQuotes
+}
+
+// Migrate creates the tables; Postgres support started with the pair column,
+// so there are no older schemas to upgrade
+func (r *postgresRepository) Migrate(ctx context.Context) error {
+	statements := []string{`
+	CREATE TABLE IF NOT EXISTS quotes (
+		id BIGSERIAL PRIMARY KEY,
+		pair TEXT NOT NULL DEFAULT '` + defaultPair + `',
+		bid TEXT NOT NULL,
+		timestamp TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);`,
+		"CREATE INDEX IF NOT EXISTS idx_quotes_pair ON quotes (pair, id)", `
+	CREATE TABLE IF NOT EXISTS api_tokens (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		revoked_at TIMESTAMPTZ,
+		request_count BIGINT NOT NULL DEFAULT 0,
+		last_used_at TIMESTAMPTZ
+	);`, `
+	CREATE TABLE IF NOT EXISTS report_deliveries (
+		id BIGSERIAL PRIMARY KEY,
+		report_date TEXT NOT NULL,
+		channel TEXT NOT NULL,
+		triggered_by TEXT NOT NULL,
+		status TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);`, `
+	CREATE TABLE IF NOT EXISTS quote_daily_summaries (
+		pair TEXT NOT NULL,
+		day TEXT NOT NULL,
+		open DOUBLE PRECISION NOT NULL,
+		high DOUBLE PRECISION NOT NULL,
+		low DOUBLE PRECISION NOT NULL,
+		close DOUBLE PRECISION NOT NULL,
+		quotes INTEGER NOT NULL,
+		PRIMARY KEY (pair, day)
+	);`,
+	}
+	for _, statement := range statements {
+		if _, err := r.db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
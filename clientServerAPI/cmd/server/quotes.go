@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqliteTimeLayout is how CURRENT_TIMESTAMP stores the quote timestamps
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+// Orders accepted by /cotacoes
+const (
+	orderAsc  = "asc"
+	orderDesc = "desc"
+)
+
+// quoteFilter selects stored quotes of one pair. From and Until are "YYYY-MM-DD
+// HH:MM:SS" bounds in UTC, empty when absent; Until is exclusive.
+type quoteFilter struct {
+	Pair   string
+	From   string
+	Until  string
+	Order  string
+	Cursor int64
+	Limit  int
+}
+
+// QuotePage is a page of stored quotes; NextCursor is set when more quotes match
+type QuotePage struct {
+	Quotes     []QuoteRecord `json:"quotes"`
+	NextCursor *int64        `json:"next_cursor,omitempty"`
+}
+
+// find returns the quotes matching filter, reading from the replica when configured
+func (s *quoteStore) find(ctx context.Context, filter quoteFilter) ([]QuoteRecord, error) {
+	if s.replica != nil {
+		records, err := s.replica.Find(ctx, filter)
+		if err == nil {
+			return records, nil
+		}
+		log.Printf("Read replica query failed, falling back to primary: %v", err)
+	}
+	return s.primary.Find(ctx, filter)
+}
+
+// parseQuoteTime parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC midnight)
+// and reports whether it was a date
+func parseQuoteTime(value string) (t time.Time, date bool, err error) {
+	if t, err = time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// parseQuoteFilter reads pair, from, to, order, cursor and limit from the query string.
+// A date in to includes the whole day; a timestamp is inclusive to the second.
+func parseQuoteFilter(r *http.Request) (quoteFilter, error) {
+	query := r.URL.Query()
+	filter := quoteFilter{Pair: defaultPair, Order: orderDesc, Limit: 10}
+
+	if value := query.Get("pair"); value != "" {
+		pair, ok := lookupPair(value)
+		if !ok {
+			return filter, fmt.Errorf("pair must be one of %s", strings.Join(pairCodes(), ", "))
+		}
+		filter.Pair = pair.Code
+	}
+
+	var from, until time.Time
+	if value := query.Get("from"); value != "" {
+		t, _, err := parseQuoteTime(value)
+		if err != nil {
+			return filter, fmt.Errorf("from must be YYYY-MM-DD or RFC 3339")
+		}
+		from = t.UTC()
+		filter.From = from.Format(sqliteTimeLayout)
+	}
+	if value := query.Get("to"); value != "" {
+		t, date, err := parseQuoteTime(value)
+		if err != nil {
+			return filter, fmt.Errorf("to must be YYYY-MM-DD or RFC 3339")
+		}
+		if date {
+			until = t.AddDate(0, 0, 1)
+		} else {
+			until = t.UTC().Truncate(time.Second).Add(time.Second)
+		}
+		filter.Until = until.Format(sqliteTimeLayout)
+	}
+	if !from.IsZero() && !until.IsZero() && !from.Before(until) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+
+	switch order := strings.ToLower(query.Get("order")); order {
+	case "":
+	case orderAsc, orderDesc:
+		filter.Order = order
+	default:
+		return filter, fmt.Errorf("order must be asc or desc")
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || cursor < 1 {
+			return filter, fmt.Errorf("cursor must be a quote id")
+		}
+		filter.Cursor = cursor
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 100 {
+			return filter, fmt.Errorf("limit must be between 1 and 100")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// quotesHandler lists the stored quotes of a pair within an optional period, one
+// page at a time: the next page is requested with cursor=next_cursor
+func quotesHandler(store *quoteStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseQuoteFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		// One extra quote tells whether there is a next page
+		limit := filter.Limit
+		filter.Limit++
+		records, err := store.find(ctx, filter)
+		if err != nil {
+			log.Printf("Error reading stored quotes: %v", err)
+			http.Error(w, "Failed to read stored quotes", http.StatusInternalServerError)
+			return
+		}
+
+		page := QuotePage{Quotes: records}
+		if len(records) > limit {
+			page.Quotes = records[:limit]
+			next := page.Quotes[limit-1].ID
+			page.NextCursor = &next
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}
+}
+
+// historyHandler answers /historico with the quotes of the first /cotacoes page
+// as a bare list, its original format: the most recent ones of pair by default
+func historyHandler(store *quoteStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseQuoteFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		records, err := store.find(ctx, filter)
+		if err != nil {
+			log.Printf("Error reading quote history: %v", err)
+			http.Error(w, "Failed to read quote history", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(records)
+	}
+}
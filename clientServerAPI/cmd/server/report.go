@@ -0,0 +1,419 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report delivery channels, triggers and statuses stored in report_deliveries
+const (
+	channelWebhook = "webhook"
+	channelEmail   = "email"
+
+	triggerScheduled = "scheduled"
+	triggerManual    = "manual"
+
+	deliverySent   = "sent"
+	deliveryFailed = "failed"
+)
+
+// reportDeliveryTimeout bounds each delivery attempt
+const reportDeliveryTimeout = 10 * time.Second
+
+// PairSummary is the daily open/high/low/close of one pair; the prices are
+// zero when no quote was stored that day
+type PairSummary struct {
+	Pair          string  `json:"pair"`
+	Open          float64 `json:"open"`
+	High          float64 `json:"high"`
+	Low           float64 `json:"low"`
+	Close         float64 `json:"close"`
+	ChangePercent float64 `json:"change_percent"`
+	Quotes        int     `json:"quotes"`
+}
+
+// DailyReport summarizes the quotes of one UTC day
+type DailyReport struct {
+	Date        string        `json:"date"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Pairs       []PairSummary `json:"pairs"`
+}
+
+// ReportDelivery is the persisted outcome of sending a report through one channel
+type ReportDelivery struct {
+	ID         int64     `json:"id"`
+	ReportDate string    `json:"report_date"`
+	Channel    string    `json:"channel"`
+	Trigger    string    `json:"trigger"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// reportSender delivers a report through one channel
+type reportSender interface {
+	channel() string
+	send(ctx context.Context, report *DailyReport) error
+}
+
+// webhookSender posts the report as JSON to REPORT_WEBHOOK_URL
+type webhookSender struct {
+	url string
+}
+
+func (s *webhookSender) channel() string { return channelWebhook }
+
+func (s *webhookSender) send(ctx context.Context, report *DailyReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// smtpSender emails the report as plain text
+type smtpSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+func (s *smtpSender) channel() string { return channelEmail }
+
+func (s *smtpSender) send(ctx context.Context, report *DailyReport) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&msg, "Subject: Resumo diário de cotações - %s\r\n", report.Date)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(formatReport(report))
+
+	// net/smtp has no context support, so the send is bounded like the database writes
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(s.addr, s.auth, s.from, s.to, []byte(msg.String()))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// formatReport renders the report as the plain text body of the email
+func formatReport(report *DailyReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Resumo de cotações de %s (UTC)\r\n\r\n", report.Date)
+	for _, pair := range report.Pairs {
+		if pair.Quotes == 0 {
+			fmt.Fprintf(&b, "%s: nenhuma cotação registrada\r\n", pair.Pair)
+			continue
+		}
+		fmt.Fprintf(&b, "%s: abertura %.4f | máxima %.4f | mínima %.4f | fechamento %.4f | variação %+.2f%% (%d cotações)\r\n",
+			pair.Pair, pair.Open, pair.High, pair.Low, pair.Close, pair.ChangePercent, pair.Quotes)
+	}
+	return b.String()
+}
+
+// reportSendersFromEnv builds a sender for each configured channel:
+// REPORT_WEBHOOK_URL for the webhook and SMTP_ADDR, REPORT_EMAIL_FROM and
+// REPORT_EMAIL_TO (comma separated) for email, with optional SMTP_USERNAME/SMTP_PASSWORD
+func reportSendersFromEnv() ([]reportSender, error) {
+	var senders []reportSender
+	if url := os.Getenv("REPORT_WEBHOOK_URL"); url != "" {
+		senders = append(senders, &webhookSender{url: url})
+	}
+
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		from := os.Getenv("REPORT_EMAIL_FROM")
+		var to []string
+		for _, address := range strings.Split(os.Getenv("REPORT_EMAIL_TO"), ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				to = append(to, address)
+			}
+		}
+		if from == "" || len(to) == 0 {
+			return nil, fmt.Errorf("SMTP_ADDR requires REPORT_EMAIL_FROM and REPORT_EMAIL_TO")
+		}
+
+		sender := &smtpSender{addr: addr, from: from, to: to}
+		if username := os.Getenv("SMTP_USERNAME"); username != "" {
+			host, _, _ := strings.Cut(addr, ":")
+			sender.auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+		}
+		senders = append(senders, sender)
+	}
+	return senders, nil
+}
+
+// reportScheduleFromEnv reads REPORT_TIME as HH:MM in UTC (default 00:05)
+func reportScheduleFromEnv() (hour, minute int, err error) {
+	value := os.Getenv("REPORT_TIME")
+	if value == "" {
+		return 0, 5, nil
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("REPORT_TIME must be HH:MM: %w", err)
+	}
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+// reportService compiles daily reports and records each delivery
+type reportService struct {
+	db      *database
+	senders []reportSender
+}
+
+// compile summarizes the quotes stored on the UTC day of date, one entry per supported pair
+func (s *reportService) compile(ctx context.Context, date time.Time) (*DailyReport, error) {
+	start := date.UTC().Truncate(24 * time.Hour)
+	day := start.Format("2006-01-02")
+	rows, err := s.db.QueryContext(ctx, "SELECT pair, bid FROM quotes WHERE timestamp >= ? AND timestamp < ? ORDER BY id",
+		start.Format(sqliteTimeLayout), start.AddDate(0, 0, 1).Format(sqliteTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make([]PairSummary, len(supportedPairs))
+	index := make(map[string]int, len(supportedPairs))
+	for i, pair := range supportedPairs {
+		summaries[i].Pair = pair.Code
+		index[pair.Code] = i
+	}
+	for rows.Next() {
+		var pair, bid string
+		if err := rows.Scan(&pair, &bid); err != nil {
+			return nil, err
+		}
+		i, ok := index[pair]
+		if !ok {
+			log.Printf("Skipping quote of unsupported pair %q in report", pair)
+			continue
+		}
+		price, err := strconv.ParseFloat(bid, 64)
+		if err != nil {
+			log.Printf("Skipping quote with invalid bid %q in report: %v", bid, err)
+			continue
+		}
+
+		summary := &summaries[i]
+		if summary.Quotes == 0 {
+			summary.Open, summary.High, summary.Low = price, price, price
+		}
+		summary.High = math.Max(summary.High, price)
+		summary.Low = math.Min(summary.Low, price)
+		summary.Close = price
+		summary.Quotes++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		if summary := &summaries[i]; summary.Open != 0 {
+			summary.ChangePercent = math.Round((summary.Close-summary.Open)/summary.Open*10000) / 100
+		}
+	}
+
+	return &DailyReport{Date: day, GeneratedAt: time.Now().UTC(), Pairs: summaries}, nil
+}
+
+// deliver compiles the report of date and sends it through every channel. Scheduled
+// runs skip channels that already delivered that date, e.g. after a restart.
+func (s *reportService) deliver(ctx context.Context, date time.Time, trigger string) (*DailyReport, []ReportDelivery, error) {
+	report, err := s.compile(ctx, date)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compiling report: %w", err)
+	}
+
+	deliveries := []ReportDelivery{}
+	for _, sender := range s.senders {
+		if trigger == triggerScheduled {
+			sent, err := s.alreadySent(ctx, report.Date, sender.channel())
+			if err != nil {
+				return nil, nil, err
+			}
+			if sent {
+				log.Printf("Daily report for %s already sent via %s, skipping", report.Date, sender.channel())
+				continue
+			}
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, reportDeliveryTimeout)
+		sendErr := sender.send(sendCtx, report)
+		cancel()
+
+		delivery := ReportDelivery{ReportDate: report.Date, Channel: sender.channel(), Trigger: trigger, Status: deliverySent}
+		if sendErr != nil {
+			delivery.Status = deliveryFailed
+			delivery.Error = sendErr.Error()
+			log.Printf("Error sending daily report for %s via %s: %v", report.Date, sender.channel(), sendErr)
+		} else {
+			log.Printf("Daily report for %s sent via %s", report.Date, sender.channel())
+		}
+
+		if err := s.record(ctx, &delivery); err != nil {
+			return nil, nil, fmt.Errorf("recording delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return report, deliveries, nil
+}
+
+func (s *reportService) alreadySent(ctx context.Context, reportDate, channel string) (bool, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM report_deliveries WHERE report_date = ? AND channel = ? AND status = ?",
+		reportDate, channel, deliverySent).Scan(&count)
+	return count > 0, err
+}
+
+func (s *reportService) record(ctx context.Context, delivery *ReportDelivery) error {
+	err := s.db.QueryRowContext(ctx,
+		"INSERT INTO report_deliveries (report_date, channel, triggered_by, status, error) VALUES (?, ?, ?, ?, ?) RETURNING id",
+		delivery.ReportDate, delivery.Channel, delivery.Trigger, delivery.Status, delivery.Error).Scan(&delivery.ID)
+	delivery.CreatedAt = time.Now().UTC()
+	return err
+}
+
+// deliveries lists the most recent delivery attempts
+func (s *reportService) deliveries(ctx context.Context, limit int) ([]ReportDelivery, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, report_date, channel, triggered_by, status, error, created_at FROM report_deliveries ORDER BY id DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []ReportDelivery{}
+	for rows.Next() {
+		var delivery ReportDelivery
+		if err := rows.Scan(&delivery.ID, &delivery.ReportDate, &delivery.Channel, &delivery.Trigger,
+			&delivery.Status, &delivery.Error, &delivery.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// nextReportRun returns the next time at hour:minute UTC strictly after now
+func nextReportRun(now time.Time, hour, minute int) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// runReportScheduler sends the report of the previous UTC day every day at hour:minute UTC
+func runReportScheduler(ctx context.Context, reports *reportService, hour, minute int) {
+	for {
+		next := nextReportRun(time.Now(), hour, minute)
+		log.Printf("Next daily report scheduled for %s", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, _, err := reports.deliver(ctx, next.AddDate(0, 0, -1), triggerScheduled); err != nil {
+			log.Printf("Error running scheduled daily report: %v", err)
+		}
+	}
+}
+
+// triggerReportHandler sends the report of ?date=YYYY-MM-DD (default: yesterday, UTC)
+// right away and returns it with the outcome of each delivery
+func triggerReportHandler(reports *reportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		date := time.Now().UTC().AddDate(0, 0, -1)
+		if value := r.URL.Query().Get("date"); value != "" {
+			parsed, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				http.Error(w, "date must be YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			date = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(len(reports.senders)+1)*reportDeliveryTimeout)
+		defer cancel()
+
+		report, deliveries, err := reports.deliver(ctx, date, triggerManual)
+		if err != nil {
+			log.Printf("Error running manual daily report: %v", err)
+			http.Error(w, "Failed to run daily report", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Report     *DailyReport     `json:"report"`
+			Deliveries []ReportDelivery `json:"deliveries"`
+		}{report, deliveries})
+	}
+}
+
+// reportDeliveriesHandler lists the latest report deliveries, ?limit=1..100 (default 20)
+func reportDeliveriesHandler(reports *reportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 20
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > 100 {
+				http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		deliveries, err := reports.deliveries(ctx, limit)
+		if err != nil {
+			log.Printf("Error reading report deliveries: %v", err)
+			http.Error(w, "Failed to read report deliveries", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(deliveries)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Database drivers selectable with DB_DRIVER
+const (
+	driverSQLite   = "sqlite"
+	driverPostgres = "postgres"
+)
+
+// QuoteRepository stores the quotes. Migrate also creates the tables the
+// server keeps next to them (API tokens, report deliveries and daily summaries).
+type QuoteRepository interface {
+	// Migrate creates the tables and upgrades those of older versions
+	Migrate(ctx context.Context) error
+	// Save stores a new quote of pair, timestamped by the database
+	Save(ctx context.Context, pair, bid string) error
+	// Find returns the quotes matching filter
+	Find(ctx context.Context, filter quoteFilter) ([]QuoteRecord, error)
+	// OldestBefore returns the timestamp of the oldest quote stored before
+	// cutoff; ok is false when there is none
+	OldestBefore(ctx context.Context, cutoff time.Time) (oldest time.Time, ok bool, err error)
+	// Prune deletes the quotes stored before cutoff and returns how many were removed
+	Prune(ctx context.Context, cutoff time.Time) (int64, error)
+	// SaveDailySummary stores the summary of day, replacing the one of the same pair and day
+	SaveDailySummary(ctx context.Context, day string, summary PairSummary) error
+	// CheckWritable fails if a quote could not be stored right now
+	CheckWritable(ctx context.Context) error
+	// Ping checks that the database is reachable
+	Ping(ctx context.Context) error
+}
+
+// database is a connection pool whose queries are written with ? placeholders
+// and SQLite's UTC timestamps, and adapted to the driver when run
+type database struct {
+	pool   *sql.DB
+	driver string
+}
+
+// openDatabase opens the database of driver at dsn
+func openDatabase(driver, dsn string) (*database, error) {
+	switch driver {
+	case driverSQLite:
+		pool, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, err
+		}
+		return &database{pool: pool, driver: driver}, nil
+	case driverPostgres:
+		pool, err := openPostgres(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return &database{pool: pool, driver: driver}, nil
+	default:
+		return nil, fmt.Errorf("DB_DRIVER must be %s or %s, got %q", driverSQLite, driverPostgres, driver)
+	}
+}
+
+// rebind numbers the ? placeholders of query ($1, $2...) for Postgres
+func (d *database) rebind(query string) string {
+	if d.driver != driverPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func (d *database) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.pool.ExecContext(ctx, d.rebind(query), args...)
+}
+
+func (d *database) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.pool.QueryContext(ctx, d.rebind(query), args...)
+}
+
+func (d *database) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.pool.QueryRowContext(ctx, d.rebind(query), args...)
+}
+
+func (d *database) PingContext(ctx context.Context) error {
+	return d.pool.PingContext(ctx)
+}
+
+func (d *database) Close() error {
+	return d.pool.Close()
+}
+
+// newQuoteRepository returns the repository implementation of the driver of db
+func newQuoteRepository(db *database) QuoteRepository {
+	if db.driver == driverPostgres {
+		return &postgresRepository{sqlQuotes{db: db}}
+	}
+	return &sqliteRepository{sqlQuotes{db: db}}
+}
+
+// sqlQuotes implements the quote queries both databases share
+type sqlQuotes struct {
+	db *database
+}
+
+func (q sqlQuotes) Save(ctx context.Context, pair, bid string) error {
+	_, err := q.db.ExecContext(ctx, "INSERT INTO quotes (pair, bid) VALUES (?, ?)", pair, bid)
+	return err
+}
+
+// Find pages through the quotes by id, which follows the timestamps, so the
+// cursor is the id of the last quote of the previous page
+func (q sqlQuotes) Find(ctx context.Context, filter quoteFilter) ([]QuoteRecord, error) {
+	conditions := []string{"pair = ?"}
+	args := []any{filter.Pair}
+	if filter.From != "" {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, filter.From)
+	}
+	if filter.Until != "" {
+		conditions = append(conditions, "timestamp < ?")
+		args = append(args, filter.Until)
+	}
+	if filter.Cursor > 0 {
+		if filter.Order == orderAsc {
+			conditions = append(conditions, "id > ?")
+		} else {
+			conditions = append(conditions, "id < ?")
+		}
+		args = append(args, filter.Cursor)
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf("SELECT id, pair, bid, timestamp FROM quotes WHERE %s ORDER BY id %s LIMIT ?",
+		strings.Join(conditions, " AND "), strings.ToUpper(filter.Order))
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanQuotes(rows)
+}
+
+func (q sqlQuotes) OldestBefore(ctx context.Context, cutoff time.Time) (time.Time, bool, error) {
+	var oldest time.Time
+	err := q.db.QueryRowContext(ctx, "SELECT timestamp FROM quotes WHERE timestamp < ? ORDER BY id LIMIT 1",
+		cutoff.UTC().Format(sqliteTimeLayout)).Scan(&oldest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return oldest, true, nil
+}
+
+func (q sqlQuotes) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, "DELETE FROM quotes WHERE timestamp < ?", cutoff.UTC().Format(sqliteTimeLayout))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// SaveDailySummary upserts with ON CONFLICT, which SQLite and Postgres both support
+func (q sqlQuotes) SaveDailySummary(ctx context.Context, day string, summary PairSummary) error {
+	_, err := q.db.ExecContext(ctx, `
+	INSERT INTO quote_daily_summaries (pair, day, open, high, low, close, quotes)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (pair, day) DO UPDATE SET open = excluded.open, high = excluded.high,
+		low = excluded.low, close = excluded.close, quotes = excluded.quotes`,
+		summary.Pair, day, summary.Open, summary.High, summary.Low, summary.Close, summary.Quotes)
+	return err
+}
+
+// CheckWritable inserts a quote inside a transaction and rolls it back, which
+// fails if the database cannot take its write lock or is read-only
+func (q sqlQuotes) CheckWritable(ctx context.Context) error {
+	tx, err := q.db.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, q.db.rebind("INSERT INTO quotes (bid) VALUES (?)"), "health-check")
+	return err
+}
+
+func (q sqlQuotes) Ping(ctx context.Context) error {
+	return q.db.PingContext(ctx)
+}
+
+// scanQuotes reads and closes rows of id, pair, bid and timestamp
+func scanQuotes(rows *sql.Rows) ([]QuoteRecord, error) {
+	defer rows.Close()
+	records := []QuoteRecord{}
+	for rows.Next() {
+		var record QuoteRecord
+		if err := rows.Scan(&record.ID, &record.Pair, &record.Bid, &record.Timestamp); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// Quote inserts wait up to the challenge's 10ms on the local SQLite file, and
+// longer for the network round trip to Postgres
+const (
+	sqliteWriteTimeout   = 10 * time.Millisecond
+	postgresWriteTimeout = 100 * time.Millisecond
+)
+
+// openStorage opens the primary database selected by DB_DRIVER (sqlite by
+// default) and DB_DSN, migrates it and opens the optional read replica
+func openStorage(ctx context.Context) (*database, *quoteStore, error) {
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = driverSQLite
+	}
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		if driver != driverSQLite {
+			return nil, nil, fmt.Errorf("DB_DSN is required with DB_DRIVER=%s", driver)
+		}
+		dsn = defaultSQLitePath()
+	}
+
+	db, err := openDatabase(driver, dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	primary := newQuoteRepository(db)
+	if err := primary.Migrate(ctx); err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	store := &quoteStore{primary: primary, writeTimeout: sqliteWriteTimeout}
+	if driver == driverPostgres {
+		store.writeTimeout = postgresWriteTimeout
+	}
+	store.replica, err = openReplica(driver)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return db, store, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// What the retention job does with the quotes older than the retention window
+const (
+	retentionDelete  = "delete"
+	retentionCompact = "compact"
+)
+
+// retentionInterval is how often the retention job runs
+const retentionInterval = time.Hour
+
+// retentionPolicy keeps the quotes of the last Days whole UTC days (plus today)
+type retentionPolicy struct {
+	Days int
+	Mode string
+}
+
+// retentionFromEnv reads QUOTE_RETENTION_DAYS (unset or 0 keeps every quote) and
+// QUOTE_RETENTION_MODE (delete, the default, or compact)
+func retentionFromEnv() (retentionPolicy, error) {
+	policy := retentionPolicy{Mode: retentionDelete}
+	if value := os.Getenv("QUOTE_RETENTION_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return policy, fmt.Errorf("QUOTE_RETENTION_DAYS must be a number of days")
+		}
+		policy.Days = days
+	}
+	switch mode := os.Getenv("QUOTE_RETENTION_MODE"); mode {
+	case "":
+	case retentionDelete, retentionCompact:
+		policy.Mode = mode
+	default:
+		return policy, fmt.Errorf("QUOTE_RETENTION_MODE must be %s or %s", retentionDelete, retentionCompact)
+	}
+	return policy, nil
+}
+
+// cutoff returns the start of the oldest UTC day kept at now
+func (p retentionPolicy) cutoff(now time.Time) time.Time {
+	return now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -p.Days)
+}
+
+// retentionService removes the quotes older than the policy, first summarizing
+// each of their days into quote_daily_summaries in compact mode
+type retentionService struct {
+	quotes  QuoteRepository
+	reports *reportService
+	policy  retentionPolicy
+}
+
+// run applies the policy at now and returns the number of quotes removed
+func (s *retentionService) run(ctx context.Context, now time.Time) (int64, error) {
+	cutoff := s.policy.cutoff(now)
+	if s.policy.Mode == retentionCompact {
+		if err := s.compact(ctx, cutoff); err != nil {
+			return 0, fmt.Errorf("compacting quotes: %w", err)
+		}
+	}
+
+	return s.quotes.Prune(ctx, cutoff)
+}
+
+// compact stores the daily summary of every day before cutoff that still has
+// quotes. A summary is overwritten if its day is compacted again, e.g. when the
+// delete failed after it was saved, so the run can simply be retried.
+func (s *retentionService) compact(ctx context.Context, cutoff time.Time) error {
+	oldest, ok, err := s.quotes.OldestBefore(ctx, cutoff)
+	if err != nil || !ok {
+		return err
+	}
+
+	for day := oldest.UTC().Truncate(24 * time.Hour); day.Before(cutoff); day = day.AddDate(0, 0, 1) {
+		report, err := s.reports.compile(ctx, day)
+		if err != nil {
+			return err
+		}
+		for _, summary := range report.Pairs {
+			if summary.Quotes == 0 {
+				continue
+			}
+			if err := s.quotes.SaveDailySummary(ctx, report.Date, summary); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runRetention applies the policy right away and then every retentionInterval until ctx is done
+func runRetention(ctx context.Context, retention *retentionService) {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		removed, err := retention.run(ctx, time.Now())
+		if err != nil {
+			log.Printf("Error applying quote retention: %v", err)
+		} else if removed > 0 {
+			log.Printf("Quote retention (%s) removed %d quotes older than %d days", retention.policy.Mode, removed, retention.policy.Days)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicyCutoff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		days     int
+		now      time.Time
+		expected time.Time
+	}{
+		{"keeps today only with 0 days", 0, time.Date(2025, 7, 22, 14, 3, 11, 0, time.UTC), time.Date(2025, 7, 22, 0, 0, 0, 0, time.UTC)},
+		{"keeps whole past days", 3, time.Date(2025, 7, 22, 14, 3, 11, 0, time.UTC), time.Date(2025, 7, 19, 0, 0, 0, 0, time.UTC)},
+		{"crosses month boundary", 5, time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)},
+		{"uses the UTC day", 1, time.Date(2025, 7, 22, 22, 30, 0, 0, time.FixedZone("BRT", -3*60*60)), time.Date(2025, 7, 22, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cutoff := retentionPolicy{Days: tc.days}.cutoff(tc.now)
+			if !cutoff.Equal(tc.expected) {
+				t.Errorf("Expected cutoff %s, got %s", tc.expected, cutoff)
+			}
+		})
+	}
+}
+
+func TestRetentionFromEnv(t *testing.T) {
+	testCases := []struct {
+		name     string
+		days     string
+		mode     string
+		expected retentionPolicy
+		wantErr  bool
+	}{
+		{"defaults keep every quote", "", "", retentionPolicy{Days: 0, Mode: retentionDelete}, false},
+		{"days with default mode", "90", "", retentionPolicy{Days: 90, Mode: retentionDelete}, false},
+		{"compact mode", "30", "compact", retentionPolicy{Days: 30, Mode: retentionCompact}, false},
+		{"explicit delete mode", "7", "delete", retentionPolicy{Days: 7, Mode: retentionDelete}, false},
+		{"negative days", "-1", "", retentionPolicy{}, true},
+		{"days not a number", "ninety", "", retentionPolicy{}, true},
+		{"unknown mode", "30", "archive", retentionPolicy{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("QUOTE_RETENTION_DAYS", tc.days)
+			t.Setenv("QUOTE_RETENTION_MODE", tc.mode)
+
+			policy, err := retentionFromEnv()
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got policy %+v", policy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if policy != tc.expected {
+				t.Errorf("Expected policy %+v, got %+v", tc.expected, policy)
+			}
+		})
+	}
+}
+
+// retentionNow is when the retention tests run; with 5 days the cutoff is 2025-07-17
+var retentionNow = time.Date(2025, 7, 22, 14, 3, 11, 0, time.UTC)
+
+// openRetentionTestDB migrates a SQLite database in a temporary directory and
+// stores quotes before, on and after the 2025-07-17 cutoff
+func openRetentionTestDB(t *testing.T) (*database, QuoteRepository) {
+	t.Helper()
+	ctx := context.Background()
+	db, err := openDatabase(driverSQLite, filepath.Join(t.TempDir(), "quotes.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	quotes := newQuoteRepository(db)
+	if err := quotes.Migrate(ctx); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	rows := []struct {
+		pair, bid, timestamp string
+	}{
+		{"USD-BRL", "5.00", "2025-07-01 10:00:00"},
+		{"EUR-BRL", "6.00", "2025-07-01 12:00:00"},
+		{"USD-BRL", "5.20", "2025-07-01 15:00:00"},
+		{"USD-BRL", "5.10", "2025-07-03 09:00:00"},
+		{"USD-BRL", "5.40", "2025-07-16 23:59:59"},
+		{"USD-BRL", "5.50", "2025-07-17 00:00:00"},
+		{"BTC-BRL", "350000.00", "2025-07-20 09:00:00"},
+	}
+	for _, row := range rows {
+		if _, err := db.ExecContext(ctx, "INSERT INTO quotes (pair, bid, timestamp) VALUES (?, ?, ?)", row.pair, row.bid, row.timestamp); err != nil {
+			t.Fatalf("Failed to insert quote: %v", err)
+		}
+	}
+	return db, quotes
+}
+
+// remainingBids returns the bids still stored, in insertion order
+func remainingBids(t *testing.T, db *database) []string {
+	t.Helper()
+	rows, err := db.QueryContext(context.Background(), "SELECT bid FROM quotes ORDER BY id")
+	if err != nil {
+		t.Fatalf("Failed to query quotes: %v", err)
+	}
+	defer rows.Close()
+	var bids []string
+	for rows.Next() {
+		var bid string
+		if err := rows.Scan(&bid); err != nil {
+			t.Fatalf("Failed to scan quote: %v", err)
+		}
+		bids = append(bids, bid)
+	}
+	return bids
+}
+
+// dailySummary is a row of quote_daily_summaries
+type dailySummary struct {
+	Pair, Day              string
+	Open, High, Low, Close float64
+	Quotes                 int
+}
+
+// storedSummaries returns the daily summaries by day and pair
+func storedSummaries(t *testing.T, db *database) []dailySummary {
+	t.Helper()
+	rows, err := db.QueryContext(context.Background(), "SELECT pair, day, open, high, low, close, quotes FROM quote_daily_summaries ORDER BY day, pair")
+	if err != nil {
+		t.Fatalf("Failed to query summaries: %v", err)
+	}
+	defer rows.Close()
+	var summaries []dailySummary
+	for rows.Next() {
+		var s dailySummary
+		if err := rows.Scan(&s.Pair, &s.Day, &s.Open, &s.High, &s.Low, &s.Close, &s.Quotes); err != nil {
+			t.Fatalf("Failed to scan summary: %v", err)
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries
+}
+
+func TestPrune(t *testing.T) {
+	db, quotes := openRetentionTestDB(t)
+
+	removed, err := quotes.Prune(context.Background(), time.Date(2025, 7, 17, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if removed != 5 {
+		t.Errorf("Expected 5 quotes removed, got %d", removed)
+	}
+	if bids, expected := remainingBids(t, db), []string{"5.50", "350000.00"}; !reflect.DeepEqual(bids, expected) {
+		t.Errorf("Expected quotes %v to remain, got %v", expected, bids)
+	}
+}
+
+func TestRetentionRunDelete(t *testing.T) {
+	db, quotes := openRetentionTestDB(t)
+	retention := &retentionService{quotes: quotes, reports: &reportService{db: db}, policy: retentionPolicy{Days: 5, Mode: retentionDelete}}
+
+	removed, err := retention.run(context.Background(), retentionNow)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if removed != 5 {
+		t.Errorf("Expected 5 quotes removed, got %d", removed)
+	}
+	if bids, expected := remainingBids(t, db), []string{"5.50", "350000.00"}; !reflect.DeepEqual(bids, expected) {
+		t.Errorf("Expected quotes %v to remain, got %v", expected, bids)
+	}
+	if summaries := storedSummaries(t, db); len(summaries) != 0 {
+		t.Errorf("Expected no daily summaries in delete mode, got %+v", summaries)
+	}
+}
+
+func TestRetentionRunCompact(t *testing.T) {
+	db, quotes := openRetentionTestDB(t)
+	retention := &retentionService{quotes: quotes, reports: &reportService{db: db}, policy: retentionPolicy{Days: 5, Mode: retentionCompact}}
+
+	removed, err := retention.run(context.Background(), retentionNow)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if removed != 5 {
+		t.Errorf("Expected 5 quotes removed, got %d", removed)
+	}
+	if bids, expected := remainingBids(t, db), []string{"5.50", "350000.00"}; !reflect.DeepEqual(bids, expected) {
+		t.Errorf("Expected quotes %v to remain, got %v", expected, bids)
+	}
+
+	// Days without quotes (2025-07-02) and pairs without quotes get no summary
+	expected := []dailySummary{
+		{"EUR-BRL", "2025-07-01", 6.00, 6.00, 6.00, 6.00, 1},
+		{"USD-BRL", "2025-07-01", 5.00, 5.20, 5.00, 5.20, 2},
+		{"USD-BRL", "2025-07-03", 5.10, 5.10, 5.10, 5.10, 1},
+		{"USD-BRL", "2025-07-16", 5.40, 5.40, 5.40, 5.40, 1},
+	}
+	if summaries := storedSummaries(t, db); !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("Expected summaries %+v, got %+v", expected, summaries)
+	}
+
+	// A second run finds nothing left to compact and keeps the summaries
+	removed, err = retention.run(context.Background(), retentionNow)
+	if err != nil {
+		t.Fatalf("Unexpected error on second run: %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("Expected nothing removed on second run, got %d", removed)
+	}
+	if summaries := storedSummaries(t, db); !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("Expected summaries %+v after second run, got %+v", expected, summaries)
+	}
+}
+
+func TestRetentionCompactSummarizesAgain(t *testing.T) {
+	db, quotes := openRetentionTestDB(t)
+	retention := &retentionService{quotes: quotes, reports: &reportService{db: db}, policy: retentionPolicy{Days: 5, Mode: retentionCompact}}
+	cutoff := retention.policy.cutoff(retentionNow)
+
+	// A stale summary left by a run whose delete failed is overwritten
+	if err := quotes.SaveDailySummary(context.Background(), "2025-07-03", PairSummary{Pair: "USD-BRL", Open: 1, High: 1, Low: 1, Close: 1, Quotes: 9}); err != nil {
+		t.Fatalf("Failed to save summary: %v", err)
+	}
+	if err := retention.compact(context.Background(), cutoff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	summaries := storedSummaries(t, db)
+	if len(summaries) != 4 || summaries[2] != (dailySummary{"USD-BRL", "2025-07-03", 5.10, 5.10, 5.10, 5.10, 1}) {
+		t.Errorf("Expected the 2025-07-03 summary to be rewritten, got %+v", summaries)
+	}
+	if bids := remainingBids(t, db); len(bids) != 7 {
+		t.Errorf("Expected compact to leave the quotes for Prune, got %v", bids)
+	}
+}
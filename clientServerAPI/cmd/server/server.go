@@ -2,84 +2,201 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
+// Build information, injected at build time with
+// -ldflags "-X main.version=... -X main.commit=..."
+var (
+	version = "dev"
+	commit  = "unknown"
+)
+
+// startTime is when this server instance started
+var startTime = time.Now()
+
+// Components reported by the deep health check
+const (
+	componentDatabase        = "database"
+	componentReplica         = "replica"
+	componentExchangeRateAPI = "exchangerate-api"
+	componentAwesomeAPI      = "awesomeapi"
+)
+
+// Health statuses of a component and of the whole server
+const (
+	statusUp       = "up"
+	statusDown     = "down"
+	statusOK       = "ok"
+	statusDegraded = "degraded"
+)
+
+// healthCheckTimeout bounds each deep health probe, so load balancers get a fast answer
+const healthCheckTimeout = 150 * time.Millisecond
+
+// ComponentHealth is the state of one dependency in the deep health check
+type ComponentHealth struct {
+	Status      string     `json:"status"`
+	LatencyMs   int64      `json:"latency_ms"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// HealthResponse is returned by /health; Components is only set with deep=true
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	CheckedAt  time.Time                  `json:"checked_at"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+}
+
+// successTracker remembers when each component last worked, from regular
+// traffic and from health probes
+type successTracker struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+var lastSuccess = &successTracker{last: make(map[string]time.Time)}
+
+func (t *successTracker) record(component string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last[component] = time.Now()
+}
+
+func (t *successTracker) get(component string) *time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	last, ok := t.last[component]
+	if !ok {
+		return nil
+	}
+	return &last
+}
+
+type VersionInfo struct {
+	Version   string    `json:"version"`
+	Commit    string    `json:"commit"`
+	GoVersion string    `json:"go_version"`
+	StartTime time.Time `json:"start_time"`
+}
+
 type ExchangeResponse struct {
-	Rates struct {
-		BRL float64 `json:"BRL"`
-	} `json:"rates"`
-	Base string `json:"base"`
-	Date string `json:"date"`
-}
-
-// Legacy response structure for fallback APIs
-type AwesomeAPIResponse struct {
-	USDBRL struct {
-		Code       string `json:"code"`
-		Codein     string `json:"codein"`
-		Name       string `json:"name"`
-		High       string `json:"high"`
-		Low        string `json:"low"`
-		VarBid     string `json:"varBid"`
-		PctChange  string `json:"pctChange"`
-		Bid        string `json:"bid"`
-		Ask        string `json:"ask"`
-		Timestamp  string `json:"timestamp"`
-		CreateDate string `json:"create_date"`
-	} `json:"USDBRL"`
+	Rates map[string]float64 `json:"rates"`
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+}
+
+// AwesomeAPIQuote is the quote of one pair in an AwesomeAPI response
+type AwesomeAPIQuote struct {
+	Code       string `json:"code"`
+	Codein     string `json:"codein"`
+	Name       string `json:"name"`
+	High       string `json:"high"`
+	Low        string `json:"low"`
+	VarBid     string `json:"varBid"`
+	PctChange  string `json:"pctChange"`
+	Bid        string `json:"bid"`
+	Ask        string `json:"ask"`
+	Timestamp  string `json:"timestamp"`
+	CreateDate string `json:"create_date"`
 }
 
+// Legacy response structure for fallback APIs, keyed by the pair without
+// the dash (e.g. USDBRL)
+type AwesomeAPIResponse map[string]AwesomeAPIQuote
+
+// Quote is returned by /cotacao; FetchedAt is when the provider was called
+// and Stale is set when the poller has failed to refresh the quote for a while
 type Quote struct {
-	Bid string `json:"bid"`
+	Pair       string    `json:"pair"`
+	Bid        string    `json:"bid"`
+	FetchedAt  time.Time `json:"fetched_at"`
+	AgeSeconds int64     `json:"age_seconds"`
+	Stale      bool      `json:"stale"`
 }
 
-func initDB() (*sql.DB, error) {
-	// Use different paths for Docker vs local development
-	dbPath := "./quotes.db" // Default for local development
-	if _, err := os.Stat("/data"); err == nil {
-		// /data directory exists, we're in Docker
-		dbPath = "/data/quotes.db"
-	}
+// QuoteRecord is a stored quote returned by /historico and /cotacoes
+type QuoteRecord struct {
+	ID        int64     `json:"id"`
+	Pair      string    `json:"pair"`
+	Bid       string    `json:"bid"`
+	Timestamp time.Time `json:"timestamp"`
+}
 
-	db, err := sql.Open("sqlite", dbPath)
-	if err != nil {
-		return nil, err
-	}
+// APIToken is a client token; Token is only returned when the token is created
+type APIToken struct {
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	Token        string     `json:"token,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RequestCount int64      `json:"request_count"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+}
+
+// tokenStore keeps API tokens and their usage in the primary database.
+// Only the SHA-256 of each token is stored.
+type tokenStore struct {
+	db *database
+}
+
+// quoteStore sends writes to the primary database and history reads to an
+// optional read replica, falling back to the primary when the replica fails
+type quoteStore struct {
+	primary      QuoteRepository
+	replica      QuoteRepository
+	writeTimeout time.Duration
+}
 
-	createTable := `
-	CREATE TABLE IF NOT EXISTS quotes (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		bid TEXT NOT NULL,
-		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP
-	);`
+// openReplica opens the read-only database configured in QUOTES_REPLICA_DSN, if
+// any, with the driver of the primary. E.g. file:/replica/quotes.db?mode=ro for
+// a replicated SQLite copy.
+func openReplica(driver string) (QuoteRepository, error) {
+	dsn := os.Getenv("QUOTES_REPLICA_DSN")
+	if dsn == "" {
+		return nil, nil
+	}
 
-	_, err = db.Exec(createTable)
+	db, err := openDatabase(driver, dsn)
 	if err != nil {
 		return nil, err
 	}
+	replica := newQuoteRepository(db)
 
-	return db, nil
+	// An unavailable replica is not fatal: reads fall back to the primary
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := replica.Ping(ctx); err != nil {
+		log.Printf("Read replica unavailable at startup, using primary for reads: %v", err)
+	}
+	return replica, nil
 }
 
-func saveQuoteToDatabase(db *sql.DB, bid string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+// save stores a new quote in the primary database within the write timeout
+func (s *quoteStore) save(pair, bid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.writeTimeout)
 	defer cancel()
 
-	query := "INSERT INTO quotes (bid) VALUES (?)"
-
 	done := make(chan error, 1)
 	go func() {
-		_, err := db.Exec(query, bid)
-		done <- err
+		done <- s.primary.Save(ctx, pair, bid)
 	}()
 
 	select {
@@ -88,6 +205,7 @@ func saveQuoteToDatabase(db *sql.DB, bid string) error {
 			log.Printf("Error saving to database: %v", err)
 			return err
 		}
+		lastSuccess.record(componentDatabase)
 		return nil
 	case <-ctx.Done():
 		log.Printf("Database operation timeout: %v", ctx.Err())
@@ -95,12 +213,207 @@ func saveQuoteToDatabase(db *sql.DB, bid string) error {
 	}
 }
 
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// create issues a new random token named name
+func (s *tokenStore) create(ctx context.Context, name string) (*APIToken, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(secret)
+
+	var id int64
+	err := s.db.QueryRowContext(ctx, "INSERT INTO api_tokens (name, token_hash) VALUES (?, ?) RETURNING id", name, hashToken(token)).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return &APIToken{ID: id, Name: name, Token: token, CreatedAt: time.Now().UTC()}, nil
+}
+
+// revoke disables the token with id; it reports false if there is no such active token
+func (s *tokenStore) revoke(ctx context.Context, id int64) (bool, error) {
+	result, err := s.db.ExecContext(ctx, "UPDATE api_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL", id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// use counts a request made with token and reports whether the token is active
+func (s *tokenStore) use(ctx context.Context, token string) (bool, error) {
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE api_tokens SET request_count = request_count + 1, last_used_at = CURRENT_TIMESTAMP WHERE token_hash = ? AND revoked_at IS NULL",
+		hashToken(token))
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// stats lists every token with its request count, most used first
+func (s *tokenStore) stats(ctx context.Context) ([]APIToken, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, name, created_at, revoked_at, request_count, last_used_at FROM api_tokens ORDER BY request_count DESC, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []APIToken{}
+	for rows.Next() {
+		var (
+			token      APIToken
+			revokedAt  sql.NullTime
+			lastUsedAt sql.NullTime
+		)
+		if err := rows.Scan(&token.ID, &token.Name, &token.CreatedAt, &revokedAt, &token.RequestCount, &lastUsedAt); err != nil {
+			return nil, err
+		}
+		if revokedAt.Valid {
+			token.RevokedAt = &revokedAt.Time
+		}
+		if lastUsedAt.Valid {
+			token.LastUsedAt = &lastUsedAt.Time
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// bearerToken returns the token of an "Authorization: Bearer <token>" header
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// requireToken rejects requests without an active API token and meters the others
+func requireToken(tokens *tokenStore, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "API token required", http.StatusUnauthorized)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		active, err := tokens.use(ctx, token)
+		if err != nil {
+			log.Printf("Error checking API token: %v", err)
+			http.Error(w, "Failed to check API token", http.StatusInternalServerError)
+			return
+		}
+		if !active {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "Invalid or revoked API token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireAdmin only lets through requests bearing adminToken
+func requireAdmin(adminToken string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(bearerToken(r)), []byte(adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Admin token required", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// createTokenHandler issues a token for the client named in {"name": "..."}
+func createTokenHandler(tokens *tokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || strings.TrimSpace(request.Name) == "" {
+			http.Error(w, `body must be {"name": "..."}`, http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		token, err := tokens.create(ctx, strings.TrimSpace(request.Name))
+		if err != nil {
+			log.Printf("Error creating API token: %v", err)
+			http.Error(w, "Failed to create API token", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("API token %d created for %q", token.ID, token.Name)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(token)
+	}
+}
+
+// revokeTokenHandler revokes the token in the {id} path segment
+func revokeTokenHandler(tokens *tokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid token id", http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		revoked, err := tokens.revoke(ctx, id)
+		if err != nil {
+			log.Printf("Error revoking API token %d: %v", id, err)
+			http.Error(w, "Failed to revoke API token", http.StatusInternalServerError)
+			return
+		}
+		if !revoked {
+			http.Error(w, "API token not found or already revoked", http.StatusNotFound)
+			return
+		}
+		log.Printf("API token %d revoked", id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// statsHandler reports the request count of every token
+func statsHandler(tokens *tokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		stats, err := tokens.stats(ctx)
+		if err != nil {
+			log.Printf("Error reading API token stats: %v", err)
+			http.Error(w, "Failed to read API token stats", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+	}
+}
+
 // Fallback function to try AwesomeAPI if ExchangeRate-API fails
-func fetchFromAwesomeAPI() (string, error) {
+func fetchFromAwesomeAPI(pair currencyPair) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://economia.awesomeapi.com.br/json/last/USD-BRL", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", pair.awesomeAPIURL(), nil)
 	if err != nil {
 		return "", err
 	}
@@ -121,45 +434,52 @@ func fetchFromAwesomeAPI() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	quote, ok := apiResp[pair.awesomeAPIKey()]
+	if !ok {
+		return "", fmt.Errorf("AwesomeAPI response has no %s quote", pair.Code)
+	}
 
-	return apiResp.USDBRL.Bid, nil
+	lastSuccess.record(componentAwesomeAPI)
+	return quote.Bid, nil
 }
 
-func fetchExchangeRateWithFallback() (*ExchangeResponse, error) {
-	// Try primary API first (ExchangeRate-API)
-	result, err := fetchExchangeRate()
-	if err == nil {
-		return result, nil
+func fetchExchangeRateWithFallback(pair currencyPair) (*ExchangeResponse, error) {
+	// Try primary API first (ExchangeRate-API), when it quotes the pair
+	var err error
+	if pair.ExchangeRateAPI {
+		var result *ExchangeResponse
+		result, err = fetchExchangeRate(pair)
+		if err == nil {
+			return result, nil
+		}
+		log.Printf("Primary ExchangeRate-API failed for %s, trying AwesomeAPI fallback: %v", pair.Code, err)
 	}
 
-	log.Printf("Primary ExchangeRate-API failed, trying AwesomeAPI fallback: %v", err)
-
 	// Try AwesomeAPI as fallback
-	bid, err := fetchFromAwesomeAPI()
-	if err == nil {
+	bid, fallbackErr := fetchFromAwesomeAPI(pair)
+	if fallbackErr == nil {
 		// Convert string bid to float64 then back to match our structure
-		var brlRate float64
-		if _, parseErr := fmt.Sscanf(bid, "%f", &brlRate); parseErr == nil {
+		var rate float64
+		if _, fallbackErr = fmt.Sscanf(bid, "%f", &rate); fallbackErr == nil {
 			return &ExchangeResponse{
-				Rates: struct {
-					BRL float64 `json:"BRL"`
-				}{
-					BRL: brlRate,
-				},
-				Base: "USD",
-				Date: time.Now().Format("2006-01-02"),
+				Rates: map[string]float64{pair.Quote: rate},
+				Base:  pair.Base,
+				Date:  time.Now().Format("2006-01-02"),
 			}, nil
 		}
 	}
 
-	return nil, fmt.Errorf("all exchange rate APIs failed - Primary: %v", err)
+	if !pair.ExchangeRateAPI {
+		return nil, fmt.Errorf("AwesomeAPI failed for %s: %v", pair.Code, fallbackErr)
+	}
+	return nil, fmt.Errorf("all exchange rate APIs failed - Primary: %v, AwesomeAPI: %v", err, fallbackErr)
 }
 
-func fetchExchangeRate() (*ExchangeResponse, error) {
+func fetchExchangeRate(pair currencyPair) (*ExchangeResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.exchangerate-api.com/v4/latest/USD", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", pair.exchangeRateAPIURL(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -186,49 +506,249 @@ func fetchExchangeRate() (*ExchangeResponse, error) {
 		log.Printf("Error decoding JSON response: %v", err)
 		return nil, err
 	}
+	if _, ok := exchangeResp.Rates[pair.Quote]; !ok {
+		return nil, fmt.Errorf("ExchangeRate-API response has no %s rate", pair.Quote)
+	}
 
-	log.Printf("Successfully fetched BRL rate: %.4f", exchangeResp.Rates.BRL)
+	log.Printf("Successfully fetched %s rate: %.4f", pair.Code, exchangeResp.Rates[pair.Quote])
+	lastSuccess.record(componentExchangeRateAPI)
 	return &exchangeResp, nil
 }
 
-func quotationHandler(db *sql.DB) http.HandlerFunc {
+// quotationHandler serves the quote of the {pair} path segment; without it
+// (the original /cotacao route) the pair is USD-BRL. With the poller running
+// (pollInterval > 0) the latest polled quote is served and the provider is only
+// called for a pair the poller has not quoted yet; otherwise every request
+// fetches a new quote. Every quote fetched is published to the stream subscribers of hub.
+func quotationHandler(store *quoteStore, hub *quoteHub, pollInterval time.Duration) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		exchangeData, err := fetchExchangeRateWithFallback()
-		if err != nil {
-			log.Printf("Error fetching exchange rate from all sources: %v", err)
-			http.Error(w, "Failed to fetch exchange rate", http.StatusInternalServerError)
+		code := r.PathValue("pair")
+		if code == "" {
+			code = defaultPair
+		}
+		pair, ok := lookupPair(code)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unsupported pair %q, use one of %s", code, strings.Join(pairCodes(), ", ")), http.StatusNotFound)
 			return
 		}
 
-		// Convert float64 to string with 4 decimal places
-		bid := fmt.Sprintf("%.4f", exchangeData.Rates.BRL)
-		log.Printf("Successfully fetched USD-BRL bid: %s", bid)
-
-		// Save to database (with timeout handling)
-		err = saveQuoteToDatabase(db, bid)
-		if err != nil {
-			log.Printf("Error saving quote to database: %v", err)
-			// Continue serving the response even if DB save fails
-		} else {
-			log.Printf("Successfully saved quote to database: %s", bid)
+		event, cached := hub.last(pair.Code)
+		if pollInterval == 0 || !cached {
+			var err error
+			event, err = refreshQuote(store, hub, pair)
+			if err != nil {
+				log.Printf("Error fetching %s exchange rate from all sources: %v", pair.Code, err)
+				http.Error(w, "Failed to fetch exchange rate", http.StatusInternalServerError)
+				return
+			}
 		}
 
-		quote := Quote{Bid: bid}
+		age := time.Since(event.Timestamp).Truncate(time.Second)
+		quote := Quote{
+			Pair:       pair.Code,
+			Bid:        event.Bid,
+			FetchedAt:  event.Timestamp,
+			AgeSeconds: int64(age.Seconds()),
+			// The poller missed at least one refresh in a row
+			Stale: pollInterval > 0 && age > 2*pollInterval,
+		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(quote)
-		log.Printf("Response sent to client with bid: %s", bid)
+		log.Printf("Response sent to client with %s bid: %s", pair.Code, event.Bid)
+	}
+}
+
+// checkProvider requests url and expects a 200
+func checkProvider(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// probe runs check with healthCheckTimeout and reports the component's health
+func probe(component string, check func(ctx context.Context) error) ComponentHealth {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	health := ComponentHealth{Status: statusUp, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		health.Status = statusDown
+		health.Error = err.Error()
+	} else {
+		lastSuccess.record(component)
 	}
+	health.LastSuccess = lastSuccess.get(component)
+	return health
+}
+
+// healthHandler answers "ok" by default; with deep=true it probes the database,
+// the replica and each provider in parallel. The server is down (503) when the
+// database is not writable or no provider answers, and degraded when only some fail.
+func healthHandler(store *quoteStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := HealthResponse{Status: statusOK, CheckedAt: time.Now()}
+		if deep, _ := strconv.ParseBool(r.URL.Query().Get("deep")); !deep {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		usdBRL, _ := lookupPair(defaultPair)
+		checks := map[string]func(ctx context.Context) error{
+			componentDatabase:        store.primary.CheckWritable,
+			componentExchangeRateAPI: func(ctx context.Context) error { return checkProvider(ctx, usdBRL.exchangeRateAPIURL()) },
+			componentAwesomeAPI:      func(ctx context.Context) error { return checkProvider(ctx, usdBRL.awesomeAPIURL()) },
+		}
+		if store.replica != nil {
+			checks[componentReplica] = store.replica.Ping
+		}
+
+		var (
+			mu sync.Mutex
+			wg sync.WaitGroup
+		)
+		response.Components = make(map[string]ComponentHealth, len(checks))
+		for component, check := range checks {
+			wg.Add(1)
+			go func(component string, check func(ctx context.Context) error) {
+				defer wg.Done()
+				health := probe(component, check)
+				mu.Lock()
+				response.Components[component] = health
+				mu.Unlock()
+			}(component, check)
+		}
+		wg.Wait()
+
+		statusCode := http.StatusOK
+		providersDown := response.Components[componentExchangeRateAPI].Status == statusDown &&
+			response.Components[componentAwesomeAPI].Status == statusDown
+		switch {
+		case response.Components[componentDatabase].Status == statusDown || providersDown:
+			response.Status = statusDown
+			statusCode = http.StatusServiceUnavailable
+		default:
+			for _, health := range response.Components {
+				if health.Status == statusDown {
+					response.Status = statusDegraded
+				}
+			}
+		}
+		if response.Status != statusOK {
+			log.Printf("Deep health check: %s", response.Status)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func versionHandler(w http.ResponseWriter, r *http.Request) {
+	info := VersionInfo{
+		Version:   version,
+		Commit:    commit,
+		GoVersion: runtime.Version(),
+		StartTime: startTime,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// withVersionHeader adds the server version to every response
+func withVersionHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Server-Version", version)
+		next.ServeHTTP(w, r)
+	})
 }
 
 func main() {
-	db, err := initDB()
+	log.SetPrefix(fmt.Sprintf("[server %s] ", version))
+	log.Printf("Build: version=%s commit=%s go=%s", version, commit, runtime.Version())
+
+	db, store, err := openStorage(context.Background())
 	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 	defer db.Close()
+	log.Printf("Storing quotes in %s", db.driver)
+	if store.replica != nil {
+		log.Println("History reads will use the read replica")
+	}
+	tokens := &tokenStore{db: db}
 
-	http.HandleFunc("/cotacao", quotationHandler(db))
+	senders, err := reportSendersFromEnv()
+	if err != nil {
+		log.Fatal("Invalid daily report configuration:", err)
+	}
+	reports := &reportService{db: db, senders: senders}
+	if len(senders) > 0 {
+		hour, minute, err := reportScheduleFromEnv()
+		if err != nil {
+			log.Fatal("Invalid daily report configuration:", err)
+		}
+		go runReportScheduler(context.Background(), reports, hour, minute)
+	} else {
+		log.Println("No REPORT_WEBHOOK_URL or SMTP_ADDR set, daily report disabled")
+	}
+
+	policy, err := retentionFromEnv()
+	if err != nil {
+		log.Fatal("Invalid quote retention configuration:", err)
+	}
+	if policy.Days > 0 {
+		retention := &retentionService{quotes: store.primary, reports: reports, policy: policy}
+		log.Printf("Keeping quotes for %d days (%s older ones)", policy.Days, policy.Mode)
+		go runRetention(context.Background(), retention)
+	} else {
+		log.Println("No QUOTE_RETENTION_DAYS set, keeping every quote")
+	}
+
+	pollInterval, err := pollIntervalFromEnv()
+	if err != nil {
+		log.Fatal("Invalid quote poller configuration:", err)
+	}
+	hub := newQuoteHub()
+	if pollInterval > 0 {
+		log.Printf("Refreshing quotes every %s in the background", pollInterval)
+		go runQuotePoller(context.Background(), store, hub, pollInterval)
+	} else {
+		log.Println("QUOTE_POLL_INTERVAL is 0, quotes are fetched on every /cotacao request")
+	}
+
+	http.HandleFunc("/cotacao", quotationHandler(store, hub, pollInterval))
+	http.HandleFunc("/cotacao/{pair}", quotationHandler(store, hub, pollInterval))
+	http.HandleFunc("GET /cotacao/stream", sseHandler(hub))
+	http.HandleFunc("GET /ws", wsHandler(hub))
+	http.HandleFunc("/historico", requireToken(tokens, historyHandler(store)))
+	http.HandleFunc("/cotacoes", requireToken(tokens, quotesHandler(store)))
+	http.HandleFunc("/version", versionHandler)
+	http.HandleFunc("/health", healthHandler(store))
+
+	// Token management is only exposed when an admin token is configured
+	if adminToken := os.Getenv("ADMIN_TOKEN"); adminToken != "" {
+		http.HandleFunc("POST /admin/tokens", requireAdmin(adminToken, createTokenHandler(tokens)))
+		http.HandleFunc("DELETE /admin/tokens/{id}", requireAdmin(adminToken, revokeTokenHandler(tokens)))
+		http.HandleFunc("GET /admin/stats", requireAdmin(adminToken, statsHandler(tokens)))
+		http.HandleFunc("POST /admin/reports/daily", requireAdmin(adminToken, triggerReportHandler(reports)))
+		http.HandleFunc("GET /admin/reports/deliveries", requireAdmin(adminToken, reportDeliveriesHandler(reports)))
+	} else {
+		log.Println("ADMIN_TOKEN not set, token management and report endpoints disabled")
+	}
 
 	log.Println("Server starting on port 8080...")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	log.Fatal(http.ListenAndServe(":8080", withVersionHeader(http.DefaultServeMux)))
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// defaultSQLitePath is the database file used when DB_DSN is not set
+func defaultSQLitePath() string {
+	// Use different paths for Docker vs local development
+	if _, err := os.Stat("/data"); err == nil {
+		// /data directory exists, we're in Docker
+		return "/data/quotes.db"
+	}
+	return "./quotes.db" // Default for local development
+}
+
+// sqliteRepository stores the quotes in a SQLite file
+type sqliteRepository struct {
+	sqlQuotes
+}
+
+func (r *sqliteRepository) Migrate(ctx context.Context) error {
+	createTable := `
+	CREATE TABLE IF NOT EXISTS quotes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		bid TEXT NOT NULL,
+		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	_, err := r.db.ExecContext(ctx, createTable)
+	if err != nil {
+		return err
+	}
+
+	// Databases created before multi-currency support only hold USD-BRL quotes
+	err = r.addColumnIfMissing(ctx, "quotes", "pair", "TEXT NOT NULL DEFAULT '"+defaultPair+"'")
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_quotes_pair ON quotes (pair, id)")
+	if err != nil {
+		return err
+	}
+
+	createTokensTable := `
+	CREATE TABLE IF NOT EXISTS api_tokens (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		revoked_at DATETIME,
+		request_count INTEGER NOT NULL DEFAULT 0,
+		last_used_at DATETIME
+	);`
+
+	_, err = r.db.ExecContext(ctx, createTokensTable)
+	if err != nil {
+		return err
+	}
+
+	createDeliveriesTable := `
+	CREATE TABLE IF NOT EXISTS report_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		report_date TEXT NOT NULL,
+		channel TEXT NOT NULL,
+		triggered_by TEXT NOT NULL,
+		status TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	_, err = r.db.ExecContext(ctx, createDeliveriesTable)
+	if err != nil {
+		return err
+	}
+
+	createSummariesTable := `
+	CREATE TABLE IF NOT EXISTS quote_daily_summaries (
+		pair TEXT NOT NULL,
+		day TEXT NOT NULL,
+		open REAL NOT NULL,
+		high REAL NOT NULL,
+		low REAL NOT NULL,
+		close REAL NOT NULL,
+		quotes INTEGER NOT NULL,
+		PRIMARY KEY (pair, day)
+	);`
+
+	_, err = r.db.ExecContext(ctx, createSummariesTable)
+	return err
+}
+
+// addColumnIfMissing adds a column to a table created by an older version of the server
+func (r *sqliteRepository) addColumnIfMissing(ctx context.Context, table, column, definition string) error {
+	rows, err := r.db.QueryContext(ctx, "SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket heartbeats: the server pings every wsPingInterval and drops a
+// connection that sends nothing (not even a pong) for wsPongTimeout
+const (
+	wsPingInterval = 30 * time.Second
+	wsPongTimeout  = 2 * wsPingInterval
+	wsWriteTimeout = 5 * time.Second
+)
+
+// subscriberBuffer is how many quotes a slow subscriber may fall behind before missing some
+const subscriberBuffer = 16
+
+// QuoteEvent is pushed to the stream subscribers for every new quote
+type QuoteEvent struct {
+	Pair      string    `json:"pair"`
+	Bid       string    `json:"bid"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// quoteHub broadcasts new quotes to every subscriber and keeps the latest quote of each pair
+type quoteHub struct {
+	mu          sync.Mutex
+	subscribers map[chan QuoteEvent]struct{}
+	latest      map[string]QuoteEvent
+}
+
+func newQuoteHub() *quoteHub {
+	return &quoteHub{
+		subscribers: make(map[chan QuoteEvent]struct{}),
+		latest:      make(map[string]QuoteEvent),
+	}
+}
+
+// subscribe returns a channel receiving the new quotes and a function that cancels the subscription
+func (h *quoteHub) subscribe() (<-chan QuoteEvent, func()) {
+	events := make(chan QuoteEvent, subscriberBuffer)
+	h.mu.Lock()
+	h.subscribers[events] = struct{}{}
+	h.mu.Unlock()
+
+	return events, func() {
+		h.mu.Lock()
+		delete(h.subscribers, events)
+		h.mu.Unlock()
+	}
+}
+
+// publish sends event to every subscriber without waiting: a subscriber whose
+// buffer is full misses it, so one stalled client cannot hold up the fetches
+func (h *quoteHub) publish(event QuoteEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.latest[event.Pair] = event
+	for events := range h.subscribers {
+		select {
+		case events <- event:
+		default:
+			log.Printf("Stream subscriber is falling behind, dropped %s quote", event.Pair)
+		}
+	}
+}
+
+// last returns the latest quote of pair seen so far
+func (h *quoteHub) last(pair string) (QuoteEvent, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	event, ok := h.latest[pair]
+	return event, ok
+}
+
+// snapshot returns the latest quote of each supported pair seen so far, in
+// registry order, or only that of pair when it is set
+func (h *quoteHub) snapshot(pair string) []QuoteEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var events []QuoteEvent
+	for _, supported := range supportedPairs {
+		if pair != "" && supported.Code != pair {
+			continue
+		}
+		if event, ok := h.latest[supported.Code]; ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// count returns the number of subscribers
+func (h *quoteHub) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+// Quotes are public and the connection carries no credentials, so dashboards
+// on any origin may subscribe
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// streamPair reads the optional ?pair= filter of the stream endpoints; empty means every pair
+func streamPair(r *http.Request) (string, bool) {
+	value := r.URL.Query().Get("pair")
+	if value == "" {
+		return "", true
+	}
+	supported, ok := lookupPair(value)
+	return supported.Code, ok
+}
+
+// wsHandler upgrades to WebSocket and pushes every new quote as a JSON text
+// message, optionally only those of ?pair=
+func wsHandler(hub *quoteHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pair, ok := streamPair(r)
+		if !ok {
+			http.Error(w, "unsupported pair", http.StatusBadRequest)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade has already answered the client
+			log.Printf("WebSocket upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		events, unsubscribe := hub.subscribe()
+		defer unsubscribe()
+		log.Printf("WebSocket client connected from %s (%d subscribers)", r.RemoteAddr, hub.count())
+
+		// The reader only handles pongs and the close frame; it stops on any read error
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			conn.SetReadLimit(512)
+			conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+			conn.SetPongHandler(func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+			})
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ping := time.NewTicker(wsPingInterval)
+		defer ping.Stop()
+		for {
+			select {
+			case event := <-events:
+				if pair != "" && event.Pair != pair {
+					continue
+				}
+				conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+				if err := conn.WriteJSON(event); err != nil {
+					log.Printf("WebSocket write failed, closing: %v", err)
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+					log.Printf("WebSocket ping failed, closing: %v", err)
+					return
+				}
+			case <-closed:
+				log.Printf("WebSocket client %s disconnected", r.RemoteAddr)
+				return
+			}
+		}
+	}
+}
+
+// Server-Sent Events: a comment every sseKeepAliveInterval keeps proxies from
+// closing an idle stream; ?interval= may not be shorter than sseMinInterval
+const (
+	sseKeepAliveInterval = 15 * time.Second
+	sseMinInterval       = time.Second
+)
+
+// sseHandler streams quotes as Server-Sent Events, optionally only those of
+// ?pair=. By default every new quote is sent as it arrives; with ?interval=
+// (a duration such as 10s) the latest quote of each pair is sent on every tick instead.
+func sseHandler(hub *quoteHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pair, ok := streamPair(r)
+		if !ok {
+			http.Error(w, "unsupported pair", http.StatusBadRequest)
+			return
+		}
+
+		var interval time.Duration
+		if value := r.URL.Query().Get("interval"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed < sseMinInterval {
+				http.Error(w, fmt.Sprintf("interval must be a duration of at least %s", sseMinInterval), http.StatusBadRequest)
+				return
+			}
+			interval = parsed
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		rc := http.NewResponseController(w)
+		if err := rc.Flush(); err != nil {
+			log.Printf("SSE stream cannot be flushed: %v", err)
+			return
+		}
+		log.Printf("SSE client connected from %s", r.RemoteAddr)
+
+		// Only one of events and tick is set, the other stays nil and never fires
+		var events <-chan QuoteEvent
+		var tick <-chan time.Time
+		if interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		} else {
+			subscription, unsubscribe := hub.subscribe()
+			defer unsubscribe()
+			events = subscription
+		}
+		keepAlive := time.NewTicker(sseKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		for {
+			var err error
+			select {
+			case event := <-events:
+				if pair != "" && event.Pair != pair {
+					continue
+				}
+				err = writeSSEEvent(w, event)
+			case <-tick:
+				for _, event := range hub.snapshot(pair) {
+					if err = writeSSEEvent(w, event); err != nil {
+						break
+					}
+				}
+			case <-keepAlive.C:
+				_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			case <-r.Context().Done():
+				log.Printf("SSE client %s disconnected", r.RemoteAddr)
+				return
+			}
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil {
+				log.Printf("SSE write failed, closing: %v", err)
+				return
+			}
+		}
+	}
+}
+
+// writeSSEEvent writes event as a "quote" event with its JSON as the data
+func writeSSEEvent(w http.ResponseWriter, event QuoteEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: quote\ndata: %s\n\n", data)
+	return err
+}
@@ -2,7 +2,11 @@ module clientServerAPI
 
 go 1.22.5
 
-require modernc.org/sqlite v1.29.8
+require (
+	github.com/gorilla/websocket v1.5.0
+	github.com/lib/pq v1.10.9
+	modernc.org/sqlite v1.29.8
+)
 
 require (
 	github.com/dustin/go-humanize v1.0.1 // indirect
@@ -1,18 +1,36 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	_ "cloudrun/docs" // Import docs for swagger
 
 	"cloudrun/config"
+	"cloudrun/internal/apikey"
+	"cloudrun/internal/cache"
+	"cloudrun/internal/compress"
+	"cloudrun/internal/domain"
 	"cloudrun/internal/handler"
+	"cloudrun/internal/history"
+	"cloudrun/internal/logging"
+	"cloudrun/internal/lookup"
+	"cloudrun/internal/ratelimit"
 	"cloudrun/internal/repository"
+	"cloudrun/internal/secrets"
+	"cloudrun/internal/server"
 	"cloudrun/internal/service"
+	"cloudrun/internal/status"
+	"cloudrun/pkg/telemetry"
 
-	"github.com/gorilla/mux"
-	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 )
 
 // @title Weather API
@@ -31,41 +49,290 @@ import (
 // @BasePath /
 // @schemes http https
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Exigida apenas quando API_KEYS ou API_KEYS_FILE está configurada
+
 // @tag.name weather
 // @tag.description Operações relacionadas ao clima
 
 // @tag.name health
 // @tag.description Health check da aplicação
 
+// @tag.name stats
+// @tag.description Estatísticas de uso para análise de produto
+
 func main() {
+	// One-off lookups share the binary: cloudrun lookup <cep>
+	if len(os.Args) > 1 && os.Args[1] == "lookup" {
+		os.Exit(lookup.Main("cloudrun lookup", os.Args[2:]))
+	}
+
 	// Load configuration
 	cfg := config.New()
 	if err := cfg.Validate(); err != nil {
-		log.Fatal(err)
+		fatal("Invalid configuration", err)
+	}
+
+	// Structured logs in the Cloud Logging format, correlated with the request trace
+	logger, err := newLogger(cfg)
+	if err != nil {
+		fatal("Invalid logging configuration", err)
+	}
+	slog.SetDefault(logger)
+
+	// Initialize tracing; spans are exported over OTLP when configured
+	shutdownTracer, err := telemetry.InitTracer(context.Background(), cfg.ServiceName, status.Version)
+	if err != nil {
+		fatal("Failed to initialize tracing", err)
 	}
 
 	// Initialize repositories
-	locationRepo := repository.NewViaCEPRepository()
-	weatherRepo := repository.NewWeatherAPIRepository(cfg.WeatherAPIKey)
+	locationRepo := repository.NewViaCEPRepository().WithTimeout(cfg.ViaCEPTimeout).WithRetryPolicy(cfg.ViaCEPRetry)
+	weatherRepo, weatherChecks, err := lookup.NewWeatherData(cfg)
+	if err != nil {
+		fatal("Invalid weather provider configuration", err)
+	}
+	slog.Info("Weather providers configured", "failover_order", cfg.WeatherProviders)
+
+	locationCheck := status.Check{Name: "ViaCEP", Probe: locationRepo.Ping}
+	checks := append([]status.Check{locationCheck}, weatherChecks...)
+
+	// Cache lookups in front of the repositories
+	var (
+		locations   domain.LocationService    = locationRepo
+		weatherData domain.WeatherDataService = weatherRepo
+	)
+	lookupCache, err := newCache(cfg)
+	if err != nil {
+		fatal("Failed to create lookup cache", err)
+	}
+	if lookupCache != nil {
+		locations = repository.NewCachedLocationService(locationRepo, lookupCache, cfg.CacheCEPTTL)
+		weatherData = repository.NewCachedWeatherDataService(weatherRepo, lookupCache, cfg.CacheWeatherTTL)
+		if redisCache, ok := lookupCache.(*cache.Redis); ok {
+			defer redisCache.Close()
+			checks = append(checks, status.Check{Name: "Redis", Probe: redisCache.Ping})
+		}
+	}
 
 	// Initialize services
-	weatherService := service.NewWeatherService(locationRepo, weatherRepo)
+	weatherService := service.NewWeatherService(locations, weatherData).
+		WithDefaultPrecision(cfg.TemperaturePrecision).
+		WithDefaultLanguage(cfg.WeatherLanguage)
+	if cfg.GeoIPProvider != config.GeoIPProviderNone {
+		ipLocator, err := repository.NewIPLocator(cfg.GeoIPProvider, cfg.GeoIPToken, cfg.GeoIPTimeout, cfg.GeoIPRetry)
+		if err != nil {
+			fatal("Invalid geolocation provider configuration", err)
+		}
+		if lookupCache != nil {
+			ipLocator = repository.NewCachedIPLocationService(ipLocator, lookupCache, cfg.CacheCEPTTL)
+		}
+		weatherService.WithIPLocator(ipLocator)
+		slog.Info("Geolocating /weather/me callers", "provider", cfg.GeoIPProvider)
+	}
+
+	// Query history for GET /stats, written off the request path
+	historyStore, recorder, err := newHistory(cfg)
+	if err != nil {
+		fatal("Failed to open query history", err)
+	}
+	if recorder != nil {
+		weatherService.WithRecorder(recorder)
+	}
 
 	// Initialize handlers
-	weatherHandler := handler.NewWeatherHandler(weatherService)
-	healthHandler := handler.NewHealthHandler()
+	weatherHandler := handler.NewWeatherHandler(weatherService).
+		WithBatchLimits(cfg.BatchMaxCEPs, cfg.BatchWorkers).
+		WithCacheControl(cfg.CacheControlMaxAge)
+	if cfg.CacheControlMaxAge > 0 {
+		slog.Info("Letting CDNs cache weather lookups", "max_age", cfg.CacheControlMaxAge.String())
+	}
+	healthHandler := handler.NewHealthHandler([]status.Check{locationCheck}, weatherChecks)
+
+	// Status page: live dependency checks, errors of the last 15 minutes and latency per route
+	errorCounter := status.NewErrorCounter(15 * time.Minute)
+	latency := status.NewLatencyRecorder()
+	statusHandler := handler.NewStatusHandler(errorCounter, checks...).WithLatency(latency)
+	if lookupCache != nil {
+		statusHandler.WithCache(lookupCache)
+	}
+
+	// API keys and rate limit guard the weather lookups
+	apiKeys, err := loadAPIKeys(cfg)
+	if err != nil {
+		fatal("Failed to load API keys", err)
+	}
+	rt := routes{weather: weatherHandler, health: healthHandler, status: statusHandler}
+	if historyStore != nil {
+		rt.stats = handler.NewStatsHandler(historyStore)
+	}
+	if apiKeys.Len() > 0 {
+		rt.auth = []server.Middleware{apiKeys.Middleware}
+		slog.Info("Requiring an API key on /weather", "keys", apiKeys.Len(), "header", apikey.Header)
+	}
+	if cfg.RateLimitRPM > 0 {
+		rt.limit = []server.Middleware{ratelimit.New(cfg.RateLimitRPM, cfg.RateLimitBurst).Middleware}
+		slog.Info("Rate limiting /weather per client IP", "requests_per_minute", cfg.RateLimitRPM, "burst", cfg.RateLimitBurst)
+	}
+	if cfg.GzipMinSize >= 0 {
+		slog.Info("Compressing responses with gzip", "min_size", cfg.GzipMinSize)
+	}
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		slog.Info("Allowing cross-origin calls", "origins", cfg.CORSAllowedOrigins)
+	}
+
+	// Panics and CORS preflights are handled before routing; the route
+	// middleware needs the matched route to name spans and status page entries.
+	// Recovered panics never reach the error counter, so they are reported to it.
+	countPanic := func(r *http.Request, recovered any, stack []byte) {
+		errorCounter.Record(http.StatusInternalServerError)
+	}
+	srv := server.New(
+		server.WithAddr(":"+cfg.Port),
+		server.WithDrainTimeout(cfg.ShutdownTimeout),
+		server.WithMiddleware(server.RecoverWith(countPanic), server.CORS(cfg.CORSAllowedOrigins)),
+		server.WithRouteMiddleware(
+			server.Middleware(otelmux.Middleware(cfg.ServiceName, otelmux.WithFilter(traced))),
+			logging.Middleware,
+			errorCounter.Middleware,
+			latency.Middleware,
+			compress.Middleware(cfg.GzipMinSize),
+		),
+	)
+	rt.register(srv)
+
+	ln, err := srv.Listen()
+	if err != nil {
+		fatal("Failed to listen", err)
+	}
+
+	slog.Info("Server starting", "port", cfg.Port,
+		"status_url", "http://localhost:"+cfg.Port+"/status",
+		"swagger_url", "http://localhost:"+cfg.Port+"/swagger/index.html")
+
+	// Cloud Run sends SIGTERM before stopping an instance
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Rotated secrets take effect without a redeploy
+	if len(cfg.Secrets) > 0 && cfg.SecretRefreshInterval > 0 {
+		watcher := secrets.NewWatcher(secrets.NewSecretManager(), cfg.Secrets)
+		go watcher.Run(ctx, cfg.SecretRefreshInterval, applySecret(cfg, weatherRepo, apiKeys))
+		slog.Info("Refreshing secrets from Secret Manager", "secrets", len(cfg.Secrets), "interval", cfg.SecretRefreshInterval)
+	}
+
+	err = srv.Serve(ctx, ln)
+	if recorder != nil {
+		flushCtx, cancel := context.WithTimeout(context.Background(), historyFlushTimeout)
+		if flushErr := recorder.Close(flushCtx); flushErr != nil {
+			slog.Error("Query history flush failed", "error", flushErr)
+		}
+		cancel()
+		historyStore.Close()
+	}
+	if shutdownErr := shutdownTracer(context.Background()); shutdownErr != nil {
+		slog.Error("Tracer shutdown failed", "error", shutdownErr)
+	}
+	if err != nil {
+		fatal("Server failed", err)
+	}
+	slog.Info("Server shutdown complete")
+}
+
+// fatal logs err with CRITICAL severity and exits
+func fatal(msg string, err error) {
+	slog.Log(context.Background(), logging.LevelCritical, msg, "error", err)
+	os.Exit(1)
+}
 
-	// Setup router
-	r := mux.NewRouter()
+// newLogger builds the logger selected by LOG_FORMAT and LOG_LEVEL, naming
+// traces after the Google Cloud project when it can be found
+func newLogger(cfg *config.Config) (*slog.Logger, error) {
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	return logging.New(os.Stdout, cfg.LogFormat, level, logging.ProjectID(context.Background()))
+}
+
+// loadAPIKeys merges the keys of API_KEYS and API_KEYS_FILE; an empty set leaves the API open
+func loadAPIKeys(cfg *config.Config) (*apikey.Keys, error) {
+	keys := apikey.Parse(cfg.APIKeys)
+	if cfg.APIKeysFile != "" {
+		fileKeys, err := apikey.ReadFile(cfg.APIKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API_KEYS_FILE: %w", err)
+		}
+		keys = append(keys, fileKeys...)
+	}
+	return apikey.New(keys...), nil
+}
+
+// applySecret puts a rotated secret into use. API_KEYS rotates the accepted keys
+// but cannot turn authentication on or off; REDIS_URL needs a restart.
+func applySecret(cfg *config.Config, weatherData *repository.FailoverWeatherDataService, apiKeys *apikey.Keys) func(env, value string) {
+	return func(env, value string) {
+		cfg.SetSecret(env, value)
+		switch env {
+		case "WEATHER_API_KEY":
+			for _, p := range weatherData.Providers() {
+				if weatherAPI, ok := p.Provider.(*repository.WeatherAPIRepository); ok {
+					weatherAPI.SetAPIKey(value)
+				}
+			}
+		case "API_KEYS":
+			keys, err := loadAPIKeys(cfg)
+			if err != nil {
+				slog.Error("Failed to reload API keys", "error", err)
+				return
+			}
+			apiKeys.Replace(keys)
+		default:
+			slog.Warn("Secret changed, restart the service to use it", "env", env)
+		}
+	}
+}
+
+// newCache builds the lookup cache selected by CACHE_BACKEND; nil disables caching
+func newCache(cfg *config.Config) (cache.Cache, error) {
+	switch cfg.CacheBackend {
+	case cache.BackendMemory:
+		slog.Info("Caching lookups in memory", "max_entries", cfg.CacheSize)
+		return cache.NewLRU(cfg.CacheSize), nil
+	case cache.BackendRedis:
+		redisCache, err := cache.NewRedis(cfg.RedisURL, cfg.ServiceName+":")
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+		}
+		slog.Info("Caching lookups in Redis")
+		return redisCache, nil
+	default:
+		slog.Info("Lookup cache disabled")
+		return nil, nil
+	}
+}
 
-	// API endpoints
-	r.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
-	r.HandleFunc("/health", healthHandler.HealthCheck).Methods("GET")
+// historyFlushTimeout bounds the write of the buffered lookups on shutdown,
+// within the 10 seconds Cloud Run allows after SIGTERM
+const historyFlushTimeout = time.Second
 
-	// Swagger documentation
-	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+// newHistory opens the query history selected by HISTORY_BACKEND; both are nil when disabled
+func newHistory(cfg *config.Config) (history.Store, *history.AsyncRecorder, error) {
+	if cfg.HistoryBackend != history.BackendSQLite {
+		slog.Info("Query history disabled")
+		return nil, nil, nil
+	}
+	store, err := history.OpenSQLite(cfg.HistoryPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.Info("Recording query history in SQLite", "path", cfg.HistoryPath, "buffer", cfg.HistoryBuffer)
+	return store, history.NewAsyncRecorder(store, cfg.HistoryBuffer), nil
+}
 
-	log.Printf("Server starting on port %s", cfg.Port)
-	log.Printf("Swagger documentation available at: http://localhost:%s/swagger/index.html", cfg.Port)
-	log.Fatal(http.ListenAndServe(":"+cfg.Port, r))
+// traced keeps health checks and the Swagger UI out of traces
+func traced(r *http.Request) bool {
+	return !strings.HasPrefix(r.URL.Path, "/health") && !strings.HasPrefix(r.URL.Path, "/swagger/")
 }
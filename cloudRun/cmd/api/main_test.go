@@ -1,15 +1,28 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"cloudrun/config"
+	"cloudrun/internal/apikey"
+	"cloudrun/internal/cache"
 	"cloudrun/internal/domain"
 	"cloudrun/internal/handler"
+	"cloudrun/internal/history"
+	"cloudrun/internal/repository"
+	"cloudrun/internal/server"
 	"cloudrun/internal/service"
+	"cloudrun/internal/status"
 
 	"github.com/gorilla/mux"
 )
@@ -17,7 +30,7 @@ import (
 // MockWeatherService for testing
 type MockWeatherService struct{}
 
-func (m *MockWeatherService) GetLocationByCEP(cep string) (*domain.ViaCEPResponse, error) {
+func (m *MockWeatherService) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
 	if cep == "01310100" {
 		return &domain.ViaCEPResponse{
 			CEP:        "01310-100",
@@ -34,37 +47,79 @@ func (m *MockWeatherService) GetLocationByCEP(cep string) (*domain.ViaCEPRespons
 			Erro:       false,
 		}, nil
 	}
-	return nil, service.ErrCEPNotFound
+	if cep == "30112000" {
+		return &domain.ViaCEPResponse{
+			CEP:        "30112-000",
+			Localidade: "Belo Horizonte",
+			UF:         "MG",
+			Erro:       false,
+		}, nil
+	}
+	return nil, domain.ErrCEPNotFound
 }
 
-func (m *MockWeatherService) GetWeatherByLocation(location string) (*domain.WeatherAPIResponse, error) {
+func (m *MockWeatherService) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
 	// Test that we handle locations with special characters properly
 	if location == "São Paulo,SP" || location == "Rio de Janeiro,RJ" {
+		text := "Partly cloudy"
+		if lang, _ := domain.Language(ctx); lang == domain.LanguagePortuguese {
+			text = "Parcialmente nublado"
+		}
 		return &domain.WeatherAPIResponse{
-			Current: struct {
-				TempC float64 `json:"temp_c"`
-			}{
+			Current: domain.WeatherAPICurrent{
 				TempC: 28.5,
+				IsDay: 1,
+				Condition: domain.WeatherAPICondition{
+					Text: text,
+					Code: 1003,
+				},
 			},
+			Provider:  repository.ProviderWeatherAPI,
+			FetchedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
 		}, nil
 	}
+	if location == "Atlantis,Brazil" {
+		return nil, domain.ErrLocationNotFound
+	}
+	if location == "Belo Horizonte,MG" {
+		return nil, &domain.RateLimitError{RetryAfter: 1500 * time.Millisecond}
+	}
 	return nil, service.ErrWeatherDataUnavailable
 }
 
+func (m *MockWeatherService) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	if fmt.Sprintf("%.2f,%.2f", latitude, longitude) == "-23.55,-46.63" {
+		return m.GetWeatherByLocation(ctx, "São Paulo,SP")
+	}
+	return nil, domain.ErrLocationNotFound
+}
+
+func (m *MockWeatherService) GetLocationByIP(ctx context.Context, ip string) (*domain.IPLocation, error) {
+	if ip == "200.160.2.3" {
+		return &domain.IPLocation{City: "São Paulo", Region: "São Paulo", Country: "BR", Latitude: -23.5505, Longitude: -46.6333}, nil
+	}
+	return nil, domain.ErrIPNotLocated
+}
+
 func setupTestRouter() *mux.Router {
 	// Setup mock services
 	locationRepo := &MockWeatherService{}
 	weatherRepo := &MockWeatherService{}
-	weatherService := service.NewWeatherService(locationRepo, weatherRepo)
+	weatherService := service.NewWeatherService(locationRepo, weatherRepo).WithIPLocator(locationRepo)
 
 	// Setup handlers
 	weatherHandler := handler.NewWeatherHandler(weatherService)
-	healthHandler := handler.NewHealthHandler()
+	healthHandler := handler.NewHealthHandler(nil, nil)
 
 	// Setup router
 	r := mux.NewRouter()
+	r.HandleFunc("/weather", weatherHandler.GetWeatherByCity).Methods("GET")
+	r.HandleFunc("/weather/batch", weatherHandler.WithBatchLimits(3, 2).GetWeatherBatch).Methods("POST")
+	r.HandleFunc("/weather/coords", weatherHandler.GetWeatherByCoordinates).Methods("GET")
+	r.HandleFunc("/weather/me", weatherHandler.GetWeatherByIP).Methods("GET")
 	r.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
 	r.HandleFunc("/health", healthHandler.HealthCheck).Methods("GET")
+	r.HandleFunc("/health/live", healthHandler.Live).Methods("GET")
 
 	return r
 }
@@ -90,6 +145,109 @@ func TestHealthEndpoint(t *testing.T) {
 	}
 }
 
+func TestLivenessEndpoint(t *testing.T) {
+	router := setupTestRouter()
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/health/live", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	var body domain.LivenessResponse
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil || body.Status != domain.HealthUp {
+		t.Errorf("Expected status up, got %+v (%v)", body, err)
+	}
+}
+
+func TestReadinessEndpoint(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("weather API rejected the API key (status 401)") }
+
+	tests := []struct {
+		name       string
+		required   []status.Check
+		weather    []status.Check
+		wantStatus int
+		wantDown   []string
+	}{
+		{
+			name:       "all dependencies up",
+			required:   []status.Check{{Name: "ViaCEP", Probe: up}},
+			weather:    []status.Check{{Name: "WeatherAPI", Probe: up}, {Name: "Open-Meteo", Probe: up}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "one weather provider down",
+			required:   []status.Check{{Name: "ViaCEP", Probe: up}},
+			weather:    []status.Check{{Name: "WeatherAPI", Probe: down}, {Name: "Open-Meteo", Probe: up}},
+			wantStatus: http.StatusOK,
+			wantDown:   []string{"WeatherAPI"},
+		},
+		{
+			name:       "every weather provider down",
+			required:   []status.Check{{Name: "ViaCEP", Probe: up}},
+			weather:    []status.Check{{Name: "WeatherAPI", Probe: down}},
+			wantStatus: http.StatusServiceUnavailable,
+			wantDown:   []string{"WeatherAPI"},
+		},
+		{
+			name:       "ViaCEP down",
+			required:   []status.Check{{Name: "ViaCEP", Probe: down}},
+			weather:    []status.Check{{Name: "WeatherAPI", Probe: up}},
+			wantStatus: http.StatusServiceUnavailable,
+			wantDown:   []string{"ViaCEP"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthHandler := handler.NewHealthHandler(tt.required, tt.weather)
+
+			rr := httptest.NewRecorder()
+			healthHandler.Ready(rr, httptest.NewRequest("GET", "/health/ready", nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			var body domain.ReadinessResponse
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(body.Dependencies) != len(tt.required)+len(tt.weather) {
+				t.Errorf("Expected one entry per dependency, got %+v", body.Dependencies)
+			}
+			var down []string
+			for _, dependency := range body.Dependencies {
+				if dependency.Status == domain.HealthDown {
+					down = append(down, dependency.Name)
+					if dependency.Error == "" {
+						t.Errorf("Expected an error for %s", dependency.Name)
+					}
+				}
+			}
+			if strings.Join(down, ",") != strings.Join(tt.wantDown, ",") {
+				t.Errorf("Expected down dependencies %v, got %v", tt.wantDown, down)
+			}
+		})
+	}
+}
+
+func TestReadinessEndpoint_CachesResult(t *testing.T) {
+	calls := 0
+	healthHandler := handler.NewHealthHandler([]status.Check{{Name: "ViaCEP", Probe: func(ctx context.Context) error {
+		calls++
+		return nil
+	}}}, nil)
+
+	for range 3 {
+		healthHandler.Ready(httptest.NewRecorder(), httptest.NewRequest("GET", "/health/ready", nil))
+	}
+	if calls != 1 {
+		t.Errorf("Expected the readiness result to be reused, got %d checks", calls)
+	}
+}
+
 func TestWeatherEndpointSuccess(t *testing.T) {
 	router := setupTestRouter()
 
@@ -122,6 +280,40 @@ func TestWeatherEndpointSuccess(t *testing.T) {
 	}
 }
 
+func TestWeatherEndpointDetailed(t *testing.T) {
+	router := setupTestRouter()
+
+	req, err := http.NewRequest("GET", "/weather/01310100?detail=full", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var response domain.DetailedWeatherResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal("Failed to unmarshal response")
+	}
+
+	if response.TempC != 28.5 {
+		t.Errorf("Expected temp_C to be 28.5, got %v", response.TempC)
+	}
+	if response.Condition.Code != "partly_cloudy" {
+		t.Errorf("Expected condition code 'partly_cloudy', got '%s'", response.Condition.Code)
+	}
+	if response.Condition.Icon != "partly-cloudy-day" {
+		t.Errorf("Expected condition icon 'partly-cloudy-day', got '%s'", response.Condition.Icon)
+	}
+	if response.Condition.Text != "Partly cloudy" {
+		t.Errorf("Expected condition text 'Partly cloudy', got '%s'", response.Condition.Text)
+	}
+}
+
 func TestWeatherEndpointInvalidCEP(t *testing.T) {
 	router := setupTestRouter()
 
@@ -148,6 +340,57 @@ func TestWeatherEndpointInvalidCEP(t *testing.T) {
 	}
 }
 
+// countingLocationRepo counts the CEP lookups that reach the location repository
+type countingLocationRepo struct {
+	MockWeatherService
+	calls int
+}
+
+func (m *countingLocationRepo) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	m.calls++
+	return m.MockWeatherService.GetLocationByCEP(ctx, cep)
+}
+
+func TestWeatherEndpoint_CEPFormats(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantCalls  int
+	}{
+		{"dashed", "/weather/01310-100", http.StatusOK, 1},
+		{"dotted", "/weather/01.310-100", http.StatusOK, 1},
+		{"spaced", "/weather/01310%20100", http.StatusOK, 1},
+		{"letters", "/weather/0131O100", http.StatusUnprocessableEntity, 0},
+		{"alphabetic", "/weather/abcdefgh", http.StatusUnprocessableEntity, 0},
+		{"too short", "/weather/01310-10", http.StatusUnprocessableEntity, 0},
+		{"too long", "/weather/01310-1000", http.StatusUnprocessableEntity, 0},
+		{"only separators", "/weather/-.-", http.StatusUnprocessableEntity, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := &countingLocationRepo{}
+			weatherHandler := handler.NewWeatherHandler(service.NewWeatherService(locationRepo, &MockWeatherService{}))
+			r := mux.NewRouter()
+			r.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest("GET", tt.path, nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if tt.wantStatus == http.StatusUnprocessableEntity && rr.Body.String() != "{\"message\":\"invalid zipcode\"}\n" {
+				t.Errorf("Expected the invalid zipcode body, got %q", rr.Body.String())
+			}
+			if locationRepo.calls != tt.wantCalls {
+				t.Errorf("Expected %d ViaCEP lookups, got %d", tt.wantCalls, locationRepo.calls)
+			}
+		})
+	}
+}
+
 func TestWeatherEndpointCEPNotFound(t *testing.T) {
 	router := setupTestRouter()
 
@@ -174,6 +417,82 @@ func TestWeatherEndpointCEPNotFound(t *testing.T) {
 	}
 }
 
+// failingLocationRepo fails every CEP lookup with err, like ViaCEP when it is down
+type failingLocationRepo struct {
+	MockWeatherService
+	err error
+}
+
+func (m *failingLocationRepo) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	return nil, m.err
+}
+
+func TestWeatherEndpoint_ViaCEPFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{"not found", domain.ErrCEPNotFound, http.StatusNotFound, "can not find zipcode"},
+		{"transport error", fmt.Errorf("failed to fetch location data: %w", errors.New("connection refused")), http.StatusInternalServerError, "error fetching zipcode location"},
+		{"timeout", fmt.Errorf("failed to fetch location data: %w", context.DeadlineExceeded), http.StatusInternalServerError, "error fetching zipcode location"},
+		{"retries exhausted", errors.New("ViaCEP API returned status 502"), http.StatusInternalServerError, "error fetching zipcode location"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weatherHandler := handler.NewWeatherHandler(service.NewWeatherService(&failingLocationRepo{err: tt.err}, &MockWeatherService{}))
+			r := mux.NewRouter()
+			r.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest("GET", "/weather/01310100", nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			var response domain.ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal("Failed to unmarshal error response")
+			}
+			if response.Message != tt.wantMessage {
+				t.Errorf("Expected error message '%s', got '%s'", tt.wantMessage, response.Message)
+			}
+		})
+	}
+}
+
+func TestWeatherEndpointRateLimited(t *testing.T) {
+	router := setupTestRouter()
+
+	req, err := http.NewRequest("GET", "/weather/30112000", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusServiceUnavailable {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusServiceUnavailable)
+	}
+
+	// 1.5s is rounded up to whole seconds
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "2" {
+		t.Errorf("Expected Retry-After '2', got '%s'", retryAfter)
+	}
+
+	var response domain.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal("Failed to unmarshal error response")
+	}
+
+	if response.Message != service.ErrRateLimited.Error() {
+		t.Errorf("Expected error message '%s', got '%s'", service.ErrRateLimited.Error(), response.Message)
+	}
+}
+
 func TestWeatherEndpointWithSpecialCharacters(t *testing.T) {
 	// This test would have caught the URL encoding issue we fixed
 	router := setupTestRouter()
@@ -202,6 +521,537 @@ func TestWeatherEndpointWithSpecialCharacters(t *testing.T) {
 	}
 }
 
+func TestWeatherBatchEndpoint(t *testing.T) {
+	router := setupTestRouter()
+
+	req := httptest.NewRequest("POST", "/weather/batch?detail=full", strings.NewReader(`{"ceps": ["01310-100", "123", "99999999"]}`))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var response domain.BatchWeatherResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(response.Results))
+	}
+
+	ok := response.Results[0]
+	if ok.CEP != "01310-100" || ok.Status != http.StatusOK || ok.WeatherResponse == nil || ok.TempC != 28.5 {
+		t.Errorf("Expected the weather of 01310-100, got %+v", ok)
+	}
+	if ok.Condition == nil || ok.Condition.Code != "partly_cloudy" {
+		t.Errorf("Expected the condition with detail=full, got %+v", ok.Condition)
+	}
+
+	invalid := response.Results[1]
+	if invalid.Status != http.StatusUnprocessableEntity || invalid.Error != "invalid zipcode" || invalid.WeatherResponse != nil {
+		t.Errorf("Expected an invalid zipcode entry, got %+v", invalid)
+	}
+
+	notFound := response.Results[2]
+	if notFound.Status != http.StatusNotFound || notFound.Error != "can not find zipcode" {
+		t.Errorf("Expected a not found entry, got %+v", notFound)
+	}
+}
+
+func TestWeatherBatchEndpoint_OmitsConditionWithoutDetail(t *testing.T) {
+	router := setupTestRouter()
+
+	req := httptest.NewRequest("POST", "/weather/batch", strings.NewReader(`{"ceps": ["01310100"]}`))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if strings.Contains(rr.Body.String(), "condition") {
+		t.Errorf("Expected no condition without detail=full, got %s", rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `"temp_C":28.5`) {
+		t.Errorf("Expected temperatures in the result, got %s", rr.Body.String())
+	}
+}
+
+func TestWeatherBatchEndpoint_InvalidRequests(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"malformed body", `{"ceps": "01310100"}`, http.StatusBadRequest},
+		{"empty batch", `{"ceps": []}`, http.StatusUnprocessableEntity},
+		{"too many ceps", `{"ceps": ["01310100", "20040020", "30112000", "01310100"]}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/weather/batch", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestWeatherByCoordinatesEndpoint(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"success", "lat=-23.5505&lon=-46.6333", http.StatusOK, `"temp_C":28.5`},
+		{"detailed", "lat=-23.55&lon=-46.63&detail=full", http.StatusOK, `"code":"partly_cloudy"`},
+		{"missing lon", "lat=-23.55", http.StatusUnprocessableEntity, `"message":"invalid coordinates"`},
+		{"not a number", "lat=abc&lon=-46.63", http.StatusUnprocessableEntity, `"message":"invalid coordinates"`},
+		{"out of range", "lat=-91&lon=-46.63", http.StatusUnprocessableEntity, `"message":"invalid coordinates"`},
+		{"no data", "lat=10&lon=10", http.StatusNotFound, `"message":"can not find location"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather/coords?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %s", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestWeatherByIPEndpoint(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []struct {
+		name       string
+		forwarded  string
+		remoteAddr string
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"cloud run entry", "10.0.0.1, 200.160.2.3", "169.254.1.1:1234", "", http.StatusOK, `"city":"São Paulo","region":"São Paulo","country":"BR"`},
+		{"detailed", "200.160.2.3", "169.254.1.1:1234", "?detail=full", http.StatusOK, `"condition":{"code":"partly_cloudy"`},
+		{"forged first entry", "200.160.2.3, 10.0.0.1", "169.254.1.1:1234", "", http.StatusNotFound, `"message":"can not geolocate ip"`},
+		{"peer address", "", "200.160.2.3:1234", "", http.StatusOK, `"temp_C":28.5`},
+		{"unknown ip", "8.8.8.8", "169.254.1.1:1234", "", http.StatusNotFound, `"message":"can not geolocate ip"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather/me"+tt.query, nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %s", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestWeatherByIPEndpoint_GeolocationDisabled(t *testing.T) {
+	weatherService := service.NewWeatherService(&MockWeatherService{}, &MockWeatherService{})
+	r := mux.NewRouter()
+	r.HandleFunc("/weather/me", handler.NewWeatherHandler(weatherService).GetWeatherByIP).Methods("GET")
+
+	req := httptest.NewRequest("GET", "/weather/me", nil)
+	req.Header.Set("X-Forwarded-For", "200.160.2.3")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+}
+
+func TestStatsEndpoint(t *testing.T) {
+	store, err := history.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	recorder := history.NewAsyncRecorder(store, 100)
+
+	weatherService := service.NewWeatherService(&MockWeatherService{}, &MockWeatherService{}).WithRecorder(recorder)
+	weatherHandler := handler.NewWeatherHandler(weatherService)
+	r := mux.NewRouter()
+	r.HandleFunc("/weather", weatherHandler.GetWeatherByCity).Methods("GET")
+	r.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
+	r.HandleFunc("/stats", handler.NewStatsHandler(store).Stats).Methods("GET")
+
+	for _, path := range []string{"/weather/01310100", "/weather/01310-100", "/weather?city=S%C3%A3o+Paulo&uf=SP", "/weather/123"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	if err := recorder.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/stats?window=1h&top=5", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var stats domain.StatsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Lookups != 4 || stats.ErrorRate != 0.25 || stats.Outcomes["invalid_input"] != 1 {
+		t.Errorf("Expected 4 lookups with 1 invalid, got %+v", stats)
+	}
+	if len(stats.TopCities) != 1 || stats.TopCities[0].City != "São Paulo" || stats.TopCities[0].Lookups != 3 {
+		t.Errorf("Expected São Paulo looked up 3 times, got %+v", stats.TopCities)
+	}
+
+	for _, query := range []string{"window=forever", "window=1s", "top=0", "top=101"} {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", "/stats?"+query, nil))
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusUnprocessableEntity, rr.Code)
+		}
+	}
+}
+
+func TestWeatherEndpoint_Meta(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []struct {
+		name     string
+		path     string
+		accept   string
+		wantBody string
+		wantMeta bool
+	}{
+		{"omitted by default", "/weather/01310100", "", `"temp_C":28.5`, false},
+		{"meta=false", "/weather/01310100?meta=false", "", `"temp_C":28.5`, false},
+		{"cep", "/weather/01310100?meta=true", "", `"meta":{"provider":"weatherapi","fetched_at":"2025-01-01T12:00:00Z","cached":false}`, true},
+		{"detailed city", "/weather?city=S%C3%A3o+Paulo&uf=SP&detail=full&meta=1", "", `"meta":{"provider":"weatherapi"`, true},
+		{"xml", "/weather/01310100?meta=true", "application/xml", "<meta><provider>weatherapi</provider><fetched_at>2025-01-01T12:00:00Z</fetched_at><cached>false</cached></meta>", true},
+		{"csv", "/weather/01310100?meta=true", "text/csv", "temp_C,temp_F,temp_K,provider,fetched_at,cached\n28.5,", true},
+		{"csv without meta", "/weather/01310100", "text/csv", "temp_C,temp_F,temp_K\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			body := rr.Body.String()
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, body)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %s", tt.wantBody, body)
+			}
+			if hasMeta := strings.Contains(body, "provider"); hasMeta != tt.wantMeta {
+				t.Errorf("Expected metadata %v, got body %s", tt.wantMeta, body)
+			}
+		})
+	}
+}
+
+func TestWeatherEndpoint_Precision(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"raw by default", "/weather/01310100", http.StatusOK, `"temp_F":83.30000000000001`},
+		{"one place", "/weather/01310100?precision=1", http.StatusOK, `"temp_C":28.5,"temp_F":83.3,"temp_K":301.5`},
+		{"integers", "/weather/01310100?precision=0", http.StatusOK, `"temp_C":29,"temp_F":83,"temp_K":302`},
+		{"city", "/weather?city=S%C3%A3o+Paulo&uf=SP&precision=0", http.StatusOK, `"temp_C":29`},
+		{"batch", "/weather/batch?precision=0", http.StatusOK, `"temp_C":29`},
+		{"not a number", "/weather/01310100?precision=abc", http.StatusUnprocessableEntity, `"message":"invalid precision"`},
+		{"too many places", "/weather/01310100?precision=7", http.StatusUnprocessableEntity, `"message":"invalid precision"`},
+		{"invalid in batch", "/weather/batch?precision=-2", http.StatusUnprocessableEntity, `"message":"invalid precision"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if strings.HasPrefix(tt.path, "/weather/batch") {
+				req = httptest.NewRequest("POST", tt.path, strings.NewReader(`{"ceps": ["01310100"]}`))
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if body := rr.Body.String(); !strings.Contains(body, tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %s", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestWeatherEndpoint_Language(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"english by default", "/weather/01310100?detail=full", http.StatusOK, `"text":"Partly cloudy"`},
+		{"portuguese", "/weather/01310100?detail=full&lang=pt", http.StatusOK, `"code":"partly_cloudy","icon":"partly-cloudy-day","text":"Parcialmente nublado"`},
+		{"uppercase", "/weather/01310100?detail=full&lang=PT", http.StatusOK, `"text":"Parcialmente nublado"`},
+		{"city", "/weather?city=S%C3%A3o+Paulo&uf=SP&detail=full&lang=pt", http.StatusOK, `"text":"Parcialmente nublado"`},
+		{"coordinates", "/weather/coords?lat=-23.55&lon=-46.63&detail=full&lang=pt", http.StatusOK, `"text":"Parcialmente nublado"`},
+		{"batch", "/weather/batch?detail=full&lang=pt", http.StatusOK, `"text":"Parcialmente nublado"`},
+		{"unsupported", "/weather/01310100?lang=es", http.StatusUnprocessableEntity, `"message":"invalid lang"`},
+		{"unsupported in batch", "/weather/batch?lang=es", http.StatusUnprocessableEntity, `"message":"invalid lang"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if strings.HasPrefix(tt.path, "/weather/batch") {
+				req = httptest.NewRequest("POST", tt.path, strings.NewReader(`{"ceps": ["01310100"]}`))
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if body := rr.Body.String(); !strings.Contains(body, tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %s", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestWeatherEndpoint_CacheControl(t *testing.T) {
+	weatherService := service.NewWeatherService(&MockWeatherService{}, &MockWeatherService{}).WithIPLocator(&MockWeatherService{})
+	weatherHandler := handler.NewWeatherHandler(weatherService).WithCacheControl(5 * time.Minute)
+	r := mux.NewRouter()
+	r.HandleFunc("/weather/batch", weatherHandler.GetWeatherBatch).Methods("POST")
+	r.HandleFunc("/weather/me", weatherHandler.GetWeatherByIP).Methods("GET")
+	r.HandleFunc("/weather/{cep}", weatherHandler.GetWeatherByCEP).Methods("GET")
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		wantControl string
+		wantExpires bool
+	}{
+		{"success", "GET", "/weather/01310100", "", "public, max-age=300", true},
+		{"not found", "GET", "/weather/99999999", "", "no-store", false},
+		{"rate limited", "GET", "/weather/30112000", "", "no-store", false},
+		{"caller location", "GET", "/weather/me", "", "private, max-age=300", true},
+		{"batch", "POST", "/weather/batch", `{"ceps":["01310100"]}`, "no-store", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("X-Forwarded-For", "200.160.2.3")
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("Cache-Control"); got != tt.wantControl {
+				t.Errorf("Expected Cache-Control %q, got %q", tt.wantControl, got)
+			}
+			expires, err := http.ParseTime(rr.Header().Get("Expires"))
+			if tt.wantExpires && (err != nil || time.Until(expires) < 4*time.Minute) {
+				t.Errorf("Expected Expires about 5 minutes ahead, got %q", rr.Header().Get("Expires"))
+			}
+			if !tt.wantExpires && err == nil {
+				t.Errorf("Expected no Expires header, got %q", rr.Header().Get("Expires"))
+			}
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	setupTestRouter().ServeHTTP(rr, httptest.NewRequest("GET", "/weather/01310100", nil))
+	if got := rr.Header().Get("Cache-Control"); got != "" {
+		t.Errorf("Expected no Cache-Control without a max age, got %q", got)
+	}
+}
+
+func TestWeatherEndpointContentNegotiation(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []struct {
+		name            string
+		path            string
+		accept          string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{"default", "/weather/01310100", "", http.StatusOK, "application/json", `"temp_C":28.5`},
+		{"wildcard", "/weather/01310100", "*/*", http.StatusOK, "application/json", `"temp_C":28.5`},
+		{"xml", "/weather/01310100", "application/xml", http.StatusOK, "application/xml; charset=utf-8",
+			"<weather><temp_C>28.5</temp_C><temp_F>83.3"},
+		{"text/xml", "/weather/01310100?detail=full", "text/xml", http.StatusOK, "application/xml; charset=utf-8",
+			"<condition><code>partly_cloudy</code>"},
+		{"csv", "/weather/01310100", "text/csv", http.StatusOK, "text/csv; charset=utf-8", "temp_C,temp_F,temp_K\n28.5,83.3"},
+		{"csv detailed", "/weather/01310100?detail=full", "text/csv", http.StatusOK, "text/csv; charset=utf-8",
+			"condition_code,condition_icon,condition_text\n28.5,"},
+		{"q values", "/weather/01310100", "application/json;q=0.5, text/csv", http.StatusOK, "text/csv; charset=utf-8", "temp_C"},
+		{"unsupported falls back to json", "/weather/01310100", "text/html", http.StatusOK, "application/json", `"temp_C":28.5`},
+		{"xml error", "/weather/123", "application/xml", http.StatusUnprocessableEntity, "application/xml; charset=utf-8",
+			"<error><message>invalid zipcode</message></error>"},
+		{"csv error", "/weather/99999999", "text/csv", http.StatusNotFound, "text/csv; charset=utf-8", "message\ncan not find zipcode\n"},
+		{"city csv", "/weather?city=S%C3%A3o+Paulo&uf=SP", "text/csv", http.StatusOK, "text/csv; charset=utf-8", "temp_C,temp_F,temp_K\n28.5,"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if got := rr.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.wantContentType, got)
+			}
+			if rr.Header().Get("Vary") != "Accept" {
+				t.Errorf("Expected Vary: Accept, got %q", rr.Header().Get("Vary"))
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestWeatherBatchEndpoint_ContentNegotiation(t *testing.T) {
+	router := setupTestRouter()
+	body := `{"ceps": ["01310100", "99999999"]}`
+
+	req := httptest.NewRequest("POST", "/weather/batch?detail=full", strings.NewReader(body))
+	req.Header.Set("Accept", "text/csv")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if len(lines) != 3 || lines[0] != "cep,status,temp_C,temp_F,temp_K,condition_code,condition_icon,condition_text,error" {
+		t.Fatalf("Expected a header and 2 rows, got %q", rr.Body.String())
+	}
+	if !strings.HasPrefix(lines[1], "01310100,200,28.5,") || !strings.HasSuffix(lines[1], ",301.5,partly_cloudy,partly-cloudy-day,Partly cloudy,") {
+		t.Errorf("Expected the weather of 01310100, got %q", lines[1])
+	}
+	if lines[2] != "99999999,404,,,,,,,can not find zipcode" {
+		t.Errorf("Expected a not found row, got %q", lines[2])
+	}
+
+	req = httptest.NewRequest("POST", "/weather/batch", strings.NewReader(body))
+	req.Header.Set("Accept", "application/xml")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	for _, want := range []string{
+		"<batch><result><cep>01310100</cep><status>200</status><temp_C>28.5</temp_C>",
+		"<result><cep>99999999</cep><status>404</status><error>can not find zipcode</error></result></batch>",
+	} {
+		if !strings.Contains(rr.Body.String(), want) {
+			t.Errorf("Expected XML to contain %q, got %s", want, rr.Body.String())
+		}
+	}
+	if strings.Contains(rr.Body.String(), "condition") {
+		t.Errorf("Expected no condition without detail=full, got %s", rr.Body.String())
+	}
+}
+
+func TestWeatherByCityEndpoint(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"city and uf", "city=S%C3%A3o+Paulo&uf=SP", http.StatusOK, `"temp_C":28.5`},
+		{"detailed", "city=Rio+de+Janeiro&uf=rj&detail=full", http.StatusOK, `"code":"partly_cloudy"`},
+		{"missing city", "uf=SP", http.StatusUnprocessableEntity, `"message":"invalid city"`},
+		{"invalid city", "city=01310100", http.StatusUnprocessableEntity, `"message":"invalid city"`},
+		{"invalid uf", "city=Natal&uf=XX", http.StatusUnprocessableEntity, `"message":"invalid uf"`},
+		{"unknown city", "city=Atlantis", http.StatusNotFound, `"message":"can not find city"`},
+		{"rate limited", "city=Belo+Horizonte&uf=MG", http.StatusServiceUnavailable, `"message":"weather provider rate limit exceeded"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %s, got %s", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestWeatherEndpointCacheStatus(t *testing.T) {
+	lookupCache := cache.NewLRU(10)
+	weatherService := service.NewWeatherService(
+		repository.NewCachedLocationService(&MockWeatherService{}, lookupCache, time.Hour),
+		repository.NewCachedWeatherDataService(&MockWeatherService{}, lookupCache, time.Minute),
+	)
+	router := mux.NewRouter()
+	router.HandleFunc("/weather/{cep}", handler.NewWeatherHandler(weatherService).GetWeatherByCEP).Methods("GET")
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest("GET", "/weather/01310100", nil))
+	if header := first.Header().Get("Cache-Status"); header != "viacep; fwd=miss, weatherapi; fwd=miss" {
+		t.Errorf("Expected misses on first request, got %q", header)
+	}
+
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, httptest.NewRequest("GET", "/weather/01310100", nil))
+	if second.Code != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", second.Code, http.StatusOK)
+	}
+	header := second.Header().Get("Cache-Status")
+	if !strings.HasPrefix(header, "viacep; hit; ttl=") || !strings.Contains(header, "weatherapi; hit; ttl=") {
+		t.Errorf("Expected hits on second request, got %q", header)
+	}
+}
+
 func TestConfig(t *testing.T) {
 	cfg := config.New()
 
@@ -209,4 +1059,304 @@ func TestConfig(t *testing.T) {
 	if cfg.Port != "8080" {
 		t.Errorf("Expected default port to be '8080', got '%s'", cfg.Port)
 	}
+
+	// Test default logging
+	if cfg.LogFormat != "json" || cfg.LogLevel != "info" {
+		t.Errorf("Expected json logs at info level by default, got %s at %s", cfg.LogFormat, cfg.LogLevel)
+	}
+
+	// Test default weather provider failover order
+	if strings.Join(cfg.WeatherProviders, ",") != "weatherapi,openmeteo" {
+		t.Errorf("Expected providers 'weatherapi,openmeteo', got %v", cfg.WeatherProviders)
+	}
+
+	// Test default compression threshold
+	if cfg.GzipMinSize != 1024 {
+		t.Errorf("Expected GZIP_MIN_SIZE to default to 1024, got %d", cfg.GzipMinSize)
+	}
+}
+
+func TestConfigRetryPolicies(t *testing.T) {
+	t.Setenv("VIACEP_MAX_RETRIES", "4")
+	t.Setenv("VIACEP_RETRY_STATUS_CODES", "500, 502")
+	t.Setenv("VIACEP_ATTEMPT_TIMEOUT", "2s")
+
+	cfg := config.New()
+	if cfg.ViaCEPRetry.MaxRetries != 4 || cfg.ViaCEPRetry.AttemptTimeout != 2*time.Second {
+		t.Errorf("Expected 4 retries with 2s attempts, got %+v", cfg.ViaCEPRetry)
+	}
+	if fmt.Sprint(cfg.ViaCEPRetry.RetryStatusCodes) != "[500 502]" {
+		t.Errorf("Expected status codes [500 502], got %v", cfg.ViaCEPRetry.RetryStatusCodes)
+	}
+	if fmt.Sprint(cfg.WeatherRetry.RetryStatusCodes) != "[502 503 504]" {
+		t.Errorf("Expected default status codes [502 503 504], got %v", cfg.WeatherRetry.RetryStatusCodes)
+	}
+
+	t.Setenv("WEATHER_API_KEY", "key")
+	t.Setenv("WEATHER_RETRY_BACKOFF", "2s")
+	t.Setenv("WEATHER_RETRY_MAX_BACKOFF", "1s")
+	if err := config.New().Validate(); !errors.Is(err, config.ErrInvalidRetryPolicy) {
+		t.Errorf("Expected ErrInvalidRetryPolicy, got %v", err)
+	}
+}
+
+func TestConfigTimeouts(t *testing.T) {
+	cfg := config.New()
+	if cfg.ViaCEPTimeout != 10*time.Second || cfg.WeatherTimeout != 10*time.Second {
+		t.Errorf("Expected 10s lookup timeouts by default, got %v and %v", cfg.ViaCEPTimeout, cfg.WeatherTimeout)
+	}
+
+	t.Setenv("WEATHER_API_KEY", "key")
+	t.Setenv("VIACEP_TIMEOUT", "3s")
+	t.Setenv("WEATHER_TIMEOUT", "-1s")
+	cfg = config.New()
+	if cfg.ViaCEPTimeout != 3*time.Second {
+		t.Errorf("Expected VIACEP_TIMEOUT 3s, got %v", cfg.ViaCEPTimeout)
+	}
+	if err := cfg.Validate(); !errors.Is(err, config.ErrInvalidTimeout) {
+		t.Errorf("Expected ErrInvalidTimeout, got %v", err)
+	}
+}
+
+func TestConfigGeoIPProvider(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "key")
+	if cfg := config.New(); cfg.GeoIPProvider != repository.GeoIPProviderIPAPI {
+		t.Errorf("Expected ipapi by default, got %q", cfg.GeoIPProvider)
+	}
+
+	t.Setenv("GEOIP_PROVIDER", config.GeoIPProviderNone)
+	if err := config.New().Validate(); err != nil {
+		t.Errorf("Expected none to disable geolocation, got %v", err)
+	}
+
+	t.Setenv("GEOIP_PROVIDER", "maxmind")
+	if err := config.New().Validate(); !errors.Is(err, config.ErrUnknownGeoIPProvider) {
+		t.Errorf("Expected ErrUnknownGeoIPProvider, got %v", err)
+	}
+}
+
+func TestConfigHistory(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "key")
+	if cfg := config.New(); cfg.HistoryBackend != history.BackendNone {
+		t.Errorf("Expected the query history disabled by default, got %q", cfg.HistoryBackend)
+	}
+
+	t.Setenv("HISTORY_BACKEND", "firestore")
+	if err := config.New().Validate(); !errors.Is(err, config.ErrUnknownHistoryBackend) {
+		t.Errorf("Expected ErrUnknownHistoryBackend, got %v", err)
+	}
+
+	t.Setenv("HISTORY_BACKEND", history.BackendSQLite)
+	t.Setenv("HISTORY_BUFFER", "0")
+	if err := config.New().Validate(); !errors.Is(err, config.ErrInvalidHistoryBuffer) {
+		t.Errorf("Expected ErrInvalidHistoryBuffer, got %v", err)
+	}
+}
+
+func TestConfigTemperaturePrecision(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "key")
+	if cfg := config.New(); cfg.TemperaturePrecision != service.PrecisionRaw {
+		t.Errorf("Expected unrounded temperatures by default, got precision %d", cfg.TemperaturePrecision)
+	}
+
+	t.Setenv("TEMPERATURE_PRECISION", "1")
+	if err := config.New().Validate(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	t.Setenv("TEMPERATURE_PRECISION", "7")
+	if err := config.New().Validate(); !errors.Is(err, config.ErrInvalidTemperaturePrecision) {
+		t.Errorf("Expected ErrInvalidTemperaturePrecision, got %v", err)
+	}
+}
+
+func TestConfigWeatherLanguage(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "key")
+	if cfg := config.New(); cfg.WeatherLanguage != domain.LanguageEnglish {
+		t.Errorf("Expected English by default, got %q", cfg.WeatherLanguage)
+	}
+
+	t.Setenv("WEATHER_LANGUAGE", "PT")
+	if cfg := config.New(); cfg.Validate() != nil || cfg.WeatherLanguage != domain.LanguagePortuguese {
+		t.Errorf("Expected pt, got %q (%v)", cfg.WeatherLanguage, cfg.Validate())
+	}
+
+	t.Setenv("WEATHER_LANGUAGE", "es")
+	if err := config.New().Validate(); !errors.Is(err, config.ErrInvalidWeatherLanguage) {
+		t.Errorf("Expected ErrInvalidWeatherLanguage, got %v", err)
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys")
+	if err := os.WriteFile(path, []byte("file-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := loadAPIKeys(&config.Config{APIKeys: "env-key", APIKeysFile: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if keys.Len() != 2 || !keys.Valid("env-key") || !keys.Valid("file-key") {
+		t.Errorf("Expected the keys of API_KEYS and API_KEYS_FILE, got %d keys", keys.Len())
+	}
+
+	keys, err = loadAPIKeys(&config.Config{})
+	if err != nil || keys.Len() != 0 {
+		t.Errorf("Expected no keys without configuration, got %d keys and error %v", keys.Len(), err)
+	}
+
+	if _, err := loadAPIKeys(&config.Config{APIKeysFile: filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Error("Expected an error for a missing API_KEYS_FILE")
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	weatherService := service.NewWeatherService(&MockWeatherService{}, &MockWeatherService{})
+	store, err := history.OpenSQLite(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+
+	srv := server.New()
+	routes{
+		weather: handler.NewWeatherHandler(weatherService),
+		health:  handler.NewHealthHandler(nil, nil),
+		status:  handler.NewStatusHandler(status.NewErrorCounter(time.Minute)),
+		stats:   handler.NewStatsHandler(store),
+		auth:    []server.Middleware{apikey.New("secret").Middleware},
+	}.register(srv)
+
+	tests := []struct {
+		path       string
+		key        string
+		wantStatus int
+	}{
+		{"/weather/01310100", "", http.StatusUnauthorized},
+		{"/weather/01310100", "secret", http.StatusOK},
+		{"/weather?city=São+Paulo&uf=SP", "", http.StatusUnauthorized},
+		{"/stats", "", http.StatusUnauthorized},
+		{"/stats", "secret", http.StatusOK},
+		{"/health/live", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.key, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(apikey.Header, tt.key)
+			}
+			rr := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+type stubCache struct{}
+
+func (stubCache) CacheStats() status.CacheStats {
+	return status.CacheStats{Hits: 9, Misses: 1, Entries: 4}
+}
+
+func TestStatusEndpoint(t *testing.T) {
+	router := setupTestRouter()
+	errorCounter := status.NewErrorCounter(15 * time.Minute)
+	latency := status.NewLatencyRecorder()
+	statusHandler := handler.NewStatusHandler(errorCounter,
+		status.Check{Name: "ViaCEP", Probe: func(ctx context.Context) error { return nil }},
+		status.Check{Name: "WeatherAPI", Probe: func(ctx context.Context) error { return errors.New("weather API returned status 401") }},
+	).WithCache(stubCache{}).WithLatency(latency)
+	router.Use(errorCounter.Middleware, latency.Middleware)
+	router.HandleFunc("/status", statusHandler.Status).Methods("GET")
+
+	// Generate a recent error
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/weather/123", nil))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/status", nil))
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if contentType := rr.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/html") {
+		t.Errorf("Expected HTML content type, got %q", contentType)
+	}
+
+	body := rr.Body.String()
+	for _, expected := range []string{
+		"Há dependências com falha",
+		"ViaCEP",
+		"weather API returned status 401",
+		"90.0%",
+		"<td>422</td><td>1</td>",
+		"<td><code>GET /weather/{cep}</code></td><td>1</td>",
+		"Versão",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected status page to contain %q", expected)
+		}
+	}
+}
+
+// secretsAccessor serves secrets from a map
+type secretsAccessor map[string]string
+
+func (a secretsAccessor) Access(ctx context.Context, name string) (string, error) {
+	value, ok := a[name]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return value, nil
+}
+
+func TestConfigSecrets(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "")
+	t.Setenv("WEATHER_API_KEY_SECRET", "projects/p/secrets/weather-api-key")
+	accessor := secretsAccessor{"projects/p/secrets/weather-api-key": "secret-key"}
+
+	cfg := config.NewWithSecrets(context.Background(), accessor)
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected a valid configuration, got %v", err)
+	}
+	if cfg.WeatherAPIKey != "secret-key" {
+		t.Errorf("Expected the key from Secret Manager, got %q", cfg.WeatherAPIKey)
+	}
+	if len(cfg.Secrets) != 1 || cfg.Secrets[0].Env != "WEATHER_API_KEY" {
+		t.Errorf("Expected one WEATHER_API_KEY binding, got %+v", cfg.Secrets)
+	}
+
+	t.Setenv("WEATHER_API_KEY_SECRET", "projects/p/secrets/missing")
+	cfg = config.NewWithSecrets(context.Background(), accessor)
+	if err := cfg.Validate(); !errors.Is(err, config.ErrSecretAccess) {
+		t.Errorf("Expected ErrSecretAccess, got %v", err)
+	}
+}
+
+func TestApplySecret(t *testing.T) {
+	weatherAPI := repository.NewWeatherAPIRepository("old-key")
+	weatherData := repository.NewFailoverWeatherDataService(
+		repository.NamedWeatherProvider{Name: repository.ProviderWeatherAPI, Provider: weatherAPI},
+	)
+	cfg := &config.Config{APIKeys: "old-client"}
+	apiKeys, err := loadAPIKeys(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apply := applySecret(cfg, weatherData, apiKeys)
+	apply("API_KEYS", "new-client")
+
+	if apiKeys.Valid("old-client") || !apiKeys.Valid("new-client") {
+		t.Error("Expected API_KEYS rotation to replace the accepted keys")
+	}
+
+	apply("WEATHER_API_KEY", "new-key")
+	if cfg.WeatherAPIKey != "new-key" {
+		t.Errorf("Expected the config to hold the rotated key, got %q", cfg.WeatherAPIKey)
+	}
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+
+	"cloudrun/internal/handler"
+	"cloudrun/internal/server"
+
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+// routes holds the handlers of the API and the middleware guarding them
+type routes struct {
+	weather *handler.WeatherHandler
+	health  *handler.HealthHandler
+	status  *handler.StatusHandler
+	// stats is nil without a query history
+	stats *handler.StatsHandler
+	// auth checks the API key of the weather lookups and stats; empty leaves them open
+	auth []server.Middleware
+	// limit rate limits the weather lookups, after the key check so
+	// unauthenticated calls spend no tokens
+	limit []server.Middleware
+}
+
+// register adds the routes to s. Only the weather lookups spend provider quota
+// and are rate limited; fixed /weather paths come before /weather/{cep}.
+func (rt routes) register(s *server.Server) {
+	weather := s.Group("/weather", slices.Concat(rt.auth, rt.limit)...)
+	weather.HandleFunc("", rt.weather.GetWeatherByCity, http.MethodGet)
+	weather.HandleFunc("/batch", rt.weather.GetWeatherBatch, http.MethodPost)
+	weather.HandleFunc("/coords", rt.weather.GetWeatherByCoordinates, http.MethodGet)
+	weather.HandleFunc("/me", rt.weather.GetWeatherByIP, http.MethodGet)
+	weather.HandleFunc("/{cep}", rt.weather.GetWeatherByCEP, http.MethodGet)
+
+	s.HandleFunc("/health", rt.health.HealthCheck, http.MethodGet)
+	s.HandleFunc("/health/live", rt.health.Live, http.MethodGet)
+	s.HandleFunc("/health/ready", rt.health.Ready, http.MethodGet)
+	s.HandleFunc("/status", rt.status.Status, http.MethodGet)
+
+	// Stats expose what is being looked up, so they sit behind the same keys
+	if rt.stats != nil {
+		s.Group("", rt.auth...).HandleFunc("/stats", rt.stats.Stats, http.MethodGet)
+	}
+
+	// Swagger documentation
+	s.PathPrefix("/swagger/", httpSwagger.WrapHandler)
+}
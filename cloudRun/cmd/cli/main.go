@@ -0,0 +1,13 @@
+// Command cli prints the temperature of a CEP or city from the terminal,
+// going through the same lookup as `cloudrun lookup`
+package main
+
+import (
+	"os"
+
+	"cloudrun/internal/lookup"
+)
+
+func main() {
+	os.Exit(lookup.Main("cli", os.Args[1:]))
+}
@@ -1,20 +1,229 @@
 package config
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudrun/internal/cache"
+	"cloudrun/internal/compress"
+	"cloudrun/internal/domain"
+	"cloudrun/internal/handler"
+	"cloudrun/internal/history"
+	"cloudrun/internal/logging"
+	"cloudrun/internal/repository"
+	"cloudrun/internal/secrets"
+	"cloudrun/internal/service"
 )
 
+// secretEnvVars may be read from Secret Manager by setting <NAME>_SECRET to the
+// resource name of a secret, e.g. WEATHER_API_KEY_SECRET=projects/p/secrets/weather-api-key
+var secretEnvVars = []string{"WEATHER_API_KEY", "API_KEYS", "REDIS_URL", "GEOIP_TOKEN"}
+
+// GeoIPProviderNone disables /weather/me
+const GeoIPProviderNone = "none"
+
+// secretAccessTimeout bounds reading each secret at startup
+const secretAccessTimeout = 10 * time.Second
+
 // Config holds all configuration for the application
 type Config struct {
 	WeatherAPIKey string
-	Port          string
+	// WeatherProviders lists the weather providers in failover order: weatherapi and openmeteo.
+	// WEATHER_API_KEY is only required while weatherapi is listed.
+	WeatherProviders []string
+	Port             string
+	// ServiceName identifies the service in traces; Cloud Run sets K_SERVICE
+	ServiceName string
+	// ShutdownTimeout bounds how long in-flight requests may drain after SIGTERM;
+	// Cloud Run kills the instance 10s after sending it
+	ShutdownTimeout time.Duration
+
+	// TemperaturePrecision is the decimal places of temp_C, temp_F and temp_K, from 0 to 6;
+	// -1 keeps them unrounded. Requests may override it with ?precision=.
+	TemperaturePrecision int
+	// WeatherLanguage is the language of the condition text, en or pt. Requests may
+	// override it with ?lang=.
+	WeatherLanguage string
+
+	// GeoIPProvider geolocates callers of /weather/me: ipapi, ipinfo or none to disable it
+	GeoIPProvider string
+	// GeoIPToken is the optional ipinfo token; without it the anonymous quota applies
+	GeoIPToken string
+
+	// CacheBackend stores ViaCEP and WeatherAPI lookups: memory, redis or none
+	CacheBackend string
+	// CacheSize bounds the entries of the memory backend
+	CacheSize int
+	// RedisURL points to the redis backend, e.g. a Memorystore instance
+	RedisURL        string
+	CacheCEPTTL     time.Duration
+	CacheWeatherTTL time.Duration
+	// CacheControlMaxAge is how long CDNs and browsers may keep a successful lookup; 0 disables it
+	CacheControlMaxAge time.Duration
+
+	// HistoryBackend records every lookup for GET /stats: sqlite or none
+	HistoryBackend string
+	// HistoryPath is the SQLite database file of the query history
+	HistoryPath string
+	// HistoryBuffer bounds the lookups waiting to be written; more are dropped
+	HistoryBuffer int
+
+	// RateLimitRPM is the requests per minute allowed per client IP on /weather; 0 disables it
+	RateLimitRPM int
+	// RateLimitBurst is how many requests a client IP may make at once
+	RateLimitBurst int
+
+	// ViaCEPTimeout bounds each ViaCEP lookup, retries included; 0 leaves it to the incoming request
+	ViaCEPTimeout time.Duration
+	// WeatherTimeout bounds each weather provider lookup, retries included; 0 leaves it to the incoming request
+	WeatherTimeout time.Duration
+	// GeoIPTimeout bounds each IP geolocation, retries included; 0 leaves it to the incoming request
+	GeoIPTimeout time.Duration
+
+	// ViaCEPRetry is the retry policy of ViaCEP lookups (VIACEP_* variables)
+	ViaCEPRetry repository.RetryPolicy
+	// WeatherRetry is the retry policy of every weather provider (WEATHER_* variables)
+	WeatherRetry repository.RetryPolicy
+	// GeoIPRetry is the retry policy of the geolocation provider (GEOIP_* variables)
+	GeoIPRetry repository.RetryPolicy
+
+	// BatchMaxCEPs is the most CEPs accepted by POST /weather/batch
+	BatchMaxCEPs int
+	// BatchWorkers bounds the concurrent lookups of one batch
+	BatchWorkers int
+
+	// GzipMinSize is the smallest response body gzipped; negative disables compression
+	GzipMinSize int
+
+	// CORSAllowedOrigins lists the browser origins allowed to call the API, or * for any;
+	// empty sends no CORS headers
+	CORSAllowedOrigins []string
+
+	// APIKeys lists the keys accepted in X-API-Key, separated by commas
+	APIKeys string
+	// APIKeysFile holds one key per line, e.g. a Secret Manager secret mounted as a volume.
+	// Without keys in APIKeys or APIKeysFile the API is open.
+	APIKeysFile string
+
+	// LogFormat is json (Cloud Logging structured entries) or text for local runs
+	LogFormat string
+	// LogLevel is the minimum level logged: debug, info, warn or error
+	LogLevel string
+
+	// Secrets lists the variables read from Secret Manager, with their current values
+	Secrets []secrets.Binding
+	// SecretRefreshInterval is how often the secrets are read again; 0 disables the refresh
+	SecretRefreshInterval time.Duration
+	// secretErr is the first secret that could not be read, reported by Validate
+	secretErr error
 }
 
-// New creates a new configuration instance
+// New creates a new configuration instance, reading the <NAME>_SECRET variables from Secret Manager
 func New() *Config {
-	return &Config{
+	return NewWithSecrets(context.Background(), secrets.NewSecretManager())
+}
+
+// NewWithSecrets creates a new configuration instance, reading the <NAME>_SECRET variables through accessor
+func NewWithSecrets(ctx context.Context, accessor secrets.Accessor) *Config {
+	c := &Config{
 		WeatherAPIKey: getEnv("WEATHER_API_KEY", ""),
-		Port:          getEnv("PORT", "8080"),
+		WeatherProviders: getEnvList("WEATHER_PROVIDERS",
+			[]string{repository.ProviderWeatherAPI, repository.ProviderOpenMeteo}),
+		Port:        getEnv("PORT", "8080"),
+		ServiceName: getEnv("K_SERVICE", "weather-api"),
+
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 8*time.Second),
+
+		TemperaturePrecision: getEnvInt("TEMPERATURE_PRECISION", service.PrecisionRaw),
+		WeatherLanguage:      strings.ToLower(getEnv("WEATHER_LANGUAGE", domain.LanguageEnglish)),
+
+		GeoIPProvider: getEnv("GEOIP_PROVIDER", repository.GeoIPProviderIPAPI),
+		GeoIPToken:    getEnv("GEOIP_TOKEN", ""),
+
+		CacheBackend:    getEnv("CACHE_BACKEND", cache.BackendMemory),
+		CacheSize:       getEnvInt("CACHE_SIZE", 10000),
+		RedisURL:        getEnv("REDIS_URL", ""),
+		CacheCEPTTL:     getEnvDuration("CACHE_CEP_TTL", 24*time.Hour),
+		CacheWeatherTTL: getEnvDuration("CACHE_WEATHER_TTL", 5*time.Minute),
+
+		CacheControlMaxAge: getEnvDuration("CACHE_CONTROL_MAX_AGE", 0),
+
+		HistoryBackend: getEnv("HISTORY_BACKEND", history.BackendNone),
+		HistoryPath:    getEnv("HISTORY_PATH", "history.db"),
+		HistoryBuffer:  getEnvInt("HISTORY_BUFFER", 1000),
+
+		RateLimitRPM:   getEnvInt("RATE_LIMIT_RPM", 60),
+		RateLimitBurst: getEnvInt("RATE_LIMIT_BURST", 10),
+
+		ViaCEPTimeout:  getEnvDuration("VIACEP_TIMEOUT", repository.DefaultTimeout),
+		WeatherTimeout: getEnvDuration("WEATHER_TIMEOUT", repository.DefaultTimeout),
+		GeoIPTimeout:   getEnvDuration("GEOIP_TIMEOUT", repository.DefaultTimeout),
+
+		ViaCEPRetry:  getEnvRetryPolicy("VIACEP", repository.DefaultRetryPolicy),
+		WeatherRetry: getEnvRetryPolicy("WEATHER", repository.DefaultRetryPolicy),
+		GeoIPRetry:   getEnvRetryPolicy("GEOIP", repository.DefaultRetryPolicy),
+
+		BatchMaxCEPs: getEnvInt("BATCH_MAX_CEPS", handler.DefaultBatchMaxCEPs),
+		BatchWorkers: getEnvInt("BATCH_WORKERS", handler.DefaultBatchWorkers),
+
+		GzipMinSize: getEnvInt("GZIP_MIN_SIZE", compress.DefaultMinSize),
+
+		CORSAllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS", nil),
+
+		APIKeys:     getEnv("API_KEYS", ""),
+		APIKeysFile: getEnv("API_KEYS_FILE", ""),
+
+		LogFormat: getEnv("LOG_FORMAT", logging.FormatJSON),
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
+
+		SecretRefreshInterval: getEnvDuration("SECRET_REFRESH_INTERVAL", 10*time.Minute),
+	}
+	c.loadSecrets(ctx, accessor)
+	return c
+}
+
+// loadSecrets replaces each variable that has a <NAME>_SECRET with the secret payload
+func (c *Config) loadSecrets(ctx context.Context, accessor secrets.Accessor) {
+	for _, env := range secretEnvVars {
+		name := os.Getenv(env + "_SECRET")
+		if name == "" {
+			continue
+		}
+
+		accessCtx, cancel := context.WithTimeout(ctx, secretAccessTimeout)
+		value, err := accessor.Access(accessCtx, name)
+		cancel()
+		if err != nil {
+			if c.secretErr == nil {
+				c.secretErr = fmt.Errorf("%w: %s_SECRET: %v", ErrSecretAccess, env, err)
+			}
+			continue
+		}
+		if os.Getenv(env) != "" {
+			slog.Warn("Both a variable and its secret are set, using the secret", "env", env, "secret", name)
+		}
+
+		c.SetSecret(env, value)
+		c.Secrets = append(c.Secrets, secrets.Binding{Env: env, Name: name, Value: value})
+	}
+}
+
+// SetSecret sets the field configured by the secret-capable variable env
+func (c *Config) SetSecret(env, value string) {
+	switch env {
+	case "WEATHER_API_KEY":
+		c.WeatherAPIKey = value
+	case "API_KEYS":
+		c.APIKeys = value
+	case "REDIS_URL":
+		c.RedisURL = value
+	case "GEOIP_TOKEN":
+		c.GeoIPToken = value
 	}
 }
 
@@ -26,10 +235,163 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvDuration gets a duration environment variable or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("Invalid environment variable, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("Invalid environment variable, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvList gets a comma-separated, case-insensitive list or returns a default value
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvIntList gets a comma-separated list of integers or returns a default value
+func getEnvIntList(key string, defaultValue []int) []int {
+	var list []int
+	for _, item := range getEnvList(key, nil) {
+		parsed, err := strconv.Atoi(item)
+		if err != nil {
+			slog.Warn("Invalid environment variable, using default", "key", key, "value", os.Getenv(key), "default", defaultValue)
+			return defaultValue
+		}
+		list = append(list, parsed)
+	}
+	if list == nil {
+		return defaultValue
+	}
+	return list
+}
+
+// getEnvRetryPolicy reads <prefix>_MAX_RETRIES, <prefix>_RETRY_BACKOFF, <prefix>_RETRY_MAX_BACKOFF,
+// <prefix>_RETRY_STATUS_CODES and <prefix>_ATTEMPT_TIMEOUT over a default policy
+func getEnvRetryPolicy(prefix string, defaultValue repository.RetryPolicy) repository.RetryPolicy {
+	return repository.RetryPolicy{
+		MaxRetries:       getEnvInt(prefix+"_MAX_RETRIES", defaultValue.MaxRetries),
+		BaseDelay:        getEnvDuration(prefix+"_RETRY_BACKOFF", defaultValue.BaseDelay),
+		MaxDelay:         getEnvDuration(prefix+"_RETRY_MAX_BACKOFF", defaultValue.MaxDelay),
+		RetryStatusCodes: getEnvIntList(prefix+"_RETRY_STATUS_CODES", defaultValue.RetryStatusCodes),
+		AttemptTimeout:   getEnvDuration(prefix+"_ATTEMPT_TIMEOUT", defaultValue.AttemptTimeout),
+	}
+}
+
+// validRetryPolicy rejects negative settings and status codes that are not HTTP errors
+func validRetryPolicy(policy repository.RetryPolicy) bool {
+	if policy.MaxRetries < 0 || policy.BaseDelay < 0 || policy.MaxDelay < policy.BaseDelay || policy.AttemptTimeout < 0 {
+		return false
+	}
+	for _, code := range policy.RetryStatusCodes {
+		if code < 400 || code > 599 {
+			return false
+		}
+	}
+	return true
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.WeatherAPIKey == "" {
-		return ErrMissingWeatherAPIKey
+	if c.secretErr != nil {
+		return c.secretErr
+	}
+	if len(c.WeatherProviders) == 0 {
+		return ErrNoWeatherProviders
+	}
+	for _, name := range c.WeatherProviders {
+		switch name {
+		case repository.ProviderWeatherAPI:
+			if c.WeatherAPIKey == "" {
+				return ErrMissingWeatherAPIKey
+			}
+		case repository.ProviderOpenMeteo:
+		default:
+			return ErrUnknownWeatherProvider
+		}
+	}
+	switch c.GeoIPProvider {
+	case repository.GeoIPProviderIPAPI, repository.GeoIPProviderIPInfo, GeoIPProviderNone:
+	default:
+		return ErrUnknownGeoIPProvider
+	}
+	switch c.CacheBackend {
+	case cache.BackendMemory:
+		if c.CacheSize <= 0 {
+			return ErrInvalidCacheSize
+		}
+	case cache.BackendRedis:
+		if c.RedisURL == "" {
+			return ErrMissingRedisURL
+		}
+	case cache.BackendNone:
+	default:
+		return ErrUnknownCacheBackend
+	}
+	if !service.ValidPrecision(c.TemperaturePrecision) {
+		return ErrInvalidTemperaturePrecision
+	}
+	if !domain.ValidLanguage(c.WeatherLanguage) {
+		return ErrInvalidWeatherLanguage
+	}
+	if c.CacheControlMaxAge < 0 {
+		return ErrInvalidCacheControlMaxAge
+	}
+	switch c.HistoryBackend {
+	case history.BackendSQLite:
+		if c.HistoryBuffer <= 0 {
+			return ErrInvalidHistoryBuffer
+		}
+	case history.BackendNone:
+	default:
+		return ErrUnknownHistoryBackend
+	}
+	if c.RateLimitRPM < 0 || (c.RateLimitRPM > 0 && c.RateLimitBurst <= 0) {
+		return ErrInvalidRateLimit
+	}
+	if c.ViaCEPTimeout < 0 || c.WeatherTimeout < 0 || c.GeoIPTimeout < 0 {
+		return ErrInvalidTimeout
+	}
+	if !validRetryPolicy(c.ViaCEPRetry) || !validRetryPolicy(c.WeatherRetry) || !validRetryPolicy(c.GeoIPRetry) {
+		return ErrInvalidRetryPolicy
+	}
+	if c.BatchMaxCEPs <= 0 || c.BatchWorkers <= 0 {
+		return ErrInvalidBatchLimits
+	}
+	if c.LogFormat != logging.FormatJSON && c.LogFormat != logging.FormatText {
+		return ErrUnknownLogFormat
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		return ErrInvalidLogLevel
 	}
 	return nil
 }
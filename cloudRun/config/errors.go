@@ -4,5 +4,61 @@ import "errors"
 
 var (
 	// ErrMissingWeatherAPIKey is returned when the weather API key is not configured
-	ErrMissingWeatherAPIKey = errors.New("WEATHER_API_KEY environment variable is required")
+	ErrMissingWeatherAPIKey = errors.New("WEATHER_API_KEY environment variable is required when WEATHER_PROVIDERS includes weatherapi")
+
+	// ErrSecretAccess is returned when a <NAME>_SECRET variable names a secret that cannot be read
+	ErrSecretAccess = errors.New("failed to read secret from Secret Manager")
+
+	// ErrNoWeatherProviders is returned when WEATHER_PROVIDERS lists no provider
+	ErrNoWeatherProviders = errors.New("WEATHER_PROVIDERS must list at least one provider")
+
+	// ErrUnknownWeatherProvider is returned when WEATHER_PROVIDERS has a name other than weatherapi or openmeteo
+	ErrUnknownWeatherProvider = errors.New("WEATHER_PROVIDERS must only contain weatherapi or openmeteo")
+
+	// ErrInvalidTemperaturePrecision is returned when TEMPERATURE_PRECISION is not -1 to 6
+	ErrInvalidTemperaturePrecision = errors.New("TEMPERATURE_PRECISION must be -1 (unrounded) to 6")
+
+	// ErrInvalidWeatherLanguage is returned when WEATHER_LANGUAGE is not en or pt
+	ErrInvalidWeatherLanguage = errors.New("WEATHER_LANGUAGE must be en or pt")
+
+	// ErrUnknownGeoIPProvider is returned when GEOIP_PROVIDER is not ipapi, ipinfo or none
+	ErrUnknownGeoIPProvider = errors.New("GEOIP_PROVIDER must be ipapi, ipinfo or none")
+
+	// ErrUnknownCacheBackend is returned when CACHE_BACKEND is not memory, redis or none
+	ErrUnknownCacheBackend = errors.New("CACHE_BACKEND must be memory, redis or none")
+
+	// ErrInvalidCacheControlMaxAge is returned when CACHE_CONTROL_MAX_AGE is negative
+	ErrInvalidCacheControlMaxAge = errors.New("CACHE_CONTROL_MAX_AGE must not be negative")
+
+	// ErrUnknownHistoryBackend is returned when HISTORY_BACKEND is not sqlite or none
+	ErrUnknownHistoryBackend = errors.New("HISTORY_BACKEND must be sqlite or none")
+
+	// ErrInvalidHistoryBuffer is returned when HISTORY_BUFFER is not positive
+	ErrInvalidHistoryBuffer = errors.New("HISTORY_BUFFER must be positive")
+
+	// ErrInvalidCacheSize is returned when CACHE_SIZE is not positive
+	ErrInvalidCacheSize = errors.New("CACHE_SIZE must be positive")
+
+	// ErrMissingRedisURL is returned when CACHE_BACKEND=redis is set without REDIS_URL
+	ErrMissingRedisURL = errors.New("REDIS_URL is required when CACHE_BACKEND is redis")
+
+	// ErrInvalidRateLimit is returned when RATE_LIMIT_RPM or RATE_LIMIT_BURST is negative,
+	// or the burst is 0 while the limit is enabled
+	ErrInvalidRateLimit = errors.New("RATE_LIMIT_RPM must not be negative and RATE_LIMIT_BURST must be positive")
+
+	// ErrInvalidTimeout is returned when VIACEP_TIMEOUT, WEATHER_TIMEOUT or GEOIP_TIMEOUT is negative
+	ErrInvalidTimeout = errors.New("VIACEP_TIMEOUT, WEATHER_TIMEOUT and GEOIP_TIMEOUT must not be negative")
+
+	// ErrInvalidRetryPolicy is returned when a VIACEP_*, WEATHER_* or GEOIP_* retry setting is negative,
+	// the max backoff is below the backoff or a retry status code is not 4xx or 5xx
+	ErrInvalidRetryPolicy = errors.New("retry settings must not be negative, the max backoff must not be below the backoff and status codes must be 4xx or 5xx")
+
+	// ErrInvalidBatchLimits is returned when BATCH_MAX_CEPS or BATCH_WORKERS is not positive
+	ErrInvalidBatchLimits = errors.New("BATCH_MAX_CEPS and BATCH_WORKERS must be positive")
+
+	// ErrUnknownLogFormat is returned when LOG_FORMAT is not json or text
+	ErrUnknownLogFormat = errors.New("LOG_FORMAT must be json or text")
+
+	// ErrInvalidLogLevel is returned when LOG_LEVEL is not debug, info, warn or error
+	ErrInvalidLogLevel = errors.New("LOG_LEVEL must be debug, info, warn or error")
 )
@@ -25,7 +25,7 @@ const docTemplate = `{
     "paths": {
         "/health": {
             "get": {
-                "description": "Verifica se a aplicação está funcionando",
+                "description": "Verifica se a aplicação está funcionando (mantido por compatibilidade, equivale a /health/live)",
                 "produces": [
                     "text/plain"
                 ],
@@ -43,15 +43,632 @@ const docTemplate = `{
                 }
             }
         },
-        "/weather/{cep}": {
+        "/health/live": {
+            "get": {
+                "description": "Responde enquanto o processo está de pé, sem consultar dependências",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.LivenessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Verifica se ViaCEP e os provedores de clima estão acessíveis e se a chave da WeatherAPI é válida. O resultado é reaproveitado por 10 segundos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReadinessResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReadinessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Agrega o histórico de consultas (HISTORY_BACKEND): cidades mais consultadas, taxa de erro e latência média",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Estatísticas de uso",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "168h",
+                        "description": "Janela de tempo, de 1m a 2160h (padrão: 24h)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade de cidades, de 1 a 100 (padrão: 10)",
+                        "name": "top",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estatísticas da janela",
+                        "schema": {
+                            "$ref": "#/definitions/domain.StatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "window ou top inválido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro ao ler o histórico",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "description": "Página HTML com a saúde das dependências (checadas a cada acesso), estatísticas de cache, latência por rota, versão/commit e contagem de erros recentes",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Página de status",
+                "responses": {
+                    "200": {
+                        "description": "Página de status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Consulta a WeatherAPI diretamente, sem passar pelo ViaCEP, para clientes que já conhecem a cidade\nSem uf, a busca é restrita ao Brasil. Com detail=full, inclui a condição do tempo normalizada",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/csv"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Obter temperatura por cidade",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"São Paulo\"",
+                        "description": "Nome da cidade",
+                        "name": "city",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"SP\"",
+                        "description": "Sigla do estado",
+                        "name": "uf",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full"
+                        ],
+                        "type": "string",
+                        "description": "Modo de resposta",
+                        "name": "detail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui em meta o provedor, o momento da consulta e se veio do cache",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 6,
+                        "minimum": -1,
+                        "type": "integer",
+                        "description": "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pt",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Informações de temperatura (condition apenas com detail=full)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DetailedWeatherResponse"
+                        },
+                        "headers": {
+                            "Cache-Status": {
+                                "type": "string",
+                                "description": "Caches consultados, ex.: weatherapi; hit; ttl=240"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cidade não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Cidade, UF, precision ou lang inválido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições por IP atingido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos a aguardar antes de tentar novamente"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Limite de requisições da WeatherAPI atingido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos a aguardar antes de tentar novamente"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Consulta até BATCH_MAX_CEPS CEPs (padrão 50) em paralelo e retorna um resultado por CEP, na ordem da requisição\nCada resultado traz o status HTTP que a consulta individual teria; erros de um CEP não afetam os demais\nCom detail=full, inclui a condição do tempo normalizada",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/csv"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Obter temperatura de vários CEPs",
+                "parameters": [
+                    {
+                        "description": "CEPs a consultar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.BatchWeatherRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "full"
+                        ],
+                        "type": "string",
+                        "description": "Modo de resposta",
+                        "name": "detail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui em meta o provedor, o momento da consulta e se veio do cache",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 6,
+                        "minimum": -1,
+                        "type": "integer",
+                        "description": "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pt",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultados por CEP",
+                        "schema": {
+                            "$ref": "#/definitions/domain.BatchWeatherResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo inválido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Lote vazio, com CEPs demais, ou precision ou lang inválido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições por IP atingido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/coords": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Consulta o clima de um ponto (latitude e longitude em graus decimais), para clientes móveis com GPS mas sem CEP\nAs coordenadas são arredondadas para 0,01° (cerca de 1 km). Com detail=full, inclui a condição do tempo normalizada",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/csv"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Obter temperatura por coordenadas",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "example": -23.5505,
+                        "description": "Latitude, de -90 a 90",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "example": -46.6333,
+                        "description": "Longitude, de -180 a 180",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "full"
+                        ],
+                        "type": "string",
+                        "description": "Modo de resposta",
+                        "name": "detail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui em meta o provedor, o momento da consulta e se veio do cache",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 6,
+                        "minimum": -1,
+                        "type": "integer",
+                        "description": "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pt",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Informações de temperatura (condition apenas com detail=full)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DetailedWeatherResponse"
+                        },
+                        "headers": {
+                            "Cache-Status": {
+                                "type": "string",
+                                "description": "Caches consultados, ex.: weatherapi; hit; ttl=240"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Sem dados de clima para o ponto",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Coordenadas ausentes ou fora do intervalo, ou precision ou lang inválido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições por IP atingido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos a aguardar antes de tentar novamente"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Limite de requisições da WeatherAPI atingido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos a aguardar antes de tentar novamente"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/me": {
             "get": {
-                "description": "Recebe um CEP brasileiro válido e retorna a temperatura atual em Celsius, Fahrenheit e Kelvin",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Geolocaliza o IP de quem chama (último endereço do X-Forwarded-For, adicionado pelo Cloud Run) e retorna a temperatura do local aproximado\nCom detail=full, inclui a condição do tempo normalizada",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/csv"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Obter temperatura pelo IP do cliente",
+                "parameters": [
+                    {
+                        "enum": [
+                            "full"
+                        ],
+                        "type": "string",
+                        "description": "Modo de resposta",
+                        "name": "detail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui em meta o provedor, o momento da consulta e se veio do cache",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 6,
+                        "minimum": -1,
+                        "type": "integer",
+                        "description": "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pt",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Temperatura e local aproximado (condition apenas com detail=full)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.IPWeatherResponse"
+                        },
+                        "headers": {
+                            "Cache-Status": {
+                                "type": "string",
+                                "description": "Caches consultados, ex.: weatherapi; hit; ttl=240"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "IP privado ou desconhecido pelo provedor de geolocalização",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "precision ou lang inválido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições por IP atingido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos a aguardar antes de tentar novamente"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Limite de requisições da WeatherAPI atingido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos a aguardar antes de tentar novamente"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/{cep}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Recebe um CEP brasileiro válido e retorna a temperatura atual em Celsius, Fahrenheit e Kelvin\nCom detail=full, inclui a condição do tempo normalizada (enum estável e identificador de ícone)",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/csv"
+                ],
                 "tags": [
                     "weather"
                 ],
@@ -64,13 +681,58 @@ const docTemplate = `{
                         "name": "cep",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "full"
+                        ],
+                        "type": "string",
+                        "description": "Modo de resposta",
+                        "name": "detail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui em meta o provedor, o momento da consulta e se veio do cache",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 6,
+                        "minimum": -1,
+                        "type": "integer",
+                        "description": "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pt",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)",
+                        "name": "lang",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Informações de temperatura",
+                        "description": "Informações de temperatura (condition apenas com detail=full)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DetailedWeatherResponse"
+                        },
+                        "headers": {
+                            "Cache-Status": {
+                                "type": "string",
+                                "description": "Caches consultados, ex.: viacep; hit; ttl=86100, weatherapi; fwd=miss"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key ausente ou inválida",
                         "schema": {
-                            "$ref": "#/definitions/domain.WeatherResponse"
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
                     "404": {
@@ -80,9 +742,21 @@ const docTemplate = `{
                         }
                     },
                     "422": {
-                        "description": "CEP inválido",
+                        "description": "CEP, precision ou lang inválido",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições por IP atingido (ver header Retry-After)",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos a aguardar antes de tentar novamente"
+                            }
                         }
                     },
                     "500": {
@@ -90,12 +764,160 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Limite de requisições da WeatherAPI atingido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos a aguardar antes de tentar novamente"
+                            }
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "domain.BatchWeatherRequest": {
+            "description": "Lista de CEPs a consultar",
+            "type": "object",
+            "properties": {
+                "ceps": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "01310100",
+                        "20040020"
+                    ]
+                }
+            }
+        },
+        "domain.BatchWeatherResponse": {
+            "description": "Um resultado por CEP, na ordem da requisição",
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BatchWeatherResult"
+                    }
+                }
+            }
+        },
+        "domain.BatchWeatherResult": {
+            "description": "Temperaturas do CEP ou a mensagem de erro, com o status HTTP que a consulta individual teria",
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310100"
+                },
+                "condition": {
+                    "$ref": "#/definitions/domain.WeatherCondition"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "can not find zipcode"
+                },
+                "meta": {
+                    "$ref": "#/definitions/domain.ResponseMeta"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "temp_C": {
+                    "type": "number",
+                    "example": 28.5
+                },
+                "temp_F": {
+                    "type": "number",
+                    "example": 83.3
+                },
+                "temp_K": {
+                    "type": "number",
+                    "example": 301.5
+                }
+            }
+        },
+        "domain.CityStats": {
+            "description": "Consultas de uma cidade e a temperatura média das que tiveram sucesso",
+            "type": "object",
+            "properties": {
+                "avg_temp_C": {
+                    "type": "number",
+                    "example": 24.7
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "lookups": {
+                    "type": "integer",
+                    "example": 312
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "domain.DependencyStatus": {
+            "description": "Resultado da checagem de uma dependência externa",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "weather API rejected the API key (status 401)"
+                },
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ViaCEP"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "down"
+                    ],
+                    "example": "up"
+                }
+            }
+        },
+        "domain.DetailedWeatherResponse": {
+            "description": "Temperaturas acrescidas da condição do tempo normalizada",
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "$ref": "#/definitions/domain.WeatherCondition"
+                },
+                "meta": {
+                    "$ref": "#/definitions/domain.ResponseMeta"
+                },
+                "temp_C": {
+                    "type": "number",
+                    "example": 28.5
+                },
+                "temp_F": {
+                    "type": "number",
+                    "example": 83.3
+                },
+                "temp_K": {
+                    "type": "number",
+                    "example": 301.5
+                }
+            }
+        },
         "domain.ErrorResponse": {
             "description": "Resposta de erro da API",
             "type": "object",
@@ -106,10 +928,28 @@ const docTemplate = `{
                 }
             }
         },
-        "domain.WeatherResponse": {
-            "description": "Resposta contendo a temperatura em Celsius, Fahrenheit e Kelvin",
+        "domain.IPWeatherResponse": {
+            "description": "Temperaturas no local aproximado do IP do cliente",
             "type": "object",
             "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "condition": {
+                    "$ref": "#/definitions/domain.WeatherCondition"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "BR"
+                },
+                "meta": {
+                    "$ref": "#/definitions/domain.ResponseMeta"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
                 "temp_C": {
                     "type": "number",
                     "example": 28.5
@@ -123,6 +963,114 @@ const docTemplate = `{
                     "example": 301.5
                 }
             }
+        },
+        "domain.LivenessResponse": {
+            "description": "O processo está de pé",
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "domain.ReadinessResponse": {
+            "description": "Pronto quando ViaCEP e ao menos um provedor de clima respondem",
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DependencyStatus"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "down"
+                    ],
+                    "example": "up"
+                }
+            }
+        },
+        "domain.ResponseMeta": {
+            "description": "Provedor que respondeu, momento da consulta e se os dados vieram do cache",
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "fetched_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "weatherapi"
+                }
+            }
+        },
+        "domain.StatsResponse": {
+            "description": "Estatísticas das consultas de clima feitas na janela pedida",
+            "type": "object",
+            "properties": {
+                "avg_latency_ms": {
+                    "type": "number",
+                    "example": 182.4
+                },
+                "error_rate": {
+                    "type": "number",
+                    "example": 0.031
+                },
+                "lookups": {
+                    "type": "integer",
+                    "example": 1520
+                },
+                "outcomes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "since": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                },
+                "top_cities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CityStats"
+                    }
+                }
+            }
+        },
+        "domain.WeatherCondition": {
+            "description": "Condição do tempo em um enum estável, independente do provedor",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "partly_cloudy"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "partly-cloudy-day"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Partly cloudy"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Exigida apenas quando API_KEYS ou API_KEYS_FILE está configurada",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     },
     "tags": [
@@ -133,6 +1081,10 @@ const docTemplate = `{
         {
             "description": "Health check da aplicação",
             "name": "health"
+        },
+        {
+            "description": "Estatísticas de uso para análise de produto",
+            "name": "stats"
         }
     ]
 }`
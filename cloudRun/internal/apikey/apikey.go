@@ -0,0 +1,118 @@
+// Package apikey restricts the weather lookups to callers sending one of the
+// configured keys, so a shared Cloud Run URL does not expose the WeatherAPI quota.
+package apikey
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"cloudrun/internal/domain"
+)
+
+// Header carries the API key of a request
+const Header = "X-API-Key"
+
+// Keys is the set of accepted API keys. Only their SHA-256 is kept, which
+// also gives every comparison the same length.
+type Keys struct {
+	mu     sync.RWMutex
+	hashes [][sha256.Size]byte
+}
+
+// New creates the set from keys, ignoring blank entries
+func New(keys ...string) *Keys {
+	k := &Keys{}
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			k.hashes = append(k.hashes, sha256.Sum256([]byte(key)))
+		}
+	}
+	return k
+}
+
+// Len returns the number of accepted keys; an empty set disables authentication
+func (k *Keys) Len() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.hashes)
+}
+
+// Valid reports whether key is accepted, comparing it with every key in constant time
+func (k *Keys) Valid(key string) bool {
+	if key == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(key))
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	valid := 0
+	for _, hash := range k.hashes {
+		valid |= subtle.ConstantTimeCompare(sum[:], hash[:])
+	}
+	return valid == 1
+}
+
+// Replace swaps the accepted keys for the keys of other, e.g. after a secret rotation
+func (k *Keys) Replace(other *Keys) {
+	other.mu.RLock()
+	hashes := other.hashes
+	other.mu.RUnlock()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.hashes = hashes
+}
+
+// Middleware answers 401 unless the request sends a valid key in X-API-Key.
+// Responses vary by key, so shared caches never serve one client's answer to another.
+func (k *Keys) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", Header)
+		key := r.Header.Get(Header)
+		if !k.Valid(key) {
+			message := "invalid api key"
+			if key == "" {
+				message = "missing api key"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(domain.ErrorResponse{Message: message})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Parse splits a list of keys separated by commas or newlines
+func Parse(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+}
+
+// ReadFile reads one key per line from path, e.g. a Secret Manager secret
+// mounted as a volume. Blank lines and lines starting with # are skipped.
+func ReadFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var keys []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, Parse(line)...)
+	}
+	return keys, scanner.Err()
+}
@@ -0,0 +1,101 @@
+package apikey
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	keys := New("first-key", " ", "second-key")
+
+	if keys.Len() != 2 {
+		t.Errorf("Expected 2 keys, got %d", keys.Len())
+	}
+	for _, key := range []string{"first-key", "second-key"} {
+		if !keys.Valid(key) {
+			t.Errorf("Expected %q to be valid", key)
+		}
+	}
+	for _, key := range []string{"", "other-key", "first-key "} {
+		if keys.Valid(key) {
+			t.Errorf("Expected %q to be invalid", key)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := New("secret").Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name    string
+		key     string
+		status  int
+		message string
+	}{
+		{"valid key", "secret", http.StatusOK, ""},
+		{"missing key", "", http.StatusUnauthorized, "missing api key"},
+		{"wrong key", "guess", http.StatusUnauthorized, "invalid api key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather/01310100", nil)
+			if tt.key != "" {
+				req.Header.Set(Header, tt.key)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rr.Code)
+			}
+			if tt.message != "" && !strings.Contains(rr.Body.String(), tt.message) {
+				t.Errorf("Expected body to contain %q, got %s", tt.message, rr.Body.String())
+			}
+			if vary := rr.Header().Get("Vary"); vary != Header {
+				t.Errorf("Expected Vary: %s, got %q", Header, vary)
+			}
+		})
+	}
+}
+
+func TestParseAndReadFile(t *testing.T) {
+	if got := Parse("a, b,,c"); !reflect.DeepEqual(got, []string{"a", " b", "c"}) {
+		t.Errorf("Expected [a  b c], got %q", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "api-keys")
+	if err := os.WriteFile(path, []byte("# partners\nfirst-key\n\nsecond-key,third-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := []string{"first-key", "second-key", "third-key"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected %q, got %q", want, keys)
+	}
+
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	keys := New("old-key")
+
+	keys.Replace(New("new-key", "other-key"))
+
+	if keys.Valid("old-key") {
+		t.Error("Expected old-key to be rejected after Replace")
+	}
+	if !keys.Valid("new-key") || keys.Len() != 2 {
+		t.Errorf("Expected the 2 new keys, got %d keys", keys.Len())
+	}
+}
@@ -0,0 +1,108 @@
+// Package cache stores upstream lookups so repeated CEPs and cities skip the network.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloudrun/internal/status"
+)
+
+// Backends accepted by CACHE_BACKEND
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendNone   = "none"
+)
+
+// Cache stores values by key for a limited time. Errors mean the backend is
+// unreachable; callers should treat them as misses.
+type Cache interface {
+	Get(ctx context.Context, key string) (value []byte, ttl time.Duration, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	CacheStats() status.CacheStats
+}
+
+// counters tracks hits and misses for CacheStats
+type counters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func (c *counters) record(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+// Lookup is the outcome of one cached lookup, reported in the Cache-Status header
+type Lookup struct {
+	// Name identifies the cache, e.g. viacep or weatherapi
+	Name string
+	Hit  bool
+	// TTL is the remaining freshness of a hit
+	TTL time.Duration
+	// Collapsed marks a miss that shared one upstream call with concurrent
+	// lookups of the same key
+	Collapsed bool
+}
+
+// String formats the lookup as a Cache-Status (RFC 9211) list member
+func (l Lookup) String() string {
+	if l.Hit {
+		return fmt.Sprintf("%s; hit; ttl=%d", l.Name, int(l.TTL.Seconds()))
+	}
+	if l.Collapsed {
+		return fmt.Sprintf("%s; fwd=miss; collapsed", l.Name)
+	}
+	return fmt.Sprintf("%s; fwd=miss", l.Name)
+}
+
+// Lookups collects the cache outcomes of one request
+type Lookups struct {
+	mu      sync.Mutex
+	lookups []Lookup
+}
+
+// Header returns the Cache-Status header value, empty when no cache was used
+func (l *Lookups) Header() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	members := make([]string, len(l.lookups))
+	for i, lookup := range l.lookups {
+		members[i] = lookup.String()
+	}
+	return strings.Join(members, ", ")
+}
+
+// All returns the lookups recorded so far
+func (l *Lookups) All() []Lookup {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Lookup(nil), l.lookups...)
+}
+
+type lookupsKey struct{}
+
+// WithLookups returns a context in which cached repositories record their outcomes
+func WithLookups(ctx context.Context) (context.Context, *Lookups) {
+	lookups := &Lookups{}
+	return context.WithValue(ctx, lookupsKey{}, lookups), lookups
+}
+
+// Record adds lookup to the Lookups of ctx, if any
+func Record(ctx context.Context, lookup Lookup) {
+	lookups, ok := ctx.Value(lookupsKey{}).(*Lookups)
+	if !ok {
+		return
+	}
+	lookups.mu.Lock()
+	defer lookups.mu.Unlock()
+	lookups.lookups = append(lookups.lookups, lookup)
+}
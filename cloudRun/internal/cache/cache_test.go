@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLookups_Header(t *testing.T) {
+	ctx, lookups := WithLookups(context.Background())
+	if header := lookups.Header(); header != "" {
+		t.Errorf("Expected empty header without lookups, got %q", header)
+	}
+
+	Record(ctx, Lookup{Name: "viacep", Hit: true, TTL: 90*time.Second + 500*time.Millisecond})
+	Record(ctx, Lookup{Name: "weatherapi"})
+	Record(ctx, Lookup{Name: "weatherapi", Collapsed: true})
+
+	expected := "viacep; hit; ttl=90, weatherapi; fwd=miss, weatherapi; fwd=miss; collapsed"
+	if header := lookups.Header(); header != expected {
+		t.Errorf("Expected %q, got %q", expected, header)
+	}
+}
+
+func TestRecord_WithoutLookups(t *testing.T) {
+	// Lookups outside a request are simply not reported
+	Record(context.Background(), Lookup{Name: "viacep"})
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"cloudrun/internal/status"
+)
+
+// LRU is an in-memory cache evicting the least recently used entry when full
+type LRU struct {
+	counters
+
+	capacity int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU creates an in-memory cache holding up to capacity entries
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored under key if it has not expired
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, time.Duration, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.record(false)
+		return nil, 0, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	ttl := entry.expiresAt.Sub(c.now())
+	if ttl <= 0 {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		c.record(false)
+		return nil, 0, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	c.record(true)
+	return entry.value, ttl, true, nil
+}
+
+// Set stores value under key for ttl, evicting the least recently used entry when full
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+// CacheStats reports hits, misses and the entries held, expired ones included until evicted
+func (c *LRU) CacheStats() status.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return status.CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Entries: c.order.Len()}
+}
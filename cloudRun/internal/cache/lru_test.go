@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU_GetSet(t *testing.T) {
+	c := NewLRU(10)
+	ctx := context.Background()
+
+	if _, _, ok, _ := c.Get(ctx, "cep:01310100"); ok {
+		t.Fatal("Expected miss on empty cache")
+	}
+	c.Set(ctx, "cep:01310100", []byte("value"), time.Minute)
+
+	value, ttl, ok, err := c.Get(ctx, "cep:01310100")
+	if err != nil || !ok {
+		t.Fatalf("Expected hit, got ok=%v err=%v", ok, err)
+	}
+	if string(value) != "value" {
+		t.Errorf("Expected value, got %q", value)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected remaining TTL within a minute, got %v", ttl)
+	}
+
+	stats := c.CacheStats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("Expected 1 hit, 1 miss and 1 entry, got %+v", stats)
+	}
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	c := NewLRU(10)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	c.Set(ctx, "key", []byte("value"), time.Minute)
+	now = now.Add(time.Minute)
+
+	if _, _, ok, _ := c.Get(ctx, "key"); ok {
+		t.Error("Expected expired entry to miss")
+	}
+	if entries := c.CacheStats().Entries; entries != 0 {
+		t.Errorf("Expected expired entry to be removed, got %d entries", entries)
+	}
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU(2)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", []byte("a"), time.Minute)
+	c.Set(ctx, "b", []byte("b"), time.Minute)
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("c"), time.Minute)
+
+	if _, _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, _, ok, _ := c.Get(ctx, key); !ok {
+			t.Errorf("Expected %s to be kept", key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloudrun/internal/status"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a cache shared by all instances, e.g. on Memorystore
+type Redis struct {
+	counters
+
+	client *redis.Client
+	prefix string
+}
+
+// NewRedis creates a cache on the Redis at url (redis://[:password@]host:port[/db]),
+// namespacing its keys with prefix
+func NewRedis(url, prefix string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Redis{client: redis.NewClient(opts), prefix: prefix}, nil
+}
+
+// Get returns the value stored under key and its remaining TTL
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, time.Duration, bool, error) {
+	key = c.prefix + key
+	pipe := c.client.Pipeline()
+	get := pipe.Get(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if errors.Is(err, redis.Nil) {
+		c.record(false)
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		c.record(false)
+		return nil, 0, false, err
+	}
+
+	c.record(true)
+	return []byte(get.Val()), ttl.Val(), true, nil
+}
+
+// Set stores value under key for ttl
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// Ping checks that Redis is reachable
+func (c *Redis) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Close releases the connections to Redis
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
+
+// CacheStats reports the hits and misses of this instance; entries are shared
+// with other instances and reported as -1
+func (c *Redis) CacheStats() status.CacheStats {
+	return status.CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Entries: -1}
+}
@@ -0,0 +1,188 @@
+// Package compress gzips responses for clients that accept it, cutting the
+// egress of large JSON bodies such as batch lookups and the Swagger assets.
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultMinSize is the smallest body worth compressing; below it the gzip
+// header and checksum outweigh the savings
+const DefaultMinSize = 1024
+
+// compressible lists the content types gzipped; images and other binary
+// formats are already compressed
+var compressible = map[string]bool{
+	"application/json":       true,
+	"application/xml":        true,
+	"application/javascript": true,
+	"text/javascript":        true,
+	"image/svg+xml":          true,
+}
+
+var writers = sync.Pool{New: func() any {
+	w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+	return w
+}}
+
+// Middleware gzips responses of at least minSize bytes with a compressible
+// content type when the request accepts gzip. A negative minSize disables it.
+func Middleware(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if minSize < 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || !AcceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &responseWriter{ResponseWriter: w, minSize: minSize, statusCode: http.StatusOK}
+			defer gw.close()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// AcceptsGzip reports whether an Accept-Encoding header allows gzip, either by
+// name or through *, honouring q=0 as a refusal
+func AcceptsGzip(header string) bool {
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		accepted := quality(params) > 0
+		if coding == "gzip" {
+			return accepted
+		}
+		wildcard = accepted
+	}
+	return wildcard
+}
+
+// quality parses the q parameter of an Accept-Encoding entry, 1 when absent
+func quality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.ToLower(strings.TrimSpace(name)) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
+
+// isCompressible reports whether a Content-Type is text-like
+func isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || compressible[mediaType]
+}
+
+// responseWriter holds the body back until minSize bytes decide whether it
+// is gzipped, delaying the status code until then
+type responseWriter struct {
+	http.ResponseWriter
+	minSize    int
+	statusCode int
+
+	buf         []byte
+	wroteHeader bool
+	decided     bool
+	gz          *gzip.Writer
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.statusCode = code
+	// Informational and bodiless responses go through untouched
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		w.decided = true
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide starts gzip when the response qualifies and writes the held-back bytes
+func (w *responseWriter) decide() error {
+	w.decided = true
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	// Partial content is a byte range of the identity body and cannot be re-encoded
+	if len(w.buf) > 0 && len(w.buf) >= w.minSize && w.statusCode != http.StatusPartialContent &&
+		header.Get("Content-Encoding") == "" && isCompressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = writers.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+		w.ResponseWriter.WriteHeader(w.statusCode)
+		_, err := w.gz.Write(w.buf)
+		w.buf = nil
+		return err
+	}
+
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	_, err := w.ResponseWriter.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// close sends a body smaller than minSize as is, or ends the gzip stream
+func (w *responseWriter) close() {
+	if !w.decided {
+		if !w.wroteHeader {
+			return
+		}
+		w.decide()
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		writers.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,148 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(t *testing.T, minSize int, acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rr := httptest.NewRecorder()
+	Middleware(minSize)(handler).ServeHTTP(rr, req)
+	return rr
+}
+
+func jsonHandler(statusCode int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		io.WriteString(w, body)
+	}
+}
+
+func gunzip(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	reader, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatalf("Expected a gzip body, got %v", err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to read gzip body: %v", err)
+	}
+	return string(body)
+}
+
+func TestMiddleware_CompressesLargeJSON(t *testing.T) {
+	body := `{"results":[` + strings.Repeat(`{"temp_C":28.5},`, 100) + `{}]}`
+	rr := serve(t, 1024, "gzip, deflate, br", jsonHandler(http.StatusCreated, body))
+
+	if rr.Code != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", rr.Code)
+	}
+	if rr.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected Content-Encoding gzip, got %q", rr.Header().Get("Content-Encoding"))
+	}
+	if rr.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Expected Vary: Accept-Encoding, got %q", rr.Header().Get("Vary"))
+	}
+	if rr.Body.Len() >= len(body) {
+		t.Errorf("Expected a smaller body, got %d bytes for %d", rr.Body.Len(), len(body))
+	}
+	if got := gunzip(t, rr); got != body {
+		t.Errorf("Expected the original body after gunzip, got %q", got)
+	}
+}
+
+func TestMiddleware_LeavesSmallBodies(t *testing.T) {
+	rr := serve(t, 1024, "gzip", jsonHandler(http.StatusNotFound, `{"message":"can not find zipcode"}`))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rr.Code)
+	}
+	if rr.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected no Content-Encoding, got %q", rr.Header().Get("Content-Encoding"))
+	}
+	if rr.Body.String() != `{"message":"can not find zipcode"}` {
+		t.Errorf("Expected the body as is, got %q", rr.Body.String())
+	}
+}
+
+func TestMiddleware_SkipsWithoutGzipOrForBinary(t *testing.T) {
+	body := strings.Repeat("a", 2048)
+
+	tests := []struct {
+		name           string
+		minSize        int
+		acceptEncoding string
+		contentType    string
+	}{
+		{"no Accept-Encoding", 0, "", "application/json"},
+		{"gzip refused", 0, "gzip;q=0, br", "application/json"},
+		{"binary content", 0, "gzip", "image/png"},
+		{"disabled", -1, "gzip", "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serve(t, tt.minSize, tt.acceptEncoding, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				io.WriteString(w, body)
+			})
+			if rr.Header().Get("Content-Encoding") != "" {
+				t.Errorf("Expected no Content-Encoding, got %q", rr.Header().Get("Content-Encoding"))
+			}
+			if rr.Body.String() != body {
+				t.Errorf("Expected the body as is, got %d bytes", rr.Body.Len())
+			}
+		})
+	}
+}
+
+func TestMiddleware_DetectsContentTypeOfSwaggerAssets(t *testing.T) {
+	body := "<!DOCTYPE html><html><body>" + strings.Repeat("<p>Swagger UI</p>", 100) + "</body></html>"
+	rr := serve(t, 1024, "gzip", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body[:500])
+		io.WriteString(w, body[500:])
+	})
+
+	if rr.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected Content-Encoding gzip, got %q", rr.Header().Get("Content-Encoding"))
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("Expected detected text/html, got %q", rr.Header().Get("Content-Type"))
+	}
+	if got := gunzip(t, rr); got != body {
+		t.Error("Expected the original body after gunzip")
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := map[string]bool{
+		"":                  false,
+		"gzip":              true,
+		"GZIP":              true,
+		"deflate, gzip":     true,
+		"gzip;q=0":          false,
+		"gzip; q=0.5":       true,
+		"br":                false,
+		"*":                 true,
+		"*;q=0":             false,
+		"gzip;q=0, *":       false,
+		"identity, *;q=0.1": true,
+	}
+
+	for header, want := range tests {
+		if got := AcceptsGzip(header); got != want {
+			t.Errorf("AcceptsGzip(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
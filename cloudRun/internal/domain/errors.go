@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRateLimited indica que o provedor continua recusando requisições com 429
+var ErrRateLimited = errors.New("weather provider rate limit exceeded")
+
+// ErrCEPNotFound indica que o ViaCEP não conhece o CEP
+var ErrCEPNotFound = errors.New("CEP not found")
+
+// ErrLocationNotFound indica que o provedor de clima não reconheceu a localização
+var ErrLocationNotFound = errors.New("weather provider found no matching location")
+
+// ErrIPNotLocated indica que o provedor de geolocalização não conhece o IP, ex.: um endereço privado
+var ErrIPNotLocated = errors.New("geolocation provider could not locate the ip")
+
+// RateLimitError carrega quanto tempo o provedor pediu para aguardar
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+// Unwrap permite comparar com errors.Is(err, ErrRateLimited)
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "context"
+
+// Idiomas do texto da condição do tempo, nos códigos do parâmetro lang da WeatherAPI
+const (
+	LanguageEnglish    = "en"
+	LanguagePortuguese = "pt"
+)
+
+type languageKey struct{}
+
+// ValidLanguage informa se o texto da condição pode ser pedido em lang
+func ValidLanguage(lang string) bool {
+	return lang == LanguageEnglish || lang == LanguagePortuguese
+}
+
+// WithLanguage retorna um contexto em que os provedores descrevem a condição em lang
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// Language retorna o idioma definido em ctx; sem idioma, os provedores respondem em inglês
+func Language(ctx context.Context) (lang string, ok bool) {
+	lang, ok = ctx.Value(languageKey{}).(string)
+	return lang, ok
+}
+
+// LocalizedKey acrescenta o idioma de ctx a uma chave de cache ou de agrupamento,
+// para que consultas em idiomas diferentes não compartilhem o texto. As chaves em
+// inglês ficam como antes.
+func LocalizedKey(ctx context.Context, key string) string {
+	if lang, ok := Language(ctx); ok && lang != LanguageEnglish {
+		return key + "@" + lang
+	}
+	return key
+}
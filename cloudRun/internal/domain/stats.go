@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// StatsResponse representa a resposta de GET /stats
+// @Description Estatísticas das consultas de clima feitas na janela pedida
+type StatsResponse struct {
+	Since        time.Time      `json:"since" example:"2025-01-01T12:00:00Z" description:"Início da janela"`
+	Lookups      int            `json:"lookups" example:"1520" description:"Consultas na janela"`
+	ErrorRate    float64        `json:"error_rate" example:"0.031" description:"Fração das consultas que não tiveram sucesso"`
+	AvgLatencyMS float64        `json:"avg_latency_ms" example:"182.4" description:"Latência média das consultas em milissegundos"`
+	Outcomes     map[string]int `json:"outcomes" description:"Consultas por resultado: ok, invalid_input, not_found, rate_limited ou error"`
+	TopCities    []CityStats    `json:"top_cities" description:"Cidades mais consultadas"`
+}
+
+// CityStats representa as consultas de uma cidade
+// @Description Consultas de uma cidade e a temperatura média das que tiveram sucesso
+type CityStats struct {
+	City     string   `json:"city" example:"São Paulo"`
+	UF       string   `json:"uf,omitempty" example:"SP"`
+	Lookups  int      `json:"lookups" example:"312"`
+	AvgTempC *float64 `json:"avg_temp_C,omitempty" example:"24.7" description:"Ausente quando todas as consultas falharam"`
+}
@@ -1,17 +1,108 @@
 package domain
 
+import "time"
+
 // WeatherResponse representa a resposta com informações de temperatura
 // @Description Resposta contendo a temperatura em Celsius, Fahrenheit e Kelvin
 type WeatherResponse struct {
-	TempC float64 `json:"temp_C" example:"28.5" description:"Temperatura em Celsius"`
-	TempF float64 `json:"temp_F" example:"83.3" description:"Temperatura em Fahrenheit"`
-	TempK float64 `json:"temp_K" example:"301.5" description:"Temperatura em Kelvin"`
+	TempC float64       `json:"temp_C" xml:"temp_C" example:"28.5" description:"Temperatura em Celsius"`
+	TempF float64       `json:"temp_F" xml:"temp_F" example:"83.3" description:"Temperatura em Fahrenheit"`
+	TempK float64       `json:"temp_K" xml:"temp_K" example:"301.5" description:"Temperatura em Kelvin"`
+	Meta  *ResponseMeta `json:"meta,omitempty" xml:"meta,omitempty" description:"Origem dos dados, apenas com meta=true"`
+}
+
+// DropMeta removes the metadata, which clients only get when they ask for it
+func (r *WeatherResponse) DropMeta() {
+	r.Meta = nil
+}
+
+// ResponseMeta representa a origem dos dados de clima de uma resposta
+// @Description Provedor que respondeu, momento da consulta e se os dados vieram do cache
+type ResponseMeta struct {
+	Provider  string    `json:"provider" xml:"provider" example:"weatherapi" description:"Provedor de clima que respondeu"`
+	FetchedAt time.Time `json:"fetched_at" xml:"fetched_at" example:"2025-01-01T12:00:00Z" description:"Momento em que o provedor foi consultado"`
+	Cached    bool      `json:"cached" xml:"cached" example:"true" description:"Dados servidos pelo cache, e não consultados agora"`
+}
+
+// WeatherCondition representa a condição do tempo normalizada
+// @Description Condição do tempo em um enum estável, independente do provedor
+type WeatherCondition struct {
+	Code string `json:"code" xml:"code" example:"partly_cloudy" description:"Condição normalizada"`
+	Icon string `json:"icon" xml:"icon" example:"partly-cloudy-day" description:"Identificador do ícone"`
+	Text string `json:"text" xml:"text" example:"Partly cloudy" description:"Descrição retornada pelo provedor"`
+}
+
+// DetailedWeatherResponse representa a resposta detalhada (?detail=full)
+// @Description Temperaturas acrescidas da condição do tempo normalizada
+type DetailedWeatherResponse struct {
+	WeatherResponse
+	Condition WeatherCondition `json:"condition" xml:"condition"`
+}
+
+// IPWeatherResponse representa a resposta de GET /weather/me
+// @Description Temperaturas no local aproximado do IP do cliente
+type IPWeatherResponse struct {
+	WeatherResponse
+	Condition *WeatherCondition `json:"condition,omitempty" xml:"condition,omitempty"`
+	City      string            `json:"city" xml:"city" example:"São Paulo" description:"Cidade aproximada do IP"`
+	Region    string            `json:"region" xml:"region" example:"São Paulo" description:"Estado ou região"`
+	Country   string            `json:"country" xml:"country" example:"BR" description:"Código ISO do país"`
+}
+
+// BatchWeatherRequest representa o corpo de POST /weather/batch
+// @Description Lista de CEPs a consultar
+type BatchWeatherRequest struct {
+	CEPs []string `json:"ceps" example:"01310100,20040020"`
+}
+
+// BatchWeatherResult representa o resultado de um CEP do lote
+// @Description Temperaturas do CEP ou a mensagem de erro, com o status HTTP que a consulta individual teria
+type BatchWeatherResult struct {
+	CEP    string `json:"cep" xml:"cep" example:"01310100" description:"CEP como enviado"`
+	Status int    `json:"status" xml:"status" example:"200" description:"Status HTTP da consulta deste CEP"`
+	*WeatherResponse
+	Condition *WeatherCondition `json:"condition,omitempty" xml:"condition,omitempty"`
+	Error     string            `json:"error,omitempty" xml:"error,omitempty" example:"can not find zipcode" description:"Mensagem de erro"`
+}
+
+// BatchWeatherResponse representa a resposta de POST /weather/batch
+// @Description Um resultado por CEP, na ordem da requisição
+type BatchWeatherResponse struct {
+	Results []BatchWeatherResult `json:"results" xml:"result"`
+}
+
+// Status of the process or of a dependency in the health responses
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+// LivenessResponse representa a resposta de GET /health/live
+// @Description O processo está de pé
+type LivenessResponse struct {
+	Status string `json:"status" example:"up"`
+}
+
+// DependencyStatus representa a checagem de uma dependência
+// @Description Resultado da checagem de uma dependência externa
+type DependencyStatus struct {
+	Name      string `json:"name" example:"ViaCEP" description:"Nome da dependência"`
+	Status    string `json:"status" example:"up" enums:"up,down"`
+	LatencyMS int64  `json:"latency_ms" example:"42" description:"Duração da checagem em milissegundos"`
+	Error     string `json:"error,omitempty" example:"weather API rejected the API key (status 401)" description:"Motivo da falha"`
+}
+
+// ReadinessResponse representa a resposta de GET /health/ready
+// @Description Pronto quando ViaCEP e ao menos um provedor de clima respondem
+type ReadinessResponse struct {
+	Status       string             `json:"status" example:"up" enums:"up,down"`
+	Dependencies []DependencyStatus `json:"dependencies"`
 }
 
 // ErrorResponse representa uma resposta de erro
 // @Description Resposta de erro da API
 type ErrorResponse struct {
-	Message string `json:"message" example:"invalid zipcode" description:"Mensagem de erro"`
+	Message string `json:"message" xml:"message" example:"invalid zipcode" description:"Mensagem de erro"`
 }
 
 // ViaCEPResponse representa a resposta da API ViaCEP
@@ -26,9 +117,35 @@ type ViaCEPResponse struct {
 
 // WeatherAPIResponse representa a resposta da API de clima
 type WeatherAPIResponse struct {
-	Current struct {
-		TempC float64 `json:"temp_c"`
-	} `json:"current"`
+	Current WeatherAPICurrent `json:"current"`
+	// Provider and FetchedAt are set by the failover, not sent by the providers,
+	// and are kept in the cache so hits report the original lookup
+	Provider  string    `json:"provider,omitempty"`
+	FetchedAt time.Time `json:"fetched_at,omitzero"`
+	// Cached is set on cache hits and never stored
+	Cached bool `json:"-"`
+}
+
+// WeatherAPICurrent representa as condições atuais retornadas pela API de clima
+type WeatherAPICurrent struct {
+	TempC     float64             `json:"temp_c"`
+	IsDay     int                 `json:"is_day"`
+	Condition WeatherAPICondition `json:"condition"`
+}
+
+// WeatherAPICondition representa a condição do tempo no esquema de códigos da API de clima
+type WeatherAPICondition struct {
+	Text string `json:"text"`
+	Code int    `json:"code"`
+}
+
+// IPLocation representa o local aproximado de um endereço IP
+type IPLocation struct {
+	City      string
+	Region    string
+	Country   string
+	Latitude  float64
+	Longitude float64
 }
 
 // Location representa uma localização
@@ -0,0 +1,90 @@
+// Package flight collapses concurrent identical calls into one. Unlike a bare
+// singleflight.Group, the shared call follows its callers: it runs detached
+// from any single caller's cancellation, and is cancelled once every caller
+// waiting for it has given up.
+package flight
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Group collapses calls by key; the zero value is ready to use
+type Group struct {
+	group singleflight.Group
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// call is the context shared by the callers of one key
+type call struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+}
+
+// Result is the outcome of Do for one caller
+type Result struct {
+	Val any
+	Err error
+	// Shared reports whether the value was given to more than one caller
+	Shared bool
+	// Leader reports whether this caller ran fn, rather than waiting for another
+	Leader bool
+}
+
+// Do runs fn once for all concurrent callers of key and waits for its result.
+// fn gets a context with the values of the first caller, cancelled only when no
+// caller waits anymore. A caller whose ctx ends stops waiting and gets ctx.Err().
+func (g *Group) Do(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) Result {
+	c := g.join(ctx, key)
+	defer g.leave(key, c)
+
+	leader := false
+	ch := g.group.DoChan(key, func() (any, error) {
+		leader = true
+		return fn(c.ctx)
+	})
+
+	select {
+	case <-ctx.Done():
+		return Result{Err: ctx.Err()}
+	case res := <-ch:
+		return Result{Val: res.Val, Err: res.Err, Shared: res.Shared, Leader: leader}
+	}
+}
+
+// join registers a caller of key, creating the shared context for the first one
+func (g *Group) join(ctx context.Context, key string) *call {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &call{ctx: callCtx, cancel: cancel}
+		g.calls[key] = c
+	}
+	c.waiters++
+	return c
+}
+
+// leave unregisters a caller of key. The last one cancels the shared call, if
+// still running, and makes the next caller start a new one.
+func (g *Group) leave(key string, c *call) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c.waiters--
+	if c.waiters > 0 {
+		return
+	}
+	c.cancel()
+	g.group.Forget(key)
+	delete(g.calls, key)
+}
@@ -0,0 +1,117 @@
+package flight
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_CollapsesConcurrentCalls(t *testing.T) {
+	var g Group
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make(chan Result, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- g.Do(context.Background(), "key", func(ctx context.Context) (any, error) {
+				calls.Add(1)
+				<-release
+				return "value", nil
+			})
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	leaders := 0
+	for res := range results {
+		if res.Err != nil || res.Val != "value" {
+			t.Errorf("Expected the shared value, got %v, %v", res.Val, res.Err)
+		}
+		if res.Leader {
+			leaders++
+		}
+	}
+	if calls.Load() != 1 || leaders != 1 {
+		t.Errorf("Expected 1 call run by 1 leader, got %d calls and %d leaders", calls.Load(), leaders)
+	}
+}
+
+func TestGroup_CancelsWhenEveryCallerLeaves(t *testing.T) {
+	var g Group
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	fn := func(ctx context.Context) (any, error) {
+		close(started)
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return nil, ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	done := make(chan Result, 2)
+	go func() { done <- g.Do(ctx1, "key", fn) }()
+	<-started
+	go func() { done <- g.Do(ctx2, "key", fn) }()
+	time.Sleep(20 * time.Millisecond)
+
+	// The first caller gives up; the call keeps running for the second
+	cancel1()
+	if res := <-done; !errors.Is(res.Err, context.Canceled) {
+		t.Errorf("Expected context.Canceled for the first caller, got %v", res.Err)
+	}
+	select {
+	case <-cancelled:
+		t.Fatal("Expected the call to keep running while a caller waits")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The last caller gives up and the call is cancelled
+	cancel2()
+	<-done
+	select {
+	case err := <-cancelled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the call context cancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the call to be cancelled once no caller waits")
+	}
+
+	// A later caller starts a fresh call instead of joining the cancelled one
+	res := g.Do(context.Background(), "key", func(ctx context.Context) (any, error) {
+		return "fresh", ctx.Err()
+	})
+	if res.Err != nil || res.Val != "fresh" {
+		t.Errorf("Expected a fresh call, got %v, %v", res.Val, res.Err)
+	}
+}
+
+type ctxKey struct{}
+
+func TestGroup_KeepsCallerValues(t *testing.T) {
+	var g Group
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), ctxKey{}, "trace"), time.Second)
+	defer cancel()
+
+	res := g.Do(ctx, "key", func(ctx context.Context) (any, error) {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("Expected the shared call without the caller's deadline")
+		}
+		return ctx.Value(ctxKey{}), nil
+	})
+	if res.Val != "trace" {
+		t.Errorf("Expected the caller's values, got %v", res.Val)
+	}
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudrun/internal/domain"
+)
+
+// Media types the weather endpoints can answer with
+const (
+	mediaJSON = "application/json"
+	mediaXML  = "application/xml"
+	mediaCSV  = "text/csv"
+)
+
+// encoder writes a response body in one media type
+type encoder struct {
+	mediaType   string
+	contentType string
+	encode      func(w io.Writer, data any) error
+}
+
+// encoders lists the supported media types in order of preference, so JSON
+// wins ties such as */* or an absent Accept header
+var encoders = []encoder{
+	{mediaJSON, mediaJSON, encodeJSON},
+	{mediaXML, mediaXML + "; charset=utf-8", encodeXML},
+	{mediaCSV, mediaCSV + "; charset=utf-8", encodeCSV},
+}
+
+// aliases maps other names clients use to a supported media type
+var aliases = map[string]string{
+	"text/xml":        mediaXML,
+	"application/csv": mediaCSV,
+}
+
+// negotiate picks the encoder with the highest q value in an Accept header.
+// A header naming no supported type falls back to JSON rather than 406, so
+// clients sending e.g. text/plain keep working as before.
+func negotiate(accept string) encoder {
+	best, bestQ := encoders[0], 0.0
+	for _, e := range encoders {
+		if q := acceptQuality(accept, e.mediaType); q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the q value an Accept header gives mediaType, using
+// the most specific matching range; an empty header accepts everything
+func acceptQuality(accept, mediaType string) float64 {
+	if strings.TrimSpace(accept) == "" {
+		return 1
+	}
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		name, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+
+		s := -1
+		switch name {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity = s
+		q = 1
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+	return q
+}
+
+// send writes data with the status code in the media type negotiated from the
+// Accept header. The response metadata is only kept when asked for with meta=true.
+func (h *WeatherHandler) send(w http.ResponseWriter, r *http.Request, statusCode int, data any) {
+	if meta, _ := strconv.ParseBool(r.URL.Query().Get("meta")); !meta {
+		dropMeta(data)
+	}
+	h.setCacheControl(w, r, statusCode, data)
+	e := negotiate(r.Header.Get("Accept"))
+	w.Header().Add("Vary", "Accept")
+	w.Header().Set("Content-Type", e.contentType)
+	w.WriteHeader(statusCode)
+	e.encode(w, data)
+}
+
+// metaDropper is implemented by the weather responses, which embed domain.WeatherResponse
+type metaDropper interface {
+	DropMeta()
+}
+
+// dropMeta removes the metadata of the weather responses in data
+func dropMeta(data any) {
+	switch v := data.(type) {
+	case metaDropper:
+		v.DropMeta()
+	case domain.BatchWeatherResponse:
+		for _, result := range v.Results {
+			if result.WeatherResponse != nil {
+				result.DropMeta()
+			}
+		}
+	}
+}
+
+func encodeJSON(w io.Writer, data any) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+// encodeXML writes data under a <weather>, <batch> or <error> root element
+func encodeXML(w io.Writer, data any) error {
+	root := "weather"
+	switch data.(type) {
+	case domain.BatchWeatherResponse:
+		root = "batch"
+	case domain.ErrorResponse:
+		root = "error"
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if err := xml.NewEncoder(w).EncodeElement(data, xml.StartElement{Name: xml.Name{Local: root}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// encodeCSV writes data as a header row followed by one row per result. The
+// columns are fixed per response type so legacy parsers can rely on them; the
+// provider, fetched_at and cached columns are appended only with meta=true.
+func encodeCSV(w io.Writer, data any) error {
+	var rows [][]string
+	switch v := data.(type) {
+	case *domain.WeatherResponse:
+		rows = withMetaColumns(v, [][]string{
+			{"temp_C", "temp_F", "temp_K"},
+			temperatureColumns(v),
+		})
+	case *domain.DetailedWeatherResponse:
+		rows = withMetaColumns(&v.WeatherResponse, [][]string{
+			{"temp_C", "temp_F", "temp_K", "condition_code", "condition_icon", "condition_text"},
+			append(temperatureColumns(&v.WeatherResponse), conditionColumns(&v.Condition)...),
+		})
+	case *domain.IPWeatherResponse:
+		rows = withMetaColumns(&v.WeatherResponse, [][]string{
+			{"temp_C", "temp_F", "temp_K", "city", "region", "country", "condition_code", "condition_icon", "condition_text"},
+			append(append(temperatureColumns(&v.WeatherResponse), v.City, v.Region, v.Country), conditionColumns(v.Condition)...),
+		})
+	case domain.BatchWeatherResponse:
+		meta := false
+		for _, result := range v.Results {
+			meta = meta || (result.WeatherResponse != nil && result.Meta != nil)
+		}
+		header := []string{"cep", "status", "temp_C", "temp_F", "temp_K", "condition_code", "condition_icon", "condition_text", "error"}
+		if meta {
+			header = append(header, metaHeader...)
+		}
+		rows = [][]string{header}
+		for _, result := range v.Results {
+			row := []string{result.CEP, strconv.Itoa(result.Status)}
+			row = append(row, temperatureColumns(result.WeatherResponse)...)
+			row = append(row, conditionColumns(result.Condition)...)
+			row = append(row, result.Error)
+			if meta {
+				row = append(row, metaColumns(result.WeatherResponse)...)
+			}
+			rows = append(rows, row)
+		}
+	case domain.ErrorResponse:
+		rows = [][]string{{"message"}, {v.Message}}
+	default:
+		return fmt.Errorf("no CSV encoding for %T", data)
+	}
+
+	writer := csv.NewWriter(w)
+	return writer.WriteAll(rows)
+}
+
+// metaHeader names the metadata columns
+var metaHeader = []string{"provider", "fetched_at", "cached"}
+
+// withMetaColumns appends the metadata columns to a header and single row when weather carries metadata
+func withMetaColumns(weather *domain.WeatherResponse, rows [][]string) [][]string {
+	if weather.Meta == nil {
+		return rows
+	}
+	rows[0] = append(rows[0], metaHeader...)
+	rows[1] = append(rows[1], metaColumns(weather)...)
+	return rows
+}
+
+// metaColumns returns the provider, fetch time and cached flag of weather; nil leaves them empty
+func metaColumns(weather *domain.WeatherResponse) []string {
+	if weather == nil || weather.Meta == nil {
+		return []string{"", "", ""}
+	}
+	fetchedAt := ""
+	if !weather.Meta.FetchedAt.IsZero() {
+		fetchedAt = weather.Meta.FetchedAt.Format(time.RFC3339)
+	}
+	return []string{weather.Meta.Provider, fetchedAt, strconv.FormatBool(weather.Meta.Cached)}
+}
+
+// temperatureColumns formats the temperatures like the JSON body; nil leaves them empty
+func temperatureColumns(weather *domain.WeatherResponse) []string {
+	if weather == nil {
+		return []string{"", "", ""}
+	}
+	return []string{formatFloat(weather.TempC), formatFloat(weather.TempF), formatFloat(weather.TempK)}
+}
+
+// conditionColumns returns the code, icon and text of a condition; nil leaves them empty
+func conditionColumns(condition *domain.WeatherCondition) []string {
+	if condition == nil {
+		return []string{"", "", ""}
+	}
+	return []string{condition.Code, condition.Icon, condition.Text}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
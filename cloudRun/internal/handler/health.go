@@ -1,18 +1,40 @@
 package handler
 
-import "net/http"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
 
-// HealthHandler handles health check requests
-type HealthHandler struct{}
+	"cloudrun/internal/domain"
+	"cloudrun/internal/status"
+)
 
-// NewHealthHandler creates a new health handler
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+// readyCacheTTL is how long a readiness result is reused, so frequent probes
+// from several instances do not spend WeatherAPI quota on every call
+const readyCacheTTL = 10 * time.Second
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	required []status.Check
+	weather  []status.Check
+
+	mu        sync.Mutex
+	ready     *domain.ReadinessResponse
+	checkedAt time.Time
+}
+
+// NewHealthHandler creates a health handler. The instance is ready when every
+// required check passes and at least one weather provider answers, since the
+// failover serves from whichever provider is up.
+func NewHealthHandler(required []status.Check, weather []status.Check) *HealthHandler {
+	return &HealthHandler{required: required, weather: weather}
 }
 
 // HealthCheck godoc
 // @Summary Health check
-// @Description Verifica se a aplicação está funcionando
+// @Description Verifica se a aplicação está funcionando (mantido por compatibilidade, equivale a /health/live)
 // @Tags health
 // @Produce plain
 // @Success 200 {string} string "OK"
@@ -21,3 +43,77 @@ func (h *HealthHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Responde enquanto o processo está de pé, sem consultar dependências
+// @Tags health
+// @Produce json
+// @Success 200 {object} domain.LivenessResponse
+// @Router /health/live [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	sendHealth(w, http.StatusOK, domain.LivenessResponse{Status: domain.HealthUp})
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Verifica se ViaCEP e os provedores de clima estão acessíveis e se a chave da WeatherAPI é válida. O resultado é reaproveitado por 10 segundos.
+// @Tags health
+// @Produce json
+// @Success 200 {object} domain.ReadinessResponse
+// @Failure 503 {object} domain.ReadinessResponse
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	if h.ready == nil || time.Since(h.checkedAt) > readyCacheTTL {
+		// The result is shared, so a probe that gives up must not cache its cancellation
+		h.ready = h.checkReadiness(context.WithoutCancel(r.Context()))
+		h.checkedAt = time.Now()
+	}
+	ready := h.ready
+	h.mu.Unlock()
+
+	code := http.StatusOK
+	if ready.Status != domain.HealthUp {
+		code = http.StatusServiceUnavailable
+	}
+	sendHealth(w, code, ready)
+}
+
+// checkReadiness runs all checks concurrently and summarizes them
+func (h *HealthHandler) checkReadiness(ctx context.Context) *domain.ReadinessResponse {
+	checks := append(append([]status.Check(nil), h.required...), h.weather...)
+	results := status.RunChecks(ctx, checks, checkTimeout)
+
+	ready := &domain.ReadinessResponse{Status: domain.HealthUp}
+	weatherUp := len(h.weather) == 0
+	for i, result := range results {
+		dependency := domain.DependencyStatus{
+			Name:      result.Name,
+			Status:    domain.HealthUp,
+			LatencyMS: result.Latency.Milliseconds(),
+			Error:     result.Error,
+		}
+		isWeather := i >= len(h.required)
+		if !result.Healthy {
+			dependency.Status = domain.HealthDown
+			if !isWeather {
+				ready.Status = domain.HealthDown
+			}
+		} else if isWeather {
+			weatherUp = true
+		}
+		ready.Dependencies = append(ready.Dependencies, dependency)
+	}
+	if !weatherUp {
+		ready.Status = domain.HealthDown
+	}
+	return ready
+}
+
+func sendHealth(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cloudrun/internal/domain"
+	"cloudrun/internal/history"
+)
+
+// Limits of the /stats query parameters
+const (
+	defaultStatsWindow = 24 * time.Hour
+	maxStatsWindow     = 90 * 24 * time.Hour
+	defaultStatsTop    = 10
+	maxStatsTop        = 100
+)
+
+// StatsHandler serves aggregates of the query history for product analytics
+type StatsHandler struct {
+	store history.Store
+	now   func() time.Time
+}
+
+// NewStatsHandler creates a stats handler reading from store
+func NewStatsHandler(store history.Store) *StatsHandler {
+	return &StatsHandler{store: store, now: time.Now}
+}
+
+// Stats godoc
+// @Summary Estatísticas de uso
+// @Description Agrega o histórico de consultas (HISTORY_BACKEND): cidades mais consultadas, taxa de erro e latência média
+// @Tags stats
+// @Produce json
+// @Param window query string false "Janela de tempo, de 1m a 2160h (padrão: 24h)" example(168h)
+// @Param top query int false "Quantidade de cidades, de 1 a 100 (padrão: 10)"
+// @Success 200 {object} domain.StatsResponse "Estatísticas da janela"
+// @Failure 401 {object} domain.ErrorResponse "API key ausente ou inválida"
+// @Failure 422 {object} domain.ErrorResponse "window ou top inválido"
+// @Failure 500 {object} domain.ErrorResponse "Erro ao ler o histórico"
+// @Security ApiKeyAuth
+// @Router /stats [get]
+func (h *StatsHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	window, top := defaultStatsWindow, defaultStatsTop
+	if value := r.URL.Query().Get("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < time.Minute || parsed > maxStatsWindow {
+			sendStats(w, http.StatusUnprocessableEntity, domain.ErrorResponse{Message: "invalid window"})
+			return
+		}
+		window = parsed
+	}
+	if value := r.URL.Query().Get("top"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxStatsTop {
+			sendStats(w, http.StatusUnprocessableEntity, domain.ErrorResponse{Message: "invalid top"})
+			return
+		}
+		top = parsed
+	}
+
+	stats, err := h.store.Stats(r.Context(), h.now().Add(-window), top)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to read query history", "error", err)
+		sendStats(w, http.StatusInternalServerError, domain.ErrorResponse{Message: "error reading stats"})
+		return
+	}
+
+	response := domain.StatsResponse{
+		Since:        stats.Since.UTC(),
+		Lookups:      stats.Lookups,
+		ErrorRate:    stats.ErrorRate(),
+		AvgLatencyMS: float64(stats.AverageLatency.Microseconds()) / 1000,
+		Outcomes:     stats.Outcomes,
+		TopCities:    make([]domain.CityStats, 0, len(stats.TopCities)),
+	}
+	for _, city := range stats.TopCities {
+		response.TopCities = append(response.TopCities, domain.CityStats{
+			City:     city.City,
+			UF:       city.UF,
+			Lookups:  city.Lookups,
+			AvgTempC: city.AverageTempC,
+		})
+	}
+	sendStats(w, http.StatusOK, response)
+}
+
+// sendStats writes data as JSON; stats change with every lookup, so they are not cached
+func sendStats(w http.ResponseWriter, statusCode int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"cloudrun/internal/status"
+)
+
+//go:embed templates/status.html
+var statusTemplates embed.FS
+
+var statusTemplate = template.Must(template.New("status.html").Funcs(template.FuncMap{
+	"ms":      func(d time.Duration) int64 { return d.Milliseconds() },
+	"percent": func(ratio float64) string { return fmt.Sprintf("%.1f%%", ratio*100) },
+}).ParseFS(statusTemplates, "templates/status.html"))
+
+// checkTimeout bounds each live dependency check
+const checkTimeout = 3 * time.Second
+
+// StatusHandler serves the HTML status page for on-call engineers
+type StatusHandler struct {
+	checks    []status.Check
+	errors    *status.ErrorCounter
+	cache     status.CacheStatsProvider
+	latency   *status.LatencyRecorder
+	startedAt time.Time
+}
+
+// statusPage is the data rendered by the status template
+type statusPage struct {
+	Healthy     bool
+	Checks      []status.CheckResult
+	Build       status.BuildInfo
+	Uptime      time.Duration
+	Errors      []status.ErrorCount
+	ErrorWindow time.Duration
+	Cache       *status.CacheStats
+	Latency     []status.RouteLatency
+	GeneratedAt time.Time
+}
+
+// NewStatusHandler creates a status handler running the given dependency checks
+func NewStatusHandler(errors *status.ErrorCounter, checks ...status.Check) *StatusHandler {
+	return &StatusHandler{
+		checks:    checks,
+		errors:    errors,
+		startedAt: time.Now(),
+	}
+}
+
+// WithCache adds the stats of cache to the status page
+func (h *StatusHandler) WithCache(cache status.CacheStatsProvider) *StatusHandler {
+	h.cache = cache
+	return h
+}
+
+// WithLatency adds the latency histograms of latency to the status page
+func (h *StatusHandler) WithLatency(latency *status.LatencyRecorder) *StatusHandler {
+	h.latency = latency
+	return h
+}
+
+// Status godoc
+// @Summary Página de status
+// @Description Página HTML com a saúde das dependências (checadas a cada acesso), estatísticas de cache, latência por rota, versão/commit e contagem de erros recentes
+// @Tags health
+// @Produce html
+// @Success 200 {string} string "Página de status"
+// @Router /status [get]
+func (h *StatusHandler) Status(w http.ResponseWriter, r *http.Request) {
+	page := statusPage{
+		Healthy:     true,
+		Checks:      status.RunChecks(r.Context(), h.checks, checkTimeout),
+		Build:       status.Build(),
+		Uptime:      time.Since(h.startedAt).Truncate(time.Second),
+		Errors:      h.errors.Counts(),
+		ErrorWindow: h.errors.Window(),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, check := range page.Checks {
+		if !check.Healthy {
+			page.Healthy = false
+		}
+	}
+	if h.cache != nil {
+		stats := h.cache.CacheStats()
+		page.Cache = &stats
+	}
+	if h.latency != nil {
+		page.Latency = h.latency.Routes()
+	}
+
+	var buf bytes.Buffer
+	if err := statusTemplate.Execute(&buf, page); err != nil {
+		slog.ErrorContext(r.Context(), "Error rendering status page", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
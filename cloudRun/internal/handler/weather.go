@@ -1,65 +1,431 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"cloudrun/internal/cache"
 	"cloudrun/internal/domain"
+	"cloudrun/internal/ratelimit"
 	"cloudrun/internal/service"
 
 	"github.com/gorilla/mux"
 )
 
+// detailFull is the value of the detail query parameter that enables the detailed response mode
+const detailFull = "full"
+
+// Batch limits used unless WithBatchLimits overrides them
+const (
+	DefaultBatchMaxCEPs = 50
+	DefaultBatchWorkers = 8
+)
+
+// maxBatchBodyBytes bounds the body of POST /weather/batch
+const maxBatchBodyBytes = 64 << 10
+
 // WeatherHandler handles HTTP requests for weather endpoints
 type WeatherHandler struct {
 	weatherService *service.WeatherService
+	batchMaxCEPs   int
+	batchWorkers   int
+	cacheMaxAge    time.Duration
 }
 
 // NewWeatherHandler creates a new weather handler
 func NewWeatherHandler(weatherService *service.WeatherService) *WeatherHandler {
 	return &WeatherHandler{
 		weatherService: weatherService,
+		batchMaxCEPs:   DefaultBatchMaxCEPs,
+		batchWorkers:   DefaultBatchWorkers,
 	}
 }
 
+// WithBatchLimits sets how many CEPs a batch may carry and how many are looked up concurrently
+func (h *WeatherHandler) WithBatchLimits(maxCEPs, workers int) *WeatherHandler {
+	h.batchMaxCEPs = maxCEPs
+	h.batchWorkers = workers
+	return h
+}
+
+// WithCacheControl lets CDNs and browsers keep successful GET lookups for maxAge;
+// 0 sends no Cache-Control header
+func (h *WeatherHandler) WithCacheControl(maxAge time.Duration) *WeatherHandler {
+	h.cacheMaxAge = maxAge
+	return h
+}
+
 // GetWeatherByCEP godoc
 // @Summary Obter temperatura por CEP
 // @Description Recebe um CEP brasileiro válido e retorna a temperatura atual em Celsius, Fahrenheit e Kelvin
+// @Description Com detail=full, inclui a condição do tempo normalizada (enum estável e identificador de ícone)
 // @Tags weather
 // @Accept json
-// @Produce json
+// @Produce json,xml,text/csv
 // @Param cep path string true "CEP brasileiro (8 dígitos)" example("01310100")
-// @Success 200 {object} domain.WeatherResponse "Informações de temperatura"
-// @Failure 422 {object} domain.ErrorResponse "CEP inválido"
+// @Param detail query string false "Modo de resposta" Enums(full)
+// @Param meta query bool false "Inclui em meta o provedor, o momento da consulta e se veio do cache"
+// @Param precision query int false "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)" minimum(-1) maximum(6)
+// @Param lang query string false "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)" Enums(pt, en)
+// @Success 200 {object} domain.DetailedWeatherResponse "Informações de temperatura (condition apenas com detail=full)"
+// @Failure 422 {object} domain.ErrorResponse "CEP, precision ou lang inválido"
 // @Failure 404 {object} domain.ErrorResponse "CEP não encontrado"
+// @Failure 401 {object} domain.ErrorResponse "API key ausente ou inválida"
+// @Failure 429 {object} domain.ErrorResponse "Limite de requisições por IP atingido (ver header Retry-After)"
 // @Failure 500 {object} domain.ErrorResponse "Erro interno do servidor"
+// @Failure 503 {object} domain.ErrorResponse "Limite de requisições da WeatherAPI atingido (ver header Retry-After)"
+// @Header 200 {string} Cache-Status "Caches consultados, ex.: viacep; hit; ttl=86100, weatherapi; fwd=miss"
+// @Header 429,503 {integer} Retry-After "Segundos a aguardar antes de tentar novamente"
+// @Security ApiKeyAuth
 // @Router /weather/{cep} [get]
 func (h *WeatherHandler) GetWeatherByCEP(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	cep := vars["cep"]
+	ctx, lookups := cache.WithLookups(r.Context())
+	ctx, err := withLookupOptions(ctx, r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if r.URL.Query().Get("detail") == detailFull {
+		weather, err := h.weatherService.GetDetailedWeatherByCEP(ctx, cep)
+		setCacheStatus(w, lookups)
+		if err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+
+		h.send(w, r, http.StatusOK, weather)
+		return
+	}
 
-	weather, err := h.weatherService.GetWeatherByCEP(cep)
+	weather, err := h.weatherService.GetWeatherByCEP(ctx, cep)
+	setCacheStatus(w, lookups)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	h.sendJSON(w, http.StatusOK, weather)
+	h.send(w, r, http.StatusOK, weather)
+}
+
+// GetWeatherByCity godoc
+// @Summary Obter temperatura por cidade
+// @Description Consulta a WeatherAPI diretamente, sem passar pelo ViaCEP, para clientes que já conhecem a cidade
+// @Description Sem uf, a busca é restrita ao Brasil. Com detail=full, inclui a condição do tempo normalizada
+// @Tags weather
+// @Accept json
+// @Produce json,xml,text/csv
+// @Param city query string true "Nome da cidade" example("São Paulo")
+// @Param uf query string false "Sigla do estado" example("SP")
+// @Param detail query string false "Modo de resposta" Enums(full)
+// @Param meta query bool false "Inclui em meta o provedor, o momento da consulta e se veio do cache"
+// @Param precision query int false "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)" minimum(-1) maximum(6)
+// @Param lang query string false "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)" Enums(pt, en)
+// @Success 200 {object} domain.DetailedWeatherResponse "Informações de temperatura (condition apenas com detail=full)"
+// @Failure 422 {object} domain.ErrorResponse "Cidade, UF, precision ou lang inválido"
+// @Failure 404 {object} domain.ErrorResponse "Cidade não encontrada"
+// @Failure 401 {object} domain.ErrorResponse "API key ausente ou inválida"
+// @Failure 429 {object} domain.ErrorResponse "Limite de requisições por IP atingido (ver header Retry-After)"
+// @Failure 500 {object} domain.ErrorResponse "Erro interno do servidor"
+// @Failure 503 {object} domain.ErrorResponse "Limite de requisições da WeatherAPI atingido (ver header Retry-After)"
+// @Header 200 {string} Cache-Status "Caches consultados, ex.: weatherapi; hit; ttl=240"
+// @Header 429,503 {integer} Retry-After "Segundos a aguardar antes de tentar novamente"
+// @Security ApiKeyAuth
+// @Router /weather [get]
+func (h *WeatherHandler) GetWeatherByCity(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	city, uf := query.Get("city"), query.Get("uf")
+	ctx, lookups := cache.WithLookups(r.Context())
+	ctx, err := withLookupOptions(ctx, r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if query.Get("detail") == detailFull {
+		weather, err := h.weatherService.GetDetailedWeatherByCity(ctx, city, uf)
+		setCacheStatus(w, lookups)
+		if err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+
+		h.send(w, r, http.StatusOK, weather)
+		return
+	}
+
+	weather, err := h.weatherService.GetWeatherByCity(ctx, city, uf)
+	setCacheStatus(w, lookups)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.send(w, r, http.StatusOK, weather)
+}
+
+// GetWeatherByCoordinates godoc
+// @Summary Obter temperatura por coordenadas
+// @Description Consulta o clima de um ponto (latitude e longitude em graus decimais), para clientes móveis com GPS mas sem CEP
+// @Description As coordenadas são arredondadas para 0,01° (cerca de 1 km). Com detail=full, inclui a condição do tempo normalizada
+// @Tags weather
+// @Accept json
+// @Produce json,xml,text/csv
+// @Param lat query number true "Latitude, de -90 a 90" example(-23.5505)
+// @Param lon query number true "Longitude, de -180 a 180" example(-46.6333)
+// @Param detail query string false "Modo de resposta" Enums(full)
+// @Param meta query bool false "Inclui em meta o provedor, o momento da consulta e se veio do cache"
+// @Param precision query int false "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)" minimum(-1) maximum(6)
+// @Param lang query string false "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)" Enums(pt, en)
+// @Success 200 {object} domain.DetailedWeatherResponse "Informações de temperatura (condition apenas com detail=full)"
+// @Failure 422 {object} domain.ErrorResponse "Coordenadas ausentes ou fora do intervalo, ou precision ou lang inválido"
+// @Failure 404 {object} domain.ErrorResponse "Sem dados de clima para o ponto"
+// @Failure 401 {object} domain.ErrorResponse "API key ausente ou inválida"
+// @Failure 429 {object} domain.ErrorResponse "Limite de requisições por IP atingido (ver header Retry-After)"
+// @Failure 500 {object} domain.ErrorResponse "Erro interno do servidor"
+// @Failure 503 {object} domain.ErrorResponse "Limite de requisições da WeatherAPI atingido (ver header Retry-After)"
+// @Header 200 {string} Cache-Status "Caches consultados, ex.: weatherapi; hit; ttl=240"
+// @Header 429,503 {integer} Retry-After "Segundos a aguardar antes de tentar novamente"
+// @Security ApiKeyAuth
+// @Router /weather/coords [get]
+func (h *WeatherHandler) GetWeatherByCoordinates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	latitude, latErr := strconv.ParseFloat(strings.TrimSpace(query.Get("lat")), 64)
+	longitude, lonErr := strconv.ParseFloat(strings.TrimSpace(query.Get("lon")), 64)
+	if latErr != nil || lonErr != nil {
+		h.handleError(w, r, service.ErrInvalidCoordinates)
+		return
+	}
+	ctx, lookups := cache.WithLookups(r.Context())
+	ctx, err := withLookupOptions(ctx, r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if query.Get("detail") == detailFull {
+		weather, err := h.weatherService.GetDetailedWeatherByCoordinates(ctx, latitude, longitude)
+		setCacheStatus(w, lookups)
+		if err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+
+		h.send(w, r, http.StatusOK, weather)
+		return
+	}
+
+	weather, err := h.weatherService.GetWeatherByCoordinates(ctx, latitude, longitude)
+	setCacheStatus(w, lookups)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.send(w, r, http.StatusOK, weather)
+}
+
+// GetWeatherByIP godoc
+// @Summary Obter temperatura pelo IP do cliente
+// @Description Geolocaliza o IP de quem chama (último endereço do X-Forwarded-For, adicionado pelo Cloud Run) e retorna a temperatura do local aproximado
+// @Description Com detail=full, inclui a condição do tempo normalizada
+// @Tags weather
+// @Accept json
+// @Produce json,xml,text/csv
+// @Param detail query string false "Modo de resposta" Enums(full)
+// @Param meta query bool false "Inclui em meta o provedor, o momento da consulta e se veio do cache"
+// @Param precision query int false "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)" minimum(-1) maximum(6)
+// @Param lang query string false "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)" Enums(pt, en)
+// @Success 200 {object} domain.IPWeatherResponse "Temperatura e local aproximado (condition apenas com detail=full)"
+// @Failure 422 {object} domain.ErrorResponse "precision ou lang inválido"
+// @Failure 404 {object} domain.ErrorResponse "IP privado ou desconhecido pelo provedor de geolocalização"
+// @Failure 401 {object} domain.ErrorResponse "API key ausente ou inválida"
+// @Failure 429 {object} domain.ErrorResponse "Limite de requisições por IP atingido (ver header Retry-After)"
+// @Failure 500 {object} domain.ErrorResponse "Erro interno do servidor"
+// @Failure 503 {object} domain.ErrorResponse "Limite de requisições da WeatherAPI atingido (ver header Retry-After)"
+// @Header 200 {string} Cache-Status "Caches consultados, ex.: weatherapi; hit; ttl=240"
+// @Header 429,503 {integer} Retry-After "Segundos a aguardar antes de tentar novamente"
+// @Security ApiKeyAuth
+// @Router /weather/me [get]
+func (h *WeatherHandler) GetWeatherByIP(w http.ResponseWriter, r *http.Request) {
+	ctx, lookups := cache.WithLookups(r.Context())
+	ctx, err := withLookupOptions(ctx, r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	weather, err := h.weatherService.GetWeatherByIP(ctx, ratelimit.ClientIP(r), r.URL.Query().Get("detail") == detailFull)
+	setCacheStatus(w, lookups)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.send(w, r, http.StatusOK, weather)
+}
+
+// GetWeatherBatch godoc
+// @Summary Obter temperatura de vários CEPs
+// @Description Consulta até BATCH_MAX_CEPS CEPs (padrão 50) em paralelo e retorna um resultado por CEP, na ordem da requisição
+// @Description Cada resultado traz o status HTTP que a consulta individual teria; erros de um CEP não afetam os demais
+// @Description Com detail=full, inclui a condição do tempo normalizada
+// @Tags weather
+// @Accept json
+// @Produce json,xml,text/csv
+// @Param request body domain.BatchWeatherRequest true "CEPs a consultar"
+// @Param detail query string false "Modo de resposta" Enums(full)
+// @Param meta query bool false "Inclui em meta o provedor, o momento da consulta e se veio do cache"
+// @Param precision query int false "Casas decimais das temperaturas, de 0 a 6; -1 mantém o valor sem arredondar (padrão: TEMPERATURE_PRECISION)" minimum(-1) maximum(6)
+// @Param lang query string false "Idioma do texto da condição (padrão: WEATHER_LANGUAGE)" Enums(pt, en)
+// @Success 200 {object} domain.BatchWeatherResponse "Resultados por CEP"
+// @Failure 400 {object} domain.ErrorResponse "Corpo inválido"
+// @Failure 422 {object} domain.ErrorResponse "Lote vazio, com CEPs demais, ou precision ou lang inválido"
+// @Failure 401 {object} domain.ErrorResponse "API key ausente ou inválida"
+// @Failure 429 {object} domain.ErrorResponse "Limite de requisições por IP atingido (ver header Retry-After)"
+// @Security ApiKeyAuth
+// @Router /weather/batch [post]
+func (h *WeatherHandler) GetWeatherBatch(w http.ResponseWriter, r *http.Request) {
+	var request domain.BatchWeatherRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)).Decode(&request); err != nil {
+		h.send(w, r, http.StatusBadRequest, domain.ErrorResponse{Message: `body must be {"ceps": ["..."]}`})
+		return
+	}
+	if len(request.CEPs) == 0 || len(request.CEPs) > h.batchMaxCEPs {
+		h.send(w, r, http.StatusUnprocessableEntity, domain.ErrorResponse{
+			Message: fmt.Sprintf("batch must have between 1 and %d zipcodes", h.batchMaxCEPs),
+		})
+		return
+	}
+
+	ctx, err := withLookupOptions(r.Context(), r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	detailed := r.URL.Query().Get("detail") == detailFull
+	batch := h.weatherService.GetDetailedWeatherBatch(ctx, request.CEPs, h.batchWorkers)
+
+	response := domain.BatchWeatherResponse{Results: make([]domain.BatchWeatherResult, len(batch))}
+	for i, item := range batch {
+		result := domain.BatchWeatherResult{CEP: item.CEP, Status: http.StatusOK}
+		if item.Err != nil {
+			result.Status, result.Error = errorStatus(item.Err)
+		} else {
+			result.WeatherResponse = &item.Weather.WeatherResponse
+			if detailed {
+				result.Condition = &item.Weather.Condition
+			}
+		}
+		response.Results[i] = result
+	}
+
+	h.send(w, r, http.StatusOK, response)
+}
+
+// withLookupOptions applies the precision and lang query parameters, if any, to the lookups of ctx
+func withLookupOptions(ctx context.Context, r *http.Request) (context.Context, error) {
+	query := r.URL.Query()
+	if value := query.Get("precision"); value != "" {
+		precision, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return ctx, service.ErrInvalidPrecision
+		}
+		if ctx, err = service.WithPrecision(ctx, precision); err != nil {
+			return ctx, err
+		}
+	}
+	if lang := query.Get("lang"); lang != "" {
+		return service.WithLanguage(ctx, lang)
+	}
+	return ctx, nil
+}
+
+// setCacheStatus reports the caches consulted by the request in the Cache-Status header
+func setCacheStatus(w http.ResponseWriter, lookups *cache.Lookups) {
+	if header := lookups.Header(); header != "" {
+		w.Header().Set("Cache-Status", header)
+	}
+}
+
+// setCacheControl marks successful GET lookups cacheable for cacheMaxAge and
+// everything else uncacheable, so a CDN never keeps an error or a 503 from a
+// WeatherAPI quota spike. Answers for the caller's IP are only for its browser.
+func (h *WeatherHandler) setCacheControl(w http.ResponseWriter, r *http.Request, statusCode int, data any) {
+	if h.cacheMaxAge <= 0 {
+		return
+	}
+	if statusCode != http.StatusOK || r.Method != http.MethodGet {
+		w.Header().Set("Cache-Control", "no-store")
+		return
+	}
+	scope := "public"
+	if _, ok := data.(*domain.IPWeatherResponse); ok {
+		scope = "private"
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int(h.cacheMaxAge.Seconds())))
+	w.Header().Set("Expires", time.Now().Add(h.cacheMaxAge).UTC().Format(http.TimeFormat))
 }
 
 // handleError handles different types of errors and sends appropriate HTTP responses
-func (h *WeatherHandler) handleError(w http.ResponseWriter, err error) {
-	var statusCode int
-	var message string
+func (h *WeatherHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode, message := errorStatus(err)
+	if errors.Is(err, service.ErrRateLimited) {
+		w.Header().Set("Retry-After", retryAfterSeconds(err))
+	}
 
+	errorResponse := domain.ErrorResponse{Message: message}
+	h.send(w, r, statusCode, errorResponse)
+}
+
+// errorStatus maps a service error to its HTTP status code and public message
+func errorStatus(err error) (statusCode int, message string) {
 	switch {
 	case errors.Is(err, service.ErrInvalidCEP):
 		statusCode = http.StatusUnprocessableEntity
 		message = service.ErrInvalidCEP.Error()
+	case errors.Is(err, service.ErrInvalidCity), errors.Is(err, service.ErrInvalidUF):
+		statusCode = http.StatusUnprocessableEntity
+		message = err.Error()
+	case errors.Is(err, service.ErrInvalidPrecision):
+		statusCode = http.StatusUnprocessableEntity
+		message = service.ErrInvalidPrecision.Error()
+	case errors.Is(err, service.ErrInvalidLanguage):
+		statusCode = http.StatusUnprocessableEntity
+		message = service.ErrInvalidLanguage.Error()
+	case errors.Is(err, service.ErrInvalidCoordinates):
+		statusCode = http.StatusUnprocessableEntity
+		message = service.ErrInvalidCoordinates.Error()
 	case errors.Is(err, service.ErrCEPNotFound):
 		statusCode = http.StatusNotFound
 		message = service.ErrCEPNotFound.Error()
+	case errors.Is(err, service.ErrCityNotFound):
+		statusCode = http.StatusNotFound
+		message = service.ErrCityNotFound.Error()
+	case errors.Is(err, service.ErrCoordinatesNotFound):
+		statusCode = http.StatusNotFound
+		message = service.ErrCoordinatesNotFound.Error()
+	case errors.Is(err, service.ErrIPNotLocated):
+		statusCode = http.StatusNotFound
+		message = service.ErrIPNotLocated.Error()
+	case errors.Is(err, service.ErrGeolocationUnavailable):
+		statusCode = http.StatusInternalServerError
+		message = service.ErrGeolocationUnavailable.Error()
+	case errors.Is(err, service.ErrRateLimited):
+		statusCode = http.StatusServiceUnavailable
+		message = service.ErrRateLimited.Error()
+	case errors.Is(err, service.ErrLocationUnavailable):
+		statusCode = http.StatusInternalServerError
+		message = service.ErrLocationUnavailable.Error()
 	case errors.Is(err, service.ErrWeatherDataUnavailable):
 		statusCode = http.StatusInternalServerError
 		message = service.ErrWeatherDataUnavailable.Error()
@@ -67,14 +433,15 @@ func (h *WeatherHandler) handleError(w http.ResponseWriter, err error) {
 		statusCode = http.StatusInternalServerError
 		message = "internal server error"
 	}
-
-	errorResponse := domain.ErrorResponse{Message: message}
-	h.sendJSON(w, statusCode, errorResponse)
+	return statusCode, message
 }
 
-// sendJSON sends a JSON response
-func (h *WeatherHandler) sendJSON(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
+// retryAfterSeconds formats the delay requested upstream as whole seconds, at least 1
+func retryAfterSeconds(err error) string {
+	var rateLimitErr *domain.RateLimitError
+	seconds := 1.0
+	if errors.As(err, &rateLimitErr) {
+		seconds = math.Max(seconds, math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+	}
+	return strconv.Itoa(int(seconds))
 }
@@ -0,0 +1,83 @@
+// Package history records weather lookups for product analytics and
+// aggregates them for the stats endpoint
+package history
+
+import (
+	"context"
+	"time"
+)
+
+// Backends accepted by HISTORY_BACKEND
+const (
+	BackendSQLite = "sqlite"
+	BackendNone   = "none"
+)
+
+// Kinds of lookup, one per way of asking for the weather
+const (
+	KindCEP    = "cep"
+	KindCity   = "city"
+	KindCoords = "coords"
+	KindIP     = "ip"
+)
+
+// Outcomes of a lookup; every outcome but OutcomeOK counts as an error
+const (
+	OutcomeOK           = "ok"
+	OutcomeInvalidInput = "invalid_input"
+	OutcomeNotFound     = "not_found"
+	OutcomeRateLimited  = "rate_limited"
+	OutcomeError        = "error"
+)
+
+// Lookup is one weather lookup. City and UF are empty for coordinate
+// lookups and for failures before the location was known.
+type Lookup struct {
+	Time    time.Time
+	Kind    string
+	CEP     string
+	City    string
+	UF      string
+	TempC   *float64
+	Latency time.Duration
+	Outcome string
+}
+
+// Recorder receives lookups as they finish; implementations must not block the caller
+type Recorder interface {
+	Record(lookup Lookup)
+}
+
+// Stats aggregates the lookups made since a point in time
+type Stats struct {
+	Since          time.Time
+	Lookups        int
+	Errors         int
+	AverageLatency time.Duration
+	Outcomes       map[string]int
+	TopCities      []CityCount
+}
+
+// ErrorRate is the fraction of lookups that did not succeed, 0 without lookups
+func (s *Stats) ErrorRate() float64 {
+	if s.Lookups == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Lookups)
+}
+
+// CityCount is how often a city was looked up and its average temperature
+// over the successful lookups, nil when all of them failed
+type CityCount struct {
+	City         string
+	UF           string
+	Lookups      int
+	AverageTempC *float64
+}
+
+// Store persists lookups and aggregates them
+type Store interface {
+	Save(ctx context.Context, lookups []Lookup) error
+	Stats(ctx context.Context, since time.Time, top int) (*Stats, error)
+	Close() error
+}
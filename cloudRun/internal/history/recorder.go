@@ -0,0 +1,112 @@
+package history
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Batching of the background writes
+const (
+	flushSize     = 100
+	flushInterval = time.Second
+	saveTimeout   = 5 * time.Second
+)
+
+// AsyncRecorder buffers lookups and saves them in batches from a background
+// goroutine, so a slow store never delays a response. Lookups arriving while
+// the buffer is full are dropped and counted.
+type AsyncRecorder struct {
+	store    Store
+	lookups  chan Lookup
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	dropped  atomic.Int64
+}
+
+// NewAsyncRecorder starts saving lookups to store, buffering up to buffer of them
+func NewAsyncRecorder(store Store, buffer int) *AsyncRecorder {
+	r := &AsyncRecorder{
+		store:   store,
+		lookups: make(chan Lookup, max(1, buffer)),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record queues lookup without blocking
+func (r *AsyncRecorder) Record(lookup Lookup) {
+	select {
+	case r.lookups <- lookup:
+	default:
+		if r.dropped.Add(1) == 1 {
+			slog.Warn("Query history buffer full, dropping lookups")
+		}
+	}
+}
+
+// Dropped returns how many lookups were dropped because the buffer was full
+func (r *AsyncRecorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close saves the buffered lookups and stops the background goroutine,
+// giving up when ctx ends. Lookups recorded afterwards are not saved.
+func (r *AsyncRecorder) Close(ctx context.Context) error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *AsyncRecorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Lookup, 0, flushSize)
+	for {
+		select {
+		case lookup := <-r.lookups:
+			if batch = append(batch, lookup); len(batch) >= flushSize {
+				batch = r.save(batch)
+			}
+		case <-ticker.C:
+			batch = r.save(batch)
+		case <-r.stop:
+			for {
+				select {
+				case lookup := <-r.lookups:
+					if batch = append(batch, lookup); len(batch) >= flushSize {
+						batch = r.save(batch)
+					}
+				default:
+					r.save(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// save writes batch to the store and returns it emptied; failed batches are
+// logged and discarded, since history must not grow without bound
+func (r *AsyncRecorder) save(batch []Lookup) []Lookup {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	defer cancel()
+	if err := r.store.Save(ctx, batch); err != nil {
+		slog.Error("Failed to save query history", "lookups", len(batch), "error", err)
+	}
+	return batch[:0]
+}
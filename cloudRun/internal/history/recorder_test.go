@@ -0,0 +1,76 @@
+package history
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore keeps saved lookups in a slice; block delays every save until closed
+type memoryStore struct {
+	mu      sync.Mutex
+	lookups []Lookup
+	block   chan struct{}
+}
+
+func (s *memoryStore) Save(ctx context.Context, lookups []Lookup) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lookups = append(s.lookups, lookups...)
+	return nil
+}
+
+func (s *memoryStore) Stats(ctx context.Context, since time.Time, top int) (*Stats, error) {
+	return &Stats{}, nil
+}
+
+func (s *memoryStore) Close() error { return nil }
+
+func (s *memoryStore) saved() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.lookups)
+}
+
+func TestAsyncRecorder_FlushesOnClose(t *testing.T) {
+	store := &memoryStore{}
+	recorder := NewAsyncRecorder(store, 1000)
+
+	for range flushSize + 5 {
+		recorder.Record(Lookup{Kind: KindCEP, Outcome: OutcomeOK})
+	}
+	if err := recorder.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved := store.saved(); saved != flushSize+5 {
+		t.Errorf("Expected %d saved lookups, got %d", flushSize+5, saved)
+	}
+}
+
+func TestAsyncRecorder_DropsWhenFull(t *testing.T) {
+	store := &memoryStore{block: make(chan struct{})}
+	recorder := NewAsyncRecorder(store, 1)
+
+	// The first batch fills up and blocks in Save; later lookups overflow the buffer
+	for range flushSize + 10 {
+		recorder.Record(Lookup{Kind: KindCEP, Outcome: OutcomeOK})
+	}
+	if recorder.Dropped() == 0 {
+		t.Error("Expected lookups to be dropped while the store is blocked")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := recorder.Close(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected Close to give up with the context, got %v", err)
+	}
+
+	close(store.block)
+	if err := recorder.Close(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go driver, so the image keeps building with CGO_ENABLED=0
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS lookups (
+	id         INTEGER PRIMARY KEY,
+	time_ms    INTEGER NOT NULL,
+	kind       TEXT NOT NULL,
+	cep        TEXT NOT NULL DEFAULT '',
+	city       TEXT NOT NULL DEFAULT '',
+	uf         TEXT NOT NULL DEFAULT '',
+	temp_c     REAL,
+	latency_us INTEGER NOT NULL,
+	outcome    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS lookups_time ON lookups (time_ms);
+`
+
+// SQLiteStore keeps the query history in a SQLite database file
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens or creates the database at path; ":memory:" keeps it in memory
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// One connection serializes the writer and the stats queries, and keeps a
+	// ":memory:" database from being opened once per connection
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=5000", schema} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to initialize query history: %w", err)
+		}
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Save inserts lookups in one transaction
+func (s *SQLiteStore) Save(ctx context.Context, lookups []Lookup) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO lookups (time_ms, kind, cep, city, uf, temp_c, latency_us, outcome) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, l := range lookups {
+		if _, err := stmt.ExecContext(ctx, l.Time.UnixMilli(), l.Kind, l.CEP, l.City, l.UF, l.TempC, l.Latency.Microseconds(), l.Outcome); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Stats aggregates the lookups made since since, with the top most looked up cities
+func (s *SQLiteStore) Stats(ctx context.Context, since time.Time, top int) (*Stats, error) {
+	stats := &Stats{Since: since, Outcomes: map[string]int{}, TopCities: []CityCount{}}
+	from := since.UnixMilli()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT outcome, COUNT(*), SUM(latency_us) FROM lookups WHERE time_ms >= ? GROUP BY outcome`, from)
+	if err != nil {
+		return nil, err
+	}
+	var totalLatency int64
+	for rows.Next() {
+		var (
+			outcome      string
+			count        int
+			latencySumUs int64
+		)
+		if err := rows.Scan(&outcome, &count, &latencySumUs); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.Outcomes[outcome] = count
+		stats.Lookups += count
+		if outcome != OutcomeOK {
+			stats.Errors += count
+		}
+		totalLatency += latencySumUs
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if stats.Lookups > 0 {
+		stats.AverageLatency = time.Duration(totalLatency/int64(stats.Lookups)) * time.Microsecond
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT city, uf, COUNT(*) AS n, AVG(temp_c)
+		FROM lookups
+		WHERE time_ms >= ? AND city != ''
+		GROUP BY city, uf
+		ORDER BY n DESC, city, uf
+		LIMIT ?`, from, top)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			city    CityCount
+			avgTemp sql.NullFloat64
+		)
+		if err := rows.Scan(&city.City, &city.UF, &city.Lookups, &avgTemp); err != nil {
+			return nil, err
+		}
+		if avgTemp.Valid {
+			city.AverageTempC = &avgTemp.Float64
+		}
+		stats.TopCities = append(stats.TopCities, city)
+	}
+	return stats, rows.Err()
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
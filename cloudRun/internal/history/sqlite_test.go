@@ -0,0 +1,59 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSQLiteStore_Stats(t *testing.T) {
+	store, err := OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now()
+	temp := func(v float64) *float64 { return &v }
+	lookups := []Lookup{
+		{Time: now, Kind: KindCEP, CEP: "01310100", City: "São Paulo", UF: "SP", TempC: temp(20), Latency: 100 * time.Millisecond, Outcome: OutcomeOK},
+		{Time: now, Kind: KindCity, City: "São Paulo", UF: "SP", TempC: temp(24), Latency: 200 * time.Millisecond, Outcome: OutcomeOK},
+		{Time: now, Kind: KindCEP, CEP: "20040020", City: "Rio de Janeiro", UF: "RJ", Latency: 300 * time.Millisecond, Outcome: OutcomeError},
+		{Time: now, Kind: KindCEP, CEP: "123", Latency: 0, Outcome: OutcomeInvalidInput},
+		{Time: now.Add(-48 * time.Hour), Kind: KindCity, City: "Curitiba", UF: "PR", Latency: time.Second, Outcome: OutcomeOK},
+	}
+	if err := store.Save(context.Background(), lookups); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats, err := store.Stats(context.Background(), now.Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Lookups != 4 || stats.Errors != 2 {
+		t.Errorf("Expected 4 lookups and 2 errors in the window, got %d and %d", stats.Lookups, stats.Errors)
+	}
+	if stats.ErrorRate() != 0.5 {
+		t.Errorf("Expected error rate 0.5, got %v", stats.ErrorRate())
+	}
+	if stats.AverageLatency != 150*time.Millisecond {
+		t.Errorf("Expected average latency 150ms, got %v", stats.AverageLatency)
+	}
+	if stats.Outcomes[OutcomeOK] != 2 || stats.Outcomes[OutcomeInvalidInput] != 1 {
+		t.Errorf("Unexpected outcomes %v", stats.Outcomes)
+	}
+
+	if len(stats.TopCities) != 2 {
+		t.Fatalf("Expected 2 cities in the window, got %+v", stats.TopCities)
+	}
+	if sp := stats.TopCities[0]; sp.City != "São Paulo" || sp.Lookups != 2 || sp.AverageTempC == nil || *sp.AverageTempC != 22 {
+		t.Errorf("Expected São Paulo first with 2 lookups at 22°C, got %+v", sp)
+	}
+	if rj := stats.TopCities[1]; rj.City != "Rio de Janeiro" || rj.AverageTempC != nil {
+		t.Errorf("Expected Rio de Janeiro without temperature, got %+v", rj)
+	}
+
+	if stats, _ := store.Stats(context.Background(), now.Add(-24*time.Hour), 1); len(stats.TopCities) != 1 {
+		t.Errorf("Expected top to limit the cities, got %+v", stats.TopCities)
+	}
+}
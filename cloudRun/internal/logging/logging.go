@@ -0,0 +1,160 @@
+// Package logging writes structured logs in the Cloud Logging format, correlated
+// with the trace of the request that produced them.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formats accepted by LOG_FORMAT
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// LevelCritical is logged with CRITICAL severity, e.g. right before exiting
+const LevelCritical = slog.LevelError + 4
+
+// Special fields recognized by Cloud Logging in JSON payloads
+const (
+	TraceKey          = "logging.googleapis.com/trace"
+	SpanIDKey         = "logging.googleapis.com/spanId"
+	TraceSampledKey   = "logging.googleapis.com/trace_sampled"
+	SourceLocationKey = "logging.googleapis.com/sourceLocation"
+	HTTPRequestKey    = "httpRequest"
+)
+
+// Fields that send an entry to Error Reporting, which groups the entries by the
+// stack trace; used for panics, which are not errors a handler returned
+const (
+	ErrorReportingTypeKey = "@type"
+	ReportedErrorEvent    = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+	StackTraceKey         = "stack_trace"
+)
+
+// metadataProjectURL answers the project ID on Google Cloud
+const metadataProjectURL = "http://metadata.google.internal/computeMetadata/v1/project/project-id"
+
+// New builds a logger writing to w in format (json or text) from level on.
+// Entries logged with a context carrying a span get the trace fields, named
+// after projectID when known so the console groups them under the trace.
+func New(w io.Writer, format string, level slog.Level, projectID string) (*slog.Logger, error) {
+	var handler slog.Handler
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: true, Level: level, ReplaceAttr: cloudLoggingAttr})
+	case FormatText:
+		handler = slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected %s or %s)", format, FormatJSON, FormatText)
+	}
+	return slog.New(traceHandler{Handler: handler, projectID: projectID}), nil
+}
+
+// ParseLevel parses debug, info, warn or error
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
+
+// ProjectID returns GOOGLE_CLOUD_PROJECT or, on Cloud Run, asks the metadata
+// server. It returns an empty string when the project is unknown.
+func ProjectID(ctx context.Context) string {
+	if project := os.Getenv("GOOGLE_CLOUD_PROJECT"); project != "" {
+		return project
+	}
+	if os.Getenv("K_SERVICE") == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataProjectURL, nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		slog.Warn("Could not read the project ID from the metadata server", "error", err)
+		return ""
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		slog.Warn("Could not read the project ID from the metadata server", "status", resp.StatusCode)
+		return ""
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// cloudLoggingAttr renames the built-in keys to the ones Cloud Logging expects
+func cloudLoggingAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		return slog.String("severity", severity(a.Value.Any().(slog.Level)))
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.SourceKey:
+		a.Key = SourceLocationKey
+	}
+	return a
+}
+
+// severity maps a slog level to a Cloud Logging severity
+func severity(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARNING"
+	case level < LevelCritical:
+		return "ERROR"
+	default:
+		return "CRITICAL"
+	}
+}
+
+// traceHandler adds the trace fields of the span in the record's context
+type traceHandler struct {
+	slog.Handler
+	projectID string
+}
+
+func (h traceHandler) Handle(ctx context.Context, record slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		traceName := sc.TraceID().String()
+		if h.projectID != "" {
+			traceName = "projects/" + h.projectID + "/traces/" + traceName
+		}
+		record.AddAttrs(
+			slog.String(TraceKey, traceName),
+			slog.String(SpanIDKey, sc.SpanID().String()),
+			slog.Bool(TraceSampledKey, sc.IsSampled()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{Handler: h.Handler.WithAttrs(attrs), projectID: h.projectID}
+}
+
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{Handler: h.Handler.WithGroup(name), projectID: h.projectID}
+}
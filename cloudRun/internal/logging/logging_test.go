@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloudrun/pkg/telemetry"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// decode parses the single JSON entry written to buf
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON entry, got %q: %v", buf.String(), err)
+	}
+	return entry
+}
+
+func TestNew_CloudLoggingFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, slog.LevelInfo, "my-project")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sc, _ := telemetry.ParseCloudTraceContext("105445aa7843bc8bf206b12000100000/1;o=1")
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+	logger.WarnContext(ctx, "Cache unavailable", "cache", "viacep")
+
+	entry := decode(t, &buf)
+	expected := map[string]any{
+		"severity":      "WARNING",
+		"message":       "Cache unavailable",
+		"cache":         "viacep",
+		TraceKey:        "projects/my-project/traces/105445aa7843bc8bf206b12000100000",
+		SpanIDKey:       "0000000000000001",
+		TraceSampledKey: true,
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %s %v, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry[SourceLocationKey].(map[string]any); !ok {
+		t.Errorf("Expected %s, got %v", SourceLocationKey, entry[SourceLocationKey])
+	}
+	if _, ok := entry["level"]; ok {
+		t.Error("Expected level to be renamed to severity")
+	}
+}
+
+func TestNew_WithoutSpan(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := New(&buf, FormatJSON, slog.LevelInfo, "my-project")
+
+	logger.Info("Server starting")
+
+	entry := decode(t, &buf)
+	if _, ok := entry[TraceKey]; ok {
+		t.Errorf("Expected no trace without a span, got %v", entry[TraceKey])
+	}
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := New(&buf, FormatJSON, slog.LevelWarn, "")
+
+	logger.Info("dropped")
+	if buf.Len() != 0 {
+		t.Errorf("Expected info to be dropped at warn level, got %q", buf.String())
+	}
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "xml", slog.LevelInfo, ""); err == nil {
+		t.Error("Expected error for unknown format, got nil")
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	testCases := map[slog.Level]string{
+		slog.LevelDebug: "DEBUG",
+		slog.LevelInfo:  "INFO",
+		slog.LevelWarn:  "WARNING",
+		slog.LevelError: "ERROR",
+		LevelCritical:   "CRITICAL",
+	}
+	for level, expected := range testCases {
+		if got := severity(level); got != expected {
+			t.Errorf("Expected %s for %v, got %s", expected, level, got)
+		}
+	}
+}
+
+func TestMiddleware_LogsHTTPRequestWithCloudTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := New(&buf, FormatJSON, slog.LevelInfo, "my-project")
+	previousLogger := slog.Default()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(previousLogger)
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(telemetry.CloudTraceContext{})
+	defer otel.SetTextMapPropagator(previousPropagator)
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"can not find zipcode"}`))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/weather/99999999", nil)
+	req.Header.Set(telemetry.CloudTraceHeader, "105445aa7843bc8bf206b12000100000/1;o=1")
+	req.Header.Set("User-Agent", "curl/8.0")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := decode(t, &buf)
+	if entry["severity"] != "WARNING" {
+		t.Errorf("Expected WARNING for a 404, got %v", entry["severity"])
+	}
+	if entry[TraceKey] != "projects/my-project/traces/105445aa7843bc8bf206b12000100000" {
+		t.Errorf("Expected the trace of X-Cloud-Trace-Context, got %v", entry[TraceKey])
+	}
+	httpRequest, ok := entry[HTTPRequestKey].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected %s, got %v", HTTPRequestKey, entry[HTTPRequestKey])
+	}
+	expected := map[string]any{
+		"requestMethod": "GET",
+		"requestUrl":    "/weather/99999999",
+		"status":        float64(404),
+		"responseSize":  float64(34),
+		"userAgent":     "curl/8.0",
+	}
+	for key, value := range expected {
+		if httpRequest[key] != value {
+			t.Errorf("Expected httpRequest.%s %v, got %v", key, value, httpRequest[key])
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"cloudrun/internal/ratelimit"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware logs every request with an httpRequest field. Requests without
+// a span (e.g. filtered out of tracing) are still correlated through the
+// incoming X-Cloud-Trace-Context or traceparent header.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+			r = r.WithContext(ctx)
+		}
+
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		slog.Log(ctx, level(rw.statusCode), fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, rw.statusCode),
+			slog.Group(HTTPRequestKey,
+				slog.String("requestMethod", r.Method),
+				slog.String("requestUrl", r.URL.String()),
+				slog.Int("status", rw.statusCode),
+				slog.Int64("responseSize", rw.size),
+				slog.String("userAgent", r.UserAgent()),
+				slog.String("remoteIp", ratelimit.ClientIP(r)),
+				slog.String("referer", r.Referer()),
+				slog.String("latency", fmt.Sprintf("%.9fs", time.Since(start).Seconds())),
+				slog.String("protocol", r.Proto),
+			),
+		)
+	})
+}
+
+// level logs server errors as errors and client errors as warnings
+func level(statusCode int) slog.Level {
+	switch {
+	case statusCode >= 500:
+		return slog.LevelError
+	case statusCode >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// responseWriter captures the status code and body size written by a handler
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int64
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.statusCode = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
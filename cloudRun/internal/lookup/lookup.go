@@ -0,0 +1,251 @@
+// Package lookup runs one-off weather lookups from the terminal, shared by the
+// `cloudrun lookup` subcommand and the cli binary
+package lookup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"cloudrun/config"
+	"cloudrun/internal/domain"
+	"cloudrun/internal/repository"
+	"cloudrun/internal/service"
+	"cloudrun/internal/status"
+	"cloudrun/pkg/validator"
+)
+
+// Exit codes of a lookup
+const (
+	ExitOK             = 0
+	ExitError          = 1
+	ExitInvalidInput   = 2
+	ExitNotFound       = 3
+	ExitWeatherFailure = 4
+	ExitTimeout        = 5
+)
+
+const usage = "usage: %s [-format json|pretty] [-json] [-detail] [-meta] [-precision N] [-lang pt|en] [-uf UF] [-timeout 15s] [-verbose] <cep|cidade>\n"
+
+// Main builds the service from the environment, like the API does without the
+// cache, runs the lookup of args and returns the process exit code; name is
+// the command shown in the usage message
+func Main(name string, args []string) int {
+	cfg := config.New()
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitError
+	}
+	weatherData, _, err := NewWeatherData(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitError
+	}
+
+	locationRepo := repository.NewViaCEPRepository().WithTimeout(cfg.ViaCEPTimeout).WithRetryPolicy(cfg.ViaCEPRetry)
+	weatherService := service.NewWeatherService(locationRepo, weatherData).
+		WithDefaultPrecision(cfg.TemperaturePrecision).
+		WithDefaultLanguage(cfg.WeatherLanguage)
+	return Run(context.Background(), weatherService, name, args, os.Stdout, os.Stderr)
+}
+
+// weatherProviderLabels names the providers on the status page
+var weatherProviderLabels = map[string]string{
+	repository.ProviderWeatherAPI: "WeatherAPI",
+	repository.ProviderOpenMeteo:  "Open-Meteo",
+}
+
+// NewWeatherData builds the providers of WEATHER_PROVIDERS behind a failover
+// service, in the configured order, with one status check per provider
+func NewWeatherData(cfg *config.Config) (*repository.FailoverWeatherDataService, []status.Check, error) {
+	var (
+		providers []repository.NamedWeatherProvider
+		checks    []status.Check
+	)
+	for _, name := range cfg.WeatherProviders {
+		if name == repository.ProviderWeatherAPI && cfg.WeatherAPIKey == "" {
+			return nil, nil, config.ErrMissingWeatherAPIKey
+		}
+		provider, err := repository.NewWeatherProvider(name, cfg.WeatherAPIKey, cfg.WeatherTimeout, cfg.WeatherRetry)
+		if err != nil {
+			return nil, nil, err
+		}
+		providers = append(providers, repository.NamedWeatherProvider{Name: name, Provider: provider})
+		checks = append(checks, status.Check{Name: weatherProviderLabels[name], Probe: provider.Ping})
+	}
+	if len(providers) == 0 {
+		return nil, nil, config.ErrNoWeatherProviders
+	}
+	return repository.NewFailoverWeatherDataService(providers...), checks, nil
+}
+
+// Run parses the arguments, looks up the weather of the CEP or city and writes
+// it to stdout; errors go to stderr, or to stdout as JSON with -format json
+func Run(ctx context.Context, weatherService *service.WeatherService, name string, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, usage, name)
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "pretty", "output format: json or pretty")
+	asJSON := fs.Bool("json", false, "same as -format json")
+	detail := fs.Bool("detail", false, "include the normalized weather condition")
+	meta := fs.Bool("meta", false, "include the provider, fetch time and whether the data was cached")
+	precision := fs.Int("precision", service.PrecisionRaw, "decimal places of the temperatures, 0 to 6, or -1 to keep them unrounded; overrides TEMPERATURE_PRECISION")
+	lang := fs.String("lang", "", "language of the condition text, en or pt; overrides WEATHER_LANGUAGE")
+	uf := fs.String("uf", "", "state of the city, e.g. SP")
+	timeout := fs.Duration("timeout", 15*time.Second, "maximum time for the whole lookup")
+	verbose := fs.Bool("verbose", false, "log the upstream calls to stderr")
+	if err := fs.Parse(args); err != nil {
+		return ExitInvalidInput
+	}
+	if fs.NArg() == 0 || (*format != "json" && *format != "pretty") {
+		fs.Usage()
+		return ExitInvalidInput
+	}
+	if *asJSON {
+		*format = "json"
+	}
+	// Unquoted city names arrive as several arguments
+	query := strings.Join(fs.Args(), " ")
+
+	level := slog.LevelError + 1
+	if *verbose {
+		level = slog.LevelInfo
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: level})))
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	// Only an explicit -precision overrides TEMPERATURE_PRECISION
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "precision" {
+			ctx, err = service.WithPrecision(ctx, *precision)
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "-precision must be -1 to 6")
+		return ExitInvalidInput
+	}
+	if *lang != "" {
+		if ctx, err = service.WithLanguage(ctx, *lang); err != nil {
+			fmt.Fprintln(stderr, "-lang must be en or pt")
+			return ExitInvalidInput
+		}
+	}
+
+	isCEP := isCEPQuery(query)
+	if isCEP && *uf != "" {
+		fmt.Fprintln(stderr, "-uf only applies to city lookups")
+		return ExitInvalidInput
+	}
+
+	var result interface{ DropMeta() }
+	switch {
+	case isCEP && *detail:
+		result, err = weatherService.GetDetailedWeatherByCEP(ctx, query)
+	case isCEP:
+		result, err = weatherService.GetWeatherByCEP(ctx, query)
+	case *detail:
+		result, err = weatherService.GetDetailedWeatherByCity(ctx, query, *uf)
+	default:
+		result, err = weatherService.GetWeatherByCity(ctx, query, *uf)
+	}
+	if err != nil {
+		if *format == "json" {
+			writeJSON(stdout, domain.ErrorResponse{Message: err.Error()})
+		} else {
+			fmt.Fprintf(stderr, "lookup %s: %v\n", query, err)
+		}
+		return exitCode(err)
+	}
+
+	// Like the API without meta=true
+	if !*meta {
+		result.DropMeta()
+	}
+	if *format == "json" {
+		writeJSON(stdout, result)
+		return ExitOK
+	}
+	if isCEP {
+		query = validator.CleanCEP(query)
+	}
+	printWeather(stdout, isCEP, query, result)
+	return ExitOK
+}
+
+// isCEPQuery reports whether query is written like a CEP, made only of
+// digits, dashes, dots and spaces; anything else is looked up as a city
+func isCEPQuery(query string) bool {
+	cep := validator.CleanCEP(query)
+	if cep == "" {
+		return false
+	}
+	for _, r := range cep {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// exitCode maps service errors to exit codes
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidCEP), errors.Is(err, service.ErrInvalidCity), errors.Is(err, service.ErrInvalidUF):
+		return ExitInvalidInput
+	case errors.Is(err, service.ErrCEPNotFound), errors.Is(err, service.ErrCityNotFound):
+		return ExitNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	case errors.Is(err, service.ErrLocationUnavailable), errors.Is(err, service.ErrWeatherDataUnavailable), errors.Is(err, service.ErrRateLimited):
+		return ExitWeatherFailure
+	default:
+		return ExitError
+	}
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v any) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// printWeather writes a human-readable summary of a WeatherResponse or DetailedWeatherResponse
+func printWeather(w io.Writer, isCEP bool, query string, result interface{ DropMeta() }) {
+	var (
+		weather   domain.WeatherResponse
+		condition *domain.WeatherCondition
+	)
+	switch r := result.(type) {
+	case *domain.DetailedWeatherResponse:
+		weather, condition = r.WeatherResponse, &r.Condition
+	case *domain.WeatherResponse:
+		weather = *r
+	}
+
+	if isCEP {
+		fmt.Fprintf(w, "CEP:         %s\n", query)
+	} else {
+		fmt.Fprintf(w, "Cidade:      %s\n", query)
+	}
+	fmt.Fprintf(w, "Temperatura: %.1f°C | %.1f°F | %.1fK\n", weather.TempC, weather.TempF, weather.TempK)
+	if condition != nil {
+		fmt.Fprintf(w, "Condição:    %s (%s)\n", condition.Text, condition.Code)
+	}
+	if weather.Meta != nil {
+		fmt.Fprintf(w, "Fonte:       %s em %s (cache: %t)\n", weather.Meta.Provider, weather.Meta.FetchedAt.Local().Format("02/01/2006 15:04:05"), weather.Meta.Cached)
+	}
+}
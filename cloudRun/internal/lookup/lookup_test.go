@@ -0,0 +1,138 @@
+package lookup
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cloudrun/config"
+	"cloudrun/internal/domain"
+	"cloudrun/internal/repository"
+	"cloudrun/internal/service"
+)
+
+// mockRepository serves one CEP and a few cities
+type mockRepository struct{}
+
+func (m *mockRepository) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	if cep == "01310100" {
+		return &domain.ViaCEPResponse{CEP: "01310-100", Localidade: "São Paulo", UF: "SP"}, nil
+	}
+	return nil, domain.ErrCEPNotFound
+}
+
+func (m *mockRepository) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	switch location {
+	case "São Paulo,SP", "São Paulo,Brazil":
+		return &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{
+			TempC:     28.5,
+			IsDay:     1,
+			Condition: domain.WeatherAPICondition{Text: "Partly cloudy", Code: 1003},
+		}}, nil
+	case "Atlantis,Brazil":
+		return nil, domain.ErrLocationNotFound
+	case "Belo Horizonte,MG":
+		return nil, &domain.RateLimitError{RetryAfter: time.Second}
+	case "Slow,Brazil":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return nil, service.ErrWeatherDataUnavailable
+}
+
+func (m *mockRepository) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	return nil, service.ErrWeatherDataUnavailable
+}
+
+func TestRun(t *testing.T) {
+	weatherService := service.NewWeatherService(&mockRepository{}, &mockRepository{})
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		{"cep", []string{"01310-100"}, ExitOK, "CEP:         01310100"},
+		{"pretty", []string{"01310-100"}, ExitOK, "Temperatura: 28.5°C"},
+		{"format json", []string{"-format", "json", "01310100"}, ExitOK, `"temp_C": 28.5`},
+		{"json", []string{"--json", "01310100"}, ExitOK, `"temp_C": 28.5`},
+		{"cep detail", []string{"--detail", "01310100"}, ExitOK, "Condição:    Partly cloudy (partly_cloudy)"},
+		{"city", []string{"São", "Paulo"}, ExitOK, "Cidade:      São Paulo"},
+		{"city with uf", []string{"--uf", "SP", "--json", "São Paulo"}, ExitOK, `"temp_F": 83.3`},
+		{"meta", []string{"--json", "--meta", "01310100"}, ExitOK, `"cached": false`},
+		{"precision", []string{"--json", "--precision", "0", "01310100"}, ExitOK, `"temp_C": 29`},
+		{"invalid precision", []string{"--precision", "9", "01310100"}, ExitInvalidInput, ""},
+		{"lang", []string{"--detail", "--lang", "pt", "01310100"}, ExitOK, "Condição:"},
+		{"invalid lang", []string{"--lang", "es", "01310100"}, ExitInvalidInput, ""},
+		{"invalid CEP", []string{"-format", "json", "123"}, ExitInvalidInput, `"message": "invalid zipcode"`},
+		{"invalid uf", []string{"--uf", "XX", "São Paulo"}, ExitInvalidInput, ""},
+		{"uf with CEP", []string{"--uf", "SP", "01310100"}, ExitInvalidInput, ""},
+		{"CEP not found", []string{"99999999"}, ExitNotFound, ""},
+		{"city not found", []string{"--json", "Atlantis"}, ExitNotFound, `"message": "can not find city"`},
+		{"rate limited", []string{"--uf", "MG", "Belo Horizonte"}, ExitWeatherFailure, ""},
+		{"timeout", []string{"--timeout", "20ms", "Slow"}, ExitTimeout, ""},
+		{"missing query", nil, ExitInvalidInput, ""},
+		{"unknown format", []string{"-format", "xml", "01310100"}, ExitInvalidInput, ""},
+		{"unknown flag", []string{"--xml", "01310100"}, ExitInvalidInput, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			code := Run(context.Background(), weatherService, "lookup", tt.args, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.wantCode, code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantOut) {
+				t.Errorf("Expected output to contain %q, got %q", tt.wantOut, stdout.String())
+			}
+		})
+	}
+}
+
+func TestIsCEPQuery(t *testing.T) {
+	tests := map[string]bool{
+		"01310-100":  true,
+		"01.310-100": true,
+		"123":        true,
+		"São Paulo":  false,
+		"0131O100":   false,
+		" - ":        false,
+	}
+	for query, want := range tests {
+		if got := isCEPQuery(query); got != want {
+			t.Errorf("isCEPQuery(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestNewWeatherData(t *testing.T) {
+	weatherData, checks, err := NewWeatherData(&config.Config{
+		WeatherAPIKey:    "key",
+		WeatherProviders: []string{repository.ProviderWeatherAPI, repository.ProviderOpenMeteo},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(checks) != 2 || checks[0].Name != "WeatherAPI" || checks[1].Name != "Open-Meteo" {
+		t.Errorf("Expected one check per provider in failover order, got %+v", checks)
+	}
+	if providers := weatherData.Providers(); len(providers) != 2 || providers[0].Name != repository.ProviderWeatherAPI {
+		t.Errorf("Expected WeatherAPI first, got %+v", providers)
+	}
+
+	if _, _, err := NewWeatherData(&config.Config{WeatherProviders: []string{repository.ProviderOpenMeteo}}); err != nil {
+		t.Errorf("Expected Open-Meteo to work without WEATHER_API_KEY, got %v", err)
+	}
+
+	if _, _, err := NewWeatherData(&config.Config{WeatherProviders: []string{repository.ProviderWeatherAPI}}); !errors.Is(err, config.ErrMissingWeatherAPIKey) {
+		t.Errorf("Expected ErrMissingWeatherAPIKey, got %v", err)
+	}
+
+	if _, _, err := NewWeatherData(&config.Config{}); !errors.Is(err, config.ErrNoWeatherProviders) {
+		t.Errorf("Expected ErrNoWeatherProviders, got %v", err)
+	}
+}
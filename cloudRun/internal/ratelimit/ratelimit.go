@@ -0,0 +1,114 @@
+// Package ratelimit limits requests per client IP so a public deployment cannot drain the WeatherAPI quota.
+package ratelimit
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloudrun/internal/domain"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is how long a client is remembered after its last request
+const idleTimeout = 10 * time.Minute
+
+// Limiter is a token bucket per client IP: requestsPerMinute tokens are refilled
+// each minute and up to burst can be spent at once
+type Limiter struct {
+	limit rate.Limit
+	burst int
+	now   func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+}
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// New creates a limiter allowing requestsPerMinute per IP with the given burst
+func New(requestsPerMinute, burst int) *Limiter {
+	return &Limiter{
+		limit:   rate.Limit(float64(requestsPerMinute) / 60),
+		burst:   burst,
+		now:     time.Now,
+		clients: make(map[string]*client),
+	}
+}
+
+// Allow spends a token of ip, or reports how long to wait for the next one
+func (l *Limiter) Allow(ip string) (bool, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= idleTimeout {
+		l.sweep(now)
+	}
+
+	c, ok := l.clients[ip]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[ip] = c
+	}
+	c.lastSeen = now
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// sweep forgets clients idle for idleTimeout; their buckets are full again by then
+func (l *Limiter) sweep(now time.Time) {
+	for ip, c := range l.clients {
+		if now.Sub(c.lastSeen) >= idleTimeout {
+			delete(l.clients, ip)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Middleware answers 429 with Retry-After once the client IP runs out of tokens
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, retryAfter := l.Allow(ClientIP(r))
+		if !allowed {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(domain.ErrorResponse{Message: "too many requests"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientIP returns the address Cloud Run appended to X-Forwarded-For, falling
+// back to the peer address. Earlier entries are ignored since clients can forge them.
+func ClientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		entries := strings.Split(forwarded, ",")
+		if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(requestsPerMinute, burst int) (*Limiter, *time.Time) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(requestsPerMinute, burst)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestAllow_BurstThenRefill(t *testing.T) {
+	l, now := newTestLimiter(60, 2)
+
+	for i := 0; i < 2; i++ {
+		if allowed, _ := l.Allow("1.2.3.4"); !allowed {
+			t.Fatalf("Expected request %d within burst to be allowed", i+1)
+		}
+	}
+
+	allowed, retryAfter := l.Allow("1.2.3.4")
+	if allowed {
+		t.Fatal("Expected request over burst to be rejected")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("Expected retry after 1s, got %v", retryAfter)
+	}
+
+	if allowed, _ := l.Allow("5.6.7.8"); !allowed {
+		t.Error("Expected another IP to have its own bucket")
+	}
+
+	*now = now.Add(time.Second)
+	if allowed, _ := l.Allow("1.2.3.4"); !allowed {
+		t.Error("Expected a token to be refilled after 1s")
+	}
+}
+
+func TestAllow_SweepsIdleClients(t *testing.T) {
+	l, now := newTestLimiter(60, 1)
+	l.Allow("1.2.3.4")
+
+	*now = now.Add(idleTimeout)
+	l.Allow("5.6.7.8")
+
+	if _, ok := l.clients["1.2.3.4"]; ok {
+		t.Error("Expected idle client to be forgotten")
+	}
+	if len(l.clients) != 1 {
+		t.Errorf("Expected 1 client, got %d", len(l.clients))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	l, _ := newTestLimiter(30, 1)
+	handler := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	codes := make([]int, 2)
+	for i := range codes {
+		req := httptest.NewRequest("GET", "/weather/01310100", nil)
+		req.Header.Set("X-Forwarded-For", "9.9.9.9, 1.2.3.4")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		codes[i] = rr.Code
+
+		if rr.Code == http.StatusTooManyRequests && rr.Header().Get("Retry-After") != "2" {
+			t.Errorf("Expected Retry-After 2, got %q", rr.Header().Get("Retry-After"))
+		}
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("Expected 200 then 429, got %v", codes)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		forwarded  string
+		remoteAddr string
+		expected   string
+	}{
+		{"peer address", "", "10.0.0.1:5555", "10.0.0.1"},
+		{"appended by Cloud Run", "1.2.3.4", "10.0.0.1:5555", "1.2.3.4"},
+		{"forged entries ignored", "6.6.6.6, 1.2.3.4", "10.0.0.1:5555", "1.2.3.4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if ip := ClientIP(req); ip != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, ip)
+			}
+		})
+	}
+}
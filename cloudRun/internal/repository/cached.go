@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"cloudrun/internal/cache"
+	"cloudrun/internal/domain"
+	"cloudrun/internal/flight"
+	"cloudrun/pkg/validator"
+)
+
+// Cache names reported in the Cache-Status header
+const (
+	CacheNameViaCEP     = "viacep"
+	CacheNameWeatherAPI = "weatherapi"
+	CacheNameGeoIP      = "geoip"
+)
+
+// CachedLocationService serves CEP lookups from a cache before asking ViaCEP.
+// CEPs are keyed by their canonical form and concurrent misses share one call.
+type CachedLocationService struct {
+	next   domain.LocationService
+	cache  cache.Cache
+	ttl    time.Duration
+	flight flight.Group
+}
+
+// NewCachedLocationService caches the successful lookups of next for ttl
+func NewCachedLocationService(next domain.LocationService, c cache.Cache, ttl time.Duration) *CachedLocationService {
+	return &CachedLocationService{next: next, cache: c, ttl: ttl}
+}
+
+// GetLocationByCEP returns the cached location of cep or fetches and caches it
+func (s *CachedLocationService) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	cep = validator.CleanCEP(cep)
+	var location domain.ViaCEPResponse
+	_, err := cached(ctx, s.cache, &s.flight, CacheNameViaCEP, "cep:"+cep, s.ttl, &location, func(ctx context.Context) (any, error) {
+		return s.next.GetLocationByCEP(ctx, cep)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// CachedWeatherDataService serves weather lookups from a cache before asking
+// the weather API; concurrent misses for one location share one call
+type CachedWeatherDataService struct {
+	next   domain.WeatherDataService
+	cache  cache.Cache
+	ttl    time.Duration
+	flight flight.Group
+}
+
+// NewCachedWeatherDataService caches the successful lookups of next for ttl
+func NewCachedWeatherDataService(next domain.WeatherDataService, c cache.Cache, ttl time.Duration) *CachedWeatherDataService {
+	return &CachedWeatherDataService{next: next, cache: c, ttl: ttl}
+}
+
+// GetWeatherByLocation returns the cached weather of location or fetches and caches it
+func (s *CachedWeatherDataService) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	var weather domain.WeatherAPIResponse
+	hit, err := cached(ctx, s.cache, &s.flight, CacheNameWeatherAPI, domain.LocalizedKey(ctx, "weather:"+strings.ToLower(location)), s.ttl, &weather, func(ctx context.Context) (any, error) {
+		return s.next.GetWeatherByLocation(ctx, location)
+	})
+	if err != nil {
+		return nil, err
+	}
+	weather.Cached = hit
+	return &weather, nil
+}
+
+// GetWeatherByCoordinates returns the cached weather of the point or fetches and caches it
+func (s *CachedWeatherDataService) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	var weather domain.WeatherAPIResponse
+	key := domain.LocalizedKey(ctx, fmt.Sprintf("weather:coords:%.4f,%.4f", latitude, longitude))
+	hit, err := cached(ctx, s.cache, &s.flight, CacheNameWeatherAPI, key, s.ttl, &weather, func(ctx context.Context) (any, error) {
+		return s.next.GetWeatherByCoordinates(ctx, latitude, longitude)
+	})
+	if err != nil {
+		return nil, err
+	}
+	weather.Cached = hit
+	return &weather, nil
+}
+
+// CachedIPLocationService serves IP geolocations from a cache before asking the
+// provider, whose free tiers allow few lookups a day
+type CachedIPLocationService struct {
+	next   domain.IPLocationService
+	cache  cache.Cache
+	ttl    time.Duration
+	flight flight.Group
+}
+
+// NewCachedIPLocationService caches the successful lookups of next for ttl
+func NewCachedIPLocationService(next domain.IPLocationService, c cache.Cache, ttl time.Duration) *CachedIPLocationService {
+	return &CachedIPLocationService{next: next, cache: c, ttl: ttl}
+}
+
+// GetLocationByIP returns the cached location of ip or fetches and caches it
+func (s *CachedIPLocationService) GetLocationByIP(ctx context.Context, ip string) (*domain.IPLocation, error) {
+	var location domain.IPLocation
+	_, err := cached(ctx, s.cache, &s.flight, CacheNameGeoIP, "ip:"+ip, s.ttl, &location, func(ctx context.Context) (any, error) {
+		return s.next.GetLocationByIP(ctx, ip)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// cached decodes the value under key into target, or stores the result of fetch
+// there and in the cache. Concurrent misses of one key wait for a single fetch,
+// which is cancelled once every caller waiting for it has given up.
+// Cache errors are logged and treated as misses. hit reports whether target came from the cache.
+func cached(ctx context.Context, c cache.Cache, group *flight.Group, name, key string, ttl time.Duration, target any, fetch func(context.Context) (any, error)) (hit bool, err error) {
+	value, remaining, ok, err := c.Get(ctx, key)
+	if err != nil {
+		slog.WarnContext(ctx, "Cache unavailable, fetching upstream", "cache", name, "key", key, "error", err)
+	}
+	if ok {
+		if err := json.Unmarshal(value, target); err == nil {
+			cache.Record(ctx, cache.Lookup{Name: name, Hit: true, TTL: remaining})
+			return true, nil
+		}
+		slog.WarnContext(ctx, "Ignoring undecodable cache entry", "cache", name, "key", key)
+	}
+
+	res := group.Do(ctx, key, func(fetchCtx context.Context) (any, error) {
+		result, err := fetch(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Set(fetchCtx, key, value, ttl); err != nil {
+			slog.WarnContext(ctx, "Failed to store cache entry", "cache", name, "key", key, "error", err)
+		}
+		return value, nil
+	})
+	cache.Record(ctx, cache.Lookup{Name: name, Collapsed: res.Shared})
+	if res.Shared {
+		slog.DebugContext(ctx, "Collapsed concurrent lookups into one upstream call", "cache", name, "key", key)
+	}
+	if res.Err != nil {
+		return false, res.Err
+	}
+	return false, json.Unmarshal(res.Val.([]byte), target)
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cloudrun/internal/cache"
+	"cloudrun/internal/domain"
+)
+
+// countingLocationService answers São Paulo for every CEP and counts the calls
+type countingLocationService struct {
+	calls int
+	err   error
+}
+
+func (s *countingLocationService) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &domain.ViaCEPResponse{CEP: cep, Localidade: "São Paulo", UF: "SP"}, nil
+}
+
+// countingWeatherService answers 25°C for every location and counts the calls
+type countingWeatherService struct {
+	calls int
+}
+
+func (s *countingWeatherService) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	s.calls++
+	return &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 25}}, nil
+}
+
+func (s *countingWeatherService) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	s.calls++
+	return &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 25}}, nil
+}
+
+func TestCachedLocationService_ServesRepeatedCEPsFromCache(t *testing.T) {
+	next := &countingLocationService{}
+	service := NewCachedLocationService(next, cache.NewLRU(10), time.Hour)
+
+	ctx, lookups := cache.WithLookups(context.Background())
+	for i := 0; i < 3; i++ {
+		location, err := service.GetLocationByCEP(ctx, "01310100")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if location.Localidade != "São Paulo" {
+			t.Errorf("Expected São Paulo, got %q", location.Localidade)
+		}
+	}
+
+	if next.calls != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", next.calls)
+	}
+	expected := "viacep; fwd=miss, viacep; hit; ttl=3599, viacep; hit; ttl=3599"
+	if header := lookups.Header(); header != expected {
+		t.Errorf("Expected %q, got %q", expected, header)
+	}
+}
+
+func TestCachedLocationService_DoesNotCacheErrors(t *testing.T) {
+	next := &countingLocationService{err: errors.New("CEP not found")}
+	service := NewCachedLocationService(next, cache.NewLRU(10), time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.GetLocationByCEP(context.Background(), "99999999"); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	}
+	if next.calls != 2 {
+		t.Errorf("Expected every failed lookup to reach upstream, got %d calls", next.calls)
+	}
+}
+
+func TestCachedWeatherDataService_KeyIgnoresCase(t *testing.T) {
+	next := &countingWeatherService{}
+	service := NewCachedWeatherDataService(next, cache.NewLRU(10), time.Minute)
+
+	for _, location := range []string{"São Paulo,SP", "são paulo,sp"} {
+		weather, err := service.GetWeatherByLocation(context.Background(), location)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if weather.Current.TempC != 25 {
+			t.Errorf("Expected 25°C, got %v", weather.Current.TempC)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", next.calls)
+	}
+}
+
+func TestCachedWeatherDataService_KeyIncludesLanguage(t *testing.T) {
+	next := &countingWeatherService{}
+	service := NewCachedWeatherDataService(next, cache.NewLRU(10), time.Minute)
+
+	pt := domain.WithLanguage(context.Background(), domain.LanguagePortuguese)
+	en := domain.WithLanguage(context.Background(), domain.LanguageEnglish)
+	for _, ctx := range []context.Context{context.Background(), en, pt, pt} {
+		if _, err := service.GetWeatherByLocation(ctx, "São Paulo,SP"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	// English shares the entries cached before languages existed
+	if next.calls != 2 {
+		t.Errorf("Expected 1 upstream call per language, got %d", next.calls)
+	}
+}
+
+func TestCachedWeatherDataService_MarksHits(t *testing.T) {
+	fetchedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	next := &stubWeatherProvider{weather: &domain.WeatherAPIResponse{Provider: ProviderOpenMeteo, FetchedAt: fetchedAt}}
+	service := NewCachedWeatherDataService(next, cache.NewLRU(10), time.Minute)
+
+	for i, wantCached := range []bool{false, true} {
+		weather, err := service.GetWeatherByCoordinates(context.Background(), -23.55, -46.63)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if weather.Cached != wantCached {
+			t.Errorf("Lookup %d: expected cached %v, got %v", i, wantCached, weather.Cached)
+		}
+		if weather.Provider != ProviderOpenMeteo || !weather.FetchedAt.Equal(fetchedAt) {
+			t.Errorf("Lookup %d: expected the original provider and fetch time, got %q at %v", i, weather.Provider, weather.FetchedAt)
+		}
+	}
+}
+
+func TestCachedLocationService_KeyIsCanonicalCEP(t *testing.T) {
+	next := &countingLocationService{}
+	lru := cache.NewLRU(10)
+	service := NewCachedLocationService(next, lru, time.Hour)
+
+	for _, cep := range []string{"01310-100", "01310100", "01.310-100"} {
+		location, err := service.GetLocationByCEP(context.Background(), cep)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if location.CEP != "01310100" {
+			t.Errorf("Expected upstream to see the canonical CEP, got %q", location.CEP)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", next.calls)
+	}
+	if entries := lru.CacheStats().Entries; entries != 1 {
+		t.Errorf("Expected 1 cache entry, got %d", entries)
+	}
+}
+
+// blockingLocationService holds every lookup until release is closed
+type blockingLocationService struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (s *blockingLocationService) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	s.calls.Add(1)
+	<-s.release
+	return &domain.ViaCEPResponse{CEP: cep, Localidade: "São Paulo", UF: "SP"}, nil
+}
+
+func TestCachedLocationService_CollapsesConcurrentLookups(t *testing.T) {
+	next := &blockingLocationService{release: make(chan struct{})}
+	service := NewCachedLocationService(next, cache.NewLRU(10), time.Hour)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		cep := "01310100"
+		if i%2 == 0 {
+			cep = "01310-100"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			location, err := service.GetLocationByCEP(context.Background(), cep)
+			if err == nil && location.Localidade != "São Paulo" {
+				err = errors.New("unexpected location " + location.Localidade)
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
+	// Lookups arriving after the release hit the cache instead, so the
+	// upstream is called once either way
+	if calls := next.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", calls)
+	}
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// DefaultTimeout bounds a whole upstream lookup, retries included
+const DefaultTimeout = 10 * time.Second
+
+// newHTTPClient creates the traced client shared by the repositories. It has no
+// timeout of its own: each lookup derives a deadline from the incoming request.
+func newHTTPClient() *http.Client {
+	return &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+}
+
+// withTimeout derives the deadline of one lookup from ctx, so it ends with the
+// incoming request or after timeout, whichever comes first. A timeout of 0
+// leaves ctx unbounded.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cloudrun/internal/domain"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Weather provider names accepted in WEATHER_PROVIDERS
+const (
+	ProviderWeatherAPI = "weatherapi"
+	ProviderOpenMeteo  = "openmeteo"
+)
+
+// ErrUnknownWeatherProvider is returned for a provider name missing from the registry
+var ErrUnknownWeatherProvider = errors.New("unknown weather provider")
+
+// WeatherProvider is a weather data source that can also be probed by the status page
+type WeatherProvider interface {
+	domain.WeatherDataService
+	Ping(ctx context.Context) error
+}
+
+// NamedWeatherProvider pairs a provider with the name used in logs, traces and checks
+type NamedWeatherProvider struct {
+	Name     string
+	Provider WeatherProvider
+}
+
+// NewWeatherProvider builds the registered provider with the given name, lookup timeout
+// and retry policy. Only WeatherAPI uses apiKey; Open-Meteo needs no key.
+func NewWeatherProvider(name, apiKey string, timeout time.Duration, policy RetryPolicy) (WeatherProvider, error) {
+	switch name {
+	case ProviderWeatherAPI:
+		return NewWeatherAPIRepository(apiKey).WithTimeout(timeout).WithRetryPolicy(policy), nil
+	case ProviderOpenMeteo:
+		return NewOpenMeteoRepository().WithTimeout(timeout).WithRetryPolicy(policy), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownWeatherProvider, name)
+	}
+}
+
+// FailoverWeatherDataService asks each provider in order until one answers.
+// A location the provider does not know is a final answer, not a provider failure.
+type FailoverWeatherDataService struct {
+	providers []NamedWeatherProvider
+}
+
+// NewFailoverWeatherDataService creates a failover service over the providers, in priority order
+func NewFailoverWeatherDataService(providers ...NamedWeatherProvider) *FailoverWeatherDataService {
+	return &FailoverWeatherDataService{providers: providers}
+}
+
+// Providers returns the providers in failover order
+func (s *FailoverWeatherDataService) Providers() []NamedWeatherProvider {
+	return s.providers
+}
+
+// GetWeatherByLocation returns the first successful answer. When every provider fails
+// the errors are joined, so errors.Is still finds domain.ErrRateLimited.
+func (s *FailoverWeatherDataService) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	return s.first(ctx, location, func(p WeatherProvider) (*domain.WeatherAPIResponse, error) {
+		return p.GetWeatherByLocation(ctx, location)
+	})
+}
+
+// GetWeatherByCoordinates returns the first successful answer for the point, like GetWeatherByLocation
+func (s *FailoverWeatherDataService) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	return s.first(ctx, fmt.Sprintf("%.4f,%.4f", latitude, longitude), func(p WeatherProvider) (*domain.WeatherAPIResponse, error) {
+		return p.GetWeatherByCoordinates(ctx, latitude, longitude)
+	})
+}
+
+// first asks each provider in order with fetch until one answers, stamping the
+// answer with the provider name and time; location only labels the logs
+func (s *FailoverWeatherDataService) first(ctx context.Context, location string, fetch func(WeatherProvider) (*domain.WeatherAPIResponse, error)) (*domain.WeatherAPIResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	var errs []error
+	for i, p := range s.providers {
+		weather, err := fetch(p.Provider)
+		if err == nil {
+			weather.Provider, weather.FetchedAt = p.Name, time.Now().UTC()
+			span.SetAttributes(attribute.String("weather.provider", p.Name))
+			if i > 0 {
+				span.SetAttributes(attribute.Int("weather.failovers", i))
+			}
+			return weather, nil
+		}
+		if errors.Is(err, domain.ErrLocationNotFound) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		slog.WarnContext(ctx, "Weather provider failed", "provider", p.Name, "location", location, "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no weather provider configured")
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloudrun/internal/domain"
+)
+
+type stubWeatherProvider struct {
+	weather *domain.WeatherAPIResponse
+	err     error
+	calls   int
+}
+
+func (s *stubWeatherProvider) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	s.calls++
+	return s.weather, s.err
+}
+
+func (s *stubWeatherProvider) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	s.calls++
+	return s.weather, s.err
+}
+
+func (s *stubWeatherProvider) Ping(ctx context.Context) error {
+	return s.err
+}
+
+func TestNewWeatherProvider(t *testing.T) {
+	if p, err := NewWeatherProvider(ProviderWeatherAPI, "key", DefaultTimeout, DefaultRetryPolicy); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	} else if _, ok := p.(*WeatherAPIRepository); !ok {
+		t.Errorf("Expected *WeatherAPIRepository, got %T", p)
+	}
+
+	if p, err := NewWeatherProvider(ProviderOpenMeteo, "", DefaultTimeout, DefaultRetryPolicy); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	} else if _, ok := p.(*OpenMeteoRepository); !ok {
+		t.Errorf("Expected *OpenMeteoRepository, got %T", p)
+	}
+
+	if _, err := NewWeatherProvider("darksky", "", DefaultTimeout, DefaultRetryPolicy); !errors.Is(err, ErrUnknownWeatherProvider) {
+		t.Errorf("Expected ErrUnknownWeatherProvider, got %v", err)
+	}
+}
+
+func TestFailover_UsesFirstHealthyProvider(t *testing.T) {
+	primary := &stubWeatherProvider{weather: &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 20}}}
+	secondary := &stubWeatherProvider{weather: &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 30}}}
+	service := NewFailoverWeatherDataService(
+		NamedWeatherProvider{Name: "primary", Provider: primary},
+		NamedWeatherProvider{Name: "secondary", Provider: secondary},
+	)
+
+	weather, err := service.GetWeatherByLocation(context.Background(), "Curitiba,PR")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if weather.Current.TempC != 20 {
+		t.Errorf("Expected primary answer 20, got %v", weather.Current.TempC)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("Expected secondary not to be called, got %d calls", secondary.calls)
+	}
+}
+
+func TestFailover_FallsBackOnProviderFailure(t *testing.T) {
+	primary := &stubWeatherProvider{err: errors.New("weather API returned status 401")}
+	secondary := &stubWeatherProvider{weather: &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 30}}}
+	service := NewFailoverWeatherDataService(
+		NamedWeatherProvider{Name: "primary", Provider: primary},
+		NamedWeatherProvider{Name: "secondary", Provider: secondary},
+	)
+
+	weather, err := service.GetWeatherByLocation(context.Background(), "Curitiba,PR")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if weather.Current.TempC != 30 {
+		t.Errorf("Expected secondary answer 30, got %v", weather.Current.TempC)
+	}
+	if weather.Provider != "secondary" || weather.FetchedAt.IsZero() {
+		t.Errorf("Expected the answer stamped by secondary, got provider %q at %v", weather.Provider, weather.FetchedAt)
+	}
+}
+
+func TestFailover_GetWeatherByCoordinates(t *testing.T) {
+	primary := &stubWeatherProvider{err: errors.New("weather API returned status 500")}
+	secondary := &stubWeatherProvider{weather: &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 30}}}
+	service := NewFailoverWeatherDataService(
+		NamedWeatherProvider{Name: "primary", Provider: primary},
+		NamedWeatherProvider{Name: "secondary", Provider: secondary},
+	)
+
+	weather, err := service.GetWeatherByCoordinates(context.Background(), -25.43, -49.27)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if weather.Current.TempC != 30 || primary.calls != 1 {
+		t.Errorf("Expected the secondary answer after one primary call, got %v after %d calls", weather.Current.TempC, primary.calls)
+	}
+}
+
+func TestFailover_LocationNotFoundIsFinal(t *testing.T) {
+	primary := &stubWeatherProvider{err: domain.ErrLocationNotFound}
+	secondary := &stubWeatherProvider{weather: &domain.WeatherAPIResponse{}}
+	service := NewFailoverWeatherDataService(
+		NamedWeatherProvider{Name: "primary", Provider: primary},
+		NamedWeatherProvider{Name: "secondary", Provider: secondary},
+	)
+
+	_, err := service.GetWeatherByLocation(context.Background(), "Atlantis,Brazil")
+	if !errors.Is(err, domain.ErrLocationNotFound) {
+		t.Errorf("Expected ErrLocationNotFound, got %v", err)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("Expected secondary not to be called, got %d calls", secondary.calls)
+	}
+}
+
+func TestFailover_AllProvidersFail(t *testing.T) {
+	service := NewFailoverWeatherDataService(
+		NamedWeatherProvider{Name: "primary", Provider: &stubWeatherProvider{err: &domain.RateLimitError{}}},
+		NamedWeatherProvider{Name: "secondary", Provider: &stubWeatherProvider{err: errors.New("timeout")}},
+	)
+
+	_, err := service.GetWeatherByLocation(context.Background(), "Curitiba,PR")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !errors.Is(err, domain.ErrRateLimited) {
+		t.Errorf("Expected joined error to match ErrRateLimited, got %v", err)
+	}
+}
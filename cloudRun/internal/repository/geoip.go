@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudrun/internal/domain"
+)
+
+// Geolocation provider names accepted in GEOIP_PROVIDER
+const (
+	GeoIPProviderIPAPI  = "ipapi"
+	GeoIPProviderIPInfo = "ipinfo"
+)
+
+// ErrUnknownGeoIPProvider is returned for a geolocation provider name missing from the registry
+var ErrUnknownGeoIPProvider = errors.New("unknown geolocation provider")
+
+// NewIPLocator builds the registered geolocation provider with the given name,
+// lookup timeout and retry policy. Only ipinfo uses token, and works without it
+// within the anonymous quota.
+func NewIPLocator(name, token string, timeout time.Duration, policy RetryPolicy) (domain.IPLocationService, error) {
+	switch name {
+	case GeoIPProviderIPAPI:
+		return NewIPAPIRepository().WithTimeout(timeout).WithRetryPolicy(policy), nil
+	case GeoIPProviderIPInfo:
+		return NewIPInfoRepository(token).WithTimeout(timeout).WithRetryPolicy(policy), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownGeoIPProvider, name)
+	}
+}
+
+// IPAPIRepository geolocates IPs with ipapi.co, which needs no key
+type IPAPIRepository struct {
+	client      *http.Client
+	baseURL     string
+	timeout     time.Duration
+	retryPolicy RetryPolicy
+	sleep       func(time.Duration)
+}
+
+// NewIPAPIRepository creates a new ipapi.co repository
+func NewIPAPIRepository() *IPAPIRepository {
+	return &IPAPIRepository{
+		client:      newHTTPClient(),
+		baseURL:     "https://ipapi.co",
+		timeout:     DefaultTimeout,
+		retryPolicy: DefaultRetryPolicy,
+		sleep:       time.Sleep,
+	}
+}
+
+// WithTimeout replaces the deadline of each lookup; 0 leaves it to the incoming request
+func (r *IPAPIRepository) WithTimeout(timeout time.Duration) *IPAPIRepository {
+	r.timeout = timeout
+	return r
+}
+
+// WithRetryPolicy replaces the retry policy of the lookups
+func (r *IPAPIRepository) WithRetryPolicy(policy RetryPolicy) *IPAPIRepository {
+	r.retryPolicy = policy
+	return r
+}
+
+// ipapiResponse is the body of ipapi.co; reserved addresses come back as
+// 200 with error set
+type ipapiResponse struct {
+	City        string  `json:"city"`
+	Region      string  `json:"region"`
+	CountryCode string  `json:"country_code"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Error       bool    `json:"error"`
+	Reason      string  `json:"reason"`
+}
+
+// GetLocationByIP returns the approximate location of ip
+func (r *IPAPIRepository) GetLocationByIP(ctx context.Context, ip string) (*domain.IPLocation, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s/%s/json/", r.baseURL, url.PathEscape(ip))
+	resp, err := doWithRetry(r.client, r.retryPolicy, r.sleep, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to geolocate ip: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", domain.ErrIPNotLocated, ip)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ipapi returned status %d", resp.StatusCode)
+	}
+
+	var body ipapiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode ipapi response: %w", err)
+	}
+	if body.Error {
+		return nil, fmt.Errorf("%w: %s (%s)", domain.ErrIPNotLocated, ip, body.Reason)
+	}
+
+	return &domain.IPLocation{
+		City:      body.City,
+		Region:    body.Region,
+		Country:   body.CountryCode,
+		Latitude:  body.Latitude,
+		Longitude: body.Longitude,
+	}, nil
+}
+
+// IPInfoRepository geolocates IPs with ipinfo.io
+type IPInfoRepository struct {
+	client      *http.Client
+	token       string
+	baseURL     string
+	timeout     time.Duration
+	retryPolicy RetryPolicy
+	sleep       func(time.Duration)
+}
+
+// NewIPInfoRepository creates a new ipinfo.io repository; an empty token uses the anonymous quota
+func NewIPInfoRepository(token string) *IPInfoRepository {
+	return &IPInfoRepository{
+		client:      newHTTPClient(),
+		token:       token,
+		baseURL:     "https://ipinfo.io",
+		timeout:     DefaultTimeout,
+		retryPolicy: DefaultRetryPolicy,
+		sleep:       time.Sleep,
+	}
+}
+
+// WithTimeout replaces the deadline of each lookup; 0 leaves it to the incoming request
+func (r *IPInfoRepository) WithTimeout(timeout time.Duration) *IPInfoRepository {
+	r.timeout = timeout
+	return r
+}
+
+// WithRetryPolicy replaces the retry policy of the lookups
+func (r *IPInfoRepository) WithRetryPolicy(policy RetryPolicy) *IPInfoRepository {
+	r.retryPolicy = policy
+	return r
+}
+
+// ipinfoResponse is the body of ipinfo.io; loc is "lat,lon" and bogon marks
+// private and reserved addresses
+type ipinfoResponse struct {
+	City    string `json:"city"`
+	Region  string `json:"region"`
+	Country string `json:"country"`
+	Loc     string `json:"loc"`
+	Bogon   bool   `json:"bogon"`
+}
+
+// GetLocationByIP returns the approximate location of ip
+func (r *IPInfoRepository) GetLocationByIP(ctx context.Context, ip string) (*domain.IPLocation, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s/%s/json", r.baseURL, url.PathEscape(ip))
+	if r.token != "" {
+		endpoint += "?token=" + url.QueryEscape(r.token)
+	}
+	resp, err := doWithRetry(r.client, r.retryPolicy, r.sleep, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to geolocate ip: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", domain.ErrIPNotLocated, ip)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ipinfo returned status %d", resp.StatusCode)
+	}
+
+	var body ipinfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode ipinfo response: %w", err)
+	}
+	latitude, longitude, ok := parseLoc(body.Loc)
+	if body.Bogon || !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrIPNotLocated, ip)
+	}
+
+	return &domain.IPLocation{
+		City:      body.City,
+		Region:    body.Region,
+		Country:   body.Country,
+		Latitude:  latitude,
+		Longitude: longitude,
+	}, nil
+}
+
+// parseLoc reads the "lat,lon" coordinates of an ipinfo response
+func parseLoc(loc string) (float64, float64, bool) {
+	lat, lon, ok := strings.Cut(loc, ",")
+	if !ok {
+		return 0, 0, false
+	}
+	latitude, latErr := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	longitude, lonErr := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	return latitude, longitude, latErr == nil && lonErr == nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloudrun/internal/domain"
+)
+
+func newGeoIPServer(t *testing.T, body string, requestURI *string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestURI != nil {
+			*requestURI = r.URL.RequestURI()
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestIPAPI_GetLocationByIP(t *testing.T) {
+	var requestURI string
+	repo := NewIPAPIRepository()
+	repo.baseURL = newGeoIPServer(t, `{"city":"São Paulo","region":"São Paulo","country_code":"BR","latitude":-23.5475,"longitude":-46.6361}`, &requestURI)
+
+	location, err := repo.GetLocationByIP(context.Background(), "200.160.2.3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requestURI != "/200.160.2.3/json/" {
+		t.Errorf("Expected /200.160.2.3/json/, got %s", requestURI)
+	}
+	if location.City != "São Paulo" || location.Country != "BR" || location.Latitude != -23.5475 || location.Longitude != -46.6361 {
+		t.Errorf("Unexpected location %+v", location)
+	}
+}
+
+func TestIPAPI_GetLocationByIP_Reserved(t *testing.T) {
+	repo := NewIPAPIRepository()
+	repo.baseURL = newGeoIPServer(t, `{"ip":"10.0.0.1","error":true,"reason":"Reserved IP Address","reserved":true}`, nil)
+
+	if _, err := repo.GetLocationByIP(context.Background(), "10.0.0.1"); !errors.Is(err, domain.ErrIPNotLocated) {
+		t.Errorf("Expected ErrIPNotLocated, got %v", err)
+	}
+}
+
+func TestIPInfo_GetLocationByIP(t *testing.T) {
+	var requestURI string
+	repo := NewIPInfoRepository("secret")
+	repo.baseURL = newGeoIPServer(t, `{"city":"Campinas","region":"São Paulo","country":"BR","loc":"-22.9056,-47.0608"}`, &requestURI)
+
+	location, err := repo.GetLocationByIP(context.Background(), "2804:14c::1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requestURI != "/2804:14c::1/json?token=secret" {
+		t.Errorf("Expected the token in the query, got %s", requestURI)
+	}
+	if location.City != "Campinas" || location.Region != "São Paulo" || location.Latitude != -22.9056 || location.Longitude != -47.0608 {
+		t.Errorf("Unexpected location %+v", location)
+	}
+}
+
+func TestIPInfo_GetLocationByIP_NotLocated(t *testing.T) {
+	for name, body := range map[string]string{
+		"bogon":       `{"ip":"192.168.0.1","bogon":true}`,
+		"missing loc": `{"ip":"203.0.113.9","country":"BR"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := NewIPInfoRepository("")
+			repo.baseURL = newGeoIPServer(t, body, nil)
+
+			if _, err := repo.GetLocationByIP(context.Background(), "192.168.0.1"); !errors.Is(err, domain.ErrIPNotLocated) {
+				t.Errorf("Expected ErrIPNotLocated, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewIPLocator(t *testing.T) {
+	if _, err := NewIPLocator(GeoIPProviderIPInfo, "", DefaultTimeout, DefaultRetryPolicy); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err := NewIPLocator("maxmind", "", DefaultTimeout, DefaultRetryPolicy); !errors.Is(err, ErrUnknownGeoIPProvider) {
+		t.Errorf("Expected ErrUnknownGeoIPProvider, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloudrun/internal/domain"
+)
+
+// stateNames maps the Brazilian UFs to the admin1 names returned by the Open-Meteo geocoding API
+var stateNames = map[string]string{
+	"AC": "Acre", "AL": "Alagoas", "AP": "Amapá", "AM": "Amazonas", "BA": "Bahia",
+	"CE": "Ceará", "DF": "Distrito Federal", "ES": "Espírito Santo", "GO": "Goiás",
+	"MA": "Maranhão", "MT": "Mato Grosso", "MS": "Mato Grosso do Sul", "MG": "Minas Gerais",
+	"PA": "Pará", "PB": "Paraíba", "PR": "Paraná", "PE": "Pernambuco", "PI": "Piauí",
+	"RJ": "Rio de Janeiro", "RN": "Rio Grande do Norte", "RS": "Rio Grande do Sul",
+	"RO": "Rondônia", "RR": "Roraima", "SC": "Santa Catarina", "SP": "São Paulo",
+	"SE": "Sergipe", "TO": "Tocantins",
+}
+
+// wmoConditions maps the WMO weather interpretation codes used by Open-Meteo to
+// WeatherAPI condition codes, so pkg/condition normalizes both providers alike
+var wmoConditions = map[int]domain.WeatherAPICondition{
+	0:  {Code: 1000, Text: "Clear"},
+	1:  {Code: 1003, Text: "Mainly clear"},
+	2:  {Code: 1003, Text: "Partly cloudy"},
+	3:  {Code: 1009, Text: "Overcast"},
+	45: {Code: 1135, Text: "Fog"},
+	48: {Code: 1147, Text: "Freezing fog"},
+	51: {Code: 1150, Text: "Light drizzle"},
+	53: {Code: 1153, Text: "Drizzle"},
+	55: {Code: 1153, Text: "Dense drizzle"},
+	56: {Code: 1168, Text: "Freezing drizzle"},
+	57: {Code: 1171, Text: "Heavy freezing drizzle"},
+	61: {Code: 1183, Text: "Light rain"},
+	63: {Code: 1189, Text: "Moderate rain"},
+	65: {Code: 1195, Text: "Heavy rain"},
+	66: {Code: 1198, Text: "Light freezing rain"},
+	67: {Code: 1201, Text: "Heavy freezing rain"},
+	71: {Code: 1213, Text: "Light snow"},
+	73: {Code: 1219, Text: "Moderate snow"},
+	75: {Code: 1225, Text: "Heavy snow"},
+	77: {Code: 1213, Text: "Snow grains"},
+	80: {Code: 1240, Text: "Light rain shower"},
+	81: {Code: 1243, Text: "Moderate rain shower"},
+	82: {Code: 1246, Text: "Torrential rain shower"},
+	85: {Code: 1255, Text: "Light snow showers"},
+	86: {Code: 1258, Text: "Heavy snow showers"},
+	95: {Code: 1276, Text: "Thunderstorm"},
+	96: {Code: 1276, Text: "Thunderstorm with light hail"},
+	99: {Code: 1276, Text: "Thunderstorm with heavy hail"},
+}
+
+// wmoTextsPT describes the WMO codes in Portuguese, for lookups asking for lang=pt
+var wmoTextsPT = map[int]string{
+	0:  "Céu limpo",
+	1:  "Predominantemente limpo",
+	2:  "Parcialmente nublado",
+	3:  "Encoberto",
+	45: "Nevoeiro",
+	48: "Nevoeiro gelado",
+	51: "Chuvisco fraco",
+	53: "Chuvisco",
+	55: "Chuvisco denso",
+	56: "Chuvisco congelante",
+	57: "Chuvisco congelante forte",
+	61: "Chuva fraca",
+	63: "Chuva moderada",
+	65: "Chuva forte",
+	66: "Chuva congelante fraca",
+	67: "Chuva congelante forte",
+	71: "Neve fraca",
+	73: "Neve moderada",
+	75: "Neve forte",
+	77: "Grãos de neve",
+	80: "Pancada de chuva fraca",
+	81: "Pancada de chuva moderada",
+	82: "Pancada de chuva torrencial",
+	85: "Pancadas de neve fracas",
+	86: "Pancadas de neve fortes",
+	95: "Trovoada",
+	96: "Trovoada com granizo fraco",
+	99: "Trovoada com granizo forte",
+}
+
+// openMeteoGeocoding is the body of the geocoding search endpoint
+type openMeteoGeocoding struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Admin1    string  `json:"admin1"`
+	} `json:"results"`
+}
+
+// openMeteoForecast is the body of the forecast endpoint with the current block requested
+type openMeteoForecast struct {
+	Current struct {
+		Temperature float64 `json:"temperature_2m"`
+		WeatherCode int     `json:"weather_code"`
+		IsDay       int     `json:"is_day"`
+	} `json:"current"`
+}
+
+// OpenMeteoRepository fetches current weather from Open-Meteo, which needs no API key.
+// Locations are resolved to coordinates with the Open-Meteo geocoding API first.
+type OpenMeteoRepository struct {
+	client       *http.Client
+	geocodingURL string
+	forecastURL  string
+	timeout      time.Duration
+	retryPolicy  RetryPolicy
+	sleep        func(time.Duration)
+}
+
+// NewOpenMeteoRepository creates a new Open-Meteo repository
+func NewOpenMeteoRepository() *OpenMeteoRepository {
+	return &OpenMeteoRepository{
+		client:       newHTTPClient(),
+		timeout:      DefaultTimeout,
+		geocodingURL: "https://geocoding-api.open-meteo.com/v1",
+		forecastURL:  "https://api.open-meteo.com/v1",
+		retryPolicy:  DefaultRetryPolicy,
+		sleep:        time.Sleep,
+	}
+}
+
+// WithTimeout replaces the deadline of each lookup; 0 leaves it to the incoming request
+func (r *OpenMeteoRepository) WithTimeout(timeout time.Duration) *OpenMeteoRepository {
+	r.timeout = timeout
+	return r
+}
+
+// WithRetryPolicy replaces the retry policy of the lookups
+func (r *OpenMeteoRepository) WithRetryPolicy(policy RetryPolicy) *OpenMeteoRepository {
+	r.retryPolicy = policy
+	return r
+}
+
+// GetWeatherByLocation resolves a "city,UF" or "city,Brazil" location and fetches its current weather
+func (r *OpenMeteoRepository) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	latitude, longitude, err := r.geocode(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return r.forecast(ctx, latitude, longitude)
+}
+
+// GetWeatherByCoordinates fetches the current weather of a point, skipping the geocoding
+func (r *OpenMeteoRepository) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.forecast(ctx, latitude, longitude)
+}
+
+// forecast fetches the current weather at the coordinates
+func (r *OpenMeteoRepository) forecast(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	query := url.Values{}
+	query.Set("latitude", fmt.Sprintf("%.4f", latitude))
+	query.Set("longitude", fmt.Sprintf("%.4f", longitude))
+	query.Set("current", "temperature_2m,weather_code,is_day")
+
+	var forecast openMeteoForecast
+	if err := r.getJSON(ctx, r.forecastURL+"/forecast?"+query.Encode(), &forecast); err != nil {
+		return nil, fmt.Errorf("failed to fetch weather data: %w", err)
+	}
+
+	condition := wmoCondition(ctx, forecast.Current.WeatherCode)
+
+	return &domain.WeatherAPIResponse{
+		Current: domain.WeatherAPICurrent{
+			TempC:     forecast.Current.Temperature,
+			IsDay:     forecast.Current.IsDay,
+			Condition: condition,
+		},
+	}, nil
+}
+
+// wmoCondition maps a WMO code to a WeatherAPI condition described in the language of ctx
+func wmoCondition(ctx context.Context, code int) domain.WeatherAPICondition {
+	lang, _ := domain.Language(ctx)
+	condition, ok := wmoConditions[code]
+	if !ok {
+		condition = domain.WeatherAPICondition{Text: fmt.Sprintf("WMO code %d", code)}
+		if lang == domain.LanguagePortuguese {
+			condition.Text = fmt.Sprintf("Código WMO %d", code)
+		}
+		return condition
+	}
+	if lang == domain.LanguagePortuguese {
+		condition.Text = wmoTextsPT[code]
+	}
+	return condition
+}
+
+// geocode returns the coordinates of the first Brazilian match for the city,
+// restricted to the state when the location carries a UF
+func (r *OpenMeteoRepository) geocode(ctx context.Context, location string) (float64, float64, error) {
+	city, region, _ := strings.Cut(location, ",")
+	city = strings.TrimSpace(city)
+	state := stateNames[strings.ToUpper(strings.TrimSpace(region))]
+
+	query := url.Values{}
+	query.Set("name", city)
+	query.Set("count", "10")
+	query.Set("language", "pt")
+	query.Set("countryCode", "BR")
+
+	var geocoding openMeteoGeocoding
+	if err := r.getJSON(ctx, r.geocodingURL+"/search?"+query.Encode(), &geocoding); err != nil {
+		return 0, 0, fmt.Errorf("failed to geocode location: %w", err)
+	}
+
+	for _, result := range geocoding.Results {
+		if state == "" || strings.EqualFold(result.Admin1, state) {
+			return result.Latitude, result.Longitude, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: %s", domain.ErrLocationNotFound, location)
+}
+
+// getJSON decodes the body of a successful GET into target, retrying rate-limited requests and transient failures
+func (r *OpenMeteoRepository) getJSON(ctx context.Context, endpoint string, target any) error {
+	resp, err := doWithRetry(r.client, r.retryPolicy, r.sleep, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Open-Meteo API returned status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Ping checks that the Open-Meteo forecast API answers for São Paulo
+func (r *OpenMeteoRepository) Ping(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	endpoint := r.forecastURL + "/forecast?latitude=-23.5475&longitude=-46.6361&current=temperature_2m"
+	var forecast openMeteoForecast
+	if err := r.getJSON(ctx, endpoint, &forecast); err != nil {
+		return fmt.Errorf("failed to reach Open-Meteo: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloudrun/internal/domain"
+	"cloudrun/pkg/condition"
+)
+
+const geocodingBody = `{"results":[
+	{"name":"São Paulo","latitude":-22.0,"longitude":-47.0,"admin1":"Rio Grande do Sul"},
+	{"name":"São Paulo","latitude":-23.5475,"longitude":-46.63611,"admin1":"São Paulo"}
+]}`
+
+func newOpenMeteoServer(t *testing.T, geocoding string, forecastQuery *string) *OpenMeteoRepository {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(geocoding))
+	})
+	mux.HandleFunc("/forecast", func(w http.ResponseWriter, r *http.Request) {
+		if forecastQuery != nil {
+			*forecastQuery = r.URL.RawQuery
+		}
+		w.Write([]byte(`{"current":{"temperature_2m":24.3,"weather_code":61,"is_day":0}}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return &OpenMeteoRepository{
+		client:       &http.Client{},
+		geocodingURL: server.URL,
+		forecastURL:  server.URL,
+	}
+}
+
+func TestOpenMeteo_GetWeatherByLocation(t *testing.T) {
+	var forecastQuery string
+	repo := newOpenMeteoServer(t, geocodingBody, &forecastQuery)
+
+	weather, err := repo.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if weather.Current.TempC != 24.3 {
+		t.Errorf("Expected temperature 24.3, got %v", weather.Current.TempC)
+	}
+	if weather.Current.IsDay != 0 {
+		t.Errorf("Expected is_day 0, got %d", weather.Current.IsDay)
+	}
+	if got, _ := condition.Normalize(weather.Current.Condition.Code, false); got != condition.Rain {
+		t.Errorf("Expected WMO 61 to normalize to %s, got %s", condition.Rain, got)
+	}
+	if forecastQuery != "current=temperature_2m%2Cweather_code%2Cis_day&latitude=-23.5475&longitude=-46.6361" {
+		t.Errorf("Expected coordinates of the SP match, got %s", forecastQuery)
+	}
+}
+
+func TestOpenMeteo_GetWeatherByLocation_WithoutUFUsesFirstMatch(t *testing.T) {
+	var forecastQuery string
+	repo := newOpenMeteoServer(t, geocodingBody, &forecastQuery)
+
+	if _, err := repo.GetWeatherByLocation(context.Background(), "São Paulo,Brazil"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if forecastQuery != "current=temperature_2m%2Cweather_code%2Cis_day&latitude=-22.0000&longitude=-47.0000" {
+		t.Errorf("Expected coordinates of the first match, got %s", forecastQuery)
+	}
+}
+
+func TestOpenMeteo_GetWeatherByCoordinates(t *testing.T) {
+	var forecastQuery string
+	// Geocoding must be skipped, so an empty result set would fail the lookup
+	repo := newOpenMeteoServer(t, `{"results":[]}`, &forecastQuery)
+
+	weather, err := repo.GetWeatherByCoordinates(context.Background(), -23.55, -46.63)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if weather.Current.TempC != 24.3 {
+		t.Errorf("Expected temperature 24.3, got %v", weather.Current.TempC)
+	}
+	if forecastQuery != "current=temperature_2m%2Cweather_code%2Cis_day&latitude=-23.5500&longitude=-46.6300" {
+		t.Errorf("Expected the given coordinates, got %s", forecastQuery)
+	}
+}
+
+func TestOpenMeteo_GetWeatherByLocation_NotFound(t *testing.T) {
+	tests := []struct {
+		name      string
+		geocoding string
+		location  string
+	}{
+		{"No results", `{}`, "Cidade Inexistente,Brazil"},
+		{"No match in the state", geocodingBody, "São Paulo,AM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newOpenMeteoServer(t, tt.geocoding, nil)
+
+			_, err := repo.GetWeatherByLocation(context.Background(), tt.location)
+			if !errors.Is(err, domain.ErrLocationNotFound) {
+				t.Errorf("Expected ErrLocationNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenMeteo_UnknownWMOCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(geocodingBody))
+	})
+	mux.HandleFunc("/forecast", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"current":{"temperature_2m":20,"weather_code":42,"is_day":1}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	repo := &OpenMeteoRepository{client: &http.Client{}, geocodingURL: server.URL, forecastURL: server.URL}
+
+	weather, err := repo.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got, _ := condition.Normalize(weather.Current.Condition.Code, true); got != condition.Unknown {
+		t.Errorf("Expected unknown condition, got %s", got)
+	}
+}
+
+func TestOpenMeteo_Language(t *testing.T) {
+	repo := newOpenMeteoServer(t, geocodingBody, nil)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"default", context.Background(), "Light rain"},
+		{"en", domain.WithLanguage(context.Background(), domain.LanguageEnglish), "Light rain"},
+		{"pt", domain.WithLanguage(context.Background(), domain.LanguagePortuguese), "Chuva fraca"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weather, err := repo.GetWeatherByCoordinates(tt.ctx, -23.55, -46.63)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if weather.Current.Condition.Text != tt.want {
+				t.Errorf("Expected condition text %q, got %q", tt.want, weather.Current.Condition.Text)
+			}
+		})
+	}
+
+	for code, condition := range wmoConditions {
+		if wmoTextsPT[code] == "" {
+			t.Errorf("Expected a Portuguese text for WMO code %d (%s)", code, condition.Text)
+		}
+	}
+}
+
+func TestOpenMeteo_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	repo := &OpenMeteoRepository{client: &http.Client{}, geocodingURL: server.URL, forecastURL: server.URL}
+
+	_, err := repo.GetWeatherByLocation(context.Background(), "São Paulo,SP")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if errors.Is(err, domain.ErrLocationNotFound) {
+		t.Errorf("Expected an upstream failure, got %v", err)
+	}
+}
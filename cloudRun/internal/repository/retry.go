@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudrun/internal/domain"
+)
+
+// RetryPolicy bounds the retries of failed upstream requests. Requests rejected
+// with 429 Too Many Requests wait for Retry-After; transient failures (the
+// status codes in RetryStatusCodes, timeouts and connection errors) back off
+// exponentially.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseDelay is used when Retry-After is absent and doubles on each retry
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait; a longer Retry-After fails without retrying
+	MaxDelay time.Duration
+	// RetryStatusCodes lists the transient statuses retried besides 429
+	RetryStatusCodes []int
+	// AttemptTimeout bounds each attempt so a hung request can be retried; 0 leaves it to the lookup timeout
+	AttemptTimeout time.Duration
+}
+
+// DefaultRetryPolicy keeps the worst case well below the client timeout
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:       2,
+	BaseDelay:        500 * time.Millisecond,
+	MaxDelay:         3 * time.Second,
+	RetryStatusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	AttemptTimeout:   4 * time.Second,
+}
+
+// backoff is the wait before retry number attempt+1, capped at MaxDelay
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	return min(p.BaseDelay<<attempt, p.MaxDelay)
+}
+
+// doWithRetry sends the request built by newRequest, retrying while the upstream answers 429
+// or fails transiently. When 429 retries run out it returns a *domain.RateLimitError with the
+// last requested delay; other failures return the last response or error.
+func doWithRetry(client *http.Client, policy RetryPolicy, sleep func(time.Duration), newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		ctx := req.Context()
+
+		cancel := context.CancelFunc(func() {})
+		if policy.AttemptTimeout > 0 {
+			var attemptCtx context.Context
+			attemptCtx, cancel = context.WithTimeout(ctx, policy.AttemptTimeout)
+			req = req.WithContext(attemptCtx)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			cancel()
+			// The caller gave up; only failures of the attempt itself are retried
+			if ctx.Err() != nil || attempt >= policy.MaxRetries {
+				return nil, err
+			}
+			slog.WarnContext(ctx, "Upstream request failed, retrying", "url", redactURL(req.URL), "attempt", attempt+1, "error", err)
+			sleep(policy.backoff(attempt))
+			continue
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			resp.Body.Close()
+			cancel()
+
+			delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if !ok {
+				delay = policy.BaseDelay << attempt
+			}
+			if attempt >= policy.MaxRetries || delay > policy.MaxDelay {
+				return nil, &domain.RateLimitError{RetryAfter: delay}
+			}
+			sleep(delay)
+		case slices.Contains(policy.RetryStatusCodes, resp.StatusCode) && attempt < policy.MaxRetries:
+			resp.Body.Close()
+			cancel()
+
+			slog.WarnContext(ctx, "Upstream returned a transient error, retrying", "url", redactURL(req.URL), "attempt", attempt+1, "status", resp.StatusCode)
+			sleep(policy.backoff(attempt))
+		default:
+			// The attempt deadline also covers reading the body, released when it is closed
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+	}
+}
+
+// cancelOnClose releases the attempt context once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// redactURL drops the query string, which carries the WeatherAPI key
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.RawQuery = ""
+	return redacted.String()
+}
+
+// parseRetryAfter reads a Retry-After header in delay-seconds or HTTP-date form
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
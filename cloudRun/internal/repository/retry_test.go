@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cloudrun/internal/domain"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Mon, 10 Mar 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"Mon, 10 Mar 2025 11:59:00 GMT", 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tc.value, now)
+			if delay != tc.expected || ok != tc.ok {
+				t.Errorf("Expected (%v, %v), got (%v, %v)", tc.expected, tc.ok, delay, ok)
+			}
+		})
+	}
+}
+
+// newRateLimitedRepo returns a repository whose server answers 429 for the first failures requests
+func newRateLimitedRepo(t *testing.T, failures int, retryAfter string, policy RetryPolicy) (*WeatherAPIRepository, *int, *[]time.Duration) {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 21}})
+	}))
+	t.Cleanup(server.Close)
+
+	var waits []time.Duration
+	repo := &WeatherAPIRepository{
+		client:      &http.Client{},
+		apiKey:      "test_key",
+		baseURL:     server.URL,
+		retryPolicy: policy,
+		sleep:       func(d time.Duration) { waits = append(waits, d) },
+	}
+	return repo, &calls, &waits
+}
+
+func TestGetWeatherByLocation_RetriesAfterRateLimit(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+	repo, calls, waits := newRateLimitedRepo(t, 2, "2", policy)
+
+	result, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Current.TempC != 21 || *calls != 3 {
+		t.Errorf("Expected success on the 3rd call, got %v after %d calls", result.Current.TempC, *calls)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 2*time.Second {
+		t.Errorf("Expected two waits of 2s, got %v", *waits)
+	}
+}
+
+func TestGetWeatherByLocation_BackoffWithoutRetryAfter(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+	repo, _, waits := newRateLimitedRepo(t, 3, "", policy)
+
+	_, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
+
+	var rateLimitErr *domain.RateLimitError
+	if !errors.As(err, &rateLimitErr) || !errors.Is(err, domain.ErrRateLimited) {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	if rateLimitErr.RetryAfter != 400*time.Millisecond {
+		t.Errorf("Expected retry after 400ms, got %v", rateLimitErr.RetryAfter)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 100*time.Millisecond || (*waits)[1] != 200*time.Millisecond {
+		t.Errorf("Expected exponential waits of 100ms and 200ms, got %v", *waits)
+	}
+}
+
+func TestGetWeatherByLocation_RetryAfterAboveMaxDelay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+	repo, calls, waits := newRateLimitedRepo(t, 1, "60", policy)
+
+	_, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
+
+	var rateLimitErr *domain.RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != time.Minute {
+		t.Fatalf("Expected a rate limit error with retry after 1m, got %v", err)
+	}
+	if *calls != 1 || len(*waits) != 0 {
+		t.Errorf("Expected no retries, got %d calls and waits %v", *calls, *waits)
+	}
+}
+
+// newFlakyViaCEPRepo returns a repository whose server answers status for the first failures requests
+func newFlakyViaCEPRepo(t *testing.T, failures, status int, policy RetryPolicy) (*ViaCEPRepository, *int, *[]time.Duration) {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(domain.ViaCEPResponse{CEP: "01310-100", Localidade: "São Paulo", UF: "SP"})
+	}))
+	t.Cleanup(server.Close)
+
+	var waits []time.Duration
+	repo := &ViaCEPRepository{
+		client:      &http.Client{},
+		baseURL:     server.URL,
+		retryPolicy: policy,
+		sleep:       func(d time.Duration) { waits = append(waits, d) },
+	}
+	return repo, &calls, &waits
+}
+
+func TestGetLocationByCEP_RetriesTransientStatus(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 150 * time.Millisecond, RetryStatusCodes: []int{502, 503}}
+	repo, calls, waits := newFlakyViaCEPRepo(t, 2, http.StatusBadGateway, policy)
+
+	location, err := repo.GetLocationByCEP(context.Background(), "01310100")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if location.Localidade != "São Paulo" || *calls != 3 {
+		t.Errorf("Expected success on the 3rd call, got %q after %d calls", location.Localidade, *calls)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 100*time.Millisecond || (*waits)[1] != 150*time.Millisecond {
+		t.Errorf("Expected waits of 100ms and 150ms (capped), got %v", *waits)
+	}
+}
+
+func TestGetLocationByCEP_RetriesRunOut(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 1, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, RetryStatusCodes: []int{502}}
+	repo, calls, _ := newFlakyViaCEPRepo(t, 5, http.StatusBadGateway, policy)
+
+	_, err := repo.GetLocationByCEP(context.Background(), "01310100")
+	if err == nil || !strings.Contains(err.Error(), "status 502") {
+		t.Errorf("Expected the last 502 to be reported, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", *calls)
+	}
+}
+
+func TestGetLocationByCEP_DoesNotRetryOtherStatus(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, RetryStatusCodes: []int{502}}
+	repo, calls, _ := newFlakyViaCEPRepo(t, 5, http.StatusInternalServerError, policy)
+
+	if _, err := repo.GetLocationByCEP(context.Background(), "01310100"); err == nil {
+		t.Error("Expected an error, got nil")
+	}
+	if *calls != 1 {
+		t.Errorf("Expected no retries for a status outside RetryStatusCodes, got %d calls", *calls)
+	}
+}
+
+func TestDoWithRetry_RetriesAttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	policy := RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, AttemptTimeout: 50 * time.Millisecond}
+	resp, err := doWithRetry(&http.Client{}, policy, func(time.Duration) {}, func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "ok" || calls.Load() != 2 {
+		t.Errorf("Expected body 'ok' after 2 calls, got %q (%v) after %d calls", body, err, calls.Load())
+	}
+}
+
+func TestDoWithRetry_StopsWhenCallerGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, AttemptTimeout: time.Second}
+	_, err := doWithRetry(&http.Client{}, policy, func(time.Duration) {}, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected no retries after the caller's deadline, got %d calls", calls.Load())
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,25 +12,46 @@ import (
 
 // ViaCEPRepository handles communication with ViaCEP API
 type ViaCEPRepository struct {
-	client  *http.Client
-	baseURL string
+	client      *http.Client
+	baseURL     string
+	timeout     time.Duration
+	retryPolicy RetryPolicy
+	sleep       func(time.Duration)
 }
 
 // NewViaCEPRepository creates a new ViaCEP repository
 func NewViaCEPRepository() *ViaCEPRepository {
 	return &ViaCEPRepository{
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		baseURL: "https://viacep.com.br/ws",
+		client:      newHTTPClient(),
+		timeout:     DefaultTimeout,
+		baseURL:     "https://viacep.com.br/ws",
+		retryPolicy: DefaultRetryPolicy,
+		sleep:       time.Sleep,
 	}
 }
 
-// GetLocationByCEP fetches location data from ViaCEP API
-func (r *ViaCEPRepository) GetLocationByCEP(cep string) (*domain.ViaCEPResponse, error) {
+// WithTimeout replaces the deadline of each lookup; 0 leaves it to the incoming request
+func (r *ViaCEPRepository) WithTimeout(timeout time.Duration) *ViaCEPRepository {
+	r.timeout = timeout
+	return r
+}
+
+// WithRetryPolicy replaces the retry policy of the lookups
+func (r *ViaCEPRepository) WithRetryPolicy(policy RetryPolicy) *ViaCEPRepository {
+	r.retryPolicy = policy
+	return r
+}
+
+// GetLocationByCEP fetches location data from ViaCEP API, retrying transient failures
+func (r *ViaCEPRepository) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	url := fmt.Sprintf("%s/%s/json/", r.baseURL, cep)
 
-	resp, err := r.client.Get(url)
+	resp, err := doWithRetry(r.client, r.retryPolicy, r.sleep, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch location data: %w", err)
 	}
@@ -45,8 +67,30 @@ func (r *ViaCEPRepository) GetLocationByCEP(cep string) (*domain.ViaCEPResponse,
 	}
 
 	if viacepResp.Erro {
-		return nil, fmt.Errorf("CEP not found")
+		return nil, domain.ErrCEPNotFound
 	}
 
 	return &viacepResp, nil
 }
+
+// Ping checks that ViaCEP answers a lookup for a well-known CEP
+func (r *ViaCEPRepository) Ping(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/01001000/json/", r.baseURL), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach ViaCEP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ViaCEP API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"cloudrun/internal/domain"
 )
@@ -18,8 +21,8 @@ func TestNewViaCEPRepository(t *testing.T) {
 		t.Errorf("Expected base URL to be %s, got %s", expectedBaseURL, repo.baseURL)
 	}
 
-	if repo.client.Timeout.Seconds() != 10 {
-		t.Errorf("Expected timeout to be 10 seconds, got %v", repo.client.Timeout.Seconds())
+	if repo.timeout != DefaultTimeout {
+		t.Errorf("Expected timeout to be %v, got %v", DefaultTimeout, repo.timeout)
 	}
 }
 
@@ -50,7 +53,7 @@ func TestGetLocationByCEP_Success(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	result, err := repo.GetLocationByCEP("01310100")
+	result, err := repo.GetLocationByCEP(context.Background(), "01310100")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -88,7 +91,7 @@ func TestGetLocationByCEP_NotFound(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetLocationByCEP("99999999")
+	_, err := repo.GetLocationByCEP(context.Background(), "99999999")
 	if err == nil {
 		t.Fatal("Expected error for CEP not found")
 	}
@@ -96,6 +99,9 @@ func TestGetLocationByCEP_NotFound(t *testing.T) {
 	if !strings.Contains(err.Error(), "CEP not found") {
 		t.Errorf("Expected error to contain 'CEP not found', got %v", err.Error())
 	}
+	if !errors.Is(err, domain.ErrCEPNotFound) {
+		t.Errorf("Expected domain.ErrCEPNotFound, got %v", err)
+	}
 }
 
 func TestGetLocationByCEP_HTTPError(t *testing.T) {
@@ -111,7 +117,7 @@ func TestGetLocationByCEP_HTTPError(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetLocationByCEP("01310100")
+	_, err := repo.GetLocationByCEP(context.Background(), "01310100")
 	if err == nil {
 		t.Fatal("Expected error for HTTP 500 response")
 	}
@@ -134,7 +140,7 @@ func TestGetLocationByCEP_InvalidJSON(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetLocationByCEP("01310100")
+	_, err := repo.GetLocationByCEP(context.Background(), "01310100")
 	if err == nil {
 		t.Fatal("Expected error for invalid JSON response")
 	}
@@ -151,7 +157,7 @@ func TestGetLocationByCEP_NetworkError(t *testing.T) {
 		baseURL: "http://invalid-url-that-does-not-exist.local",
 	}
 
-	_, err := repo.GetLocationByCEP("01310100")
+	_, err := repo.GetLocationByCEP(context.Background(), "01310100")
 	if err == nil {
 		t.Fatal("Expected network error")
 	}
@@ -161,6 +167,31 @@ func TestGetLocationByCEP_NetworkError(t *testing.T) {
 	}
 }
 
+func TestGetLocationByCEP_Timeout(t *testing.T) {
+	// Server slower than the lookup timeout
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	repo := NewViaCEPRepository().WithTimeout(50 * time.Millisecond).WithRetryPolicy(RetryPolicy{})
+	repo.baseURL = server.URL
+
+	start := time.Now()
+	_, err := repo.GetLocationByCEP(context.Background(), "01310100")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the lookup to stop after its timeout, took %v", elapsed)
+	}
+}
+
 // Test that verifies URL construction with different CEPs
 func TestGetLocationByCEP_URLConstruction(t *testing.T) {
 	testCases := []struct {
@@ -202,7 +233,7 @@ func TestGetLocationByCEP_URLConstruction(t *testing.T) {
 				baseURL: server.URL,
 			}
 
-			_, err := repo.GetLocationByCEP(tc.cep)
+			_, err := repo.GetLocationByCEP(context.Background(), tc.cep)
 			if err != nil {
 				t.Fatalf("Expected no error, got %v", err)
 			}
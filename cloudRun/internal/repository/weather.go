@@ -1,45 +1,113 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"cloudrun/internal/domain"
 )
 
+// weatherAPINoMatchingLocation is the WeatherAPI error code for an unknown q parameter
+const weatherAPINoMatchingLocation = 1006
+
+// weatherAPIError is the body WeatherAPI sends with 4xx responses
+type weatherAPIError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
 // WeatherAPIRepository handles communication with Weather API
 type WeatherAPIRepository struct {
-	client  *http.Client
-	apiKey  string
-	baseURL string
+	client      *http.Client
+	mu          sync.RWMutex
+	apiKey      string
+	baseURL     string
+	timeout     time.Duration
+	retryPolicy RetryPolicy
+	sleep       func(time.Duration)
 }
 
 // NewWeatherAPIRepository creates a new Weather API repository
 func NewWeatherAPIRepository(apiKey string) *WeatherAPIRepository {
 	return &WeatherAPIRepository{
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		apiKey:  apiKey,
-		baseURL: "https://api.weatherapi.com/v1",
+		client:      newHTTPClient(),
+		timeout:     DefaultTimeout,
+		apiKey:      apiKey,
+		baseURL:     "https://api.weatherapi.com/v1",
+		retryPolicy: DefaultRetryPolicy,
+		sleep:       time.Sleep,
 	}
 }
 
-// GetWeatherByLocation fetches weather data from Weather API
-func (r *WeatherAPIRepository) GetWeatherByLocation(location string) (*domain.WeatherAPIResponse, error) {
+// WithTimeout replaces the deadline of each lookup; 0 leaves it to the incoming request
+func (r *WeatherAPIRepository) WithTimeout(timeout time.Duration) *WeatherAPIRepository {
+	r.timeout = timeout
+	return r
+}
+
+// WithRetryPolicy replaces the retry policy of the lookups
+func (r *WeatherAPIRepository) WithRetryPolicy(policy RetryPolicy) *WeatherAPIRepository {
+	r.retryPolicy = policy
+	return r
+}
+
+// SetAPIKey replaces the key used by the next requests, e.g. after a secret rotation
+func (r *WeatherAPIRepository) SetAPIKey(apiKey string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiKey = apiKey
+}
+
+func (r *WeatherAPIRepository) key() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.apiKey
+}
+
+// GetWeatherByLocation fetches weather data from Weather API, retrying rate-limited requests and transient failures
+func (r *WeatherAPIRepository) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	return r.current(ctx, location)
+}
+
+// GetWeatherByCoordinates fetches weather data for a point, which Weather API takes as a "lat,lon" query
+func (r *WeatherAPIRepository) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	return r.current(ctx, fmt.Sprintf("%.4f,%.4f", latitude, longitude))
+}
+
+// current fetches the current weather for a q parameter: a place name or "lat,lon"
+func (r *WeatherAPIRepository) current(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	// URL encode the location to handle special characters
 	encodedLocation := url.QueryEscape(location)
-	url := fmt.Sprintf("%s/current.json?key=%s&q=%s&aqi=no", r.baseURL, r.apiKey, encodedLocation)
+	url := fmt.Sprintf("%s/current.json?key=%s&q=%s&aqi=no", r.baseURL, r.key(), encodedLocation)
+	// condition.text comes in English unless another language is asked for
+	if lang, ok := domain.Language(ctx); ok && lang != domain.LanguageEnglish {
+		url += "&lang=" + lang
+	}
 
-	resp, err := r.client.Get(url)
+	resp, err := doWithRetry(r.client, r.retryPolicy, r.sleep, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch weather data: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusBadRequest {
+		var apiErr weatherAPIError
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Code == weatherAPINoMatchingLocation {
+			return nil, fmt.Errorf("%w: %s", domain.ErrLocationNotFound, location)
+		}
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("weather API returned status %d for location: %s", resp.StatusCode, location)
 	}
@@ -51,3 +119,30 @@ func (r *WeatherAPIRepository) GetWeatherByLocation(location string) (*domain.We
 
 	return &weatherResp, nil
 }
+
+// Ping checks that the Weather API accepts the configured key
+func (r *WeatherAPIRepository) Ping(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s/current.json?key=%s&q=%s&aqi=no", r.baseURL, r.key(), url.QueryEscape("Sao Paulo"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach weather API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("weather API rejected the API key (status %d)", resp.StatusCode)
+	default:
+		return fmt.Errorf("weather API returned status %d", resp.StatusCode)
+	}
+}
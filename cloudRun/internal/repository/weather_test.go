@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -24,8 +26,8 @@ func TestNewWeatherAPIRepository(t *testing.T) {
 		t.Errorf("Expected base URL to be %s, got %s", expectedBaseURL, repo.baseURL)
 	}
 
-	if repo.client.Timeout.Seconds() != 10 {
-		t.Errorf("Expected timeout to be 10 seconds, got %v", repo.client.Timeout.Seconds())
+	if repo.timeout != DefaultTimeout {
+		t.Errorf("Expected timeout to be %v, got %v", DefaultTimeout, repo.timeout)
 	}
 }
 
@@ -37,9 +39,7 @@ func TestGetWeatherByLocation_URLEncoding(t *testing.T) {
 
 		// Return a valid weather response
 		response := domain.WeatherAPIResponse{
-			Current: struct {
-				TempC float64 `json:"temp_c"`
-			}{
+			Current: domain.WeatherAPICurrent{
 				TempC: 25.0,
 			},
 		}
@@ -57,7 +57,7 @@ func TestGetWeatherByLocation_URLEncoding(t *testing.T) {
 
 	// Test with location containing special characters (São Paulo)
 	location := "São Paulo,SP"
-	_, err := repo.GetWeatherByLocation(location)
+	_, err := repo.GetWeatherByLocation(context.Background(), location)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -87,9 +87,7 @@ func TestGetWeatherByLocation_Success(t *testing.T) {
 	// Mock server with successful response
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := domain.WeatherAPIResponse{
-			Current: struct {
-				TempC float64 `json:"temp_c"`
-			}{
+			Current: domain.WeatherAPICurrent{
 				TempC: 22.5,
 			},
 		}
@@ -104,7 +102,7 @@ func TestGetWeatherByLocation_Success(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	result, err := repo.GetWeatherByLocation("Test Location")
+	result, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -114,6 +112,57 @@ func TestGetWeatherByLocation_Success(t *testing.T) {
 	}
 }
 
+func TestGetWeatherByLocation_Language(t *testing.T) {
+	var lang string
+	var hasLang bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang, hasLang = r.URL.Query().Get("lang"), r.URL.Query().Has("lang")
+		json.NewEncoder(w).Encode(domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 19}})
+	}))
+	defer server.Close()
+
+	repo := &WeatherAPIRepository{client: &http.Client{}, apiKey: "test_key", baseURL: server.URL}
+
+	if _, err := repo.GetWeatherByLocation(domain.WithLanguage(context.Background(), domain.LanguagePortuguese), "São Paulo,SP"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if lang != "pt" {
+		t.Errorf("Expected lang=pt, got %q", lang)
+	}
+
+	// English is the default of WeatherAPI, so it is not sent
+	for _, ctx := range []context.Context{context.Background(), domain.WithLanguage(context.Background(), domain.LanguageEnglish)} {
+		if _, err := repo.GetWeatherByLocation(ctx, "São Paulo,SP"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if hasLang {
+			t.Errorf("Expected no lang parameter, got %q", lang)
+		}
+	}
+}
+
+func TestGetWeatherByCoordinates(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		json.NewEncoder(w).Encode(domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 19}})
+	}))
+	defer server.Close()
+
+	repo := &WeatherAPIRepository{client: &http.Client{}, apiKey: "test_key", baseURL: server.URL}
+
+	result, err := repo.GetWeatherByCoordinates(context.Background(), -23.5505, -46.6333)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query != "-23.5505,-46.6333" {
+		t.Errorf("Expected q=-23.5505,-46.6333, got %q", query)
+	}
+	if result.Current.TempC != 19 {
+		t.Errorf("Expected temperature to be 19, got %v", result.Current.TempC)
+	}
+}
+
 func TestGetWeatherByLocation_HTTPError(t *testing.T) {
 	// Mock server that returns HTTP error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -128,7 +177,7 @@ func TestGetWeatherByLocation_HTTPError(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetWeatherByLocation("Test Location")
+	_, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
 	if err == nil {
 		t.Fatal("Expected error for HTTP 401 response")
 	}
@@ -156,7 +205,7 @@ func TestGetWeatherByLocation_InvalidJSON(t *testing.T) {
 		baseURL: server.URL,
 	}
 
-	_, err := repo.GetWeatherByLocation("Test Location")
+	_, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
 	if err == nil {
 		t.Fatal("Expected error for invalid JSON response")
 	}
@@ -174,7 +223,7 @@ func TestGetWeatherByLocation_NetworkError(t *testing.T) {
 		baseURL: "http://invalid-url-that-does-not-exist.local",
 	}
 
-	_, err := repo.GetWeatherByLocation("Test Location")
+	_, err := repo.GetWeatherByLocation(context.Background(), "Test Location")
 	if err == nil {
 		t.Fatal("Expected network error")
 	}
@@ -234,9 +283,7 @@ func TestGetWeatherByLocation_SpecialCharactersEncoding(t *testing.T) {
 				capturedURL = r.URL.String()
 
 				response := domain.WeatherAPIResponse{
-					Current: struct {
-						TempC float64 `json:"temp_c"`
-					}{
+					Current: domain.WeatherAPICurrent{
 						TempC: 20.0,
 					},
 				}
@@ -251,7 +298,7 @@ func TestGetWeatherByLocation_SpecialCharactersEncoding(t *testing.T) {
 				baseURL: server.URL,
 			}
 
-			_, err := repo.GetWeatherByLocation(tc.location)
+			_, err := repo.GetWeatherByLocation(context.Background(), tc.location)
 			if err != nil {
 				t.Fatalf("Expected no error, got %v", err)
 			}
@@ -262,3 +309,43 @@ func TestGetWeatherByLocation_SpecialCharactersEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWeatherByLocation_NoMatchingLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":1006,"message":"No matching location found."}}`))
+	}))
+	defer server.Close()
+
+	repo := &WeatherAPIRepository{
+		client:  &http.Client{},
+		apiKey:  "test_key",
+		baseURL: server.URL,
+	}
+
+	_, err := repo.GetWeatherByLocation(context.Background(), "Atlantis,Brazil")
+	if !errors.Is(err, domain.ErrLocationNotFound) {
+		t.Errorf("Expected ErrLocationNotFound, got %v", err)
+	}
+}
+
+func TestWeatherAPIRepository_SetAPIKey(t *testing.T) {
+	var capturedKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedKey = r.URL.Query().Get("key")
+		json.NewEncoder(w).Encode(domain.WeatherAPIResponse{})
+	}))
+	defer server.Close()
+
+	repo := NewWeatherAPIRepository("old_key")
+	repo.baseURL = server.URL
+	repo.SetAPIKey("rotated_key")
+
+	if _, err := repo.GetWeatherByLocation(context.Background(), "Curitiba,PR"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if capturedKey != "rotated_key" {
+		t.Errorf("Expected the rotated key, got %s", capturedKey)
+	}
+}
@@ -0,0 +1,226 @@
+// Package secrets reads configuration secrets from Google Secret Manager over
+// its REST API, authenticating as the service account of the Cloud Run instance.
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	secretManagerURL = "https://secretmanager.googleapis.com/v1"
+	// metadataTokenURL answers an access token for the instance service account on Google Cloud
+	metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// AccessTokenEnv overrides the metadata server token, e.g. with
+// $(gcloud auth print-access-token) when running outside Google Cloud
+const AccessTokenEnv = "GOOGLE_OAUTH_ACCESS_TOKEN"
+
+// ErrInvalidName is returned for a name that is not projects/P/secrets/S[/versions/V]
+var ErrInvalidName = errors.New("secret name must be projects/PROJECT/secrets/SECRET[/versions/VERSION]")
+
+// castagnoli is the CRC32C table Secret Manager uses for payload checksums
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Accessor returns the payload of a secret version
+type Accessor interface {
+	Access(ctx context.Context, name string) (string, error)
+}
+
+// SecretManager accesses secret versions through the Secret Manager REST API
+type SecretManager struct {
+	client   *http.Client
+	baseURL  string
+	tokenURL string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewSecretManager creates a Secret Manager accessor
+func NewSecretManager() *SecretManager {
+	return &SecretManager{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		baseURL:  secretManagerURL,
+		tokenURL: metadataTokenURL,
+	}
+}
+
+// accessResponse is the body of the versions.access method
+type accessResponse struct {
+	Payload struct {
+		Data       string `json:"data"`
+		DataCrc32c string `json:"dataCrc32c"`
+	} `json:"payload"`
+}
+
+// Access returns the payload of the secret version name without surrounding
+// whitespace. A name without a version reads the latest one.
+func (m *SecretManager) Access(ctx context.Context, name string) (string, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return "", err
+	}
+	token, err := m.accessToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.baseURL+"/"+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach Secret Manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Secret Manager returned status %d for %s", resp.StatusCode, name)
+	}
+
+	var body accessResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode secret %s: %w", name, err)
+	}
+	data, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret %s: %w", name, err)
+	}
+	if body.Payload.DataCrc32c != "" {
+		want, err := strconv.ParseUint(body.Payload.DataCrc32c, 10, 32)
+		if err != nil || crc32.Checksum(data, castagnoli) != uint32(want) {
+			return "", fmt.Errorf("secret %s failed its CRC32C check", name)
+		}
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// accessToken returns GOOGLE_OAUTH_ACCESS_TOKEN or a metadata server token,
+// reused until a minute before it expires
+func (m *SecretManager) accessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv(AccessTokenEnv); token != "" {
+		return token, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.token != "" && time.Until(m.expiry) > time.Minute {
+		return m.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	m.token = token.AccessToken
+	m.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return m.token, nil
+}
+
+// normalizeName validates name and appends /versions/latest when it has no version
+func normalizeName(name string) (string, error) {
+	parts := strings.Split(strings.Trim(name, "/"), "/")
+	for _, part := range parts {
+		if part == "" {
+			return "", ErrInvalidName
+		}
+	}
+	switch {
+	case len(parts) == 4 && parts[0] == "projects" && parts[2] == "secrets":
+		return strings.Join(parts, "/") + "/versions/latest", nil
+	case len(parts) == 6 && parts[0] == "projects" && parts[2] == "secrets" && parts[4] == "versions":
+		return strings.Join(parts, "/"), nil
+	default:
+		return "", ErrInvalidName
+	}
+}
+
+// Binding ties an environment variable to the secret holding its value
+type Binding struct {
+	// Env is the variable the secret replaces, e.g. WEATHER_API_KEY
+	Env string
+	// Name is the resource name of the secret version
+	Name string
+	// Value is the payload last read
+	Value string
+}
+
+// Watcher re-reads bound secrets and reports the ones whose payload changed
+type Watcher struct {
+	accessor Accessor
+	bindings []Binding
+}
+
+// NewWatcher creates a watcher starting from the values already in bindings
+func NewWatcher(accessor Accessor, bindings []Binding) *Watcher {
+	return &Watcher{accessor: accessor, bindings: append([]Binding(nil), bindings...)}
+}
+
+// Refresh reads every secret once and calls onChange for each new payload.
+// A failed read keeps the previous value and is only logged.
+func (w *Watcher) Refresh(ctx context.Context, onChange func(env, value string)) {
+	for i := range w.bindings {
+		binding := &w.bindings[i]
+		value, err := w.accessor.Access(ctx, binding.Name)
+		if err != nil {
+			slog.WarnContext(ctx, "Secret refresh failed, keeping the current value", "env", binding.Env, "secret", binding.Name, "error", err)
+			continue
+		}
+		if value == binding.Value {
+			continue
+		}
+		binding.Value = value
+		slog.InfoContext(ctx, "Secret changed", "env", binding.Env, "secret", binding.Name)
+		onChange(binding.Env, value)
+	}
+}
+
+// Run refreshes the secrets every interval until ctx is done
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, onChange func(env, value string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Refresh(ctx, onChange)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func newTestSecretManager(t *testing.T, payload string, checksum string, tokenCalls *int) *SecretManager {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		*tokenCalls++
+		fmt.Fprint(w, `{"access_token":"metadata-token","expires_in":3599,"token_type":"Bearer"}`)
+	})
+	mux.HandleFunc("/projects/p/secrets/weather-api-key/versions/latest:access", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer metadata-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"name":"projects/p/secrets/weather-api-key/versions/3","payload":{"data":%q,"dataCrc32c":%q}}`,
+			base64.StdEncoding.EncodeToString([]byte(payload)), checksum)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return &SecretManager{client: &http.Client{}, baseURL: server.URL, tokenURL: server.URL + "/token"}
+}
+
+func checksum(payload string) string {
+	return strconv.FormatUint(uint64(crc32.Checksum([]byte(payload), castagnoli)), 10)
+}
+
+func TestSecretManager_Access(t *testing.T) {
+	tokenCalls := 0
+	manager := newTestSecretManager(t, "secret-key\n", checksum("secret-key\n"), &tokenCalls)
+
+	for range 2 {
+		value, err := manager.Access(context.Background(), "projects/p/secrets/weather-api-key")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if value != "secret-key" {
+			t.Errorf("Expected trimmed payload 'secret-key', got %q", value)
+		}
+	}
+	if tokenCalls != 1 {
+		t.Errorf("Expected the metadata token to be reused, got %d token requests", tokenCalls)
+	}
+}
+
+func TestSecretManager_Access_ChecksumMismatch(t *testing.T) {
+	tokenCalls := 0
+	manager := newTestSecretManager(t, "secret-key", checksum("other"), &tokenCalls)
+
+	if _, err := manager.Access(context.Background(), "projects/p/secrets/weather-api-key/versions/latest"); err == nil {
+		t.Error("Expected a CRC32C error, got nil")
+	}
+}
+
+func TestSecretManager_Access_TokenFromEnv(t *testing.T) {
+	t.Setenv(AccessTokenEnv, "metadata-token")
+	tokenCalls := 0
+	manager := newTestSecretManager(t, "secret-key", "", &tokenCalls)
+
+	if _, err := manager.Access(context.Background(), "projects/p/secrets/weather-api-key"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if tokenCalls != 0 {
+		t.Errorf("Expected no metadata token request, got %d", tokenCalls)
+	}
+}
+
+func TestSecretManager_Access_HTTPError(t *testing.T) {
+	tokenCalls := 0
+	manager := newTestSecretManager(t, "secret-key", "", &tokenCalls)
+
+	if _, err := manager.Access(context.Background(), "projects/p/secrets/missing"); err == nil {
+		t.Error("Expected an error for a missing secret, got nil")
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"projects/p/secrets/s", "projects/p/secrets/s/versions/latest", false},
+		{"projects/p/secrets/s/versions/3", "projects/p/secrets/s/versions/3", false},
+		{"/projects/p/secrets/s/", "projects/p/secrets/s/versions/latest", false},
+		{"weather-api-key", "", true},
+		{"projects/p/secrets//versions/3", "", true},
+		{"projects/p/keys/s", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeName(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+		if tt.wantErr && !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Expected ErrInvalidName for %q, got %v", tt.name, err)
+		}
+	}
+}
+
+// fakeAccessor serves values from a map and fails for names missing from it
+type fakeAccessor map[string]string
+
+func (f fakeAccessor) Access(ctx context.Context, name string) (string, error) {
+	value, ok := f[name]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func TestWatcher_Refresh(t *testing.T) {
+	accessor := fakeAccessor{"projects/p/secrets/a": "old-a", "projects/p/secrets/b": "old-b"}
+	watcher := NewWatcher(accessor, []Binding{
+		{Env: "A", Name: "projects/p/secrets/a", Value: "old-a"},
+		{Env: "B", Name: "projects/p/secrets/b", Value: "old-b"},
+	})
+
+	changed := map[string]string{}
+	onChange := func(env, value string) { changed[env] = value }
+
+	watcher.Refresh(context.Background(), onChange)
+	if len(changed) != 0 {
+		t.Errorf("Expected no change, got %v", changed)
+	}
+
+	accessor["projects/p/secrets/a"] = "new-a"
+	delete(accessor, "projects/p/secrets/b")
+	watcher.Refresh(context.Background(), onChange)
+	if len(changed) != 1 || changed["A"] != "new-a" {
+		t.Errorf("Expected only A to change to new-a, got %v", changed)
+	}
+
+	// A failed read keeps the value, so the secret coming back unchanged is no change
+	accessor["projects/p/secrets/b"] = "old-b"
+	watcher.Refresh(context.Background(), onChange)
+	if _, ok := changed["B"]; ok {
+		t.Errorf("Expected B to keep its value, got %v", changed)
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"cloudrun/internal/domain"
+	"cloudrun/internal/logging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PanicReporter receives every panic Recover turns into a 500, with the stack of
+// the panicking goroutine, e.g. to count it or forward it to Sentry
+type PanicReporter func(r *http.Request, recovered any, stack []byte)
+
+// Recover turns a panicking handler into a 500 and logs the panic with its
+// stack, so one bad request does not take the instance down. The entry is
+// marked for Error Reporting, which groups the panics by stack.
+func Recover(next http.Handler) http.Handler {
+	return RecoverWith()(next)
+}
+
+// RecoverWith is Recover that also hands each panic to reporters. A panicking
+// reporter is logged and skipped.
+func RecoverWith(reporters ...PanicReporter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				stack := debug.Stack()
+
+				// Recover runs before tracing, so correlate through the incoming headers
+				ctx := r.Context()
+				if !trace.SpanContextFromContext(ctx).IsValid() {
+					ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+				}
+				slog.ErrorContext(ctx, fmt.Sprintf("panic: %v", p), "method", r.Method, "path", r.URL.Path,
+					logging.ErrorReportingTypeKey, logging.ReportedErrorEvent,
+					logging.StackTraceKey, fmt.Sprintf("panic: %v\n\n%s", p, stack))
+				for _, report := range reporters {
+					reportPanic(ctx, report, r, p, stack)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(domain.ErrorResponse{Message: "internal server error"})
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// reportPanic calls report, logging instead of propagating a panic of the reporter itself
+func reportPanic(ctx context.Context, report PanicReporter, r *http.Request, recovered any, stack []byte) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.ErrorContext(ctx, "Panic reporter panicked", "panic", p)
+		}
+	}()
+	report(r, recovered, stack)
+}
+
+// Headers browsers may send and read on cross-origin calls
+const (
+	corsAllowMethods  = "GET, POST, OPTIONS"
+	corsAllowHeaders  = "Accept, Content-Type, X-API-Key"
+	corsExposeHeaders = "Cache-Status, Retry-After"
+	corsMaxAge        = "600"
+)
+
+// CORS lets browsers on origins call the API; "*" allows any origin and an
+// empty list sends no CORS headers. Preflights of allowed origins are answered
+// here with 204 and never reach the routes.
+func CORS(origins []string) Middleware {
+	return func(next http.Handler) http.Handler {
+		if len(origins) == 0 {
+			return next
+		}
+		anyOrigin := slices.Contains(origins, "*")
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+			if origin == "" || !(anyOrigin || slices.ContainsFunc(origins, func(o string) bool { return strings.EqualFold(o, origin) })) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+				w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
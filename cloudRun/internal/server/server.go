@@ -0,0 +1,184 @@
+// Package server assembles the HTTP server of the API: ordered middleware
+// chains, route groups with their own middleware and graceful shutdown
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Defaults used unless an option overrides them
+const (
+	DefaultAddr         = ":8080"
+	DefaultDrainTimeout = 8 * time.Second
+)
+
+// Middleware wraps a handler; chains run in the order they are given, the first outermost
+type Middleware func(http.Handler) http.Handler
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithAddr sets the address Listen binds to
+func WithAddr(addr string) Option {
+	return func(s *Server) { s.addr = addr }
+}
+
+// WithDrainTimeout bounds how long Serve waits for in-flight requests on shutdown
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(s *Server) { s.drainTimeout = timeout }
+}
+
+// WithMiddleware wraps every request, routed or not, e.g. panic recovery and
+// CORS preflights, which must also answer methods no route accepts
+func WithMiddleware(mw ...Middleware) Option {
+	return func(s *Server) { s.middleware = append(s.middleware, mw...) }
+}
+
+// WithRouteMiddleware wraps the requests that matched a route, inside the
+// WithMiddleware chain, for middleware that needs the route such as tracing
+func WithRouteMiddleware(mw ...Middleware) Option {
+	return func(s *Server) {
+		for _, m := range mw {
+			s.router.Use(mux.MiddlewareFunc(m))
+		}
+	}
+}
+
+// Server routes requests through the middleware chains to the registered handlers
+type Server struct {
+	root         *Group
+	router       *mux.Router
+	addr         string
+	drainTimeout time.Duration
+	middleware   []Middleware
+}
+
+// New creates a server without routes
+func New(opts ...Option) *Server {
+	s := &Server{
+		router:       mux.NewRouter(),
+		addr:         DefaultAddr,
+		drainTimeout: DefaultDrainTimeout,
+	}
+	s.root = &Group{router: s.router}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Group creates a group of routes under prefix that run mw after the route middleware
+func (s *Server) Group(prefix string, mw ...Middleware) *Group {
+	return s.root.Group(prefix, mw...)
+}
+
+// Handle routes the methods of path to h without group middleware; no methods accepts any
+func (s *Server) Handle(path string, h http.Handler, methods ...string) {
+	s.root.Handle(path, h, methods...)
+}
+
+// HandleFunc is Handle for a handler function
+func (s *Server) HandleFunc(path string, h http.HandlerFunc, methods ...string) {
+	s.root.Handle(path, h, methods...)
+}
+
+// PathPrefix routes every path under prefix to h without group middleware
+func (s *Server) PathPrefix(prefix string, h http.Handler) {
+	s.root.PathPrefix(prefix, h)
+}
+
+// Addr returns the address Listen binds to
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Handler returns the router wrapped in the WithMiddleware chain
+func (s *Server) Handler() http.Handler {
+	return chain(s.router, s.middleware)
+}
+
+// Listen opens the listener for Serve, so bind errors surface before serving
+func (s *Server) Listen() (net.Listener, error) {
+	return net.Listen("tcp", s.addr)
+}
+
+// Serve answers requests on ln until ctx is cancelled, then stops accepting
+// connections and waits up to the drain timeout for in-flight requests to finish
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{Addr: s.addr, Handler: s.Handler()}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down server, draining in-flight requests", "timeout", s.drainTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("server shutdown: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Group registers routes under a path prefix, each wrapped in the group middleware
+type Group struct {
+	router     *mux.Router
+	prefix     string
+	middleware []Middleware
+}
+
+// Group creates a subgroup under prefix whose routes run mw after the middleware of g
+func (g *Group) Group(prefix string, mw ...Middleware) *Group {
+	return &Group{
+		router:     g.router,
+		prefix:     g.prefix + prefix,
+		middleware: append(append([]Middleware(nil), g.middleware...), mw...),
+	}
+}
+
+// Handle routes the methods of path, relative to the group prefix, to h; no
+// methods accepts any. Routes are matched in registration order, so fixed
+// paths must come before patterns such as /{cep}.
+func (g *Group) Handle(path string, h http.Handler, methods ...string) {
+	route := g.router.Handle(g.prefix+path, chain(h, g.middleware))
+	if len(methods) > 0 {
+		route.Methods(methods...)
+	}
+}
+
+// HandleFunc is Handle for a handler function
+func (g *Group) HandleFunc(path string, h http.HandlerFunc, methods ...string) {
+	g.Handle(path, h, methods...)
+}
+
+// PathPrefix routes every path under prefix, relative to the group prefix, to h
+func (g *Group) PathPrefix(prefix string, h http.Handler) {
+	g.router.PathPrefix(g.prefix + prefix).Handler(chain(h, g.middleware))
+}
+
+// chain wraps h in mw, the first middleware outermost
+func chain(h http.Handler, mw []Middleware) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cloudrun/internal/logging"
+)
+
+// tag returns middleware that appends name to the X-Chain header, to observe the order middleware runs in
+func tag(name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Chain", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ok(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestServer_MiddlewareOrder(t *testing.T) {
+	s := New(
+		WithMiddleware(tag("outer1"), tag("outer2")),
+		WithRouteMiddleware(tag("route")),
+	)
+	api := s.Group("/api", tag("api"))
+	api.Group("/v1", tag("v1")).HandleFunc("/items", ok, http.MethodGet)
+	s.HandleFunc("/health", ok, http.MethodGet)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantChain  string
+	}{
+		{"/api/v1/items", http.StatusOK, "outer1,outer2,route,api,v1"},
+		{"/health", http.StatusOK, "outer1,outer2,route"},
+		{"/missing", http.StatusNotFound, "outer1,outer2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if chain := strings.Join(rr.Header().Values("X-Chain"), ","); chain != tt.wantChain {
+				t.Errorf("Expected middleware chain %q, got %q", tt.wantChain, chain)
+			}
+		})
+	}
+}
+
+func TestServer_Methods(t *testing.T) {
+	s := New()
+	s.HandleFunc("/items", ok, http.MethodPost)
+
+	rr := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rr.Code)
+	}
+}
+
+func TestServer_Options(t *testing.T) {
+	s := New(WithAddr(":9090"), WithDrainTimeout(time.Second))
+	if s.Addr() != ":9090" {
+		t.Errorf("Expected address :9090, got %s", s.Addr())
+	}
+	if s.drainTimeout != time.Second {
+		t.Errorf("Expected drain timeout 1s, got %v", s.drainTimeout)
+	}
+
+	if s := New(); s.Addr() != DefaultAddr || s.drainTimeout != DefaultDrainTimeout {
+		t.Errorf("Expected defaults %s and %v, got %s and %v", DefaultAddr, DefaultDrainTimeout, s.Addr(), s.drainTimeout)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	var reported []any
+	h := RecoverWith(
+		func(r *http.Request, recovered any, stack []byte) {
+			panic("reporter bug")
+		},
+		func(r *http.Request, recovered any, stack []byte) {
+			reported = append(reported, recovered)
+			if !strings.Contains(string(stack), "TestRecover") {
+				t.Errorf("Expected the stack of the panicking handler, got %s", stack)
+			}
+		},
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/weather/01310100", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, `"message":"internal server error"`) {
+		t.Errorf("Expected error body, got %s", body)
+	}
+	if len(reported) != 1 || reported[0] != "boom" {
+		t.Errorf("Expected the panic to reach the second reporter, got %v", reported)
+	}
+
+	var entry map[string]any
+	if err := json.NewDecoder(&logs).Decode(&entry); err != nil {
+		t.Fatalf("Failed to decode log entry: %v", err)
+	}
+	if entry["@type"] != logging.ReportedErrorEvent {
+		t.Errorf("Expected the entry marked for Error Reporting, got %v", entry["@type"])
+	}
+	if stack, _ := entry["stack_trace"].(string); !strings.HasPrefix(stack, "panic: boom\n\ngoroutine ") {
+		t.Errorf("Expected a Go stack trace, got %q", stack)
+	}
+}
+
+func TestRecover_AbortHandler(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("Expected http.ErrAbortHandler to propagate, got %v", p)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name       string
+		origins    []string
+		method     string
+		origin     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"allowed origin", []string{"https://app.example.com"}, http.MethodGet, "https://app.example.com", http.StatusOK, "https://app.example.com"},
+		{"other origin", []string{"https://app.example.com"}, http.MethodGet, "https://evil.example.com", http.StatusOK, ""},
+		{"any origin", []string{"*"}, http.MethodGet, "https://evil.example.com", http.StatusOK, "https://evil.example.com"},
+		{"no origin", []string{"*"}, http.MethodGet, "", http.StatusOK, ""},
+		{"disabled", nil, http.MethodGet, "https://app.example.com", http.StatusOK, ""},
+		{"preflight", []string{"*"}, http.MethodOptions, "https://app.example.com", http.StatusNoContent, "https://app.example.com"},
+		{"preflight of other origin", []string{"https://app.example.com"}, http.MethodOptions, "https://evil.example.com", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(WithMiddleware(CORS(tt.origins)))
+			s.HandleFunc("/weather", ok, http.MethodGet)
+
+			req := httptest.NewRequest(tt.method, "/weather", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+			rr := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if origin := rr.Header().Get("Access-Control-Allow-Origin"); origin != tt.wantOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.wantOrigin, origin)
+			}
+			if tt.wantStatus == http.StatusNoContent && rr.Header().Get("Access-Control-Allow-Headers") == "" {
+				t.Error("Expected preflight to list the allowed headers")
+			}
+		})
+	}
+}
+
+// startSlowServer serves a handler that signals started and waits for release before answering
+func startSlowServer(t *testing.T, ctx context.Context, drainTimeout time.Duration) (string, chan struct{}, chan struct{}, chan error) {
+	t.Helper()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := New(WithDrainTimeout(drainTimeout))
+	s.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(ctx, ln)
+	}()
+	return "http://" + ln.Addr().String() + "/", started, release, done
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	url, started, release, done := startSlowServer(t, ctx, 5*time.Second)
+
+	respCh := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			respCh <- 0
+			return
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		respCh <- resp.StatusCode
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Expected serve to wait for the in-flight request, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if status := <-respCh; status != http.StatusOK {
+		t.Errorf("Expected in-flight request to complete with 200, got %d", status)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+}
+
+func TestServe_DrainTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	url, started, release, done := startSlowServer(t, ctx, 20*time.Millisecond)
+	defer close(release)
+
+	go http.Get(url)
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected drain deadline error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected serve to give up after the drain timeout")
+	}
+}
@@ -1,6 +1,10 @@
 package service
 
-import "errors"
+import (
+	"errors"
+
+	"cloudrun/internal/domain"
+)
 
 var (
 	// ErrInvalidCEP is returned when the CEP format is invalid
@@ -9,6 +13,41 @@ var (
 	// ErrCEPNotFound is returned when the CEP is not found
 	ErrCEPNotFound = errors.New("can not find zipcode")
 
+	// ErrLocationUnavailable is returned when ViaCEP fails, times out or keeps
+	// answering 5xx, as opposed to not knowing the CEP
+	ErrLocationUnavailable = errors.New("error fetching zipcode location")
+
+	// ErrInvalidCity is returned when the city name is empty or malformed
+	ErrInvalidCity = errors.New("invalid city")
+
+	// ErrInvalidUF is returned when the state is not a Brazilian UF
+	ErrInvalidUF = errors.New("invalid uf")
+
+	// ErrCityNotFound is returned when the weather provider does not know the city
+	ErrCityNotFound = errors.New("can not find city")
+
+	// ErrInvalidCoordinates is returned when lat or lon is missing or out of range
+	ErrInvalidCoordinates = errors.New("invalid coordinates")
+
+	// ErrCoordinatesNotFound is returned when the weather provider has no data for the point
+	ErrCoordinatesNotFound = errors.New("can not find location")
+
+	// ErrIPNotLocated is returned when the client IP is private or unknown to the geolocation provider
+	ErrIPNotLocated = errors.New("can not geolocate ip")
+
+	// ErrGeolocationUnavailable is returned when the geolocation provider fails or is not configured
+	ErrGeolocationUnavailable = errors.New("error geolocating ip")
+
+	// ErrInvalidPrecision is returned when the temperature precision is not -1 (raw) to MaxPrecision
+	ErrInvalidPrecision = errors.New("invalid precision")
+
+	// ErrInvalidLanguage is returned when the condition language is not en or pt
+	ErrInvalidLanguage = errors.New("invalid lang")
+
 	// ErrWeatherDataUnavailable is returned when weather data cannot be retrieved
 	ErrWeatherDataUnavailable = errors.New("error fetching weather data")
+
+	// ErrRateLimited is returned when the weather provider keeps answering 429;
+	// the error may be a *domain.RateLimitError carrying the requested delay
+	ErrRateLimited = domain.ErrRateLimited
 )
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"cloudrun/internal/cache"
+	"cloudrun/internal/flight"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// flightResult is a shared upstream answer with the cache outcomes of the call
+type flightResult struct {
+	value   any
+	lookups []cache.Lookup
+}
+
+// collapse runs fetch once for all concurrent callers with the same key, so a
+// popular CEP or city costs a single upstream call. The call outlives a caller
+// that stops waiting, which gets its context error, and is cancelled once no
+// caller waits for it anymore.
+func collapse[T any](ctx context.Context, group *flight.Group, key string, fetch func(context.Context) (T, error)) (T, error) {
+	res := group.Do(ctx, key, func(fetchCtx context.Context) (any, error) {
+		fetchCtx, lookups := cache.WithLookups(fetchCtx)
+		value, err := fetch(fetchCtx)
+		return flightResult{value: value, lookups: lookups.All()}, err
+	})
+
+	var zero T
+	if res.Val == nil {
+		return zero, res.Err
+	}
+	shared := res.Val.(flightResult)
+	for _, lookup := range shared.lookups {
+		// A caller that waited for another request did not reach the upstream itself
+		lookup.Collapsed = lookup.Collapsed || !lookup.Hit && !res.Leader
+		cache.Record(ctx, lookup)
+	}
+	if res.Shared && !res.Leader {
+		trace.SpanFromContext(ctx).AddEvent("lookup.collapsed", trace.WithAttributes(attribute.String("lookup.key", key)))
+		slog.DebugContext(ctx, "Collapsed concurrent lookups into one upstream call", "key", key)
+	}
+	if res.Err != nil {
+		return zero, res.Err
+	}
+	return shared.value.(T), nil
+}
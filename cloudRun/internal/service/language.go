@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"cloudrun/internal/domain"
+)
+
+// WithLanguage returns a context in which lookups describe the weather condition
+// in lang (en or pt), overriding the default of the service for one request
+func WithLanguage(ctx context.Context, lang string) (context.Context, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if !domain.ValidLanguage(lang) {
+		return ctx, ErrInvalidLanguage
+	}
+	return domain.WithLanguage(ctx, lang), nil
+}
+
+// withLanguage sets the default language of the service on ctx unless the request chose one
+func (s *WeatherService) withLanguage(ctx context.Context) context.Context {
+	if _, ok := domain.Language(ctx); ok {
+		return ctx
+	}
+	return domain.WithLanguage(ctx, s.defaultLanguage)
+}
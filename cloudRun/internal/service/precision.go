@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"math"
+)
+
+// Temperature precision: decimal places kept in temp_C, temp_F and temp_K
+const (
+	// PrecisionRaw keeps the temperatures as converted, without rounding
+	PrecisionRaw = -1
+	// MaxPrecision is the most decimal places a temperature can be rounded to
+	MaxPrecision = 6
+)
+
+type precisionKey struct{}
+
+// WithPrecision returns a context in which lookups round temperatures to precision
+// decimal places, overriding the default of the service for one request
+func WithPrecision(ctx context.Context, precision int) (context.Context, error) {
+	if !ValidPrecision(precision) {
+		return ctx, ErrInvalidPrecision
+	}
+	return context.WithValue(ctx, precisionKey{}, precision), nil
+}
+
+// ValidPrecision reports whether precision is PrecisionRaw or 0 to MaxPrecision
+func ValidPrecision(precision int) bool {
+	return precision >= PrecisionRaw && precision <= MaxPrecision
+}
+
+// precision returns the precision set on ctx, or the default of the service
+func (s *WeatherService) precision(ctx context.Context) int {
+	if precision, ok := ctx.Value(precisionKey{}).(int); ok {
+		return precision
+	}
+	return s.defaultPrecision
+}
+
+// round rounds v to precision decimal places, turning -0 into 0; PrecisionRaw leaves v as is
+func round(v float64, precision int) float64 {
+	if precision == PrecisionRaw {
+		return v
+	}
+	scale := math.Pow10(precision)
+	rounded := math.Round(v*scale) / scale
+	if rounded == 0 {
+		return 0
+	}
+	return rounded
+}
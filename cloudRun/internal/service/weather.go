@@ -1,62 +1,462 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
 
 	"cloudrun/internal/domain"
+	"cloudrun/internal/flight"
+	"cloudrun/internal/history"
+	"cloudrun/pkg/condition"
 	"cloudrun/pkg/temperature"
 	"cloudrun/pkg/validator"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// WeatherService implements the weather service business logic
+// tracerName names the spans started by the service
+const tracerName = "cloudrun/internal/service"
+
+// coordinatePrecision is the grid coordinate lookups are rounded to, about 1 km,
+// far finer than the resolution of the weather providers
+const coordinatePrecision = 0.01
+
+// BatchResult is the outcome of one CEP of a batch; Weather is nil when Err is set
+type BatchResult struct {
+	CEP     string
+	Weather *domain.DetailedWeatherResponse
+	Err     error
+}
+
+// WeatherService implements the weather service business logic.
+// Concurrent identical upstream lookups share one call.
 type WeatherService struct {
 	locationRepo    domain.LocationService
 	weatherDataRepo domain.WeatherDataService
+	ipLocator       domain.IPLocationService
+	recorder        history.Recorder
+	// defaultPrecision rounds temperatures unless the request context sets its own
+	defaultPrecision int
+	// defaultLanguage describes the condition unless the request context sets its own
+	defaultLanguage string
+	flight          flight.Group
 }
 
 // NewWeatherService creates a new weather service
 func NewWeatherService(locationRepo domain.LocationService, weatherDataRepo domain.WeatherDataService) *WeatherService {
 	return &WeatherService{
-		locationRepo:    locationRepo,
-		weatherDataRepo: weatherDataRepo,
+		locationRepo:     locationRepo,
+		weatherDataRepo:  weatherDataRepo,
+		defaultPrecision: PrecisionRaw,
+		defaultLanguage:  domain.LanguageEnglish,
 	}
 }
 
+// WithIPLocator sets the geolocation provider used by GetWeatherByIP
+func (s *WeatherService) WithIPLocator(ipLocator domain.IPLocationService) *WeatherService {
+	s.ipLocator = ipLocator
+	return s
+}
+
+// WithDefaultPrecision rounds temperatures to precision decimal places unless a
+// request sets its own with WithPrecision; PrecisionRaw disables rounding
+func (s *WeatherService) WithDefaultPrecision(precision int) *WeatherService {
+	s.defaultPrecision = precision
+	return s
+}
+
+// WithDefaultLanguage describes the weather condition in lang (en or pt) unless
+// a request sets its own with WithLanguage
+func (s *WeatherService) WithDefaultLanguage(lang string) *WeatherService {
+	s.defaultLanguage = lang
+	return s
+}
+
+// WithRecorder sends every CEP, city, coordinate and IP lookup to recorder
+func (s *WeatherService) WithRecorder(recorder history.Recorder) *WeatherService {
+	s.recorder = recorder
+	return s
+}
+
 // GetWeatherByCEP gets weather information for a given CEP
-func (s *WeatherService) GetWeatherByCEP(cep string) (*domain.WeatherResponse, error) {
+func (s *WeatherService) GetWeatherByCEP(ctx context.Context, cep string) (*domain.WeatherResponse, error) {
+	weather, err := s.fetchWeather(ctx, cep)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toWeatherResponse(weather, s.precision(ctx))
+	return &response, nil
+}
+
+// GetDetailedWeatherByCEP gets weather information for a given CEP, including
+// the weather condition normalized to the internal enum and icon identifiers
+func (s *WeatherService) GetDetailedWeatherByCEP(ctx context.Context, cep string) (*domain.DetailedWeatherResponse, error) {
+	weather, err := s.fetchWeather(ctx, cep)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toDetailedWeatherResponse(weather, s.precision(ctx))
+	return &response, nil
+}
+
+// GetWeatherByCity gets weather information for a city, skipping the CEP lookup;
+// uf is optional and narrows the search to a state
+func (s *WeatherService) GetWeatherByCity(ctx context.Context, city, uf string) (*domain.WeatherResponse, error) {
+	weather, err := s.fetchCityWeather(ctx, city, uf)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toWeatherResponse(weather, s.precision(ctx))
+	return &response, nil
+}
+
+// GetDetailedWeatherByCity gets weather information for a city, including the normalized condition
+func (s *WeatherService) GetDetailedWeatherByCity(ctx context.Context, city, uf string) (*domain.DetailedWeatherResponse, error) {
+	weather, err := s.fetchCityWeather(ctx, city, uf)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toDetailedWeatherResponse(weather, s.precision(ctx))
+	return &response, nil
+}
+
+// GetWeatherByCoordinates gets weather information for a point, e.g. the GPS position of a mobile client
+func (s *WeatherService) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherResponse, error) {
+	weather, err := s.fetchCoordinatesWeather(ctx, latitude, longitude, history.Lookup{Kind: history.KindCoords})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toWeatherResponse(weather, s.precision(ctx))
+	return &response, nil
+}
+
+// GetDetailedWeatherByCoordinates gets weather information for a point, including the normalized condition
+func (s *WeatherService) GetDetailedWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.DetailedWeatherResponse, error) {
+	weather, err := s.fetchCoordinatesWeather(ctx, latitude, longitude, history.Lookup{Kind: history.KindCoords})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toDetailedWeatherResponse(weather, s.precision(ctx))
+	return &response, nil
+}
+
+// GetWeatherByIP gets weather information for the approximate location of a
+// client IP, with the normalized condition when detailed is set
+func (s *WeatherService) GetWeatherByIP(ctx context.Context, ip string, detailed bool) (*domain.IPWeatherResponse, error) {
+	start := time.Now()
+	location, err := s.locateIP(ctx, ip)
+	if err != nil {
+		s.record(history.Lookup{Kind: history.KindIP}, start, nil, err)
+		return nil, err
+	}
+	lookup := history.Lookup{Kind: history.KindIP, City: location.City, UF: location.Region}
+	weather, err := s.fetchCoordinatesWeather(ctx, location.Latitude, location.Longitude, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.IPWeatherResponse{
+		WeatherResponse: toWeatherResponse(weather, s.precision(ctx)),
+		City:            location.City,
+		Region:          location.Region,
+		Country:         location.Country,
+	}
+	if detailed {
+		condition := toDetailedWeatherResponse(weather, s.precision(ctx)).Condition
+		response.Condition = &condition
+	}
+	return response, nil
+}
+
+// GetDetailedWeatherBatch looks up every CEP with at most workers lookups in flight.
+// Results keep the order of ceps and failures are reported per CEP.
+func (s *WeatherService) GetDetailedWeatherBatch(ctx context.Context, ceps []string, workers int) []BatchResult {
+	results := make([]BatchResult, len(ceps))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(1, min(workers, len(ceps))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.batchItem(ctx, ceps[i])
+			}
+		}()
+	}
+	for i := range ceps {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// batchItem looks up one CEP of a batch in its own span, so the CEP attributes don't collide
+func (s *WeatherService) batchItem(ctx context.Context, cep string) BatchResult {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "weather.batch.item")
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return BatchResult{CEP: cep, Err: err}
+	}
+	weather, err := s.GetDetailedWeatherByCEP(ctx, cep)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return BatchResult{CEP: cep, Weather: weather, Err: err}
+}
+
+// fetchWeather validates the CEP, resolves its location and fetches the current weather
+func (s *WeatherService) fetchWeather(ctx context.Context, cep string) (weather *domain.WeatherAPIResponse, err error) {
+	lookup := history.Lookup{Kind: history.KindCEP, CEP: validator.CleanCEP(cep)}
+	start := time.Now()
+	defer func() { s.record(lookup, start, weather, err) }()
+
 	// Validate CEP format
 	if !validator.ValidateCEP(cep) {
 		return nil, ErrInvalidCEP
 	}
 
-	// Clean CEP (remove dashes and spaces)
+	// Clean CEP (remove dashes, dots and spaces); the canonical form keys the caches
 	cleanCEP := validator.CleanCEP(cep)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("cep.raw", cep),
+		attribute.String("cep.normalized", cleanCEP),
+	)
+	if cleanCEP != cep {
+		slog.InfoContext(ctx, "Normalized CEP", "cep", cep, "normalized_cep", cleanCEP)
+	}
 
 	// Get location by CEP
-	location, err := s.locationRepo.GetLocationByCEP(cleanCEP)
+	location, err := collapse(ctx, &s.flight, "cep:"+cleanCEP, func(ctx context.Context) (*domain.ViaCEPResponse, error) {
+		return s.locationRepo.GetLocationByCEP(ctx, cleanCEP)
+	})
 	if err != nil {
-		log.Printf("Error fetching location for CEP %s: %v", cleanCEP, err)
-		return nil, ErrCEPNotFound
+		if errors.Is(err, domain.ErrCEPNotFound) {
+			slog.InfoContext(ctx, "CEP not found", "cep", cleanCEP)
+			return nil, ErrCEPNotFound
+		}
+		slog.ErrorContext(ctx, "Error fetching location", "cep", cleanCEP, "error", err)
+		return nil, upstreamError(ctx, ErrLocationUnavailable)
 	}
+	lookup.City, lookup.UF = location.Localidade, location.UF
 
 	// Get weather data for the location
 	locationQuery := fmt.Sprintf("%s,%s", location.Localidade, location.UF)
-	log.Printf("Fetching weather for location: %s", locationQuery)
-	weather, err := s.weatherDataRepo.GetWeatherByLocation(locationQuery)
+	slog.InfoContext(ctx, "Fetching weather", "location", locationQuery)
+	weather, err = s.getWeather(ctx, locationQuery)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching weather", "location", locationQuery, "error", err)
+		if errors.Is(err, ErrRateLimited) {
+			return nil, err
+		}
+		return nil, upstreamError(ctx, ErrWeatherDataUnavailable)
+	}
+
+	return weather, nil
+}
+
+// fetchCityWeather validates the city and state and fetches the current weather.
+// Without uf the search is restricted to Brazil.
+func (s *WeatherService) fetchCityWeather(ctx context.Context, city, uf string) (weather *domain.WeatherAPIResponse, err error) {
+	lookup := history.Lookup{Kind: history.KindCity, City: strings.TrimSpace(city), UF: strings.ToUpper(strings.TrimSpace(uf))}
+	start := time.Now()
+	defer func() { s.record(lookup, start, weather, err) }()
+
+	if !validator.ValidateCity(city) {
+		return nil, ErrInvalidCity
+	}
+	region := "Brazil"
+	if uf != "" {
+		if !validator.ValidateUF(uf) {
+			return nil, ErrInvalidUF
+		}
+		region = strings.ToUpper(strings.TrimSpace(uf))
+	}
+
+	locationQuery := fmt.Sprintf("%s,%s", strings.TrimSpace(city), region)
+	slog.InfoContext(ctx, "Fetching weather", "location", locationQuery)
+	weather, err = s.getWeather(ctx, locationQuery)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching weather", "location", locationQuery, "error", err)
+		switch {
+		case errors.Is(err, ErrRateLimited):
+			return nil, err
+		case errors.Is(err, domain.ErrLocationNotFound):
+			return nil, ErrCityNotFound
+		}
+		return nil, upstreamError(ctx, ErrWeatherDataUnavailable)
+	}
+
+	return weather, nil
+}
+
+// fetchCoordinatesWeather validates the point and fetches its current weather. The
+// coordinates are rounded to coordinatePrecision so nearby clients share lookups.
+// The lookup is recorded as lookup, which tells coordinate and IP lookups apart.
+func (s *WeatherService) fetchCoordinatesWeather(ctx context.Context, latitude, longitude float64, lookup history.Lookup) (weather *domain.WeatherAPIResponse, err error) {
+	start := time.Now()
+	defer func() { s.record(lookup, start, weather, err) }()
+
+	if !validator.ValidateCoordinates(latitude, longitude) {
+		return nil, ErrInvalidCoordinates
+	}
+	latitude, longitude = roundCoordinate(latitude), roundCoordinate(longitude)
+
+	point := fmt.Sprintf("%.2f,%.2f", latitude, longitude)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("weather.coordinates", point))
+	slog.InfoContext(ctx, "Fetching weather", "coordinates", point)
+	ctx = s.withLanguage(ctx)
+	weather, err = collapse(ctx, &s.flight, domain.LocalizedKey(ctx, "weather:coords:"+point), func(ctx context.Context) (*domain.WeatherAPIResponse, error) {
+		return s.weatherDataRepo.GetWeatherByCoordinates(ctx, latitude, longitude)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching weather", "coordinates", point, "error", err)
+		switch {
+		case errors.Is(err, ErrRateLimited):
+			return nil, err
+		case errors.Is(err, domain.ErrLocationNotFound):
+			return nil, ErrCoordinatesNotFound
+		}
+		return nil, upstreamError(ctx, ErrWeatherDataUnavailable)
+	}
+
+	return weather, nil
+}
+
+// locateIP geolocates a public client IP. Private, loopback and other
+// non-routable addresses fail without asking the provider.
+func (s *WeatherService) locateIP(ctx context.Context, ip string) (*domain.IPLocation, error) {
+	if s.ipLocator == nil {
+		return nil, ErrGeolocationUnavailable
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return nil, ErrIPNotLocated
+	}
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return nil, ErrIPNotLocated
+	}
+
+	location, err := collapse(ctx, &s.flight, "ip:"+addr.String(), func(ctx context.Context) (*domain.IPLocation, error) {
+		return s.ipLocator.GetLocationByIP(ctx, addr.String())
+	})
 	if err != nil {
-		log.Printf("Error fetching weather for location %s: %v", locationQuery, err)
-		return nil, ErrWeatherDataUnavailable
+		if errors.Is(err, domain.ErrIPNotLocated) {
+			return nil, ErrIPNotLocated
+		}
+		slog.ErrorContext(ctx, "Error geolocating client IP", "error", err)
+		return nil, ErrGeolocationUnavailable
 	}
 
-	// Convert temperatures
+	slog.InfoContext(ctx, "Geolocated client IP", "city", location.City, "region", location.Region, "country", location.Country)
+	return location, nil
+}
+
+// record sends a finished lookup to the recorder, if any
+func (s *WeatherService) record(lookup history.Lookup, start time.Time, weather *domain.WeatherAPIResponse, err error) {
+	if s.recorder == nil {
+		return
+	}
+	lookup.Time = start
+	lookup.Latency = time.Since(start)
+	lookup.Outcome = outcome(err)
+	if weather != nil {
+		tempC := weather.Current.TempC
+		lookup.TempC = &tempC
+	}
+	s.recorder.Record(lookup)
+}
+
+// outcome classifies a lookup error for the query history
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return history.OutcomeOK
+	case errors.Is(err, ErrInvalidCEP), errors.Is(err, ErrInvalidCity), errors.Is(err, ErrInvalidUF), errors.Is(err, ErrInvalidCoordinates):
+		return history.OutcomeInvalidInput
+	case errors.Is(err, ErrCEPNotFound), errors.Is(err, ErrCityNotFound), errors.Is(err, ErrCoordinatesNotFound), errors.Is(err, ErrIPNotLocated):
+		return history.OutcomeNotFound
+	case errors.Is(err, ErrRateLimited):
+		return history.OutcomeRateLimited
+	default:
+		return history.OutcomeError
+	}
+}
+
+// upstreamError returns the deadline or cancellation of ctx when the caller
+// gave up, so a lookup that ran out of time is not reported as unavailable
+func upstreamError(ctx context.Context, unavailable error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return unavailable
+}
+
+// roundCoordinate rounds v to coordinatePrecision, turning -0 into 0 so both share a key
+func roundCoordinate(v float64) float64 {
+	rounded := math.Round(v/coordinatePrecision) * coordinatePrecision
+	if rounded == 0 {
+		return 0
+	}
+	return rounded
+}
+
+// getWeather fetches the weather of locationQuery, sharing the call with
+// concurrent lookups of the same location and language from CEP and city requests alike
+func (s *WeatherService) getWeather(ctx context.Context, locationQuery string) (*domain.WeatherAPIResponse, error) {
+	ctx = s.withLanguage(ctx)
+	return collapse(ctx, &s.flight, domain.LocalizedKey(ctx, "weather:"+strings.ToLower(locationQuery)), func(ctx context.Context) (*domain.WeatherAPIResponse, error) {
+		return s.weatherDataRepo.GetWeatherByLocation(ctx, locationQuery)
+	})
+}
+
+// toWeatherResponse converts the current temperature to all supported scales, each
+// rounded to precision, and describes where the data came from; handlers drop
+// Meta unless it is asked for
+func toWeatherResponse(weather *domain.WeatherAPIResponse, precision int) domain.WeatherResponse {
 	tempC := weather.Current.TempC
-	tempF := temperature.ConvertCelsiusToFahrenheit(tempC)
-	tempK := temperature.ConvertCelsiusToKelvin(tempC)
-
-	return &domain.WeatherResponse{
-		TempC: tempC,
-		TempF: tempF,
-		TempK: tempK,
-	}, nil
+
+	return domain.WeatherResponse{
+		TempC: round(tempC, precision),
+		TempF: round(temperature.ConvertCelsiusToFahrenheit(tempC), precision),
+		TempK: round(temperature.ConvertCelsiusToKelvin(tempC), precision),
+		Meta: &domain.ResponseMeta{
+			Provider:  weather.Provider,
+			FetchedAt: weather.FetchedAt,
+			Cached:    weather.Cached,
+		},
+	}
+}
+
+// toDetailedWeatherResponse adds the condition normalized to the internal enum and icon identifiers
+func toDetailedWeatherResponse(weather *domain.WeatherAPIResponse, precision int) domain.DetailedWeatherResponse {
+	code, icon := condition.Normalize(weather.Current.Condition.Code, weather.Current.IsDay == 1)
+
+	return domain.DetailedWeatherResponse{
+		WeatherResponse: toWeatherResponse(weather, precision),
+		Condition: domain.WeatherCondition{
+			Code: string(code),
+			Icon: icon,
+			Text: weather.Current.Condition.Text,
+		},
+	}
 }
@@ -1,9 +1,21 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"cloudrun/internal/cache"
 	"cloudrun/internal/domain"
+	"cloudrun/internal/history"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // MockLocationRepo for testing
@@ -11,9 +23,9 @@ type MockLocationRepo struct {
 	shouldFail bool
 }
 
-func (m *MockLocationRepo) GetLocationByCEP(cep string) (*domain.ViaCEPResponse, error) {
+func (m *MockLocationRepo) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
 	if m.shouldFail {
-		return nil, ErrCEPNotFound
+		return nil, domain.ErrCEPNotFound
 	}
 
 	// Return different cities to test URL encoding scenarios
@@ -40,7 +52,7 @@ func (m *MockLocationRepo) GetLocationByCEP(cep string) (*domain.ViaCEPResponse,
 			Erro:       false,
 		}, nil
 	}
-	return nil, ErrCEPNotFound
+	return nil, domain.ErrCEPNotFound
 }
 
 // MockWeatherRepo for testing
@@ -48,7 +60,7 @@ type MockWeatherRepo struct {
 	shouldFail bool
 }
 
-func (m *MockWeatherRepo) GetWeatherByLocation(location string) (*domain.WeatherAPIResponse, error) {
+func (m *MockWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
 	if m.shouldFail {
 		return nil, ErrWeatherDataUnavailable
 	}
@@ -58,18 +70,34 @@ func (m *MockWeatherRepo) GetWeatherByLocation(location string) (*domain.Weather
 		"São Paulo,SP":      25.5,
 		"Rio de Janeiro,RJ": 28.0,
 		"Belo Horizonte,MG": 22.0,
+		"Curitiba,Brazil":   18.0,
 	}
 
 	if temp, exists := tempMap[location]; exists {
 		return &domain.WeatherAPIResponse{
-			Current: struct {
-				TempC float64 `json:"temp_c"`
-			}{
+			Current: domain.WeatherAPICurrent{
 				TempC: temp,
 			},
 		}, nil
 	}
 
+	if location == "Atlantis,Brazil" {
+		return nil, domain.ErrLocationNotFound
+	}
+
+	return nil, ErrWeatherDataUnavailable
+}
+
+func (m *MockWeatherRepo) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	if m.shouldFail {
+		return nil, ErrWeatherDataUnavailable
+	}
+	switch fmt.Sprintf("%.2f,%.2f", latitude, longitude) {
+	case "-23.55,-46.63":
+		return &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 25.5}}, nil
+	case "0.00,0.00":
+		return nil, domain.ErrLocationNotFound
+	}
 	return nil, ErrWeatherDataUnavailable
 }
 
@@ -102,7 +130,7 @@ func TestWeatherService_GetWeatherByCEP_Success(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
-			result, err := service.GetWeatherByCEP(tc.cep)
+			result, err := service.GetWeatherByCEP(context.Background(), tc.cep)
 			if err != nil {
 				t.Fatalf("Expected no error, got %v", err)
 			}
@@ -125,6 +153,133 @@ func TestWeatherService_GetWeatherByCEP_Success(t *testing.T) {
 	}
 }
 
+func TestWeatherService_Precision(t *testing.T) {
+	tests := []struct {
+		name             string
+		defaultPrecision int
+		precision        *int
+		wantC            float64
+		wantF            float64
+		wantK            float64
+	}{
+		{"raw by default", PrecisionRaw, nil, 25.5, 77.9, 298.5},
+		{"integers", 0, nil, 26, 78, 299},
+		{"request overrides default", 0, ptr(1), 25.5, 77.9, 298.5},
+		{"request keeps raw", 0, ptr(PrecisionRaw), 25.5, 77.9, 298.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewWeatherService(&MockLocationRepo{}, &MockWeatherRepo{}).WithDefaultPrecision(tt.defaultPrecision)
+			ctx := context.Background()
+			if tt.precision != nil {
+				var err error
+				if ctx, err = WithPrecision(ctx, *tt.precision); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+
+			result, err := service.GetDetailedWeatherByCEP(ctx, "01310100")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.TempC != tt.wantC || result.TempF != tt.wantF || result.TempK != tt.wantK {
+				t.Errorf("Expected %v/%v/%v, got %v/%v/%v", tt.wantC, tt.wantF, tt.wantK, result.TempC, result.TempF, result.TempK)
+			}
+		})
+	}
+
+	for _, precision := range []int{-2, MaxPrecision + 1} {
+		if _, err := WithPrecision(context.Background(), precision); !errors.Is(err, ErrInvalidPrecision) {
+			t.Errorf("Expected ErrInvalidPrecision for %d, got %v", precision, err)
+		}
+	}
+}
+
+// languageWeatherRepo describes the condition in the language the service asked for
+type languageWeatherRepo struct{}
+
+func (m *languageWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	text := "Partly cloudy"
+	if lang, _ := domain.Language(ctx); lang == domain.LanguagePortuguese {
+		text = "Parcialmente nublado"
+	}
+	return &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{
+		TempC:     25,
+		IsDay:     1,
+		Condition: domain.WeatherAPICondition{Text: text, Code: 1003},
+	}}, nil
+}
+
+func (m *languageWeatherRepo) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	return m.GetWeatherByLocation(ctx, "")
+}
+
+func TestWeatherService_Language(t *testing.T) {
+	tests := []struct {
+		name            string
+		defaultLanguage string
+		lang            string
+		want            string
+	}{
+		{"english by default", domain.LanguageEnglish, "", "Partly cloudy"},
+		{"portuguese default", domain.LanguagePortuguese, "", "Parcialmente nublado"},
+		{"request overrides default", domain.LanguageEnglish, "pt", "Parcialmente nublado"},
+		{"request language is case insensitive", domain.LanguagePortuguese, " EN ", "Partly cloudy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewWeatherService(&MockLocationRepo{}, &languageWeatherRepo{}).WithDefaultLanguage(tt.defaultLanguage)
+			ctx := context.Background()
+			if tt.lang != "" {
+				var err error
+				if ctx, err = WithLanguage(ctx, tt.lang); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+
+			result, err := service.GetDetailedWeatherByCEP(ctx, "01310100")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Condition.Text != tt.want {
+				t.Errorf("Expected condition text %q, got %q", tt.want, result.Condition.Text)
+			}
+			if result.Condition.Code != "partly_cloudy" {
+				t.Errorf("Expected the code to stay partly_cloudy, got %q", result.Condition.Code)
+			}
+		})
+	}
+
+	if _, err := WithLanguage(context.Background(), "es"); !errors.Is(err, ErrInvalidLanguage) {
+		t.Errorf("Expected ErrInvalidLanguage, got %v", err)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		v         float64
+		precision int
+		want      float64
+	}{
+		{83.30000000000001, 1, 83.3},
+		{-0.4, 0, 0},
+		{-1.25, 1, -1.3},
+		{1.23456789, 6, 1.234568},
+		{1.23456789, PrecisionRaw, 1.23456789},
+	}
+	for _, tt := range tests {
+		if got := round(tt.v, tt.precision); got != tt.want || math.Signbit(got) != math.Signbit(tt.want) {
+			t.Errorf("round(%v, %d) = %v, want %v", tt.v, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func ptr(v int) *int {
+	return &v
+}
+
 func TestWeatherService_GetWeatherByCEP_InvalidCEP(t *testing.T) {
 	locationRepo := &MockLocationRepo{}
 	weatherRepo := &MockWeatherRepo{}
@@ -140,7 +295,7 @@ func TestWeatherService_GetWeatherByCEP_InvalidCEP(t *testing.T) {
 
 	for _, cep := range invalidCEPs {
 		t.Run("Invalid CEP: "+cep, func(t *testing.T) {
-			_, err := service.GetWeatherByCEP(cep)
+			_, err := service.GetWeatherByCEP(context.Background(), cep)
 			if err != ErrInvalidCEP {
 				t.Errorf("Expected ErrInvalidCEP, got %v", err)
 			}
@@ -153,18 +308,49 @@ func TestWeatherService_GetWeatherByCEP_CEPNotFound(t *testing.T) {
 	weatherRepo := &MockWeatherRepo{}
 	service := NewWeatherService(locationRepo, weatherRepo)
 
-	_, err := service.GetWeatherByCEP("99999999")
+	_, err := service.GetWeatherByCEP(context.Background(), "99999999")
 	if err != ErrCEPNotFound {
 		t.Errorf("Expected ErrCEPNotFound, got %v", err)
 	}
 }
 
+// slowLocationRepo fails CEP lookups like ViaCEP when it is down, after the caller gives up if it blocks
+type slowLocationRepo struct {
+	block bool
+}
+
+func (m *slowLocationRepo) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	if m.block {
+		<-ctx.Done()
+		return nil, fmt.Errorf("failed to fetch location data: %w", ctx.Err())
+	}
+	return nil, errors.New("ViaCEP API returned status 502")
+}
+
+func TestWeatherService_GetWeatherByCEP_LocationUnavailable(t *testing.T) {
+	service := NewWeatherService(&slowLocationRepo{}, &MockWeatherRepo{})
+
+	_, err := service.GetWeatherByCEP(context.Background(), "01310100")
+	if !errors.Is(err, ErrLocationUnavailable) {
+		t.Errorf("Expected ErrLocationUnavailable, got %v", err)
+	}
+
+	service = NewWeatherService(&slowLocationRepo{block: true}, &MockWeatherRepo{})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = service.GetWeatherByCEP(ctx, "01310100")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the caller's deadline, got %v", err)
+	}
+}
+
 func TestWeatherService_GetWeatherByCEP_WeatherDataUnavailable(t *testing.T) {
 	locationRepo := &MockLocationRepo{}
 	weatherRepo := &MockWeatherRepo{shouldFail: true}
 	service := NewWeatherService(locationRepo, weatherRepo)
 
-	_, err := service.GetWeatherByCEP("01310100")
+	_, err := service.GetWeatherByCEP(context.Background(), "01310100")
 	if err != ErrWeatherDataUnavailable {
 		t.Errorf("Expected ErrWeatherDataUnavailable, got %v", err)
 	}
@@ -196,11 +382,16 @@ func TestWeatherService_CEPCleaning(t *testing.T) {
 			expectedTemp: 25.5,
 			description:  "CEP with spaces should be cleaned",
 		},
+		{
+			inputCEP:     "01.310-100", // With dot and dash
+			expectedTemp: 25.5,
+			description:  "CEP with dots should be cleaned",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
-			result, err := service.GetWeatherByCEP(tc.inputCEP)
+			result, err := service.GetWeatherByCEP(context.Background(), tc.inputCEP)
 			if err != nil {
 				t.Fatalf("Expected no error for cleaned CEP, got %v", err)
 			}
@@ -212,6 +403,29 @@ func TestWeatherService_CEPCleaning(t *testing.T) {
 	}
 }
 
+func TestWeatherService_RecordsNormalizedCEPOnSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	service := NewWeatherService(&MockLocationRepo{}, &MockWeatherRepo{})
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "GET /weather/{cep}")
+	if _, err := service.GetWeatherByCEP(ctx, "01310-100"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	span.End()
+
+	attrs := map[attribute.Key]string{}
+	for _, attr := range recorder.Ended()[0].Attributes() {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	if attrs["cep.raw"] != "01310-100" {
+		t.Errorf("Expected cep.raw 01310-100, got %q", attrs["cep.raw"])
+	}
+	if attrs["cep.normalized"] != "01310100" {
+		t.Errorf("Expected cep.normalized 01310100, got %q", attrs["cep.normalized"])
+	}
+}
+
 // Test to ensure our fixes for location name handling work
 func TestWeatherService_LocationQueryConstruction(t *testing.T) {
 	// This test verifies that the service processes different CEPs correctly
@@ -240,7 +454,7 @@ func TestWeatherService_LocationQueryConstruction(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
-			result, err := service.GetWeatherByCEP(tc.cep)
+			result, err := service.GetWeatherByCEP(context.Background(), tc.cep)
 			if err != nil {
 				t.Fatalf("Expected no error, got %v", err)
 			}
@@ -260,3 +474,414 @@ func TestWeatherService_LocationQueryConstruction(t *testing.T) {
 		})
 	}
 }
+
+// rateLimitedWeatherRepo always reports the provider rate limit
+type rateLimitedWeatherRepo struct{}
+
+func (rateLimitedWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	return nil, &domain.RateLimitError{RetryAfter: 5 * time.Second}
+}
+
+func (rateLimitedWeatherRepo) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	return nil, &domain.RateLimitError{RetryAfter: 5 * time.Second}
+}
+
+func TestWeatherService_GetWeatherByCEP_RateLimited(t *testing.T) {
+	service := NewWeatherService(&MockLocationRepo{}, rateLimitedWeatherRepo{})
+
+	_, err := service.GetWeatherByCEP(context.Background(), "01310100")
+
+	var rateLimitErr *domain.RateLimitError
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected the rate limit error to be preserved, got %v", err)
+	}
+	if rateLimitErr.RetryAfter != 5*time.Second {
+		t.Errorf("Expected retry after 5s, got %v", rateLimitErr.RetryAfter)
+	}
+}
+
+func TestWeatherService_GetWeatherByCity(t *testing.T) {
+	service := NewWeatherService(&MockLocationRepo{}, &MockWeatherRepo{})
+
+	tests := []struct {
+		name     string
+		city     string
+		uf       string
+		wantTemp float64
+		wantErr  error
+	}{
+		{"City and UF", "São Paulo", "SP", 25.5, nil},
+		{"Lowercase UF and padded city", " Rio de Janeiro ", "rj", 28.0, nil},
+		{"City without UF", "Curitiba", "", 18.0, nil},
+		{"Invalid city", "São Paulo,SP", "", 0, ErrInvalidCity},
+		{"Empty city", "", "SP", 0, ErrInvalidCity},
+		{"Invalid UF", "São Paulo", "XX", 0, ErrInvalidUF},
+		{"Unknown city", "Atlantis", "", 0, ErrCityNotFound},
+		{"Provider failure", "Natal", "RN", 0, ErrWeatherDataUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.GetWeatherByCity(context.Background(), tt.city, tt.uf)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && result.TempC != tt.wantTemp {
+				t.Errorf("Expected TempC %v, got %v", tt.wantTemp, result.TempC)
+			}
+		})
+	}
+}
+
+func TestWeatherService_GetWeatherByCoordinates(t *testing.T) {
+	service := NewWeatherService(&MockLocationRepo{}, &MockWeatherRepo{})
+
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		wantTemp  float64
+		wantErr   error
+	}{
+		{"Rounded to the grid", -23.5505, -46.6333, 25.5, nil},
+		{"Already on the grid", -23.55, -46.63, 25.5, nil},
+		{"Latitude out of range", 91, 0, 0, ErrInvalidCoordinates},
+		{"Longitude out of range", 0, 181, 0, ErrInvalidCoordinates},
+		{"No data for the point", 0.001, -0.001, 0, ErrCoordinatesNotFound},
+		{"Provider failure", 10, 10, 0, ErrWeatherDataUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.GetWeatherByCoordinates(context.Background(), tt.latitude, tt.longitude)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && result.TempC != tt.wantTemp {
+				t.Errorf("Expected TempC %v, got %v", tt.wantTemp, result.TempC)
+			}
+		})
+	}
+}
+
+// mockIPLocator places one public IP in São Paulo and counts the lookups
+type mockIPLocator struct {
+	calls atomic.Int32
+}
+
+func (m *mockIPLocator) GetLocationByIP(ctx context.Context, ip string) (*domain.IPLocation, error) {
+	m.calls.Add(1)
+	switch ip {
+	case "200.160.2.3":
+		return &domain.IPLocation{City: "São Paulo", Region: "São Paulo", Country: "BR", Latitude: -23.5505, Longitude: -46.6333}, nil
+	case "203.0.113.9":
+		return nil, domain.ErrIPNotLocated
+	}
+	return nil, errors.New("ipapi returned status 429")
+}
+
+func TestWeatherService_GetWeatherByIP(t *testing.T) {
+	locator := &mockIPLocator{}
+	service := NewWeatherService(&MockLocationRepo{}, &MockWeatherRepo{}).WithIPLocator(locator)
+
+	tests := []struct {
+		name    string
+		ip      string
+		wantErr error
+	}{
+		{"Public IP", "200.160.2.3", nil},
+		{"IPv4-mapped IPv6", "::ffff:200.160.2.3", nil},
+		{"Private IP", "192.168.0.10", ErrIPNotLocated},
+		{"Loopback", "127.0.0.1", ErrIPNotLocated},
+		{"Not an IP", "localhost", ErrIPNotLocated},
+		{"Unknown to the provider", "203.0.113.9", ErrIPNotLocated},
+		{"Provider failure", "8.8.8.8", ErrGeolocationUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.GetWeatherByIP(context.Background(), tt.ip, true)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if result.TempC != 25.5 || result.City != "São Paulo" || result.Country != "BR" {
+				t.Errorf("Unexpected response %+v", result)
+			}
+			if result.Condition == nil {
+				t.Error("Expected the condition of a detailed lookup")
+			}
+		})
+	}
+
+	if calls := locator.calls.Load(); calls != 4 {
+		t.Errorf("Expected only public IPs to reach the provider (4 lookups), got %d", calls)
+	}
+}
+
+func TestWeatherService_GetWeatherByIP_NoLocator(t *testing.T) {
+	service := NewWeatherService(&MockLocationRepo{}, &MockWeatherRepo{})
+
+	if _, err := service.GetWeatherByIP(context.Background(), "200.160.2.3", false); !errors.Is(err, ErrGeolocationUnavailable) {
+		t.Errorf("Expected ErrGeolocationUnavailable, got %v", err)
+	}
+}
+
+// recordedLookups collects the lookups sent to the history
+type recordedLookups []history.Lookup
+
+func (r *recordedLookups) Record(lookup history.Lookup) {
+	*r = append(*r, lookup)
+}
+
+func TestWeatherService_RecordsLookups(t *testing.T) {
+	var lookups recordedLookups
+	service := NewWeatherService(&MockLocationRepo{}, &MockWeatherRepo{}).WithRecorder(&lookups)
+	ctx := context.Background()
+
+	service.GetWeatherByCEP(ctx, "01310-100")
+	service.GetDetailedWeatherByCEP(ctx, "99999999")
+	service.GetWeatherByCity(ctx, "Atlantis", "")
+	service.GetWeatherByCoordinates(ctx, 91, 0)
+
+	want := []history.Lookup{
+		{Kind: history.KindCEP, CEP: "01310100", City: "São Paulo", UF: "SP", Outcome: history.OutcomeOK},
+		{Kind: history.KindCEP, CEP: "99999999", Outcome: history.OutcomeNotFound},
+		{Kind: history.KindCity, City: "Atlantis", Outcome: history.OutcomeNotFound},
+		{Kind: history.KindCoords, Outcome: history.OutcomeInvalidInput},
+	}
+	if len(lookups) != len(want) {
+		t.Fatalf("Expected %d recorded lookups, got %d", len(want), len(lookups))
+	}
+	for i, got := range lookups {
+		w := want[i]
+		if got.Kind != w.Kind || got.CEP != w.CEP || got.City != w.City || got.UF != w.UF || got.Outcome != w.Outcome {
+			t.Errorf("Lookup %d: expected %+v, got %+v", i, w, got)
+		}
+		if got.Time.IsZero() {
+			t.Errorf("Lookup %d: expected the start time", i)
+		}
+	}
+	if lookups[0].TempC == nil || *lookups[0].TempC != 25.5 {
+		t.Errorf("Expected the temperature of the successful lookup, got %v", lookups[0].TempC)
+	}
+	if lookups[1].TempC != nil {
+		t.Errorf("Expected no temperature for a failed lookup, got %v", *lookups[1].TempC)
+	}
+}
+
+func TestWeatherService_GetWeatherByCity_RateLimited(t *testing.T) {
+	service := NewWeatherService(&MockLocationRepo{}, rateLimitedWeatherRepo{})
+
+	_, err := service.GetDetailedWeatherByCity(context.Background(), "São Paulo", "SP")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected the rate limit error to be preserved, got %v", err)
+	}
+}
+
+// anyLocationRepo resolves every CEP to a city named after it
+type anyLocationRepo struct{}
+
+func (anyLocationRepo) GetLocationByCEP(ctx context.Context, cep string) (*domain.ViaCEPResponse, error) {
+	if cep == "99999999" {
+		return nil, domain.ErrCEPNotFound
+	}
+	return &domain.ViaCEPResponse{CEP: cep, Localidade: "City " + cep, UF: "SP"}, nil
+}
+
+// concurrencyWeatherRepo records the most lookups seen in flight at once
+type concurrencyWeatherRepo struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (r *concurrencyWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	current := r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+	for {
+		seen := r.maxInFlight.Load()
+		if current <= seen || r.maxInFlight.CompareAndSwap(seen, current) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	var temp float64
+	fmt.Sscanf(location, "City %f", &temp)
+	return &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: temp}}, nil
+}
+
+func (r *concurrencyWeatherRepo) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	return r.GetWeatherByLocation(ctx, fmt.Sprintf("City %f", latitude))
+}
+
+func TestWeatherService_GetDetailedWeatherBatch(t *testing.T) {
+	weatherRepo := &concurrencyWeatherRepo{}
+	service := NewWeatherService(anyLocationRepo{}, weatherRepo)
+
+	ceps := []string{"00000001", "00000002", "invalid", "00000004", "99999999", "00000006", "00000007"}
+	results := service.GetDetailedWeatherBatch(context.Background(), ceps, 2)
+
+	if len(results) != len(ceps) {
+		t.Fatalf("Expected %d results, got %d", len(ceps), len(results))
+	}
+	for i, result := range results {
+		if result.CEP != ceps[i] {
+			t.Errorf("Expected result %d for CEP %s, got %s", i, ceps[i], result.CEP)
+		}
+	}
+	if results[0].Err != nil || results[0].Weather.TempC != 1 {
+		t.Errorf("Expected the weather of the first CEP, got %+v", results[0])
+	}
+	if results[6].Err != nil || results[6].Weather.TempC != 7 {
+		t.Errorf("Expected the weather of the last CEP, got %+v", results[6])
+	}
+	if !errors.Is(results[2].Err, ErrInvalidCEP) {
+		t.Errorf("Expected ErrInvalidCEP, got %v", results[2].Err)
+	}
+	if !errors.Is(results[4].Err, ErrCEPNotFound) {
+		t.Errorf("Expected ErrCEPNotFound, got %v", results[4].Err)
+	}
+	if got := weatherRepo.maxInFlight.Load(); got > 2 {
+		t.Errorf("Expected at most 2 lookups in flight, got %d", got)
+	}
+}
+
+func TestWeatherService_GetDetailedWeatherBatch_CancelledContext(t *testing.T) {
+	service := NewWeatherService(anyLocationRepo{}, &concurrencyWeatherRepo{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := service.GetDetailedWeatherBatch(ctx, []string{"00000001", "00000002"}, 4)
+
+	for _, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Expected context.Canceled for %s, got %v", result.CEP, result.Err)
+		}
+	}
+}
+
+// blockingWeatherRepo counts lookups and holds each one until release is closed
+type blockingWeatherRepo struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	if r.calls.Add(1) == 1 {
+		close(r.started)
+	}
+	cache.Record(ctx, cache.Lookup{Name: "weatherapi"})
+	<-r.release
+	return &domain.WeatherAPIResponse{Current: domain.WeatherAPICurrent{TempC: 21}}, nil
+}
+
+func (r *blockingWeatherRepo) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	return r.GetWeatherByLocation(ctx, fmt.Sprintf("%f,%f", latitude, longitude))
+}
+
+func TestWeatherService_CollapsesConcurrentLookups(t *testing.T) {
+	weatherRepo := &blockingWeatherRepo{started: make(chan struct{}), release: make(chan struct{})}
+	service := NewWeatherService(&MockLocationRepo{}, weatherRepo)
+
+	const callers = 10
+	errs := make(chan error, callers+1)
+	headers := make(chan string, callers)
+	for range callers {
+		go func() {
+			ctx, lookups := cache.WithLookups(context.Background())
+			_, err := service.GetWeatherByCEP(ctx, "01310-100")
+			headers <- lookups.Header()
+			errs <- err
+		}()
+	}
+	// A city lookup for the same location shares the call too
+	go func() {
+		_, err := service.GetWeatherByCity(context.Background(), "são paulo", "sp")
+		errs <- err
+	}()
+
+	<-weatherRepo.started
+	time.Sleep(20 * time.Millisecond)
+	close(weatherRepo.release)
+
+	for range callers + 1 {
+		if err := <-errs; err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
+	if got := weatherRepo.calls.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", got)
+	}
+
+	// Every caller reports the cache miss; only the one that made the call is not collapsed
+	counts := map[string]int{}
+	for range callers {
+		counts[<-headers]++
+	}
+	if counts["weatherapi; fwd=miss; collapsed"] < callers-1 || counts["weatherapi; fwd=miss"] > 1 {
+		t.Errorf("Expected collapsed misses for the waiting callers, got %v", counts)
+	}
+}
+
+func TestWeatherService_CollapsedCallerStopsWaitingOnCancel(t *testing.T) {
+	weatherRepo := &blockingWeatherRepo{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(weatherRepo.release)
+	service := NewWeatherService(&MockLocationRepo{}, weatherRepo)
+
+	go service.GetWeatherByCEP(context.Background(), "01310100")
+	<-weatherRepo.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := service.getWeather(ctx, "São Paulo,SP")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// cancelAwareWeatherRepo holds each lookup until its context ends and reports the error
+type cancelAwareWeatherRepo struct {
+	started chan struct{}
+	ended   chan error
+}
+
+func (r *cancelAwareWeatherRepo) GetWeatherByLocation(ctx context.Context, location string) (*domain.WeatherAPIResponse, error) {
+	close(r.started)
+	<-ctx.Done()
+	r.ended <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func (r *cancelAwareWeatherRepo) GetWeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*domain.WeatherAPIResponse, error) {
+	return r.GetWeatherByLocation(ctx, "")
+}
+
+func TestWeatherService_CancelledRequestCancelsUpstreamCall(t *testing.T) {
+	weatherRepo := &cancelAwareWeatherRepo{started: make(chan struct{}), ended: make(chan error, 1)}
+	service := NewWeatherService(&MockLocationRepo{}, weatherRepo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.GetWeatherByCEP(ctx, "01310100")
+		done <- err
+	}()
+	<-weatherRepo.started
+	cancel()
+
+	select {
+	case err := <-weatherRepo.ended:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the upstream call cancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the upstream call to be cancelled with the request")
+	}
+	if err := <-done; err == nil {
+		t.Error("Expected the cancelled lookup to fail")
+	}
+}
@@ -0,0 +1,38 @@
+package status
+
+import "runtime/debug"
+
+// Build metadata, set at build time with
+// -ldflags "-X cloudrun/internal/status.Version=... -X cloudrun/internal/status.Commit=..."
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	GoVersion string
+}
+
+// Build returns the build metadata, falling back to the VCS revision
+// recorded by the Go toolchain when Commit was not set via ldflags
+func Build() BuildInfo {
+	info := BuildInfo{Version: Version, Commit: Commit}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		if info.Commit == "" {
+			for _, setting := range bi.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Commit = setting.Value
+				}
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	return info
+}
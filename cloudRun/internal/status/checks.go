@@ -0,0 +1,69 @@
+package status
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Check probes a dependency; a nil error means it is healthy
+type Check struct {
+	Name  string
+	Probe func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a Check
+type CheckResult struct {
+	Name    string
+	Healthy bool
+	Error   string
+	Latency time.Duration
+}
+
+// RunChecks runs all checks concurrently, each bounded by timeout
+func RunChecks(ctx context.Context, checks []Check, timeout time.Duration) []CheckResult {
+	results := make([]CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Probe(checkCtx)
+			results[i] = CheckResult{Name: check.Name, Healthy: err == nil, Latency: time.Since(start)}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// CacheStats summarizes a cache
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Entries held by the cache, -1 when unknown (e.g. shared with other instances)
+	Entries int
+}
+
+// HitRatio returns the fraction of lookups served from the cache
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// CacheStatsProvider is implemented by caches that can report their stats
+type CacheStatsProvider interface {
+	CacheStats() CacheStats
+}
@@ -0,0 +1,43 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunChecks(t *testing.T) {
+	checks := []Check{
+		{Name: "healthy", Probe: func(ctx context.Context) error { return nil }},
+		{Name: "failing", Probe: func(ctx context.Context) error { return errors.New("connection refused") }},
+		{Name: "slow", Probe: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}
+
+	results := RunChecks(context.Background(), checks, 20*time.Millisecond)
+
+	if len(results) != len(checks) {
+		t.Fatalf("Expected %d results, got %d", len(checks), len(results))
+	}
+	if !results[0].Healthy || results[0].Name != "healthy" {
+		t.Errorf("Expected healthy check to pass, got %+v", results[0])
+	}
+	if results[1].Healthy || results[1].Error != "connection refused" {
+		t.Errorf("Expected failing check to report its error, got %+v", results[1])
+	}
+	if results[2].Healthy || results[2].Error != context.DeadlineExceeded.Error() {
+		t.Errorf("Expected slow check to time out, got %+v", results[2])
+	}
+}
+
+func TestCacheStats_HitRatio(t *testing.T) {
+	if ratio := (CacheStats{}).HitRatio(); ratio != 0 {
+		t.Errorf("HitRatio() of empty cache = %v, want 0", ratio)
+	}
+	if ratio := (CacheStats{Hits: 3, Misses: 1}).HitRatio(); ratio != 0.75 {
+		t.Errorf("HitRatio() = %v, want 0.75", ratio)
+	}
+}
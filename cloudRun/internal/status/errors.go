@@ -0,0 +1,108 @@
+package status
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrorCounter counts error responses (status >= 400) by status code over a
+// sliding window, using one bucket per minute
+type ErrorCounter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	buckets []errorBucket
+	now     func() time.Time
+}
+
+type errorBucket struct {
+	minute int64
+	counts map[int]int
+}
+
+// ErrorCount is the number of responses with a given status code
+type ErrorCount struct {
+	StatusCode int
+	Count      int
+}
+
+// NewErrorCounter creates a counter remembering errors for window (at least one minute)
+func NewErrorCounter(window time.Duration) *ErrorCounter {
+	minutes := int(window / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return &ErrorCounter{
+		window:  time.Duration(minutes) * time.Minute,
+		buckets: make([]errorBucket, minutes),
+		now:     time.Now,
+	}
+}
+
+// Window returns how far back the counts go
+func (c *ErrorCounter) Window() time.Duration {
+	return c.window
+}
+
+// Record counts statusCode if it is an error
+func (c *ErrorCounter) Record(statusCode int) {
+	if statusCode < http.StatusBadRequest {
+		return
+	}
+
+	minute := c.now().Unix() / 60
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bucket := &c.buckets[minute%int64(len(c.buckets))]
+	if bucket.minute != minute || bucket.counts == nil {
+		bucket.minute = minute
+		bucket.counts = make(map[int]int)
+	}
+	bucket.counts[statusCode]++
+}
+
+// Counts returns the errors within the window, sorted by status code
+func (c *ErrorCounter) Counts() []ErrorCount {
+	oldest := c.now().Unix()/60 - int64(len(c.buckets)) + 1
+
+	totals := make(map[int]int)
+	c.mu.Lock()
+	for _, bucket := range c.buckets {
+		if bucket.minute < oldest {
+			continue
+		}
+		for code, count := range bucket.counts {
+			totals[code] += count
+		}
+	}
+	c.mu.Unlock()
+
+	counts := make([]ErrorCount, 0, len(totals))
+	for code, count := range totals {
+		counts = append(counts, ErrorCount{StatusCode: code, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].StatusCode < counts[j].StatusCode })
+	return counts
+}
+
+// Middleware records the status code of every response
+func (c *ErrorCounter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		c.Record(sw.statusCode)
+	})
+}
+
+// statusWriter captures the status code written by a handler
+type statusWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.statusCode = code
+	w.ResponseWriter.WriteHeader(code)
+}
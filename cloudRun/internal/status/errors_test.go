@@ -0,0 +1,66 @@
+package status
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestErrorCounter_CountsErrorsWithinWindow(t *testing.T) {
+	now := time.Date(2025, 7, 22, 12, 0, 0, 0, time.UTC)
+	counter := NewErrorCounter(5 * time.Minute)
+	counter.now = func() time.Time { return now }
+
+	counter.Record(http.StatusOK)
+	counter.Record(http.StatusNotFound)
+	counter.Record(http.StatusInternalServerError)
+
+	now = now.Add(2 * time.Minute)
+	counter.Record(http.StatusInternalServerError)
+
+	counts := counter.Counts()
+	expected := []ErrorCount{{StatusCode: 404, Count: 1}, {StatusCode: 500, Count: 2}}
+	if len(counts) != len(expected) {
+		t.Fatalf("Counts() = %v, want %v", counts, expected)
+	}
+	for i := range expected {
+		if counts[i] != expected[i] {
+			t.Errorf("Counts()[%d] = %v, want %v", i, counts[i], expected[i])
+		}
+	}
+
+	// The first minute leaves the window, the bucket is reused afterwards
+	now = now.Add(4 * time.Minute)
+	counts = counter.Counts()
+	if len(counts) != 1 || counts[0] != (ErrorCount{StatusCode: 500, Count: 1}) {
+		t.Errorf("Counts() after expiry = %v, want [{500 1}]", counts)
+	}
+
+	counter.Record(http.StatusBadGateway)
+	for _, count := range counter.Counts() {
+		if count.StatusCode == 404 {
+			t.Errorf("Expired 404 reappeared after bucket reuse: %v", counter.Counts())
+		}
+	}
+}
+
+func TestErrorCounter_Middleware(t *testing.T) {
+	counter := NewErrorCounter(time.Minute)
+	handler := counter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+
+	for _, path := range []string{"/ok", "/missing", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	counts := counter.Counts()
+	if len(counts) != 1 || counts[0] != (ErrorCount{StatusCode: 404, Count: 2}) {
+		t.Errorf("Counts() = %v, want [{404 2}]", counts)
+	}
+}
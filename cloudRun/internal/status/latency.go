@@ -0,0 +1,147 @@
+package status
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// LatencyBounds are the upper bounds of the latency histogram buckets; slower
+// responses fall into a last, unbounded bucket
+var LatencyBounds = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// UnmatchedRoute names the requests no route matched, e.g. 404s for unknown paths
+const UnmatchedRoute = "unmatched"
+
+// LatencyRecorder keeps one latency histogram per route since the instance started
+type LatencyRecorder struct {
+	mu     sync.Mutex
+	routes map[string]*histogram
+}
+
+type histogram struct {
+	counts []int
+	total  int
+	sum    time.Duration
+}
+
+// RouteLatency is the latency histogram of one route
+type RouteLatency struct {
+	Route   string
+	Count   int
+	Mean    time.Duration
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+	Buckets []LatencyBucket
+}
+
+// LatencyBucket counts the responses up to UpperBound and slower than the
+// previous bucket; the last bucket has no bound (zero)
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      int
+}
+
+// NewLatencyRecorder creates an empty recorder
+func NewLatencyRecorder() *LatencyRecorder {
+	return &LatencyRecorder{routes: make(map[string]*histogram)}
+}
+
+// Record adds one response of route that took latency
+func (l *LatencyRecorder) Record(route string, latency time.Duration) {
+	bucket := sort.Search(len(LatencyBounds), func(i int) bool { return latency <= LatencyBounds[i] })
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	h, ok := l.routes[route]
+	if !ok {
+		h = &histogram{counts: make([]int, len(LatencyBounds)+1)}
+		l.routes[route] = h
+	}
+	h.counts[bucket]++
+	h.total++
+	h.sum += latency
+}
+
+// Routes returns the histogram of every route seen, sorted by route
+func (l *LatencyRecorder) Routes() []RouteLatency {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	routes := make([]RouteLatency, 0, len(l.routes))
+	for route, h := range l.routes {
+		buckets := make([]LatencyBucket, len(h.counts))
+		for i, count := range h.counts {
+			buckets[i].Count = count
+			if i < len(LatencyBounds) {
+				buckets[i].UpperBound = LatencyBounds[i]
+			}
+		}
+		routes = append(routes, RouteLatency{
+			Route:   route,
+			Count:   h.total,
+			Mean:    h.sum / time.Duration(h.total),
+			P50:     h.quantile(0.50),
+			P95:     h.quantile(0.95),
+			P99:     h.quantile(0.99),
+			Buckets: buckets,
+		})
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Route < routes[j].Route })
+	return routes
+}
+
+// quantile estimates the q quantile as the upper bound of the bucket holding
+// it; quantiles in the unbounded bucket report the last bound
+func (h *histogram) quantile(q float64) time.Duration {
+	rank := int(q*float64(h.total) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	seen := 0
+	for i, count := range h.counts {
+		seen += count
+		if seen >= rank && i < len(LatencyBounds) {
+			return LatencyBounds[i]
+		}
+	}
+	return LatencyBounds[len(LatencyBounds)-1]
+}
+
+// Middleware records the latency of every response under its route template
+// and method, e.g. "GET /weather/{cep}". It must run after routing.
+func (l *LatencyRecorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		l.Record(routeName(r), time.Since(start))
+	})
+}
+
+// routeName returns the method and path template of the route matched for r
+func routeName(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return UnmatchedRoute
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return UnmatchedRoute
+	}
+	return r.Method + " " + template
+}
@@ -0,0 +1,70 @@
+package status
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestLatencyRecorder_Routes(t *testing.T) {
+	recorder := NewLatencyRecorder()
+	for range 90 {
+		recorder.Record("GET /weather/{cep}", 20*time.Millisecond)
+	}
+	for range 9 {
+		recorder.Record("GET /weather/{cep}", 300*time.Millisecond)
+	}
+	recorder.Record("GET /weather/{cep}", 10*time.Second)
+	recorder.Record("GET /health", time.Millisecond)
+
+	routes := recorder.Routes()
+	if len(routes) != 2 || routes[0].Route != "GET /health" || routes[1].Route != "GET /weather/{cep}" {
+		t.Fatalf("Expected the health and weather routes sorted, got %+v", routes)
+	}
+
+	weather := routes[1]
+	if weather.Count != 100 {
+		t.Errorf("Expected count 100, got %d", weather.Count)
+	}
+	if weather.P50 != 25*time.Millisecond {
+		t.Errorf("Expected p50 25ms, got %v", weather.P50)
+	}
+	if weather.P95 != 500*time.Millisecond {
+		t.Errorf("Expected p95 500ms, got %v", weather.P95)
+	}
+	if weather.P99 != 500*time.Millisecond {
+		t.Errorf("Expected p99 500ms, got %v", weather.P99)
+	}
+	if len(weather.Buckets) != len(LatencyBounds)+1 {
+		t.Fatalf("Expected %d buckets, got %d", len(LatencyBounds)+1, len(weather.Buckets))
+	}
+	if last := weather.Buckets[len(weather.Buckets)-1]; last.UpperBound != 0 || last.Count != 1 {
+		t.Errorf("Expected one response in the unbounded bucket, got %+v", last)
+	}
+	if weather.Buckets[2].Count != 90 || weather.Buckets[2].UpperBound != 25*time.Millisecond {
+		t.Errorf("Expected 90 responses up to 25ms, got %+v", weather.Buckets[2])
+	}
+}
+
+func TestLatencyRecorder_Middleware(t *testing.T) {
+	recorder := NewLatencyRecorder()
+	router := mux.NewRouter()
+	router.Use(recorder.Middleware)
+	router.HandleFunc("/weather/{cep}", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/weather/01310100", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/weather/99999999", nil))
+
+	routes := recorder.Routes()
+	if len(routes) != 1 || routes[0].Route != "GET /weather/{cep}" || routes[0].Count != 2 {
+		t.Errorf("Expected two requests under the route template, got %+v", routes)
+	}
+
+	recorder.Middleware(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	if routes := recorder.Routes(); routes[len(routes)-1].Route != UnmatchedRoute {
+		t.Errorf("Expected unmatched requests under %q, got %+v", UnmatchedRoute, routes)
+	}
+}
@@ -0,0 +1,250 @@
+// Package client is a typed Go client for the Weather API served by cmd/api.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudrun/pkg/validator"
+)
+
+// DefaultTimeout bounds a whole GetWeather call, retries included
+const DefaultTimeout = 10 * time.Second
+
+// Weather is the temperature of a CEP; Condition is only set with WithDetail
+type Weather struct {
+	TempC     float64    `json:"temp_C"`
+	TempF     float64    `json:"temp_F"`
+	TempK     float64    `json:"temp_K"`
+	Condition *Condition `json:"condition,omitempty"`
+}
+
+// Condition is the normalized weather condition
+type Condition struct {
+	Code string `json:"code"`
+	Icon string `json:"icon"`
+	Text string `json:"text"`
+}
+
+// RetryPolicy bounds the retries of network errors and 5xx responses
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseDelay is used when Retry-After is absent and doubles on each retry
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait; a longer Retry-After fails without retrying
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries twice, starting at 200ms
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	BaseDelay:  200 * time.Millisecond,
+	MaxDelay:   3 * time.Second,
+}
+
+// Hooks let callers trace each attempt, e.g. starting a span and injecting its
+// headers in RequestStart and ending it in RequestDone. Both are optional.
+type Hooks struct {
+	// RequestStart runs before each attempt; the returned context is used for the request
+	RequestStart func(ctx context.Context, req *http.Request, attempt int) context.Context
+	// RequestDone runs after each attempt with the context returned by RequestStart
+	RequestDone func(ctx context.Context, attempt int, resp *http.Response, err error)
+}
+
+type options struct {
+	httpClient *http.Client
+	timeout    time.Duration
+	retry      RetryPolicy
+	detail     bool
+	hooks      Hooks
+	apiKey     string
+}
+
+// Option configures a Client, or a single GetWeather call overriding the client's options
+type Option func(*options)
+
+// WithHTTPClient sets the http.Client used to send requests
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) { o.httpClient = c }
+}
+
+// WithTimeout bounds a whole GetWeather call, retries included; zero disables it
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithRetry sets the retry policy; RetryPolicy{} disables retries
+func WithRetry(p RetryPolicy) Option {
+	return func(o *options) { o.retry = p }
+}
+
+// WithDetail asks for the detailed response, which includes the condition
+func WithDetail() Option {
+	return func(o *options) { o.detail = true }
+}
+
+// WithHooks sets the tracing hooks
+func WithHooks(h Hooks) Option {
+	return func(o *options) { o.hooks = h }
+}
+
+// WithAPIKey sends key in X-API-Key, for deployments that require API keys
+func WithAPIKey(key string) Option {
+	return func(o *options) { o.apiKey = key }
+}
+
+// Client calls the Weather API
+type Client struct {
+	baseURL string
+	opts    options
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// New creates a client for the API at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	o := options{
+		httpClient: http.DefaultClient,
+		timeout:    DefaultTimeout,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		opts:    o,
+		sleep:   sleepContext,
+	}
+}
+
+// GetWeather returns the temperature of cep. Errors can be compared with
+// errors.Is against ErrInvalidCEP, ErrNotFound, ErrUnauthorized, ErrTooManyRequests,
+// ErrRateLimited and ErrUnavailable.
+func (c *Client) GetWeather(ctx context.Context, cep string, opts ...Option) (*Weather, error) {
+	o := c.opts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if !validator.ValidateCEP(cep) {
+		return nil, ErrInvalidCEP
+	}
+	endpoint := c.baseURL + "/weather/" + url.PathEscape(validator.CleanCEP(cep))
+	if o.detail {
+		endpoint += "?detail=full"
+	}
+
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		weather, delay, err := c.attempt(ctx, o, endpoint, attempt)
+		if err == nil {
+			return weather, nil
+		}
+		if !retryable(err) || attempt >= o.retry.MaxRetries {
+			return nil, err
+		}
+		if delay <= 0 {
+			delay = o.retry.BaseDelay << attempt
+		}
+		if delay > o.retry.MaxDelay {
+			return nil, err
+		}
+		if sleepErr := c.sleep(ctx, delay); sleepErr != nil {
+			return nil, fmt.Errorf("%w: %v", err, sleepErr)
+		}
+	}
+}
+
+// attempt sends one request, returning the delay requested by the API on failure
+func (c *Client) attempt(ctx context.Context, o options, endpoint string, attempt int) (*Weather, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("X-API-Key", o.apiKey)
+	}
+	if o.hooks.RequestStart != nil {
+		ctx = o.hooks.RequestStart(ctx, req, attempt)
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if o.hooks.RequestDone != nil {
+		o.hooks.RequestDone(ctx, attempt, resp, err)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    readMessage(resp.Body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+		return nil, apiErr.RetryAfter, apiErr
+	}
+
+	var weather Weather
+	if err := json.NewDecoder(resp.Body).Decode(&weather); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode weather response: %w", err)
+	}
+	return &weather, 0, nil
+}
+
+// retryable reports whether err may succeed on another attempt
+func retryable(err error) bool {
+	return errors.Is(err, ErrUnavailable) || errors.Is(err, ErrRateLimited) || errors.Is(err, ErrTooManyRequests)
+}
+
+// readMessage reads the message of an ErrorResponse body, falling back to the raw body
+func readMessage(body io.Reader) string {
+	raw, _ := io.ReadAll(io.LimitReader(body, 4096))
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &payload); err == nil && payload.Message != "" {
+		return payload.Message
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// parseRetryAfter reads a Retry-After header in delay-seconds form
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for server that records its waits instead of sleeping
+func newTestClient(server *httptest.Server, opts ...Option) (*Client, *[]time.Duration) {
+	c := New(server.URL, opts...)
+	var waits []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return c, &waits
+}
+
+func TestGetWeather_Success(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.RequestURI()
+		fmt.Fprint(w, `{"temp_C":28.5,"temp_F":83.3,"temp_K":301.5,"condition":{"code":"sunny","icon":"clear-day","text":"Sunny"}}`)
+	}))
+	defer server.Close()
+
+	c, _ := newTestClient(server)
+	weather, err := c.GetWeather(context.Background(), "01310-100", WithDetail())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requested != "/weather/01310100?detail=full" {
+		t.Errorf("Unexpected request %q", requested)
+	}
+	if weather.TempC != 28.5 || weather.Condition == nil || weather.Condition.Code != "sunny" {
+		t.Errorf("Unexpected weather %+v", weather)
+	}
+}
+
+func TestGetWeather_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{"not found", http.StatusNotFound, `{"message":"can not find zipcode"}`, ErrNotFound},
+		{"invalid", http.StatusUnprocessableEntity, `{"message":"invalid zipcode"}`, ErrInvalidCEP},
+		{"server error", http.StatusInternalServerError, `{"message":"internal server error"}`, ErrUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer server.Close()
+
+			c, _ := newTestClient(server, WithRetry(RetryPolicy{}))
+			_, err := c.GetWeather(context.Background(), "01310100")
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, err)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.status {
+				t.Errorf("Expected an APIError with status %d, got %v", tc.status, err)
+			}
+		})
+	}
+}
+
+func TestGetWeather_InvalidCEPIsNotSent(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	c, _ := newTestClient(server)
+	if _, err := c.GetWeather(context.Background(), "123"); !errors.Is(err, ErrInvalidCEP) {
+		t.Errorf("Expected ErrInvalidCEP, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no request, got %d", calls)
+	}
+}
+
+func TestGetWeather_RetriesRateLimit(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"message":"weather provider rate limit exceeded"}`)
+			return
+		}
+		fmt.Fprint(w, `{"temp_C":20,"temp_F":68,"temp_K":293}`)
+	}))
+	defer server.Close()
+
+	c, waits := newTestClient(server)
+	weather, err := c.GetWeather(context.Background(), "01310100")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if weather.TempC != 20 || calls != 2 {
+		t.Errorf("Expected success on the 2nd call, got %v after %d calls", weather.TempC, calls)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 2*time.Second {
+		t.Errorf("Expected to wait the Retry-After delay, got %v", *waits)
+	}
+}
+
+func TestGetWeather_TooManyRequests(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"message":"too many requests"}`)
+	}))
+	defer server.Close()
+
+	c, waits := newTestClient(server)
+	_, err := c.GetWeather(context.Background(), "01310100")
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("Expected ErrTooManyRequests, got %v", err)
+	}
+	if calls != 3 || len(*waits) != 2 || (*waits)[0] != time.Second {
+		t.Errorf("Expected 3 calls waiting the Retry-After delay, got %d calls and waits %v", calls, *waits)
+	}
+}
+
+func TestGetWeather_APIKey(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("X-API-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"invalid api key"}`))
+			return
+		}
+		w.Write([]byte(`{"temp_C":25,"temp_F":77,"temp_K":298}`))
+	}))
+	defer server.Close()
+
+	c, _ := newTestClient(server)
+	_, err := c.GetWeather(context.Background(), "01310100")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a 401 not to be retried, got %d calls", calls)
+	}
+
+	weather, err := c.GetWeather(context.Background(), "01310100", WithAPIKey("secret"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if weather.TempC != 25 {
+		t.Errorf("Expected 25°C, got %v", weather.TempC)
+	}
+}
+
+func TestGetWeather_RetryAfterAboveMaxDelay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := newTestClient(server)
+	_, err := c.GetWeather(context.Background(), "01310100")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single call, got %d", calls)
+	}
+}
+
+func TestGetWeather_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithTimeout(50*time.Millisecond))
+	_, err := c.GetWeather(context.Background(), "01310100")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGetWeather_Hooks(t *testing.T) {
+	type key struct{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Traceparent") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"temp_C":20,"temp_F":68,"temp_K":293}`)
+	}))
+	defer server.Close()
+
+	var done []int
+	hooks := Hooks{
+		RequestStart: func(ctx context.Context, req *http.Request, attempt int) context.Context {
+			req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			return context.WithValue(ctx, key{}, attempt)
+		},
+		RequestDone: func(ctx context.Context, attempt int, resp *http.Response, err error) {
+			if ctx.Value(key{}) == attempt && err == nil {
+				done = append(done, resp.StatusCode)
+			}
+		},
+	}
+
+	c, _ := newTestClient(server)
+	if _, err := c.GetWeather(context.Background(), "01310100", WithHooks(hooks)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(done) != 1 || done[0] != http.StatusOK {
+		t.Errorf("Expected RequestDone with the RequestStart context, got %v", done)
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidCEP is returned for malformed CEPs, checked before any request is sent
+	ErrInvalidCEP = errors.New("invalid zipcode")
+
+	// ErrNotFound is returned when the API can not find the CEP
+	ErrNotFound = errors.New("can not find zipcode")
+
+	// ErrUnauthorized is returned when the API requires an API key and none or
+	// a wrong one was sent with WithAPIKey
+	ErrUnauthorized = errors.New("missing or invalid api key")
+
+	// ErrRateLimited is returned when the API keeps answering 503 because the
+	// weather provider's rate limit was hit
+	ErrRateLimited = errors.New("weather provider rate limit exceeded")
+
+	// ErrTooManyRequests is returned when the API keeps answering 429 because
+	// this client exceeded its per-IP rate limit
+	ErrTooManyRequests = errors.New("too many requests")
+
+	// ErrUnavailable is returned when the API keeps failing with 5xx or network errors
+	ErrUnavailable = errors.New("weather api unavailable")
+)
+
+// APIError describes an unexpected response from the API. It unwraps to the
+// sentinel error matching its status, so callers can use errors.Is.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is the delay requested by the API, if any
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("weather api returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the status code to ErrInvalidCEP, ErrNotFound, ErrUnauthorized,
+// ErrTooManyRequests, ErrRateLimited or ErrUnavailable
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == 422:
+		return ErrInvalidCEP
+	case e.StatusCode == 404:
+		return ErrNotFound
+	case e.StatusCode == 401:
+		return ErrUnauthorized
+	case e.StatusCode == 429:
+		return ErrTooManyRequests
+	case e.StatusCode == 503 && e.RetryAfter > 0:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
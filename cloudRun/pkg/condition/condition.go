@@ -0,0 +1,94 @@
+package condition
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Condition is the stable internal weather condition, independent of the upstream provider
+type Condition string
+
+// Internal weather conditions
+const (
+	Clear        Condition = "clear"
+	PartlyCloudy Condition = "partly_cloudy"
+	Cloudy       Condition = "cloudy"
+	Overcast     Condition = "overcast"
+	Fog          Condition = "fog"
+	Drizzle      Condition = "drizzle"
+	Rain         Condition = "rain"
+	HeavyRain    Condition = "heavy_rain"
+	Sleet        Condition = "sleet"
+	Snow         Condition = "snow"
+	Hail         Condition = "hail"
+	Thunderstorm Condition = "thunderstorm"
+	Unknown      Condition = "unknown"
+)
+
+// UnknownIcon is returned for codes missing from the mapping table
+const UnknownIcon = "unknown"
+
+// Tabela de códigos da WeatherAPI (https://www.weatherapi.com/docs/weather_conditions.json)
+//
+//go:embed conditions.csv
+var conditionsCSV string
+
+type entry struct {
+	condition Condition
+	iconDay   string
+	iconNight string
+}
+
+var table = mustParseTable(conditionsCSV)
+
+// Normalize maps a WeatherAPI condition code to the internal condition and icon identifier.
+// Unknown codes map to Unknown and UnknownIcon.
+func Normalize(code int, isDay bool) (Condition, string) {
+	e, ok := table[code]
+	if !ok {
+		return Unknown, UnknownIcon
+	}
+	if isDay {
+		return e.condition, e.iconDay
+	}
+	return e.condition, e.iconNight
+}
+
+func mustParseTable(data string) map[int]entry {
+	t, err := parseTable(data)
+	if err != nil {
+		panic(fmt.Sprintf("condition: invalid mapping table: %v", err))
+	}
+	return t
+}
+
+func parseTable(data string) (map[int]entry, error) {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty table")
+	}
+
+	t := make(map[int]entry, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 columns, got %d", i+2, len(record))
+		}
+		code, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid code %q", i+2, record[0])
+		}
+		t[code] = entry{
+			condition: Condition(record[1]),
+			iconDay:   record[2],
+			iconNight: record[3],
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,41 @@
+package condition
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name              string
+		code              int
+		isDay             bool
+		expectedCondition Condition
+		expectedIcon      string
+	}{
+		{"Sunny during the day", 1000, true, Clear, "clear-day"},
+		{"Clear at night", 1000, false, Clear, "clear-night"},
+		{"Mist maps to fog", 1030, true, Fog, "fog"},
+		{"Torrential rain shower", 1246, true, HeavyRain, "heavy-rain"},
+		{"Rain with thunder", 1276, false, Thunderstorm, "thunderstorm"},
+		{"Unknown code", 9999, true, Unknown, UnknownIcon},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, icon := Normalize(tt.code, tt.isDay)
+			if cond != tt.expectedCondition {
+				t.Errorf("Normalize(%d) condition = %q, want %q", tt.code, cond, tt.expectedCondition)
+			}
+			if icon != tt.expectedIcon {
+				t.Errorf("Normalize(%d) icon = %q, want %q", tt.code, icon, tt.expectedIcon)
+			}
+		})
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	if _, err := parseTable("code,condition,icon_day,icon_night\n1000,clear,clear-day\n"); err == nil {
+		t.Error("Expected error for row with missing columns")
+	}
+	if _, err := parseTable("code,condition,icon_day,icon_night\nabc,clear,clear-day,clear-night\n"); err == nil {
+		t.Error("Expected error for non-numeric code")
+	}
+}
@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CloudTraceHeader carries the trace context set by Google Cloud load balancers
+// and Cloud Run, formatted as TRACE_ID/SPAN_ID;o=OPTIONS with a decimal span ID
+const CloudTraceHeader = "X-Cloud-Trace-Context"
+
+// CloudTraceContext propagates the trace context in X-Cloud-Trace-Context.
+// Register it before propagation.TraceContext so a traceparent header wins
+// when both are present.
+type CloudTraceContext struct{}
+
+var _ propagation.TextMapPropagator = CloudTraceContext{}
+
+// Inject writes the span context of ctx to the carrier
+func (CloudTraceContext) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	spanID := sc.SpanID()
+	options := 0
+	if sc.IsSampled() {
+		options = 1
+	}
+	carrier.Set(CloudTraceHeader, fmt.Sprintf("%s/%d;o=%d", sc.TraceID(), binary.BigEndian.Uint64(spanID[:]), options))
+}
+
+// Extract returns ctx with the remote span context read from the carrier; an
+// absent or malformed header leaves ctx unchanged
+func (CloudTraceContext) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sc, ok := ParseCloudTraceContext(carrier.Get(CloudTraceHeader))
+	if !ok {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the header the propagator reads and writes
+func (CloudTraceContext) Fields() []string {
+	return []string{CloudTraceHeader}
+}
+
+// ParseCloudTraceContext parses an X-Cloud-Trace-Context value
+func ParseCloudTraceContext(value string) (trace.SpanContext, bool) {
+	value, options, _ := strings.Cut(strings.TrimSpace(value), ";")
+	traceHex, spanDecimal, found := strings.Cut(value, "/")
+	if !found {
+		return trace.SpanContext{}, false
+	}
+
+	traceID, err := trace.TraceIDFromHex(traceHex)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	spanNumber, err := strconv.ParseUint(spanDecimal, 10, 64)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], spanNumber)
+
+	var flags trace.TraceFlags
+	if options == "o=1" {
+		flags = trace.FlagsSampled
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	return sc, sc.IsValid()
+}
@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseCloudTraceContext(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		valid   bool
+		spanID  string
+		sampled bool
+	}{
+		{"sampled", "105445aa7843bc8bf206b12000100000/1;o=1", true, "0000000000000001", true},
+		{"not sampled", "105445aa7843bc8bf206b12000100000/18446744073709551615;o=0", true, "ffffffffffffffff", false},
+		{"without options", "105445aa7843bc8bf206b12000100000/255", true, "00000000000000ff", false},
+		{"missing span", "105445aa7843bc8bf206b12000100000", false, "", false},
+		{"zero span", "105445aa7843bc8bf206b12000100000/0;o=1", false, "", false},
+		{"hex span", "105445aa7843bc8bf206b12000100000/abc;o=1", false, "", false},
+		{"short trace", "105445aa/1;o=1", false, "", false},
+		{"empty", "", false, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, ok := ParseCloudTraceContext(tc.value)
+			if ok != tc.valid {
+				t.Fatalf("Expected valid %v, got %v", tc.valid, ok)
+			}
+			if !ok {
+				return
+			}
+			if sc.TraceID().String() != "105445aa7843bc8bf206b12000100000" {
+				t.Errorf("Expected trace ID 105445aa7843bc8bf206b12000100000, got %s", sc.TraceID())
+			}
+			if sc.SpanID().String() != tc.spanID {
+				t.Errorf("Expected span ID %s, got %s", tc.spanID, sc.SpanID())
+			}
+			if sc.IsSampled() != tc.sampled {
+				t.Errorf("Expected sampled %v, got %v", tc.sampled, sc.IsSampled())
+			}
+		})
+	}
+}
+
+func TestCloudTraceContext_RoundTrip(t *testing.T) {
+	header := http.Header{}
+	header.Set(CloudTraceHeader, "105445aa7843bc8bf206b12000100000/42;o=1")
+	ctx := CloudTraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(header))
+	if !trace.SpanContextFromContext(ctx).IsRemote() {
+		t.Fatal("Expected a remote span context")
+	}
+
+	out := http.Header{}
+	CloudTraceContext{}.Inject(ctx, propagation.HeaderCarrier(out))
+	if got := out.Get(CloudTraceHeader); got != "105445aa7843bc8bf206b12000100000/42;o=1" {
+		t.Errorf("Expected the header to round-trip, got %q", got)
+	}
+}
+
+func TestCloudTraceContext_TraceparentWins(t *testing.T) {
+	header := http.Header{}
+	header.Set(CloudTraceHeader, "105445aa7843bc8bf206b12000100000/42;o=1")
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	propagator := propagation.NewCompositeTextMapPropagator(CloudTraceContext{}, propagation.TraceContext{})
+	sc := trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.HeaderCarrier(header)))
+	if sc.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the traceparent trace ID, got %s", sc.TraceID())
+	}
+}
@@ -0,0 +1,129 @@
+// Package telemetry bootstraps OpenTelemetry tracing for the Weather API.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// Span exporters accepted by TRACE_EXPORTER
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNoop   = "noop"
+)
+
+// shutdownTimeout bounds the final flush so a stuck exporter cannot hold the process
+const shutdownTimeout = 5 * time.Second
+
+// InitTracer initializes tracing with the exporter selected by TRACE_EXPORTER.
+// Without it, spans go to OTLP when an OTEL_EXPORTER_OTLP_* endpoint is set and
+// are dropped otherwise. Sampling and batching follow the standard OTEL_* variables.
+// The returned function flushes pending spans and shuts the provider down.
+func InitTracer(ctx context.Context, serviceName, version string) (func(context.Context) error, error) {
+	kind := ExporterKind()
+	exporter, err := NewExporter(ctx, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		),
+		resource.WithAttributes(cloudRunAttributes()...),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		CloudTraceContext{},
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	slog.Info("Tracing initialized", "service", serviceName, "exporter", kind)
+
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+		defer cancel()
+
+		if err := tp.ForceFlush(ctx); err != nil {
+			slog.Error("Failed to flush spans before shutdown", "error", err)
+		}
+		return tp.Shutdown(ctx)
+	}, nil
+}
+
+// ExporterKind returns TRACE_EXPORTER, defaulting to otlp when an OTLP endpoint
+// is configured and to noop otherwise
+func ExporterKind() string {
+	if kind := strings.ToLower(strings.TrimSpace(os.Getenv("TRACE_EXPORTER"))); kind != "" {
+		return kind
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		return ExporterOTLP
+	}
+	return ExporterNoop
+}
+
+// NewExporter builds the span exporter for kind. The noop exporter returns a
+// nil exporter: spans are still created and propagated but dropped.
+//
+//   - otlp uses OTLP/HTTP configured by the standard OTEL_EXPORTER_OTLP_* variables
+//   - stdout pretty-prints spans to standard output
+func NewExporter(ctx context.Context, kind string) (sdktrace.SpanExporter, error) {
+	switch kind {
+	case ExporterOTLP:
+		return otlptracehttp.New(ctx)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterNoop:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q (expected %s, %s or %s)",
+			kind, ExporterOTLP, ExporterStdout, ExporterNoop)
+	}
+}
+
+// cloudRunAttributes describes the Cloud Run revision from the variables the
+// platform injects; outside Cloud Run it returns nothing
+func cloudRunAttributes() []attribute.KeyValue {
+	service := os.Getenv("K_SERVICE")
+	if service == "" {
+		return nil
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderGCP,
+		semconv.CloudPlatformGCPCloudRun,
+		semconv.FaaSName(service),
+	}
+	if revision := os.Getenv("K_REVISION"); revision != "" {
+		attrs = append(attrs, semconv.FaaSVersion(revision))
+	}
+	return attrs
+}
@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExporterKind(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"no configuration", nil, ExporterNoop},
+		{"otlp endpoint", map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}, ExporterOTLP},
+		{"otlp traces endpoint", map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces"}, ExporterOTLP},
+		{"explicit exporter wins", map[string]string{"TRACE_EXPORTER": " Stdout ", "OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}, ExporterStdout},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("TRACE_EXPORTER", "")
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			if got := ExporterKind(); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewExporter_Unknown(t *testing.T) {
+	if _, err := NewExporter(context.Background(), "zipkin"); err == nil {
+		t.Error("Expected error for unknown exporter, got nil")
+	}
+}
+
+func TestCloudRunAttributes(t *testing.T) {
+	t.Setenv("K_SERVICE", "")
+	if attrs := cloudRunAttributes(); len(attrs) != 0 {
+		t.Errorf("Expected no attributes outside Cloud Run, got %v", attrs)
+	}
+
+	t.Setenv("K_SERVICE", "weather-api")
+	t.Setenv("K_REVISION", "weather-api-00042-abc")
+	attrs := cloudRunAttributes()
+	found := map[string]string{}
+	for _, attr := range attrs {
+		found[string(attr.Key)] = attr.Value.Emit()
+	}
+	if found["faas.name"] != "weather-api" {
+		t.Errorf("Expected faas.name weather-api, got %q", found["faas.name"])
+	}
+	if found["faas.version"] != "weather-api-00042-abc" {
+		t.Errorf("Expected faas.version weather-api-00042-abc, got %q", found["faas.version"])
+	}
+	if found["cloud.platform"] != "gcp_cloud_run" {
+		t.Errorf("Expected cloud.platform gcp_cloud_run, got %q", found["cloud.platform"])
+	}
+}
@@ -0,0 +1,149 @@
+package temperature
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Unit is a temperature scale
+type Unit string
+
+// Supported units
+const (
+	Celsius    Unit = "C"
+	Fahrenheit Unit = "F"
+	Kelvin     Unit = "K"
+	Rankine    Unit = "R"
+)
+
+// KelvinOffset is the Celsius value of 0 K used by the API, rounded to whole
+// degrees as the API has always answered; Rankine follows it
+const KelvinOffset = 273
+
+var (
+	// ErrUnknownUnit is returned for a unit other than Celsius, Fahrenheit, Kelvin or Rankine
+	ErrUnknownUnit = errors.New("unknown temperature unit")
+
+	// ErrInvalidTemperature is returned when a temperature cannot be parsed
+	ErrInvalidTemperature = errors.New("invalid temperature")
+)
+
+// unitNames maps the lowercase names and symbols accepted by ParseUnit to their unit
+var unitNames = map[string]Unit{
+	"c": Celsius, "°c": Celsius, "celsius": Celsius,
+	"f": Fahrenheit, "°f": Fahrenheit, "fahrenheit": Fahrenheit,
+	"k": Kelvin, "kelvin": Kelvin,
+	"r": Rankine, "°r": Rankine, "ra": Rankine, "rankine": Rankine,
+}
+
+// ParseUnit reads a unit from its symbol or name, e.g. "C", "°F", "kelvin" or "Ra"
+func ParseUnit(s string) (Unit, error) {
+	unit, ok := unitNames[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownUnit, s)
+	}
+	return unit, nil
+}
+
+// Symbol returns how the unit is written after a value: °C, °F, K or °R
+func (u Unit) Symbol() string {
+	if u == Kelvin {
+		return string(u)
+	}
+	return "°" + string(u)
+}
+
+// Valid reports whether u is a supported unit
+func (u Unit) Valid() bool {
+	switch u {
+	case Celsius, Fahrenheit, Kelvin, Rankine:
+		return true
+	}
+	return false
+}
+
+// Temperature is a value in a unit
+type Temperature struct {
+	Value float64
+	Unit  Unit
+}
+
+// Parse reads a temperature written as a number followed by a unit, e.g.
+// "28.5°C", "83.3 F" or "-10 celsius"
+func Parse(s string) (Temperature, error) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndexAny(s, "0123456789.") + 1
+	if i == 0 {
+		return Temperature{}, fmt.Errorf("%w: %q", ErrInvalidTemperature, s)
+	}
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return Temperature{}, fmt.Errorf("%w: %q", ErrInvalidTemperature, s)
+	}
+	unit, err := ParseUnit(s[i:])
+	if err != nil {
+		return Temperature{}, err
+	}
+	return Temperature{Value: value, Unit: unit}, nil
+}
+
+// To converts t to unit
+func (t Temperature) To(unit Unit) (Temperature, error) {
+	value, err := Convert(t.Value, t.Unit, unit)
+	if err != nil {
+		return Temperature{}, err
+	}
+	return Temperature{Value: value, Unit: unit}, nil
+}
+
+// Format writes t with precision decimal places, or as few as needed with -1, e.g. "28.5°C"
+func (t Temperature) Format(precision int) string {
+	return strconv.FormatFloat(t.Value, 'f', precision, 64) + t.Unit.Symbol()
+}
+
+// String writes t with as few decimal places as needed
+func (t Temperature) String() string {
+	return t.Format(-1)
+}
+
+// Convert converts value from one unit to another, going through Celsius
+func Convert(value float64, from, to Unit) (float64, error) {
+	if !from.Valid() {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, from)
+	}
+	if !to.Valid() {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, to)
+	}
+	if from == to {
+		return value, nil
+	}
+	return fromCelsius(toCelsius(value, from), to), nil
+}
+
+// toCelsius converts value in a valid unit to Celsius
+func toCelsius(value float64, from Unit) float64 {
+	switch from {
+	case Fahrenheit:
+		return (value - 32) / 1.8
+	case Kelvin:
+		return value - KelvinOffset
+	case Rankine:
+		return value/1.8 - KelvinOffset
+	}
+	return value
+}
+
+// fromCelsius converts a Celsius value to a valid unit
+func fromCelsius(celsius float64, to Unit) float64 {
+	switch to {
+	case Fahrenheit:
+		return celsius*1.8 + 32
+	case Kelvin:
+		return celsius + KelvinOffset
+	case Rankine:
+		return (celsius + KelvinOffset) * 1.8
+	}
+	return celsius
+}
@@ -0,0 +1,118 @@
+package temperature
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to Unit
+		expected float64
+	}{
+		{"Celsius to Fahrenheit", 100, Celsius, Fahrenheit, 212},
+		{"Celsius to Kelvin", 28.5, Celsius, Kelvin, 301.5},
+		{"Celsius to Rankine", 0, Celsius, Rankine, 491.4},
+		{"Fahrenheit to Kelvin", 32, Fahrenheit, Kelvin, 273},
+		{"Kelvin to Fahrenheit", 373, Kelvin, Fahrenheit, 212},
+		{"Rankine to Celsius", 491.4, Rankine, Celsius, 0},
+		{"Rankine to Kelvin", 540, Rankine, Kelvin, 300},
+		{"Same unit", -40, Fahrenheit, Fahrenheit, -40},
+		{"Scales cross", -40, Celsius, Fahrenheit, -40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(tt.value, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.value, tt.from, tt.to, result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := Convert(1, Celsius, "X"); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("Expected ErrUnknownUnit, got %v", err)
+	}
+	if _, err := Convert(1, "", Kelvin); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("Expected ErrUnknownUnit, got %v", err)
+	}
+}
+
+func TestConvert_MatchesLegacyFunctions(t *testing.T) {
+	for _, celsius := range []float64{-10, 0, 28.5, 37} {
+		if f, _ := Convert(celsius, Celsius, Fahrenheit); f != ConvertCelsiusToFahrenheit(celsius) {
+			t.Errorf("Expected %v°F for %v°C, got %v", ConvertCelsiusToFahrenheit(celsius), celsius, f)
+		}
+		if k, _ := Convert(celsius, Celsius, Kelvin); k != ConvertCelsiusToKelvin(celsius) {
+			t.Errorf("Expected %vK for %v°C, got %v", ConvertCelsiusToKelvin(celsius), celsius, k)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Temperature
+	}{
+		{"28.5°C", Temperature{28.5, Celsius}},
+		{"83.3 F", Temperature{83.3, Fahrenheit}},
+		{"301.5K", Temperature{301.5, Kelvin}},
+		{"-10 celsius", Temperature{-10, Celsius}},
+		{" 500 Ra ", Temperature{500, Rankine}},
+		{"0°r", Temperature{0, Rankine}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+
+	for input, expected := range map[string]error{
+		"":      ErrInvalidTemperature,
+		"hot":   ErrInvalidTemperature,
+		"1-2C":  ErrInvalidTemperature,
+		"28.5":  ErrUnknownUnit,
+		"28.5X": ErrUnknownUnit,
+	} {
+		if _, err := Parse(input); !errors.Is(err, expected) {
+			t.Errorf("Parse(%q): expected %v, got %v", input, expected, err)
+		}
+	}
+}
+
+func TestTemperature_ToAndFormat(t *testing.T) {
+	celsius := Temperature{Value: 28.5, Unit: Celsius}
+
+	fahrenheit, err := celsius.To(Fahrenheit)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := fahrenheit.Format(1); got != "83.3°F" {
+		t.Errorf("Expected 83.3°F, got %s", got)
+	}
+
+	kelvin, _ := celsius.To(Kelvin)
+	if got := kelvin.String(); got != "301.5K" {
+		t.Errorf("Expected 301.5K, got %s", got)
+	}
+	if got := (Temperature{Value: 28.6, Unit: Celsius}).Format(0); got != "29°C" {
+		t.Errorf("Expected 29°C, got %s", got)
+	}
+
+	if _, err := celsius.To("X"); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("Expected ErrUnknownUnit, got %v", err)
+	}
+}
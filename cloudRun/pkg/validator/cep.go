@@ -3,27 +3,26 @@ package validator
 import (
 	"regexp"
 	"strings"
+	"unicode"
 )
 
-// ValidateCEP validates Brazilian postal code format
-func ValidateCEP(cep string) bool {
-	// Remove traços e espaços
-	cep = strings.ReplaceAll(cep, "-", "")
-	cep = strings.ReplaceAll(cep, " ", "")
-
-	// Verifica se tem exatamente 8 dígitos
-	if len(cep) != 8 {
-		return false
-	}
+// cepPattern matches a clean CEP: exactly 8 ASCII digits
+var cepPattern = regexp.MustCompile(`^[0-9]{8}$`)
 
-	// Verifica se todos são números
-	matched, _ := regexp.MatchString(`^\d{8}$`, cep)
-	return matched
+// ValidateCEP validates Brazilian postal code format. Formatted CEPs such as
+// 01310-100 are valid; letters or a wrong number of digits are not.
+func ValidateCEP(cep string) bool {
+	// Remove traços, pontos e espaços e verifica se restam exatamente 8 dígitos
+	return cepPattern.MatchString(CleanCEP(cep))
 }
 
-// CleanCEP removes dashes and spaces from CEP
+// CleanCEP returns the canonical form of cep, removing dashes, dots and
+// whitespace, so 01310-100, 01.310-100 and 01310100 share one cache key
 func CleanCEP(cep string) string {
-	cep = strings.ReplaceAll(cep, "-", "")
-	cep = strings.ReplaceAll(cep, " ", "")
-	return cep
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, cep)
 }
@@ -11,11 +11,16 @@ func TestValidateCEP(t *testing.T) {
 		{"Valid CEP without dash", "01310100", true},
 		{"Valid CEP with dash", "01310-100", true},
 		{"Valid CEP with spaces", "01310 100", true},
+		{"Valid CEP with dots", "01.310-100", true},
 		{"Invalid CEP too short", "0131010", false},
 		{"Invalid CEP too long", "013101000", false},
 		{"Invalid CEP with letters", "0131010A", false},
 		{"Invalid CEP empty", "", false},
 		{"Invalid CEP special chars", "01310@100", false},
+		{"Invalid CEP with letter O", "0131O-100", false},
+		{"Invalid CEP alphabetic", "abcdefgh", false},
+		{"Invalid CEP non-ASCII digits", "０１３１０１００", false},
+		{"Invalid CEP only separators", "-.-", false},
 	}
 
 	for _, tt := range tests {
@@ -39,6 +44,8 @@ func TestCleanCEP(t *testing.T) {
 		{"CEP with dash and spaces", "01310- 100", "01310100"},
 		{"Clean CEP", "01310100", "01310100"},
 		{"Multiple dashes and spaces", "0-1 3-1 0-1 0-0", "01310100"},
+		{"CEP with dots", "01.310-100", "01310100"},
+		{"CEP with tabs and newlines", "\t01310-100\n", "01310100"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxCityLength is longer than any Brazilian municipality name
+const maxCityLength = 60
+
+// states holds the 27 Brazilian federative units
+var states = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// ValidateCity validates a city name: letters, spaces, hyphens, apostrophes and dots
+func ValidateCity(city string) bool {
+	city = strings.TrimSpace(city)
+	if city == "" || utf8.RuneCountInString(city) > maxCityLength {
+		return false
+	}
+
+	hasLetter := false
+	for _, r := range city {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.Is(unicode.Mn, r), r == ' ', r == '-', r == '\'', r == '.':
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// ValidateUF validates a Brazilian state abbreviation, case-insensitively
+func ValidateUF(uf string) bool {
+	return states[strings.ToUpper(strings.TrimSpace(uf))]
+}
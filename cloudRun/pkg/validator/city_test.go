@@ -0,0 +1,54 @@
+package validator
+
+import "testing"
+
+func TestValidateCity(t *testing.T) {
+	tests := []struct {
+		name     string
+		city     string
+		expected bool
+	}{
+		{"Valid city", "Curitiba", true},
+		{"Valid city with accents", "São Paulo", true},
+		{"Valid city with hyphen and apostrophe", "Olho-d'Água das Flores", true},
+		{"Valid city with surrounding spaces", "  Natal ", true},
+		{"Invalid city empty", "", false},
+		{"Invalid city only spaces", "   ", false},
+		{"Invalid city only punctuation", "--", false},
+		{"Invalid city with digits", "Cidade 1", false},
+		{"Invalid city with separator", "São Paulo,SP", false},
+		{"Invalid city too long", "Aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateCity(tt.city)
+			if result != tt.expected {
+				t.Errorf("ValidateCity(%q) = %v, want %v", tt.city, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateUF(t *testing.T) {
+	tests := []struct {
+		name     string
+		uf       string
+		expected bool
+	}{
+		{"Valid UF", "SP", true},
+		{"Valid UF lowercase", "rj", true},
+		{"Invalid UF unknown", "XX", false},
+		{"Invalid UF empty", "", false},
+		{"Invalid UF full name", "São Paulo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateUF(tt.uf)
+			if result != tt.expected {
+				t.Errorf("ValidateUF(%q) = %v, want %v", tt.uf, result, tt.expected)
+			}
+		})
+	}
+}
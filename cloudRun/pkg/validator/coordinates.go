@@ -0,0 +1,6 @@
+package validator
+
+// ValidateCoordinates validates a latitude in [-90, 90] and a longitude in [-180, 180]; NaN is invalid
+func ValidateCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
@@ -0,0 +1,32 @@
+package validator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidateCoordinates(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		expected  bool
+	}{
+		{"Valid São Paulo", -23.5505, -46.6333, true},
+		{"Valid bounds", 90, -180, true},
+		{"Valid origin", 0, 0, true},
+		{"Invalid latitude", 90.1, 0, false},
+		{"Invalid longitude", 0, -180.5, false},
+		{"Invalid NaN", math.NaN(), 0, false},
+		{"Invalid infinity", 0, math.Inf(1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateCoordinates(tt.latitude, tt.longitude)
+			if result != tt.expected {
+				t.Errorf("ValidateCoordinates(%v, %v) = %v, want %v", tt.latitude, tt.longitude, result, tt.expected)
+			}
+		})
+	}
+}
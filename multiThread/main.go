@@ -1,12 +1,31 @@
 ﻿package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type BrasilAPIResponse struct {
@@ -36,117 +55,642 @@ type ViaCEPResponse struct {
 }
 
 type CEPResult struct {
-	CEP      string
-	Street   string
-	District string
-	City     string
-	State    string
-	Source   string
+	CEP      string `json:"cep"`
+	Street   string `json:"street"`
+	District string `json:"district"`
+	City     string `json:"city"`
+	State    string `json:"state"`
+	Source   string `json:"source"`
 }
 
-func fetchBrasilAPI(cep string, ch chan<- CEPResult) {
-	url := fmt.Sprintf("https://brasilapi.com.br/api/cep/v1/%s", cep)
+// Exit codes, stable so scripts and CI jobs can tell failures apart
+const (
+	exitOK                 = 0
+	exitError              = 1 // unexpected error, e.g. unreadable batch file
+	exitInvalidInput       = 2
+	exitAllProvidersFailed = 3
+	exitTimeout            = 4
+	exitPartialSuccess     = 5 // batch mode: some CEPs failed
+)
+
+var (
+	errInvalidInput       = errors.New("entrada inválida")
+	errAllProvidersFailed = errors.New("todas as APIs falharam")
+	errTimeout            = errors.New("timeout - nenhuma API respondeu em 1 segundo")
+)
 
-	resp, err := http.Get(url)
+// classify maps an error to its exit code and the error code used in JSON output
+func classify(err error) (int, string) {
+	switch {
+	case err == nil:
+		return exitOK, ""
+	case errors.Is(err, errInvalidInput):
+		return exitInvalidInput, "invalid_input"
+	case errors.Is(err, errAllProvidersFailed):
+		return exitAllProvidersFailed, "all_providers_failed"
+	case errors.Is(err, errTimeout):
+		return exitTimeout, "timeout"
+	default:
+		return exitError, "error"
+	}
+}
+
+// Trace exporters accepted by -trace and TRACE_EXPORTER, the same ones the OTel services use
+const (
+	exporterZipkin = "zipkin"
+	exporterOTLP   = "otlp"
+	exporterStdout = "stdout"
+)
+
+// defaultZipkinURL is the Zipkin of the OTel docker-compose stack
+const defaultZipkinURL = "http://localhost:9411/api/v2/spans"
+
+// tracer creates the lookup and provider spans; it is a no-op until initTracing
+// installs a provider
+var tracer = otel.Tracer("multiThread")
+
+// flushTracing sends the pending spans; exit calls it since os.Exit skips defers
+var flushTracing = func() {}
+
+// exporterFromEnv picks the exporter when -trace is not given: TRACE_EXPORTER,
+// else zipkin when ZIPKIN_URL is set, else otlp when an OTLP endpoint is set.
+// Tracing stays off when none of them is set.
+func exporterFromEnv() string {
+	switch {
+	case os.Getenv("TRACE_EXPORTER") != "":
+		return os.Getenv("TRACE_EXPORTER")
+	case os.Getenv("ZIPKIN_URL") != "":
+		return exporterZipkin
+	case os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "", os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "":
+		return exporterOTLP
+	default:
+		return ""
+	}
+}
+
+// initTracing installs a tracer provider exporting to kind: Zipkin at
+// ZIPKIN_URL, OTLP/HTTP configured by the OTEL_EXPORTER_OTLP_* variables, or
+// stderr so it does not mix with the JSON of -quiet
+func initTracing(kind string) error {
+	ctx := context.Background()
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(kind) {
+	case exporterZipkin:
+		url := os.Getenv("ZIPKIN_URL")
+		if url == "" {
+			url = defaultZipkinURL
+		}
+		exporter, err = zipkin.New(url)
+	case exporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case exporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr), stdouttrace.WithPrettyPrint())
+	default:
+		return fmt.Errorf("%w: exportador de traces %q desconhecido (use %s, %s ou %s)",
+			errInvalidInput, kind, exporterZipkin, exporterOTLP, exporterStdout)
+	}
 	if err != nil {
-		return
+		return fmt.Errorf("não foi possível criar o exportador de traces: %w", err)
+	}
+
+	res := resource.NewWithAttributes("", semconv.ServiceName("multithread-cep"))
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+
+	flushTracing = func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Aviso: falha ao enviar os traces: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// exit flushes the pending spans and terminates with code
+func exit(code int) {
+	flushTracing()
+	os.Exit(code)
+}
+
+// providerResult is the answer of one API: a result or the reason it failed.
+// winner is set for the first successful answer of the race.
+type providerResult struct {
+	result CEPResult
+	err    error
+	winner bool
+}
+
+// race hands the win to the first provider that claims it, so the span of
+// exactly one provider is marked as the winner
+type race struct {
+	done atomic.Bool
+}
+
+// claim reports whether the caller won; later claims, including the one made
+// by lookupCEP when it gives up, lose
+func (r *race) claim() bool {
+	return r.done.CompareAndSwap(false, true)
+}
+
+// getJSON fetches url into v, failing on any status other than 200
+func getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
 	}
 	defer resp.Body.Close()
 
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
 	if resp.StatusCode != 200 {
-		return
+		return fmt.Errorf("status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return
+		return err
 	}
+	return json.Unmarshal(body, v)
+}
+
+func fetchBrasilAPI(ctx context.Context, cep string) (CEPResult, error) {
+	url := fmt.Sprintf("https://brasilapi.com.br/api/cep/v1/%s", cep)
 
 	var result BrasilAPIResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return
+	if err := getJSON(ctx, url, &result); err != nil {
+		return CEPResult{}, fmt.Errorf("BrasilAPI: %w", err)
 	}
 
-	ch <- CEPResult{
+	return CEPResult{
 		CEP:      result.CEP,
 		Street:   result.Street,
 		District: result.District,
 		City:     result.City,
 		State:    result.State,
 		Source:   "BrasilAPI",
-	}
+	}, nil
 }
 
-func fetchViaCEP(cep string, ch chan<- CEPResult) {
+func fetchViaCEP(ctx context.Context, cep string) (CEPResult, error) {
 	url := fmt.Sprintf("http://viacep.com.br/ws/%s/json/", cep)
 
-	resp, err := http.Get(url)
+	var result ViaCEPResponse
+	if err := getJSON(ctx, url, &result); err != nil {
+		return CEPResult{}, fmt.Errorf("ViaCEP: %w", err)
+	}
+
+	return CEPResult{
+		CEP:      result.CEP,
+		Street:   result.Logradouro,
+		District: result.Bairro,
+		City:     result.Localidade,
+		State:    result.UF,
+		Source:   "ViaCEP",
+	}, nil
+}
+
+// provider is one API taking part in the race
+type provider struct {
+	name  string
+	fetch func(ctx context.Context, cep string) (CEPResult, error)
+}
+
+var providers = []provider{
+	{name: "BrasilAPI", fetch: fetchBrasilAPI},
+	{name: "ViaCEP", fetch: fetchViaCEP},
+}
+
+// runProvider queries p inside its own span and sends the answer to ch
+func runProvider(ctx context.Context, p provider, cep string, r *race, ch chan<- providerResult) {
+	ctx, span := tracer.Start(ctx, "cep.provider "+p.name, trace.WithAttributes(
+		attribute.String("cep.provider", p.name),
+		attribute.String("cep.code", cep),
+	))
+	defer span.End()
+
+	result, err := p.fetch(ctx, cep)
+	winner := err == nil && r.claim()
+	span.SetAttributes(attribute.Bool("cep.winner", winner))
 	if err != nil {
-		return
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
-	defer resp.Body.Close()
+	ch <- providerResult{result: result, err: err, winner: winner}
+}
 
-	if resp.StatusCode != 200 {
-		return
+// batchConcurrency limits how many CEPs are raced at the same time in batch mode
+const batchConcurrency = 10
+
+// lookupCEP races BrasilAPI and ViaCEP and returns the fastest successful answer
+// within 1 second, errAllProvidersFailed if both fail first, or errTimeout.
+// The providers still running when it returns are cancelled.
+func lookupCEP(ctx context.Context, cep string) (CEPResult, time.Duration, error) {
+	ctx, span := tracer.Start(ctx, "cep.lookup", trace.WithAttributes(attribute.String("cep.code", cep)))
+	defer span.End()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result, elapsed, err := runRace(ctx, cep)
+	span.SetAttributes(attribute.Int64("cep.elapsed_ms", elapsed.Milliseconds()))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.String("cep.winner", result.Source))
 	}
+	return result, elapsed, err
+}
 
-	body, err := io.ReadAll(resp.Body)
+// runRace starts every provider and waits for the winner, the failure of all
+// of them or the 1 second timeout
+func runRace(ctx context.Context, cep string) (CEPResult, time.Duration, error) {
+	ch := make(chan providerResult, len(providers))
+	start := time.Now()
+
+	r := &race{}
+	for _, p := range providers {
+		go runProvider(ctx, p, cep, r, ch)
+	}
+
+	timeout := time.After(1 * time.Second)
+	var failures []string
+	for received := 0; received < len(providers); {
+		select {
+		case res := <-ch:
+			received++
+			if res.winner {
+				return res.result, time.Since(start), nil
+			}
+			if res.err != nil {
+				failures = append(failures, res.err.Error())
+			}
+		case <-timeout:
+			// A provider that already claimed the win is about to send its answer
+			if r.claim() {
+				return CEPResult{}, time.Since(start), errTimeout
+			}
+		}
+	}
+	return CEPResult{}, time.Since(start), fmt.Errorf("%w: %s", errAllProvidersFailed, strings.Join(failures, "; "))
+}
+
+// lookupOutput is the JSON printed for one CEP in quiet mode
+type lookupOutput struct {
+	Input     string     `json:"input"`
+	Result    *CEPResult `json:"result,omitempty"`
+	ElapsedMS int64      `json:"elapsed_ms"`
+	Error     string     `json:"error,omitempty"`
+	ErrorCode string     `json:"error_code,omitempty"`
+}
+
+// batchOutput is the JSON printed for a batch in quiet mode
+type batchOutput struct {
+	Results  []lookupOutput `json:"results"`
+	Total    int            `json:"total"`
+	Unique   int            `json:"unique"`
+	Failed   int            `json:"failed"`
+	ExitCode int            `json:"exit_code"`
+}
+
+func newLookupOutput(input string, result CEPResult, elapsed time.Duration, err error) lookupOutput {
+	out := lookupOutput{Input: input, ElapsedMS: elapsed.Milliseconds()}
 	if err != nil {
-		return
+		_, out.ErrorCode = classify(err)
+		out.Error = err.Error()
+		return out
 	}
+	out.Result = &result
+	return out
+}
 
-	var result ViaCEPResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return
+// printJSON writes v as the only output of quiet mode
+func printJSON(v any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// output selects how results are printed: human-readable (default), JSON
+// (-quiet) or one line per CEP rendered from -template
+type output struct {
+	quiet    bool
+	template *template.Template
+}
+
+// machine reports whether only results are printed, without progress messages
+func (o output) machine() bool {
+	return o.quiet || o.template != nil
+}
+
+// templateData is what -template sees for each CEP: the result fields
+// (.City, .State, .Source, ...) plus the input, latency and error, so
+// failures can be rendered too
+type templateData struct {
+	CEPResult
+	Input     string
+	Elapsed   time.Duration
+	ElapsedMS int64
+	Error     string
+	ErrorCode string
+}
+
+func newTemplateData(input string, result CEPResult, elapsed time.Duration, err error) templateData {
+	data := templateData{CEPResult: result, Input: input, Elapsed: elapsed.Round(time.Millisecond), ElapsedMS: elapsed.Milliseconds()}
+	if err != nil {
+		_, data.ErrorCode = classify(err)
+		data.Error = err.Error()
 	}
+	return data
+}
 
-	ch <- CEPResult{
-		CEP:      result.CEP,
-		Street:   result.Logradouro,
-		District: result.Bairro,
-		City:     result.Localidade,
-		State:    result.UF,
-		Source:   "ViaCEP",
+// parseTemplate parses the -template flag; \n and \t are unescaped so they can be typed in a shell
+func parseTemplate(text string) (*template.Template, error) {
+	text = strings.NewReplacer(`\n`, "\n", `\t`, "\t").Replace(text)
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: template inválido: %v", errInvalidInput, err)
 	}
+	return tmpl, nil
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Uso: go run main.go <CEP>")
-		fmt.Println("Exemplo: go run main.go 01153000")
-		os.Exit(1)
+// render prints one CEP with the template, ending the line if the template does not.
+// A template referring to unknown fields stops the program with exitInvalidInput.
+func (o output) render(data templateData) {
+	var b strings.Builder
+	if err := o.template.Execute(&b, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v: template inválido: %v\n", errInvalidInput, err)
+		exit(exitInvalidInput)
+	}
+	line := b.String()
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
 	}
+	fmt.Print(line)
+}
 
-	cep := os.Args[1]
+// errInvalidCEP is the validation error for a CEP without 8 digits
+var errInvalidCEP = fmt.Errorf("%w: CEP deve ter 8 dígitos", errInvalidInput)
 
+// fail reports err for input and exits with its exit code
+func fail(input string, err error, out output, hints ...string) {
+	code, _ := classify(err)
+	if out.quiet {
+		printJSON(newLookupOutput(input, CEPResult{}, 0, err))
+	} else if out.template != nil {
+		out.render(newTemplateData(input, CEPResult{}, 0, err))
+	} else {
+		fmt.Printf("Erro: %v\n", err)
+		for _, hint := range hints {
+			fmt.Println(hint)
+		}
+	}
+	exit(code)
+}
+
+// normalizeCEP removes hyphens and spaces so "01153-000" and "01153000" are treated as the same CEP
+func normalizeCEP(cep string) string {
+	cep = strings.ReplaceAll(cep, "-", "")
+	return strings.ReplaceAll(cep, " ", "")
+}
+
+func isValidCEP(cep string) bool {
 	if len(cep) != 8 {
-		fmt.Println("Erro: CEP deve ter 8 dígitos")
-		fmt.Println("Exemplo: 01153000")
-		os.Exit(1)
+		return false
+	}
+	for _, r := range cep {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
+	return true
+}
+
+// readCEPFile reads one CEP per line, skipping blank lines
+func readCEPFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var ceps []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			ceps = append(ceps, line)
+		}
+	}
+	return ceps, scanner.Err()
+}
 
-	ch := make(chan CEPResult, 2)
+type batchResult struct {
+	result  CEPResult
+	elapsed time.Duration
+	err     error
+}
+
+// runBatch looks up every unique CEP once, concurrently, and shares the result
+// across repeated entries. Returns the exit code of the batch.
+func runBatch(ceps []string, out output) int {
+	unique := make(map[string]*batchResult)
+	var order []string
+	for _, cep := range ceps {
+		key := normalizeCEP(cep)
+		if _, seen := unique[key]; !seen {
+			unique[key] = &batchResult{}
+			order = append(order, key)
+		}
+	}
 
-	fmt.Printf("🔍 Buscando CEP %s nas APIs BrasilAPI e ViaCEP...\n", cep)
+	if !out.machine() {
+		fmt.Printf("🔍 Buscando %d CEPs (%d únicos) nas APIs BrasilAPI e ViaCEP...\n", len(ceps), len(order))
+	}
 	start := time.Now()
 
-	go fetchBrasilAPI(cep, ch)
-	go fetchViaCEP(cep, ch)
-
-	select {
-	case result := <-ch:
-		elapsed := time.Since(start)
-		fmt.Printf("\n✅ === RESULTADO MAIS RÁPIDO ===\n")
-		fmt.Printf("🏆 API Vencedora: %s\n", result.Source)
-		fmt.Printf("📮 CEP: %s\n", result.CEP)
-		fmt.Printf("🏠 Logradouro: %s\n", result.Street)
-		fmt.Printf("🏘️  Bairro: %s\n", result.District)
-		fmt.Printf("🏙️  Cidade: %s\n", result.City)
-		fmt.Printf("🗺️  Estado: %s\n", result.State)
-		fmt.Printf("⏱️  Tempo de resposta: %v\n", elapsed.Round(time.Millisecond))
-
-	case <-time.After(1 * time.Second):
-		fmt.Println("\n❌ Erro: Timeout - Nenhuma API respondeu em 1 segundo")
-		os.Exit(1)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchConcurrency)
+	for _, key := range order {
+		entry := unique[key]
+		if !isValidCEP(key) {
+			entry.err = errInvalidCEP
+			continue
+		}
+
+		wg.Add(1)
+		go func(cep string, entry *batchResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			entry.result, entry.elapsed, entry.err = lookupCEP(context.Background(), cep)
+		}(key, entry)
+	}
+	wg.Wait()
+
+	code := batchExitCode(ceps, unique)
+	if out.quiet {
+		batch := batchOutput{Total: len(ceps), Unique: len(order), ExitCode: code}
+		for _, cep := range ceps {
+			entry := unique[normalizeCEP(cep)]
+			if entry.err != nil {
+				batch.Failed++
+			}
+			batch.Results = append(batch.Results, newLookupOutput(cep, entry.result, entry.elapsed, entry.err))
+		}
+		printJSON(batch)
+		return code
+	}
+	if out.template != nil {
+		for _, cep := range ceps {
+			entry := unique[normalizeCEP(cep)]
+			out.render(newTemplateData(cep, entry.result, entry.elapsed, entry.err))
+		}
+		return code
+	}
+
+	fmt.Printf("\n✅ === RESULTADOS ===\n")
+	for _, cep := range ceps {
+		entry := unique[normalizeCEP(cep)]
+		if entry.err != nil {
+			fmt.Printf("❌ %s: %v\n", cep, entry.err)
+			continue
+		}
+		r := entry.result
+		fmt.Printf("📮 %s: %s, %s - %s/%s (%s, %v)\n",
+			cep, r.Street, r.District, r.City, r.State, r.Source, entry.elapsed.Round(time.Millisecond))
+	}
+
+	duplicates := len(ceps) - len(order)
+	fmt.Printf("\n📊 === DEDUPLICAÇÃO ===\n")
+	fmt.Printf("📄 CEPs no lote: %d\n", len(ceps))
+	fmt.Printf("🔑 CEPs únicos: %d\n", len(order))
+	fmt.Printf("♻️  Duplicados reaproveitados: %d (%d requisições HTTP evitadas)\n", duplicates, duplicates*2)
+	fmt.Printf("⏱️  Tempo total: %v\n", time.Since(start).Round(time.Millisecond))
+
+	return code
+}
+
+// batchExitCode is exitOK when every CEP succeeded, exitPartialSuccess when
+// only some did, and otherwise the shared exit code of the failures
+// (exitAllProvidersFailed when they failed for different reasons)
+func batchExitCode(ceps []string, unique map[string]*batchResult) int {
+	succeeded, failed := 0, 0
+	failureCode := -1
+	for _, cep := range ceps {
+		entry := unique[normalizeCEP(cep)]
+		if entry.err == nil {
+			succeeded++
+			continue
+		}
+		failed++
+		code, _ := classify(entry.err)
+		switch failureCode {
+		case -1:
+			failureCode = code
+		case code:
+		default:
+			failureCode = exitAllProvidersFailed
+		}
+	}
+
+	switch {
+	case failed == 0:
+		return exitOK
+	case succeeded > 0:
+		return exitPartialSuccess
+	default:
+		return failureCode
+	}
+}
+
+func main() {
+	batchFile := flag.String("batch", "", "arquivo com um CEP por linha (modo lote)")
+	quiet := flag.Bool("quiet", false, "imprime apenas o resultado em JSON")
+	templateText := flag.String("template", "", "formata cada resultado com um template Go, ex.: '{{.City}}/{{.State}} ({{.Source}})'")
+	traceExporter := flag.String("trace", exporterFromEnv(), "envia traces da corrida para zipkin, otlp ou stdout (padrão: TRACE_EXPORTER, ZIPKIN_URL ou OTEL_EXPORTER_OTLP_ENDPOINT)")
+	flag.Parse()
+	args := flag.Args()
+
+	out := output{quiet: *quiet}
+	if *templateText != "" {
+		if *quiet {
+			fail("", fmt.Errorf("%w: use -quiet ou -template, não ambos", errInvalidInput), output{})
+		}
+		tmpl, err := parseTemplate(*templateText)
+		if err != nil {
+			fail("", err, output{})
+		}
+		out.template = tmpl
+	}
+	if *traceExporter != "" {
+		if err := initTracing(*traceExporter); err != nil {
+			fail("", err, out)
+		}
+	}
+
+	if *batchFile != "" || len(args) > 1 {
+		ceps := args
+		if *batchFile != "" {
+			fileCEPs, err := readCEPFile(*batchFile)
+			if err != nil {
+				fail(*batchFile, fmt.Errorf("não foi possível ler o arquivo %s: %w", *batchFile, err), out)
+			}
+			ceps = append(fileCEPs, args...)
+		}
+		if len(ceps) == 0 {
+			fail(*batchFile, fmt.Errorf("%w: nenhum CEP informado no lote", errInvalidInput), out)
+		}
+		exit(runBatch(ceps, out))
+	}
+
+	if len(args) < 1 {
+		fail("", fmt.Errorf("%w: nenhum CEP informado", errInvalidInput), out,
+			"Uso: go run main.go [-quiet | -template TEXTO] <CEP>",
+			"     go run main.go [-quiet | -template TEXTO] -batch ceps.txt",
+			"     go run main.go [-quiet | -template TEXTO] <CEP> <CEP> ...",
+			"Exemplo: go run main.go 01153000")
 	}
+
+	// Accept "01153-000" as in batch mode, looking up the bare digits
+	cep := normalizeCEP(args[0])
+
+	if !isValidCEP(cep) {
+		fail(args[0], errInvalidCEP, out, "Exemplo: 01153000")
+	}
+
+	if !out.machine() {
+		fmt.Printf("🔍 Buscando CEP %s nas APIs BrasilAPI e ViaCEP...\n", cep)
+	}
+
+	result, elapsed, err := lookupCEP(context.Background(), cep)
+	if out.quiet {
+		printJSON(newLookupOutput(cep, result, elapsed, err))
+		code, _ := classify(err)
+		exit(code)
+	}
+	if out.template != nil {
+		out.render(newTemplateData(cep, result, elapsed, err))
+		code, _ := classify(err)
+		exit(code)
+	}
+	if err != nil {
+		code, _ := classify(err)
+		fmt.Printf("\n❌ Erro: %v\n", err)
+		exit(code)
+	}
+
+	fmt.Printf("\n✅ === RESULTADO MAIS RÁPIDO ===\n")
+	fmt.Printf("🏆 API Vencedora: %s\n", result.Source)
+	fmt.Printf("📮 CEP: %s\n", result.CEP)
+	fmt.Printf("🏠 Logradouro: %s\n", result.Street)
+	fmt.Printf("🏘️  Bairro: %s\n", result.District)
+	fmt.Printf("🏙️  Cidade: %s\n", result.City)
+	fmt.Printf("🗺️  Estado: %s\n", result.State)
+	fmt.Printf("⏱️  Tempo de resposta: %v\n", elapsed.Round(time.Millisecond))
 }